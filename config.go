@@ -3,12 +3,15 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
@@ -19,18 +22,138 @@ type Config struct {
 	Server   Server   `mapstructure:"server"`
 	Monitor  Monitor  `mapstructure:"monitor"`
 	Claude   Claude   `mapstructure:"claude"`
+	Receiver Receiver `mapstructure:"receiver"`
+	Display  Display  `mapstructure:"display"`
+	Quota    Quota    `mapstructure:"quota"`
+	Budget   Budget   `mapstructure:"budget"`
+	Goal     Goal     `mapstructure:"goal"`
+	Anomaly  Anomaly  `mapstructure:"anomaly"`
+	Alerts   Alerts   `mapstructure:"alerts"`
+
+	file     string   // config file used, empty when running on defaults
+	includes []string // resolved paths of the included config files
 }
 
 // Database configuration
 type Database struct {
-	Path string `mapstructure:"path"`
+	Path        string              `mapstructure:"path"`
+	Backup      DatabaseBackup      `mapstructure:"backup"`
+	WriteBehind DatabaseWriteBehind `mapstructure:"write_behind"`
+}
+
+// DatabaseWriteBehind configuration for batching the stored requests of server mode into fewer write transactions
+type DatabaseWriteBehind struct {
+	Interval string `mapstructure:"interval"` // longest time a request waits to be written, "0" or empty writes every batch right away
+	Size     int    `mapstructure:"size"`     // pending requests which are written without waiting for the interval, 0 uses the default
+}
+
+// DatabaseBackup configuration for the periodic backup a corrupt database is restored from
+type DatabaseBackup struct {
+	Path     string `mapstructure:"path"`     // backup file, empty disables backups and recovery
+	Interval string `mapstructure:"interval"` // how often the server replaces the backup
+}
+
+// ServerSnapshotToken configuration of a labelled snapshot token, exactly one of token and file is set
+type ServerSnapshotToken struct {
+	Label string `mapstructure:"label"` // logged when a replica pulls a snapshot with the token
+	Token string `mapstructure:"token"`
+	File  string `mapstructure:"file"` // file holding the token, read again on SIGHUP
+}
+
+// ServerToken configuration of a labelled access token, exactly one of token and file is set
+type ServerToken struct {
+	Label string `mapstructure:"label"` // logged when a call is rejected
+	Token string `mapstructure:"token"`
+	File  string `mapstructure:"file"` // file holding the token, read again on SIGHUP
+	Role  string `mapstructure:"role"` // read (queries only) or write (also OTLP export and starring), default read
 }
 
 // Server configuration
 type Server struct {
-	Address   string      `mapstructure:"address"`
-	Retention string      `mapstructure:"retention"`
-	Cache     ServerCache `mapstructure:"cache"`
+	Address        string                `mapstructure:"address"`
+	Retention      string                `mapstructure:"retention"`
+	Cleanup        ServerCleanup         `mapstructure:"cleanup"`
+	User           string                `mapstructure:"user"`            // drop privileges to this user after binding
+	SnapshotToken  string                `mapstructure:"snapshot_token"`  // enables snapshot sync for replicas
+	SnapshotTokens []ServerSnapshotToken `mapstructure:"snapshot_tokens"` // further accepted tokens, e.g. while rotating
+	Tokens         []ServerToken         `mapstructure:"tokens"`          // required by the query service and OTLP export when set
+	Cache          ServerCache           `mapstructure:"cache"`
+	Replica        ServerReplica         `mapstructure:"replica"`
+	HTTP           ServerHTTP            `mapstructure:"http"`
+	Debug          ServerDebug           `mapstructure:"debug"`
+	QueryLog       ServerQueryLog        `mapstructure:"query_log"`
+	DailySummary   ServerDailySummary    `mapstructure:"daily_summary"`
+	Throttle       ServerThrottle        `mapstructure:"throttle"`
+	Limits         ServerLimits          `mapstructure:"limits"`
+	TLS            ServerTLS             `mapstructure:"tls"`
+}
+
+// ServerTLS configuration for serving gRPC and the HTTP API over TLS, enabled when the certificate is set
+type ServerTLS struct {
+	CertFile     string `mapstructure:"cert_file"`      // PEM certificate served to clients
+	KeyFile      string `mapstructure:"key_file"`       // PEM private key of the certificate
+	ClientCAFile string `mapstructure:"client_ca_file"` // requires client certificates signed by this CA, empty accepts any client
+}
+
+// ClientTLS configuration for connecting to a ccmon server over TLS
+type ClientTLS struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	CAFile   string `mapstructure:"ca_file"`   // verifies the server certificate, empty uses the system roots
+	CertFile string `mapstructure:"cert_file"` // client certificate for servers with server.tls.client_ca_file
+	KeyFile  string `mapstructure:"key_file"`
+}
+
+// ServerLimits configuration for protecting the receiver from exporters sending too many log exports
+type ServerLimits struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"` // log exports per second of each client, 0 disables the limit
+	Burst             int     `mapstructure:"burst"`               // exports a client may send at once, 0 rounds requests_per_second up
+}
+
+// ServerCleanup configuration for the retention cleanup scheduler
+type ServerCleanup struct {
+	Interval string `mapstructure:"interval"` // how often records older than the retention are deleted
+	DryRun   bool   `mapstructure:"dry_run"`  // only log how many records would be deleted
+}
+
+// ServerThrottle configuration for the throttle signal file polled by agent orchestrators
+type ServerThrottle struct {
+	Path      string  `mapstructure:"path"`      // signal file, empty disables it
+	Block     string  `mapstructure:"block"`     // block start time in monitor.timezone, e.g. "5am"
+	Threshold float64 `mapstructure:"threshold"` // block usage percentage at which should_throttle is set
+	Interval  string  `mapstructure:"interval"`  // how often the file is updated
+}
+
+// ServerDailySummary configuration for the end-of-day usage summary
+type ServerDailySummary struct {
+	At      string `mapstructure:"at"`      // local time of day in monitor.timezone, e.g. "18:00", empty disables
+	Webhook string `mapstructure:"webhook"` // URL the summary is posted to, empty only writes it to the server log
+}
+
+// ServerQueryLog configuration for logging query service calls
+type ServerQueryLog struct {
+	Access        bool   `mapstructure:"access"`         // log every query service call to the server log
+	SlowThreshold string `mapstructure:"slow_threshold"` // duration above which calls are logged as slow, empty or "0" disables
+	SlowPath      string `mapstructure:"slow_path"`      // dedicated slow query log file, empty logs to the server log
+}
+
+// ServerDebug configuration for the runtime profiling endpoints
+type ServerDebug struct {
+	PProf        bool   `mapstructure:"pprof"`         // serve the pprof endpoints
+	PProfAddress string `mapstructure:"pprof_address"` // listen address of the pprof endpoints
+}
+
+// ServerHTTP configuration for the JSON HTTP API used by editor plugins and the OTLP/HTTP receiver
+type ServerHTTP struct {
+	Address     string   `mapstructure:"address"`      // listen address of the HTTP API and OTLP/HTTP exports, empty disables both
+	CORSOrigins []string `mapstructure:"cors_origins"` // origins allowed to call the API from a browser, "*" allows any
+}
+
+// ServerReplica holds configuration for running as a read-only replica
+type ServerReplica struct {
+	Primary  string    `mapstructure:"primary"`  // primary server address, enables replica mode
+	Token    string    `mapstructure:"token"`    // snapshot token of the primary
+	Interval string    `mapstructure:"interval"` // snapshot sync interval
+	TLS      ClientTLS `mapstructure:"tls"`      // connection to the primary
 }
 
 // ServerCache configuration
@@ -44,17 +167,163 @@ type CacheStats struct {
 	TTL     string `mapstructure:"ttl"`
 }
 
+// Receiver configuration
+type Receiver struct {
+	Ignore       ReceiverIgnore       `mapstructure:"ignore"`
+	ClockSkew    ReceiverClockSkew    `mapstructure:"clock_skew"`
+	TelemetryGap ReceiverTelemetryGap `mapstructure:"telemetry_gap"`
+	Workers      ReceiverWorkers      `mapstructure:"workers"`
+	Dedupe       ReceiverDedupe       `mapstructure:"dedupe"`
+	Plugins      []ReceiverPlugin     `mapstructure:"plugins"`
+	FillCosts    bool                 `mapstructure:"fill_costs"` // price requests reported with a zero cost from the embedded prices
+}
+
+// ReceiverPlugin configuration for a subprocess enriching, relabeling or forwarding each received API request
+type ReceiverPlugin struct {
+	Name    string   `mapstructure:"name"`    // identifies the plugin in the server log
+	Command []string `mapstructure:"command"` // program and arguments, started once and fed one JSON request per line
+	Timeout string   `mapstructure:"timeout"` // time to wait for each reply, empty uses the default
+}
+
+// ReceiverWorkers configuration for processing log exports outside of the gRPC handler
+type ReceiverWorkers struct {
+	Count     int `mapstructure:"count"`      // number of workers, 0 processes exports in the gRPC handler
+	QueueSize int `mapstructure:"queue_size"` // exports waiting for a worker before exporters are pushed back
+}
+
+// ReceiverDedupe configuration for dropping API requests re-sent by exporters retrying a failed export
+type ReceiverDedupe struct {
+	Window string `mapstructure:"window"` // duration a received request is remembered, empty or "0" disables
+}
+
+// ReceiverTelemetryGap configuration for alerting when a previously active exporter goes silent
+type ReceiverTelemetryGap struct {
+	Threshold string   `mapstructure:"threshold"` // duration without events before alerting, empty or "0" disables
+	Days      []string `mapstructure:"days"`      // weekdays to alert on in monitor.timezone (e.g. "mon"), empty means every day
+}
+
+// ReceiverClockSkew configuration for API requests timestamped ahead of the server clock
+type ReceiverClockSkew struct {
+	Tolerance string `mapstructure:"tolerance"` // duration, "0" disables detection
+	Action    string `mapstructure:"action"`    // enum: clamp, drop
+}
+
+// ReceiverIgnore configuration for dropping API requests before they are stored
+type ReceiverIgnore struct {
+	Models          []string `mapstructure:"models"`           // regular expressions matched against model names
+	SessionPrefixes []string `mapstructure:"session_prefixes"` // session ID prefixes
+}
+
 // Monitor configuration
 type Monitor struct {
-	Server          string `mapstructure:"server"`
-	Timezone        string `mapstructure:"timezone"`
-	RefreshInterval string `mapstructure:"refresh_interval"`
+	Server          string              `mapstructure:"server"`
+	Token           string              `mapstructure:"token"` // access token presented to a server with server.tokens
+	Timezone        string              `mapstructure:"timezone"`
+	RefreshInterval string              `mapstructure:"refresh_interval"`
+	AltScreen       bool                `mapstructure:"alt_screen"` // render in the alternate screen buffer instead of inline
+	Mouse           bool                `mapstructure:"mouse"`      // scroll and click the tables and tabs, terminal text selection needs shift
+	Highlight       MonitorHighlight    `mapstructure:"highlight"`
+	Filter          MonitorFilter       `mapstructure:"filter"`
+	Leaderboard     MonitorLeaderboard  `mapstructure:"leaderboard"`
+	Keys            map[string][]string `mapstructure:"keys"` // rebinds monitor actions, e.g. filter_hour = "H"
+	TLS             ClientTLS           `mapstructure:"tls"`  // connection to monitor.server
+}
+
+// MonitorHighlight configuration for highlighting expensive requests in the requests table
+type MonitorHighlight struct {
+	Cost   float64 `mapstructure:"cost"`   // cost threshold in USD per request, 0 disables
+	Tokens int64   `mapstructure:"tokens"` // total tokens threshold per request, 0 disables
+}
+
+// MonitorFilter configuration for narrowing the requests table, empty values match every request
+type MonitorFilter struct {
+	Model   string `mapstructure:"model"`   // model name, e.g. claude-sonnet-4-20250514
+	Session string `mapstructure:"session"` // session ID
+	Source  string `mapstructure:"source"`  // source reporting the request
+	Origin  string `mapstructure:"origin"`  // live or import, e.g. live to hide backfilled records
+	Project string `mapstructure:"project"` // project the request was made in, e.g. the repository name
+}
+
+// MonitorLeaderboard configuration for the tab ranking the users of a multi-user deployment
+type MonitorLeaderboard struct {
+	Enabled bool   `mapstructure:"enabled"` // show the leaderboard tab
+	User    string `mapstructure:"user"`    // the viewer's email or account UUID reported by telemetry
+	Private bool   `mapstructure:"private"` // start with only the viewer's rank shown
+}
+
+// Display configuration shared by the monitor, tmux status and format variables
+type Display struct {
+	CostPrecision *int   `mapstructure:"cost_precision"` // decimals for regular cost amounts, unset keeps the decimals of each view
+	CostHumanize  bool   `mapstructure:"cost_humanize"`  // keep significant digits for small costs and abbreviate large ones
+	DateFormat    string `mapstructure:"date_format"`    // date pattern, e.g. "DD/MM/YYYY"
+	TimeFormat    string `mapstructure:"time_format"`    // clock, "12h" or "24h"
+}
+
+// Quota configuration
+type Quota struct {
+	HardDaily float64     `mapstructure:"hard_daily"` // daily cost limit in USD, 0 disables the quota
+	Warning   string      `mapstructure:"warning"`    // prefixed to --format output once exceeded
+	UserDaily float64     `mapstructure:"user_daily"` // server mode daily cost limit in USD of each user, 0 disables it
+	Users     []QuotaUser `mapstructure:"users"`      // server mode daily cost limits of individual users
+}
+
+// QuotaUser configuration, an array of tables since user emails contain dots viper splits map keys on
+type QuotaUser struct {
+	User  string  `mapstructure:"user"`  // user email or account UUID reported by telemetry
+	Daily float64 `mapstructure:"daily"` // daily cost limit in USD, 0 makes the user unlimited
+}
+
+// Budget configuration
+type Budget struct {
+	Daily   float64 `mapstructure:"daily"`   // daily budget in USD, 0 spreads the monthly budget over the month
+	Monthly float64 `mapstructure:"monthly"` // monthly budget in USD, 0 uses the plan price
+}
+
+// Goal configuration
+type Goal struct {
+	DailyCost   float64 `mapstructure:"daily_cost"`   // daily cost goal in USD, 0 disables the cost goal
+	DailyTokens int64   `mapstructure:"daily_tokens"` // daily input + output token goal, 0 disables the token goal
+}
+
+// Anomaly configuration for flagging days which cost far more than the week before
+type Anomaly struct {
+	Multiple float64 `mapstructure:"multiple"` // times the 7-day baseline a day may cost before it is flagged, 0 disables
+}
+
+// Alerts configuration for the budget alerts evaluated by the server as requests arrive
+type Alerts struct {
+	Block    string         `mapstructure:"block"`    // block start time in monitor.timezone, required by block rules
+	Rules    []AlertRule    `mapstructure:"rules"`    // thresholds, each alerts once per day, month or block
+	Webhooks []AlertWebhook `mapstructure:"webhooks"` // URLs the alerts are posted to, none only writes them to the server log
+}
+
+// AlertRule configuration, exactly one of above and percent is set
+type AlertRule struct {
+	Metric  string  `mapstructure:"metric"`  // enum: daily_cost, monthly_cost, block_cost, block_tokens
+	Above   float64 `mapstructure:"above"`   // USD for costs, tokens for block_tokens
+	Percent float64 `mapstructure:"percent"` // percentage of the block token limit, only for block_tokens
+}
+
+// AlertWebhook configuration
+type AlertWebhook struct {
+	URL    string `mapstructure:"url"`
+	Format string `mapstructure:"format"` // enum: generic, slack, discord
 }
 
 // Claude configuration
 type Claude struct {
-	Plan      string `mapstructure:"plan"`       // enum: unset, pro, max, max20
-	MaxTokens int    `mapstructure:"max_tokens"` // override default token limits
+	Plan          string       `mapstructure:"plan"`           // enum: unset, pro, max, max20
+	MaxTokens     int          `mapstructure:"max_tokens"`     // override default token limits
+	BlockDuration string       `mapstructure:"block_duration"` // length of the token limit blocks, e.g. "5h", "1h" or "24h"
+	Transcripts   string       `mapstructure:"transcripts"`    // Claude Code transcripts directory for session titles, empty disables
+	User          string       `mapstructure:"user"`           // your user.email or user.account_uuid, selects your plan and usage on a team server
+	Users         []ClaudeUser `mapstructure:"users"`          // plans of the members of a team server
+}
+
+// ClaudeUser configuration, an array of tables since user emails contain dots viper splits map keys on
+type ClaudeUser struct {
+	User string `mapstructure:"user"` // user email or account UUID reported by telemetry
+	Plan string `mapstructure:"plan"` // enum: unset, pro, max, max20
 }
 
 // LoadConfig loads configuration from files and command-line flags
@@ -63,15 +332,68 @@ func LoadConfig() (*Config, error) {
 
 	// Set default values
 	v.SetDefault("database.path", "~/.ccmon/ccmon.db")
+	v.SetDefault("database.backup.path", "")
+	v.SetDefault("database.backup.interval", "1h")
+	v.SetDefault("database.write_behind.interval", "0")
+	v.SetDefault("database.write_behind.size", 1000)
 	v.SetDefault("server.address", "127.0.0.1:4317")
 	v.SetDefault("server.retention", "never")
+	v.SetDefault("server.cleanup.interval", "6h")
+	v.SetDefault("server.cleanup.dry_run", false)
 	v.SetDefault("server.cache.stats.enabled", true)
 	v.SetDefault("server.cache.stats.ttl", "1m")
+	v.SetDefault("server.replica.interval", "5m")
+	v.SetDefault("server.http.address", "")
+	v.SetDefault("server.limits.requests_per_second", 0)
+	v.SetDefault("server.limits.burst", 0)
+	v.SetDefault("server.debug.pprof", false)
+	v.SetDefault("server.debug.pprof_address", "127.0.0.1:6060")
+	v.SetDefault("server.query_log.access", false)
+	v.SetDefault("server.query_log.slow_threshold", "")
+	v.SetDefault("server.query_log.slow_path", "")
+	v.SetDefault("server.daily_summary.at", "")
+	v.SetDefault("server.daily_summary.webhook", "")
+	v.SetDefault("server.throttle.path", "")
+	v.SetDefault("server.throttle.block", "")
+	v.SetDefault("server.throttle.threshold", entity.DefaultThrottleThreshold)
+	v.SetDefault("server.throttle.interval", "10s")
+	v.SetDefault("receiver.clock_skew.tolerance", entity.DefaultClockSkewTolerance.String())
+	v.SetDefault("receiver.clock_skew.action", string(entity.ClockSkewClamp))
+	v.SetDefault("receiver.workers.count", 0) // Workers acknowledge an export before it is stored, so they are opt-in
+	v.SetDefault("receiver.workers.queue_size", 256)
+	v.SetDefault("receiver.fill_costs", true)
+	v.SetDefault("receiver.dedupe.window", service.DefaultDedupeWindow.String())
 	v.SetDefault("monitor.server", "127.0.0.1:4317")
 	v.SetDefault("monitor.timezone", "UTC")
 	v.SetDefault("monitor.refresh_interval", "5s")
+	v.SetDefault("monitor.alt_screen", true)
+	v.SetDefault("monitor.mouse", true)
+	v.SetDefault("monitor.highlight.cost", 0.0)
+	v.SetDefault("monitor.highlight.tokens", 0)
+	v.SetDefault("monitor.filter.model", "")
+	v.SetDefault("monitor.filter.session", "")
+	v.SetDefault("monitor.filter.source", "")
+	v.SetDefault("monitor.filter.origin", "")
+	v.SetDefault("monitor.filter.project", "")
+	v.SetDefault("monitor.leaderboard.enabled", false)
+	v.SetDefault("monitor.leaderboard.user", "")
+	v.SetDefault("monitor.leaderboard.private", false)
+	v.SetDefault("display.cost_humanize", false)
+	v.SetDefault("display.date_format", entity.DefaultDatePattern)
+	v.SetDefault("display.time_format", "")
+	v.SetDefault("quota.hard_daily", 0.0)
+	v.SetDefault("quota.warning", entity.DefaultQuotaWarning)
+	v.SetDefault("budget.daily", 0.0)
+	v.SetDefault("budget.monthly", 0.0)
+	v.SetDefault("goal.daily_cost", 0.0)
+	v.SetDefault("goal.daily_tokens", 0)
+	v.SetDefault("anomaly.multiple", entity.DefaultCostAnomalyMultiple)
+	v.SetDefault("alerts.block", "")
 	v.SetDefault("claude.plan", "unset")
 	v.SetDefault("claude.max_tokens", 0) // 0 means use plan defaults
+	v.SetDefault("claude.block_duration", entity.TimeBlockDuration.String())
+	v.SetDefault("claude.transcripts", "~/.claude/projects")
+	v.SetDefault("claude.user", "")
 
 	// Define command-line flags using pflag (if not already defined)
 	if pflag.Lookup("database-path") == nil {
@@ -83,18 +405,45 @@ func LoadConfig() (*Config, error) {
 	if pflag.Lookup("server-retention") == nil {
 		pflag.String("server-retention", "", "Data retention period (e.g., '7d', '30d', 'never')")
 	}
+	if pflag.Lookup("cleanup-dry-run") == nil {
+		pflag.Bool("cleanup-dry-run", false, "Only log how many records the retention cleanup would delete")
+	}
+	if pflag.Lookup("server-user") == nil {
+		pflag.String("server-user", "", "Drop privileges to this user after binding the listener (unix only)")
+	}
+	if pflag.Lookup("server-debug-pprof") == nil {
+		pflag.Bool("server-debug-pprof", false, "Serve the pprof profiling endpoints in server mode")
+	}
 	if pflag.Lookup("monitor-server") == nil {
 		pflag.String("monitor-server", "", "gRPC server address for query service")
 	}
 	if pflag.Lookup("monitor-timezone") == nil {
 		pflag.String("monitor-timezone", "", "Timezone for time filtering and display")
 	}
+	if pflag.Lookup("monitor-filter-model") == nil {
+		pflag.String("monitor-filter-model", "", "Only show requests of this model in the monitor")
+	}
+	if pflag.Lookup("monitor-filter-session") == nil {
+		pflag.String("monitor-filter-session", "", "Only show requests of this session in the monitor")
+	}
+	if pflag.Lookup("monitor-filter-source") == nil {
+		pflag.String("monitor-filter-source", "", "Only show requests reported by this source in the monitor")
+	}
+	if pflag.Lookup("monitor-filter-origin") == nil {
+		pflag.String("monitor-filter-origin", "", "Only show live or imported requests in the monitor (live, import)")
+	}
+	if pflag.Lookup("monitor-filter-project") == nil {
+		pflag.String("monitor-filter-project", "", "Only show requests of this project in the monitor")
+	}
 	if pflag.Lookup("claude-plan") == nil {
 		pflag.String("claude-plan", "", "Claude subscription plan (unset, pro, max, max20)")
 	}
 	if pflag.Lookup("claude-max-tokens") == nil {
 		pflag.Int("claude-max-tokens", 0, "Custom token limit override (0 means use plan defaults)")
 	}
+	if pflag.Lookup("block-duration") == nil {
+		pflag.String("block-duration", "", "Length of the --block token limit blocks (e.g., '5h', '1h', '24h')")
+	}
 	if pflag.Lookup("server-cache-stats-enabled") == nil {
 		pflag.Bool("server-cache-stats-enabled", true, "Enable stats cache")
 	}
@@ -117,18 +466,45 @@ func LoadConfig() (*Config, error) {
 	if err := v.BindPFlag("server.retention", pflag.Lookup("server-retention")); err != nil {
 		log.Printf("Warning: failed to bind server-retention flag: %v", err)
 	}
+	if err := v.BindPFlag("server.cleanup.dry_run", pflag.Lookup("cleanup-dry-run")); err != nil {
+		log.Printf("Warning: failed to bind cleanup-dry-run flag: %v", err)
+	}
+	if err := v.BindPFlag("server.user", pflag.Lookup("server-user")); err != nil {
+		log.Printf("Warning: failed to bind server-user flag: %v", err)
+	}
+	if err := v.BindPFlag("server.debug.pprof", pflag.Lookup("server-debug-pprof")); err != nil {
+		log.Printf("Warning: failed to bind server-debug-pprof flag: %v", err)
+	}
 	if err := v.BindPFlag("monitor.server", pflag.Lookup("monitor-server")); err != nil {
 		log.Printf("Warning: failed to bind monitor-server flag: %v", err)
 	}
 	if err := v.BindPFlag("monitor.timezone", pflag.Lookup("monitor-timezone")); err != nil {
 		log.Printf("Warning: failed to bind monitor-timezone flag: %v", err)
 	}
+	if err := v.BindPFlag("monitor.filter.model", pflag.Lookup("monitor-filter-model")); err != nil {
+		log.Printf("Warning: failed to bind monitor-filter-model flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.filter.session", pflag.Lookup("monitor-filter-session")); err != nil {
+		log.Printf("Warning: failed to bind monitor-filter-session flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.filter.source", pflag.Lookup("monitor-filter-source")); err != nil {
+		log.Printf("Warning: failed to bind monitor-filter-source flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.filter.origin", pflag.Lookup("monitor-filter-origin")); err != nil {
+		log.Printf("Warning: failed to bind monitor-filter-origin flag: %v", err)
+	}
+	if err := v.BindPFlag("monitor.filter.project", pflag.Lookup("monitor-filter-project")); err != nil {
+		log.Printf("Warning: failed to bind monitor-filter-project flag: %v", err)
+	}
 	if err := v.BindPFlag("claude.plan", pflag.Lookup("claude-plan")); err != nil {
 		log.Printf("Warning: failed to bind claude-plan flag: %v", err)
 	}
 	if err := v.BindPFlag("claude.max_tokens", pflag.Lookup("claude-max-tokens")); err != nil {
 		log.Printf("Warning: failed to bind claude-max-tokens flag: %v", err)
 	}
+	if err := v.BindPFlag("claude.block_duration", pflag.Lookup("block-duration")); err != nil {
+		log.Printf("Warning: failed to bind block-duration flag: %v", err)
+	}
 	if err := v.BindPFlag("server.cache.stats.enabled", pflag.Lookup("server-cache-stats-enabled")); err != nil {
 		log.Printf("Warning: failed to bind server-cache-stats-enabled flag: %v", err)
 	}
@@ -153,6 +529,16 @@ func LoadConfig() (*Config, error) {
 		// No config file found is OK - use defaults
 	}
 
+	// Merge included config files (e.g. a shared server config)
+	if err := mergeIncludes(v); err != nil {
+		return nil, err
+	}
+
+	// Map deprecated keys to their new names, warning until the files are migrated
+	if err := migrateConfig(v); err != nil {
+		return nil, err
+	}
+
 	// Unmarshal config
 	var config Config
 	if err := v.Unmarshal(&config); err != nil {
@@ -161,6 +547,18 @@ func LoadConfig() (*Config, error) {
 
 	// Expand home directory in database path
 	config.Database.Path = expandPath(config.Database.Path)
+	config.Database.Backup.Path = expandPath(config.Database.Backup.Path)
+	config.Claude.Transcripts = expandPath(config.Claude.Transcripts)
+	config.Server.QueryLog.SlowPath = expandPath(config.Server.QueryLog.SlowPath)
+	config.Server.Throttle.Path = expandPath(config.Server.Throttle.Path)
+	for i := range config.Server.SnapshotTokens {
+		config.Server.SnapshotTokens[i].File = expandPath(config.Server.SnapshotTokens[i].File)
+	}
+	for i := range config.Server.Tokens {
+		config.Server.Tokens[i].File = expandPath(config.Server.Tokens[i].File)
+	}
+	config.file = v.ConfigFileUsed()
+	config.includes = includePaths(v)
 
 	// Validate configuration
 	if err := config.Validate(); err != nil {
@@ -170,6 +568,53 @@ func LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// mergeIncludes merges the files listed in the `include` key into the loaded configuration.
+// Included files are merged in order (later files override earlier ones), then the including
+// file is applied again so its own values always take precedence. Command-line flags still
+// override everything. Relative paths are resolved against the including file's directory.
+func mergeIncludes(v *viper.Viper) error {
+	mainFile := v.ConfigFileUsed()
+	includes := v.GetStringSlice("include")
+	if mainFile == "" || len(includes) == 0 {
+		return nil
+	}
+
+	for i, path := range includePaths(v) {
+		v.SetConfigFile(path)
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("error reading included config %s: %w", includes[i], err)
+		}
+	}
+
+	// Re-apply the including file so it overrides values from included files
+	v.SetConfigFile(mainFile)
+	if err := v.MergeInConfig(); err != nil {
+		return fmt.Errorf("error reading config: %w", err)
+	}
+
+	return nil
+}
+
+// includePaths returns the paths of the files listed in the `include` key
+// Relative paths are resolved against the including file's directory
+func includePaths(v *viper.Viper) []string {
+	mainFile := v.ConfigFileUsed()
+	if mainFile == "" {
+		return nil
+	}
+
+	baseDir := filepath.Dir(mainFile)
+	var paths []string
+	for _, include := range v.GetStringSlice("include") {
+		path := expandPath(include)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
 // expandPath expands ~ to home directory
 func expandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -194,6 +639,14 @@ func (c *Config) Validate() error {
 	if !validPlans[c.Claude.Plan] {
 		return fmt.Errorf("invalid claude plan: %s (must be one of: unset, pro, max, max20)", c.Claude.Plan)
 	}
+	for _, user := range c.Claude.Users {
+		if user.User == "" {
+			return fmt.Errorf("claude.users entries must set user")
+		}
+		if !validPlans[user.Plan] {
+			return fmt.Errorf("invalid claude.users plan of %q: %s (must be one of: unset, pro, max, max20)", user.User, user.Plan)
+		}
+	}
 
 	// Validate timezone
 	if c.Monitor.Timezone != "" {
@@ -207,12 +660,55 @@ func (c *Config) Validate() error {
 	if c.Claude.MaxTokens < 0 {
 		return fmt.Errorf("claude.max_tokens must be >= 0, got: %d", c.Claude.MaxTokens)
 	}
+	if _, err := entity.ParseBlockDuration(c.Claude.BlockDuration); err != nil {
+		return fmt.Errorf("invalid claude.block_duration: %w", err)
+	}
 
 	// Validate retention
 	if err := c.Server.ValidateRetention(); err != nil {
 		return fmt.Errorf("invalid server.retention: %w", err)
 	}
 
+	// Validate database backup
+	if c.Database.Backup.Path != "" {
+		if _, err := c.Database.Backup.GetInterval(); err != nil {
+			return fmt.Errorf("invalid database.backup: %w", err)
+		}
+		if c.Database.Backup.Path == c.Database.Path {
+			return fmt.Errorf("database.backup.path must differ from database.path")
+		}
+	}
+
+	// Validate database write-behind
+	if _, err := c.Database.WriteBehind.GetInterval(); err != nil {
+		return fmt.Errorf("invalid database.write_behind: %w", err)
+	}
+	if c.Database.WriteBehind.Size < 0 {
+		return fmt.Errorf("database.write_behind.size must not be negative, got %d", c.Database.WriteBehind.Size)
+	}
+
+	// Validate cleanup interval
+	if c.Server.Cleanup.Interval != "" {
+		if _, err := c.Server.Cleanup.GetInterval(); err != nil {
+			return fmt.Errorf("invalid server.cleanup: %w", err)
+		}
+	}
+
+	// Validate slow query threshold
+	if _, err := c.Server.QueryLog.GetSlowThreshold(); err != nil {
+		return fmt.Errorf("invalid server.query_log.slow_threshold: %w", err)
+	}
+
+	// Validate daily summary
+	if err := c.Server.DailySummary.Validate(); err != nil {
+		return fmt.Errorf("invalid server.daily_summary: %w", err)
+	}
+
+	// Validate throttle signal
+	if err := c.Server.Throttle.Validate(); err != nil {
+		return fmt.Errorf("invalid server.throttle: %w", err)
+	}
+
 	// Validate cache TTL
 	if c.Server.Cache.Stats.TTL != "" {
 		_, err := time.ParseDuration(c.Server.Cache.Stats.TTL)
@@ -221,6 +717,128 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate snapshot tokens
+	if err := c.Server.ValidateSnapshotTokens(); err != nil {
+		return fmt.Errorf("invalid server.snapshot_tokens: %w", err)
+	}
+
+	// Validate access tokens
+	if err := c.Server.ValidateAccessTokens(); err != nil {
+		return fmt.Errorf("invalid server.tokens: %w", err)
+	}
+
+	// Validate replica
+	if err := c.Server.Replica.Validate(); err != nil {
+		return fmt.Errorf("invalid server.replica: %w", err)
+	}
+
+	// Validate TLS
+	if err := c.Server.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid server.tls: %w", err)
+	}
+	if err := c.Monitor.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid monitor.tls: %w", err)
+	}
+
+	// Validate ignore rules
+	if _, err := c.Receiver.GetIgnoreRules(); err != nil {
+		return fmt.Errorf("invalid receiver.ignore: %w", err)
+	}
+	if _, err := c.Receiver.GetClockSkewPolicy(); err != nil {
+		return fmt.Errorf("invalid receiver.clock_skew: %w", err)
+	}
+	if _, err := c.Receiver.GetTelemetryGapPolicy(time.UTC); err != nil {
+		return fmt.Errorf("invalid receiver.telemetry_gap: %w", err)
+	}
+	if _, err := c.Receiver.GetDedupeWindow(); err != nil {
+		return fmt.Errorf("invalid receiver.dedupe: %w", err)
+	}
+	if c.Receiver.Workers.Count < 0 {
+		return fmt.Errorf("receiver.workers.count must not be negative, got: %d", c.Receiver.Workers.Count)
+	}
+	if c.Server.Limits.RequestsPerSecond < 0 {
+		return fmt.Errorf("server.limits.requests_per_second must not be negative, got: %v", c.Server.Limits.RequestsPerSecond)
+	}
+	if c.Server.Limits.Burst < 0 {
+		return fmt.Errorf("server.limits.burst must not be negative, got: %d", c.Server.Limits.Burst)
+	}
+	if c.Receiver.Workers.QueueSize < 0 {
+		return fmt.Errorf("receiver.workers.queue_size must not be negative, got: %d", c.Receiver.Workers.QueueSize)
+	}
+	if _, err := c.Receiver.GetPlugins(); err != nil {
+		return fmt.Errorf("invalid receiver.plugins: %w", err)
+	}
+
+	// Validate cost precision
+	if precision := c.Display.CostPrecision; precision != nil && (*precision < entity.MinCostPrecision || *precision > entity.MaxCostPrecision) {
+		return fmt.Errorf("display.cost_precision must be between %d and %d, got: %d", entity.MinCostPrecision, entity.MaxCostPrecision, *precision)
+	}
+
+	// Validate monitor filter origin
+	if c.Monitor.Filter.Origin != "" {
+		if _, err := entity.ParseOrigin(c.Monitor.Filter.Origin); err != nil {
+			return fmt.Errorf("invalid monitor.filter.origin: %w", err)
+		}
+	}
+
+	// Validate date and time format
+	if _, err := entity.NewTimeFormat(c.Display.DateFormat, entity.ClockDefault); err != nil {
+		return fmt.Errorf("invalid display.date_format: %w", err)
+	}
+	if _, err := entity.NewTimeFormat("", entity.Clock(c.Display.TimeFormat)); err != nil {
+		return fmt.Errorf("invalid display.time_format: %w", err)
+	}
+
+	// Validate highlight thresholds
+	if c.Monitor.Highlight.Cost < 0 {
+		return fmt.Errorf("monitor.highlight.cost must not be negative, got: %v", c.Monitor.Highlight.Cost)
+	}
+	if c.Monitor.Highlight.Tokens < 0 {
+		return fmt.Errorf("monitor.highlight.tokens must not be negative, got: %d", c.Monitor.Highlight.Tokens)
+	}
+
+	// Validate quota
+	if c.Quota.HardDaily < 0 {
+		return fmt.Errorf("quota.hard_daily must not be negative, got: %v", c.Quota.HardDaily)
+	}
+	if c.Quota.UserDaily < 0 {
+		return fmt.Errorf("quota.user_daily must not be negative, got: %v", c.Quota.UserDaily)
+	}
+	for _, user := range c.Quota.Users {
+		if user.User == "" {
+			return fmt.Errorf("quota.users entries must set user")
+		}
+		if user.Daily < 0 {
+			return fmt.Errorf("quota.users daily of %q must not be negative, got: %v", user.User, user.Daily)
+		}
+	}
+
+	// Validate budget
+	if c.Budget.Daily < 0 {
+		return fmt.Errorf("budget.daily must not be negative, got: %v", c.Budget.Daily)
+	}
+	if c.Budget.Monthly < 0 {
+		return fmt.Errorf("budget.monthly must not be negative, got: %v", c.Budget.Monthly)
+	}
+
+	// Validate goal
+	if c.Goal.DailyCost < 0 {
+		return fmt.Errorf("goal.daily_cost must not be negative, got: %v", c.Goal.DailyCost)
+	}
+	if c.Goal.DailyTokens < 0 {
+		return fmt.Errorf("goal.daily_tokens must not be negative, got: %d", c.Goal.DailyTokens)
+	}
+
+	// Validate anomaly, a multiple of 1 or less would flag ordinary days
+	if c.Anomaly.Multiple < 0 || (c.Anomaly.Multiple > 0 && c.Anomaly.Multiple <= 1) {
+		return fmt.Errorf("anomaly.multiple must be 0 (disabled) or above 1, got: %v", c.Anomaly.Multiple)
+	}
+
+	// Validate alerts
+	if err := c.Alerts.Validate(); err != nil {
+		return fmt.Errorf("invalid alerts: %w", err)
+	}
+
 	return nil
 }
 
@@ -263,6 +881,64 @@ func (s *Server) GetRetentionDuration() time.Duration {
 	return duration
 }
 
+// GetCleanupInterval returns how often the retention cleanup runs
+func (s *Server) GetCleanupInterval() time.Duration {
+	interval, err := s.Cleanup.GetInterval()
+	if err != nil {
+		return 6 * time.Hour // Default when unset
+	}
+	return interval
+}
+
+// IsCleanupDryRun returns true if the retention cleanup only logs what it would delete
+func (s *Server) IsCleanupDryRun() bool {
+	return s.Cleanup.DryRun
+}
+
+// IsEnabled returns true if the server keeps a backup to restore a corrupt database from
+func (b *DatabaseBackup) IsEnabled() bool {
+	return b.Path != ""
+}
+
+// GetInterval returns how often the backup is replaced, at least a minute
+func (b *DatabaseBackup) GetInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(b.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", b.Interval, err)
+	}
+	if interval < time.Minute {
+		return 0, fmt.Errorf("interval must be at least 1m, got %s", b.Interval)
+	}
+	return interval, nil
+}
+
+// GetInterval returns how long stored requests are held before they are written, zero disables the batching
+func (w *DatabaseWriteBehind) GetInterval() (time.Duration, error) {
+	if w.Interval == "" {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(w.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", w.Interval, err)
+	}
+	if interval < 0 {
+		return 0, fmt.Errorf("interval must not be negative, got %s", w.Interval)
+	}
+	return interval, nil
+}
+
+// GetInterval returns how often the retention cleanup runs, at least once a minute apart
+func (c *ServerCleanup) GetInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", c.Interval, err)
+	}
+	if interval < time.Minute {
+		return 0, fmt.Errorf("interval must be at least 1m, got %s", c.Interval)
+	}
+	return interval, nil
+}
+
 // parseRetentionDuration parses duration strings with support for days (e.g., "7d", "30d")
 func (s *Server) parseRetentionDuration(retention string) (time.Duration, error) {
 	// Handle days suffix (e.g., "7d", "30d")
@@ -282,6 +958,504 @@ func (s *Server) parseRetentionDuration(retention string) (time.Duration, error)
 	return time.ParseDuration(retention)
 }
 
+// GetUser returns the user the server switches to after binding its listener
+func (s *Server) GetUser() string {
+	return s.User
+}
+
+// GetSnapshotToken returns the token replicas must present to pull snapshots
+func (s *Server) GetSnapshotToken() string {
+	return s.SnapshotToken
+}
+
+// GetSnapshotTokens returns every token replicas can pull snapshots with, snapshot_token is labelled "snapshot_token"
+func (s *Server) GetSnapshotTokens() []entity.AccessToken {
+	tokens := make([]entity.AccessToken, 0, len(s.SnapshotTokens)+1)
+	if s.SnapshotToken != "" {
+		tokens = append(tokens, entity.AccessToken{Label: "snapshot_token", Token: s.SnapshotToken})
+	}
+	for _, token := range s.SnapshotTokens {
+		tokens = append(tokens, entity.AccessToken{Label: token.Label, Token: token.Token, File: token.File})
+	}
+	return tokens
+}
+
+// ValidateSnapshotTokens checks every token has a unique label and exactly one of token and file
+func (s *Server) ValidateSnapshotTokens() error {
+	labels := make(map[string]bool)
+	if s.SnapshotToken != "" {
+		labels["snapshot_token"] = true
+	}
+	for i, token := range s.SnapshotTokens {
+		if token.Label == "" {
+			return fmt.Errorf("token %d: label is required", i+1)
+		}
+		if labels[token.Label] {
+			return fmt.Errorf("token %d: label %q is used twice", i+1, token.Label)
+		}
+		labels[token.Label] = true
+		if (token.Token == "") == (token.File == "") {
+			return fmt.Errorf("token %s: exactly one of token and file must be set", token.Label)
+		}
+	}
+	return nil
+}
+
+// GetAccessTokens returns the tokens clients must present to the query service and OTLP export, empty when open to everyone
+func (s *Server) GetAccessTokens() []entity.AccessToken {
+	tokens := make([]entity.AccessToken, 0, len(s.Tokens))
+	for _, token := range s.Tokens {
+		role, _ := entity.ParseTokenRole(token.Role) // Validate rejects unknown roles
+		tokens = append(tokens, entity.AccessToken{Label: token.Label, Token: token.Token, File: token.File, Role: role})
+	}
+	return tokens
+}
+
+// ValidateAccessTokens checks every token has a unique label, a known role and exactly one of token and file
+func (s *Server) ValidateAccessTokens() error {
+	labels := make(map[string]bool)
+	for i, token := range s.Tokens {
+		if token.Label == "" {
+			return fmt.Errorf("token %d: label is required", i+1)
+		}
+		if labels[token.Label] {
+			return fmt.Errorf("token %d: label %q is used twice", i+1, token.Label)
+		}
+		labels[token.Label] = true
+		if (token.Token == "") == (token.File == "") {
+			return fmt.Errorf("token %s: exactly one of token and file must be set", token.Label)
+		}
+		if _, err := entity.ParseTokenRole(token.Role); err != nil {
+			return fmt.Errorf("token %s: %w", token.Label, err)
+		}
+	}
+	return nil
+}
+
+// GetHTTPAddress returns the HTTP API listen address, empty when disabled
+func (s *Server) GetHTTPAddress() string {
+	return s.HTTP.Address
+}
+
+// GetRequestsPerSecond returns the log exports allowed per second of each client, 0 when unlimited
+func (s *Server) GetRequestsPerSecond() float64 {
+	return s.Limits.RequestsPerSecond
+}
+
+// GetBurst returns the log exports a client may send at once, 0 derives it from the rate
+func (s *Server) GetBurst() int {
+	return s.Limits.Burst
+}
+
+// GetTLSCertFile returns the certificate served over TLS, empty when TLS is disabled
+func (s *Server) GetTLSCertFile() string {
+	return s.TLS.CertFile
+}
+
+// GetTLSKeyFile returns the private key of the TLS certificate
+func (s *Server) GetTLSKeyFile() string {
+	return s.TLS.KeyFile
+}
+
+// GetTLSClientCAFile returns the CA client certificates must be signed by, empty when clients are not verified
+func (s *Server) GetTLSClientCAFile() string {
+	return s.TLS.ClientCAFile
+}
+
+// GetPProfAddress returns the pprof endpoints listen address, empty when debugging is disabled
+func (s *Server) GetPProfAddress() string {
+	if !s.Debug.PProf {
+		return ""
+	}
+	return s.Debug.PProfAddress
+}
+
+// IsAccessLogEnabled returns true when every query service call is logged
+func (s *Server) IsAccessLogEnabled() bool {
+	return s.QueryLog.Access
+}
+
+// GetSlowQueryThreshold returns the duration above which query service calls are logged as slow, 0 when disabled
+func (s *Server) GetSlowQueryThreshold() time.Duration {
+	threshold, err := s.QueryLog.GetSlowThreshold()
+	if err != nil {
+		return 0
+	}
+	return threshold
+}
+
+// GetSlowQueryLogPath returns the dedicated slow query log file, empty logs to the server log
+func (s *Server) GetSlowQueryLogPath() string {
+	return s.QueryLog.SlowPath
+}
+
+// GetSlowThreshold parses the slow query threshold, empty or "0" disables the slow query log
+func (q *ServerQueryLog) GetSlowThreshold() (time.Duration, error) {
+	if q.SlowThreshold == "" {
+		return 0, nil
+	}
+
+	threshold, err := time.ParseDuration(q.SlowThreshold)
+	if err != nil {
+		return 0, err
+	}
+	if threshold < 0 {
+		return 0, fmt.Errorf("must not be negative, got %s", q.SlowThreshold)
+	}
+	return threshold, nil
+}
+
+// GetSchedule returns when the summary is sent in the timezone, disabled when no time is set
+func (d *ServerDailySummary) GetSchedule(timezone *time.Location) (entity.DailySummarySchedule, error) {
+	return entity.ParseDailySummarySchedule(d.At, timezone)
+}
+
+// Validate validates the time of day and the webhook URL
+func (d *ServerDailySummary) Validate() error {
+	if _, err := d.GetSchedule(time.UTC); err != nil {
+		return fmt.Errorf("at: %w", err)
+	}
+	if d.Webhook == "" {
+		return nil
+	}
+
+	webhook, err := url.Parse(d.Webhook)
+	if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+		return fmt.Errorf("webhook must be an http or https URL, got %q", d.Webhook)
+	}
+	return nil
+}
+
+// IsEnabled returns true if the throttle signal file is written
+func (t *ServerThrottle) IsEnabled() bool {
+	return t.Path != ""
+}
+
+// GetPolicy returns when agents should pause, blocks start at the configured time in the timezone
+func (t *ServerThrottle) GetPolicy(timezone *time.Location, tokenLimit int) (entity.ThrottlePolicy, error) {
+	if t.Block == "" {
+		return entity.ThrottlePolicy{}, fmt.Errorf("block is required, e.g. \"5am\"")
+	}
+	startHour, err := entity.ParseBlockStartHour(t.Block)
+	if err != nil {
+		return entity.ThrottlePolicy{}, fmt.Errorf("invalid block %q: %w", t.Block, err)
+	}
+	return entity.NewThrottlePolicy(startHour, timezone, tokenLimit, t.Threshold)
+}
+
+// GetInterval returns how often the throttle signal file is updated
+func (t *ServerThrottle) GetInterval() (time.Duration, error) {
+	interval, err := time.ParseDuration(t.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q: %w", t.Interval, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("interval must be positive, got %s", t.Interval)
+	}
+	return interval, nil
+}
+
+// Validate validates the block, threshold and interval when the throttle signal is enabled
+func (t *ServerThrottle) Validate() error {
+	if !t.IsEnabled() {
+		return nil
+	}
+	if _, err := t.GetPolicy(time.UTC, 0); err != nil {
+		return err
+	}
+	_, err := t.GetInterval()
+	return err
+}
+
+// Validate validates the replica configuration
+func (r *ServerReplica) Validate() error {
+	if !r.IsEnabled() {
+		return nil
+	}
+
+	if r.Token == "" {
+		return fmt.Errorf("token is required when primary is set")
+	}
+
+	interval, err := time.ParseDuration(r.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval format: %s", r.Interval)
+	}
+
+	// Minimum interval: 10 seconds, a full snapshot is transferred on every sync
+	if interval < 10*time.Second {
+		return fmt.Errorf("interval must be at least 10s, got: %s", r.Interval)
+	}
+
+	if err := r.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid tls: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks the certificate and its key are set together, a client CA needs a certificate to serve
+func (t *ServerTLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if t.ClientCAFile != "" && t.CertFile == "" {
+		return fmt.Errorf("client_ca_file requires cert_file and key_file")
+	}
+	return nil
+}
+
+// Validate checks the client certificate and its key are set together, files without enabled would silently connect in cleartext
+func (t *ClientTLS) Validate() error {
+	if !t.Enabled && (t.CAFile != "" || t.CertFile != "" || t.KeyFile != "") {
+		return fmt.Errorf("enabled must be true when ca_file, cert_file or key_file is set")
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// IsEnabled returns true if the server runs as a replica of a primary
+func (r *ServerReplica) IsEnabled() bool {
+	return r.Primary != ""
+}
+
+// GetPrimary returns the primary server address
+func (r *ServerReplica) GetPrimary() string {
+	return r.Primary
+}
+
+// GetSyncInterval returns the snapshot sync interval
+func (r *ServerReplica) GetSyncInterval() time.Duration {
+	interval, err := time.ParseDuration(r.Interval)
+	if err != nil {
+		return 5 * time.Minute // Should not happen after validation
+	}
+
+	return interval
+}
+
+// GetIgnoreRules returns the ingestion ignore rules
+func (r *Receiver) GetIgnoreRules() (entity.IgnoreRules, error) {
+	return entity.NewIgnoreRules(r.Ignore.Models, r.Ignore.SessionPrefixes)
+}
+
+// GetClockSkewPolicy returns the policy for API requests timestamped ahead of the server clock
+// Detection is disabled when the tolerance is not set
+func (r *Receiver) GetClockSkewPolicy() (entity.ClockSkewPolicy, error) {
+	var tolerance time.Duration
+	if r.ClockSkew.Tolerance != "" {
+		var err error
+		tolerance, err = time.ParseDuration(r.ClockSkew.Tolerance)
+		if err != nil {
+			return entity.ClockSkewPolicy{}, fmt.Errorf("invalid tolerance %q: %w", r.ClockSkew.Tolerance, err)
+		}
+	}
+
+	action := entity.ClockSkewAction(r.ClockSkew.Action)
+	if action == "" {
+		action = entity.ClockSkewClamp
+	}
+
+	return entity.NewClockSkewPolicy(tolerance, action)
+}
+
+// GetDedupeWindow returns how long received API requests are remembered to drop re-sent ones
+// Deduplication is disabled when the window is not set
+func (r *Receiver) GetDedupeWindow() (time.Duration, error) {
+	if r.Dedupe.Window == "" {
+		return 0, nil
+	}
+
+	window, err := time.ParseDuration(r.Dedupe.Window)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q: %w", r.Dedupe.Window, err)
+	}
+	if window < 0 {
+		return 0, fmt.Errorf("window must not be negative, got: %s", r.Dedupe.Window)
+	}
+	return window, nil
+}
+
+// GetTelemetryGapPolicy returns the policy for alerting on silent telemetry loss
+// Detection is disabled when the threshold is not set
+func (r *Receiver) GetTelemetryGapPolicy(timezone *time.Location) (entity.TelemetryGapPolicy, error) {
+	var threshold time.Duration
+	if r.TelemetryGap.Threshold != "" {
+		var err error
+		threshold, err = time.ParseDuration(r.TelemetryGap.Threshold)
+		if err != nil {
+			return entity.TelemetryGapPolicy{}, fmt.Errorf("invalid threshold %q: %w", r.TelemetryGap.Threshold, err)
+		}
+	}
+
+	days := make([]time.Weekday, 0, len(r.TelemetryGap.Days))
+	for _, name := range r.TelemetryGap.Days {
+		day, err := entity.ParseWeekday(name)
+		if err != nil {
+			return entity.TelemetryGapPolicy{}, err
+		}
+		days = append(days, day)
+	}
+
+	return entity.NewTelemetryGapPolicy(threshold, days, timezone)
+}
+
+// GetPlugins returns the configured plugins, in configuration order
+func (r *Receiver) GetPlugins() ([]entity.Plugin, error) {
+	plugins := make([]entity.Plugin, 0, len(r.Plugins))
+	seen := make(map[string]struct{}, len(r.Plugins))
+	for i, plugin := range r.Plugins {
+		if plugin.Name == "" {
+			return nil, fmt.Errorf("plugin %d has no name", i+1)
+		}
+		if _, ok := seen[plugin.Name]; ok {
+			return nil, fmt.Errorf("duplicate plugin name %q", plugin.Name)
+		}
+		seen[plugin.Name] = struct{}{}
+		if len(plugin.Command) == 0 || plugin.Command[0] == "" {
+			return nil, fmt.Errorf("plugin %q has no command", plugin.Name)
+		}
+
+		timeout := entity.DefaultPluginTimeout
+		if plugin.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(plugin.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timeout of plugin %q: %w", plugin.Name, err)
+			}
+			if timeout <= 0 {
+				return nil, fmt.Errorf("timeout of plugin %q must be positive, got: %s", plugin.Name, plugin.Timeout)
+			}
+		}
+
+		plugins = append(plugins, entity.NewPlugin(plugin.Name, plugin.Command, timeout))
+	}
+	return plugins, nil
+}
+
+// GetCount returns the number of workers processing log exports
+func (w *ReceiverWorkers) GetCount() int {
+	return w.Count
+}
+
+// GetQueueSize returns the number of log exports waiting for a worker
+func (w *ReceiverWorkers) GetQueueSize() int {
+	return w.QueueSize
+}
+
+// GetCostFormat returns the display format for cost amounts
+// Without cost_precision the format has no precision, each view keeps the decimals it always used
+func (d *Display) GetCostFormat() entity.CostFormat {
+	if d.CostPrecision == nil {
+		return entity.NewCostFormatWithoutPrecision(d.CostHumanize)
+	}
+	return entity.NewCostFormat(*d.CostPrecision, d.CostHumanize)
+}
+
+// GetTimeFormat returns the display format for dates, times and block labels
+func (d *Display) GetTimeFormat() (entity.TimeFormat, error) {
+	return entity.NewTimeFormat(d.DateFormat, entity.Clock(d.TimeFormat))
+}
+
+// GetHighlight returns the thresholds for highlighting expensive requests
+func (h *MonitorHighlight) GetHighlight() entity.Highlight {
+	return entity.NewHighlight(entity.NewCost(h.Cost), h.Tokens)
+}
+
+// GetFilter returns the dimensions narrowing the requests table, the period is set by the monitor
+func (f *MonitorFilter) GetFilter() entity.Filter {
+	return entity.Filter{}.WithModel(f.Model).WithSessionID(f.Session).WithSource(f.Source).WithOrigin(f.Origin).WithProject(f.Project)
+}
+
+// GetQuota returns the hard spending quota
+func (q *Quota) GetQuota() entity.Quota {
+	return entity.NewQuota(entity.NewCost(q.HardDaily), q.Warning)
+}
+
+// GetUserQuotas returns the daily cost quotas of each user in server mode
+func (q *Quota) GetUserQuotas() entity.UserQuotas {
+	users := make(map[string]entity.Cost, len(q.Users))
+	for _, user := range q.Users {
+		users[user.User] = entity.NewCost(user.Daily)
+	}
+	return entity.NewUserQuotas(entity.NewCost(q.UserDaily), users)
+}
+
+// GetBudget returns the budgets for the budget left variables
+func (b *Budget) GetBudget() entity.Budget {
+	return entity.NewBudget(entity.NewCost(b.Daily), entity.NewCost(b.Monthly))
+}
+
+// GetGoal returns the daily goal streaks are counted against
+func (g *Goal) GetGoal() entity.Goal {
+	return entity.NewGoal(entity.NewCost(g.DailyCost), g.DailyTokens)
+}
+
+// GetCostAnomalyPolicy returns the multiple of the 7-day baseline above which days are flagged
+func (a *Anomaly) GetCostAnomalyPolicy() entity.CostAnomalyPolicy {
+	return entity.NewCostAnomalyPolicy(a.Multiple)
+}
+
+// IsEnabled returns true if any alert rule is configured
+func (a *Alerts) IsEnabled() bool {
+	return len(a.Rules) > 0
+}
+
+// GetPolicy returns the alert rules, days and months follow the timezone and blocks start at the configured time in it
+func (a *Alerts) GetPolicy(timezone *time.Location, tokenLimit int) (entity.AlertPolicy, error) {
+	rules := make([]entity.AlertRule, 0, len(a.Rules))
+	hasBlockRule := false
+	for _, config := range a.Rules {
+		rule, err := entity.NewAlertRule(config.Metric, config.Above, config.Percent)
+		if err != nil {
+			return entity.AlertPolicy{}, fmt.Errorf("rules: %w", err)
+		}
+		hasBlockRule = hasBlockRule || rule.IsBlock()
+		rules = append(rules, rule)
+	}
+
+	startHour := 0
+	if a.Block != "" {
+		var err error
+		startHour, err = entity.ParseBlockStartHour(a.Block)
+		if err != nil {
+			return entity.AlertPolicy{}, fmt.Errorf("invalid block %q: %w", a.Block, err)
+		}
+	} else if hasBlockRule {
+		return entity.AlertPolicy{}, fmt.Errorf("block is required by block_cost and block_tokens rules, e.g. \"5am\"")
+	}
+
+	return entity.NewAlertPolicy(rules, startHour, timezone, tokenLimit), nil
+}
+
+// GetWebhooks returns the webhooks the alerts are posted to
+func (a *Alerts) GetWebhooks() ([]service.AlertWebhook, error) {
+	webhooks := make([]service.AlertWebhook, 0, len(a.Webhooks))
+	for _, config := range a.Webhooks {
+		webhook, err := url.Parse(config.URL)
+		if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+			return nil, fmt.Errorf("webhooks: url must be an http or https URL, got %q", config.URL)
+		}
+		format, err := service.ParseWebhookFormat(config.Format)
+		if err != nil {
+			return nil, fmt.Errorf("webhooks: %w", err)
+		}
+		webhooks = append(webhooks, service.AlertWebhook{URL: config.URL, Format: format})
+	}
+	return webhooks, nil
+}
+
+// Validate validates the rules, the block and the webhooks
+func (a *Alerts) Validate() error {
+	if _, err := a.GetPolicy(time.UTC, 0); err != nil {
+		return err
+	}
+	_, err := a.GetWebhooks()
+	return err
+}
+
 // GetTokenLimit returns the effective token limit based on plan and config
 func (c *Claude) GetTokenLimit() int {
 	// If max_tokens is explicitly set, use it
@@ -290,7 +1464,7 @@ func (c *Claude) GetTokenLimit() int {
 	}
 
 	// Otherwise, use plan defaults
-	switch c.Plan {
+	switch c.GetPlan() {
 	case "pro":
 		return 7000
 	case "max":
@@ -302,7 +1476,36 @@ func (c *Claude) GetTokenLimit() int {
 	}
 }
 
+// GetBlockDuration returns the length of the token limit blocks, 5 hours unless configured
+// The plan token limits are those of 5-hour blocks, other lengths usually need claude.max_tokens
+func (c *Claude) GetBlockDuration() time.Duration {
+	duration, err := entity.ParseBlockDuration(c.BlockDuration)
+	if err != nil {
+		return entity.TimeBlockDuration // Rejected by Validate
+	}
+	return duration
+}
+
+// GetUserPlans returns the plans of the team members, members without an own plan use claude.plan
+func (c *Claude) GetUserPlans() entity.UserPlans {
+	users := make(map[string]string, len(c.Users))
+	for _, user := range c.Users {
+		users[user.User] = user.Plan
+	}
+	return entity.NewUserPlans(c.Plan, users)
+}
+
+// GetPlan returns your plan, the plan assigned to claude.user or claude.plan
+func (c *Claude) GetPlan() string {
+	return c.GetUserPlans().PlanFor(c.User)
+}
+
 // GetClaudePlan returns the configured Claude plan, implementing PlanConfig interface
 func (c *Config) GetClaudePlan() string {
 	return c.Claude.Plan
 }
+
+// GetClaudeUserPlan returns the plan of the team member, implementing PlanConfig interface
+func (c *Config) GetClaudeUserPlan(user string) string {
+	return c.Claude.GetUserPlans().PlanFor(user)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// configMigration maps a deprecated config key to the key replacing it
+type configMigration struct {
+	from string
+	to   string
+}
+
+// configMigrations lists the renamed config keys, add an entry when a key is renamed
+// Deprecated keys keep working with a warning until the config file is migrated
+var configMigrations = []configMigration{
+	{from: "server.cache.enabled", to: "server.cache.stats.enabled"},
+	{from: "server.cache.ttl", to: "server.cache.stats.ttl"},
+}
+
+// migrateConfig applies deprecated keys of the loaded config files to their new keys and logs a warning for each
+// Values are merged into the config file layer, so command-line flags still override them
+// A new key set in the config files wins over its deprecated key
+func migrateConfig(v *viper.Viper) error {
+	for _, m := range configMigrations {
+		if !v.InConfig(m.from) {
+			continue
+		}
+
+		if v.InConfig(m.to) {
+			log.Printf("Warning: config key %s is deprecated and ignored, %s is already set", m.from, m.to)
+			continue
+		}
+
+		log.Printf("Warning: config key %s is deprecated, use %s instead (run `ccmon config migrate --write` to update the config file)", m.from, m.to)
+		if err := v.MergeConfigMap(nestedConfigMap(m.to, v.Get(m.from))); err != nil {
+			return fmt.Errorf("error migrating config key %s: %w", m.from, err)
+		}
+	}
+
+	return nil
+}
+
+// nestedConfigMap builds the nested map of a dotted config key, e.g. a.b = 1 becomes {a: {b: 1}}
+func nestedConfigMap(key string, value any) map[string]any {
+	parts := strings.Split(key, ".")
+
+	result := map[string]any{parts[len(parts)-1]: value}
+	for i := len(parts) - 2; i >= 0; i-- {
+		result = map[string]any{parts[i]: result}
+	}
+	return result
+}
+
+// configFileMigration is the outcome of migrating a single config file
+type configFileMigration struct {
+	path     string
+	settings map[string]any
+	renamed  []configMigration
+}
+
+// migrateConfigFile renames the deprecated keys of a single config file, without defaults or flags
+func migrateConfigFile(path string) (configFileMigration, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return configFileMigration{}, fmt.Errorf("error reading config %s: %w", path, err)
+	}
+
+	result := configFileMigration{path: path}
+	for _, m := range configMigrations {
+		if !v.InConfig(m.from) {
+			continue
+		}
+
+		if !v.InConfig(m.to) {
+			if err := v.MergeConfigMap(nestedConfigMap(m.to, v.Get(m.from))); err != nil {
+				return configFileMigration{}, fmt.Errorf("error migrating config key %s: %w", m.from, err)
+			}
+		}
+		result.renamed = append(result.renamed, m)
+	}
+
+	result.settings = v.AllSettings()
+	for _, m := range result.renamed {
+		deleteConfigKey(result.settings, m.from)
+	}
+	return result, nil
+}
+
+// deleteConfigKey removes a dotted config key from nested settings, dropping tables left empty
+func deleteConfigKey(settings map[string]any, key string) {
+	head, rest, nested := strings.Cut(key, ".")
+	if !nested {
+		delete(settings, head)
+		return
+	}
+
+	child, ok := settings[head].(map[string]any)
+	if !ok {
+		return
+	}
+
+	deleteConfigKey(child, rest)
+	if len(child) == 0 {
+		delete(settings, head)
+	}
+}
+
+// write rewrites the config file with the migrated settings, keeping the original as a .bak file
+// Comments are not preserved by the rewrite
+func (m configFileMigration) write() error {
+	original, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("error reading config %s: %w", m.path, err)
+	}
+	if err := os.WriteFile(m.path+".bak", original, 0600); err != nil {
+		return fmt.Errorf("error backing up config %s: %w", m.path, err)
+	}
+
+	v := viper.New()
+	if err := v.MergeConfigMap(m.settings); err != nil {
+		return fmt.Errorf("error migrating config %s: %w", m.path, err)
+	}
+	v.SetConfigType(strings.TrimPrefix(filepath.Ext(m.path), "."))
+	if err := v.WriteConfigAs(m.path); err != nil {
+		return fmt.Errorf("error writing config %s: %w", m.path, err)
+	}
+
+	return nil
+}
+
+// runConfig runs the config subcommands and returns the exit code
+func runConfig(config *Config, command string, write bool) int {
+	switch command {
+	case "migrate":
+		return runConfigMigrate(config, write)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config command: %q, usage: ccmon config migrate [--write]\n", command)
+		return 1
+	}
+}
+
+// runConfigMigrate lists the deprecated keys of the config file and its includes, and rewrites them with --write
+func runConfigMigrate(config *Config, write bool) int {
+	if config.file == "" {
+		fmt.Println("No config file found, nothing to migrate")
+		return 0
+	}
+
+	paths := append([]string{config.file}, config.includes...)
+	pending := 0
+	for _, path := range paths {
+		migration, err := migrateConfigFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		if len(migration.renamed) == 0 {
+			continue
+		}
+
+		for _, m := range migration.renamed {
+			fmt.Printf("%s: %s -> %s\n", path, m.from, m.to)
+		}
+		pending += len(migration.renamed)
+
+		if !write {
+			continue
+		}
+		if err := migration.write(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("Rewrote %s, the original is kept as %s.bak\n", path, path)
+	}
+
+	switch {
+	case pending == 0:
+		fmt.Println("Config is up to date, nothing to migrate")
+	case !write:
+		fmt.Printf("%d deprecated keys found, run `ccmon config migrate --write` to rewrite the config files\n", pending)
+	}
+	return 0
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantEnabled bool
+		wantTTL     string
+		wantLog     string
+	}{
+		{
+			name:        "current keys are kept without warning",
+			content:     "[server.cache.stats]\nenabled = false\nttl = \"5m\"\n",
+			wantEnabled: false,
+			wantTTL:     "5m",
+		},
+		{
+			name:        "deprecated keys are mapped to new keys",
+			content:     "[server.cache]\nenabled = false\nttl = \"30s\"\n",
+			wantEnabled: false,
+			wantTTL:     "30s",
+			wantLog:     "config key server.cache.ttl is deprecated, use server.cache.stats.ttl instead",
+		},
+		{
+			name:        "new key wins over deprecated key",
+			content:     "[server.cache]\nttl = \"30s\"\n[server.cache.stats]\nttl = \"2m\"\n",
+			wantEnabled: true,
+			wantTTL:     "2m",
+			wantLog:     "config key server.cache.ttl is deprecated and ignored, server.cache.stats.ttl is already set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			v := viper.New()
+			v.SetDefault("server.cache.stats.enabled", true)
+			v.SetDefault("server.cache.stats.ttl", "1m")
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				t.Fatalf("failed to read config: %v", err)
+			}
+
+			if err := migrateConfig(v); err != nil {
+				t.Fatalf("migrateConfig() unexpected error = %v", err)
+			}
+
+			if got := v.GetBool("server.cache.stats.enabled"); got != tt.wantEnabled {
+				t.Errorf("server.cache.stats.enabled = %v, want %v", got, tt.wantEnabled)
+			}
+			if got := v.GetString("server.cache.stats.ttl"); got != tt.wantTTL {
+				t.Errorf("server.cache.stats.ttl = %q, want %q", got, tt.wantTTL)
+			}
+
+			if tt.wantLog == "" && buf.Len() > 0 {
+				t.Errorf("expected no warning, got: %s", buf.String())
+			}
+			if tt.wantLog != "" && !strings.Contains(buf.String(), tt.wantLog) {
+				t.Errorf("expected warning %q, got: %s", tt.wantLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantRenamed int
+		wantTTL     string
+	}{
+		{
+			name:        "up to date config",
+			content:     "[server.cache.stats]\nttl = \"5m\"\n",
+			wantRenamed: 0,
+			wantTTL:     "5m",
+		},
+		{
+			name:        "deprecated keys are rewritten",
+			content:     "[server]\naddress = \"0.0.0.0:4317\"\n[server.cache]\nenabled = false\nttl = \"30s\"\n",
+			wantRenamed: 2,
+			wantTTL:     "30s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			migration, err := migrateConfigFile(path)
+			if err != nil {
+				t.Fatalf("migrateConfigFile() unexpected error = %v", err)
+			}
+			if len(migration.renamed) != tt.wantRenamed {
+				t.Errorf("renamed keys = %d, want %d", len(migration.renamed), tt.wantRenamed)
+			}
+
+			if err := migration.write(); err != nil {
+				t.Fatalf("write() unexpected error = %v", err)
+			}
+
+			backup, err := os.ReadFile(path + ".bak")
+			if err != nil {
+				t.Fatalf("failed to read backup: %v", err)
+			}
+			if string(backup) != tt.content {
+				t.Errorf("backup = %q, want original %q", backup, tt.content)
+			}
+
+			v := viper.New()
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				t.Fatalf("failed to read migrated config: %v", err)
+			}
+			if got := v.GetString("server.cache.stats.ttl"); got != tt.wantTTL {
+				t.Errorf("server.cache.stats.ttl = %q, want %q", got, tt.wantTTL)
+			}
+			for _, m := range configMigrations {
+				if v.InConfig(m.from) {
+					t.Errorf("deprecated key %s still in migrated config", m.from)
+				}
+			}
+		})
+	}
+}
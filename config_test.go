@@ -1,9 +1,14 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/spf13/viper"
 )
 
 func TestServer_ValidateRetention(t *testing.T) {
@@ -151,6 +156,481 @@ func TestServer_IsRetentionEnabled(t *testing.T) {
 	}
 }
 
+func TestServer_GetPProfAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		debug ServerDebug
+		want  string
+	}{
+		{
+			name:  "disabled",
+			debug: ServerDebug{PProf: false, PProfAddress: "127.0.0.1:6060"},
+			want:  "",
+		},
+		{
+			name:  "enabled",
+			debug: ServerDebug{PProf: true, PProfAddress: "127.0.0.1:6060"},
+			want:  "127.0.0.1:6060",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{
+				Debug: tt.debug,
+			}
+
+			got := server.GetPProfAddress()
+			if got != tt.want {
+				t.Errorf("GetPProfAddress() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerQueryLog_GetSlowThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "empty disables", threshold: "", want: 0},
+		{name: "zero disables", threshold: "0", want: 0},
+		{name: "duration", threshold: "500ms", want: 500 * time.Millisecond},
+		{name: "invalid", threshold: "slow", wantErr: true},
+		{name: "negative", threshold: "-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryLog := &ServerQueryLog{SlowThreshold: tt.threshold}
+
+			got, err := queryLog.GetSlowThreshold()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSlowThreshold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSlowThreshold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerDailySummary_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary ServerDailySummary
+		wantErr string
+	}{
+		{name: "disabled", summary: ServerDailySummary{}},
+		{name: "log only", summary: ServerDailySummary{At: "18:00"}},
+		{name: "webhook", summary: ServerDailySummary{At: "18:00", Webhook: "https://hooks.example.com/services/T000"}},
+		{name: "invalid time", summary: ServerDailySummary{At: "6pm"}, wantErr: "at: invalid time of day"},
+		{name: "webhook without scheme", summary: ServerDailySummary{At: "18:00", Webhook: "hooks.example.com"}, wantErr: "webhook must be an http or https URL"},
+		{name: "webhook with other scheme", summary: ServerDailySummary{At: "18:00", Webhook: "ftp://example.com"}, wantErr: "webhook must be an http or https URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.summary.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerTLS_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     ServerTLS
+		wantErr string
+	}{
+		{name: "disabled", tls: ServerTLS{}},
+		{name: "certificate", tls: ServerTLS{CertFile: "cert.pem", KeyFile: "key.pem"}},
+		{name: "client certificates", tls: ServerTLS{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: "ca.pem"}},
+		{name: "key without certificate", tls: ServerTLS{KeyFile: "key.pem"}, wantErr: "cert_file and key_file must be set together"},
+		{name: "client CA without certificate", tls: ServerTLS{ClientCAFile: "ca.pem"}, wantErr: "client_ca_file requires cert_file and key_file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tls.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientTLS_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     ClientTLS
+		wantErr string
+	}{
+		{name: "disabled", tls: ClientTLS{}},
+		{name: "system roots", tls: ClientTLS{Enabled: true}},
+		{name: "client certificate", tls: ClientTLS{Enabled: true, CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem"}},
+		{name: "files without enabled", tls: ClientTLS{CAFile: "ca.pem"}, wantErr: "enabled must be true"},
+		{name: "certificate without key", tls: ClientTLS{Enabled: true, CertFile: "cert.pem"}, wantErr: "cert_file and key_file must be set together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tls.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerThrottle_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		throttle ServerThrottle
+		wantErr  string
+	}{
+		{name: "disabled", throttle: ServerThrottle{}},
+		{name: "enabled", throttle: ServerThrottle{Path: "/tmp/throttle.json", Block: "5am", Threshold: 90, Interval: "10s"}},
+		{name: "missing block", throttle: ServerThrottle{Path: "/tmp/throttle.json", Threshold: 90, Interval: "10s"}, wantErr: "block is required"},
+		{name: "invalid block", throttle: ServerThrottle{Path: "/tmp/throttle.json", Block: "25am", Threshold: 90, Interval: "10s"}, wantErr: "invalid block"},
+		{name: "zero threshold", throttle: ServerThrottle{Path: "/tmp/throttle.json", Block: "5am", Interval: "10s"}, wantErr: "threshold must be positive"},
+		{name: "invalid interval", throttle: ServerThrottle{Path: "/tmp/throttle.json", Block: "5am", Threshold: 90, Interval: "soon"}, wantErr: "invalid interval"},
+		{name: "zero interval", throttle: ServerThrottle{Path: "/tmp/throttle.json", Block: "5am", Threshold: 90, Interval: "0s"}, wantErr: "interval must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.throttle.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_GetCleanupInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval string
+		want     time.Duration
+		wantErr  string
+	}{
+		{name: "unset", interval: "", want: 6 * time.Hour, wantErr: "invalid interval"},
+		{name: "hourly", interval: "1h", want: time.Hour},
+		{name: "too short", interval: "30s", want: 6 * time.Hour, wantErr: "interval must be at least 1m"},
+		{name: "invalid", interval: "daily", want: 6 * time.Hour, wantErr: "invalid interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{Cleanup: ServerCleanup{Interval: tt.interval}}
+			if got := server.GetCleanupInterval(); got != tt.want {
+				t.Errorf("GetCleanupInterval() = %v, want %v", got, tt.want)
+			}
+
+			_, err := server.Cleanup.GetInterval()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("GetInterval() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GetInterval() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabaseBackup_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		backup  DatabaseBackup
+		wantErr string
+	}{
+		{name: "disabled", backup: DatabaseBackup{Interval: "daily"}},
+		{name: "hourly", backup: DatabaseBackup{Path: "/data/ccmon.db.bak", Interval: "1h"}},
+		{name: "too short", backup: DatabaseBackup{Path: "/data/ccmon.db.bak", Interval: "30s"}, wantErr: "interval must be at least 1m"},
+		{name: "same path", backup: DatabaseBackup{Path: "/data/ccmon.db", Interval: "1h"}, wantErr: "must differ from database.path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Database: Database{Path: "/data/ccmon.db", Backup: tt.backup},
+				Server:   Server{Retention: "never"},
+				Claude:   Claude{Plan: "pro"},
+				Monitor:  Monitor{Timezone: "UTC"},
+			}
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDatabaseWriteBehind_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		writeBehind DatabaseWriteBehind
+		want        time.Duration
+		wantErr     string
+	}{
+		{name: "enabled", writeBehind: DatabaseWriteBehind{Interval: "100ms", Size: 1000}, want: 100 * time.Millisecond},
+		{name: "disabled", writeBehind: DatabaseWriteBehind{Interval: "0"}},
+		{name: "unset", writeBehind: DatabaseWriteBehind{}},
+		{name: "invalid interval", writeBehind: DatabaseWriteBehind{Interval: "soon"}, wantErr: "invalid interval"},
+		{name: "negative interval", writeBehind: DatabaseWriteBehind{Interval: "-1s"}, wantErr: "must not be negative"},
+		{name: "negative size", writeBehind: DatabaseWriteBehind{Interval: "1s", Size: -1}, wantErr: "database.write_behind.size must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Database: Database{Path: "/data/ccmon.db", WriteBehind: tt.writeBehind},
+				Server:   Server{Retention: "never"},
+				Claude:   Claude{Plan: "pro"},
+				Monitor:  Monitor{Timezone: "UTC"},
+			}
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				if got, _ := tt.writeBehind.GetInterval(); got != tt.want {
+					t.Errorf("GetInterval() = %v, want %v", got, tt.want)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_ValidateSnapshotTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  Server
+		want    []string // labels of the accepted tokens
+		wantErr string
+	}{
+		{name: "none", server: Server{}},
+		{name: "single token", server: Server{SnapshotToken: "secret"}, want: []string{"snapshot_token"}},
+		{
+			name: "rotating tokens",
+			server: Server{SnapshotToken: "secret", SnapshotTokens: []ServerSnapshotToken{
+				{Label: "replica-tokyo", Token: "next-secret"},
+				{Label: "replica-berlin", File: "/run/secrets/ccmon-snapshot"},
+			}},
+			want: []string{"snapshot_token", "replica-tokyo", "replica-berlin"},
+		},
+		{name: "missing label", server: Server{SnapshotTokens: []ServerSnapshotToken{{Token: "secret"}}}, wantErr: "label is required"},
+		{
+			name: "duplicate label",
+			server: Server{SnapshotToken: "secret", SnapshotTokens: []ServerSnapshotToken{
+				{Label: "snapshot_token", Token: "next-secret"},
+			}},
+			wantErr: `label "snapshot_token" is used twice`,
+		},
+		{name: "neither token nor file", server: Server{SnapshotTokens: []ServerSnapshotToken{{Label: "replica"}}}, wantErr: "exactly one of token and file"},
+		{
+			name:    "both token and file",
+			server:  Server{SnapshotTokens: []ServerSnapshotToken{{Label: "replica", Token: "secret", File: "/run/secrets/ccmon-snapshot"}}},
+			wantErr: "exactly one of token and file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.ValidateSnapshotTokens()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateSnapshotTokens() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateSnapshotTokens() returned error: %v", err)
+			}
+
+			tokens := tt.server.GetSnapshotTokens()
+			if len(tokens) != len(tt.want) {
+				t.Fatalf("Expected %d tokens, got %d", len(tt.want), len(tokens))
+			}
+			for i, label := range tt.want {
+				if tokens[i].Label != label {
+					t.Errorf("Expected token %d to be labelled %q, got %q", i, label, tokens[i].Label)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ValidateAccessTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  []ServerToken
+		want    []entity.TokenRole // roles of the accepted tokens
+		wantErr string
+	}{
+		{name: "none"},
+		{
+			name: "scoped tokens",
+			tokens: []ServerToken{
+				{Label: "dashboard", Token: "read-secret", Role: "read"},
+				{Label: "claude-code", File: "/run/secrets/ccmon-write", Role: "write"},
+				{Label: "grafana", Token: "other-secret"},
+			},
+			want: []entity.TokenRole{entity.TokenRoleRead, entity.TokenRoleWrite, entity.TokenRoleRead},
+		},
+		{name: "missing label", tokens: []ServerToken{{Token: "secret"}}, wantErr: "label is required"},
+		{
+			name:    "duplicate label",
+			tokens:  []ServerToken{{Label: "dashboard", Token: "secret"}, {Label: "dashboard", Token: "next-secret"}},
+			wantErr: `label "dashboard" is used twice`,
+		},
+		{name: "neither token nor file", tokens: []ServerToken{{Label: "dashboard"}}, wantErr: "exactly one of token and file"},
+		{name: "unknown role", tokens: []ServerToken{{Label: "dashboard", Token: "secret", Role: "admin"}}, wantErr: `unknown role "admin"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := Server{Tokens: tt.tokens}
+			err := server.ValidateAccessTokens()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateAccessTokens() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAccessTokens() returned error: %v", err)
+			}
+
+			tokens := server.GetAccessTokens()
+			if len(tokens) != len(tt.want) {
+				t.Fatalf("Expected %d tokens, got %d", len(tt.want), len(tokens))
+			}
+			for i, role := range tt.want {
+				if tokens[i].Role != role {
+					t.Errorf("Expected token %d to have role %q, got %q", i, role, tokens[i].Role)
+				}
+			}
+		})
+	}
+}
+
+func TestAlerts_Validate(t *testing.T) {
+	webhooks := []AlertWebhook{{URL: "https://hooks.slack.com/services/T000/B000/XXX", Format: "slack"}}
+
+	tests := []struct {
+		name    string
+		alerts  Alerts
+		wantErr string
+	}{
+		{name: "disabled", alerts: Alerts{}},
+		{name: "cost rule without block", alerts: Alerts{Rules: []AlertRule{{Metric: "daily_cost", Above: 10}}, Webhooks: webhooks}},
+		{name: "block rule", alerts: Alerts{Block: "5am", Rules: []AlertRule{{Metric: "block_tokens", Percent: 80}}}},
+		{name: "block rule without block", alerts: Alerts{Rules: []AlertRule{{Metric: "block_cost", Above: 5}}}, wantErr: "block is required"},
+		{name: "invalid block", alerts: Alerts{Block: "25am", Rules: []AlertRule{{Metric: "block_cost", Above: 5}}}, wantErr: "invalid block"},
+		{name: "unknown metric", alerts: Alerts{Rules: []AlertRule{{Metric: "weekly_cost", Above: 10}}}, wantErr: "unknown metric"},
+		{name: "missing threshold", alerts: Alerts{Rules: []AlertRule{{Metric: "daily_cost"}}}, wantErr: "exactly one of above or percent"},
+		{name: "invalid webhook URL", alerts: Alerts{Webhooks: []AlertWebhook{{URL: "hooks.slack.com"}}}, wantErr: "http or https URL"},
+		{name: "unknown webhook format", alerts: Alerts{Webhooks: []AlertWebhook{{URL: "https://example.com/hook", Format: "teams"}}}, wantErr: "unknown webhook format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.alerts.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlerts_GetPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[alerts]\nblock = \"5am\"\n\n[[alerts.rules]]\nmetric = \"daily_cost\"\nabove = 10.0\n\n[[alerts.rules]]\nmetric = \"block_tokens\"\npercent = 80\n\n[[alerts.webhooks]]\nurl = \"https://discord.com/api/webhooks/1/abc\"\nformat = \"discord\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var alerts Alerts
+	if err := v.UnmarshalKey("alerts", &alerts); err != nil {
+		t.Fatalf("failed to unmarshal alerts: %v", err)
+	}
+
+	policy, err := alerts.GetPolicy(time.UTC, 7000)
+	if err != nil {
+		t.Fatalf("GetPolicy() returned error: %v", err)
+	}
+	var rules []string
+	for _, rule := range policy.Rules() {
+		rules = append(rules, rule.String())
+	}
+	if got := strings.Join(rules, ", "); got != "daily_cost > 10, block_tokens > 80%" {
+		t.Errorf("Rules() = %q, want %q", got, "daily_cost > 10, block_tokens > 80%")
+	}
+
+	webhooks, err := alerts.GetWebhooks()
+	if err != nil {
+		t.Fatalf("GetWebhooks() returned error: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].Format != "discord" {
+		t.Errorf("GetWebhooks() = %v, want a single discord webhook", webhooks)
+	}
+}
+
 func TestServer_GetRetentionDuration(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -214,6 +694,8 @@ func TestServer_GetRetentionDuration(t *testing.T) {
 }
 
 func TestConfig_ValidateRetentionIntegration(t *testing.T) {
+	invalidPrecision := 12
+
 	tests := []struct {
 		name    string
 		config  Config
@@ -255,25 +737,795 @@ func TestConfig_ValidateRetentionIntegration(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid server.retention",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.Validate()
-
-			if tt.wantErr {
-				if err == nil {
-					t.Errorf("Config.Validate() expected error but got none")
-					return
-				}
-				if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
-					t.Errorf("Config.Validate() error = %v, want error containing %v", err, tt.errMsg)
-				}
-			} else {
-				if err != nil {
-					t.Errorf("Config.Validate() unexpected error = %v", err)
-				}
-			}
-		})
+		{
+			name: "invalid config with bad ignore model pattern",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Receiver: Receiver{
+					Ignore: ReceiverIgnore{
+						Models: []string{"haiku("},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid receiver.ignore",
+		},
+		{
+			name: "invalid config with unsupported clock skew action",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Receiver: Receiver{
+					ClockSkew: ReceiverClockSkew{
+						Tolerance: "5m",
+						Action:    "ignore",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid receiver.clock_skew",
+		},
+		{
+			name: "invalid config with unsupported telemetry gap day",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Receiver: Receiver{
+					TelemetryGap: ReceiverTelemetryGap{
+						Threshold: "2h",
+						Days:      []string{"mon", "workday"},
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid receiver.telemetry_gap",
+		},
+		{
+			name: "invalid config with negative rate limit",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Limits: ServerLimits{
+						RequestsPerSecond: -1,
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.limits.requests_per_second must not be negative",
+		},
+		{
+			name: "invalid config with negative rate limit burst",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Limits: ServerLimits{
+						RequestsPerSecond: 10,
+						Burst:             -1,
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "server.limits.burst must not be negative",
+		},
+		{
+			name: "invalid config with negative receiver workers",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Receiver: Receiver{
+					Workers: ReceiverWorkers{
+						Count: -1,
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "receiver.workers.count must not be negative",
+		},
+		{
+			name: "invalid config with replica missing token",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Replica: ServerReplica{
+						Primary:  "primary.example.com:4317",
+						Interval: "5m",
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.replica",
+		},
+		{
+			name: "invalid config with too short replica interval",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					Replica: ServerReplica{
+						Primary:  "primary.example.com:4317",
+						Token:    "secret",
+						Interval: "1s",
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.replica",
+		},
+		{
+			name: "invalid config with TLS certificate without key",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+					TLS: ServerTLS{
+						CertFile: "/etc/ccmon/cert.pem",
+					},
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid server.tls",
+		},
+		{
+			name: "invalid config with monitor TLS files but not enabled",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					TLS: ClientTLS{
+						CAFile: "/etc/ccmon/ca.pem",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid monitor.tls",
+		},
+		{
+			name: "invalid config with negative hard daily quota",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Quota: Quota{
+					HardDaily: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "quota.hard_daily",
+		},
+		{
+			name: "invalid config with negative user daily quota",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Quota: Quota{
+					Users: []QuotaUser{{User: "alice@example.com", Daily: -1}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "quota.users",
+		},
+		{
+			name: "invalid config with negative monthly budget",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Budget: Budget{
+					Monthly: -10,
+				},
+			},
+			wantErr: true,
+			errMsg:  "budget.monthly",
+		},
+		{
+			name: "invalid config with negative highlight cost",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Highlight: MonitorHighlight{
+						Cost: -0.5,
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.highlight.cost",
+		},
+		{
+			name: "invalid config with out of range cost precision",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Display: Display{
+					CostPrecision: &invalidPrecision,
+				},
+			},
+			wantErr: true,
+			errMsg:  "display.cost_precision",
+		},
+		{
+			name: "invalid config with unsupported date format",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Display: Display{
+					DateFormat: "%Y-%m-%d",
+				},
+			},
+			wantErr: true,
+			errMsg:  "display.date_format",
+		},
+		{
+			name: "invalid config with unsupported time format",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Display: Display{
+					TimeFormat: "am/pm",
+				},
+			},
+			wantErr: true,
+			errMsg:  "display.time_format",
+		},
+		{
+			name: "invalid config with negative goal",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Goal: Goal{
+					DailyCost: -1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "goal.daily_cost",
+		},
+		{
+			name: "invalid config with anomaly multiple of 1",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+				},
+				Anomaly: Anomaly{
+					Multiple: 1,
+				},
+			},
+			wantErr: true,
+			errMsg:  "anomaly.multiple",
+		},
+		{
+			name: "invalid config with unsupported monitor filter origin",
+			config: Config{
+				Server: Server{
+					Address:   "127.0.0.1:4317",
+					Retention: "never",
+				},
+				Claude: Claude{
+					Plan: "pro",
+				},
+				Monitor: Monitor{
+					Timezone: "UTC",
+					Filter: MonitorFilter{
+						Origin: "webhook",
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "monitor.filter.origin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Config.Validate() expected error but got none")
+					return
+				}
+				if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Config.Validate() error = %v, want error containing %v", err, tt.errMsg)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Config.Validate() unexpected error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeIncludes(t *testing.T) {
+	tests := []struct {
+		name         string
+		main         string
+		files        map[string]string
+		wantAddress  string
+		wantServer   string
+		wantTimezone string
+		wantErr      bool
+		errMsg       string
+	}{
+		{
+			name:        "no includes keeps main config",
+			main:        "[server]\naddress = \"0.0.0.0:4317\"\n",
+			wantAddress: "0.0.0.0:4317",
+		},
+		{
+			name: "included files provide values",
+			main: "include = [\"server.toml\", \"monitor.toml\"]\n",
+			files: map[string]string{
+				"server.toml":  "[server]\naddress = \"10.0.0.1:4317\"\n",
+				"monitor.toml": "[monitor]\nserver = \"10.0.0.1:4317\"\ntimezone = \"Asia/Tokyo\"\n",
+			},
+			wantAddress:  "10.0.0.1:4317",
+			wantServer:   "10.0.0.1:4317",
+			wantTimezone: "Asia/Tokyo",
+		},
+		{
+			name: "main config overrides included files",
+			main: "include = [\"server.toml\"]\n[server]\naddress = \"127.0.0.1:9999\"\n",
+			files: map[string]string{
+				"server.toml": "[server]\naddress = \"10.0.0.1:4317\"\n[monitor]\ntimezone = \"UTC\"\n",
+			},
+			wantAddress:  "127.0.0.1:9999",
+			wantTimezone: "UTC",
+		},
+		{
+			name: "later includes override earlier ones",
+			main: "include = [\"shared.toml\", \"personal.toml\"]\n",
+			files: map[string]string{
+				"shared.toml":   "[monitor]\ntimezone = \"UTC\"\nserver = \"shared:4317\"\n",
+				"personal.toml": "[monitor]\ntimezone = \"Europe/London\"\n",
+			},
+			wantServer:   "shared:4317",
+			wantTimezone: "Europe/London",
+		},
+		{
+			name: "included file in subdirectory",
+			main: "include = [\"conf.d/server.toml\"]\n",
+			files: map[string]string{
+				"conf.d/server.toml": "[server]\naddress = \"10.0.0.2:4317\"\n",
+			},
+			wantAddress: "10.0.0.2:4317",
+		},
+		{
+			name:    "missing included file",
+			main:    "include = [\"missing.toml\"]\n",
+			wantErr: true,
+			errMsg:  "error reading included config missing.toml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			mainPath := filepath.Join(dir, "config.toml")
+			if err := os.WriteFile(mainPath, []byte(tt.main), 0600); err != nil {
+				t.Fatalf("failed to write main config: %v", err)
+			}
+			for name, content := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+					t.Fatalf("failed to write included config: %v", err)
+				}
+			}
+
+			v := viper.New()
+			v.SetConfigFile(mainPath)
+			if err := v.ReadInConfig(); err != nil {
+				t.Fatalf("failed to read main config: %v", err)
+			}
+
+			err := mergeIncludes(v)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("mergeIncludes() expected error but got none")
+					return
+				}
+				if tt.errMsg != "" && !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("mergeIncludes() error = %v, want error containing %v", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeIncludes() unexpected error = %v", err)
+			}
+
+			if got := v.GetString("server.address"); got != tt.wantAddress {
+				t.Errorf("server.address = %q, want %q", got, tt.wantAddress)
+			}
+			if got := v.GetString("monitor.server"); got != tt.wantServer {
+				t.Errorf("monitor.server = %q, want %q", got, tt.wantServer)
+			}
+			if got := v.GetString("monitor.timezone"); got != tt.wantTimezone {
+				t.Errorf("monitor.timezone = %q, want %q", got, tt.wantTimezone)
+			}
+		})
+	}
+}
+
+func TestClaude_GetUserPlans(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[claude]\nplan = \"pro\"\nuser = \"alice@example.com\"\n\n[[claude.users]]\nuser = \"alice@example.com\"\nplan = \"max\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var claude Claude
+	if err := v.UnmarshalKey("claude", &claude); err != nil {
+		t.Fatalf("failed to unmarshal claude: %v", err)
+	}
+
+	if got := claude.GetUserPlans().PlanFor("bob@example.com"); got != "pro" {
+		t.Errorf("PlanFor(bob) = %s, want pro", got)
+	}
+	if got := claude.GetPlan(); got != "max" {
+		t.Errorf("GetPlan() = %s, want max", got)
+	}
+	// The token limit follows the plan of claude.user
+	if got := claude.GetTokenLimit(); got != 35000 {
+		t.Errorf("GetTokenLimit() = %d, want 35000", got)
+	}
+
+	claude.Users = append(claude.Users, ClaudeUser{User: "bob@example.com", Plan: "team"})
+	config := &Config{Claude: claude}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "invalid claude.users plan") {
+		t.Errorf("Validate() error = %v, want invalid claude.users plan", err)
+	}
+}
+
+func TestQuota_GetUserQuotas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[quota]\nuser_daily = 10.0\n\n[[quota.users]]\nuser = \"alice@example.com\"\ndaily = 25.0\n\n[[quota.users]]\nuser = \"ci@example.com\"\ndaily = 0\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var quota Quota
+	if err := v.UnmarshalKey("quota", &quota); err != nil {
+		t.Fatalf("failed to unmarshal quota: %v", err)
+	}
+
+	quotas := quota.GetUserQuotas()
+	expected := map[string]float64{
+		"alice@example.com": 25,
+		"ci@example.com":    0,
+		"bob@example.com":   10,
+	}
+	for user, want := range expected {
+		if got := quotas.DailyFor(user).Amount(); got != want {
+			t.Errorf("DailyFor(%q) = %v, want %v", user, got, want)
+		}
+	}
+}
+
+func TestMonitor_Keys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[monitor.keys]\nfilter_hour = \"H\"\nquit = [\"Q\", \"ctrl+q\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var monitor Monitor
+	if err := v.UnmarshalKey("monitor", &monitor); err != nil {
+		t.Fatalf("failed to unmarshal monitor: %v", err)
+	}
+
+	// A single key can be given without a list
+	if got := monitor.Keys["filter_hour"]; len(got) != 1 || got[0] != "H" {
+		t.Errorf("Keys[filter_hour] = %v, want [H]", got)
+	}
+	if got := monitor.Keys["quit"]; len(got) != 2 || got[1] != "ctrl+q" {
+		t.Errorf("Keys[quit] = %v, want [Q ctrl+q]", got)
+	}
+}
+
+func TestReceiver_GetPlugins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[[receiver.plugins]]\nname = \"warehouse\"\ncommand = [\"/usr/local/bin/ccmon-warehouse\", \"--table\", \"usage\"]\n\n[[receiver.plugins]]\nname = \"relabel\"\ncommand = [\"/usr/local/bin/ccmon-relabel\"]\ntimeout = \"1s\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+
+	var r Receiver
+	if err := v.UnmarshalKey("receiver", &r); err != nil {
+		t.Fatalf("failed to unmarshal receiver: %v", err)
+	}
+
+	plugins, err := r.GetPlugins()
+	if err != nil {
+		t.Fatalf("GetPlugins() returned error: %v", err)
+	}
+	if len(plugins) != 2 || plugins[0].Name() != "warehouse" || plugins[1].Name() != "relabel" {
+		t.Fatalf("GetPlugins() returned %d plugins, want warehouse and relabel in order", len(plugins))
+	}
+	if plugins[0].Timeout() != entity.DefaultPluginTimeout || plugins[1].Timeout() != time.Second {
+		t.Errorf("GetPlugins() timeouts = %v, %v, want the default and 1s", plugins[0].Timeout(), plugins[1].Timeout())
+	}
+}
+
+func TestReceiver_GetPluginsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []ReceiverPlugin
+		wantErr string
+	}{
+		{
+			name:    "missing name",
+			plugins: []ReceiverPlugin{{Command: []string{"ccmon-relabel"}}},
+			wantErr: "plugin 1 has no name",
+		},
+		{
+			name:    "duplicate name",
+			plugins: []ReceiverPlugin{{Name: "relabel", Command: []string{"a"}}, {Name: "relabel", Command: []string{"b"}}},
+			wantErr: "duplicate plugin name",
+		},
+		{
+			name:    "missing command",
+			plugins: []ReceiverPlugin{{Name: "relabel"}},
+			wantErr: "has no command",
+		},
+		{
+			name:    "invalid timeout",
+			plugins: []ReceiverPlugin{{Name: "relabel", Command: []string{"ccmon-relabel"}, Timeout: "soon"}},
+			wantErr: "invalid timeout",
+		},
+		{
+			name:    "zero timeout",
+			plugins: []ReceiverPlugin{{Name: "relabel", Command: []string{"ccmon-relabel"}, Timeout: "0s"}},
+			wantErr: "must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Receiver{Plugins: tt.plugins}
+			_, err := r.GetPlugins()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("GetPlugins() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReceiver_GetDedupeWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "not set disables", window: "", expected: 0},
+		{name: "zero disables", window: "0", expected: 0},
+		{name: "valid window", window: "10m", expected: 10 * time.Minute},
+		{name: "negative window", window: "-1m", wantErr: true},
+		{name: "invalid window", window: "ten minutes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Receiver{Dedupe: ReceiverDedupe{Window: tt.window}}
+			got, err := r.GetDedupeWindow()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetDedupeWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("GetDedupeWindow() = %v, want %v", got, tt.expected)
+			}
+
+			config := &Config{Monitor: Monitor{Timezone: "UTC"}, Claude: Claude{Plan: "pro"}, Receiver: r}
+			if err := config.Validate(); (err != nil) != tt.wantErr || (err != nil && !strings.Contains(err.Error(), "invalid receiver.dedupe")) {
+				t.Errorf("Validate() error = %v, wantErr invalid receiver.dedupe %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClaude_GetBlockDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		blockDuration string
+		expected      time.Duration
+		wantErr       bool
+	}{
+		{name: "not set uses five hours", blockDuration: "", expected: 5 * time.Hour},
+		{name: "hourly", blockDuration: "1h", expected: time.Hour},
+		{name: "daily", blockDuration: "24h", expected: 24 * time.Hour},
+		{name: "not whole hours", blockDuration: "90m", expected: 5 * time.Hour, wantErr: true},
+		{name: "invalid", blockDuration: "daily", expected: 5 * time.Hour, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claude := Claude{Plan: "pro", BlockDuration: tt.blockDuration}
+			if got := claude.GetBlockDuration(); got != tt.expected {
+				t.Errorf("GetBlockDuration() = %v, want %v", got, tt.expected)
+			}
+
+			config := &Config{Monitor: Monitor{Timezone: "UTC"}, Claude: claude}
+			if err := config.Validate(); (err != nil) != tt.wantErr || (err != nil && !strings.Contains(err.Error(), "invalid claude.block_duration")) {
+				t.Errorf("Validate() error = %v, wantErr invalid claude.block_duration %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDisplay_GetCostFormat(t *testing.T) {
+	unset := (&Display{}).GetCostFormat()
+	if unset.HasPrecision() || unset.IsHumanized() {
+		t.Errorf("Expected no precision and no humanizing by default, got %v and %v", unset.HasPrecision(), unset.IsHumanized())
+	}
+
+	precision := 3
+	format := (&Display{CostPrecision: &precision, CostHumanize: true}).GetCostFormat()
+	if !format.HasPrecision() || format.Precision() != 3 || !format.IsHumanized() {
+		t.Errorf("Expected 3 humanized decimals, got %d (%v) humanized %v", format.Precision(), format.HasPrecision(), format.IsHumanized())
 	}
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -13,8 +15,185 @@ import (
 const (
 	RequestsBucket = "requests"
 	MetadataBucket = "metadata"
+	StarsBucket    = "stars"
 )
 
+// ErrDatabaseLocked is returned when another process, usually a running server, holds the database lock
+var ErrDatabaseLocked = errors.New("database is locked by another ccmon process")
+
+// ErrDatabaseCorrupt is returned when the database file cannot be read or fails its integrity check
+var ErrDatabaseCorrupt = errors.New("database file is corrupt")
+
+// corruptTimeFormat names the corrupt database kept aside after a recovery
+const corruptTimeFormat = "20060102-150405"
+
+// DatabaseRecovery describes a corrupt database replaced by its backup on startup
+type DatabaseRecovery struct {
+	CorruptPath string    // where the corrupt database was moved, kept for inspection
+	BackupAt    time.Time // when the restored backup was written
+	LastWriteAt time.Time // when the corrupt database was last written
+	Cause       error
+}
+
+// String describes the recovery and the window of requests which may be missing
+func (r *DatabaseRecovery) String() string {
+	return fmt.Sprintf("database was corrupt (%v) and has been restored from the backup of %s, requests received between %s and %s may be missing, the corrupt database was kept at %s",
+		r.Cause,
+		r.BackupAt.Format(time.RFC3339),
+		r.BackupAt.Format(time.RFC3339),
+		r.LastWriteAt.Format(time.RFC3339),
+		r.CorruptPath,
+	)
+}
+
+// OpenDatabaseWithRecovery opens the server database and checks its integrity, fullCheck also verifies every page
+// A corrupt database is moved aside and replaced by the backup at backupPath, the recovery is nil when nothing was restored
+func OpenDatabaseWithRecovery(dbPath, backupPath string, fullCheck bool) (*bbolt.DB, *DatabaseRecovery, error) {
+	db, err := openCheckedDatabase(dbPath, fullCheck)
+	if err == nil || !errors.Is(err, ErrDatabaseCorrupt) {
+		return db, nil, err
+	}
+
+	if backupPath == "" {
+		return nil, nil, fmt.Errorf("%w, the file was left untouched: %v", ErrDatabaseCorrupt, err)
+	}
+	backupInfo, statErr := os.Stat(backupPath)
+	if statErr != nil {
+		return nil, nil, fmt.Errorf("%w and no backup is available at %s, the file was left untouched: %v", ErrDatabaseCorrupt, backupPath, err)
+	}
+
+	recovery := &DatabaseRecovery{
+		CorruptPath: dbPath + ".corrupt-" + time.Now().Format(corruptTimeFormat),
+		BackupAt:    backupInfo.ModTime(),
+		Cause:       err,
+	}
+	if info, statErr := os.Stat(dbPath); statErr == nil {
+		recovery.LastWriteAt = info.ModTime()
+	}
+
+	// The corrupt file is kept, a failed restore must never lose more than the corruption did
+	if err := os.Rename(dbPath, recovery.CorruptPath); err != nil {
+		return nil, nil, fmt.Errorf("failed to move corrupt database aside: %w", err)
+	}
+	if err := copyFile(backupPath, dbPath); err != nil {
+		return nil, nil, putBackCorruptDatabase(dbPath, recovery, fmt.Errorf("failed to restore backup %s: %w", backupPath, err))
+	}
+
+	db, err = openCheckedDatabase(dbPath, true)
+	if err != nil {
+		if removeErr := os.Remove(dbPath); removeErr != nil {
+			return nil, nil, fmt.Errorf("failed to open restored backup %s: %w, the corrupt database was kept at %s", backupPath, err, recovery.CorruptPath)
+		}
+		return nil, nil, putBackCorruptDatabase(dbPath, recovery, fmt.Errorf("failed to open restored backup %s: %w", backupPath, err))
+	}
+	return db, recovery, nil
+}
+
+// putBackCorruptDatabase moves the corrupt database back after a failed recovery, so the path never ends up without a file
+func putBackCorruptDatabase(dbPath string, recovery *DatabaseRecovery, cause error) error {
+	if err := os.Rename(recovery.CorruptPath, dbPath); err != nil {
+		return fmt.Errorf("%w, the corrupt database was kept at %s", cause, recovery.CorruptPath)
+	}
+	return fmt.Errorf("%w, the corrupt database was left in place", cause)
+}
+
+// openCheckedDatabase checks an existing database read-only before opening it, so nothing is written to a corrupt file
+// Opening validates the meta pages, full also verifies every page is reachable and consistent
+func openCheckedDatabase(dbPath string, full bool) (db *bbolt.DB, err error) {
+	// Damaged pages may panic inside bbolt instead of returning an error
+	defer func() {
+		if r := recover(); r != nil {
+			if db != nil {
+				_ = db.Close()
+			}
+			db, err = nil, fmt.Errorf("%w: %v", ErrDatabaseCorrupt, r)
+		}
+	}()
+
+	if _, statErr := os.Stat(dbPath); statErr == nil {
+		if err := checkDatabase(dbPath, full); err != nil {
+			return nil, err
+		}
+	}
+
+	db, err = NewDatabase(dbPath)
+	if isCorruptDatabaseError(err) {
+		return nil, fmt.Errorf("%w: %v", ErrDatabaseCorrupt, err)
+	}
+	return db, err
+}
+
+// checkDatabase opens the database read-only and returns ErrDatabaseCorrupt when it is damaged
+func checkDatabase(dbPath string, full bool) error {
+	db, err := NewDatabaseWithOptions(dbPath, true)
+	if isCorruptDatabaseError(err) {
+		return fmt.Errorf("%w: %v", ErrDatabaseCorrupt, err)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Printf("Error closing checked database: %v", closeErr)
+		}
+	}()
+
+	if !full {
+		return nil
+	}
+	err = db.View(func(tx *bbolt.Tx) error {
+		// The channel is drained so the check finishes before the transaction closes
+		var firstErr error
+		for checkErr := range tx.Check() {
+			if firstErr == nil {
+				firstErr = checkErr
+			}
+		}
+		return firstErr
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDatabaseCorrupt, err)
+	}
+	return nil
+}
+
+// isCorruptDatabaseError returns true if bbolt refused to open the file because of its content
+func isCorruptDatabaseError(err error) bool {
+	return errors.Is(err, bbolt.ErrInvalid) || errors.Is(err, bbolt.ErrChecksum) || errors.Is(err, bbolt.ErrVersionMismatch)
+}
+
+// copyFile copies src to dst through a temporary file, so dst is either complete or absent
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	tmp := dst + ".restore"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
 // NewDatabase creates a new database instance
 func NewDatabase(dbPath string) (*bbolt.DB, error) {
 	return NewDatabaseWithOptions(dbPath, false)
@@ -45,6 +224,9 @@ func NewDatabaseWithOptions(dbPath string, readOnly bool) (*bbolt.DB, error) {
 	}
 
 	db, err := bbolt.Open(dbPath, 0600, options)
+	if errors.Is(err, bbolt.ErrTimeout) {
+		return nil, fmt.Errorf("failed to open database %s: %w", dbPath, ErrDatabaseLocked)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -74,6 +256,10 @@ func InitializeBuckets(db *bbolt.DB) error {
 		if err != nil {
 			return fmt.Errorf("failed to create metadata bucket: %w", err)
 		}
+		_, err = tx.CreateBucketIfNotExists([]byte(StarsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create stars bucket: %w", err)
+		}
 		return nil
 	})
 }
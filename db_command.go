@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+	"go.etcd.io/bbolt"
+)
+
+// compactTxMaxSize limits the size of each transaction while compacting, so large databases are copied in steps
+const compactTxMaxSize = 64 * 1024
+
+// dbParams are the command line options of the db command
+type dbParams struct {
+	action string
+	file   string // backup file of the restore action, the configured backup when empty
+	yes    bool   // skip the confirmation of destructive actions
+}
+
+// runDB maintains the database file while the server is stopped and returns the exit code
+// Destructive actions are confirmed and keep a snapshot of the database, db undo-last restores it
+func runDB(config *Config, params dbParams) int {
+	switch params.action {
+	case "cleanup":
+		return runDBCleanup(config, params)
+	case "compact":
+		return runDBCompact(config, params)
+	case "restore":
+		return runDBRestore(config, params)
+	case "undo-last":
+		return runDBUndoLast(config, params)
+	case "":
+		fmt.Fprintf(os.Stderr, "Usage: ccmon db <cleanup|compact|restore|undo-last> [--yes]\n")
+		return 1
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown db command: %s, expected cleanup, compact, restore or undo-last\n", params.action)
+		return 1
+	}
+}
+
+// runDBCleanup deletes the records older than the retention, starred records are kept
+func runDBCleanup(config *Config, params dbParams) int {
+	retention := config.Server.GetRetentionDuration()
+	if retention == 0 {
+		fmt.Fprintf(os.Stderr, "Set server.retention or --server-retention to the age of the records to delete.\n")
+		return 1
+	}
+	cutoff := time.Now().Add(-retention)
+
+	db, ok := openMaintenanceDatabase(config.Database.Path)
+	if !ok {
+		return 1
+	}
+	defer closeMaintenanceDatabase(db)
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	command := usecase.NewCleanupOldRecordsCommandWithStars(repo, repository.NewBoltDBStarRepository(db))
+
+	if config.Server.IsCleanupDryRun() {
+		result, err := command.Execute(context.Background(), usecase.CleanupOldRecordsParams{CutoffTime: cutoff, DryRun: true})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to count old records: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%d records older than %s would be deleted from %s\n", result.DeletedCount, cutoff.Format(time.RFC3339), config.Database.Path)
+		return 0
+	}
+
+	if !confirmDestructive(params, fmt.Sprintf("Delete the records older than %s from %s?", cutoff.Format(time.RFC3339), config.Database.Path)) {
+		return 1
+	}
+	if !writeMaintenanceSnapshot(db, config.Database.Path, "cleanup") {
+		return 1
+	}
+
+	result, err := command.Execute(context.Background(), usecase.CleanupOldRecordsParams{CutoffTime: cutoff})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to delete old records: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Deleted %d records older than %s from %s\n", result.DeletedCount, cutoff.Format(time.RFC3339), config.Database.Path)
+	fmt.Printf("Run ccmon db undo-last to restore them\n")
+	return 0
+}
+
+// runDBCompact rewrites the database file without the free pages left by deleted records
+func runDBCompact(config *Config, params dbParams) int {
+	db, ok := openMaintenanceDatabase(config.Database.Path)
+	if !ok {
+		return 1
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			closeMaintenanceDatabase(db)
+		}
+	}()
+
+	if !confirmDestructive(params, fmt.Sprintf("Rewrite %s to reclaim unused space?", config.Database.Path)) {
+		return 1
+	}
+	if !writeMaintenanceSnapshot(db, config.Database.Path, "compact") {
+		return 1
+	}
+
+	before, err := os.Stat(config.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read database size: %v\n", err)
+		return 1
+	}
+
+	// The compacted copy replaces the database only once it is complete
+	compactPath := config.Database.Path + ".compact"
+	dst, err := bbolt.Open(compactPath, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create compacted database: %v\n", err)
+		return 1
+	}
+	if err := bbolt.Compact(dst, db, compactTxMaxSize); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(compactPath)
+		fmt.Fprintf(os.Stderr, "Failed to compact database: %v\n", err)
+		return 1
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(compactPath)
+		fmt.Fprintf(os.Stderr, "Failed to write compacted database: %v\n", err)
+		return 1
+	}
+
+	closed = true
+	closeMaintenanceDatabase(db)
+	if err := os.Rename(compactPath, config.Database.Path); err != nil {
+		_ = os.Remove(compactPath)
+		fmt.Fprintf(os.Stderr, "Failed to replace database: %v\n", err)
+		return 1
+	}
+
+	after, err := os.Stat(config.Database.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read database size: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Compacted %s from %d to %d bytes\n", config.Database.Path, before.Size(), after.Size())
+	return 0
+}
+
+// runDBRestore replaces the database with a backup, the backup is checked before anything is replaced
+func runDBRestore(config *Config, params dbParams) int {
+	backupPath := params.file
+	if backupPath == "" {
+		backupPath = config.Database.Backup.Path
+	}
+	if backupPath == "" {
+		fmt.Fprintf(os.Stderr, "Usage: ccmon db restore <backup file>, or set database.backup.path to restore the periodic backup\n")
+		return 1
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Backup %s is not readable: %v\n", backupPath, err)
+		return 1
+	}
+
+	db, ok := openMaintenanceDatabase(config.Database.Path)
+	if !ok {
+		return 1
+	}
+	closed := false
+	defer func() {
+		if !closed {
+			closeMaintenanceDatabase(db)
+		}
+	}()
+
+	// The backup is copied and checked next to the database, so a broken backup never replaces it
+	incomingPath := config.Database.Path + ".incoming"
+	if err := copyFile(backupPath, incomingPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to copy backup %s: %v\n", backupPath, err)
+		return 1
+	}
+	incoming, err := openCheckedDatabase(incomingPath, true)
+	if err != nil {
+		_ = os.Remove(incomingPath)
+		fmt.Fprintf(os.Stderr, "Backup %s cannot be restored: %v\n", backupPath, err)
+		return 1
+	}
+	if err := incoming.Close(); err != nil {
+		log.Printf("Error closing backup copy: %v", err)
+	}
+
+	if !confirmDestructive(params, fmt.Sprintf("Replace %s with the backup %s?", config.Database.Path, backupPath)) {
+		_ = os.Remove(incomingPath)
+		return 1
+	}
+	if !writeMaintenanceSnapshot(db, config.Database.Path, "restore") {
+		_ = os.Remove(incomingPath)
+		return 1
+	}
+
+	closed = true
+	closeMaintenanceDatabase(db)
+	if err := os.Rename(incomingPath, config.Database.Path); err != nil {
+		_ = os.Remove(incomingPath)
+		fmt.Fprintf(os.Stderr, "Failed to replace database: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Restored %s from %s\n", config.Database.Path, backupPath)
+	fmt.Printf("Run ccmon db undo-last to go back to the replaced database\n")
+	return 0
+}
+
+// runDBUndoLast restores the database to its state before the last destructive operation
+func runDBUndoLast(config *Config, params dbParams) int {
+	snapshot, err := ReadUndoSnapshot(config.Database.Path)
+	if errors.Is(err, ErrNoUndoSnapshot) {
+		fmt.Fprintf(os.Stderr, "There is no destructive operation to undo for %s.\n", config.Database.Path)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read undo snapshot: %v\n", err)
+		return 1
+	}
+
+	// Holding the database keeps a server from starting while it is replaced
+	db, ok := openMaintenanceDatabase(config.Database.Path)
+	if !ok {
+		return 1
+	}
+
+	question := fmt.Sprintf("Undo %s, changes to %s made since then are lost?", snapshot, config.Database.Path)
+	if !confirmDestructive(params, question) {
+		closeMaintenanceDatabase(db)
+		return 1
+	}
+	closeMaintenanceDatabase(db)
+
+	if err := RestoreUndoSnapshot(config.Database.Path, snapshot); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to undo %s: %v\n", snapshot.Operation, err)
+		return 1
+	}
+
+	fmt.Printf("Undid %s in %s\n", snapshot, config.Database.Path)
+	return 0
+}
+
+// openMaintenanceDatabase opens the database for a db command, the server holds the lock and must be stopped
+func openMaintenanceDatabase(dbPath string) (*bbolt.DB, bool) {
+	db, err := NewDatabase(dbPath)
+	if errors.Is(err, ErrDatabaseLocked) {
+		fmt.Fprintf(os.Stderr, "A running ccmon server is using %s.\n", dbPath)
+		fmt.Fprintf(os.Stderr, "Stop the server before changing the database.\n")
+		return nil, false
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return nil, false
+	}
+	return db, true
+}
+
+// closeMaintenanceDatabase closes the database of a db command
+func closeMaintenanceDatabase(db *bbolt.DB) {
+	if err := db.Close(); err != nil {
+		log.Printf("Error closing database: %v", err)
+	}
+}
+
+// confirmDestructive asks on the terminal before a destructive operation unless --yes was given
+func confirmDestructive(params dbParams, question string) bool {
+	if params.yes {
+		return true
+	}
+	if err := Confirm(os.Stdin, os.Stderr, isTerminal(os.Stdin), question); err != nil {
+		fmt.Fprintf(os.Stderr, "Aborted: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// writeMaintenanceSnapshot keeps a copy of the database before a destructive operation for db undo-last
+func writeMaintenanceSnapshot(db *bbolt.DB, dbPath, operation string) bool {
+	if _, err := WriteUndoSnapshot(db, dbPath, operation, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to snapshot the database before %s, nothing was changed: %v\n", operation, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.etcd.io/bbolt"
+)
+
+func TestNewDatabase_Locked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+
+	db, err := NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("Failed to close database: %v", err)
+		}
+	}()
+
+	// A second open waits for the lock held by the first one, like a second server would
+	_, err = NewDatabase(dbPath)
+	if !errors.Is(err, ErrDatabaseLocked) {
+		t.Fatalf("Expected ErrDatabaseLocked, got %v", err)
+	}
+}
+
+// createTestDatabase writes a database holding a single request key
+func createTestDatabase(t *testing.T, dbPath string) {
+	t.Helper()
+
+	db, err := NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(RequestsBucket)).Put([]byte("request-1"), []byte("{}"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to write request: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+}
+
+// corruptDatabase overwrites both meta pages, which bbolt refuses to open
+func corruptDatabase(t *testing.T, dbPath string) {
+	t.Helper()
+
+	f, err := os.OpenFile(dbPath, os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open database file: %v", err)
+	}
+	if _, err := f.WriteAt([]byte(strings.Repeat("x", 2*os.Getpagesize())), 0); err != nil {
+		t.Fatalf("Failed to corrupt database: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Failed to close database file: %v", err)
+	}
+}
+
+func TestOpenDatabaseWithRecovery_Healthy(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	createTestDatabase(t, dbPath)
+
+	db, recovery, err := OpenDatabaseWithRecovery(dbPath, filepath.Join(t.TempDir(), "missing.db"), false)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if recovery != nil {
+		t.Errorf("Expected no recovery for a healthy database, got %s", recovery)
+	}
+}
+
+func TestOpenDatabaseWithRecovery_CorruptWithoutBackup(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	createTestDatabase(t, dbPath)
+	corruptDatabase(t, dbPath)
+
+	for _, backupPath := range []string{"", filepath.Join(t.TempDir(), "missing.db")} {
+		_, _, err := OpenDatabaseWithRecovery(dbPath, backupPath, false)
+		if !errors.Is(err, ErrDatabaseCorrupt) {
+			t.Fatalf("Expected ErrDatabaseCorrupt with backup %q, got %v", backupPath, err)
+		}
+	}
+
+	// Without a backup the corrupt file stays where it is
+	matches, _ := filepath.Glob(dbPath + ".corrupt-*")
+	if len(matches) != 0 {
+		t.Errorf("Expected the corrupt database to be left untouched, found %v", matches)
+	}
+}
+
+func TestOpenDatabaseWithRecovery_RestoresBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "ccmon.db")
+	backupPath := filepath.Join(dir, "ccmon.db.bak")
+	createTestDatabase(t, dbPath)
+
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	corruptDatabase(t, dbPath)
+
+	db, recovery, err := OpenDatabaseWithRecovery(dbPath, backupPath, false)
+	if err != nil {
+		t.Fatalf("Expected the backup to be restored, got %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	if recovery == nil {
+		t.Fatal("Expected a recovery to be reported")
+	}
+	if !errors.Is(recovery.Cause, ErrDatabaseCorrupt) {
+		t.Errorf("Expected the cause to be ErrDatabaseCorrupt, got %v", recovery.Cause)
+	}
+	if _, err := os.Stat(recovery.CorruptPath); err != nil {
+		t.Errorf("Expected the corrupt database to be kept at %s: %v", recovery.CorruptPath, err)
+	}
+	if !strings.Contains(recovery.String(), "may be missing") {
+		t.Errorf("Expected the recovery to describe the data loss window, got %s", recovery)
+	}
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(RequestsBucket)).Get([]byte("request-1")) == nil {
+			return errors.New("request-1 not found")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected the restored database to hold the backup data: %v", err)
+	}
+}
+
+func TestOpenDatabaseWithRecovery_CorruptBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "ccmon.db")
+	backupPath := filepath.Join(dir, "ccmon.db.bak")
+	createTestDatabase(t, dbPath)
+	createTestDatabase(t, backupPath)
+	corruptDatabase(t, dbPath)
+	corruptDatabase(t, backupPath)
+
+	corrupt, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+
+	_, _, err = OpenDatabaseWithRecovery(dbPath, backupPath, true)
+	if !errors.Is(err, ErrDatabaseCorrupt) {
+		t.Fatalf("Expected ErrDatabaseCorrupt, got %v", err)
+	}
+
+	// The failed recovery puts the original file back as it was
+	data, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatalf("Expected the original database to be put back: %v", err)
+	}
+	if string(data) != string(corrupt) {
+		t.Error("Expected the original database to be left unchanged")
+	}
+	matches, _ := filepath.Glob(dbPath + ".corrupt-*")
+	if len(matches) != 0 {
+		t.Errorf("Expected no moved database to be left behind, found %v", matches)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// ErrNoUndoSnapshot is returned by db undo-last when no destructive operation left a snapshot
+var ErrNoUndoSnapshot = errors.New("no destructive operation to undo")
+
+// ErrNotConfirmed is returned when a destructive operation was declined or could not be confirmed
+var ErrNotConfirmed = errors.New("operation was not confirmed")
+
+// UndoSnapshot references the copy of the database taken right before the last destructive operation
+type UndoSnapshot struct {
+	Path      string    `json:"path"`
+	Operation string    `json:"operation"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// String describes the operation the snapshot was taken for
+func (s *UndoSnapshot) String() string {
+	return fmt.Sprintf("%s at %s", s.Operation, s.CreatedAt.Format(time.RFC3339))
+}
+
+// undoSnapshotPath is where the pre-operation copy of the database is kept, only the last one is kept
+func undoSnapshotPath(dbPath string) string {
+	return dbPath + ".undo"
+}
+
+// undoReferencePath is where the reference of the pre-operation copy is written
+func undoReferencePath(dbPath string) string {
+	return dbPath + ".undo.json"
+}
+
+// WriteUndoSnapshot copies the open database before a destructive operation and records it for db undo-last
+// The copy is taken in a read transaction, so it is consistent even when the operation runs right after
+func WriteUndoSnapshot(db *bbolt.DB, dbPath, operation string, now time.Time) (*UndoSnapshot, error) {
+	snapshot := &UndoSnapshot{
+		Path:      undoSnapshotPath(dbPath),
+		Operation: operation,
+		CreatedAt: now,
+	}
+
+	tmp := snapshot.Path + ".tmp"
+	if err := db.View(func(tx *bbolt.Tx) error {
+		return tx.CopyFile(tmp, 0600)
+	}); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to copy database: %w", err)
+	}
+	if err := os.Rename(tmp, snapshot.Path); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	reference := undoReferencePath(dbPath)
+	if err := os.WriteFile(reference+".tmp", data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot reference: %w", err)
+	}
+	if err := os.Rename(reference+".tmp", reference); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot reference: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// ReadUndoSnapshot returns the snapshot of the last destructive operation, ErrNoUndoSnapshot when there is none
+func ReadUndoSnapshot(dbPath string) (*UndoSnapshot, error) {
+	data, err := os.ReadFile(undoReferencePath(dbPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoUndoSnapshot
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot UndoSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot reference %s: %w", undoReferencePath(dbPath), err)
+	}
+	if _, err := os.Stat(snapshot.Path); err != nil {
+		return nil, fmt.Errorf("snapshot of %s is missing: %w", snapshot.Operation, err)
+	}
+	return &snapshot, nil
+}
+
+// RestoreUndoSnapshot replaces the closed database with the snapshot of the last destructive operation
+// The snapshot is removed afterwards, an undo cannot be undone
+func RestoreUndoSnapshot(dbPath string, snapshot *UndoSnapshot) error {
+	if err := copyFile(snapshot.Path, dbPath); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	if err := os.Remove(undoReferencePath(dbPath)); err != nil {
+		return err
+	}
+	return os.Remove(snapshot.Path)
+}
+
+// Confirm asks a yes or no question on out and reads the answer from in, only "y" or "yes" confirms
+// A non-interactive input cannot answer, so the operation has to be confirmed with --yes instead
+func Confirm(in io.Reader, out io.Writer, interactive bool, question string) error {
+	if !interactive {
+		return fmt.Errorf("%w, pass --yes to run it without a terminal", ErrNotConfirmed)
+	}
+
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if errors.Is(err, io.EOF) && answer == "" {
+		return fmt.Errorf("%w, pass --yes to run it without an answer", ErrNotConfirmed)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return ErrNotConfirmed
+}
+
+// isTerminal reports whether the file is a terminal a user can answer a confirmation on
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
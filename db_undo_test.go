@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+func TestWriteUndoSnapshot_RestoresPreviousState(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	createTestDatabase(t, dbPath)
+
+	db, err := NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	createdAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	if _, err := WriteUndoSnapshot(db, dbPath, "cleanup", createdAt); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+
+	// The destructive operation deletes the request after the snapshot
+	err = db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(RequestsBucket)).Delete([]byte("request-1"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to delete request: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+
+	snapshot, err := ReadUndoSnapshot(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to read snapshot: %v", err)
+	}
+	if snapshot.Operation != "cleanup" || !snapshot.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected the cleanup snapshot of %s, got %s", createdAt, snapshot)
+	}
+
+	if err := RestoreUndoSnapshot(dbPath, snapshot); err != nil {
+		t.Fatalf("Failed to restore snapshot: %v", err)
+	}
+
+	db, err = NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open restored database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("Failed to close database: %v", err)
+		}
+	}()
+	err = db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket([]byte(RequestsBucket)).Get([]byte("request-1")) == nil {
+			t.Error("Expected the deleted request to be restored")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to read database: %v", err)
+	}
+
+	// Only the last operation can be undone, and only once
+	if _, err := ReadUndoSnapshot(dbPath); !errors.Is(err, ErrNoUndoSnapshot) {
+		t.Errorf("Expected ErrNoUndoSnapshot after the undo, got %v", err)
+	}
+	if _, err := os.Stat(undoSnapshotPath(dbPath)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the snapshot file to be removed, got %v", err)
+	}
+}
+
+func TestReadUndoSnapshot_None(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+
+	if _, err := ReadUndoSnapshot(dbPath); !errors.Is(err, ErrNoUndoSnapshot) {
+		t.Errorf("Expected ErrNoUndoSnapshot, got %v", err)
+	}
+}
+
+func TestReadUndoSnapshot_MissingFile(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	createTestDatabase(t, dbPath)
+
+	db, err := NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if _, err := WriteUndoSnapshot(db, dbPath, "compact", time.Now()); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+	if err := os.Remove(undoSnapshotPath(dbPath)); err != nil {
+		t.Fatalf("Failed to remove snapshot: %v", err)
+	}
+
+	_, err = ReadUndoSnapshot(dbPath)
+	if err == nil || errors.Is(err, ErrNoUndoSnapshot) {
+		t.Errorf("Expected an error about the missing snapshot, got %v", err)
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		wantErr     bool
+	}{
+		{name: "yes", input: "y\n", interactive: true},
+		{name: "full yes with spaces", input: "  YES \n", interactive: true},
+		{name: "no", input: "n\n", interactive: true, wantErr: true},
+		{name: "empty answer defaults to no", input: "\n", interactive: true, wantErr: true},
+		{name: "end of input", input: "", interactive: true, wantErr: true},
+		{name: "not a terminal", input: "y\n", interactive: false, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := Confirm(strings.NewReader(tt.input), &out, tt.interactive, "Delete everything?")
+			if tt.wantErr {
+				if !errors.Is(err, ErrNotConfirmed) {
+					t.Errorf("Expected ErrNotConfirmed, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected confirmation, got %v", err)
+			}
+			if !strings.Contains(out.String(), "Delete everything? [y/N]") {
+				t.Errorf("Expected the question to be asked, got %q", out.String())
+			}
+		})
+	}
+}
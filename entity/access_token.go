@@ -0,0 +1,33 @@
+package entity
+
+import "fmt"
+
+// TokenRole scopes what an access token may call
+type TokenRole string
+
+const (
+	// TokenRoleRead tokens can query usage, e.g. a dashboard
+	TokenRoleRead TokenRole = "read"
+	// TokenRoleWrite tokens can also export usage over OTLP and change records, e.g. starring a request
+	TokenRoleWrite TokenRole = "write"
+)
+
+// ParseTokenRole parses the role of a token, empty means read so a forgotten role never grants ingest rights
+func ParseTokenRole(s string) (TokenRole, error) {
+	switch TokenRole(s) {
+	case "", TokenRoleRead:
+		return TokenRoleRead, nil
+	case TokenRoleWrite:
+		return TokenRoleWrite, nil
+	default:
+		return "", fmt.Errorf("unknown role %q, must be read or write", s)
+	}
+}
+
+// AccessToken is a token accepted from clients or replicas, either given directly or read from a file
+type AccessToken struct {
+	Label string // names the client in the logs, the token itself is never logged
+	Token string
+	File  string    // file holding the token, read again on reload
+	Role  TokenRole // only checked for client tokens, snapshot tokens have a single role
+}
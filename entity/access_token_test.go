@@ -0,0 +1,29 @@
+package entity
+
+import "testing"
+
+func TestParseTokenRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected TokenRole
+		wantErr  bool
+	}{
+		{input: "", expected: TokenRoleRead},
+		{input: "read", expected: TokenRoleRead},
+		{input: "write", expected: TokenRoleWrite},
+		{input: "admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		role, err := ParseTokenRole(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTokenRole(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if role != tt.expected {
+			t.Errorf("ParseTokenRole(%q) = %q, want %q", tt.input, role, tt.expected)
+		}
+	}
+}
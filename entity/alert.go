@@ -0,0 +1,206 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AlertMetric is the usage an alert rule watches
+type AlertMetric string
+
+const (
+	AlertDailyCost   AlertMetric = "daily_cost"   // cost of today in the timezone
+	AlertMonthlyCost AlertMetric = "monthly_cost" // cost of this month in the timezone
+	AlertBlockCost   AlertMetric = "block_cost"   // cost of the current block
+	AlertBlockTokens AlertMetric = "block_tokens" // rate limited input + output tokens of the current block
+)
+
+// AlertRule fires when the metric goes above the threshold, once per day, month or block
+type AlertRule struct {
+	metric  AlertMetric
+	above   float64 // USD for costs, tokens for block tokens
+	percent float64 // percentage of the block token limit, only for block tokens
+}
+
+// NewAlertRule creates a new AlertRule, exactly one of above and percent must be set
+// Percent is the share of the block token limit and is only supported by block tokens
+func NewAlertRule(metric string, above float64, percent float64) (AlertRule, error) {
+	rule := AlertRule{metric: AlertMetric(metric), above: above, percent: percent}
+
+	switch rule.metric {
+	case AlertDailyCost, AlertMonthlyCost, AlertBlockCost, AlertBlockTokens:
+	default:
+		return AlertRule{}, fmt.Errorf("unknown metric %q, expected daily_cost, monthly_cost, block_cost or block_tokens", metric)
+	}
+	if above < 0 || percent < 0 {
+		return AlertRule{}, fmt.Errorf("threshold of %s must not be negative", metric)
+	}
+	if (above > 0) == (percent > 0) {
+		return AlertRule{}, fmt.Errorf("exactly one of above or percent must be set for %s", metric)
+	}
+	if percent > 0 && rule.metric != AlertBlockTokens {
+		return AlertRule{}, fmt.Errorf("percent is only supported by block_tokens, use above for %s", metric)
+	}
+
+	return rule, nil
+}
+
+// Metric returns the usage the rule watches
+func (r AlertRule) Metric() AlertMetric {
+	return r.metric
+}
+
+// IsBlock returns true if the rule watches the current block
+func (r AlertRule) IsBlock() bool {
+	return r.metric == AlertBlockCost || r.metric == AlertBlockTokens
+}
+
+// String returns the rule as "metric > threshold", e.g. "daily_cost > 10" or "block_tokens > 80%"
+func (r AlertRule) String() string {
+	if r.percent > 0 {
+		return fmt.Sprintf("%s > %s%%", r.metric, strconv.FormatFloat(r.percent, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s > %s", r.metric, strconv.FormatFloat(r.above, 'f', -1, 64))
+}
+
+// AlertPolicy decides the period of each rule and when it fires
+type AlertPolicy struct {
+	rules          []AlertRule
+	blockStartHour int
+	blockDuration  time.Duration // 0 uses TimeBlockDuration
+	timezone       *time.Location
+	tokenLimit     int
+}
+
+// NewAlertPolicy creates a new AlertPolicy, days and months follow the timezone and blocks start at the hour in it
+// Rules with a percentage of the block token limit never fire without a limit
+func NewAlertPolicy(rules []AlertRule, blockStartHour int, timezone *time.Location, tokenLimit int) AlertPolicy {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return AlertPolicy{
+		rules:          rules,
+		blockStartHour: blockStartHour,
+		timezone:       timezone,
+		tokenLimit:     tokenLimit,
+	}
+}
+
+// WithBlockDuration returns a copy of the policy for blocks of the given length
+func (p AlertPolicy) WithBlockDuration(duration time.Duration) AlertPolicy {
+	p.blockDuration = duration
+	return p
+}
+
+// Rules returns the alert rules
+func (p AlertPolicy) Rules() []AlertRule {
+	return p.rules
+}
+
+// IsEnabled returns true if any rule is configured
+func (p AlertPolicy) IsEnabled() bool {
+	return len(p.rules) > 0
+}
+
+// Period returns the day, month or block containing now the rule is evaluated for
+func (p AlertPolicy) Period(rule AlertRule, now time.Time) Period {
+	switch rule.metric {
+	case AlertMonthlyCost:
+		return NewMonthPeriod(now, p.timezone)
+	case AlertBlockCost, AlertBlockTokens:
+		return NewCurrentBlockWithDuration(p.blockStartHour, p.timezone, now, p.tokenLimit, p.blockDuration).Period()
+	default:
+		return NewDayPeriod(now, p.timezone)
+	}
+}
+
+// Evaluate returns the alert of the rule when the stats of its period are above the threshold
+func (p AlertPolicy) Evaluate(rule AlertRule, stats Stats, now time.Time) (Alert, bool) {
+	threshold := rule.above
+	if rule.percent > 0 {
+		if p.tokenLimit <= 0 {
+			return Alert{}, false
+		}
+		threshold = float64(p.tokenLimit) * rule.percent / 100
+	}
+
+	value := stats.TotalCost().Amount()
+	if rule.metric == AlertBlockTokens {
+		value = float64(stats.RateLimitedTokens().Limited())
+	}
+	if value <= threshold {
+		return Alert{}, false
+	}
+
+	return Alert{
+		rule:      rule,
+		period:    p.Period(rule, now),
+		value:     value,
+		threshold: threshold,
+		firedAt:   now,
+	}, true
+}
+
+// Alert is a rule which went above its threshold in a period
+type Alert struct {
+	rule      AlertRule
+	period    Period
+	value     float64
+	threshold float64
+	firedAt   time.Time
+}
+
+// Rule returns the rule which fired
+func (a Alert) Rule() AlertRule {
+	return a.rule
+}
+
+// Period returns the day, month or block the rule fired in
+func (a Alert) Period() Period {
+	return a.period
+}
+
+// Value returns the cost in USD or the tokens when the rule fired
+func (a Alert) Value() float64 {
+	return a.value
+}
+
+// Threshold returns the cost in USD or the tokens the value went above
+func (a Alert) Threshold() float64 {
+	return a.threshold
+}
+
+// FiredAt returns when the rule fired
+func (a Alert) FiredAt() time.Time {
+	return a.firedAt
+}
+
+// Key identifies the rule in its period, a rule alerts only once for the same key
+func (a Alert) Key() string {
+	return AlertKey(a.rule, a.period)
+}
+
+// AlertKey identifies the rule in the period
+func AlertKey(rule AlertRule, period Period) string {
+	return rule.String() + "@" + strconv.FormatInt(period.StartAt().Unix(), 10)
+}
+
+// Message renders the alert as a single line, e.g. "Daily cost $12.30 is above $10.00"
+func (a Alert) Message(costFormat CostFormat) string {
+	switch a.rule.metric {
+	case AlertBlockTokens:
+		message := fmt.Sprintf("Block tokens %d are above %d", int64(a.value), int64(a.threshold))
+		if a.rule.percent > 0 {
+			message += fmt.Sprintf(" (%s%% of the limit)", strconv.FormatFloat(a.rule.percent, 'f', -1, 64))
+		}
+		return message
+	case AlertBlockCost:
+		return fmt.Sprintf("Block cost %s is above %s", costFormat.Format(NewCost(a.value)), costFormat.Format(NewCost(a.threshold)))
+	case AlertMonthlyCost:
+		return fmt.Sprintf("Monthly cost %s is above %s", costFormat.Format(NewCost(a.value)), costFormat.Format(NewCost(a.threshold)))
+	default:
+		return fmt.Sprintf("Daily cost %s is above %s", costFormat.Format(NewCost(a.value)), costFormat.Format(NewCost(a.threshold)))
+	}
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewAlertRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		metric  string
+		above   float64
+		percent float64
+		want    string
+		wantErr string
+	}{
+		{name: "daily cost", metric: "daily_cost", above: 10, want: "daily_cost > 10"},
+		{name: "block tokens percent", metric: "block_tokens", percent: 80, want: "block_tokens > 80%"},
+		{name: "block tokens above", metric: "block_tokens", above: 500000, want: "block_tokens > 500000"},
+		{name: "unknown metric", metric: "weekly_cost", above: 10, wantErr: "unknown metric"},
+		{name: "no threshold", metric: "daily_cost", wantErr: "exactly one of above or percent"},
+		{name: "both thresholds", metric: "block_tokens", above: 10, percent: 80, wantErr: "exactly one of above or percent"},
+		{name: "negative threshold", metric: "daily_cost", above: -1, wantErr: "must not be negative"},
+		{name: "percent of a cost", metric: "daily_cost", percent: 80, wantErr: "only supported by block_tokens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := NewAlertRule(tt.metric, tt.above, tt.percent)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewAlertRule() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewAlertRule() error = %v", err)
+			}
+			if rule.String() != tt.want {
+				t.Errorf("String() = %q, want %q", rule.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertPolicy_Period(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("Asia/Tokyo timezone not available")
+	}
+	now := time.Date(2025, 6, 1, 23, 30, 0, 0, time.UTC) // 08:30 on June 2 in Tokyo
+	policy := NewAlertPolicy(nil, 5, tokyo, 7000)
+
+	daily, _ := NewAlertRule("daily_cost", 10, 0)
+	monthly, _ := NewAlertRule("monthly_cost", 100, 0)
+	block, _ := NewAlertRule("block_tokens", 0, 80)
+
+	tests := []struct {
+		name      string
+		rule      AlertRule
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{name: "day in the timezone", rule: daily, wantStart: time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo), wantEnd: time.Date(2025, 6, 3, 0, 0, 0, 0, tokyo)},
+		{name: "month in the timezone", rule: monthly, wantStart: time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo), wantEnd: time.Date(2025, 7, 1, 0, 0, 0, 0, tokyo)},
+		{name: "block containing now", rule: block, wantStart: time.Date(2025, 6, 2, 5, 0, 0, 0, tokyo), wantEnd: time.Date(2025, 6, 2, 10, 0, 0, 0, tokyo)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := policy.Period(tt.rule, now)
+			if !period.StartAt().Equal(tt.wantStart) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), tt.wantStart)
+			}
+			if period.EndAt().Sub(tt.wantEnd).Abs() > time.Second {
+				t.Errorf("EndAt() = %v, want %v", period.EndAt(), tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestAlertPolicy_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+	dailyCost, _ := NewAlertRule("daily_cost", 10, 0)
+	blockPercent, _ := NewAlertRule("block_tokens", 0, 80)
+	blockAbove, _ := NewAlertRule("block_tokens", 5000, 0)
+
+	newStats := func(tokens Token, cost float64) Stats {
+		return NewStats(0, 1, Token{}, tokens, NewCost(0), NewCost(cost), NewPeriod(now.Add(-time.Hour), now))
+	}
+
+	tests := []struct {
+		name        string
+		rule        AlertRule
+		tokenLimit  int
+		stats       Stats
+		wantFired   bool
+		wantMessage string
+	}{
+		{name: "cost below the threshold", rule: dailyCost, stats: newStats(Token{}, 9.5)},
+		{name: "cost at the threshold", rule: dailyCost, stats: newStats(Token{}, 10)},
+		{name: "cost above the threshold", rule: dailyCost, stats: newStats(Token{}, 12.3), wantFired: true, wantMessage: "Daily cost $12.30 is above $10.00"},
+		{name: "block tokens below the limit share", rule: blockPercent, tokenLimit: 7000, stats: newStats(NewToken(5000, 500, 0, 0), 1)},
+		{name: "block tokens above the limit share", rule: blockPercent, tokenLimit: 7000, stats: newStats(NewToken(5000, 700, 100000, 0), 1), wantFired: true, wantMessage: "Block tokens 5700 are above 5600 (80% of the limit)"},
+		{name: "block tokens without a limit", rule: blockPercent, stats: newStats(NewToken(5000000, 0, 0, 0), 1)},
+		{name: "block tokens above an amount", rule: blockAbove, stats: newStats(NewToken(5000, 1, 0, 0), 1), wantFired: true, wantMessage: "Block tokens 5001 are above 5000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := NewAlertPolicy([]AlertRule{tt.rule}, 5, time.UTC, tt.tokenLimit)
+			alert, fired := policy.Evaluate(tt.rule, tt.stats, now)
+			if fired != tt.wantFired {
+				t.Fatalf("Evaluate() fired = %v, want %v", fired, tt.wantFired)
+			}
+			if !fired {
+				return
+			}
+			if got := alert.Message(DefaultCostFormat()); got != tt.wantMessage {
+				t.Errorf("Message() = %q, want %q", got, tt.wantMessage)
+			}
+			if alert.Key() != AlertKey(tt.rule, policy.Period(tt.rule, now)) {
+				t.Errorf("Key() = %q, want the key of the rule period", alert.Key())
+			}
+		})
+	}
+}
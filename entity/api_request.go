@@ -5,7 +5,26 @@ import (
 	"time"
 )
 
-// APIRequest represents a Claude Code API request entity
+// SourceClaudeCode is the source of API requests reported by Claude Code telemetry
+const SourceClaudeCode = "claude_code"
+
+// Origins tell how a record entered the database, so backfills can be told apart from live telemetry
+const (
+	OriginLive   = "live"   // received by the OTLP receiver as it happened
+	OriginImport = "import" // backfilled from a file, e.g. with ingest-file
+)
+
+// ParseOrigin validates an origin name like "live" or "import"
+func ParseOrigin(name string) (string, error) {
+	switch name {
+	case OriginLive, OriginImport:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unsupported origin %q, expected live or import", name)
+	}
+}
+
+// APIRequest represents an AI CLI API request entity (Claude Code unless another source is set)
 type APIRequest struct {
 	sessionID string
 	timestamp time.Time
@@ -13,6 +32,12 @@ type APIRequest struct {
 	tokens    Token
 	cost      Cost
 	duration  time.Duration
+	source    string
+	origin    string
+	user      string
+	project   string
+	star      StarScope
+	eventID   string // identifies a record re-sent by the exporter, not stored
 }
 
 // NewAPIRequest creates a new APIRequest entity
@@ -27,9 +52,67 @@ func NewAPIRequest(sessionID string, timestamp time.Time, model string, tokens T
 		tokens:    tokens,
 		cost:      cost,
 		duration:  time.Duration(durationMS) * time.Millisecond,
+		source:    SourceClaudeCode,
+		origin:    OriginLive,
 	}
 }
 
+// WithSource returns a copy of the API request reported by the given tool (e.g. "gemini_cli")
+// An empty source keeps the default, so records stored before sources existed stay Claude Code
+func (a APIRequest) WithSource(source string) APIRequest {
+	if source == "" {
+		return a
+	}
+	a.source = source
+	return a
+}
+
+// WithOrigin returns a copy of the API request with the given origin (e.g. "import")
+// An empty origin keeps the default, so records stored before origins existed stay live
+func (a APIRequest) WithOrigin(origin string) APIRequest {
+	if origin == "" {
+		return a
+	}
+	a.origin = origin
+	return a
+}
+
+// WithUser returns a copy of the API request made by the given user (e.g. an email address)
+func (a APIRequest) WithUser(user string) APIRequest {
+	a.user = user
+	return a
+}
+
+// WithProject returns a copy of the API request made in the given project (e.g. the repository name)
+func (a APIRequest) WithProject(project string) APIRequest {
+	a.project = project
+	return a
+}
+
+// WithEventID returns a copy of the API request with the ID the telemetry gives its event (e.g. log.record.uid)
+func (a APIRequest) WithEventID(eventID string) APIRequest {
+	a.eventID = eventID
+	return a
+}
+
+// WithTimestamp returns a copy of the API request with the given timestamp
+func (a APIRequest) WithTimestamp(timestamp time.Time) APIRequest {
+	a.timestamp = timestamp
+	return a
+}
+
+// WithCost returns a copy of the API request with the given cost, e.g. recalculated from a price table
+func (a APIRequest) WithCost(cost Cost) APIRequest {
+	a.cost = cost
+	return a
+}
+
+// WithStar returns a copy of the API request starred with the given scope
+func (a APIRequest) WithStar(star StarScope) APIRequest {
+	a.star = star
+	return a
+}
+
 // SessionID returns the session ID
 func (a APIRequest) SessionID() string {
 	return a.sessionID
@@ -60,6 +143,51 @@ func (a APIRequest) DurationMS() int64 {
 	return int64(a.duration / time.Millisecond)
 }
 
+// Source returns the tool that reported the request (e.g. "claude_code")
+func (a APIRequest) Source() string {
+	return a.source
+}
+
+// Origin returns how the request entered the database (e.g. "live")
+func (a APIRequest) Origin() string {
+	return a.origin
+}
+
+// User returns who made the request, empty when telemetry does not identify the user
+func (a APIRequest) User() string {
+	return a.user
+}
+
+// Project returns which project the request was made in, empty when telemetry does not identify the project
+func (a APIRequest) Project() string {
+	return a.project
+}
+
+// EventID returns the ID the telemetry gives the event, empty when it reports none
+func (a APIRequest) EventID() string {
+	return a.eventID
+}
+
+// DedupeKey identifies the request when an exporter sends it again after a retry
+// The event ID is used when reported, otherwise the session, time, model and tokens which a retry repeats unchanged
+func (a APIRequest) DedupeKey() string {
+	if a.eventID != "" {
+		return "event:" + a.eventID
+	}
+	return fmt.Sprintf("%s_%s_%s_%d_%d_%d_%d", a.sessionID, a.timestamp.Format(time.RFC3339Nano), a.model,
+		a.tokens.Input(), a.tokens.Output(), a.tokens.CacheRead(), a.tokens.CacheCreation())
+}
+
+// Star returns why the request is kept from the retention cleanup, StarNone when it is not starred
+func (a APIRequest) Star() StarScope {
+	return a.star
+}
+
+// IsStarred returns true if the request or its session is starred
+func (a APIRequest) IsStarred() bool {
+	return a.star != StarNone
+}
+
 // ID returns a unique identifier for the API request
 func (a APIRequest) ID() string {
 	return fmt.Sprintf("%s_%s", a.timestamp.Format(time.RFC3339Nano), a.sessionID)
@@ -108,3 +108,110 @@ func TestAPIRequest_ID_Uniqueness(t *testing.T) {
 		t.Errorf("Expected different IDs for different sessions, got same ID: %v", id3)
 	}
 }
+
+func TestAPIRequest_Source(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		source   *string
+		expected string
+	}{
+		{
+			name:     "defaults to claude code",
+			source:   nil,
+			expected: SourceClaudeCode,
+		},
+		{
+			name:     "custom source",
+			source:   stringPtr("gemini_cli"),
+			expected: "gemini_cli",
+		},
+		{
+			name:     "empty source keeps default",
+			source:   stringPtr(""),
+			expected: SourceClaudeCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewAPIRequest("session", time.Now(), "model", NewToken(1, 1, 0, 0), NewCost(0.01), 100)
+			if tt.source != nil {
+				req = req.WithSource(*tt.source)
+			}
+
+			if req.Source() != tt.expected {
+				t.Errorf("Source() = %q, want %q", req.Source(), tt.expected)
+			}
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestAPIRequest_Origin(t *testing.T) {
+	t.Parallel()
+
+	req := NewAPIRequest("session", time.Now(), "model", NewToken(1, 1, 0, 0), NewCost(0.01), 100)
+	if req.Origin() != OriginLive {
+		t.Errorf("Origin() = %q, want %q", req.Origin(), OriginLive)
+	}
+	if got := req.WithOrigin("").Origin(); got != OriginLive {
+		t.Errorf("empty origin: Origin() = %q, want %q", got, OriginLive)
+	}
+	if got := req.WithOrigin(OriginImport).Origin(); got != OriginImport {
+		t.Errorf("Origin() = %q, want %q", got, OriginImport)
+	}
+}
+
+func TestAPIRequest_DedupeKey(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 6, 1, 10, 0, 0, 123000000, time.UTC)
+	req := NewAPIRequest("session", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 10, 5), NewCost(0.01), 100)
+
+	// A retry repeats the record, the receiver may still set its own origin or project
+	resent := NewAPIRequest("session", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 10, 5), NewCost(0.01), 250).WithProject("ccmon")
+	if req.DedupeKey() != resent.DedupeKey() {
+		t.Errorf("Expected a re-sent request to share the key %q, got %q", req.DedupeKey(), resent.DedupeKey())
+	}
+
+	different := []APIRequest{
+		NewAPIRequest("other", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 10, 5), NewCost(0.01), 100),
+		NewAPIRequest("session", timestamp.Add(time.Millisecond), "claude-sonnet-4-20250514", NewToken(100, 50, 10, 5), NewCost(0.01), 100),
+		NewAPIRequest("session", timestamp, "claude-opus-4-20250514", NewToken(100, 50, 10, 5), NewCost(0.01), 100),
+		NewAPIRequest("session", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 10, 6), NewCost(0.01), 100),
+	}
+	for _, other := range different {
+		if req.DedupeKey() == other.DedupeKey() {
+			t.Errorf("Expected %q to differ from %q", other.DedupeKey(), req.DedupeKey())
+		}
+	}
+
+	// The event ID replaces the fields, so a record retried at another time is still recognized
+	withEvent := req.WithEventID("abc")
+	if withEvent.EventID() != "abc" || withEvent.DedupeKey() != "event:abc" {
+		t.Errorf("Expected the event ID key, got %q", withEvent.DedupeKey())
+	}
+	if got := resent.WithTimestamp(timestamp.Add(time.Hour)).WithEventID("abc").DedupeKey(); got != withEvent.DedupeKey() {
+		t.Errorf("Expected the same event ID to share the key, got %q", got)
+	}
+}
+
+func TestParseOrigin(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{OriginLive, OriginImport} {
+		if origin, err := ParseOrigin(name); err != nil || origin != name {
+			t.Errorf("ParseOrigin(%q) = %q, %v", name, origin, err)
+		}
+	}
+	if _, err := ParseOrigin("webhook"); err == nil {
+		t.Error("Expected an error for an unsupported origin")
+	}
+}
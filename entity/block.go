@@ -4,14 +4,15 @@ import (
 	"time"
 )
 
-// TimeBlockDuration represents the duration of each Claude token limit block
+// TimeBlockDuration represents the default duration of each Claude token limit block
 const TimeBlockDuration = 5 * time.Hour
 
-// Block represents a specific 5-hour token limit block for Claude
+// Block represents a specific token limit block for Claude, 5 hours long unless configured otherwise
 // This is a value object representing a concrete time period with optional token limit
 type Block struct {
-	startAt    time.Time // Concrete timestamp when this block starts
-	tokenLimit int       // Token limit for this block (0 = no limit)
+	startAt    time.Time     // Concrete timestamp when this block starts
+	tokenLimit int           // Token limit for this block (0 = no limit)
+	duration   time.Duration // Length of this block (0 = TimeBlockDuration)
 }
 
 // NewBlock creates a new Block from a concrete start timestamp without token limit
@@ -30,6 +31,20 @@ func NewBlockWithLimit(startAt time.Time, tokenLimit int) Block {
 	}
 }
 
+// WithDuration returns a copy of the block lasting the given duration, blocks before and after it keep the duration
+func (b Block) WithDuration(duration time.Duration) Block {
+	b.duration = duration
+	return b
+}
+
+// Duration returns the length of this block
+func (b Block) Duration() time.Duration {
+	if b.duration <= 0 {
+		return TimeBlockDuration
+	}
+	return b.duration
+}
+
 // StartAt returns the start time of this block
 func (b Block) StartAt() time.Time {
 	return b.startAt
@@ -37,7 +52,7 @@ func (b Block) StartAt() time.Time {
 
 // EndAt returns the end time of this block
 func (b Block) EndAt() time.Time {
-	return b.startAt.Add(TimeBlockDuration)
+	return b.startAt.Add(b.Duration())
 }
 
 // TokenLimit returns the token limit for this block (0 = no limit)
@@ -79,6 +94,20 @@ func (b Block) IsLimitExceeded(premiumTokens Token) bool {
 	return used > int64(b.tokenLimit)
 }
 
+// TokensRemaining returns the tokens left before the premium token usage reaches the limit
+// Returns false if no limit is configured, an exceeded limit has 0 tokens left
+func (b Block) TokensRemaining(premiumTokens Token) (int64, bool) {
+	if !b.HasLimit() {
+		return 0, false
+	}
+
+	left := int64(b.tokenLimit) - premiumTokens.Limited()
+	if left < 0 {
+		return 0, true
+	}
+	return left, true
+}
+
 // Period returns the time period represented by this block
 func (b Block) Period() Period {
 	return NewPeriod(b.startAt, b.EndAt())
@@ -95,9 +124,80 @@ func (b Block) NextBlock(now time.Time) Block {
 
 	// Calculate which block the current time falls into
 	delta := now.Sub(b.startAt)
-	blockIndex := int(delta / TimeBlockDuration)
+	blockIndex := int(delta / b.Duration())
 
-	// Create new block at the appropriate position, preserving token limit
-	newStart := b.startAt.Add(time.Duration(blockIndex) * TimeBlockDuration)
-	return NewBlockWithLimit(newStart, b.tokenLimit)
+	// Create new block at the appropriate position, preserving token limit and duration
+	newStart := b.startAt.Add(time.Duration(blockIndex) * b.Duration())
+	return NewBlockWithLimit(newStart, b.tokenLimit).WithDuration(b.duration)
+}
+
+// PreviousBlock returns the block immediately before this one, preserving token limit and duration
+func (b Block) PreviousBlock() Block {
+	return NewBlockWithLimit(b.startAt.Add(-b.Duration()), b.tokenLimit).WithDuration(b.duration)
+}
+
+// Elapsed returns the time passed since the block started, clamped to the block duration
+func (b Block) Elapsed(now time.Time) time.Duration {
+	elapsed := now.Sub(b.startAt)
+	if elapsed < 0 {
+		return 0
+	}
+	if elapsed > b.Duration() {
+		return b.Duration()
+	}
+	return elapsed
+}
+
+// Remaining returns the time left until the block ends, clamped to the block duration
+func (b Block) Remaining(now time.Time) time.Duration {
+	return b.Duration() - b.Elapsed(now)
+}
+
+// ElapsedPeriod returns the period from the block start covering the given elapsed time
+// Used to compare blocks at the same relative point in time
+func (b Block) ElapsedPeriod(elapsed time.Duration) Period {
+	if elapsed > b.Duration() {
+		elapsed = b.Duration()
+	}
+	return NewPeriod(b.startAt, b.startAt.Add(elapsed))
+}
+
+// minProjectionElapsed is how long a block runs before its burn rate is projected, earlier rates swing too much
+const minProjectionElapsed = time.Minute
+
+// BurnRate returns the limited tokens used per minute since the block started
+// Returns 0 before the block has run for a minute
+func (b Block) BurnRate(premiumTokens Token, now time.Time) float64 {
+	elapsed := b.Elapsed(now)
+	if elapsed < minProjectionElapsed {
+		return 0
+	}
+	return float64(premiumTokens.Limited()) / elapsed.Minutes()
+}
+
+// ProjectProgress returns the progress percentage at the block end if the current burn rate continues
+// Returns false without a limit or before the block has run for a minute
+func (b Block) ProjectProgress(premiumTokens Token, now time.Time) (float64, bool) {
+	if !b.HasLimit() || b.Elapsed(now) < minProjectionElapsed {
+		return 0, false
+	}
+
+	projected := float64(premiumTokens.Limited()) + b.BurnRate(premiumTokens, now)*b.Remaining(now).Minutes()
+	return projected / float64(b.tokenLimit) * 100, true
+}
+
+// LimitReachedAt returns when the limit is reached at the current burn rate
+// Returns false when the limit holds until the block end, an exceeded limit returns now
+func (b Block) LimitReachedAt(premiumTokens Token, now time.Time) (time.Time, bool) {
+	projected, ok := b.ProjectProgress(premiumTokens, now)
+	if !ok || projected <= 100 {
+		return time.Time{}, false
+	}
+
+	left := int64(b.tokenLimit) - premiumTokens.Limited()
+	if left <= 0 {
+		return now, true
+	}
+	minutes := float64(left) / b.BurnRate(premiumTokens, now)
+	return now.Add(time.Duration(minutes * float64(time.Minute))), true
 }
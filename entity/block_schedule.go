@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseBlockStartHour parses simple time format like "5am", "11pm" into hour (0-23)
+func ParseBlockStartHour(timeStr string) (int, error) {
+	timeStr = strings.ToLower(strings.TrimSpace(timeStr))
+
+	var hour int
+	var ampm string
+
+	// Parse formats like "5am", "11pm"
+	if strings.HasSuffix(timeStr, "am") || strings.HasSuffix(timeStr, "pm") {
+		ampm = timeStr[len(timeStr)-2:]
+		hourStr := timeStr[:len(timeStr)-2]
+
+		var err error
+		hour, err = strconv.Atoi(hourStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid hour format: %s", timeStr)
+		}
+
+		// Validate hour range
+		if hour < 1 || hour > 12 {
+			return 0, fmt.Errorf("hour must be between 1-12: %d", hour)
+		}
+
+		// Convert to 24-hour format
+		if ampm == "am" {
+			if hour == 12 {
+				hour = 0 // 12am = 0
+			}
+		} else { // pm
+			if hour != 12 {
+				hour += 12 // 1pm = 13, 11pm = 23
+			}
+			// 12pm = 12 (no change)
+		}
+	} else {
+		return 0, fmt.Errorf("time must end with 'am' or 'pm': %s", timeStr)
+	}
+
+	return hour, nil
+}
+
+// MaxBlockDuration is the longest configurable block, blocks start again at the start hour every day
+const MaxBlockDuration = 24 * time.Hour
+
+// ParseBlockDuration parses a block length like "5h", "1h" or "24h", empty returns the default 5 hours
+// Blocks are whole hours, so they line up with the hourly usage and the hours of the block labels
+func ParseBlockDuration(durationStr string) (time.Duration, error) {
+	durationStr = strings.TrimSpace(durationStr)
+	if durationStr == "" {
+		return TimeBlockDuration, nil
+	}
+
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration format: %s", durationStr)
+	}
+	if duration < time.Hour || duration > MaxBlockDuration {
+		return 0, fmt.Errorf("duration must be between 1h and 24h: %s", durationStr)
+	}
+	if duration%time.Hour != 0 {
+		return 0, fmt.Errorf("duration must be whole hours: %s", durationStr)
+	}
+
+	return duration, nil
+}
+
+// NewCurrentBlock calculates the current 5-hour block based on user's start hour and timezone
+// Always returns a valid block - either the current block or the next upcoming block.
+// Blocks last five real hours, a block spanning a daylight saving time change ends an hour later or earlier on the clock,
+// and the next day starts again at the start hour.
+func NewCurrentBlock(userStartHour int, timezone *time.Location, now time.Time, tokenLimit int) Block {
+	return NewCurrentBlockWithDuration(userStartHour, timezone, now, tokenLimit, TimeBlockDuration)
+}
+
+// NewCurrentBlockWithDuration calculates the current block of the given length based on user's start hour and timezone
+// Blocks follow each other from the start hour. Before today's start hour the upcoming block is returned,
+// unless the blocks divide the day evenly, e.g. 1-hour or 24-hour blocks, then yesterday's sequence continues until then
+func NewCurrentBlockWithDuration(userStartHour int, timezone *time.Location, now time.Time, tokenLimit int, duration time.Duration) Block {
+	if duration <= 0 {
+		duration = TimeBlockDuration
+	}
+	nowInTz := now.In(timezone)
+
+	// Create reference timestamp at start hour today, a start hour skipped by daylight saving time starts when the clocks jump
+	referenceTime := wallClock(nowInTz.Year(), nowInTz.Month(), nowInTz.Day(), userStartHour, timezone)
+
+	// Calculate time difference from reference
+	delta := nowInTz.Sub(referenceTime)
+
+	// If we're before the start time today, check if we're within a reasonable range
+	if delta < 0 {
+		// If we're before today's start hour, check if yesterday's sequence makes more sense
+		// This handles cases like current time 1am with 11pm start hour
+		if delta < -12*time.Hour {
+			// Use yesterday's reference instead
+			referenceTime = wallClock(nowInTz.Year(), nowInTz.Month(), nowInTz.Day()-1, userStartHour, timezone)
+			delta = nowInTz.Sub(referenceTime)
+		}
+
+		// If still negative, we're before the start time - show the upcoming block
+		// unless yesterday's sequence tiles the day and its block is still running
+		if delta < 0 {
+			if MaxBlockDuration%duration == 0 {
+				previousReference := wallClock(nowInTz.Year(), nowInTz.Month(), nowInTz.Day()-1, userStartHour, timezone)
+				previousStart := previousReference.Add(nowInTz.Sub(previousReference) / duration * duration)
+				if !previousStart.Add(duration).After(referenceTime) {
+					return NewBlockWithLimit(previousStart.UTC(), tokenLimit).WithDuration(duration)
+				}
+			}
+			return NewBlockWithLimit(referenceTime.UTC(), tokenLimit).WithDuration(duration)
+		}
+	}
+
+	// Calculate which block we're in based on the delta
+	blockIndex := int(delta / duration)
+	blockStart := referenceTime.Add(time.Duration(blockIndex) * duration)
+
+	return NewBlockWithLimit(blockStart.UTC(), tokenLimit).WithDuration(duration)
+}
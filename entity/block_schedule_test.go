@@ -0,0 +1,246 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestParseBlockStartHour(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  int
+		expectErr bool
+	}{
+		{name: "morning", input: "5am", expected: 5},
+		{name: "evening", input: "11pm", expected: 23},
+		{name: "midnight", input: "12am", expected: 0},
+		{name: "noon", input: "12pm", expected: 12},
+		{name: "uppercase with spaces", input: " 5AM ", expected: 5},
+		{name: "missing suffix", input: "5", expectErr: true},
+		{name: "out of range", input: "13pm", expectErr: true},
+		{name: "not a number", input: "fivepm", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hour, err := entity.ParseBlockStartHour(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ParseBlockStartHour(%q) expected error, got %d", tt.input, hour)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBlockStartHour(%q) returned error: %v", tt.input, err)
+			}
+			if hour != tt.expected {
+				t.Errorf("ParseBlockStartHour(%q) = %d, want %d", tt.input, hour, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewCurrentBlock(t *testing.T) {
+	tests := []struct {
+		name          string
+		startHour     int
+		now           time.Time
+		expectedStart time.Time
+	}{
+		{
+			name:          "within first block",
+			startHour:     5,
+			now:           time.Date(2025, 1, 1, 7, 30, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "within second block",
+			startHour:     5,
+			now:           time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "after midnight continues yesterday sequence",
+			startHour:     23,
+			now:           time.Date(2025, 1, 2, 1, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "before start hour shows upcoming block",
+			startHour:     5,
+			now:           time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := entity.NewCurrentBlock(tt.startHour, time.UTC, tt.now, 1000)
+			if !block.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("NewCurrentBlock() start = %v, want %v", block.StartAt(), tt.expectedStart)
+			}
+			if block.TokenLimit() != 1000 {
+				t.Errorf("NewCurrentBlock() token limit = %d, want 1000", block.TokenLimit())
+			}
+		})
+	}
+}
+
+func TestParseBlockDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "empty uses default", input: "", expected: entity.TimeBlockDuration},
+		{name: "one hour", input: "1h", expected: time.Hour},
+		{name: "daily", input: "24h", expected: 24 * time.Hour},
+		{name: "not a duration", input: "five hours", expectErr: true},
+		{name: "too short", input: "30m", expectErr: true},
+		{name: "too long", input: "48h", expectErr: true},
+		{name: "not whole hours", input: "1h30m", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration, err := entity.ParseBlockDuration(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("ParseBlockDuration(%q) expected error, got %v", tt.input, duration)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBlockDuration(%q) unexpected error: %v", tt.input, err)
+			}
+			if duration != tt.expected {
+				t.Errorf("ParseBlockDuration(%q) = %v, want %v", tt.input, duration, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewCurrentBlockWithDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		startHour     int
+		duration      time.Duration
+		now           time.Time
+		expectedStart time.Time
+	}{
+		{
+			name:          "hourly block",
+			startHour:     5,
+			duration:      time.Hour,
+			now:           time.Date(2025, 1, 1, 7, 30, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "hourly block before the start hour continues yesterday sequence",
+			startHour:     5,
+			duration:      time.Hour,
+			now:           time.Date(2025, 1, 1, 3, 30, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "daily block",
+			startHour:     5,
+			duration:      24 * time.Hour,
+			now:           time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "daily block before the start hour is yesterday's",
+			startHour:     5,
+			duration:      24 * time.Hour,
+			now:           time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 12, 31, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "blocks not dividing the day show upcoming block before the start hour",
+			startHour:     5,
+			duration:      7 * time.Hour,
+			now:           time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2025, 1, 1, 5, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := entity.NewCurrentBlockWithDuration(tt.startHour, time.UTC, tt.now, 1000, tt.duration)
+			if !block.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("NewCurrentBlockWithDuration() start = %v, want %v", block.StartAt(), tt.expectedStart)
+			}
+			if block.Duration() != tt.duration || !block.EndAt().Equal(tt.expectedStart.Add(tt.duration)) {
+				t.Errorf("NewCurrentBlockWithDuration() duration = %v, want %v", block.Duration(), tt.duration)
+			}
+		})
+	}
+}
+
+func TestNewCurrentBlock_DaylightSavingTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		startHour     int
+		now           time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "block spanning spring forward lasts five hours",
+			startHour:     23,
+			now:           time.Date(2025, 3, 9, 3, 30, 0, 0, newYork),
+			expectedStart: time.Date(2025, 3, 8, 23, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 3, 9, 5, 0, 0, 0, newYork),
+		},
+		{
+			name:          "block spanning fall back lasts five hours",
+			startHour:     23,
+			now:           time.Date(2025, 11, 2, 1, 30, 0, 0, newYork),
+			expectedStart: time.Date(2025, 11, 1, 23, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 11, 2, 3, 0, 0, 0, newYork),
+		},
+		{
+			name:          "start hour skipped by spring forward starts when the clocks jump",
+			startHour:     2,
+			now:           time.Date(2025, 3, 9, 4, 0, 0, 0, newYork),
+			expectedStart: time.Date(2025, 3, 9, 3, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 3, 9, 8, 0, 0, 0, newYork),
+		},
+		{
+			name:          "day after spring forward starts at the start hour",
+			startHour:     5,
+			now:           time.Date(2025, 3, 10, 12, 0, 0, 0, newYork),
+			expectedStart: time.Date(2025, 3, 10, 10, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 3, 10, 15, 0, 0, 0, newYork),
+		},
+		{
+			name:          "day after fall back starts at the start hour",
+			startHour:     5,
+			now:           time.Date(2025, 11, 3, 12, 0, 0, 0, newYork),
+			expectedStart: time.Date(2025, 11, 3, 10, 0, 0, 0, newYork),
+			expectedEnd:   time.Date(2025, 11, 3, 15, 0, 0, 0, newYork),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := entity.NewCurrentBlock(tt.startHour, newYork, tt.now, 0)
+			if !block.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("NewCurrentBlock() start = %v, want %v", block.StartAt().In(newYork), tt.expectedStart)
+			}
+			if !block.EndAt().Equal(tt.expectedEnd) {
+				t.Errorf("NewCurrentBlock() end = %v, want %v", block.EndAt().In(newYork), tt.expectedEnd)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -101,3 +102,197 @@ func TestBlock_ValueObjectBehavior(t *testing.T) {
 	})
 
 }
+
+func TestBlock_PreviousBlock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlockWithLimit(start, 7000)
+
+	prev := block.PreviousBlock()
+	if !prev.StartAt().Equal(start.Add(-TimeBlockDuration)) {
+		t.Errorf("PreviousBlock().StartAt() = %v, want %v", prev.StartAt(), start.Add(-TimeBlockDuration))
+	}
+	if prev.TokenLimit() != 7000 {
+		t.Errorf("PreviousBlock().TokenLimit() = %d, want 7000", prev.TokenLimit())
+	}
+}
+
+func TestBlock_WithDuration(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlockWithLimit(start, 7000).WithDuration(time.Hour)
+
+	if !block.EndAt().Equal(start.Add(time.Hour)) {
+		t.Errorf("EndAt() = %v, want %v", block.EndAt(), start.Add(time.Hour))
+	}
+	if got := block.Remaining(start.Add(20 * time.Minute)); got != 40*time.Minute {
+		t.Errorf("Remaining() = %v, want 40m", got)
+	}
+	if got := block.Elapsed(start.Add(2 * time.Hour)); got != time.Hour {
+		t.Errorf("Elapsed() = %v, want 1h", got)
+	}
+
+	// Blocks before and after keep the duration
+	next := block.NextBlock(start.Add(150 * time.Minute))
+	if !next.StartAt().Equal(start.Add(2*time.Hour)) || next.Duration() != time.Hour {
+		t.Errorf("NextBlock() = %v lasting %v, want %v lasting 1h", next.StartAt(), next.Duration(), start.Add(2*time.Hour))
+	}
+	prev := block.PreviousBlock()
+	if !prev.StartAt().Equal(start.Add(-time.Hour)) || prev.Duration() != time.Hour || prev.TokenLimit() != 7000 {
+		t.Errorf("PreviousBlock() = %v lasting %v, want %v lasting 1h", prev.StartAt(), prev.Duration(), start.Add(-time.Hour))
+	}
+
+	if NewBlock(start).Duration() != TimeBlockDuration {
+		t.Errorf("Expected blocks to last %v by default", TimeBlockDuration)
+	}
+}
+
+func TestBlock_Elapsed(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlock(start)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Duration
+	}{
+		{name: "before block start", now: start.Add(-time.Hour), expected: 0},
+		{name: "within block", now: start.Add(90 * time.Minute), expected: 90 * time.Minute},
+		{name: "after block end", now: start.Add(6 * time.Hour), expected: TimeBlockDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := block.Elapsed(tt.now); got != tt.expected {
+				t.Errorf("Elapsed() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlock_Remaining(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlock(start)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Duration
+	}{
+		{name: "before block start", now: start.Add(-time.Hour), expected: TimeBlockDuration},
+		{name: "within block", now: start.Add(90 * time.Minute), expected: 210 * time.Minute},
+		{name: "after block end", now: start.Add(6 * time.Hour), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := block.Remaining(tt.now); got != tt.expected {
+				t.Errorf("Remaining() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlock_TokensRemaining(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		block    Block
+		tokens   Token
+		expected int64
+		ok       bool
+	}{
+		{name: "within limit", block: NewBlockWithLimit(start, 100000), tokens: NewToken(20000, 10000, 5000, 0), expected: 70000, ok: true},
+		{name: "limit exceeded", block: NewBlockWithLimit(start, 100000), tokens: NewToken(80000, 40000, 0, 0), expected: 0, ok: true},
+		{name: "without limit", block: NewBlock(start), tokens: NewToken(1000, 0, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.block.TokensRemaining(tt.tokens)
+			if ok != tt.ok || got != tt.expected {
+				t.Errorf("TokensRemaining() = %d, %v, want %d, %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestBlock_ElapsedPeriod(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlock(start)
+
+	period := block.ElapsedPeriod(90 * time.Minute)
+	if !period.StartAt().Equal(start) || !period.EndAt().Equal(start.Add(90*time.Minute)) {
+		t.Errorf("ElapsedPeriod() = %v - %v, want %v - %v", period.StartAt(), period.EndAt(), start, start.Add(90*time.Minute))
+	}
+
+	// Elapsed time beyond the block is clamped to the block end
+	period = block.ElapsedPeriod(10 * time.Hour)
+	if !period.EndAt().Equal(block.EndAt()) {
+		t.Errorf("ElapsedPeriod() end = %v, want %v", period.EndAt(), block.EndAt())
+	}
+}
+
+func TestBlock_BurnRate(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlockWithLimit(start, 100000)
+	tokens := NewToken(20000, 10000, 0, 0)
+
+	if got := block.BurnRate(tokens, start.Add(time.Hour)); got != 500 {
+		t.Errorf("BurnRate() = %v, want 500", got)
+	}
+	if got := block.BurnRate(tokens, start.Add(30*time.Second)); got != 0 {
+		t.Errorf("BurnRate() within the first minute = %v, want 0", got)
+	}
+}
+
+func TestBlock_ProjectProgress(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		block    Block
+		tokens   Token
+		now      time.Time
+		expected float64
+		ok       bool
+	}{
+		{name: "on pace", block: NewBlockWithLimit(start, 100000), tokens: NewToken(10000, 10000, 0, 0), now: start.Add(time.Hour), expected: 100, ok: true},
+		{name: "over pace", block: NewBlockWithLimit(start, 100000), tokens: NewToken(20000, 6000, 0, 0), now: start.Add(time.Hour), expected: 130, ok: true},
+		{name: "block ended", block: NewBlockWithLimit(start, 100000), tokens: NewToken(40000, 10000, 0, 0), now: start.Add(6 * time.Hour), expected: 50, ok: true},
+		{name: "first minute", block: NewBlockWithLimit(start, 100000), tokens: NewToken(1000, 0, 0, 0), now: start.Add(30 * time.Second)},
+		{name: "without limit", block: NewBlock(start), tokens: NewToken(1000, 0, 0, 0), now: start.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.block.ProjectProgress(tt.tokens, tt.now)
+			if ok != tt.ok {
+				t.Fatalf("ProjectProgress() ok = %v, want %v", ok, tt.ok)
+			}
+			if math.Abs(got-tt.expected) > 0.001 {
+				t.Errorf("ProjectProgress() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBlock_LimitReachedAt(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	block := NewBlockWithLimit(start, 100000)
+	now := start.Add(time.Hour)
+
+	// 40K in the first hour reaches the 100K limit after 2.5 hours
+	at, ok := block.LimitReachedAt(NewToken(30000, 10000, 0, 0), now)
+	if !ok || !at.Equal(start.Add(150*time.Minute)) {
+		t.Errorf("LimitReachedAt() = %v, %v, want %v", at, ok, start.Add(150*time.Minute))
+	}
+
+	if _, ok := block.LimitReachedAt(NewToken(10000, 0, 0, 0), now); ok {
+		t.Error("Expected the limit to hold until the block end")
+	}
+
+	at, ok = block.LimitReachedAt(NewToken(100000, 20000, 0, 0), now)
+	if !ok || !at.Equal(now) {
+		t.Errorf("LimitReachedAt() of an exceeded limit = %v, %v, want %v", at, ok, now)
+	}
+}
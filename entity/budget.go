@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// Budget represents the spending budgets statuslines report the remaining amount of
+// Budgets which are not configured fall back to the plan price
+type Budget struct {
+	daily   Cost
+	monthly Cost
+}
+
+// NewBudget creates a new Budget, a zero amount falls back to the plan price
+func NewBudget(daily Cost, monthly Cost) Budget {
+	return Budget{
+		daily:   daily,
+		monthly: monthly,
+	}
+}
+
+// Daily returns the configured daily budget
+func (b Budget) Daily() Cost {
+	return b.daily
+}
+
+// Monthly returns the configured monthly budget
+func (b Budget) Monthly() Cost {
+	return b.monthly
+}
+
+// MonthlyFor returns the configured monthly budget, or the plan price when not configured
+func (b Budget) MonthlyFor(plan Plan) Cost {
+	if b.monthly.Amount() > 0 {
+		return b.monthly
+	}
+	if !plan.IsValid() || plan.Price().Amount() <= 0 {
+		return NewCost(0)
+	}
+	return plan.Price()
+}
+
+// DailyFor returns the configured daily budget, or the monthly budget spread evenly over the month containing the day
+func (b Budget) DailyFor(plan Plan, day Period) Cost {
+	if b.daily.Amount() > 0 {
+		return b.daily
+	}
+
+	dayStart := day.StartAt()
+	daysInMonth := time.Date(dayStart.Year(), dayStart.Month()+1, 0, 0, 0, 0, 0, dayStart.Location()).Day()
+	return NewCost(b.MonthlyFor(plan).Amount() / float64(daysInMonth))
+}
+
+// DailyLeft returns the daily budget left after the spent cost, false when no budget is available
+// Overspending is reported as nothing left
+func (b Budget) DailyLeft(plan Plan, day Period, spent Cost) (Cost, bool) {
+	return remaining(b.DailyFor(plan, day), spent)
+}
+
+// MonthlyLeft returns the monthly budget left after the spent cost, false when no budget is available
+// Overspending is reported as nothing left
+func (b Budget) MonthlyLeft(plan Plan, spent Cost) (Cost, bool) {
+	return remaining(b.MonthlyFor(plan), spent)
+}
+
+// remaining returns the budget left after the spent cost, never below zero
+func remaining(budget Cost, spent Cost) (Cost, bool) {
+	if budget.Amount() <= 0 {
+		return NewCost(0), false
+	}
+	return NewCost(math.Max(budget.Amount()-spent.Amount(), 0)), true
+}
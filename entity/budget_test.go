@@ -0,0 +1,106 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestBudget_Left(t *testing.T) {
+	// June has 30 days, so a $30 plan allows $1 a day
+	june := entity.NewPeriod(
+		time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 15, 23, 59, 59, 0, time.UTC),
+	)
+	pro := entity.NewPlan("pro", entity.NewCost(30))
+	unset := entity.NewPlan("unset", entity.NewCost(0))
+
+	tests := []struct {
+		name                string
+		budget              entity.Budget
+		plan                entity.Plan
+		dailySpent          float64
+		monthlySpent        float64
+		expectedDailyLeft   float64
+		expectedDailyOK     bool
+		expectedMonthlyLeft float64
+		expectedMonthlyOK   bool
+	}{
+		{
+			name:                "falls back to plan price",
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(0)),
+			plan:                pro,
+			dailySpent:          0.25,
+			monthlySpent:        12,
+			expectedDailyLeft:   0.75,
+			expectedDailyOK:     true,
+			expectedMonthlyLeft: 18,
+			expectedMonthlyOK:   true,
+		},
+		{
+			name:                "configured budgets override the plan",
+			budget:              entity.NewBudget(entity.NewCost(5), entity.NewCost(100)),
+			plan:                pro,
+			dailySpent:          1.6,
+			monthlySpent:        40,
+			expectedDailyLeft:   3.4,
+			expectedDailyOK:     true,
+			expectedMonthlyLeft: 60,
+			expectedMonthlyOK:   true,
+		},
+		{
+			name:                "daily budget derived from configured monthly budget",
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(60)),
+			plan:                unset,
+			dailySpent:          0.5,
+			monthlySpent:        10,
+			expectedDailyLeft:   1.5,
+			expectedDailyOK:     true,
+			expectedMonthlyLeft: 50,
+			expectedMonthlyOK:   true,
+		},
+		{
+			name:                "overspending leaves nothing",
+			budget:              entity.NewBudget(entity.NewCost(5), entity.NewCost(0)),
+			plan:                pro,
+			dailySpent:          7,
+			monthlySpent:        45,
+			expectedDailyLeft:   0,
+			expectedDailyOK:     true,
+			expectedMonthlyLeft: 0,
+			expectedMonthlyOK:   true,
+		},
+		{
+			name:                "no plan and no budget",
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(0)),
+			plan:                unset,
+			dailySpent:          1,
+			monthlySpent:        10,
+			expectedDailyLeft:   0,
+			expectedDailyOK:     false,
+			expectedMonthlyLeft: 0,
+			expectedMonthlyOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dailyLeft, ok := tt.budget.DailyLeft(tt.plan, june, entity.NewCost(tt.dailySpent))
+			if ok != tt.expectedDailyOK {
+				t.Errorf("Expected daily budget available %v, got %v", tt.expectedDailyOK, ok)
+			}
+			if diff := dailyLeft.Amount() - tt.expectedDailyLeft; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected $%.4f left today, got $%.4f", tt.expectedDailyLeft, dailyLeft.Amount())
+			}
+
+			monthlyLeft, ok := tt.budget.MonthlyLeft(tt.plan, entity.NewCost(tt.monthlySpent))
+			if ok != tt.expectedMonthlyOK {
+				t.Errorf("Expected monthly budget available %v, got %v", tt.expectedMonthlyOK, ok)
+			}
+			if diff := monthlyLeft.Amount() - tt.expectedMonthlyLeft; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected $%.4f left this month, got $%.4f", tt.expectedMonthlyLeft, monthlyLeft.Amount())
+			}
+		})
+	}
+}
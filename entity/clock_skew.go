@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkewAction is how API requests timestamped too far in the future are handled
+type ClockSkewAction string
+
+const (
+	// ClockSkewClamp stores the request with the server receive time as its timestamp
+	ClockSkewClamp ClockSkewAction = "clamp"
+	// ClockSkewDrop discards the request so it never reaches the stats
+	ClockSkewDrop ClockSkewAction = "drop"
+)
+
+// DefaultClockSkewTolerance is how far ahead of the server clock a request may be timestamped
+const DefaultClockSkewTolerance = 5 * time.Minute
+
+// ClockSkewPolicy detects API requests from exporters whose clock runs ahead of the server
+// Future timestamps otherwise count towards the wrong day and block
+type ClockSkewPolicy struct {
+	tolerance time.Duration
+	action    ClockSkewAction
+}
+
+// NewClockSkewPolicy creates a new ClockSkewPolicy, a zero tolerance disables detection
+func NewClockSkewPolicy(tolerance time.Duration, action ClockSkewAction) (ClockSkewPolicy, error) {
+	if tolerance < 0 {
+		return ClockSkewPolicy{}, fmt.Errorf("clock skew tolerance must not be negative, got: %v", tolerance)
+	}
+
+	switch action {
+	case ClockSkewClamp, ClockSkewDrop:
+	default:
+		return ClockSkewPolicy{}, fmt.Errorf("unsupported clock skew action %q, expected %s or %s", action, ClockSkewClamp, ClockSkewDrop)
+	}
+
+	return ClockSkewPolicy{
+		tolerance: tolerance,
+		action:    action,
+	}, nil
+}
+
+// Tolerance returns how far in the future a timestamp may be before it is treated as skewed
+func (p ClockSkewPolicy) Tolerance() time.Duration {
+	return p.tolerance
+}
+
+// Action returns how skewed requests are handled
+func (p ClockSkewPolicy) Action() ClockSkewAction {
+	return p.action
+}
+
+// IsEnabled returns true if clock skew detection is enabled
+func (p ClockSkewPolicy) IsEnabled() bool {
+	return p.tolerance > 0
+}
+
+// Skew returns how far the request timestamp is ahead of the receive time, zero when it is not
+func (p ClockSkewPolicy) Skew(req APIRequest, receivedAt time.Time) time.Duration {
+	skew := req.Timestamp().Sub(receivedAt)
+	if skew < 0 {
+		return 0
+	}
+	return skew
+}
+
+// IsSkewed returns true if the request timestamp is ahead of the receive time beyond the tolerance
+func (p ClockSkewPolicy) IsSkewed(req APIRequest, receivedAt time.Time) bool {
+	return p.IsEnabled() && p.Skew(req, receivedAt) > p.tolerance
+}
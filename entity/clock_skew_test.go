@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewClockSkewPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		tolerance time.Duration
+		action    ClockSkewAction
+		wantErr   bool
+	}{
+		{name: "clamp", tolerance: time.Minute, action: ClockSkewClamp},
+		{name: "drop", tolerance: time.Minute, action: ClockSkewDrop},
+		{name: "disabled", tolerance: 0, action: ClockSkewClamp},
+		{name: "negative tolerance", tolerance: -time.Minute, action: ClockSkewClamp, wantErr: true},
+		{name: "unsupported action", tolerance: time.Minute, action: "quarantine", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewClockSkewPolicy(tt.tolerance, tt.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewClockSkewPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClockSkewPolicy_IsSkewed(t *testing.T) {
+	t.Parallel()
+
+	receivedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		tolerance    time.Duration
+		timestamp    time.Time
+		expectedSkew time.Duration
+		skewed       bool
+	}{
+		{
+			name:      "past timestamp",
+			tolerance: 5 * time.Minute,
+			timestamp: receivedAt.Add(-time.Minute),
+		},
+		{
+			name:         "future timestamp within tolerance",
+			tolerance:    5 * time.Minute,
+			timestamp:    receivedAt.Add(4 * time.Minute),
+			expectedSkew: 4 * time.Minute,
+		},
+		{
+			name:         "future timestamp beyond tolerance",
+			tolerance:    5 * time.Minute,
+			timestamp:    receivedAt.Add(2 * time.Hour),
+			expectedSkew: 2 * time.Hour,
+			skewed:       true,
+		},
+		{
+			name:         "disabled policy never detects skew",
+			tolerance:    0,
+			timestamp:    receivedAt.Add(2 * time.Hour),
+			expectedSkew: 2 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := NewClockSkewPolicy(tt.tolerance, ClockSkewClamp)
+			if err != nil {
+				t.Fatalf("NewClockSkewPolicy() failed: %v", err)
+			}
+			req := NewAPIRequest("session", tt.timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+
+			if got := policy.Skew(req, receivedAt); got != tt.expectedSkew {
+				t.Errorf("Skew() = %v, want %v", got, tt.expectedSkew)
+			}
+			if got := policy.IsSkewed(req, receivedAt); got != tt.skewed {
+				t.Errorf("IsSkewed() = %v, want %v", got, tt.skewed)
+			}
+		})
+	}
+}
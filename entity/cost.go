@@ -19,3 +19,17 @@ func (c Cost) Amount() float64 {
 func (c Cost) Add(other Cost) Cost {
 	return Cost{amount: c.amount + other.amount}
 }
+
+// Scale returns a new Cost multiplied by the factor
+func (c Cost) Scale(factor float64) Cost {
+	return Cost{amount: c.amount * factor}
+}
+
+// PerKiloToken returns the cost of 1,000 of the tokens, false without tokens
+// Cached tokens count too, so cache reads lower the cost per 1K tokens as they are billed at a discount
+func (c Cost) PerKiloToken(tokens Token) (Cost, bool) {
+	if tokens.Total() <= 0 {
+		return Cost{}, false
+	}
+	return Cost{amount: c.amount / float64(tokens.Total()) * 1000}, true
+}
@@ -0,0 +1,79 @@
+package entity
+
+import "fmt"
+
+// DefaultCostAnomalyMultiple flags days costing more than twice their baseline
+const DefaultCostAnomalyMultiple = 2.0
+
+// CostAnomalyPolicy flags days whose premium and 1M context cost exceeds a multiple of the 7-day baseline, see MovingAverage.Baseline
+// A zero multiple disables the detection
+type CostAnomalyPolicy struct {
+	multiple float64
+}
+
+// NewCostAnomalyPolicy creates a new CostAnomalyPolicy, a multiple of 0 disables it
+func NewCostAnomalyPolicy(multiple float64) CostAnomalyPolicy {
+	return CostAnomalyPolicy{
+		multiple: multiple,
+	}
+}
+
+// Multiple returns how many times the baseline a day may cost before it is flagged
+func (p CostAnomalyPolicy) Multiple() float64 {
+	return p.multiple
+}
+
+// IsEnabled returns true if days are checked against their baseline
+func (p CostAnomalyPolicy) IsEnabled() bool {
+	return p.multiple > 0
+}
+
+// Detect compares the cost of a day with its baseline
+func (p CostAnomalyPolicy) Detect(cost, baseline Cost) CostAnomaly {
+	return CostAnomaly{
+		cost:     cost,
+		baseline: baseline,
+		multiple: p.multiple,
+	}
+}
+
+// CostAnomaly represents the cost of a day compared with its baseline
+type CostAnomaly struct {
+	cost     Cost
+	baseline Cost
+	multiple float64
+}
+
+// Cost returns the premium and 1M context cost of the day
+func (a CostAnomaly) Cost() Cost {
+	return a.cost
+}
+
+// Baseline returns the average daily premium and 1M context cost of the 7 days before
+func (a CostAnomaly) Baseline() Cost {
+	return a.baseline
+}
+
+// Ratio returns the cost as a multiple of the baseline, false without a baseline to compare with
+func (a CostAnomaly) Ratio() (float64, bool) {
+	if a.baseline.Amount() <= 0 {
+		return 0, false
+	}
+	return a.cost.Amount() / a.baseline.Amount(), true
+}
+
+// IsAnomaly returns true if the day costs more than the multiple of its baseline
+// Days without a baseline, e.g. after a week without usage, are never flagged
+func (a CostAnomaly) IsAnomaly() bool {
+	ratio, ok := a.Ratio()
+	return a.multiple > 0 && ok && ratio > a.multiple
+}
+
+// String returns the ratio of an anomalous day (e.g. "2.4x"), empty when the day is within its baseline
+func (a CostAnomaly) String() string {
+	if !a.IsAnomaly() {
+		return ""
+	}
+	ratio, _ := a.Ratio()
+	return fmt.Sprintf("%.1fx", ratio)
+}
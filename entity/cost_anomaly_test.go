@@ -0,0 +1,50 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestCostAnomalyPolicy_Detect(t *testing.T) {
+	tests := []struct {
+		name            string
+		multiple        float64
+		cost            float64
+		baseline        float64
+		expectedAnomaly bool
+		expectedString  string
+	}{
+		{name: "above the multiple", multiple: 2, cost: 12, baseline: 5, expectedAnomaly: true, expectedString: "2.4x"},
+		{name: "at the multiple", multiple: 2, cost: 10, baseline: 5},
+		{name: "below the multiple", multiple: 2, cost: 6, baseline: 5},
+		{name: "without a baseline", multiple: 2, cost: 6, baseline: 0},
+		{name: "disabled", multiple: 0, cost: 50, baseline: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomaly := entity.NewCostAnomalyPolicy(tt.multiple).Detect(entity.NewCost(tt.cost), entity.NewCost(tt.baseline))
+
+			if anomaly.IsAnomaly() != tt.expectedAnomaly {
+				t.Errorf("Expected IsAnomaly() = %v, got %v", tt.expectedAnomaly, anomaly.IsAnomaly())
+			}
+			if anomaly.String() != tt.expectedString {
+				t.Errorf("Expected String() = %q, got %q", tt.expectedString, anomaly.String())
+			}
+		})
+	}
+}
+
+func TestCostAnomaly_Ratio(t *testing.T) {
+	policy := entity.NewCostAnomalyPolicy(entity.DefaultCostAnomalyMultiple)
+
+	if _, ok := policy.Detect(entity.NewCost(3), entity.NewCost(0)).Ratio(); ok {
+		t.Error("Expected no ratio without a baseline")
+	}
+
+	ratio, ok := policy.Detect(entity.NewCost(3), entity.NewCost(2)).Ratio()
+	if !ok || ratio != 1.5 {
+		t.Errorf("Expected a ratio of 1.5, got %v (ok=%v)", ratio, ok)
+	}
+}
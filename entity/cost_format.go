@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bounds for the configurable cost precision
+const (
+	MinCostPrecision = 0
+	MaxCostPrecision = 8
+)
+
+// DefaultCostPrecision is the number of decimals of a format without a precision, when the view has no default of its own
+const DefaultCostPrecision = 2
+
+// maxHumanizedSmallDecimals caps the decimals used to show small costs in humanized mode
+const maxHumanizedSmallDecimals = 6
+
+// CostFormat represents how cost amounts are rendered for display
+// The zero value has no precision, so each view keeps the decimals it always used (see OrPrecision)
+type CostFormat struct {
+	precision    int
+	hasPrecision bool
+	humanize     bool
+}
+
+// NewCostFormat creates a new CostFormat value object
+// In humanized mode small costs keep two significant digits (e.g. "0.0012")
+// and large costs are abbreviated (e.g. "1.2k")
+func NewCostFormat(precision int, humanize bool) CostFormat {
+	return CostFormat{
+		precision:    precision,
+		hasPrecision: true,
+		humanize:     humanize,
+	}
+}
+
+// NewCostFormatWithoutPrecision creates a new CostFormat keeping the decimals of each view, e.g. when display.cost_precision is not set
+func NewCostFormatWithoutPrecision(humanize bool) CostFormat {
+	return CostFormat{humanize: humanize}
+}
+
+// DefaultCostFormat returns the humanized format with cent precision
+func DefaultCostFormat() CostFormat {
+	return NewCostFormat(2, true)
+}
+
+// OrPrecision returns the format with the given precision when it has none, e.g. the one decimal of the format variables
+func (f CostFormat) OrPrecision(precision int) CostFormat {
+	if f.hasPrecision {
+		return f
+	}
+	f.precision = precision
+	f.hasPrecision = true
+	return f
+}
+
+// HasPrecision returns true if the number of decimals was given
+func (f CostFormat) HasPrecision() bool {
+	return f.hasPrecision
+}
+
+// Precision returns the number of decimals used for regular amounts, DefaultCostPrecision without a precision
+func (f CostFormat) Precision() int {
+	if !f.hasPrecision {
+		return DefaultCostPrecision
+	}
+	return f.precision
+}
+
+// IsHumanized returns true if small and large amounts are humanized
+func (f CostFormat) IsHumanized() bool {
+	return f.humanize
+}
+
+// Format renders the cost with a dollar sign (e.g. "$1.23")
+func (f CostFormat) Format(cost Cost) string {
+	return "$" + f.FormatAmount(cost.Amount())
+}
+
+// FormatAmount renders the amount without a currency sign, for columns already labeled in dollars
+func (f CostFormat) FormatAmount(amount float64) string {
+	precision := f.Precision()
+	if !f.humanize {
+		return fmt.Sprintf("%.*f", precision, amount)
+	}
+
+	abs := math.Abs(amount)
+	switch {
+	case abs >= 1000000:
+		return fmt.Sprintf("%.1fM", amount/1000000)
+	case abs >= 1000:
+		return fmt.Sprintf("%.1fk", amount/1000)
+	case abs > 0 && abs < 0.5*math.Pow10(-precision):
+		// Would round to zero, keep two significant digits instead
+		decimals := int(math.Ceil(-math.Log10(abs))) + 1
+		if decimals > maxHumanizedSmallDecimals {
+			decimals = maxHumanizedSmallDecimals
+		}
+		return fmt.Sprintf("%.*f", decimals, amount)
+	default:
+		return fmt.Sprintf("%.*f", precision, amount)
+	}
+}
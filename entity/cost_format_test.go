@@ -0,0 +1,59 @@
+package entity
+
+import "testing"
+
+func TestCostFormat_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   CostFormat
+		amount   float64
+		expected string
+	}{
+		{name: "humanized zero", format: DefaultCostFormat(), amount: 0, expected: "$0.00"},
+		{name: "humanized regular amount", format: DefaultCostFormat(), amount: 1.234, expected: "$1.23"},
+		{name: "humanized small amount keeps significant digits", format: DefaultCostFormat(), amount: 0.001234, expected: "$0.0012"},
+		{name: "humanized tiny amount is capped", format: DefaultCostFormat(), amount: 0.0000001, expected: "$0.000000"},
+		{name: "humanized amount rounding to precision", format: DefaultCostFormat(), amount: 0.005, expected: "$0.01"},
+		{name: "humanized thousands", format: DefaultCostFormat(), amount: 1234.5, expected: "$1.2k"},
+		{name: "humanized millions", format: DefaultCostFormat(), amount: 2500000, expected: "$2.5M"},
+		{name: "humanized custom precision", format: NewCostFormat(1, true), amount: 12.34, expected: "$12.3"},
+		{name: "fixed precision", format: NewCostFormat(6, false), amount: 0.001234, expected: "$0.001234"},
+		{name: "fixed large amount is not abbreviated", format: NewCostFormat(2, false), amount: 1234.5, expected: "$1234.50"},
+		{name: "fixed zero precision", format: NewCostFormat(0, false), amount: 12.6, expected: "$13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Format(NewCost(tt.amount)); got != tt.expected {
+				t.Errorf("Format(%v) = %q, want %q", tt.amount, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCostFormat_FormatAmount(t *testing.T) {
+	format := DefaultCostFormat()
+
+	if got := format.FormatAmount(1.5); got != "1.50" {
+		t.Errorf("FormatAmount(1.5) = %q, want %q", got, "1.50")
+	}
+	if format.Precision() != 2 || !format.IsHumanized() {
+		t.Errorf("DefaultCostFormat() = precision %d humanize %v, want 2 true", format.Precision(), format.IsHumanized())
+	}
+}
+
+func TestCostFormat_OrPrecision(t *testing.T) {
+	unset := NewCostFormatWithoutPrecision(false)
+	if unset.HasPrecision() || unset.Precision() != DefaultCostPrecision {
+		t.Errorf("Expected no precision and the default of %d decimals, got %v and %d", DefaultCostPrecision, unset.HasPrecision(), unset.Precision())
+	}
+	if got := unset.OrPrecision(1).Format(NewCost(15)); got != "$15.0" {
+		t.Errorf("Expected the view precision without a precision, got %q", got)
+	}
+	if got := NewCostFormat(3, false).OrPrecision(1).Format(NewCost(15)); got != "$15.000" {
+		t.Errorf("Expected the given precision to be kept, got %q", got)
+	}
+	if got := (CostFormat{}).OrPrecision(6).FormatAmount(0.001234); got != "0.001234" {
+		t.Errorf("Expected the zero format to take the view precision, got %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// DailySummarySchedule is the local time of day the end-of-day summary is sent
+type DailySummarySchedule struct {
+	hour     int
+	minute   int
+	timezone *time.Location
+	enabled  bool
+}
+
+// ParseDailySummarySchedule parses a time of day like "18:00" in the timezone, empty disables the summary
+func ParseDailySummarySchedule(at string, timezone *time.Location) (DailySummarySchedule, error) {
+	if at == "" {
+		return DailySummarySchedule{}, nil
+	}
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	parsed, err := time.Parse("15:04", at)
+	if err != nil {
+		return DailySummarySchedule{}, fmt.Errorf("invalid time of day %q, expected HH:MM like \"18:00\"", at)
+	}
+
+	return DailySummarySchedule{
+		hour:     parsed.Hour(),
+		minute:   parsed.Minute(),
+		timezone: timezone,
+		enabled:  true,
+	}, nil
+}
+
+// IsEnabled returns true if the summary is sent every day
+func (s DailySummarySchedule) IsEnabled() bool {
+	return s.enabled
+}
+
+// NextAt returns the first scheduled time after now
+// Days follow the timezone, so the time of day is kept across daylight saving changes
+func (s DailySummarySchedule) NextAt(now time.Time) time.Time {
+	local := now.In(s.timezone)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, s.timezone)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, s.hour, s.minute, 0, 0, s.timezone)
+	}
+	return next
+}
+
+// String returns the time of day as "HH:MM"
+func (s DailySummarySchedule) String() string {
+	return fmt.Sprintf("%02d:%02d", s.hour, s.minute)
+}
+
+// DailySummary is the end-of-day digest of the usage of a day
+type DailySummary struct {
+	stats    Stats
+	planPace int  // today's cost as a percentage of the plan price spread over the month
+	hasPlan  bool // false without a priced plan, the pace is left out
+}
+
+// NewDailySummary creates the summary of the day stats, the plan pace is left out for plans without a price
+func NewDailySummary(stats Stats, plan Plan) DailySummary {
+	hasPlan := plan.IsValid() && plan.Price().Amount() > 0
+	return DailySummary{
+		stats:    stats,
+		planPace: plan.CalculateUsagePercentageInPeriod(stats.TotalCost(), stats.Period()),
+		hasPlan:  hasPlan,
+	}
+}
+
+// Stats returns the stats of the day
+func (s DailySummary) Stats() Stats {
+	return s.stats
+}
+
+// PlanPace returns today's cost as a percentage of the daily share of the plan price, false without a priced plan
+func (s DailySummary) PlanPace() (int, bool) {
+	return s.planPace, s.hasPlan
+}
+
+// Message renders the summary as a single line, e.g. "Today: $7.80, 412 requests, 62% of plan pace"
+func (s DailySummary) Message(costFormat CostFormat) string {
+	message := fmt.Sprintf("Today: %s, %d requests", costFormat.Format(s.stats.TotalCost()), s.stats.TotalRequests())
+	if s.hasPlan {
+		message += fmt.Sprintf(", %d%% of plan pace", s.planPace)
+	}
+	return message
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDailySummarySchedule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		at          string
+		wantEnabled bool
+		wantString  string
+		wantErr     bool
+	}{
+		{name: "empty disables", at: ""},
+		{name: "evening", at: "18:00", wantEnabled: true, wantString: "18:00"},
+		{name: "minutes", at: "23:45", wantEnabled: true, wantString: "23:45"},
+		{name: "hour out of range", at: "24:00", wantErr: true},
+		{name: "twelve hour clock", at: "6pm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseDailySummarySchedule(tt.at, time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDailySummarySchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if schedule.IsEnabled() != tt.wantEnabled {
+				t.Errorf("IsEnabled() = %v, want %v", schedule.IsEnabled(), tt.wantEnabled)
+			}
+			if tt.wantEnabled && schedule.String() != tt.wantString {
+				t.Errorf("String() = %q, want %q", schedule.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestDailySummarySchedule_NextAt(t *testing.T) {
+	t.Parallel()
+
+	taipei := time.FixedZone("UTC+8", 8*60*60)
+	schedule, err := ParseDailySummarySchedule("18:00", taipei)
+	if err != nil {
+		t.Fatalf("ParseDailySummarySchedule() returned error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "later today",
+			now:  time.Date(2025, 6, 1, 9, 30, 0, 0, taipei),
+			want: time.Date(2025, 6, 1, 18, 0, 0, 0, taipei),
+		},
+		{
+			name: "at the scheduled time moves to tomorrow",
+			now:  time.Date(2025, 6, 1, 18, 0, 0, 0, taipei),
+			want: time.Date(2025, 6, 2, 18, 0, 0, 0, taipei),
+		},
+		{
+			name: "local day differs from the UTC day",
+			now:  time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC), // 07:00 on June 2nd in UTC+8
+			want: time.Date(2025, 6, 2, 18, 0, 0, 0, taipei),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := schedule.NextAt(tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailySummary_Message(t *testing.T) {
+	t.Parallel()
+
+	// June has 30 days, so the pro plan pace is $20 / 30 per day
+	day := NewPeriod(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC))
+	requests := []APIRequest{
+		NewAPIRequest("s1", day.StartAt(), "claude-sonnet-4-20250514", NewToken(1000, 500, 0, 0), NewCost(0.3), 0),
+		NewAPIRequest("s1", day.StartAt(), "claude-sonnet-4-20250514", NewToken(1000, 500, 0, 0), NewCost(0.1), 0),
+	}
+	stats := NewStatsFromRequests(requests, day)
+
+	tests := []struct {
+		name     string
+		plan     Plan
+		expected string
+		wantPace bool
+	}{
+		{
+			name:     "priced plan includes the pace",
+			plan:     NewPlan("pro", NewCost(20)),
+			expected: "Today: $0.40, 2 requests, 60% of plan pace",
+			wantPace: true,
+		},
+		{
+			name:     "unset plan leaves out the pace",
+			plan:     NewPlan("unset", NewCost(0)),
+			expected: "Today: $0.40, 2 requests",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			summary := NewDailySummary(stats, tt.plan)
+			if got := summary.Message(DefaultCostFormat()); got != tt.expected {
+				t.Errorf("Message() = %q, want %q", got, tt.expected)
+			}
+			if _, ok := summary.PlanPace(); ok != tt.wantPace {
+				t.Errorf("PlanPace() ok = %v, want %v", ok, tt.wantPace)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// wallClock returns the time the clocks show the hour of the day in the timezone
+// An hour skipped when the clocks go forward starts when the clocks jump, time.Date would return the hour before the gap
+// An hour repeated when the clocks go back is its first occurrence
+func wallClock(year int, month time.Month, day int, hour int, timezone *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, timezone)
+
+	// Compare wall clocks in UTC, which has no gaps, to find how far time.Date moved back
+	want := time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if got.Before(want) {
+		t = t.Add(want.Sub(got))
+	}
+	return t
+}
+
+// ClockShift returns how far the clocks change during the calendar day containing t in the timezone
+// Positive when the clocks go forward and the day is shorter, negative when they go back, zero on regular days
+func ClockShift(t time.Time, timezone *time.Location) time.Duration {
+	day := NewDayPeriod(t, timezone)
+	length := day.EndAt().Add(time.Nanosecond).Sub(day.StartAt())
+	return 24*time.Hour - length
+}
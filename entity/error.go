@@ -0,0 +1,73 @@
+package entity
+
+import "errors"
+
+// ErrorKind classifies the errors users can act on, so each interface can explain them and scripts can tell them apart
+type ErrorKind string
+
+// Supported error kinds
+const (
+	ErrorKindUnknown       ErrorKind = ""               // unexpected errors, shown as they are
+	ErrorKindConnection    ErrorKind = "connection"     // the server is not running or not reachable
+	ErrorKindAuth          ErrorKind = "auth"           // the server rejected the credentials
+	ErrorKindNotFound      ErrorKind = "not_found"      // the requested record does not exist
+	ErrorKindInvalidPeriod ErrorKind = "invalid_period" // the period ends before it starts
+	ErrorKindInvalidFormat ErrorKind = "invalid_format" // the format string uses an unknown variable
+)
+
+// Error is an error users can act on, its message is shown to them instead of the cause
+type Error struct {
+	kind    ErrorKind
+	message string
+	cause   error
+}
+
+// NewError creates a new Error, cause is optional and kept for logs and errors.Is
+func NewError(kind ErrorKind, message string, cause error) *Error {
+	return &Error{
+		kind:    kind,
+		message: message,
+		cause:   cause,
+	}
+}
+
+// Kind returns what went wrong
+func (e *Error) Kind() ErrorKind {
+	return e.kind
+}
+
+// Message returns the explanation for users
+func (e *Error) Message() string {
+	return e.message
+}
+
+// Error implements error, the cause is appended for logs
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.message
+	}
+	return e.message + ": " + e.cause.Error()
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// ErrorKindOf returns the kind of the first Error in the chain, ErrorKindUnknown for other errors
+func ErrorKindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.kind
+	}
+	return ErrorKindUnknown
+}
+
+// ErrorMessage returns the explanation of the first Error in the chain, other errors are returned as they are
+func ErrorMessage(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.message
+	}
+	return err.Error()
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("rpc error: code = Unavailable desc = connection refused")
+	err := fmt.Errorf("failed to get stats via gRPC: %w", NewError(ErrorKindConnection, "cannot connect to the ccmon server", cause))
+
+	if kind := ErrorKindOf(err); kind != ErrorKindConnection {
+		t.Errorf("ErrorKindOf() = %q, want %q", kind, ErrorKindConnection)
+	}
+	if message := ErrorMessage(err); message != "cannot connect to the ccmon server" {
+		t.Errorf("ErrorMessage() = %q, want the message without the cause", message)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is() = false, want the cause in the chain")
+	}
+	if want := "failed to get stats via gRPC: cannot connect to the ccmon server: " + cause.Error(); err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	// Other errors are shown as they are
+	plain := errors.New("disk full")
+	if kind := ErrorKindOf(plain); kind != ErrorKindUnknown {
+		t.Errorf("ErrorKindOf() = %q, want unknown", kind)
+	}
+	if message := ErrorMessage(plain); message != "disk full" {
+		t.Errorf("ErrorMessage() = %q, want %q", message, "disk full")
+	}
+}
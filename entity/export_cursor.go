@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// ExportCursor marks how far previous exports got, so the next export only emits records added since
+// Records sharing the timestamp of the last exported record are tracked by ID, since several can be stored at the same time
+type ExportCursor struct {
+	lastTimestamp time.Time
+	lastIDs       map[string]struct{}
+}
+
+// NewExportCursor creates a cursor after the records with the IDs at the timestamp
+func NewExportCursor(lastTimestamp time.Time, lastIDs []string) ExportCursor {
+	ids := make(map[string]struct{}, len(lastIDs))
+	for _, id := range lastIDs {
+		ids[id] = struct{}{}
+	}
+	return ExportCursor{
+		lastTimestamp: lastTimestamp,
+		lastIDs:       ids,
+	}
+}
+
+// IsZero returns true before the first export, every record is new
+func (c ExportCursor) IsZero() bool {
+	return c.lastTimestamp.IsZero()
+}
+
+// LastTimestamp returns the timestamp of the last exported record
+func (c ExportCursor) LastTimestamp() time.Time {
+	return c.lastTimestamp
+}
+
+// LastIDs returns the IDs of the exported records at the last timestamp, sorted
+func (c ExportCursor) LastIDs() []string {
+	ids := make([]string, 0, len(c.lastIDs))
+	for id := range c.lastIDs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Period returns the period to read new records from, starting at the last timestamp to catch records sharing it
+func (c ExportCursor) Period(until time.Time) Period {
+	if c.IsZero() {
+		// All-time queries are not bounded by their end, start from the Unix epoch instead
+		return NewPeriod(time.Unix(0, 0).UTC(), until)
+	}
+	return NewPeriod(c.lastTimestamp, until)
+}
+
+// Pending returns the records not exported yet, in the given order
+func (c ExportCursor) Pending(requests []APIRequest) []APIRequest {
+	pending := make([]APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if c.isExported(req) {
+			continue
+		}
+		pending = append(pending, req)
+	}
+	return pending
+}
+
+// Advance returns the cursor after the exported records, an empty export keeps the cursor
+func (c ExportCursor) Advance(exported []APIRequest) ExportCursor {
+	next := c
+	for _, req := range exported {
+		switch {
+		case req.Timestamp().After(next.lastTimestamp):
+			next = NewExportCursor(req.Timestamp(), []string{req.ID()})
+		case req.Timestamp().Equal(next.lastTimestamp):
+			next = NewExportCursor(next.lastTimestamp, append(next.LastIDs(), req.ID()))
+		}
+	}
+	return next
+}
+
+// isExported returns true if a previous export emitted the record
+func (c ExportCursor) isExported(req APIRequest) bool {
+	if c.IsZero() {
+		return false
+	}
+	if req.Timestamp().Before(c.lastTimestamp) {
+		return true
+	}
+	if req.Timestamp().Equal(c.lastTimestamp) {
+		_, ok := c.lastIDs[req.ID()]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExportCursor_Pending(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	earlier := NewAPIRequest("session-1", baseTime.Add(-time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	exported := NewAPIRequest("session-1", baseTime, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	sameTime := NewAPIRequest("session-2", baseTime, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	later := NewAPIRequest("session-1", baseTime.Add(time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	requests := []APIRequest{earlier, exported, sameTime, later}
+
+	tests := []struct {
+		name     string
+		cursor   ExportCursor
+		expected []string
+	}{
+		{
+			name:     "first export includes every record",
+			cursor:   ExportCursor{},
+			expected: []string{earlier.ID(), exported.ID(), sameTime.ID(), later.ID()},
+		},
+		{
+			name:     "records sharing the last timestamp are only skipped once exported",
+			cursor:   NewExportCursor(baseTime, []string{exported.ID()}),
+			expected: []string{sameTime.ID(), later.ID()},
+		},
+		{
+			name:     "everything exported",
+			cursor:   NewExportCursor(later.Timestamp(), []string{later.ID()}),
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pending := tt.cursor.Pending(requests)
+			if len(pending) != len(tt.expected) {
+				t.Fatalf("Pending() returned %d records, want %d", len(pending), len(tt.expected))
+			}
+			for i, req := range pending {
+				if req.ID() != tt.expected[i] {
+					t.Errorf("Pending()[%d] = %s, want %s", i, req.ID(), tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportCursor_Advance(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	first := NewAPIRequest("session-1", baseTime, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	second := NewAPIRequest("session-2", baseTime, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+	later := NewAPIRequest("session-1", baseTime.Add(time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000)
+
+	cursor := ExportCursor{}.Advance([]APIRequest{first})
+	if !cursor.LastTimestamp().Equal(baseTime) {
+		t.Errorf("LastTimestamp() = %v, want %v", cursor.LastTimestamp(), baseTime)
+	}
+
+	// A later export with a record at the same time keeps both IDs
+	cursor = cursor.Advance([]APIRequest{second})
+	if ids := cursor.LastIDs(); len(ids) != 2 {
+		t.Errorf("LastIDs() = %v, want 2 IDs", ids)
+	}
+
+	// An empty export keeps the cursor
+	if unchanged := cursor.Advance(nil); len(unchanged.LastIDs()) != 2 {
+		t.Errorf("Advance(nil) changed the cursor to %v", unchanged.LastIDs())
+	}
+
+	cursor = cursor.Advance([]APIRequest{later})
+	if !cursor.LastTimestamp().Equal(later.Timestamp()) {
+		t.Errorf("LastTimestamp() = %v, want %v", cursor.LastTimestamp(), later.Timestamp())
+	}
+	if ids := cursor.LastIDs(); len(ids) != 1 || ids[0] != later.ID() {
+		t.Errorf("LastIDs() = %v, want [%s]", ids, later.ID())
+	}
+}
+
+func TestExportCursor_Period(t *testing.T) {
+	t.Parallel()
+
+	until := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// The first export reads everything, bounded by until
+	period := ExportCursor{}.Period(until)
+	if period.IsAllTime() {
+		t.Error("Period() of a zero cursor must not be all time, its end would be ignored")
+	}
+	if !period.EndAt().Equal(until) {
+		t.Errorf("Period().EndAt() = %v, want %v", period.EndAt(), until)
+	}
+
+	last := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	period = NewExportCursor(last, nil).Period(until)
+	if !period.StartAt().Equal(last) {
+		t.Errorf("Period().StartAt() = %v, want %v", period.StartAt(), last)
+	}
+}
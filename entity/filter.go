@@ -0,0 +1,201 @@
+package entity
+
+import "strings"
+
+// Filter selects API requests by period and optional dimensions
+// Empty dimensions match every request, so a filter with only a period selects the whole period
+type Filter struct {
+	period    Period
+	model     string
+	sessionID string
+	source    string
+	origin    string
+	user      string
+	project   string
+	search    string
+}
+
+// NewFilter creates a new Filter selecting every request of the period
+func NewFilter(period Period) Filter {
+	return Filter{
+		period: period,
+	}
+}
+
+// WithPeriod returns a copy of the filter selecting the period
+func (f Filter) WithPeriod(period Period) Filter {
+	f.period = period
+	return f
+}
+
+// WithModel returns a copy of the filter selecting requests of the model
+func (f Filter) WithModel(model string) Filter {
+	f.model = model
+	return f
+}
+
+// WithSessionID returns a copy of the filter selecting requests of the session
+func (f Filter) WithSessionID(sessionID string) Filter {
+	f.sessionID = sessionID
+	return f
+}
+
+// WithSource returns a copy of the filter selecting requests reported by the source
+func (f Filter) WithSource(source string) Filter {
+	f.source = source
+	return f
+}
+
+// WithOrigin returns a copy of the filter selecting requests of the origin, e.g. "live" to exclude imported records
+func (f Filter) WithOrigin(origin string) Filter {
+	f.origin = origin
+	return f
+}
+
+// WithUser returns a copy of the filter selecting requests of the user
+func (f Filter) WithUser(user string) Filter {
+	f.user = user
+	return f
+}
+
+// WithProject returns a copy of the filter selecting requests of the project
+func (f Filter) WithProject(project string) Filter {
+	f.project = project
+	return f
+}
+
+// WithSearch returns a copy of the filter selecting requests whose model contains the text, ignoring case, or whose session ID starts with it
+func (f Filter) WithSearch(search string) Filter {
+	f.search = strings.TrimSpace(search)
+	return f
+}
+
+// Period returns the period of the filter
+func (f Filter) Period() Period {
+	return f.period
+}
+
+// Model returns the model dimension, empty matches every model
+func (f Filter) Model() string {
+	return f.model
+}
+
+// SessionID returns the session dimension, empty matches every session
+func (f Filter) SessionID() string {
+	return f.sessionID
+}
+
+// Source returns the source dimension, empty matches every source
+func (f Filter) Source() string {
+	return f.source
+}
+
+// Origin returns the origin dimension, empty matches every origin
+func (f Filter) Origin() string {
+	return f.origin
+}
+
+// User returns the user dimension, empty matches every user
+func (f Filter) User() string {
+	return f.user
+}
+
+// Project returns the project dimension, empty matches every project
+func (f Filter) Project() string {
+	return f.project
+}
+
+// Search returns the model or session search, empty matches every request
+func (f Filter) Search() string {
+	return f.search
+}
+
+// HasDimensions returns true if the filter narrows the period by any dimension
+func (f Filter) HasDimensions() bool {
+	return f.model != "" || f.sessionID != "" || f.source != "" || f.origin != "" || f.user != "" || f.project != "" || f.search != ""
+}
+
+// Matches returns true if the request is in the period and matches every dimension
+func (f Filter) Matches(req APIRequest) bool {
+	if !f.period.Contains(req.Timestamp()) {
+		return false
+	}
+	return f.matchesDimensions(req)
+}
+
+// matchesDimensions returns true if the request matches every dimension, ignoring the period
+func (f Filter) matchesDimensions(req APIRequest) bool {
+	if f.model != "" && req.Model().String() != f.model {
+		return false
+	}
+	if f.sessionID != "" && req.SessionID() != f.sessionID {
+		return false
+	}
+	if f.source != "" && req.Source() != f.source {
+		return false
+	}
+	if f.origin != "" && req.Origin() != f.origin {
+		return false
+	}
+	if f.user != "" && req.User() != f.user {
+		return false
+	}
+	if f.project != "" && req.Project() != f.project {
+		return false
+	}
+	if f.search != "" && !f.matchesSearch(req) {
+		return false
+	}
+	return true
+}
+
+// matchesSearch returns true if the model contains the search text or the session ID starts with it
+func (f Filter) matchesSearch(req APIRequest) bool {
+	if strings.Contains(strings.ToLower(req.Model().String()), strings.ToLower(f.search)) {
+		return true
+	}
+	return req.SessionID() != "" && strings.HasPrefix(req.SessionID(), f.search)
+}
+
+// Apply returns the requests matching the dimensions, in the same order
+// Requests are expected to be read by period already, the period is not checked again
+func (f Filter) Apply(requests []APIRequest) []APIRequest {
+	if !f.HasDimensions() {
+		return requests
+	}
+
+	matched := make([]APIRequest, 0, len(requests))
+	for _, req := range requests {
+		if f.matchesDimensions(req) {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// String returns the dimensions as "key=value" pairs, empty when the filter has no dimensions
+func (f Filter) String() string {
+	var parts []string
+	if f.model != "" {
+		parts = append(parts, "model="+f.model)
+	}
+	if f.sessionID != "" {
+		parts = append(parts, "session="+f.sessionID)
+	}
+	if f.source != "" {
+		parts = append(parts, "source="+f.source)
+	}
+	if f.origin != "" {
+		parts = append(parts, "origin="+f.origin)
+	}
+	if f.user != "" {
+		parts = append(parts, "user="+f.user)
+	}
+	if f.project != "" {
+		parts = append(parts, "project="+f.project)
+	}
+	if f.search != "" {
+		parts = append(parts, "search="+f.search)
+	}
+	return strings.Join(parts, " ")
+}
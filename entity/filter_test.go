@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	req := NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000).WithSource("gemini_cli").WithUser("alice@example.com").WithProject("ccmon")
+	period := NewPeriod(timestamp.Add(-time.Hour), timestamp.Add(time.Hour))
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected bool
+	}{
+		{
+			name:     "period only",
+			filter:   NewFilter(period),
+			expected: true,
+		},
+		{
+			name:     "outside the period",
+			filter:   NewFilter(NewPeriod(timestamp.Add(time.Minute), timestamp.Add(time.Hour))),
+			expected: false,
+		},
+		{
+			name:     "all time ignores the period",
+			filter:   NewFilter(NewAllTimePeriod(timestamp.Add(-time.Hour))),
+			expected: true,
+		},
+		{
+			name:     "every dimension matches",
+			filter:   NewFilter(period).WithModel("claude-sonnet-4-20250514").WithSessionID("session-1").WithSource("gemini_cli"),
+			expected: true,
+		},
+		{
+			name:     "model mismatch",
+			filter:   NewFilter(period).WithModel("claude-3-5-haiku-20241022"),
+			expected: false,
+		},
+		{
+			name:     "session mismatch",
+			filter:   NewFilter(period).WithSessionID("session-2"),
+			expected: false,
+		},
+		{
+			name:     "source mismatch",
+			filter:   NewFilter(period).WithSource("claude_code"),
+			expected: false,
+		},
+		{
+			name:     "live origin",
+			filter:   NewFilter(period).WithOrigin(OriginLive),
+			expected: true,
+		},
+		{
+			name:     "imported origin excludes live requests",
+			filter:   NewFilter(period).WithOrigin(OriginImport),
+			expected: false,
+		},
+		{
+			name:     "user matches",
+			filter:   NewFilter(period).WithUser("alice@example.com"),
+			expected: true,
+		},
+		{
+			name:     "user mismatch",
+			filter:   NewFilter(period).WithUser("bob@example.com"),
+			expected: false,
+		},
+		{
+			name:     "project matches",
+			filter:   NewFilter(period).WithProject("ccmon"),
+			expected: true,
+		},
+		{
+			name:     "project mismatch",
+			filter:   NewFilter(period).WithProject("other"),
+			expected: false,
+		},
+		{
+			name:     "search matches a model substring ignoring case",
+			filter:   NewFilter(period).WithSearch("Sonnet"),
+			expected: true,
+		},
+		{
+			name:     "search matches a session ID prefix",
+			filter:   NewFilter(period).WithSearch("session-"),
+			expected: true,
+		},
+		{
+			name:     "search mismatch",
+			filter:   NewFilter(period).WithSearch("opus"),
+			expected: false,
+		},
+		{
+			name:     "search only matches the start of the session ID",
+			filter:   NewFilter(period).WithSearch("sion-1"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(req); got != tt.expected {
+				t.Errorf("Expected Matches to be %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	requests := []APIRequest{
+		NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+		NewAPIRequest("session-2", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+		NewAPIRequest("session-1", timestamp.Add(2*time.Minute), "claude-3-5-haiku-20241022", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+	}
+	period := NewPeriod(timestamp.Add(time.Hour), timestamp.Add(2*time.Hour))
+
+	if got := NewFilter(period).Apply(requests); len(got) != len(requests) {
+		t.Errorf("Expected a filter without dimensions to keep %d requests, got %d", len(requests), len(got))
+	}
+
+	// The period is not checked again, requests are expected to be read by period already
+	got := NewFilter(period).WithSessionID("session-1").Apply(requests)
+	if len(got) != 2 || got[0].ID() != requests[0].ID() || got[1].ID() != requests[2].ID() {
+		t.Errorf("Expected the session-1 requests in order, got %d requests", len(got))
+	}
+}
+
+func TestFilter_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		filter        Filter
+		expected      string
+		hasDimensions bool
+	}{
+		{
+			name:     "no dimensions",
+			filter:   NewFilter(Period{}),
+			expected: "",
+		},
+		{
+			name:          "model only",
+			filter:        NewFilter(Period{}).WithModel("claude-sonnet-4-20250514"),
+			expected:      "model=claude-sonnet-4-20250514",
+			hasDimensions: true,
+		},
+		{
+			name:          "every dimension",
+			filter:        NewFilter(Period{}).WithModel("claude-sonnet-4-20250514").WithSessionID("session-1").WithSource("gemini_cli").WithOrigin(OriginImport).WithUser("alice@example.com").WithProject("ccmon"),
+			expected:      "model=claude-sonnet-4-20250514 session=session-1 source=gemini_cli origin=import user=alice@example.com project=ccmon",
+			hasDimensions: true,
+		},
+		{
+			name:          "search is trimmed",
+			filter:        NewFilter(Period{}).WithSearch("  opus "),
+			expected:      "search=opus",
+			hasDimensions: true,
+		},
+		{
+			name:     "blank search",
+			filter:   NewFilter(Period{}).WithSearch("   "),
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.String(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if got := tt.filter.HasDimensions(); got != tt.hasDimensions {
+				t.Errorf("Expected HasDimensions to be %v, got %v", tt.hasDimensions, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package entity
+
+import "fmt"
+
+// StreakDays is the number of days scanned for a streak, longer streaks are reported as "30+ days"
+const StreakDays = 30
+
+// Goal represents the daily cost and token goal streaks are counted against
+// A zero cost or token goal is not checked
+type Goal struct {
+	dailyCost   Cost
+	dailyTokens int64
+}
+
+// NewGoal creates a new Goal, a zero amount disables that part of the goal
+func NewGoal(dailyCost Cost, dailyTokens int64) Goal {
+	return Goal{
+		dailyCost:   dailyCost,
+		dailyTokens: dailyTokens,
+	}
+}
+
+// DailyCost returns the daily cost goal
+func (g Goal) DailyCost() Cost {
+	return g.dailyCost
+}
+
+// DailyTokens returns the daily goal of tokens counting against limits (input + output)
+func (g Goal) DailyTokens() int64 {
+	return g.dailyTokens
+}
+
+// IsEnabled returns true if a daily cost or token goal is set
+func (g Goal) IsEnabled() bool {
+	return g.dailyCost.Amount() > 0 || g.dailyTokens > 0
+}
+
+// IsMet returns true if the day stays within every part of the goal
+func (g Goal) IsMet(dayStats Stats) bool {
+	if g.dailyCost.Amount() > 0 && dayStats.TotalCost().Amount() > g.dailyCost.Amount() {
+		return false
+	}
+	if g.dailyTokens > 0 && dayStats.TotalTokens().Limited() > g.dailyTokens {
+		return false
+	}
+	return true
+}
+
+// CalculateStreak counts the consecutive days meeting the goal from the newest first daily stats
+// Today counts while it is still within the goal, and days before the first usage are not counted
+func (g Goal) CalculateStreak(dailyStats []Stats) Streak {
+	if !g.IsEnabled() || len(dailyStats) == 0 {
+		return Streak{}
+	}
+
+	streak := Streak{today: dailyStats[0]}
+	for i, stats := range dailyStats {
+		if !g.IsMet(stats) {
+			return streak
+		}
+		if stats.TotalRequests() > 0 {
+			streak.days = i + 1
+		}
+	}
+
+	// Every scanned day met the goal, the streak may go back further
+	streak.exceedsWindow = streak.days == len(dailyStats)
+	return streak
+}
+
+// Streak represents the consecutive days meeting the goal, including today
+type Streak struct {
+	days          int
+	exceedsWindow bool
+	today         Stats
+}
+
+// Days returns the number of consecutive days meeting the goal
+func (s Streak) Days() int {
+	return s.days
+}
+
+// Today returns the stats of today the goal progress is shown for
+func (s Streak) Today() Stats {
+	return s.today
+}
+
+// String returns the streak length, e.g. "7 days" or "30+ days" when the streak goes back further than scanned
+func (s Streak) String() string {
+	switch {
+	case s.exceedsWindow:
+		return fmt.Sprintf("%d+ days", s.days)
+	case s.days == 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", s.days)
+	}
+}
@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+// dayStats returns the stats of a day with a single premium request, or no request when cost is negative
+func dayStats(cost float64, tokens int64) Stats {
+	period := NewPeriod(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC))
+	if cost < 0 {
+		return NewStatsFromRequests(nil, period)
+	}
+
+	request := NewAPIRequest("session", period.StartAt(), "claude-sonnet-4-20250514", NewToken(tokens, 0, 0, 0), NewCost(cost), 1000)
+	return NewStatsFromRequests([]APIRequest{request}, period)
+}
+
+func TestGoal_IsMet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		goal    Goal
+		stats   Stats
+		enabled bool
+		met     bool
+	}{
+		{
+			name:  "disabled goal is always met",
+			goal:  NewGoal(NewCost(0), 0),
+			stats: dayStats(100, 1000000),
+			met:   true,
+		},
+		{
+			name:    "at cost goal",
+			goal:    NewGoal(NewCost(5), 0),
+			stats:   dayStats(5, 1000000),
+			enabled: true,
+			met:     true,
+		},
+		{
+			name:    "above cost goal",
+			goal:    NewGoal(NewCost(5), 0),
+			stats:   dayStats(5.01, 100),
+			enabled: true,
+		},
+		{
+			name:    "above token goal",
+			goal:    NewGoal(NewCost(5), 1000),
+			stats:   dayStats(1, 1001),
+			enabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.goal.IsEnabled() != tt.enabled {
+				t.Errorf("IsEnabled() = %v, want %v", tt.goal.IsEnabled(), tt.enabled)
+			}
+			if got := tt.goal.IsMet(tt.stats); got != tt.met {
+				t.Errorf("IsMet() = %v, want %v", got, tt.met)
+			}
+		})
+	}
+}
+
+func TestGoal_CalculateStreak(t *testing.T) {
+	t.Parallel()
+
+	goal := NewGoal(NewCost(5), 0)
+
+	tests := []struct {
+		name       string
+		goal       Goal
+		dailyStats []Stats // newest first
+		days       int
+		expected   string
+	}{
+		{
+			name:       "disabled goal has no streak",
+			goal:       NewGoal(NewCost(0), 0),
+			dailyStats: []Stats{dayStats(1, 0), dayStats(1, 0)},
+			days:       0,
+			expected:   "0 days",
+		},
+		{
+			name:       "counts until a day over the goal",
+			goal:       goal,
+			dailyStats: []Stats{dayStats(1, 0), dayStats(4, 0), dayStats(6, 0), dayStats(1, 0)},
+			days:       2,
+			expected:   "2 days",
+		},
+		{
+			name:       "today over the goal resets the streak",
+			goal:       goal,
+			dailyStats: []Stats{dayStats(6, 0), dayStats(1, 0)},
+			days:       0,
+			expected:   "0 days",
+		},
+		{
+			name:       "idle days within the streak count",
+			goal:       goal,
+			dailyStats: []Stats{dayStats(-1, 0), dayStats(1, 0), dayStats(-1, 0), dayStats(2, 0), dayStats(7, 0)},
+			days:       4,
+			expected:   "4 days",
+		},
+		{
+			name:       "days before the first usage are not counted",
+			goal:       goal,
+			dailyStats: []Stats{dayStats(1, 0), dayStats(-1, 0), dayStats(-1, 0)},
+			days:       1,
+			expected:   "1 day",
+		},
+		{
+			name:       "streak longer than the scanned days",
+			goal:       goal,
+			dailyStats: []Stats{dayStats(1, 0), dayStats(1, 0), dayStats(1, 0)},
+			days:       3,
+			expected:   "3+ days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			streak := tt.goal.CalculateStreak(tt.dailyStats)
+			if streak.Days() != tt.days {
+				t.Errorf("Days() = %d, want %d", streak.Days(), tt.days)
+			}
+			if streak.String() != tt.expected {
+				t.Errorf("String() = %q, want %q", streak.String(), tt.expected)
+			}
+		})
+	}
+}
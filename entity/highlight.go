@@ -0,0 +1,45 @@
+package entity
+
+// Highlight represents the thresholds above which a single API request is highlighted
+type Highlight struct {
+	cost   Cost
+	tokens int64
+}
+
+// NewHighlight creates a new Highlight, a zero threshold disables that threshold
+func NewHighlight(cost Cost, tokens int64) Highlight {
+	return Highlight{
+		cost:   cost,
+		tokens: tokens,
+	}
+}
+
+// Cost returns the cost threshold
+func (h Highlight) Cost() Cost {
+	return h.cost
+}
+
+// Tokens returns the total tokens threshold
+func (h Highlight) Tokens() int64 {
+	return h.tokens
+}
+
+// IsEnabled returns true if any threshold is set
+func (h Highlight) IsEnabled() bool {
+	return h.cost.Amount() > 0 || h.tokens > 0
+}
+
+// IsCostExceeded returns true if the request cost is above the cost threshold
+func (h Highlight) IsCostExceeded(req APIRequest) bool {
+	return h.cost.Amount() > 0 && req.Cost().Amount() > h.cost.Amount()
+}
+
+// IsTokensExceeded returns true if the request total tokens are above the tokens threshold
+func (h Highlight) IsTokensExceeded(req APIRequest) bool {
+	return h.tokens > 0 && req.Tokens().Total() > h.tokens
+}
+
+// Matches returns true if the request exceeds any threshold
+func (h Highlight) Matches(req APIRequest) bool {
+	return h.IsCostExceeded(req) || h.IsTokensExceeded(req)
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHighlight_Matches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		costThreshold  float64
+		tokenThreshold int64
+		cost           float64
+		tokens         int64
+		enabled        bool
+		costExceeded   bool
+		tokensExceeded bool
+	}{
+		{
+			name:   "disabled highlight never matches",
+			cost:   10,
+			tokens: 1000000,
+		},
+		{
+			name:          "cost above threshold",
+			costThreshold: 0.50,
+			cost:          0.75,
+			tokens:        1000,
+			enabled:       true,
+			costExceeded:  true,
+		},
+		{
+			name:          "cost at threshold is not highlighted",
+			costThreshold: 0.50,
+			cost:          0.50,
+			tokens:        1000,
+			enabled:       true,
+		},
+		{
+			name:           "tokens above threshold",
+			tokenThreshold: 100000,
+			cost:           0.01,
+			tokens:         150000,
+			enabled:        true,
+			tokensExceeded: true,
+		},
+		{
+			name:           "both thresholds exceeded",
+			costThreshold:  0.50,
+			tokenThreshold: 100000,
+			cost:           1.20,
+			tokens:         150000,
+			enabled:        true,
+			costExceeded:   true,
+			tokensExceeded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			highlight := NewHighlight(NewCost(tt.costThreshold), tt.tokenThreshold)
+			req := NewAPIRequest("session", time.Now(), "claude-sonnet-4-20250514", NewToken(tt.tokens, 0, 0, 0), NewCost(tt.cost), 100)
+
+			if got := highlight.IsEnabled(); got != tt.enabled {
+				t.Errorf("IsEnabled() = %v, want %v", got, tt.enabled)
+			}
+			if got := highlight.IsCostExceeded(req); got != tt.costExceeded {
+				t.Errorf("IsCostExceeded() = %v, want %v", got, tt.costExceeded)
+			}
+			if got := highlight.IsTokensExceeded(req); got != tt.tokensExceeded {
+				t.Errorf("IsTokensExceeded() = %v, want %v", got, tt.tokensExceeded)
+			}
+			if got := highlight.Matches(req); got != (tt.costExceeded || tt.tokensExceeded) {
+				t.Errorf("Matches() = %v, want %v", got, tt.costExceeded || tt.tokensExceeded)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IgnoreRules represents ingestion filters for API requests that should never be stored
+type IgnoreRules struct {
+	modelPatterns   []*regexp.Regexp
+	sessionPrefixes []string
+}
+
+// NewIgnoreRules creates ignore rules from model regular expressions and session ID prefixes
+func NewIgnoreRules(modelPatterns []string, sessionPrefixes []string) (IgnoreRules, error) {
+	rules := IgnoreRules{}
+
+	for _, pattern := range modelPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return IgnoreRules{}, fmt.Errorf("invalid model pattern %q: %w", pattern, err)
+		}
+		rules.modelPatterns = append(rules.modelPatterns, re)
+	}
+
+	for _, prefix := range sessionPrefixes {
+		if prefix != "" {
+			rules.sessionPrefixes = append(rules.sessionPrefixes, prefix)
+		}
+	}
+
+	return rules, nil
+}
+
+// IsEmpty returns true if no ignore rules are configured
+func (r IgnoreRules) IsEmpty() bool {
+	return len(r.modelPatterns) == 0 && len(r.sessionPrefixes) == 0
+}
+
+// Matches returns true if the API request should be ignored
+func (r IgnoreRules) Matches(req APIRequest) bool {
+	for _, re := range r.modelPatterns {
+		if re.MatchString(req.Model().String()) {
+			return true
+		}
+	}
+
+	for _, prefix := range r.sessionPrefixes {
+		if strings.HasPrefix(req.SessionID(), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,113 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewIgnoreRules(t *testing.T) {
+	tests := []struct {
+		name            string
+		modelPatterns   []string
+		sessionPrefixes []string
+		wantErr         bool
+		wantEmpty       bool
+	}{
+		{
+			name:      "no rules",
+			wantEmpty: true,
+		},
+		{
+			name:            "empty session prefix is skipped",
+			sessionPrefixes: []string{""},
+			wantEmpty:       true,
+		},
+		{
+			name:          "valid model pattern",
+			modelPatterns: []string{"haiku"},
+			wantEmpty:     false,
+		},
+		{
+			name:          "invalid model pattern",
+			modelPatterns: []string{"("},
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := NewIgnoreRules(tt.modelPatterns, tt.sessionPrefixes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewIgnoreRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rules.IsEmpty() != tt.wantEmpty {
+				t.Errorf("IsEmpty() = %v, want %v", rules.IsEmpty(), tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestIgnoreRules_Matches(t *testing.T) {
+	rules, err := NewIgnoreRules([]string{"haiku", "^claude-3-opus"}, []string{"test-", "ci-"})
+	if err != nil {
+		t.Fatalf("NewIgnoreRules() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		model     string
+		expected  bool
+	}{
+		{
+			name:      "model matches substring pattern",
+			sessionID: "session-1",
+			model:     "claude-3-5-haiku-20241022",
+			expected:  true,
+		},
+		{
+			name:      "model matches anchored pattern",
+			sessionID: "session-1",
+			model:     "claude-3-opus-20240229",
+			expected:  true,
+		},
+		{
+			name:      "session matches prefix",
+			sessionID: "test-123",
+			model:     "claude-sonnet-4-20250514",
+			expected:  true,
+		},
+		{
+			name:      "session contains prefix but does not start with it",
+			sessionID: "my-test-123",
+			model:     "claude-sonnet-4-20250514",
+			expected:  false,
+		},
+		{
+			name:      "no rule matches",
+			sessionID: "session-1",
+			model:     "claude-sonnet-4-20250514",
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewAPIRequest(tt.sessionID, time.Now(), tt.model, NewToken(10, 10, 0, 0), NewCost(0.01), 100)
+			if got := rules.Matches(req); got != tt.expected {
+				t.Errorf("Matches() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIgnoreRules_EmptyMatchesNothing(t *testing.T) {
+	rules := IgnoreRules{}
+	req := NewAPIRequest("test-1", time.Now(), "claude-3-5-haiku-20241022", NewToken(10, 10, 0, 0), NewCost(0.01), 100)
+	if rules.Matches(req) {
+		t.Error("empty IgnoreRules should not match any request")
+	}
+}
@@ -0,0 +1,160 @@
+package entity
+
+import "time"
+
+// IngestOutcome is what happened to an API request event received by the server
+type IngestOutcome string
+
+// Supported ingest outcomes
+const (
+	IngestAccepted   IngestOutcome = "accepted"   // stored
+	IngestIgnored    IngestOutcome = "ignored"    // matched an ignore rule
+	IngestDropped    IngestOutcome = "dropped"    // dropped by a processor or for clock skew
+	IngestMalformed  IngestOutcome = "malformed"  // rejected as the event misses required data
+	IngestDuplicated IngestOutcome = "duplicated" // repeated within an export or re-sent within the dedupe window
+	IngestFailed     IngestOutcome = "failed"     // the database write failed
+)
+
+// IngestCounts counts the API request events received by the server by their outcome
+type IngestCounts struct {
+	accepted   int64
+	ignored    int64
+	dropped    int64
+	malformed  int64
+	duplicated int64
+	failed     int64
+}
+
+// NewIngestCounts creates a new IngestCounts from aggregated values
+func NewIngestCounts(accepted, ignored, dropped, malformed, duplicated, failed int64) IngestCounts {
+	return IngestCounts{
+		accepted:   accepted,
+		ignored:    ignored,
+		dropped:    dropped,
+		malformed:  malformed,
+		duplicated: duplicated,
+		failed:     failed,
+	}
+}
+
+// Record returns a copy of the counts with n events of the outcome added
+func (c IngestCounts) Record(outcome IngestOutcome, n int64) IngestCounts {
+	switch outcome {
+	case IngestAccepted:
+		c.accepted += n
+	case IngestIgnored:
+		c.ignored += n
+	case IngestDropped:
+		c.dropped += n
+	case IngestMalformed:
+		c.malformed += n
+	case IngestDuplicated:
+		c.duplicated += n
+	case IngestFailed:
+		c.failed += n
+	}
+	return c
+}
+
+// Add returns the sum of both counts
+func (c IngestCounts) Add(other IngestCounts) IngestCounts {
+	return IngestCounts{
+		accepted:   c.accepted + other.accepted,
+		ignored:    c.ignored + other.ignored,
+		dropped:    c.dropped + other.dropped,
+		malformed:  c.malformed + other.malformed,
+		duplicated: c.duplicated + other.duplicated,
+		failed:     c.failed + other.failed,
+	}
+}
+
+// Count returns the number of events with the outcome
+func (c IngestCounts) Count(outcome IngestOutcome) int64 {
+	switch outcome {
+	case IngestAccepted:
+		return c.accepted
+	case IngestIgnored:
+		return c.ignored
+	case IngestDropped:
+		return c.dropped
+	case IngestMalformed:
+		return c.malformed
+	case IngestDuplicated:
+		return c.duplicated
+	case IngestFailed:
+		return c.failed
+	}
+	return 0
+}
+
+// Accepted returns the number of stored events
+func (c IngestCounts) Accepted() int64 {
+	return c.accepted
+}
+
+// Ignored returns the number of events matching an ignore rule
+func (c IngestCounts) Ignored() int64 {
+	return c.ignored
+}
+
+// Dropped returns the number of events dropped by a processor or for clock skew
+func (c IngestCounts) Dropped() int64 {
+	return c.dropped
+}
+
+// Malformed returns the number of events rejected for missing required data
+func (c IngestCounts) Malformed() int64 {
+	return c.malformed
+}
+
+// Duplicated returns the number of events repeated within an export or re-sent within the dedupe window
+func (c IngestCounts) Duplicated() int64 {
+	return c.duplicated
+}
+
+// Failed returns the number of events the database write failed for
+func (c IngestCounts) Failed() int64 {
+	return c.failed
+}
+
+// Total returns the number of received events
+func (c IngestCounts) Total() int64 {
+	return c.accepted + c.ignored + c.dropped + c.malformed + c.duplicated + c.failed
+}
+
+// Missing returns the number of received events which were not stored
+func (c IngestCounts) Missing() int64 {
+	return c.Total() - c.accepted
+}
+
+// IngestStats represents the outcomes of the API request events received over the last hour and day
+// Counts are kept in memory, so the windows are shorter right after the server started
+type IngestStats struct {
+	lastHour IngestCounts
+	lastDay  IngestCounts
+	since    time.Time
+}
+
+// NewIngestStats creates a new IngestStats, since is when the server started counting
+func NewIngestStats(lastHour, lastDay IngestCounts, since time.Time) IngestStats {
+	return IngestStats{
+		lastHour: lastHour,
+		lastDay:  lastDay,
+		since:    since,
+	}
+}
+
+// LastHour returns the outcomes of the last hour
+func (s IngestStats) LastHour() IngestCounts {
+	return s.lastHour
+}
+
+// LastDay returns the outcomes of the last 24 hours
+func (s IngestStats) LastDay() IngestCounts {
+	return s.lastDay
+}
+
+// Since returns when the server started counting, zero when the server does not report it
+func (s IngestStats) Since() time.Time {
+	return s.since
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "testing"
+
+func TestIngestCounts_Record(t *testing.T) {
+	counts := IngestCounts{}.
+		Record(IngestAccepted, 3).
+		Record(IngestIgnored, 1).
+		Record(IngestMalformed, 2).
+		Record(IngestAccepted, 1)
+
+	if counts.Accepted() != 4 {
+		t.Errorf("Expected 4 accepted, got %d", counts.Accepted())
+	}
+	if counts.Count(IngestMalformed) != 2 {
+		t.Errorf("Expected 2 malformed, got %d", counts.Count(IngestMalformed))
+	}
+	if counts.Total() != 7 {
+		t.Errorf("Expected 7 in total, got %d", counts.Total())
+	}
+	if counts.Missing() != 3 {
+		t.Errorf("Expected 3 missing, got %d", counts.Missing())
+	}
+}
+
+func TestIngestCounts_Add(t *testing.T) {
+	sum := NewIngestCounts(1, 2, 3, 4, 5, 6).Add(NewIngestCounts(6, 5, 4, 3, 2, 1))
+
+	for _, outcome := range []IngestOutcome{IngestAccepted, IngestIgnored, IngestDropped, IngestMalformed, IngestDuplicated, IngestFailed} {
+		if sum.Count(outcome) != 7 {
+			t.Errorf("Expected 7 %s, got %d", outcome, sum.Count(outcome))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// IngestionLag represents the delay between an event timestamp and when the server received it
+// Large lag usually indicates exporter buffering rather than missing usage
+type IngestionLag struct {
+	samples int64
+	total   time.Duration
+	max     time.Duration
+}
+
+// NewIngestionLag creates a new IngestionLag from aggregated values
+func NewIngestionLag(samples int64, total time.Duration, max time.Duration) IngestionLag {
+	return IngestionLag{
+		samples: samples,
+		total:   total,
+		max:     max,
+	}
+}
+
+// Record returns a copy of the lag with the given sample added
+// Negative lag (exporter clock ahead of the server) is counted as zero
+func (l IngestionLag) Record(lag time.Duration) IngestionLag {
+	if lag < 0 {
+		lag = 0
+	}
+
+	l.samples++
+	l.total += lag
+	if lag > l.max {
+		l.max = lag
+	}
+	return l
+}
+
+// Samples returns the number of recorded samples
+func (l IngestionLag) Samples() int64 {
+	return l.samples
+}
+
+// Total returns the sum of all recorded lag
+func (l IngestionLag) Total() time.Duration {
+	return l.total
+}
+
+// Average returns the average lag, or zero when nothing is recorded
+func (l IngestionLag) Average() time.Duration {
+	if l.samples == 0 {
+		return 0
+	}
+	return l.total / time.Duration(l.samples)
+}
+
+// Max returns the largest recorded lag
+func (l IngestionLag) Max() time.Duration {
+	return l.max
+}
+
+// IsEmpty returns true if no samples are recorded
+func (l IngestionLag) IsEmpty() bool {
+	return l.samples == 0
+}
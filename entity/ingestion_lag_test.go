@@ -0,0 +1,78 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestIngestionLag_Record(t *testing.T) {
+	tests := []struct {
+		name            string
+		samples         []time.Duration
+		expectedSamples int64
+		expectedAverage time.Duration
+		expectedMax     time.Duration
+	}{
+		{
+			name:            "no samples",
+			samples:         nil,
+			expectedSamples: 0,
+			expectedAverage: 0,
+			expectedMax:     0,
+		},
+		{
+			name:            "single sample",
+			samples:         []time.Duration{2 * time.Second},
+			expectedSamples: 1,
+			expectedAverage: 2 * time.Second,
+			expectedMax:     2 * time.Second,
+		},
+		{
+			name:            "multiple samples",
+			samples:         []time.Duration{time.Second, 5 * time.Second, 3 * time.Second},
+			expectedSamples: 3,
+			expectedAverage: 3 * time.Second,
+			expectedMax:     5 * time.Second,
+		},
+		{
+			name:            "negative lag counts as zero",
+			samples:         []time.Duration{-10 * time.Second, 4 * time.Second},
+			expectedSamples: 2,
+			expectedAverage: 2 * time.Second,
+			expectedMax:     4 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lag := entity.IngestionLag{}
+			for _, sample := range tt.samples {
+				lag = lag.Record(sample)
+			}
+
+			if lag.Samples() != tt.expectedSamples {
+				t.Errorf("Samples() = %d, want %d", lag.Samples(), tt.expectedSamples)
+			}
+			if lag.Average() != tt.expectedAverage {
+				t.Errorf("Average() = %v, want %v", lag.Average(), tt.expectedAverage)
+			}
+			if lag.Max() != tt.expectedMax {
+				t.Errorf("Max() = %v, want %v", lag.Max(), tt.expectedMax)
+			}
+			if lag.IsEmpty() != (tt.expectedSamples == 0) {
+				t.Errorf("IsEmpty() = %v, want %v", lag.IsEmpty(), tt.expectedSamples == 0)
+			}
+		})
+	}
+}
+
+func TestIngestionLag_RecordDoesNotMutate(t *testing.T) {
+	original := entity.NewIngestionLag(1, time.Second, time.Second)
+	_ = original.Record(10 * time.Second)
+
+	if original.Samples() != 1 || original.Max() != time.Second {
+		t.Errorf("Record() mutated the original lag: samples=%d max=%v", original.Samples(), original.Max())
+	}
+}
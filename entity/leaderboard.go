@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+)
+
+// LeaderboardEntry is the usage of a single user ranked against the other users
+type LeaderboardEntry struct {
+	rank  int
+	user  string
+	stats Stats
+}
+
+// Rank returns the position of the user, 1 for the highest cost
+func (e LeaderboardEntry) Rank() int {
+	return e.rank
+}
+
+// User returns the user email or account UUID reported by telemetry
+func (e LeaderboardEntry) User() string {
+	return e.user
+}
+
+// Stats returns the usage of the user in the leaderboard period
+func (e LeaderboardEntry) Stats() Stats {
+	return e.stats
+}
+
+// Leaderboard ranks the users by their cost in a period
+type Leaderboard struct {
+	period  Period
+	entries []LeaderboardEntry
+}
+
+// NewLeaderboard groups the requests by user and ranks the users by cost, then tokens, highest first
+// Requests without user attribution are left out, they can't be told apart by user
+func NewLeaderboard(requests []APIRequest, period Period) Leaderboard {
+	byUser := groupByUser(requests)
+	delete(byUser, "")
+
+	entries := make([]LeaderboardEntry, 0, len(byUser))
+	for user, userRequests := range byUser {
+		entries = append(entries, LeaderboardEntry{user: user, stats: NewStatsFromRequests(userRequests, period)})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i].stats, entries[j].stats
+		if a.TotalCost().Amount() != b.TotalCost().Amount() {
+			return a.TotalCost().Amount() > b.TotalCost().Amount()
+		}
+		if a.TotalTokens().Total() != b.TotalTokens().Total() {
+			return a.TotalTokens().Total() > b.TotalTokens().Total()
+		}
+		return entries[i].user < entries[j].user
+	})
+	for i := range entries {
+		entries[i].rank = i + 1
+	}
+
+	return Leaderboard{period: period, entries: entries}
+}
+
+// Period returns the period the users are ranked in
+func (l Leaderboard) Period() Period {
+	return l.period
+}
+
+// Entries returns the ranked users, highest cost first
+func (l Leaderboard) Entries() []LeaderboardEntry {
+	return l.entries
+}
+
+// Find returns the entry of the user, ignoring case
+func (l Leaderboard) Find(user string) (LeaderboardEntry, bool) {
+	if user == "" {
+		return LeaderboardEntry{}, false
+	}
+	for _, entry := range l.entries {
+		if strings.EqualFold(entry.user, user) {
+			return entry, true
+		}
+	}
+	return LeaderboardEntry{}, false
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriodFromDuration(timestamp.Add(time.Hour), 7*24*time.Hour)
+
+	requests := []APIRequest{
+		NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(3), 1000).WithUser("alice@example.com"),
+		NewAPIRequest("session-1", timestamp.Add(-time.Hour), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(4), 1000).WithUser("alice@example.com"),
+		NewAPIRequest("session-2", timestamp, "claude-opus-4-20250514", NewToken(100, 50, 0, 0), NewCost(9), 1000).WithUser("bob@example.com"),
+		NewAPIRequest("session-3", timestamp, "claude-sonnet-4-20250514", NewToken(500, 50, 0, 0), NewCost(1), 1000).WithUser("carol@example.com"),
+		NewAPIRequest("session-4", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(1), 1000).WithUser("dave@example.com"),
+		NewAPIRequest("session-5", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(50), 1000),
+	}
+
+	leaderboard := NewLeaderboard(requests, period)
+
+	// Ties on cost are ranked by tokens, requests without a user are left out
+	expected := []struct {
+		user     string
+		requests int
+		cost     float64
+	}{
+		{user: "bob@example.com", requests: 1, cost: 9},
+		{user: "alice@example.com", requests: 2, cost: 7},
+		{user: "carol@example.com", requests: 1, cost: 1},
+		{user: "dave@example.com", requests: 1, cost: 1},
+	}
+
+	entries := leaderboard.Entries()
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.Rank() != i+1 || entry.User() != want.user {
+			t.Errorf("Entry %d: expected #%d %s, got #%d %s", i, i+1, want.user, entry.Rank(), entry.User())
+		}
+		if entry.Stats().TotalRequests() != want.requests || entry.Stats().TotalCost().Amount() != want.cost {
+			t.Errorf("Entry %d: expected %d requests costing %v, got %d costing %v", i, want.requests, want.cost, entry.Stats().TotalRequests(), entry.Stats().TotalCost().Amount())
+		}
+	}
+
+	if entry, ok := leaderboard.Find("ALICE@example.com"); !ok || entry.Rank() != 2 {
+		t.Errorf("Expected to find alice ranked 2nd ignoring case, got %v ranked %d", ok, entry.Rank())
+	}
+	if _, ok := leaderboard.Find(""); ok {
+		t.Error("Expected no entry for requests without a user")
+	}
+	if _, ok := leaderboard.Find("eve@example.com"); ok {
+		t.Error("Expected no entry for a user without requests")
+	}
+}
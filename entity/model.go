@@ -21,6 +21,29 @@ func (m Model) IsBase() bool {
 	return strings.Contains(strings.ToLower(string(m)), "haiku")
 }
 
+// IsLongContext returns true if this is a 1M context beta model (e.g. "claude-sonnet-4[1m]")
+// These models are priced differently and are tracked as a separate tier
+func (m Model) IsLongContext() bool {
+	return strings.Contains(strings.ToLower(string(m)), "[1m]")
+}
+
+// Family returns the model family, e.g. "opus" or "sonnet", with a "[1m]" suffix for 1M context models
+// Models of other families are returned by their full name
+func (m Model) Family() string {
+	name := strings.ToLower(string(m))
+	family := string(m)
+	for _, known := range []string{"opus", "sonnet", "haiku"} {
+		if strings.Contains(name, known) {
+			family = known
+			break
+		}
+	}
+	if m.IsLongContext() && family != string(m) {
+		family += "[1m]"
+	}
+	return family
+}
+
 // String returns the string representation of the model
 func (m Model) String() string {
 	return string(m)
@@ -0,0 +1,76 @@
+package entity
+
+import "sort"
+
+// ModelStats is the usage of a single model in a period, e.g. to tell the cost of Opus apart from Sonnet
+type ModelStats struct {
+	model    string
+	requests int
+	tokens   Token
+	cost     Cost
+}
+
+// NewModelStats creates the usage of the model
+func NewModelStats(model string, requests int, tokens Token, cost Cost) ModelStats {
+	return ModelStats{
+		model:    model,
+		requests: requests,
+		tokens:   tokens,
+		cost:     cost,
+	}
+}
+
+// NewModelStatsFromRequests sums the usage of each model, the most expensive first
+func NewModelStatsFromRequests(requests []APIRequest) []ModelStats {
+	index := make(map[Model]int)
+	var models []ModelStats
+	for _, req := range requests {
+		i, ok := index[req.Model()]
+		if !ok {
+			i = len(models)
+			index[req.Model()] = i
+			models = append(models, ModelStats{model: string(req.Model())})
+		}
+		models[i].requests++
+		models[i].tokens = models[i].tokens.Add(req.Tokens())
+		models[i].cost = models[i].cost.Add(req.Cost())
+	}
+
+	sortModelStats(models)
+	return models
+}
+
+// Model returns the model name as reported by telemetry, e.g. "claude-opus-4-20250514"
+func (m ModelStats) Model() string {
+	return m.model
+}
+
+// Requests returns the number of requests of the model
+func (m ModelStats) Requests() int {
+	return m.requests
+}
+
+// Tokens returns the tokens of the model
+func (m ModelStats) Tokens() Token {
+	return m.tokens
+}
+
+// Cost returns the cost of the model
+func (m ModelStats) Cost() Cost {
+	return m.cost
+}
+
+// scale returns the usage multiplied by the factor, for estimates from a sample
+func (m ModelStats) scale(factor float64) ModelStats {
+	return NewModelStats(m.model, scaleRequests(m.requests, factor), m.tokens.Scale(factor), m.cost.Scale(factor))
+}
+
+// sortModelStats orders the models by cost, the most expensive first, and by name when the cost is equal
+func sortModelStats(models []ModelStats) {
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].cost.Amount() != models[j].cost.Amount() {
+			return models[i].cost.Amount() > models[j].cost.Amount()
+		}
+		return models[i].model < models[j].model
+	})
+}
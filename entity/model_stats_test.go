@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewModelStatsFromRequests(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	requests := []APIRequest{
+		NewAPIRequest("session", at, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+		NewAPIRequest("session", at, "claude-opus-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.05), 1000),
+		NewAPIRequest("session", at, "claude-sonnet-4-20250514", NewToken(200, 100, 10, 0), NewCost(0.02), 1000),
+		NewAPIRequest("session", at, "claude-3-5-haiku-20241022", NewToken(100, 50, 0, 0), NewCost(0.001), 1000),
+		NewAPIRequest("session", at, "claude-3-haiku-20240307", NewToken(100, 50, 0, 0), NewCost(0.001), 1000),
+	}
+
+	models := NewModelStatsFromRequests(requests)
+
+	// The most expensive first, equal costs by name
+	expected := []struct {
+		model    string
+		requests int
+		tokens   int64
+		cost     float64
+	}{
+		{"claude-opus-4-20250514", 1, 150, 0.05},
+		{"claude-sonnet-4-20250514", 2, 460, 0.03},
+		{"claude-3-5-haiku-20241022", 1, 150, 0.001},
+		{"claude-3-haiku-20240307", 1, 150, 0.001},
+	}
+	if len(models) != len(expected) {
+		t.Fatalf("Expected %d models, got %d", len(expected), len(models))
+	}
+	for i, want := range expected {
+		got := models[i]
+		if got.Model() != want.model || got.Requests() != want.requests || got.Tokens().Total() != want.tokens {
+			t.Errorf("Model %d = %s with %d requests and %d tokens, want %s with %d requests and %d tokens",
+				i, got.Model(), got.Requests(), got.Tokens().Total(), want.model, want.requests, want.tokens)
+		}
+		if diff := got.Cost().Amount() - want.cost; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("Model %s cost = %f, want %f", got.Model(), got.Cost().Amount(), want.cost)
+		}
+	}
+
+	if len(NewModelStatsFromRequests(nil)) != 0 {
+		t.Error("Expected no models without requests")
+	}
+}
+
+func TestStats_WithModels(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC))
+	models := []ModelStats{
+		NewModelStats("claude-3-5-haiku-20241022", 1, NewToken(6000, 0, 0, 0), NewCost(0.01)),
+		NewModelStats("claude-opus-4-20250514", 1, NewToken(6000, 0, 0, 0), NewCost(0.5)),
+	}
+
+	stats := NewStats(0, 0, Token{}, Token{}, Cost{}, Cost{}, period).WithModels(models)
+
+	// Sorted on a copy, the given models keep their order
+	if got := stats.Models(); len(got) != 2 || got[0].Model() != "claude-opus-4-20250514" {
+		t.Errorf("Expected the most expensive model first, got %v", got)
+	}
+	if models[0].Model() != "claude-3-5-haiku-20241022" {
+		t.Error("Expected the given models to be left unchanged")
+	}
+
+	// Base models don't count against the limits
+	if rate := stats.ModelTokenBurnRate(stats.Models()[0]); rate != 100 {
+		t.Errorf("Expected an opus burn rate of 100 tokens per minute, got %f", rate)
+	}
+	if rate := stats.ModelTokenBurnRate(stats.Models()[1]); rate != 0 {
+		t.Errorf("Expected no haiku burn rate, got %f", rate)
+	}
+}
+
+func TestEstimateStatsFromSample_Models(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(at, at.Add(time.Hour))
+	sample := []APIRequest{
+		NewAPIRequest("session", at, "claude-opus-4-20250514", NewToken(200, 100, 0, 0), NewCost(0.05), 1000),
+		NewAPIRequest("session", at, "claude-sonnet-4-20250514", NewToken(200, 100, 0, 0), NewCost(0.01), 1000),
+	}
+
+	exact := EstimateStatsFromSample(sample, 2, period)
+	if len(exact.Models()) != 2 || exact.Models()[0].Requests() != 1 {
+		t.Errorf("Expected the models of a complete sample unscaled, got %v", exact.Models())
+	}
+
+	estimated := EstimateStatsFromSample(sample, 20, period)
+	models := estimated.Models()
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+	if models[0].Model() != "claude-opus-4-20250514" || models[0].Requests() != 10 || models[0].Tokens().Total() != 3000 {
+		t.Errorf("Expected opus scaled to 10 requests and 3000 tokens, got %s with %d requests and %d tokens",
+			models[0].Model(), models[0].Requests(), models[0].Tokens().Total())
+	}
+	if diff := models[0].Cost().Amount() - 0.5; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected opus cost scaled to 0.5, got %f", models[0].Cost().Amount())
+	}
+}
@@ -121,6 +121,50 @@ func TestModel_IsBase(t *testing.T) {
 	}
 }
 
+func TestModel_IsLongContext(t *testing.T) {
+	testCases := []struct {
+		name     string
+		model    string
+		expected bool
+	}{
+		{
+			name:     "sonnet_1m_suffix",
+			model:    "claude-sonnet-4-20250514[1m]",
+			expected: true,
+		},
+		{
+			name:     "uppercase_suffix",
+			model:    "claude-sonnet-4-20250514[1M]",
+			expected: true,
+		},
+		{
+			name:     "sonnet_model",
+			model:    "claude-sonnet-4-20250514",
+			expected: false,
+		},
+		{
+			name:     "haiku_model",
+			model:    "claude-3-5-haiku-20241022",
+			expected: false,
+		},
+		{
+			name:     "without_brackets",
+			model:    "claude-sonnet-4-1m",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := NewModel(tc.model)
+			result := model.IsLongContext()
+			if result != tc.expected {
+				t.Errorf("Expected IsLongContext() to return %v for model %q, got %v", tc.expected, tc.model, result)
+			}
+		})
+	}
+}
+
 func TestModel_String(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -0,0 +1,46 @@
+package entity
+
+import "sort"
+
+// ModelUsage is the share of a model family in the rate limited tokens of a period, e.g. the current block
+type ModelUsage struct {
+	family string
+	tokens int64
+}
+
+// NewModelUsages sums the rate limited tokens of each model family, the largest share first
+// Base models don't count against the limits and are left out
+func NewModelUsages(requests []APIRequest) []ModelUsage {
+	tokens := make(map[string]int64)
+	for _, req := range requests {
+		if req.Model().IsBase() {
+			continue
+		}
+		tokens[req.Model().Family()] += req.Tokens().Limited()
+	}
+
+	usages := make([]ModelUsage, 0, len(tokens))
+	for family, limited := range tokens {
+		if limited == 0 {
+			continue
+		}
+		usages = append(usages, ModelUsage{family: family, tokens: limited})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].tokens != usages[j].tokens {
+			return usages[i].tokens > usages[j].tokens
+		}
+		return usages[i].family < usages[j].family
+	})
+	return usages
+}
+
+// Family returns the model family, e.g. "opus" or "sonnet"
+func (u ModelUsage) Family() string {
+	return u.family
+}
+
+// Tokens returns the rate limited tokens of the model family
+func (u ModelUsage) Tokens() int64 {
+	return u.tokens
+}
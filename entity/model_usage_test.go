@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModel_Family(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		model    string
+		expected string
+	}{
+		{model: "claude-opus-4-1-20250805", expected: "opus"},
+		{model: "claude-sonnet-4-20250514", expected: "sonnet"},
+		{model: "claude-sonnet-4[1m]", expected: "sonnet[1m]"},
+		{model: "claude-3-5-haiku-20241022", expected: "haiku"},
+		{model: "gpt-5", expected: "gpt-5"},
+	}
+
+	for _, tt := range tests {
+		if got := NewModel(tt.model).Family(); got != tt.expected {
+			t.Errorf("Family(%q) = %s, want %s", tt.model, got, tt.expected)
+		}
+	}
+}
+
+func TestNewModelUsages(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	request := func(model string, input, output int64) APIRequest {
+		return NewAPIRequest("session", now, model, NewToken(input, output, 1000, 1000), NewCost(0.01), 1000)
+	}
+
+	usages := NewModelUsages([]APIRequest{
+		request("claude-sonnet-4-20250514", 100, 50),
+		request("claude-opus-4-1-20250805", 300, 100),
+		request("claude-sonnet-4-20250514", 50, 50),
+		request("claude-3-5-haiku-20241022", 5000, 5000), // Not rate limited
+	})
+
+	if len(usages) != 2 {
+		t.Fatalf("Expected 2 model families, got %d", len(usages))
+	}
+	if usages[0].Family() != "opus" || usages[0].Tokens() != 400 {
+		t.Errorf("Expected opus with 400 tokens first, got %s with %d", usages[0].Family(), usages[0].Tokens())
+	}
+	if usages[1].Family() != "sonnet" || usages[1].Tokens() != 250 {
+		t.Errorf("Expected sonnet with 250 tokens second, got %s with %d", usages[1].Family(), usages[1].Tokens())
+	}
+}
@@ -0,0 +1,82 @@
+package entity
+
+// Moving average windows in days
+const (
+	ShortMovingAverageDays = 7
+	LongMovingAverageDays  = 30
+)
+
+// MovingAverage represents the trailing 7-day and 30-day average daily cost ending on a day
+type MovingAverage struct {
+	short    Cost
+	long     Cost
+	baseline Cost // average of the 7 days before, see Baseline
+}
+
+// NewMovingAverage creates a new MovingAverage from the short and long window averages
+func NewMovingAverage(short, long Cost) MovingAverage {
+	return MovingAverage{
+		short: short,
+		long:  long,
+	}
+}
+
+// Short returns the average daily cost over the last 7 days
+func (m MovingAverage) Short() Cost {
+	return m.short
+}
+
+// Long returns the average daily cost over the last 30 days
+func (m MovingAverage) Long() Cost {
+	return m.long
+}
+
+// WithBaseline returns a copy of the moving average with the baseline
+func (m MovingAverage) WithBaseline(baseline Cost) MovingAverage {
+	m.baseline = baseline
+	return m
+}
+
+// Baseline returns the average daily cost of the 7 days before, zero without any day before
+// The day itself is left out, so a spike doesn't raise the baseline it is compared with
+func (m MovingAverage) Baseline() Cost {
+	return m.baseline
+}
+
+// CalculateMovingAverages returns the moving averages of the last n daily costs, in chronological order
+// Earlier costs are only history filling the windows, days without history average the available costs
+func CalculateMovingAverages(dailyCosts []Cost, n int) []MovingAverage {
+	if n > len(dailyCosts) {
+		n = len(dailyCosts)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	averages := make([]MovingAverage, 0, n)
+	for i := len(dailyCosts) - n; i < len(dailyCosts); i++ {
+		average := NewMovingAverage(
+			trailingAverage(dailyCosts, i, ShortMovingAverageDays),
+			trailingAverage(dailyCosts, i, LongMovingAverageDays),
+		)
+		if i > 0 {
+			average = average.WithBaseline(trailingAverage(dailyCosts, i-1, ShortMovingAverageDays))
+		}
+		averages = append(averages, average)
+	}
+	return averages
+}
+
+// trailingAverage returns the average of the window costs ending at index end
+func trailingAverage(costs []Cost, end int, window int) Cost {
+	start := end - window + 1
+	if start < 0 {
+		start = 0
+	}
+
+	total := NewCost(0)
+	for _, cost := range costs[start : end+1] {
+		total = total.Add(cost)
+	}
+	return NewCost(total.Amount() / float64(end-start+1))
+}
@@ -0,0 +1,106 @@
+package entity_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestCalculateMovingAverages(t *testing.T) {
+	costs := func(amounts ...float64) []entity.Cost {
+		result := make([]entity.Cost, len(amounts))
+		for i, amount := range amounts {
+			result[i] = entity.NewCost(amount)
+		}
+		return result
+	}
+
+	// 30 history days at $1 followed by a spike and a quiet week
+	steady := make([]float64, 30)
+	for i := range steady {
+		steady[i] = 1
+	}
+	spiky := costs(append(steady, 15, 0, 0, 0, 0, 0, 0)...)
+
+	tests := []struct {
+		name          string
+		dailyCosts    []entity.Cost
+		n             int
+		expectedShort []float64
+		expectedLong  []float64
+	}{
+		{
+			name:          "flat series",
+			dailyCosts:    costs(2, 2, 2, 2, 2, 2, 2, 2),
+			n:             2,
+			expectedShort: []float64{2, 2},
+			expectedLong:  []float64{2, 2},
+		},
+		{
+			name:          "without history averages the available days",
+			dailyCosts:    costs(3, 1, 2),
+			n:             3,
+			expectedShort: []float64{3, 2, 2},
+			expectedLong:  []float64{3, 2, 2},
+		},
+		{
+			name:          "spike is smoothed over the windows",
+			dailyCosts:    spiky,
+			n:             7,
+			expectedShort: []float64{21.0 / 7, 20.0 / 7, 19.0 / 7, 18.0 / 7, 17.0 / 7, 16.0 / 7, 15.0 / 7},
+			expectedLong:  []float64{44.0 / 30, 43.0 / 30, 42.0 / 30, 41.0 / 30, 40.0 / 30, 39.0 / 30, 38.0 / 30},
+		},
+		{
+			name:          "n larger than the series",
+			dailyCosts:    costs(4),
+			n:             5,
+			expectedShort: []float64{4},
+			expectedLong:  []float64{4},
+		},
+		{
+			name:       "empty series",
+			dailyCosts: nil,
+			n:          7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			averages := entity.CalculateMovingAverages(tt.dailyCosts, tt.n)
+			if len(averages) != len(tt.expectedShort) {
+				t.Fatalf("Expected %d averages, got %d", len(tt.expectedShort), len(averages))
+			}
+
+			for i, average := range averages {
+				if math.Abs(average.Short().Amount()-tt.expectedShort[i]) > 1e-9 {
+					t.Errorf("Day %d: expected 7-day average %.4f, got %.4f", i, tt.expectedShort[i], average.Short().Amount())
+				}
+				if math.Abs(average.Long().Amount()-tt.expectedLong[i]) > 1e-9 {
+					t.Errorf("Day %d: expected 30-day average %.4f, got %.4f", i, tt.expectedLong[i], average.Long().Amount())
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateMovingAverages_Baseline(t *testing.T) {
+	dailyCosts := []entity.Cost{}
+	for _, amount := range []float64{1, 1, 1, 1, 1, 1, 1, 9} {
+		dailyCosts = append(dailyCosts, entity.NewCost(amount))
+	}
+
+	averages := entity.CalculateMovingAverages(dailyCosts, len(dailyCosts))
+
+	// The first day has no day before it
+	if baseline := averages[0].Baseline().Amount(); baseline != 0 {
+		t.Errorf("Expected no baseline for the first day, got %.2f", baseline)
+	}
+	// The spike is compared with the week before, its own cost is left out
+	if baseline := averages[7].Baseline().Amount(); math.Abs(baseline-1) > 1e-9 {
+		t.Errorf("Expected the spike baseline to be 1.00, got %.4f", baseline)
+	}
+	if short := averages[7].Short().Amount(); math.Abs(short-15.0/7) > 1e-9 {
+		t.Errorf("Expected the 7-day average to include the spike, got %.4f", short)
+	}
+}
@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Period represents a time range value object
 type Period struct {
@@ -26,6 +29,44 @@ func NewAllTimePeriod(now time.Time) Period {
 	return NewPeriod(time.Time{}, now)
 }
 
+// NewDayPeriod creates a Period of the calendar day containing t in the timezone
+// Days are 23 or 25 hours long when the clocks change for daylight saving time
+func NewDayPeriod(t time.Time, timezone *time.Location) Period {
+	local := t.In(timezone)
+	dayStart := wallClock(local.Year(), local.Month(), local.Day(), 0, timezone)
+	nextDayStart := wallClock(local.Year(), local.Month(), local.Day()+1, 0, timezone)
+
+	return NewPeriod(dayStart.UTC(), nextDayStart.Add(-time.Nanosecond).UTC())
+}
+
+// NewWeekPeriod creates a Period of the calendar week containing t in the timezone, weeks start on Monday
+func NewWeekPeriod(t time.Time, timezone *time.Location) Period {
+	local := t.In(timezone)
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	weekStart := wallClock(local.Year(), local.Month(), local.Day()-daysSinceMonday, 0, timezone)
+	nextWeekStart := wallClock(local.Year(), local.Month(), local.Day()-daysSinceMonday+7, 0, timezone)
+
+	return NewPeriod(weekStart.UTC(), nextWeekStart.Add(-time.Nanosecond).UTC())
+}
+
+// NewMonthPeriod creates a Period of the calendar month containing t in the timezone
+func NewMonthPeriod(t time.Time, timezone *time.Location) Period {
+	local := t.In(timezone)
+	monthStart := wallClock(local.Year(), local.Month(), 1, 0, timezone)
+	nextMonthStart := wallClock(local.Year(), local.Month()+1, 1, 0, timezone)
+
+	return NewPeriod(monthStart.UTC(), nextMonthStart.Add(-time.Nanosecond).UTC())
+}
+
+// NewHourPeriod creates a Period of the hour on the clock of the timezone containing t
+// Hours follow the offset of the timezone, e.g. 9:30 to 10:30 UTC in a +05:30 timezone
+func NewHourPeriod(t time.Time, timezone *time.Location) Period {
+	local := t.In(timezone)
+	hourStart := local.Add(-time.Duration(local.Minute())*time.Minute - time.Duration(local.Second())*time.Second - time.Duration(local.Nanosecond()))
+
+	return NewPeriod(hourStart.UTC(), hourStart.Add(time.Hour-time.Nanosecond).UTC())
+}
+
 // StartAt returns the start time of the period
 func (p Period) StartAt() time.Time {
 	return p.startAt
@@ -40,3 +81,46 @@ func (p Period) EndAt() time.Time {
 func (p Period) IsAllTime() bool {
 	return p.startAt.IsZero()
 }
+
+// Contains returns true if at is within the period, the end included, an all-time period contains any time
+func (p Period) Contains(at time.Time) bool {
+	return p.IsAllTime() || (!at.Before(p.startAt) && !at.After(p.endAt))
+}
+
+// Validate returns an invalid period error when the period ends before it starts
+func (p Period) Validate() error {
+	if p.IsAllTime() || !p.endAt.Before(p.startAt) {
+		return nil
+	}
+	return NewError(ErrorKindInvalidPeriod, fmt.Sprintf("invalid period: it ends at %s before it starts at %s",
+		p.endAt.UTC().Format(time.RFC3339), p.startAt.UTC().Format(time.RFC3339)), nil)
+}
+
+// Until returns the period as it was at the given time, excluding anything after it
+// The period is unchanged when at is not before its end
+func (p Period) Until(at time.Time) Period {
+	if !at.Before(p.endAt) {
+		return p
+	}
+
+	// All-time queries are not bounded by their end, start from the Unix epoch instead
+	if p.IsAllTime() {
+		return NewPeriod(time.Unix(0, 0).UTC(), at)
+	}
+	return NewPeriod(p.startAt, at)
+}
+
+// ParsePointInTime parses a "YYYY-MM-DD" date or RFC3339 timestamp for time-travel queries
+// A date refers to the end of that day in the given timezone so the whole day is included
+func ParsePointInTime(value string, timezone *time.Location) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", value, timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected YYYY-MM-DD or RFC3339", value)
+	}
+
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
@@ -0,0 +1,378 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriod_Until(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		at          time.Time
+		expectedEnd time.Time
+	}{
+		{
+			name:        "at inside period truncates the end",
+			at:          time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC),
+			expectedEnd: time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "at after period keeps the end",
+			at:          time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC),
+			expectedEnd: end,
+		},
+		{
+			name:        "at equal to end keeps the end",
+			at:          end,
+			expectedEnd: end,
+		},
+	}
+
+	t.Run("all time period becomes bounded", func(t *testing.T) {
+		t.Parallel()
+
+		at := time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC)
+		period := NewAllTimePeriod(end).Until(at)
+		if period.IsAllTime() {
+			t.Error("Expected a bounded period")
+		}
+		if !period.StartAt().Equal(time.Unix(0, 0)) {
+			t.Errorf("StartAt() = %v, want Unix epoch", period.StartAt())
+		}
+		if !period.EndAt().Equal(at) {
+			t.Errorf("EndAt() = %v, want %v", period.EndAt(), at)
+		}
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := NewPeriod(start, end).Until(tt.at)
+			if !period.StartAt().Equal(start) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), start)
+			}
+			if !period.EndAt().Equal(tt.expectedEnd) {
+				t.Errorf("EndAt() = %v, want %v", period.EndAt(), tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestPeriod_Validate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := NewPeriod(start, start.Add(time.Hour)).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err := NewPeriod(start, start).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for an empty period", err)
+	}
+	if err := NewAllTimePeriod(start).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for all time", err)
+	}
+
+	err := NewPeriod(start, start.Add(-time.Hour)).Validate()
+	if ErrorKindOf(err) != ErrorKindInvalidPeriod {
+		t.Errorf("Validate() error = %v, want an invalid period error", err)
+	}
+}
+
+func TestPeriod_Contains(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := NewPeriod(start, start.Add(24*time.Hour-time.Nanosecond))
+
+	tests := []struct {
+		name     string
+		period   Period
+		at       time.Time
+		expected bool
+	}{
+		{"start", day, start, true},
+		{"within", day, start.Add(12 * time.Hour), true},
+		{"end", day, day.EndAt(), true},
+		{"before", day, start.Add(-time.Nanosecond), false},
+		{"next day", day, start.Add(24 * time.Hour), false},
+		{"all time", NewAllTimePeriod(start), start.Add(48 * time.Hour), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.period.Contains(tt.at); got != tt.expected {
+				t.Errorf("Contains(%v) = %v, want %v", tt.at, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePointInTime(t *testing.T) {
+	t.Parallel()
+
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		timezone *time.Location
+		expected time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "date refers to the end of the day",
+			value:    "2025-06-01",
+			timezone: time.UTC,
+			expected: time.Date(2025, 6, 1, 23, 59, 59, 999999999, time.UTC),
+		},
+		{
+			name:     "date uses the given timezone",
+			value:    "2025-06-01",
+			timezone: taipei,
+			expected: time.Date(2025, 6, 1, 23, 59, 59, 999999999, taipei),
+		},
+		{
+			name:     "RFC3339 timestamp is exact",
+			value:    "2025-06-01T15:30:00Z",
+			timezone: taipei,
+			expected: time.Date(2025, 6, 1, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "invalid value",
+			value:    "June 1st",
+			timezone: time.UTC,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParsePointInTime(tt.value, tt.timezone)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParsePointInTime() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewDayPeriod(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		timezone      *time.Location
+		expectedStart time.Time
+		expectedHours time.Duration
+		expectedShift time.Duration
+	}{
+		{
+			name:          "regular day",
+			at:            time.Date(2025, 3, 8, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 3, 8, 5, 0, 0, 0, time.UTC),
+			expectedHours: 24 * time.Hour,
+		},
+		{
+			name:          "spring forward",
+			at:            time.Date(2025, 3, 9, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 3, 9, 5, 0, 0, 0, time.UTC),
+			expectedHours: 23 * time.Hour,
+			expectedShift: time.Hour,
+		},
+		{
+			name:          "day after spring forward",
+			at:            time.Date(2025, 3, 10, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC),
+			expectedHours: 24 * time.Hour,
+		},
+		{
+			name:          "fall back",
+			at:            time.Date(2025, 11, 2, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 11, 2, 4, 0, 0, 0, time.UTC),
+			expectedHours: 25 * time.Hour,
+			expectedShift: -time.Hour,
+		},
+		{
+			name:          "skipped midnight starts when the clocks jump",
+			at:            time.Date(2025, 9, 7, 12, 0, 0, 0, santiago),
+			timezone:      santiago,
+			expectedStart: time.Date(2025, 9, 7, 4, 0, 0, 0, time.UTC),
+			expectedHours: 23 * time.Hour,
+			expectedShift: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := NewDayPeriod(tt.at, tt.timezone)
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if length := period.EndAt().Add(time.Nanosecond).Sub(period.StartAt()); length != tt.expectedHours {
+				t.Errorf("day length = %v, want %v", length, tt.expectedHours)
+			}
+			if shift := ClockShift(tt.at, tt.timezone); shift != tt.expectedShift {
+				t.Errorf("ClockShift() = %v, want %v", shift, tt.expectedShift)
+			}
+		})
+	}
+}
+
+func TestNewWeekPeriod(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		timezone      *time.Location
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "midweek",
+			at:            time.Date(2025, 6, 4, 15, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			expectedStart: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "sunday belongs to the week before",
+			at:            time.Date(2025, 6, 8, 23, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			expectedStart: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "monday starts the week",
+			at:            time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+			timezone:      time.UTC,
+			expectedStart: time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "week across the clock change",
+			at:            time.Date(2025, 3, 12, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 17, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "week starting before the clock change",
+			at:            time.Date(2025, 3, 5, 12, 0, 0, 0, newYork),
+			timezone:      newYork,
+			expectedStart: time.Date(2025, 3, 3, 5, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 3, 10, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := NewWeekPeriod(tt.at, tt.timezone)
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if expected := tt.expectedEnd.Add(-time.Nanosecond); !period.EndAt().Equal(expected) {
+				t.Errorf("EndAt() = %v, want %v", period.EndAt(), expected)
+			}
+		})
+	}
+}
+
+func TestNewMonthPeriod(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	period := NewMonthPeriod(time.Date(2025, 3, 15, 12, 0, 0, 0, newYork), newYork)
+
+	// March starts in standard time and ends in daylight saving time
+	if expected := time.Date(2025, 3, 1, 5, 0, 0, 0, time.UTC); !period.StartAt().Equal(expected) {
+		t.Errorf("StartAt() = %v, want %v", period.StartAt(), expected)
+	}
+	if expected := time.Date(2025, 4, 1, 4, 0, 0, 0, time.UTC).Add(-time.Nanosecond); !period.EndAt().Equal(expected) {
+		t.Errorf("EndAt() = %v, want %v", period.EndAt(), expected)
+	}
+}
+
+func TestNewHourPeriod(t *testing.T) {
+	t.Parallel()
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		at            time.Time
+		timezone      *time.Location
+		expectedStart time.Time
+	}{
+		{
+			name:          "utc",
+			at:            time.Date(2025, 3, 8, 12, 34, 56, 789, time.UTC),
+			timezone:      time.UTC,
+			expectedStart: time.Date(2025, 3, 8, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "half hour offset",
+			at:            time.Date(2025, 3, 8, 12, 10, 0, 0, time.UTC),
+			timezone:      kolkata,
+			expectedStart: time.Date(2025, 3, 8, 11, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period := NewHourPeriod(tt.at, tt.timezone)
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("StartAt() = %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if expected := tt.expectedStart.Add(time.Hour - time.Nanosecond); !period.EndAt().Equal(expected) {
+				t.Errorf("EndAt() = %v, want %v", period.EndAt(), expected)
+			}
+		})
+	}
+}
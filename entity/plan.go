@@ -1,6 +1,9 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Plan struct {
 	name  string
@@ -59,3 +62,30 @@ func (p Plan) CalculateUsagePercentageInPeriod(actualCost Cost, period Period) i
 	percentage := (actualCost.Amount() / periodBudget) * 100
 	return int(percentage)
 }
+
+// UserPlans assigns a plan to each member of a team server, members without an own plan use the default plan
+type UserPlans struct {
+	defaultPlan string
+	users       map[string]string
+}
+
+// NewUserPlans creates the plan assignments of plan names, users are matched case-insensitively
+func NewUserPlans(defaultPlan string, users map[string]string) UserPlans {
+	normalized := make(map[string]string, len(users))
+	for user, plan := range users {
+		normalized[strings.ToLower(user)] = plan
+	}
+
+	return UserPlans{
+		defaultPlan: defaultPlan,
+		users:       normalized,
+	}
+}
+
+// PlanFor returns the plan name of the user, the default plan when the user has no own plan
+func (p UserPlans) PlanFor(user string) string {
+	if plan, ok := p.users[strings.ToLower(user)]; ok && user != "" {
+		return plan
+	}
+	return p.defaultPlan
+}
@@ -200,3 +200,25 @@ func TestPlanCalculateUsagePercentageInPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestUserPlans_PlanFor(t *testing.T) {
+	t.Parallel()
+
+	plans := NewUserPlans("pro", map[string]string{"Alice@Example.com": "max"})
+
+	tests := []struct {
+		user     string
+		expected string
+	}{
+		{user: "alice@example.com", expected: "max"},
+		{user: "ALICE@EXAMPLE.COM", expected: "max"},
+		{user: "bob@example.com", expected: "pro"},
+		{user: "", expected: "pro"},
+	}
+
+	for _, tt := range tests {
+		if got := plans.PlanFor(tt.user); got != tt.expected {
+			t.Errorf("PlanFor(%q) = %s, want %s", tt.user, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// DefaultPluginTimeout is the default time to wait for a plugin to reply to a request
+const DefaultPluginTimeout = 5 * time.Second
+
+// Plugin is a subprocess the receiver passes each API request to, e.g. to relabel or forward it
+type Plugin struct {
+	name    string
+	command []string
+	timeout time.Duration
+}
+
+// NewPlugin creates a new Plugin running the command, the first element is the program
+func NewPlugin(name string, command []string, timeout time.Duration) Plugin {
+	return Plugin{
+		name:    name,
+		command: command,
+		timeout: timeout,
+	}
+}
+
+// Name returns the name identifying the plugin in the server log
+func (p Plugin) Name() string {
+	return p.name
+}
+
+// Command returns the program and its arguments
+func (p Plugin) Command() []string {
+	return p.command
+}
+
+// Timeout returns the time to wait for each reply
+func (p Plugin) Timeout() time.Duration {
+	return p.timeout
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+)
+
+// LongContextThreshold is the prompt size above which 1M context models are billed at their long context price
+const LongContextThreshold = 200_000
+
+// tokensPerMillion converts prices per million tokens to per token prices
+const tokensPerMillion = 1_000_000
+
+// ModelPrice is the price of a model in USD per million tokens of each kind
+type ModelPrice struct {
+	input         float64
+	output        float64
+	cacheRead     float64
+	cacheCreation float64
+	longContext   *ModelPrice // price of prompts above LongContextThreshold, nil when the model has none
+}
+
+// NewModelPrice creates a new ModelPrice from prices in USD per million tokens
+func NewModelPrice(input, output, cacheRead, cacheCreation float64) ModelPrice {
+	return ModelPrice{
+		input:         input,
+		output:        output,
+		cacheRead:     cacheRead,
+		cacheCreation: cacheCreation,
+	}
+}
+
+// WithLongContext returns a copy of the price billing prompts above LongContextThreshold at the long context price
+func (p ModelPrice) WithLongContext(longContext ModelPrice) ModelPrice {
+	longContext.longContext = nil
+	p.longContext = &longContext
+	return p
+}
+
+// Cost returns the cost of the tokens, long context applies the long context price to prompts above the threshold
+func (p ModelPrice) Cost(tokens Token, longContext bool) Cost {
+	price := p
+	prompt := tokens.Input() + tokens.CacheRead() + tokens.CacheCreation()
+	if longContext && p.longContext != nil && prompt > LongContextThreshold {
+		price = *p.longContext
+	}
+
+	amount := float64(tokens.Input())*price.input +
+		float64(tokens.Output())*price.output +
+		float64(tokens.CacheRead())*price.cacheRead +
+		float64(tokens.CacheCreation())*price.cacheCreation
+	return NewCost(amount / tokensPerMillion)
+}
+
+// PriceTable maps model names to their prices
+type PriceTable struct {
+	prices map[string]ModelPrice
+	names  []string // longest first, so the most specific name matches first
+}
+
+// NewPriceTable creates a new PriceTable, a model is priced by the longest name it contains (e.g. "claude-sonnet-4")
+// Names are matched case-insensitively, so provider prefixes and release dates need no entries
+func NewPriceTable(prices map[string]ModelPrice) PriceTable {
+	table := PriceTable{prices: make(map[string]ModelPrice, len(prices))}
+	for name, price := range prices {
+		name = strings.ToLower(name)
+		table.prices[name] = price
+		table.names = append(table.names, name)
+	}
+	sort.Slice(table.names, func(i, j int) bool {
+		if len(table.names[i]) != len(table.names[j]) {
+			return len(table.names[i]) > len(table.names[j])
+		}
+		return table.names[i] < table.names[j]
+	})
+	return table
+}
+
+// Find returns the price of the model, false when the table has no price for it
+func (t PriceTable) Find(model Model) (ModelPrice, bool) {
+	name := strings.ToLower(string(model))
+	for _, known := range t.names {
+		if strings.Contains(name, known) {
+			return t.prices[known], true
+		}
+	}
+	return ModelPrice{}, false
+}
+
+// CostOf returns the cost of the API request from its model and tokens, false when the model has no price
+func (t PriceTable) CostOf(req APIRequest) (Cost, bool) {
+	price, ok := t.Find(req.Model())
+	if !ok {
+		return Cost{}, false
+	}
+	return price.Cost(req.Tokens(), req.Model().IsLongContext()), true
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceTable_CostOf(t *testing.T) {
+	t.Parallel()
+
+	table := NewPriceTable(map[string]ModelPrice{
+		"claude-opus-4":   NewModelPrice(15, 75, 1.5, 18.75),
+		"claude-opus-4-5": NewModelPrice(5, 25, 0.5, 6.25),
+		"claude-sonnet-4": NewModelPrice(3, 15, 0.3, 3.75).WithLongContext(NewModelPrice(6, 22.5, 0.6, 7.5)),
+	})
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		model    string
+		tokens   Token
+		expected float64
+		priced   bool
+	}{
+		{name: "dated model", model: "claude-opus-4-1-20250805", tokens: NewToken(1_000_000, 100_000, 0, 0), expected: 22.5, priced: true},
+		{name: "most specific name", model: "claude-opus-4-5-20251101", tokens: NewToken(1_000_000, 100_000, 0, 0), expected: 7.5, priced: true},
+		{name: "cache tokens", model: "claude-sonnet-4-20250514", tokens: NewToken(0, 0, 1_000_000, 100_000), expected: 0.675, priced: true},
+		{name: "provider prefix", model: "us.anthropic.claude-sonnet-4-20250514-v1:0", tokens: NewToken(1_000_000, 0, 0, 0), expected: 3, priced: true},
+		{name: "long context above threshold", model: "claude-sonnet-4[1m]", tokens: NewToken(300_000, 10_000, 0, 0), expected: 2.025, priced: true},
+		{name: "long context below threshold", model: "claude-sonnet-4[1m]", tokens: NewToken(100_000, 10_000, 0, 0), expected: 0.45, priced: true},
+		{name: "unknown model", model: "gpt-5", tokens: NewToken(1000, 1000, 0, 0), priced: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewAPIRequest("session", now, tt.model, tt.tokens, NewCost(0), 1000)
+			cost, ok := table.CostOf(req)
+			if ok != tt.priced {
+				t.Fatalf("CostOf(%s) priced = %v, want %v", tt.model, ok, tt.priced)
+			}
+			if math.Abs(cost.Amount()-tt.expected) > 1e-9 {
+				t.Errorf("CostOf(%s) = %f, want %f", tt.model, cost.Amount(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package entity
+
+// DefaultQuotaWarning is prefixed to quick query output once the hard daily limit is exceeded
+const DefaultQuotaWarning = "⚠ QUOTA EXCEEDED"
+
+// Quota represents a hard spending limit used to stop automated agent loops
+type Quota struct {
+	hardDaily Cost
+	warning   string
+}
+
+// NewQuota creates a new Quota, a zero hard daily limit disables the quota
+func NewQuota(hardDaily Cost, warning string) Quota {
+	if warning == "" {
+		warning = DefaultQuotaWarning
+	}
+
+	return Quota{
+		hardDaily: hardDaily,
+		warning:   warning,
+	}
+}
+
+// HardDaily returns the daily cost limit
+func (q Quota) HardDaily() Cost {
+	return q.hardDaily
+}
+
+// Warning returns the text prefixed to output once the limit is exceeded
+func (q Quota) Warning() string {
+	return q.warning
+}
+
+// IsEnabled returns true if a hard daily limit is set
+func (q Quota) IsEnabled() bool {
+	return q.hardDaily.Amount() > 0
+}
+
+// IsExceeded returns true if the daily cost is above the hard daily limit
+func (q Quota) IsExceeded(dailyCost Cost) bool {
+	return q.IsEnabled() && dailyCost.Amount() > q.hardDaily.Amount()
+}
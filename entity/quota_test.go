@@ -0,0 +1,65 @@
+package entity
+
+import "testing"
+
+func TestQuota_IsExceeded(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		hardDaily float64
+		dailyCost float64
+		enabled   bool
+		exceeded  bool
+	}{
+		{
+			name:      "disabled quota is never exceeded",
+			hardDaily: 0,
+			dailyCost: 100,
+		},
+		{
+			name:      "below limit",
+			hardDaily: 25,
+			dailyCost: 24.99,
+			enabled:   true,
+		},
+		{
+			name:      "at limit is not exceeded",
+			hardDaily: 25,
+			dailyCost: 25,
+			enabled:   true,
+		},
+		{
+			name:      "above limit",
+			hardDaily: 25,
+			dailyCost: 25.01,
+			enabled:   true,
+			exceeded:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quota := NewQuota(NewCost(tt.hardDaily), "")
+			if quota.IsEnabled() != tt.enabled {
+				t.Errorf("IsEnabled() = %v, want %v", quota.IsEnabled(), tt.enabled)
+			}
+			if got := quota.IsExceeded(NewCost(tt.dailyCost)); got != tt.exceeded {
+				t.Errorf("IsExceeded(%v) = %v, want %v", tt.dailyCost, got, tt.exceeded)
+			}
+		})
+	}
+}
+
+func TestNewQuota_DefaultWarning(t *testing.T) {
+	t.Parallel()
+
+	if got := NewQuota(NewCost(10), "").Warning(); got != DefaultQuotaWarning {
+		t.Errorf("Warning() = %q, want %q", got, DefaultQuotaWarning)
+	}
+	if got := NewQuota(NewCost(10), "STOP").Warning(); got != "STOP" {
+		t.Errorf("Warning() = %q, want %q", got, "STOP")
+	}
+}
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Retention represents the effective data retention policy of the server and when it is applied next
+type Retention struct {
+	duration      time.Duration
+	nextCleanupAt time.Time
+}
+
+// NewRetention creates a new Retention, a zero duration means records are kept forever
+func NewRetention(duration time.Duration, nextCleanupAt time.Time) Retention {
+	return Retention{
+		duration:      duration,
+		nextCleanupAt: nextCleanupAt,
+	}
+}
+
+// Duration returns how long records are kept
+func (r Retention) Duration() time.Duration {
+	return r.duration
+}
+
+// NextCleanupAt returns when the next cleanup runs, zero when not scheduled
+func (r Retention) NextCleanupAt() time.Time {
+	return r.nextCleanupAt
+}
+
+// IsEnabled returns true if records are purged after the retention duration
+func (r Retention) IsEnabled() bool {
+	return r.duration > 0
+}
+
+// IsScheduled returns true if the next cleanup time is known
+func (r Retention) IsScheduled() bool {
+	return r.IsEnabled() && !r.nextCleanupAt.IsZero()
+}
+
+// NextCutoff returns the time before which records are removed by the next cleanup
+func (r Retention) NextCutoff() time.Time {
+	if !r.IsScheduled() {
+		return time.Time{}
+	}
+	return r.nextCleanupAt.Add(-r.duration)
+}
+
+// TimeUntilCleanup returns the time left until the next cleanup, zero when overdue or not scheduled
+func (r Retention) TimeUntilCleanup(now time.Time) time.Duration {
+	if !r.IsScheduled() || !r.nextCleanupAt.After(now) {
+		return 0
+	}
+	return r.nextCleanupAt.Sub(now)
+}
@@ -0,0 +1,72 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestRetention_NextCutoff(t *testing.T) {
+	now := time.Date(2025, 7, 31, 9, 0, 0, 0, time.UTC)
+	nextCleanupAt := now.Add(3 * time.Hour)
+
+	tests := []struct {
+		name              string
+		retention         entity.Retention
+		expectedEnabled   bool
+		expectedScheduled bool
+		expectedCutoff    time.Time
+		expectedUntil     time.Duration
+	}{
+		{
+			name:              "disabled retention",
+			retention:         entity.NewRetention(0, nextCleanupAt),
+			expectedEnabled:   false,
+			expectedScheduled: false,
+			expectedCutoff:    time.Time{},
+			expectedUntil:     0,
+		},
+		{
+			name:              "enabled but not scheduled yet",
+			retention:         entity.NewRetention(30*24*time.Hour, time.Time{}),
+			expectedEnabled:   true,
+			expectedScheduled: false,
+			expectedCutoff:    time.Time{},
+			expectedUntil:     0,
+		},
+		{
+			name:              "scheduled cleanup",
+			retention:         entity.NewRetention(30*24*time.Hour, nextCleanupAt),
+			expectedEnabled:   true,
+			expectedScheduled: true,
+			expectedCutoff:    time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC),
+			expectedUntil:     3 * time.Hour,
+		},
+		{
+			name:              "overdue cleanup",
+			retention:         entity.NewRetention(24*time.Hour, now.Add(-time.Minute)),
+			expectedEnabled:   true,
+			expectedScheduled: true,
+			expectedCutoff:    now.Add(-time.Minute - 24*time.Hour),
+			expectedUntil:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.retention.IsEnabled(); got != tt.expectedEnabled {
+				t.Errorf("Expected enabled %v, got %v", tt.expectedEnabled, got)
+			}
+			if got := tt.retention.IsScheduled(); got != tt.expectedScheduled {
+				t.Errorf("Expected scheduled %v, got %v", tt.expectedScheduled, got)
+			}
+			if got := tt.retention.NextCutoff(); !got.Equal(tt.expectedCutoff) {
+				t.Errorf("Expected cutoff %v, got %v", tt.expectedCutoff, got)
+			}
+			if got := tt.retention.TimeUntilCleanup(now); got != tt.expectedUntil {
+				t.Errorf("Expected %v until cleanup, got %v", tt.expectedUntil, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// minSessionBurnDuration avoids inflated burn rates for sessions with a very short active timeline
+const minSessionBurnDuration = time.Minute
+
+// Session represents the aggregated usage of a single Claude Code session
+type Session struct {
+	id                string
+	requests          int
+	tokens            Token
+	rateLimitedTokens Token
+	cost              Cost
+	firstSeen         time.Time
+	lastSeen          time.Time
+	title             SessionTitle
+}
+
+// ID returns the session ID
+func (s Session) ID() string {
+	return s.id
+}
+
+// Title returns the title read from the session transcript, zero when unknown
+func (s Session) Title() SessionTitle {
+	return s.title
+}
+
+// WithTitle returns a copy of the session with the title
+func (s Session) WithTitle(title SessionTitle) Session {
+	s.title = title
+	return s
+}
+
+// Name returns the title label of the session, or its ID when the title is unknown
+func (s Session) Name() string {
+	if label := s.title.Label(); label != "" {
+		return label
+	}
+	return s.id
+}
+
+// Requests returns the number of requests in the session
+func (s Session) Requests() int {
+	return s.requests
+}
+
+// Tokens returns the token usage across all models in the session
+func (s Session) Tokens() Token {
+	return s.tokens
+}
+
+// RateLimitedTokens returns the tokens from models that count against Claude's rate limits
+func (s Session) RateLimitedTokens() Token {
+	return s.rateLimitedTokens
+}
+
+// Cost returns the total cost of the session
+func (s Session) Cost() Cost {
+	return s.cost
+}
+
+// FirstSeen returns the timestamp of the first request in the session
+func (s Session) FirstSeen() time.Time {
+	return s.firstSeen
+}
+
+// LastSeen returns the timestamp of the latest request in the session
+func (s Session) LastSeen() time.Time {
+	return s.lastSeen
+}
+
+// ActiveDuration returns the time between the first and latest request in the session
+func (s Session) ActiveDuration() time.Duration {
+	return s.lastSeen.Sub(s.firstSeen)
+}
+
+// BurnRate returns the rate limited token consumption per minute over the session's active timeline
+// Sessions active for less than a minute are measured over one minute
+func (s Session) BurnRate() float64 {
+	duration := s.ActiveDuration()
+	if duration < minSessionBurnDuration {
+		duration = minSessionBurnDuration
+	}
+
+	return float64(s.rateLimitedTokens.Limited()) / duration.Minutes()
+}
+
+// NewSessionsFromRequests groups API requests by session ID, ordered by first seen time
+func NewSessionsFromRequests(requests []APIRequest) []Session {
+	index := make(map[string]int)
+	var sessions []Session
+
+	for _, req := range requests {
+		i, exists := index[req.SessionID()]
+		if !exists {
+			i = len(sessions)
+			index[req.SessionID()] = i
+			sessions = append(sessions, Session{
+				id:        req.SessionID(),
+				firstSeen: req.Timestamp(),
+				lastSeen:  req.Timestamp(),
+			})
+		}
+
+		session := &sessions[i]
+		session.requests++
+		session.tokens = session.tokens.Add(req.Tokens())
+		session.cost = session.cost.Add(req.Cost())
+		if !req.Model().IsBase() {
+			session.rateLimitedTokens = session.rateLimitedTokens.Add(req.Tokens())
+		}
+		if req.Timestamp().Before(session.firstSeen) {
+			session.firstSeen = req.Timestamp()
+		}
+		if req.Timestamp().After(session.lastSeen) {
+			session.lastSeen = req.Timestamp()
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].firstSeen.Before(sessions[j].firstSeen)
+	})
+
+	return sessions
+}
+
+// HotSessions returns up to limit of the fastest-burning sessions, highest burn rate first
+// Sessions with a single request are skipped since they have no timeline to measure
+func HotSessions(sessions []Session, limit int) []Session {
+	var hot []Session
+	for _, session := range sessions {
+		if session.requests > 1 && session.BurnRate() > 0 {
+			hot = append(hot, session)
+		}
+	}
+
+	sort.SliceStable(hot, func(i, j int) bool {
+		return hot[i].BurnRate() > hot[j].BurnRate()
+	})
+
+	if limit > 0 && len(hot) > limit {
+		hot = hot[:limit]
+	}
+
+	return hot
+}
+
+// TopSessionsByCost returns up to limit of the most expensive sessions, highest cost first
+func TopSessionsByCost(sessions []Session, limit int) []Session {
+	top := make([]Session, len(sessions))
+	copy(top, sessions)
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].cost.Amount() > top[j].cost.Amount()
+	})
+
+	if limit > 0 && len(top) > limit {
+		top = top[:limit]
+	}
+
+	return top
+}
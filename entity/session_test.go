@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewSessionsFromRequests(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []APIRequest{
+		NewAPIRequest("session-b", baseTime.Add(5*time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.1), 1000),
+		NewAPIRequest("session-a", baseTime, "claude-sonnet-4-20250514", NewToken(200, 100, 10, 0), NewCost(0.2), 1000),
+		NewAPIRequest("session-a", baseTime.Add(10*time.Minute), "claude-3-haiku-20240307", NewToken(50, 50, 0, 0), NewCost(0.01), 1000),
+	}
+
+	sessions := NewSessionsFromRequests(requests)
+
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+
+	a := sessions[0]
+	if a.ID() != "session-a" {
+		t.Errorf("Expected first session to be session-a, got %s", a.ID())
+	}
+	if a.Requests() != 2 {
+		t.Errorf("Expected 2 requests, got %d", a.Requests())
+	}
+	if a.Tokens().Total() != 410 {
+		t.Errorf("Expected 410 tokens, got %d", a.Tokens().Total())
+	}
+	if a.RateLimitedTokens().Limited() != 300 {
+		t.Errorf("Expected 300 rate limited tokens, got %d", a.RateLimitedTokens().Limited())
+	}
+	if a.ActiveDuration() != 10*time.Minute {
+		t.Errorf("Expected 10m active duration, got %v", a.ActiveDuration())
+	}
+	if a.BurnRate() != 30 {
+		t.Errorf("Expected burn rate 30/min, got %f", a.BurnRate())
+	}
+}
+
+func TestSession_BurnRate(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		requests []APIRequest
+		expected float64
+	}{
+		{
+			name: "single request uses minimum duration",
+			requests: []APIRequest{
+				NewAPIRequest("s", baseTime, "claude-sonnet-4-20250514", NewToken(100, 20, 0, 0), NewCost(0.1), 1000),
+			},
+			expected: 120,
+		},
+		{
+			name: "short timeline uses minimum duration",
+			requests: []APIRequest{
+				NewAPIRequest("s", baseTime, "claude-sonnet-4-20250514", NewToken(100, 20, 0, 0), NewCost(0.1), 1000),
+				NewAPIRequest("s", baseTime.Add(10*time.Second), "claude-sonnet-4-20250514", NewToken(100, 20, 0, 0), NewCost(0.1), 1000),
+			},
+			expected: 240,
+		},
+		{
+			name: "base model tokens do not count",
+			requests: []APIRequest{
+				NewAPIRequest("s", baseTime, "claude-3-haiku-20240307", NewToken(100, 20, 0, 0), NewCost(0.1), 1000),
+				NewAPIRequest("s", baseTime.Add(2*time.Minute), "claude-3-haiku-20240307", NewToken(100, 20, 0, 0), NewCost(0.1), 1000),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessions := NewSessionsFromRequests(tt.requests)
+			if got := sessions[0].BurnRate(); got != tt.expected {
+				t.Errorf("BurnRate() = %f, want %f", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHotSessions(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequests := func(sessionID string, tokens int64, count int) []APIRequest {
+		var requests []APIRequest
+		for i := 0; i < count; i++ {
+			requests = append(requests, NewAPIRequest(sessionID, baseTime.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-20250514", NewToken(tokens, 0, 0, 0), NewCost(0.1), 1000))
+		}
+		return requests
+	}
+
+	var requests []APIRequest
+	requests = append(requests, newRequests("slow", 100, 3)...)
+	requests = append(requests, newRequests("fast", 1000, 3)...)
+	requests = append(requests, newRequests("medium", 500, 3)...)
+	requests = append(requests, newRequests("single", 100000, 1)...)
+
+	sessions := NewSessionsFromRequests(requests)
+
+	hot := HotSessions(sessions, 2)
+	if len(hot) != 2 {
+		t.Fatalf("Expected 2 hot sessions, got %d", len(hot))
+	}
+	if hot[0].ID() != "fast" || hot[1].ID() != "medium" {
+		t.Errorf("Expected [fast medium], got [%s %s]", hot[0].ID(), hot[1].ID())
+	}
+
+	all := HotSessions(sessions, 0)
+	if len(all) != 3 {
+		t.Errorf("Expected single-request session to be skipped, got %d hot sessions", len(all))
+	}
+}
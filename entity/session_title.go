@@ -0,0 +1,52 @@
+package entity
+
+import "path"
+
+// SessionTitle is the human-readable name of a Claude Code session, read from its transcript
+type SessionTitle struct {
+	title   string // conversation summary, e.g. "Refactor billing module"
+	project string // workspace path the session ran in
+}
+
+// NewSessionTitle creates a new SessionTitle, either part may be empty
+func NewSessionTitle(title, project string) SessionTitle {
+	return SessionTitle{title: title, project: project}
+}
+
+// Title returns the conversation summary
+func (t SessionTitle) Title() string {
+	return t.title
+}
+
+// Project returns the workspace path the session ran in
+func (t SessionTitle) Project() string {
+	return t.project
+}
+
+// Label returns the conversation summary, or the workspace directory name when the session has no summary yet
+func (t SessionTitle) Label() string {
+	if t.title != "" {
+		return t.title
+	}
+	if t.project != "" {
+		return path.Base(t.project)
+	}
+	return ""
+}
+
+// IsZero returns true when neither the summary nor the workspace is known
+func (t SessionTitle) IsZero() bool {
+	return t.title == "" && t.project == ""
+}
+
+// SessionTitles maps session IDs to their titles
+type SessionTitles map[string]SessionTitle
+
+// Apply returns the sessions with their titles, sessions without a title are kept as is
+func (t SessionTitles) Apply(sessions []Session) []Session {
+	titled := make([]Session, len(sessions))
+	for i, session := range sessions {
+		titled[i] = session.WithTitle(t[session.ID()])
+	}
+	return titled
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionTitle_Label(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    SessionTitle
+		expected string
+	}{
+		{name: "summary", title: NewSessionTitle("Refactor billing module", "/home/alice/billing"), expected: "Refactor billing module"},
+		{name: "workspace without summary", title: NewSessionTitle("", "/home/alice/billing"), expected: "billing"},
+		{name: "unknown", title: SessionTitle{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.title.Label(); got != tt.expected {
+				t.Errorf("Expected label %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSessionTitles_Apply(t *testing.T) {
+	now := time.Now()
+	sessions := NewSessionsFromRequests([]APIRequest{
+		NewAPIRequest("5f3a1c2e", now, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+		NewAPIRequest("9b7d4e1f", now.Add(time.Minute), "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(0.01), 1000),
+	})
+	titles := SessionTitles{"5f3a1c2e": NewSessionTitle("Refactor billing module", "")}
+
+	titled := titles.Apply(sessions)
+	if got := titled[0].Name(); got != "Refactor billing module" {
+		t.Errorf("Expected titled session name, got %q", got)
+	}
+	if got := titled[1].Name(); got != "9b7d4e1f" {
+		t.Errorf("Expected untitled session to be named by ID, got %q", got)
+	}
+	if !sessions[0].Title().IsZero() {
+		t.Error("Expected Apply not to modify the given sessions")
+	}
+}
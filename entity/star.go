@@ -0,0 +1,76 @@
+package entity
+
+// StarScope represents which records a star keeps from the retention cleanup
+type StarScope int
+
+const (
+	StarNone    StarScope = iota // not starred
+	StarRequest                  // only the starred request is kept
+	StarSession                  // every request of the starred session is kept
+)
+
+// Next returns the scope after this one, cycling none -> request -> session -> none
+func (s StarScope) Next() StarScope {
+	switch s {
+	case StarNone:
+		return StarRequest
+	case StarRequest:
+		return StarSession
+	default:
+		return StarNone
+	}
+}
+
+// String returns the scope name
+func (s StarScope) String() string {
+	switch s {
+	case StarRequest:
+		return "request"
+	case StarSession:
+		return "session"
+	default:
+		return "none"
+	}
+}
+
+// Stars represents the starred requests and sessions which are never deleted by the cleanup
+type Stars struct {
+	requests map[string]struct{}
+	sessions map[string]struct{}
+}
+
+// NewStars creates a new Stars from the starred request IDs and session IDs
+func NewStars(requestIDs []string, sessionIDs []string) Stars {
+	stars := Stars{
+		requests: make(map[string]struct{}, len(requestIDs)),
+		sessions: make(map[string]struct{}, len(sessionIDs)),
+	}
+	for _, id := range requestIDs {
+		stars.requests[id] = struct{}{}
+	}
+	for _, id := range sessionIDs {
+		stars.sessions[id] = struct{}{}
+	}
+	return stars
+}
+
+// Len returns the number of starred requests and sessions
+func (s Stars) Len() int {
+	return len(s.requests) + len(s.sessions)
+}
+
+// ScopeOf returns why the request is starred, a starred session takes precedence over the request itself
+func (s Stars) ScopeOf(req APIRequest) StarScope {
+	if _, ok := s.sessions[req.SessionID()]; ok {
+		return StarSession
+	}
+	if _, ok := s.requests[req.ID()]; ok {
+		return StarRequest
+	}
+	return StarNone
+}
+
+// IsStarred returns true if the request or its session is starred
+func (s Stars) IsStarred(req APIRequest) bool {
+	return s.ScopeOf(req) != StarNone
+}
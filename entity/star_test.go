@@ -0,0 +1,90 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestStars_ScopeOf(t *testing.T) {
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(sessionID string, offset time.Duration) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, timestamp.Add(offset), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	}
+
+	starredRequest := newRequest("session-1", 0)
+	otherRequest := newRequest("session-1", time.Minute)
+	sessionRequest := newRequest("session-2", 0)
+
+	stars := entity.NewStars([]string{starredRequest.ID(), sessionRequest.ID()}, []string{"session-2"})
+
+	tests := []struct {
+		name            string
+		request         entity.APIRequest
+		expectedScope   entity.StarScope
+		expectedStarred bool
+	}{
+		{
+			name:            "starred request",
+			request:         starredRequest,
+			expectedScope:   entity.StarRequest,
+			expectedStarred: true,
+		},
+		{
+			name:            "other request of the same session",
+			request:         otherRequest,
+			expectedScope:   entity.StarNone,
+			expectedStarred: false,
+		},
+		{
+			name:            "starred session takes precedence",
+			request:         sessionRequest,
+			expectedScope:   entity.StarSession,
+			expectedStarred: true,
+		},
+		{
+			name:            "any request of a starred session",
+			request:         newRequest("session-2", time.Hour),
+			expectedScope:   entity.StarSession,
+			expectedStarred: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stars.ScopeOf(tt.request); got != tt.expectedScope {
+				t.Errorf("Expected scope %v, got %v", tt.expectedScope, got)
+			}
+			if got := stars.IsStarred(tt.request); got != tt.expectedStarred {
+				t.Errorf("Expected starred %v, got %v", tt.expectedStarred, got)
+			}
+		})
+	}
+
+	if stars.Len() != 3 {
+		t.Errorf("Expected 3 stars, got %d", stars.Len())
+	}
+	if (entity.Stars{}).IsStarred(starredRequest) {
+		t.Error("Expected empty stars to keep nothing")
+	}
+}
+
+func TestStarScope_Next(t *testing.T) {
+	tests := []struct {
+		scope    entity.StarScope
+		expected entity.StarScope
+	}{
+		{scope: entity.StarNone, expected: entity.StarRequest},
+		{scope: entity.StarRequest, expected: entity.StarSession},
+		{scope: entity.StarSession, expected: entity.StarNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope.String(), func(t *testing.T) {
+			if got := tt.scope.Next(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
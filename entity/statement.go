@@ -0,0 +1,121 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statement is a monthly usage statement with daily line items, totals, plan utilization and top sessions
+type Statement struct {
+	monthStart     time.Time
+	days           []Stats
+	movingAverages []MovingAverage
+	total          Stats
+	plan           Plan
+	topSessions    []Session
+}
+
+// ParseStatementMonth parses a "YYYY-MM" month into the first day of the month in the given timezone
+func ParseStatementMonth(value string, timezone *time.Location) (time.Time, error) {
+	month, err := time.ParseInLocation("2006-01", value, timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM", value)
+	}
+
+	return month, nil
+}
+
+// NewStatementPeriod returns the period covering the month starting at monthStart
+// Boundaries follow monthStart's timezone and are converted to UTC for database queries
+func NewStatementPeriod(monthStart time.Time) Period {
+	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	return NewPeriod(monthStart.UTC(), monthEnd.UTC())
+}
+
+// NewStatement creates a statement for the month starting at monthStart from the month's requests
+// Requests outside the month are ignored, topSessions limits the session list (0 means all)
+func NewStatement(monthStart time.Time, requests []APIRequest, plan Plan, topSessions int) Statement {
+	period := NewStatementPeriod(monthStart)
+
+	var monthRequests []APIRequest
+	dailyRequests := make(map[int][]APIRequest)
+	for _, req := range requests {
+		timestamp := req.Timestamp()
+		if timestamp.Before(period.StartAt()) || timestamp.After(period.EndAt()) {
+			continue
+		}
+
+		monthRequests = append(monthRequests, req)
+		day := timestamp.In(monthStart.Location()).Day()
+		dailyRequests[day] = append(dailyRequests[day], req)
+	}
+
+	var days []Stats
+	for dayStart := monthStart; dayStart.Before(monthStart.AddDate(0, 1, 0)); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayPeriod := NewPeriod(dayStart.UTC(), dayStart.AddDate(0, 0, 1).Add(-time.Nanosecond).UTC())
+		days = append(days, NewStatsFromRequests(dailyRequests[dayStart.Day()], dayPeriod))
+	}
+
+	return Statement{
+		monthStart:  monthStart,
+		days:        days,
+		total:       NewStatsFromRequests(monthRequests, period),
+		plan:        plan,
+		topSessions: TopSessionsByCost(NewSessionsFromRequests(monthRequests), topSessions),
+	}
+}
+
+// MonthStart returns the first day of the statement month in the statement timezone
+func (s Statement) MonthStart() time.Time {
+	return s.monthStart
+}
+
+// Period returns the period covered by the statement
+func (s Statement) Period() Period {
+	return NewStatementPeriod(s.monthStart)
+}
+
+// Days returns the statistics of every day in the month, in calendar order
+func (s Statement) Days() []Stats {
+	return s.days
+}
+
+// WithMovingAverages returns a copy of the statement with the moving average cost of every day, in calendar order
+func (s Statement) WithMovingAverages(movingAverages []MovingAverage) Statement {
+	s.movingAverages = movingAverages
+	return s
+}
+
+// MovingAverageAt returns the moving average cost of the day at index i of Days
+func (s Statement) MovingAverageAt(i int) (MovingAverage, bool) {
+	if i < 0 || i >= len(s.movingAverages) {
+		return MovingAverage{}, false
+	}
+	return s.movingAverages[i], true
+}
+
+// Total returns the statistics of the whole month
+func (s Statement) Total() Stats {
+	return s.total
+}
+
+// Plan returns the subscription plan the usage is compared against
+func (s Statement) Plan() Plan {
+	return s.plan
+}
+
+// PlanUtilization returns the month's cost as a percentage of the plan price
+func (s Statement) PlanUtilization() int {
+	return s.plan.CalculateUsagePercentage(s.total.TotalCost())
+}
+
+// WithSessionTitles returns a copy of the statement with the titles of its top sessions
+func (s Statement) WithSessionTitles(titles SessionTitles) Statement {
+	s.topSessions = titles.Apply(s.topSessions)
+	return s
+}
+
+// TopSessions returns the most expensive sessions of the month, highest cost first
+func (s Statement) TopSessions() []Session {
+	return s.topSessions
+}
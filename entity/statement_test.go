@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStatementMonth(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		expected    time.Time
+		expectError bool
+	}{
+		{
+			name:     "valid month",
+			value:    "2025-06",
+			expected: time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo),
+		},
+		{
+			name:        "full date is rejected",
+			value:       "2025-06-01",
+			expectError: true,
+		},
+		{
+			name:        "invalid month",
+			value:       "2025-13",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseStatementMonth(tt.value, tokyo)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ParseStatementMonth(%q) expected error", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStatementMonth(%q) unexpected error: %v", tt.value, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseStatementMonth(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewStatement(t *testing.T) {
+	t.Parallel()
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	monthStart := time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo)
+
+	newRequest := func(sessionID string, timestamp time.Time, cost float64) APIRequest {
+		return NewAPIRequest(sessionID, timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(cost), 1000)
+	}
+
+	requests := []APIRequest{
+		// 2025-06-01 08:00 Tokyo, still May 31 in UTC
+		newRequest("session-a", time.Date(2025, 5, 31, 23, 0, 0, 0, time.UTC), 1.0),
+		newRequest("session-a", time.Date(2025, 6, 1, 1, 0, 0, 0, time.UTC), 2.0),
+		newRequest("session-b", time.Date(2025, 6, 15, 3, 0, 0, 0, time.UTC), 5.0),
+		// 2025-07-01 01:00 Tokyo, outside the month
+		newRequest("session-c", time.Date(2025, 6, 30, 16, 0, 0, 0, time.UTC), 9.0),
+	}
+
+	statement := NewStatement(monthStart, requests, NewPlan("pro", NewCost(20)), 1)
+
+	if len(statement.Days()) != 30 {
+		t.Fatalf("Days() returned %d days, want 30", len(statement.Days()))
+	}
+	if got := statement.Days()[0].TotalRequests(); got != 2 {
+		t.Errorf("first day requests = %d, want 2", got)
+	}
+	if got := statement.Days()[14].TotalCost().Amount(); got != 5.0 {
+		t.Errorf("15th day cost = %v, want 5", got)
+	}
+	if got := statement.Total().TotalRequests(); got != 3 {
+		t.Errorf("Total() requests = %d, want 3", got)
+	}
+	if got := statement.PlanUtilization(); got != 40 {
+		t.Errorf("PlanUtilization() = %d, want 40", got)
+	}
+
+	sessions := statement.TopSessions()
+	if len(sessions) != 1 {
+		t.Fatalf("TopSessions() returned %d sessions, want 1", len(sessions))
+	}
+	if sessions[0].ID() != "session-b" {
+		t.Errorf("TopSessions()[0] = %s, want session-b", sessions[0].ID())
+	}
+}
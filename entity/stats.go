@@ -1,14 +1,25 @@
 package entity
 
+import (
+	"math"
+	"time"
+)
+
 // Stats represents aggregated statistics for API requests
 type Stats struct {
-	baseRequests    int
-	premiumRequests int
-	baseTokens      Token
-	premiumTokens   Token
-	baseCost        Cost
-	premiumCost     Cost
-	period          Period
+	baseRequests        int
+	premiumRequests     int
+	longContextRequests int
+	baseTokens          Token
+	premiumTokens       Token
+	longContextTokens   Token
+	baseCost            Cost
+	premiumCost         Cost
+	longContextCost     Cost
+	period              Period
+	models              []ModelStats // usage of each model, the most expensive first
+	approximate         bool         // estimated from a sample of the requests
+	cachedAt            time.Time    // when the stats were calculated if served from a cache, zero when fresh
 }
 
 // BaseRequests returns the number of base model requests
@@ -41,19 +52,55 @@ func (s Stats) PremiumCost() Cost {
 	return s.premiumCost
 }
 
+// LongContextRequests returns the number of 1M context model requests
+func (s Stats) LongContextRequests() int {
+	return s.longContextRequests
+}
+
+// LongContextTokens returns the token usage for 1M context models
+func (s Stats) LongContextTokens() Token {
+	return s.longContextTokens
+}
+
+// LongContextCost returns the cost for 1M context model usage
+func (s Stats) LongContextCost() Cost {
+	return s.longContextCost
+}
+
+// RateLimitedTokens returns the tokens from models that count against Claude's rate limits
+// (premium and 1M context models)
+func (s Stats) RateLimitedTokens() Token {
+	return s.premiumTokens.Add(s.longContextTokens)
+}
+
+// RateLimitedRequests returns the number of premium and 1M context model requests
+func (s Stats) RateLimitedRequests() int {
+	return s.premiumRequests + s.longContextRequests
+}
+
+// RateLimitedCost returns the cost of the premium and 1M context model usage
+func (s Stats) RateLimitedCost() Cost {
+	return s.premiumCost.Add(s.longContextCost)
+}
+
 // TotalRequests returns the total number of requests
 func (s Stats) TotalRequests() int {
-	return s.baseRequests + s.premiumRequests
+	return s.baseRequests + s.premiumRequests + s.longContextRequests
 }
 
 // TotalTokens returns the total tokens across all requests
 func (s Stats) TotalTokens() Token {
-	return s.baseTokens.Add(s.premiumTokens)
+	return s.baseTokens.Add(s.premiumTokens).Add(s.longContextTokens)
 }
 
 // TotalCost returns the total cost across all requests
 func (s Stats) TotalCost() Cost {
-	return s.baseCost.Add(s.premiumCost)
+	return s.baseCost.Add(s.premiumCost).Add(s.longContextCost)
+}
+
+// CostPerKiloToken returns the total cost of 1,000 tokens, false without tokens
+func (s Stats) CostPerKiloToken() (Cost, bool) {
+	return s.TotalCost().PerKiloToken(s.TotalTokens())
 }
 
 // Period returns the time period for these statistics
@@ -64,6 +111,29 @@ func (s Stats) Period() Period {
 // PremiumTokenBurnRate returns the premium token consumption rate per minute
 // Returns 0 for all-time periods or zero duration periods
 func (s Stats) PremiumTokenBurnRate() float64 {
+	return s.burnRate(s.premiumTokens)
+}
+
+// LongContextTokenBurnRate returns the 1M context token consumption rate per minute
+func (s Stats) LongContextTokenBurnRate() float64 {
+	return s.burnRate(s.longContextTokens)
+}
+
+// RateLimitedTokenBurnRate returns the consumption rate per minute of all tokens counting against rate limits
+func (s Stats) RateLimitedTokenBurnRate() float64 {
+	return s.burnRate(s.RateLimitedTokens())
+}
+
+// ModelTokenBurnRate returns the token consumption rate per minute of a model, zero for base models
+func (s Stats) ModelTokenBurnRate(model ModelStats) float64 {
+	if NewModel(model.Model()).IsBase() {
+		return 0
+	}
+	return s.burnRate(model.Tokens())
+}
+
+// burnRate returns the limited token consumption rate per minute over the stats period
+func (s Stats) burnRate(tokens Token) float64 {
 	// Skip calculation for all-time periods
 	if s.period.IsAllTime() {
 		return 0
@@ -81,7 +151,7 @@ func (s Stats) PremiumTokenBurnRate() float64 {
 	}
 
 	// Use Limited() tokens as these count against Claude's rate limits
-	limitedTokens := float64(s.premiumTokens.Limited())
+	limitedTokens := float64(tokens.Limited())
 	return limitedTokens / minutes
 }
 
@@ -98,14 +168,104 @@ func NewStats(baseRequests, premiumRequests int, baseTokens, premiumTokens Token
 	}
 }
 
+// WithLongContext returns a copy of the stats with the given 1M context tier values
+func (s Stats) WithLongContext(requests int, tokens Token, cost Cost) Stats {
+	s.longContextRequests = requests
+	s.longContextTokens = tokens
+	s.longContextCost = cost
+	return s
+}
+
+// WithModels returns a copy of the stats with the usage of each model, sorted with the most expensive first
+func (s Stats) WithModels(models []ModelStats) Stats {
+	s.models = append([]ModelStats(nil), models...)
+	sortModelStats(s.models)
+	return s
+}
+
+// Models returns the usage of each model, the most expensive first
+// Empty when the stats were calculated without the breakdown, e.g. by a server predating it
+func (s Stats) Models() []ModelStats {
+	return s.models
+}
+
+// WithApproximate returns a copy of the stats marked as estimated or exact
+func (s Stats) WithApproximate(approximate bool) Stats {
+	s.approximate = approximate
+	return s
+}
+
+// IsApproximate returns true if the stats are estimated from a sample of the requests
+func (s Stats) IsApproximate() bool {
+	return s.approximate
+}
+
+// WithCachedAt returns a copy of the stats served from a cache, calculated at the given time
+func (s Stats) WithCachedAt(cachedAt time.Time) Stats {
+	s.cachedAt = cachedAt
+	return s
+}
+
+// CachedAt returns when the stats were calculated if they are served from a cache, zero when fresh
+func (s Stats) CachedAt() time.Time {
+	return s.cachedAt
+}
+
+// IsCached returns true if the stats are served from a cache
+func (s Stats) IsCached() bool {
+	return !s.cachedAt.IsZero()
+}
+
+// EstimateStatsFromSample estimates the stats of total requests from an evenly spread sample of them
+// Each model tier and model is scaled up by the sampling ratio, a sample covering every request gives exact stats
+func EstimateStatsFromSample(sample []APIRequest, total int, period Period) Stats {
+	stats := NewStatsFromRequests(sample, period)
+	models := NewModelStatsFromRequests(sample)
+	if len(sample) == 0 || total <= len(sample) {
+		return stats.WithModels(models)
+	}
+
+	factor := float64(total) / float64(len(sample))
+	scale := func(requests int) int {
+		return scaleRequests(requests, factor)
+	}
+
+	for i := range models {
+		models[i] = models[i].scale(factor)
+	}
+
+	return NewStats(
+		scale(stats.baseRequests),
+		scale(stats.premiumRequests),
+		stats.baseTokens.Scale(factor),
+		stats.premiumTokens.Scale(factor),
+		stats.baseCost.Scale(factor),
+		stats.premiumCost.Scale(factor),
+		period,
+	).WithLongContext(
+		scale(stats.longContextRequests),
+		stats.longContextTokens.Scale(factor),
+		stats.longContextCost.Scale(factor),
+	).WithModels(models).WithApproximate(true)
+}
+
+// scaleRequests returns the number of requests multiplied by the factor, rounded to the nearest request
+func scaleRequests(requests int, factor float64) int {
+	return int(math.Round(float64(requests) * factor))
+}
+
 // NewStatsFromRequests calculates statistics from a list of API requests
 func NewStatsFromRequests(requests []APIRequest, period Period) Stats {
-	var baseRequests, premiumRequests int
-	var baseTokens, premiumTokens Token
-	var baseCost, premiumCost Cost
+	var baseRequests, premiumRequests, longContextRequests int
+	var baseTokens, premiumTokens, longContextTokens Token
+	var baseCost, premiumCost, longContextCost Cost
 
 	for _, req := range requests {
-		if req.Model().IsBase() {
+		if req.Model().IsLongContext() {
+			longContextRequests++
+			longContextTokens = longContextTokens.Add(req.Tokens())
+			longContextCost = longContextCost.Add(req.Cost())
+		} else if req.Model().IsBase() {
 			baseRequests++
 			baseTokens = baseTokens.Add(req.Tokens())
 			baseCost = baseCost.Add(req.Cost())
@@ -124,5 +284,5 @@ func NewStatsFromRequests(requests []APIRequest, period Period) Stats {
 		baseCost,
 		premiumCost,
 		period,
-	)
+	).WithLongContext(longContextRequests, longContextTokens, longContextCost)
 }
@@ -0,0 +1,41 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// createBenchmarkRequests creates n requests spread over a day with a mix of base, premium and long context models
+func createBenchmarkRequests(n int) []entity.APIRequest {
+	models := []string{"claude-3-5-haiku-20241022", "claude-sonnet-4-20250514", "claude-opus-4-20250514", "claude-sonnet-4-20250514[1m]"}
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	requests := make([]entity.APIRequest, 0, n)
+	for i := 0; i < n; i++ {
+		requests = append(requests, entity.NewAPIRequest("session", start.Add(time.Duration(i)*time.Second), models[i%len(models)], entity.NewToken(1000, 500, 200, 100), entity.NewCost(0.01), 1000))
+	}
+	return requests
+}
+
+func BenchmarkNewStatsFromRequests(b *testing.B) {
+	requests := createBenchmarkRequests(10000)
+	period := entity.NewPeriod(requests[0].Timestamp(), requests[len(requests)-1].Timestamp())
+
+	b.ReportAllocs()
+	for b.Loop() {
+		entity.NewStatsFromRequests(requests, period)
+	}
+}
+
+func TestNewStatsFromRequests_AllocationBudget(t *testing.T) {
+	requests := createBenchmarkRequests(10000)
+	period := entity.NewPeriod(requests[0].Timestamp(), requests[len(requests)-1].Timestamp())
+
+	// Aggregation folds value objects, it must not allocate per request
+	testutil.CheckAllocationBudget(t, 0, func() {
+		entity.NewStatsFromRequests(requests, period)
+	})
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"math"
 	"testing"
 	"time"
 )
@@ -160,3 +161,158 @@ func TestNewStatsFromRequests_ModelClassification(t *testing.T) {
 		})
 	}
 }
+
+func TestNewStatsFromRequests_LongContextTier(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(baseTime, baseTime.Add(time.Hour))
+
+	requests := []APIRequest{
+		NewAPIRequest("session", baseTime, "claude-3-haiku-20240307", NewToken(100, 50, 0, 0), NewCost(0.001), 1000),
+		NewAPIRequest("session", baseTime, "claude-sonnet-4-20250514", NewToken(200, 100, 0, 0), NewCost(0.01), 1000),
+		NewAPIRequest("session", baseTime, "claude-sonnet-4-20250514[1m]", NewToken(1000, 200, 500, 0), NewCost(0.5), 1000),
+	}
+
+	stats := NewStatsFromRequests(requests, period)
+
+	if stats.LongContextRequests() != 1 {
+		t.Errorf("Expected 1 long context request, got %d", stats.LongContextRequests())
+	}
+	if stats.PremiumRequests() != 1 {
+		t.Errorf("Expected 1 premium request, got %d", stats.PremiumRequests())
+	}
+	if stats.LongContextTokens().Total() != 1700 {
+		t.Errorf("Expected 1700 long context tokens, got %d", stats.LongContextTokens().Total())
+	}
+	if stats.LongContextCost().Amount() != 0.5 {
+		t.Errorf("Expected long context cost 0.5, got %f", stats.LongContextCost().Amount())
+	}
+	if stats.TotalRequests() != 3 {
+		t.Errorf("Expected 3 total requests, got %d", stats.TotalRequests())
+	}
+	if stats.TotalTokens().Total() != 2150 {
+		t.Errorf("Expected 2150 total tokens, got %d", stats.TotalTokens().Total())
+	}
+	if stats.RateLimitedTokens().Limited() != 1500 {
+		t.Errorf("Expected 1500 rate limited tokens, got %d", stats.RateLimitedTokens().Limited())
+	}
+	if stats.RateLimitedRequests() != 2 {
+		t.Errorf("Expected 2 rate limited requests, got %d", stats.RateLimitedRequests())
+	}
+	if math.Abs(stats.RateLimitedCost().Amount()-0.51) > 1e-9 {
+		t.Errorf("Expected rate limited cost 0.51, got %f", stats.RateLimitedCost().Amount())
+	}
+	if stats.RateLimitedTokenBurnRate() != 25 {
+		t.Errorf("Expected rate limited burn rate 25, got %f", stats.RateLimitedTokenBurnRate())
+	}
+	if stats.LongContextTokenBurnRate() != 20 {
+		t.Errorf("Expected long context burn rate 20, got %f", stats.LongContextTokenBurnRate())
+	}
+}
+
+func TestEstimateStatsFromSample(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(baseTime, baseTime.Add(time.Hour))
+
+	sample := []APIRequest{
+		NewAPIRequest("session", baseTime, "claude-3-haiku-20240307", NewToken(100, 50, 0, 0), NewCost(0.001), 1000),
+		NewAPIRequest("session", baseTime, "claude-sonnet-4-20250514", NewToken(200, 100, 0, 0), NewCost(0.01), 1000),
+	}
+
+	tests := []struct {
+		name              string
+		total             int
+		expectedRequests  int
+		expectedTokens    int64
+		expectedCost      float64
+		expectApproximate bool
+	}{
+		{
+			name:              "sample covers every request",
+			total:             2,
+			expectedRequests:  2,
+			expectedTokens:    450,
+			expectedCost:      0.011,
+			expectApproximate: false,
+		},
+		{
+			name:              "sample of every tenth request",
+			total:             20,
+			expectedRequests:  20,
+			expectedTokens:    4500,
+			expectedCost:      0.11,
+			expectApproximate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := EstimateStatsFromSample(sample, tt.total, period)
+
+			if stats.TotalRequests() != tt.expectedRequests {
+				t.Errorf("TotalRequests() = %d, want %d", stats.TotalRequests(), tt.expectedRequests)
+			}
+			if stats.TotalTokens().Total() != tt.expectedTokens {
+				t.Errorf("TotalTokens().Total() = %d, want %d", stats.TotalTokens().Total(), tt.expectedTokens)
+			}
+			if diff := stats.TotalCost().Amount() - tt.expectedCost; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("TotalCost() = %f, want %f", stats.TotalCost().Amount(), tt.expectedCost)
+			}
+			if stats.IsApproximate() != tt.expectApproximate {
+				t.Errorf("IsApproximate() = %v, want %v", stats.IsApproximate(), tt.expectApproximate)
+			}
+		})
+	}
+}
+
+func TestStats_CostPerKiloToken(t *testing.T) {
+	t.Parallel()
+
+	period := NewPeriod(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		requests []APIRequest
+		want     float64
+		wantOK   bool
+	}{
+		{
+			name:   "no requests",
+			wantOK: false,
+		},
+		{
+			name: "cost of 1,000 tokens across tiers",
+			requests: []APIRequest{
+				NewAPIRequest("s1", period.StartAt(), "claude-sonnet-4-20250514", NewToken(1000, 500, 0, 0), NewCost(1.5), 0),
+				NewAPIRequest("s1", period.StartAt(), "claude-3-5-haiku-20241022", NewToken(2000, 500, 0, 0), NewCost(0.5), 0),
+			},
+			want:   0.5, // $2.00 for 4,000 tokens
+			wantOK: true,
+		},
+		{
+			name: "cache reads lower the cost per 1K tokens",
+			requests: []APIRequest{
+				NewAPIRequest("s1", period.StartAt(), "claude-sonnet-4-20250514", NewToken(1000, 500, 8500, 0), NewCost(1.5), 0),
+			},
+			want:   0.15, // $1.50 for 10,000 tokens
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := NewStatsFromRequests(tt.requests, period).CostPerKiloToken()
+			if ok != tt.wantOK {
+				t.Fatalf("CostPerKiloToken() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := got.Amount() - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("CostPerKiloToken() = %v, want %v", got.Amount(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TelemetryGapPolicy detects when a previously active exporter stops sending events
+// Broken environment variables or exporter settings otherwise look like a quiet day
+type TelemetryGapPolicy struct {
+	threshold time.Duration
+	days      map[time.Weekday]struct{}
+	timezone  *time.Location
+}
+
+// NewTelemetryGapPolicy creates a new TelemetryGapPolicy, a zero threshold disables detection
+// Gaps are only reported on the given days in the timezone, no days means every day
+func NewTelemetryGapPolicy(threshold time.Duration, days []time.Weekday, timezone *time.Location) (TelemetryGapPolicy, error) {
+	if threshold < 0 {
+		return TelemetryGapPolicy{}, fmt.Errorf("telemetry gap threshold must not be negative, got: %v", threshold)
+	}
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	daySet := make(map[time.Weekday]struct{}, len(days))
+	for _, day := range days {
+		daySet[day] = struct{}{}
+	}
+
+	return TelemetryGapPolicy{
+		threshold: threshold,
+		days:      daySet,
+		timezone:  timezone,
+	}, nil
+}
+
+// ParseWeekday parses a weekday name like "mon" or "Monday"
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("unsupported weekday %q", name)
+}
+
+// Threshold returns how long no events may arrive before a gap is reported
+func (p TelemetryGapPolicy) Threshold() time.Duration {
+	return p.threshold
+}
+
+// IsEnabled returns true if telemetry gap detection is enabled
+func (p TelemetryGapPolicy) IsEnabled() bool {
+	return p.threshold > 0
+}
+
+// IsActiveDay returns true if gaps are reported on the day of the given time
+func (p TelemetryGapPolicy) IsActiveDay(at time.Time) bool {
+	if len(p.days) == 0 {
+		return true
+	}
+	_, ok := p.days[at.In(p.timezone).Weekday()]
+	return ok
+}
+
+// IsGap returns true if no events arrived since lastEventAt for longer than the threshold
+// A zero lastEventAt means the exporter was never active, which is not reported as a gap
+func (p TelemetryGapPolicy) IsGap(lastEventAt, now time.Time) bool {
+	if !p.IsEnabled() || lastEventAt.IsZero() {
+		return false
+	}
+	return now.Sub(lastEventAt) >= p.threshold && p.IsActiveDay(now)
+}
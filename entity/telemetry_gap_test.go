@@ -0,0 +1,138 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTelemetryGapPolicy_IsGap(t *testing.T) {
+	t.Parallel()
+
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	// 2025-06-02 is a Monday
+	monday := time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 6, 7, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		threshold   time.Duration
+		days        []time.Weekday
+		timezone    *time.Location
+		lastEventAt time.Time
+		now         time.Time
+		expected    bool
+	}{
+		{
+			name:        "disabled",
+			threshold:   0,
+			lastEventAt: monday.Add(-10 * time.Hour),
+			now:         monday,
+			expected:    false,
+		},
+		{
+			name:        "never active",
+			threshold:   2 * time.Hour,
+			lastEventAt: time.Time{},
+			now:         monday,
+			expected:    false,
+		},
+		{
+			name:        "within threshold",
+			threshold:   2 * time.Hour,
+			lastEventAt: monday.Add(-time.Hour),
+			now:         monday,
+			expected:    false,
+		},
+		{
+			name:        "beyond threshold",
+			threshold:   2 * time.Hour,
+			lastEventAt: monday.Add(-3 * time.Hour),
+			now:         monday,
+			expected:    true,
+		},
+		{
+			name:        "beyond threshold on a weekday",
+			threshold:   2 * time.Hour,
+			days:        weekdays,
+			lastEventAt: monday.Add(-3 * time.Hour),
+			now:         monday,
+			expected:    true,
+		},
+		{
+			name:        "weekend is not reported",
+			threshold:   2 * time.Hour,
+			days:        weekdays,
+			lastEventAt: saturday.Add(-3 * time.Hour),
+			now:         saturday,
+			expected:    false,
+		},
+		{
+			name:        "days follow the timezone",
+			threshold:   2 * time.Hour,
+			days:        weekdays,
+			timezone:    time.FixedZone("UTC+10", 10*60*60),
+			lastEventAt: time.Date(2025, 6, 6, 11, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, 6, 6, 15, 0, 0, 0, time.UTC), // Saturday 01:00 in UTC+10
+			expected:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := NewTelemetryGapPolicy(tt.threshold, tt.days, tt.timezone)
+			if err != nil {
+				t.Fatalf("NewTelemetryGapPolicy() returned error: %v", err)
+			}
+
+			if got := policy.IsGap(tt.lastEventAt, tt.now); got != tt.expected {
+				t.Errorf("IsGap() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewTelemetryGapPolicy_NegativeThreshold(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewTelemetryGapPolicy(-time.Hour, nil, time.UTC); err == nil {
+		t.Error("Expected error for negative threshold, got nil")
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    time.Weekday
+		expectError bool
+	}{
+		{name: "short name", input: "mon", expected: time.Monday},
+		{name: "full name", input: "Saturday", expected: time.Saturday},
+		{name: "surrounding spaces", input: " sun ", expected: time.Sunday},
+		{name: "unknown", input: "someday", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			day, err := ParseWeekday(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if day != tt.expected {
+				t.Errorf("ParseWeekday(%q) = %v, want %v", tt.input, day, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultThrottleThreshold is the default block usage percentage at which agents should pause
+const DefaultThrottleThreshold = 90.0
+
+// ThrottlePolicy decides when agent orchestrators should pause work to stay within the block token limit
+type ThrottlePolicy struct {
+	blockStartHour int
+	blockDuration  time.Duration // 0 uses TimeBlockDuration
+	timezone       *time.Location
+	tokenLimit     int
+	threshold      float64
+}
+
+// NewThrottlePolicy creates a new ThrottlePolicy for blocks starting at the hour in the timezone
+// Agents should pause once the block usage reaches threshold percent of the token limit, without a limit they never pause
+func NewThrottlePolicy(blockStartHour int, timezone *time.Location, tokenLimit int, threshold float64) (ThrottlePolicy, error) {
+	if threshold <= 0 {
+		return ThrottlePolicy{}, fmt.Errorf("throttle threshold must be positive, got: %v", threshold)
+	}
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return ThrottlePolicy{
+		blockStartHour: blockStartHour,
+		timezone:       timezone,
+		tokenLimit:     tokenLimit,
+		threshold:      threshold,
+	}, nil
+}
+
+// WithBlockDuration returns a copy of the policy for blocks of the given length
+func (p ThrottlePolicy) WithBlockDuration(duration time.Duration) ThrottlePolicy {
+	p.blockDuration = duration
+	return p
+}
+
+// Threshold returns the block usage percentage at which agents should pause
+func (p ThrottlePolicy) Threshold() float64 {
+	return p.threshold
+}
+
+// Block returns the block containing now
+func (p ThrottlePolicy) Block(now time.Time) Block {
+	return NewCurrentBlockWithDuration(p.blockStartHour, p.timezone, now, p.tokenLimit, p.blockDuration)
+}
+
+// Signal returns the throttle signal of the block given its rate limited tokens
+func (p ThrottlePolicy) Signal(block Block, rateLimitedTokens Token, now time.Time) ThrottleSignal {
+	return ThrottleSignal{
+		generatedAt: now,
+		block:       block,
+		tokens:      rateLimitedTokens,
+		threshold:   p.threshold,
+	}
+}
+
+// ThrottleSignal is the block usage polled by agent orchestrators to pause work near the limit
+type ThrottleSignal struct {
+	generatedAt time.Time
+	block       Block
+	tokens      Token
+	threshold   float64
+}
+
+// GeneratedAt returns when the signal was calculated
+func (s ThrottleSignal) GeneratedAt() time.Time {
+	return s.generatedAt
+}
+
+// Block returns the block the signal is about
+func (s ThrottleSignal) Block() Block {
+	return s.block
+}
+
+// Tokens returns the rate limited tokens used in the block
+func (s ThrottleSignal) Tokens() Token {
+	return s.tokens
+}
+
+// Threshold returns the block usage percentage at which agents should pause
+func (s ThrottleSignal) Threshold() float64 {
+	return s.threshold
+}
+
+// Progress returns the percentage of the token limit used, false when the plan has no limit
+func (s ThrottleSignal) Progress() (float64, bool) {
+	if !s.block.HasLimit() {
+		return 0, false
+	}
+	return s.block.CalculateProgress(s.tokens), true
+}
+
+// ShouldThrottle returns true once the block usage reaches the threshold
+func (s ThrottleSignal) ShouldThrottle() bool {
+	progress, ok := s.Progress()
+	return ok && progress >= s.threshold
+}
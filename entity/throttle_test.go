@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewThrottlePolicy(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewThrottlePolicy(5, time.UTC, 7000, 0); err == nil {
+		t.Error("NewThrottlePolicy() error = nil, want an error for a zero threshold")
+	}
+	if _, err := NewThrottlePolicy(5, time.UTC, 7000, -10); err == nil {
+		t.Error("NewThrottlePolicy() error = nil, want an error for a negative threshold")
+	}
+}
+
+func TestThrottleSignal_ShouldThrottle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		tokenLimit     int
+		tokens         Token
+		wantProgress   float64
+		wantLimit      bool
+		shouldThrottle bool
+	}{
+		{
+			name:         "below the threshold",
+			tokenLimit:   7000,
+			tokens:       NewToken(3000, 500, 0, 0),
+			wantProgress: 50,
+			wantLimit:    true,
+		},
+		{
+			name:           "at the threshold",
+			tokenLimit:     7000,
+			tokens:         NewToken(6000, 300, 0, 0),
+			wantProgress:   90,
+			wantLimit:      true,
+			shouldThrottle: true,
+		},
+		{
+			name:         "cache tokens do not count",
+			tokenLimit:   7000,
+			tokens:       NewToken(3000, 500, 100000, 100000),
+			wantProgress: 50,
+			wantLimit:    true,
+		},
+		{
+			name:   "no limit never throttles",
+			tokens: NewToken(1000000, 1000000, 0, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := NewThrottlePolicy(5, time.UTC, tt.tokenLimit, DefaultThrottleThreshold)
+			if err != nil {
+				t.Fatalf("NewThrottlePolicy() error = %v", err)
+			}
+
+			block := policy.Block(now)
+			if want := time.Date(2025, 6, 1, 5, 0, 0, 0, time.UTC); !block.StartAt().Equal(want) {
+				t.Errorf("Block().StartAt() = %v, want %v", block.StartAt(), want)
+			}
+
+			signal := policy.Signal(block, tt.tokens, now)
+			progress, ok := signal.Progress()
+			if ok != tt.wantLimit || progress != tt.wantProgress {
+				t.Errorf("Progress() = %v, %v, want %v, %v", progress, ok, tt.wantProgress, tt.wantLimit)
+			}
+			if signal.ShouldThrottle() != tt.shouldThrottle {
+				t.Errorf("ShouldThrottle() = %v, want %v", signal.ShouldThrottle(), tt.shouldThrottle)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clock represents the clock used to render times
+type Clock string
+
+const (
+	ClockDefault Clock = ""    // 24-hour times with compact am/pm block labels (e.g. "5am - 10am")
+	Clock12Hour  Clock = "12h" // e.g. "3:04:05 PM" and "5am - 10am"
+	Clock24Hour  Clock = "24h" // e.g. "15:04:05" and "05:00 - 10:00"
+)
+
+// DefaultDatePattern is the ISO date pattern used when no date format is configured
+const DefaultDatePattern = "YYYY-MM-DD"
+
+// datePatternTokens maps the date pattern tokens to Go layout elements, longer tokens first
+var datePatternTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"YY", "06"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"DD", "02"},
+	{"ddd", "Mon"},
+}
+
+// datePatternSeparators are the characters allowed between date pattern tokens
+const datePatternSeparators = "-/. ,"
+
+// TimeFormat represents how dates and times are rendered for display
+type TimeFormat struct {
+	datePattern string
+	dateLayout  string
+	clock       Clock
+}
+
+// NewTimeFormat creates a new TimeFormat from a date pattern like "DD/MM/YYYY" and a clock
+// Patterns use the YYYY, YY, MMM, MM, DD and ddd tokens separated by "-", "/", ".", "," or spaces
+func NewTimeFormat(datePattern string, clock Clock) (TimeFormat, error) {
+	if datePattern == "" {
+		datePattern = DefaultDatePattern
+	}
+
+	switch clock {
+	case ClockDefault, Clock12Hour, Clock24Hour:
+	default:
+		return TimeFormat{}, fmt.Errorf("unsupported clock %q, expected 12h or 24h", clock)
+	}
+
+	dateLayout, err := parseDatePattern(datePattern)
+	if err != nil {
+		return TimeFormat{}, err
+	}
+
+	return TimeFormat{
+		datePattern: datePattern,
+		dateLayout:  dateLayout,
+		clock:       clock,
+	}, nil
+}
+
+// DefaultTimeFormat returns the ISO date format with the default clock
+func DefaultTimeFormat() TimeFormat {
+	return TimeFormat{
+		datePattern: DefaultDatePattern,
+		dateLayout:  "2006-01-02",
+		clock:       ClockDefault,
+	}
+}
+
+// parseDatePattern converts a date pattern into a Go time layout
+func parseDatePattern(pattern string) (string, error) {
+	var layout strings.Builder
+	hasToken := false
+
+	for rest := pattern; rest != ""; {
+		matched := false
+		for _, t := range datePatternTokens {
+			if strings.HasPrefix(rest, t.token) {
+				layout.WriteString(t.layout)
+				rest = rest[len(t.token):]
+				matched, hasToken = true, true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+
+		if !strings.ContainsRune(datePatternSeparators, rune(rest[0])) {
+			return "", fmt.Errorf("unsupported date format %q, use YYYY, YY, MMM, MM, DD and ddd separated by %q", pattern, datePatternSeparators)
+		}
+		layout.WriteByte(rest[0])
+		rest = rest[1:]
+	}
+
+	if !hasToken {
+		return "", fmt.Errorf("date format %q has no date tokens", pattern)
+	}
+	return layout.String(), nil
+}
+
+// DatePattern returns the configured date pattern (e.g. "YYYY-MM-DD")
+func (f TimeFormat) DatePattern() string {
+	return f.datePattern
+}
+
+// Clock returns the configured clock
+func (f TimeFormat) Clock() Clock {
+	return f.clock
+}
+
+// DateLayout returns the Go time layout of the date
+func (f TimeFormat) DateLayout() string {
+	return f.dateLayout
+}
+
+// TimeLayout returns the Go time layout of the time of day with seconds
+func (f TimeFormat) TimeLayout() string {
+	if f.clock == Clock12Hour {
+		return "3:04:05 PM"
+	}
+	return "15:04:05"
+}
+
+// ShortTimeLayout returns the Go time layout of the time of day without seconds
+func (f TimeFormat) ShortTimeLayout() string {
+	if f.clock == Clock12Hour {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// DateTimeLayout returns the Go time layout of the date followed by the time of day
+func (f TimeFormat) DateTimeLayout() string {
+	return f.dateLayout + " " + f.TimeLayout()
+}
+
+// FormatHour renders an hour (0-23) as a block label, "5am" style unless the 24-hour clock is chosen
+func (f TimeFormat) FormatHour(hour int) string {
+	if f.clock == Clock24Hour {
+		return fmt.Sprintf("%02d:00", hour)
+	}
+
+	switch {
+	case hour == 0:
+		return "12am"
+	case hour < 12:
+		return fmt.Sprintf("%dam", hour)
+	case hour == 12:
+		return "12pm"
+	default:
+		return fmt.Sprintf("%dpm", hour-12)
+	}
+}
@@ -0,0 +1,122 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimeFormat(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 9, 4, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		datePattern      string
+		clock            Clock
+		expectedDate     string
+		expectedDateTime string
+		expectError      bool
+	}{
+		{
+			name:             "defaults",
+			expectedDate:     "2025-09-04",
+			expectedDateTime: "2025-09-04 15:04:05",
+		},
+		{
+			name:             "day first with 12-hour clock",
+			datePattern:      "DD/MM/YYYY",
+			clock:            Clock12Hour,
+			expectedDate:     "04/09/2025",
+			expectedDateTime: "04/09/2025 3:04:05 PM",
+		},
+		{
+			name:             "month and weekday names",
+			datePattern:      "ddd, DD MMM YY",
+			clock:            Clock24Hour,
+			expectedDate:     "Thu, 04 Sep 25",
+			expectedDateTime: "Thu, 04 Sep 25 15:04:05",
+		},
+		{
+			name:             "dotted",
+			datePattern:      "DD.MM.YYYY",
+			expectedDate:     "04.09.2025",
+			expectedDateTime: "04.09.2025 15:04:05",
+		},
+		{
+			name:        "unsupported token",
+			datePattern: "%Y-%m-%d",
+			expectError: true,
+		},
+		{
+			name:        "digits are not separators",
+			datePattern: "YYYY-MM-01",
+			expectError: true,
+		},
+		{
+			name:        "separators only",
+			datePattern: "--",
+			expectError: true,
+		},
+		{
+			name:        "unsupported clock",
+			clock:       Clock("am/pm"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := NewTimeFormat(tt.datePattern, tt.clock)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if got := at.Format(format.DateLayout()); got != tt.expectedDate {
+				t.Errorf("Expected date %q, got %q", tt.expectedDate, got)
+			}
+			if got := at.Format(format.DateTimeLayout()); got != tt.expectedDateTime {
+				t.Errorf("Expected date time %q, got %q", tt.expectedDateTime, got)
+			}
+		})
+	}
+}
+
+func TestTimeFormat_FormatHour(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		clock    Clock
+		hour     int
+		expected string
+	}{
+		{ClockDefault, 0, "12am"},
+		{ClockDefault, 5, "5am"},
+		{ClockDefault, 12, "12pm"},
+		{ClockDefault, 23, "11pm"},
+		{Clock12Hour, 15, "3pm"},
+		{Clock24Hour, 0, "00:00"},
+		{Clock24Hour, 5, "05:00"},
+		{Clock24Hour, 23, "23:00"},
+	}
+
+	for _, tt := range tests {
+		format, err := NewTimeFormat("", tt.clock)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := format.FormatHour(tt.hour); got != tt.expected {
+			t.Errorf("FormatHour(%d) with clock %q = %q, want %q", tt.hour, tt.clock, got, tt.expected)
+		}
+	}
+
+	if got := DefaultTimeFormat().FormatHour(5); got != "5am" {
+		t.Errorf("Expected the default format to keep am/pm block labels, got %q", got)
+	}
+}
@@ -1,11 +1,17 @@
 package entity
 
+import (
+	"fmt"
+	"math"
+)
+
 // Token represents token usage for an API request
 type Token struct {
 	input         int64
 	output        int64
 	cacheRead     int64
 	cacheCreation int64
+	toolUse       int64 // part of the output spent on tool calls, zero when telemetry does not report it
 }
 
 // NewToken creates a new Token value object
@@ -38,6 +44,30 @@ func (t Token) CacheCreation() int64 {
 	return t.cacheCreation
 }
 
+// ToolUse returns the number of output tokens spent on tool calls
+func (t Token) ToolUse() int64 {
+	return t.toolUse
+}
+
+// TextOutput returns the number of output tokens spent on message text
+func (t Token) TextOutput() int64 {
+	return t.output - t.toolUse
+}
+
+// ToolUseShare returns the percentage of output tokens spent on tool calls, 0 without output
+func (t Token) ToolUseShare() float64 {
+	if t.output == 0 {
+		return 0
+	}
+	return float64(t.toolUse) / float64(t.output) * 100
+}
+
+// WithToolUse returns a copy of the token with the given tool call output tokens, clamped to the output
+func (t Token) WithToolUse(toolUse int64) Token {
+	t.toolUse = min(max(toolUse, 0), t.output)
+	return t
+}
+
 // Total returns the total number of tokens
 func (t Token) Total() int64 {
 	return t.input + t.output + t.cacheRead + t.cacheCreation
@@ -60,5 +90,33 @@ func (t Token) Add(other Token) Token {
 		output:        t.output + other.output,
 		cacheRead:     t.cacheRead + other.cacheRead,
 		cacheCreation: t.cacheCreation + other.cacheCreation,
+		toolUse:       t.toolUse + other.toolUse,
+	}
+}
+
+// Scale returns a new Token with every count multiplied by the factor, rounded to whole tokens
+func (t Token) Scale(factor float64) Token {
+	scale := func(count int64) int64 {
+		return int64(math.Round(float64(count) * factor))
+	}
+
+	return Token{
+		input:         scale(t.input),
+		output:        scale(t.output),
+		cacheRead:     scale(t.cacheRead),
+		cacheCreation: scale(t.cacheCreation),
+		toolUse:       scale(t.toolUse),
+	}
+}
+
+// FormatTokenCount formats a token count compactly, e.g. "950", "12.3K" or "1.25M"
+func FormatTokenCount(tokens int64) string {
+	switch {
+	case tokens >= 1000000:
+		return fmt.Sprintf("%.2fM", float64(tokens)/1000000)
+	case tokens >= 1000:
+		return fmt.Sprintf("%.1fK", float64(tokens)/1000)
+	default:
+		return fmt.Sprintf("%d", tokens)
 	}
 }
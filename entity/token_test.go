@@ -0,0 +1,112 @@
+package entity
+
+import "testing"
+
+func TestToken_WithToolUse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		token           Token
+		toolUse         int64
+		expectedToolUse int64
+		expectedText    int64
+		expectedShare   float64
+	}{
+		{
+			name:            "not reported",
+			token:           NewToken(100, 200, 0, 0),
+			toolUse:         0,
+			expectedToolUse: 0,
+			expectedText:    200,
+			expectedShare:   0,
+		},
+		{
+			name:            "part of the output",
+			token:           NewToken(100, 200, 0, 0),
+			toolUse:         150,
+			expectedToolUse: 150,
+			expectedText:    50,
+			expectedShare:   75,
+		},
+		{
+			name:            "clamped to the output",
+			token:           NewToken(100, 200, 0, 0),
+			toolUse:         500,
+			expectedToolUse: 200,
+			expectedText:    0,
+			expectedShare:   100,
+		},
+		{
+			name:            "negative is ignored",
+			token:           NewToken(100, 200, 0, 0),
+			toolUse:         -10,
+			expectedToolUse: 0,
+			expectedText:    200,
+			expectedShare:   0,
+		},
+		{
+			name:            "no output",
+			token:           NewToken(100, 0, 0, 0),
+			toolUse:         10,
+			expectedToolUse: 0,
+			expectedText:    0,
+			expectedShare:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			token := tt.token.WithToolUse(tt.toolUse)
+
+			if token.ToolUse() != tt.expectedToolUse {
+				t.Errorf("ToolUse() = %d, want %d", token.ToolUse(), tt.expectedToolUse)
+			}
+			if token.TextOutput() != tt.expectedText {
+				t.Errorf("TextOutput() = %d, want %d", token.TextOutput(), tt.expectedText)
+			}
+			if token.ToolUseShare() != tt.expectedShare {
+				t.Errorf("ToolUseShare() = %v, want %v", token.ToolUseShare(), tt.expectedShare)
+			}
+			if token.Total() != tt.token.Total() {
+				t.Errorf("Total() = %d, want %d unchanged by the split", token.Total(), tt.token.Total())
+			}
+		})
+	}
+}
+
+func TestToken_Add_ToolUse(t *testing.T) {
+	t.Parallel()
+
+	sum := NewToken(100, 200, 0, 0).WithToolUse(150).Add(NewToken(50, 100, 0, 0))
+
+	if sum.ToolUse() != 150 {
+		t.Errorf("ToolUse() = %d, want 150", sum.ToolUse())
+	}
+	if sum.TextOutput() != 150 {
+		t.Errorf("TextOutput() = %d, want 150", sum.TextOutput())
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	tests := []struct {
+		tokens   int64
+		expected string
+	}{
+		{tokens: 0, expected: "0"},
+		{tokens: 999, expected: "999"},
+		{tokens: 1000, expected: "1.0K"},
+		{tokens: 12345, expected: "12.3K"},
+		{tokens: 1250000, expected: "1.25M"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := FormatTokenCount(tt.tokens); got != tt.expected {
+				t.Errorf("FormatTokenCount(%d) = %q, want %q", tt.tokens, got, tt.expected)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package entity
 
 // Usage represents usage statistics grouped by periods
 type Usage struct {
-	stats []Stats
+	stats          []Stats
+	movingAverages []MovingAverage
+	previous       *Stats // the period before the oldest stats, only used for its change
 }
 
 // NewUsage creates a new Usage instance with the given stats
@@ -12,7 +14,75 @@ func NewUsage(stats []Stats) Usage {
 	}
 }
 
+// WithMovingAverages returns a copy of the usage with the moving averages, in the same order as the stats
+func (u Usage) WithMovingAverages(movingAverages []MovingAverage) Usage {
+	u.movingAverages = movingAverages
+	return u
+}
+
+// WithPrevious returns a copy of the usage knowing the stats of the period before the oldest one, so it has a change too
+func (u Usage) WithPrevious(previous Stats) Usage {
+	u.previous = &previous
+	return u
+}
+
 // GetStats returns the statistics grouped by periods
 func (u Usage) GetStats() []Stats {
 	return u.stats
 }
+
+// MovingAverageAt returns the moving average cost of the period at index i
+func (u Usage) MovingAverageAt(i int) (MovingAverage, bool) {
+	if i < 0 || i >= len(u.movingAverages) {
+		return MovingAverage{}, false
+	}
+	return u.movingAverages[i], true
+}
+
+// ChangeAt returns the change of the period at index i from the period before it
+// Stats are newest first, so the period before is the next one, the oldest period needs WithPrevious
+func (u Usage) ChangeAt(i int) (UsageChange, bool) {
+	if i < 0 || i >= len(u.stats) {
+		return UsageChange{}, false
+	}
+
+	switch {
+	case i+1 < len(u.stats):
+		return NewUsageChange(u.stats[i].RateLimitedCost(), u.stats[i+1].RateLimitedCost()), true
+	case u.previous != nil:
+		return NewUsageChange(u.stats[i].RateLimitedCost(), u.previous.RateLimitedCost()), true
+	}
+	return UsageChange{}, false
+}
+
+// UsageChange compares the premium and 1M context cost of a period with the period before it, e.g. week over week
+type UsageChange struct {
+	current  Cost
+	previous Cost
+}
+
+// NewUsageChange creates a new UsageChange from the premium and 1M context cost of a period and the period before it
+func NewUsageChange(current, previous Cost) UsageChange {
+	return UsageChange{
+		current:  current,
+		previous: previous,
+	}
+}
+
+// Previous returns the premium cost of the period before
+func (c UsageChange) Previous() Cost {
+	return c.previous
+}
+
+// Delta returns how much more the period cost than the period before, negative when it cost less
+func (c UsageChange) Delta() Cost {
+	return NewCost(c.current.Amount() - c.previous.Amount())
+}
+
+// Percent returns the delta relative to the period before, false when the period before cost nothing
+func (c UsageChange) Percent() (float64, bool) {
+	if c.previous.Amount() == 0 {
+		return 0, false
+	}
+	return c.Delta().Amount() / c.previous.Amount() * 100, true
+}
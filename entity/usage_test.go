@@ -38,3 +38,49 @@ func TestUsageGetStats(t *testing.T) {
 		t.Errorf("Expected 0 stats, got %d", len(usage.GetStats()))
 	}
 }
+
+func TestUsage_ChangeAt(t *testing.T) {
+	period := NewPeriod(time.Now().Add(-time.Hour), time.Now())
+	statsWithCost := func(cost float64) Stats {
+		return NewStats(0, 1, Token{}, NewToken(100, 50, 0, 0), Cost{}, NewCost(cost), period)
+	}
+
+	// Newest first: this week, last week and the week before
+	usage := NewUsage([]Stats{statsWithCost(15), statsWithCost(10), statsWithCost(0)})
+
+	change, ok := usage.ChangeAt(0)
+	if !ok {
+		t.Fatal("Expected a change of the newest period")
+	}
+	if change.Delta().Amount() != 5 || change.Previous().Amount() != 10 {
+		t.Errorf("Expected a delta of 5 from 10, got %.2f from %.2f", change.Delta().Amount(), change.Previous().Amount())
+	}
+	if percent, ok := change.Percent(); !ok || percent != 50 {
+		t.Errorf("Expected +50%%, got %.2f (%v)", percent, ok)
+	}
+
+	// The period before cost nothing, so there is no percentage
+	change, _ = usage.ChangeAt(1)
+	if _, ok := change.Percent(); ok {
+		t.Error("Expected no percentage from a period without cost")
+	}
+
+	if _, ok := usage.ChangeAt(2); ok {
+		t.Error("Expected no change of the oldest period without the previous stats")
+	}
+	change, ok = usage.WithPrevious(statsWithCost(4)).ChangeAt(2)
+	if !ok || change.Delta().Amount() != -4 {
+		t.Errorf("Expected a delta of -4 from the previous stats, got %.2f (%v)", change.Delta().Amount(), ok)
+	}
+
+	if _, ok := usage.ChangeAt(3); ok {
+		t.Error("Expected no change out of range")
+	}
+
+	// 1M context cost counts with the premium cost
+	longContext := statsWithCost(10).WithLongContext(1, NewToken(100, 50, 0, 0), NewCost(6))
+	change, _ = NewUsage([]Stats{longContext, statsWithCost(10)}).ChangeAt(0)
+	if change.Delta().Amount() != 6 {
+		t.Errorf("Expected a delta of 6 from the 1M context cost, got %.2f", change.Delta().Amount())
+	}
+}
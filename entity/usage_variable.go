@@ -12,6 +12,35 @@ var (
 	MonthlyCostVariable      = UsageVariable{name: "Monthly Cost", key: "@monthly_cost"}
 	DailyPlanUsageVariable   = UsageVariable{name: "Daily Plan Usage", key: "@daily_plan_usage"}
 	MonthlyPlanUsageVariable = UsageVariable{name: "Monthly Plan Usage", key: "@monthly_plan_usage"}
+
+	DailyBudgetLeftVariable   = UsageVariable{name: "Daily Budget Left", key: "@daily_budget_left"}
+	MonthlyBudgetLeftVariable = UsageVariable{name: "Monthly Budget Left", key: "@monthly_budget_left"}
+
+	DailyLongContextCostVariable   = UsageVariable{name: "Daily Long Context Cost", key: "@daily_long_context_cost"}
+	MonthlyLongContextCostVariable = UsageVariable{name: "Monthly Long Context Cost", key: "@monthly_long_context_cost"}
+
+	DailyToolTokensVariable   = UsageVariable{name: "Daily Tool Use Tokens", key: "@daily_tool_tokens"}
+	MonthlyToolTokensVariable = UsageVariable{name: "Monthly Tool Use Tokens", key: "@monthly_tool_tokens"}
+	DailyToolShareVariable    = UsageVariable{name: "Daily Tool Use Share", key: "@daily_tool_share"}
+
+	CostPerKiloTokenVariable = UsageVariable{name: "Daily Cost per 1K Tokens", key: "@cost_per_ktok"}
+
+	PrevBlockUsageVariable = UsageVariable{name: "Previous Block Usage", key: "@prev_block_usage"}
+	BlockVsPrevVariable    = UsageVariable{name: "Block vs Previous", key: "@block_vs_prev"}
+	BlockTimeLeftVariable  = UsageVariable{name: "Block Time Left", key: "@block_time_left"}
+	BlockEndAtVariable     = UsageVariable{name: "Block End At", key: "@block_end_at"}
+
+	BlockUsageVariable           = UsageVariable{name: "Block Usage", key: "@block_usage"}
+	BlockTokensRemainingVariable = UsageVariable{name: "Block Tokens Remaining", key: "@block_tokens_remaining"}
+	BlockTimeRemainingVariable   = UsageVariable{name: "Block Time Remaining", key: "@block_time_remaining"}
+	BlockLimitVariable           = UsageVariable{name: "Block Limit", key: "@block_limit"}
+
+	StreakVariable = UsageVariable{name: "Goal Streak", key: "@streak"}
+
+	CostAnomalyVariable = UsageVariable{name: "Cost Anomaly", key: "@cost_anomaly"}
+
+	ProjectDailyCostVariable   = UsageVariable{name: "Project Daily Cost", key: "@project_daily_cost"}
+	ProjectMonthlyCostVariable = UsageVariable{name: "Project Monthly Cost", key: "@project_monthly_cost"}
 )
 
 // GetAllUsageVariables returns all available predefined variables
@@ -21,6 +50,26 @@ func GetAllUsageVariables() []UsageVariable {
 		MonthlyCostVariable,
 		DailyPlanUsageVariable,
 		MonthlyPlanUsageVariable,
+		DailyBudgetLeftVariable,
+		MonthlyBudgetLeftVariable,
+		DailyLongContextCostVariable,
+		MonthlyLongContextCostVariable,
+		DailyToolTokensVariable,
+		MonthlyToolTokensVariable,
+		DailyToolShareVariable,
+		CostPerKiloTokenVariable,
+		PrevBlockUsageVariable,
+		BlockVsPrevVariable,
+		BlockTimeLeftVariable,
+		BlockEndAtVariable,
+		BlockUsageVariable,
+		BlockTokensRemainingVariable,
+		BlockTimeRemainingVariable,
+		BlockLimitVariable,
+		StreakVariable,
+		CostAnomalyVariable,
+		ProjectDailyCostVariable,
+		ProjectMonthlyCostVariable,
 	}
 }
 
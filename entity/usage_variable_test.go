@@ -35,6 +35,108 @@ func TestUsageVariableDefinitions(t *testing.T) {
 			wantKey:  "@monthly_plan_usage",
 			wantName: "Monthly Plan Usage",
 		},
+		{
+			name:     "daily long context cost variable",
+			variable: DailyLongContextCostVariable,
+			wantKey:  "@daily_long_context_cost",
+			wantName: "Daily Long Context Cost",
+		},
+		{
+			name:     "monthly long context cost variable",
+			variable: MonthlyLongContextCostVariable,
+			wantKey:  "@monthly_long_context_cost",
+			wantName: "Monthly Long Context Cost",
+		},
+		{
+			name:     "daily tool use tokens variable",
+			variable: DailyToolTokensVariable,
+			wantKey:  "@daily_tool_tokens",
+			wantName: "Daily Tool Use Tokens",
+		},
+		{
+			name:     "monthly tool use tokens variable",
+			variable: MonthlyToolTokensVariable,
+			wantKey:  "@monthly_tool_tokens",
+			wantName: "Monthly Tool Use Tokens",
+		},
+		{
+			name:     "daily tool use share variable",
+			variable: DailyToolShareVariable,
+			wantKey:  "@daily_tool_share",
+			wantName: "Daily Tool Use Share",
+		},
+		{
+			name:     "previous block usage variable",
+			variable: PrevBlockUsageVariable,
+			wantKey:  "@prev_block_usage",
+			wantName: "Previous Block Usage",
+		},
+		{
+			name:     "block vs previous variable",
+			variable: BlockVsPrevVariable,
+			wantKey:  "@block_vs_prev",
+			wantName: "Block vs Previous",
+		},
+		{
+			name:     "block time left variable",
+			variable: BlockTimeLeftVariable,
+			wantKey:  "@block_time_left",
+			wantName: "Block Time Left",
+		},
+		{
+			name:     "block end at variable",
+			variable: BlockEndAtVariable,
+			wantKey:  "@block_end_at",
+			wantName: "Block End At",
+		},
+		{
+			name:     "block usage variable",
+			variable: BlockUsageVariable,
+			wantKey:  "@block_usage",
+			wantName: "Block Usage",
+		},
+		{
+			name:     "block tokens remaining variable",
+			variable: BlockTokensRemainingVariable,
+			wantKey:  "@block_tokens_remaining",
+			wantName: "Block Tokens Remaining",
+		},
+		{
+			name:     "block time remaining variable",
+			variable: BlockTimeRemainingVariable,
+			wantKey:  "@block_time_remaining",
+			wantName: "Block Time Remaining",
+		},
+		{
+			name:     "block limit variable",
+			variable: BlockLimitVariable,
+			wantKey:  "@block_limit",
+			wantName: "Block Limit",
+		},
+		{
+			name:     "daily budget left variable",
+			variable: DailyBudgetLeftVariable,
+			wantKey:  "@daily_budget_left",
+			wantName: "Daily Budget Left",
+		},
+		{
+			name:     "monthly budget left variable",
+			variable: MonthlyBudgetLeftVariable,
+			wantKey:  "@monthly_budget_left",
+			wantName: "Monthly Budget Left",
+		},
+		{
+			name:     "goal streak variable",
+			variable: StreakVariable,
+			wantKey:  "@streak",
+			wantName: "Goal Streak",
+		},
+		{
+			name:     "project daily cost variable",
+			variable: ProjectDailyCostVariable,
+			wantKey:  "@project_daily_cost",
+			wantName: "Project Daily Cost",
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,8 +154,8 @@ func TestUsageVariableDefinitions(t *testing.T) {
 func TestGetAllUsageVariables(t *testing.T) {
 	variables := GetAllUsageVariables()
 
-	if len(variables) != 4 {
-		t.Errorf("Expected 4 variables, got %d", len(variables))
+	if len(variables) != 24 {
+		t.Errorf("Expected 24 variables, got %d", len(variables))
 	}
 
 	expectedKeys := map[string]bool{
@@ -61,6 +163,35 @@ func TestGetAllUsageVariables(t *testing.T) {
 		"@monthly_cost":       false,
 		"@daily_plan_usage":   false,
 		"@monthly_plan_usage": false,
+
+		"@daily_budget_left":   false,
+		"@monthly_budget_left": false,
+
+		"@daily_long_context_cost":   false,
+		"@monthly_long_context_cost": false,
+
+		"@daily_tool_tokens":   false,
+		"@monthly_tool_tokens": false,
+		"@daily_tool_share":    false,
+
+		"@cost_per_ktok": false,
+
+		"@prev_block_usage": false,
+		"@block_vs_prev":    false,
+		"@block_time_left":  false,
+		"@block_end_at":     false,
+
+		"@block_usage":            false,
+		"@block_tokens_remaining": false,
+		"@block_time_remaining":   false,
+		"@block_limit":            false,
+
+		"@streak": false,
+
+		"@cost_anomaly": false,
+
+		"@project_daily_cost":   false,
+		"@project_monthly_cost": false,
 	}
 
 	for _, v := range variables {
@@ -0,0 +1,166 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+)
+
+// UserQuotaStatus tells how a user's daily cost compares to their quota
+type UserQuotaStatus string
+
+const (
+	UserQuotaUnlimited UserQuotaStatus = "unlimited" // no quota applies to the user
+	UserQuotaOK        UserQuotaStatus = "ok"        // daily cost is within the quota
+	UserQuotaExceeded  UserQuotaStatus = "exceeded"  // daily cost is above the quota
+)
+
+// UserQuotas holds the daily cost quotas of individual users
+// Users are matched case-insensitively, a zero quota means the user is unlimited
+type UserQuotas struct {
+	defaultDaily Cost
+	users        map[string]Cost
+}
+
+// NewUserQuotas creates the user quotas, defaultDaily applies to every user without an own quota
+func NewUserQuotas(defaultDaily Cost, users map[string]Cost) UserQuotas {
+	normalized := make(map[string]Cost, len(users))
+	for user, quota := range users {
+		normalized[strings.ToLower(user)] = quota
+	}
+
+	return UserQuotas{
+		defaultDaily: defaultDaily,
+		users:        normalized,
+	}
+}
+
+// DefaultDaily returns the daily quota of users without an own quota
+func (q UserQuotas) DefaultDaily() Cost {
+	return q.defaultDaily
+}
+
+// DailyFor returns the daily quota of the user, zero when the user is unlimited
+// Requests without user attribution are never capped, they can't be told apart by user
+func (q UserQuotas) DailyFor(user string) Cost {
+	if user == "" {
+		return Cost{}
+	}
+	if quota, ok := q.users[strings.ToLower(user)]; ok {
+		return quota
+	}
+	return q.defaultDaily
+}
+
+// Users returns the users with an own quota, sorted by name
+func (q UserQuotas) Users() []string {
+	users := make([]string, 0, len(q.users))
+	for user := range q.users {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// UserUsage is the daily and block usage of a single user, checked against the user's daily quota
+type UserUsage struct {
+	user  string
+	daily Stats
+	block Stats
+	quota Cost
+}
+
+// NewUserUsage creates the usage of the user
+func NewUserUsage(user string, daily Stats, block Stats, quota Cost) UserUsage {
+	return UserUsage{
+		user:  user,
+		daily: daily,
+		block: block,
+		quota: quota,
+	}
+}
+
+// User returns the user, empty for requests without user attribution
+func (u UserUsage) User() string {
+	return u.user
+}
+
+// Daily returns the stats of the user in the current day
+func (u UserUsage) Daily() Stats {
+	return u.daily
+}
+
+// Block returns the stats of the user in the current block, empty when no block is tracked
+func (u UserUsage) Block() Stats {
+	return u.block
+}
+
+// Quota returns the daily quota of the user, zero when the user is unlimited
+func (u UserUsage) Quota() Cost {
+	return u.quota
+}
+
+// Status returns how the daily cost compares to the quota
+func (u UserUsage) Status() UserQuotaStatus {
+	switch {
+	case u.quota.Amount() <= 0:
+		return UserQuotaUnlimited
+	case u.daily.TotalCost().Amount() > u.quota.Amount():
+		return UserQuotaExceeded
+	default:
+		return UserQuotaOK
+	}
+}
+
+// NewUserUsages groups the daily and block requests by user, sorted by user
+// Users with an own quota are listed even without requests, so their remaining quota is visible
+func NewUserUsages(daily []APIRequest, dailyPeriod Period, block []APIRequest, blockPeriod Period, quotas UserQuotas) []UserUsage {
+	dailyByUser := groupByUser(daily)
+	blockByUser := groupByUser(block)
+
+	users := make(map[string]struct{}, len(dailyByUser))
+	for user := range dailyByUser {
+		users[user] = struct{}{}
+	}
+	for user := range blockByUser {
+		users[user] = struct{}{}
+	}
+	for _, user := range quotas.Users() {
+		if !hasUserFold(users, user) {
+			users[user] = struct{}{}
+		}
+	}
+
+	usages := make([]UserUsage, 0, len(users))
+	for user := range users {
+		usages = append(usages, NewUserUsage(
+			user,
+			NewStatsFromRequests(dailyByUser[user], dailyPeriod),
+			NewStatsFromRequests(blockByUser[user], blockPeriod),
+			quotas.DailyFor(user),
+		))
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].user < usages[j].user
+	})
+	return usages
+}
+
+// groupByUser groups the requests by their user
+func groupByUser(requests []APIRequest) map[string][]APIRequest {
+	grouped := make(map[string][]APIRequest)
+	for _, req := range requests {
+		grouped[req.User()] = append(grouped[req.User()], req)
+	}
+	return grouped
+}
+
+// hasUserFold returns true if the user is in the set, ignoring case
+func hasUserFold(users map[string]struct{}, user string) bool {
+	for existing := range users {
+		if strings.EqualFold(existing, user) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserQuotas_DailyFor(t *testing.T) {
+	t.Parallel()
+
+	quotas := NewUserQuotas(NewCost(10), map[string]Cost{
+		"Alice@example.com": NewCost(25),
+		"ci@example.com":    NewCost(0),
+	})
+
+	tests := []struct {
+		name     string
+		user     string
+		expected float64
+	}{
+		{
+			name:     "own quota",
+			user:     "alice@example.com",
+			expected: 25,
+		},
+		{
+			name:     "own quota is matched ignoring case",
+			user:     "ALICE@example.com",
+			expected: 25,
+		},
+		{
+			name:     "zero own quota overrides the default",
+			user:     "ci@example.com",
+			expected: 0,
+		},
+		{
+			name:     "default quota",
+			user:     "bob@example.com",
+			expected: 10,
+		},
+		{
+			name:     "requests without a user are unlimited",
+			user:     "",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quotas.DailyFor(tt.user).Amount(); got != tt.expected {
+				t.Errorf("DailyFor(%q) = %v, want %v", tt.user, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUserUsage_Status(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	period := NewPeriod(timestamp.Add(-time.Hour), timestamp.Add(time.Hour))
+	requests := []APIRequest{
+		NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(12.5), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		quota    float64
+		expected UserQuotaStatus
+	}{
+		{
+			name:     "no quota",
+			quota:    0,
+			expected: UserQuotaUnlimited,
+		},
+		{
+			name:     "within quota",
+			quota:    12.5,
+			expected: UserQuotaOK,
+		},
+		{
+			name:     "above quota",
+			quota:    10,
+			expected: UserQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := NewUserUsage("alice@example.com", NewStatsFromRequests(requests, period), Stats{}, NewCost(tt.quota))
+			if got := usage.Status(); got != tt.expected {
+				t.Errorf("Status() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewUserUsages(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	dailyPeriod := NewPeriod(timestamp.Add(-10*time.Hour), timestamp.Add(14*time.Hour))
+	blockPeriod := NewPeriod(timestamp.Add(-time.Hour), timestamp.Add(4*time.Hour))
+
+	alice := NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", NewToken(100, 50, 0, 0), NewCost(3), 1000).WithUser("alice@example.com")
+	aliceEarlier := NewAPIRequest("session-1", timestamp.Add(-5*time.Hour), "claude-sonnet-4-20250514", NewToken(200, 100, 0, 0), NewCost(4), 1000).WithUser("alice@example.com")
+	anonymous := NewAPIRequest("session-2", timestamp, "claude-sonnet-4-20250514", NewToken(10, 5, 0, 0), NewCost(1), 1000)
+
+	quotas := NewUserQuotas(NewCost(5), map[string]Cost{"carol@example.com": NewCost(20)})
+	usages := NewUserUsages(
+		[]APIRequest{alice, aliceEarlier, anonymous},
+		dailyPeriod,
+		[]APIRequest{alice, anonymous},
+		blockPeriod,
+		quotas,
+	)
+
+	if len(usages) != 3 {
+		t.Fatalf("Expected 3 users, got %d", len(usages))
+	}
+
+	// Sorted by user, requests without a user come first
+	expected := []struct {
+		user        string
+		dailyCost   float64
+		blockTokens int64
+		status      UserQuotaStatus
+	}{
+		{user: "", dailyCost: 1, blockTokens: 15, status: UserQuotaUnlimited},
+		{user: "alice@example.com", dailyCost: 7, blockTokens: 150, status: UserQuotaExceeded},
+		{user: "carol@example.com", dailyCost: 0, blockTokens: 0, status: UserQuotaOK},
+	}
+
+	for i, want := range expected {
+		got := usages[i]
+		if got.User() != want.user {
+			t.Errorf("usages[%d].User() = %q, want %q", i, got.User(), want.user)
+		}
+		if got.Daily().TotalCost().Amount() != want.dailyCost {
+			t.Errorf("usages[%d] daily cost = %v, want %v", i, got.Daily().TotalCost().Amount(), want.dailyCost)
+		}
+		if got.Block().RateLimitedTokens().Limited() != want.blockTokens {
+			t.Errorf("usages[%d] block tokens = %d, want %d", i, got.Block().RateLimitedTokens().Limited(), want.blockTokens)
+		}
+		if got.Status() != want.status {
+			t.Errorf("usages[%d].Status() = %q, want %q", i, got.Status(), want.status)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// exportParams are the command line options of the export command
+type exportParams struct {
+	options   cli.ExportOptions
+	stateFile string // remembers the last exported record for differential exports
+	output    string // file to write to, empty for stdout
+	local     bool   // read the database file instead of asking the server
+}
+
+// runExport writes the stored requests as JSON or CSV and returns the exit code
+func runExport(config *Config, params exportParams) int {
+	if err := cli.ValidateExportFormat(params.options.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if params.options.SinceLast && params.stateFile == "" {
+		fmt.Fprintf(os.Stderr, "--state-file is required with --since-last\n")
+		return 1
+	}
+
+	var apiRepo usecase.APIRequestRepository
+	if params.local {
+		// The server holds the database lock, a local export needs it stopped
+		db, err := NewDatabaseReadOnly(config.Database.Path)
+		if errors.Is(err, ErrDatabaseLocked) {
+			fmt.Fprintf(os.Stderr, "A running ccmon server is using %s.\n", config.Database.Path)
+			fmt.Fprintf(os.Stderr, "Export without --local to read it through the server.\n")
+			return 1
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if err := db.Close(); err != nil {
+				log.Printf("Error closing database: %v", err)
+			}
+		}()
+		apiRepo = repository.NewBoltDBAPIRequestRepository(db)
+	} else {
+		grpcRepo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+			return 1
+		}
+		defer func() {
+			if err := grpcRepo.Close(); err != nil {
+				log.Printf("Error closing gRPC repository: %v", err)
+			}
+		}()
+		apiRepo = grpcRepo
+	}
+
+	var stateRepo usecase.ExportStateRepository
+	if params.options.SinceLast {
+		stateRepo = repository.NewFileExportStateRepository(params.stateFile)
+	}
+
+	var out io.Writer = os.Stdout
+	if params.output != "" {
+		file, err := os.Create(params.output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %s: %v\n", params.output, err)
+			return 1
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				log.Printf("Error closing %s: %v", params.output, err)
+			}
+		}()
+		out = file
+	}
+
+	handler := cli.NewExportHandler(usecase.NewExportApiRequestsCommand(apiRepo, stateRepo))
+	exported, err := handler.HandleExport(params.options, time.Now(), out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+
+	// stdout only carries records, so the summary goes to stderr
+	fmt.Fprintf(os.Stderr, "Exported %d records\n", exported)
+	return 0
+}
@@ -1,3 +1,3 @@
-//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/query.proto
+//go:generate protoc --go_out=. --go_opt=module=github.com/elct9620/ccmon --go-grpc_out=. --go-grpc_opt=module=github.com/elct9620/ccmon api/v1/query.proto api/v1/replication.proto api/v1/star.proto
 
 package main
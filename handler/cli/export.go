@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Supported export formats
+const (
+	ExportFormatJSONLines = "jsonl" // one JSON object per line, appends cleanly for differential exports
+	ExportFormatJSON      = "json"  // a single JSON array
+	ExportFormatCSV       = "csv"   // a header row followed by one row per request
+)
+
+// exportSettleDelay leaves the latest requests for the next differential export
+// Exporters deliver events a few seconds late, a request timestamped before the last exported one would be skipped for good
+const exportSettleDelay = time.Minute
+
+// exportCSVHeader lists the CSV columns, in the order of exportRecord.csvRow
+var exportCSVHeader = []string{
+	"id", "session_id", "timestamp", "model",
+	"input_tokens", "output_tokens", "cache_read_tokens", "cache_creation_tokens", "tool_use_tokens", "total_tokens",
+	"cost_usd", "duration_ms", "source", "origin", "user", "project",
+}
+
+// exportRecord is a single request of the export output
+type exportRecord struct {
+	ID                  string    `json:"id"`
+	SessionID           string    `json:"session_id"`
+	Timestamp           time.Time `json:"timestamp"`
+	Model               string    `json:"model"`
+	InputTokens         int64     `json:"input_tokens"`
+	OutputTokens        int64     `json:"output_tokens"`
+	CacheReadTokens     int64     `json:"cache_read_tokens"`
+	CacheCreationTokens int64     `json:"cache_creation_tokens"`
+	ToolUseTokens       int64     `json:"tool_use_tokens"`
+	TotalTokens         int64     `json:"total_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+	DurationMS          int64     `json:"duration_ms"`
+	Source              string    `json:"source"`
+	Origin              string    `json:"origin"`
+	User                string    `json:"user,omitempty"`
+	Project             string    `json:"project,omitempty"`
+}
+
+// ExportOptions selects which requests are exported and how
+type ExportOptions struct {
+	Format    string // one of the ExportFormat constants, empty for JSON lines
+	Span      string // counts back from now, e.g. "30d", "12h" or "all", empty for all
+	SinceLast bool   // only the requests added since the last differential export
+}
+
+// ExportHandler writes stored API requests as JSON or CSV for reporting and ingestion into external systems
+type ExportHandler struct {
+	exportCommand *usecase.ExportApiRequestsCommand
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(exportCommand *usecase.ExportApiRequestsCommand) *ExportHandler {
+	return &ExportHandler{
+		exportCommand: exportCommand,
+	}
+}
+
+// ValidateExportFormat returns an error for unsupported export formats
+func ValidateExportFormat(format string) error {
+	switch format {
+	case "", ExportFormatJSONLines, ExportFormatJSON, ExportFormatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q, expected %s, %s or %s", format, ExportFormatJSONLines, ExportFormatJSON, ExportFormatCSV)
+	}
+}
+
+// HandleExport writes the requests to out newest first and returns how many were written
+// A differential export only writes the requests added since the last one, except the ones of the last minute
+func (h *ExportHandler) HandleExport(options ExportOptions, now time.Time, out io.Writer) (int, error) {
+	if err := ValidateExportFormat(options.Format); err != nil {
+		return 0, err
+	}
+
+	params := usecase.ExportApiRequestsParams{
+		Until:     now.UTC(),
+		SinceLast: options.SinceLast,
+	}
+	if options.SinceLast {
+		if options.Span != "" {
+			return 0, fmt.Errorf("a period cannot be combined with a differential export")
+		}
+		params.Until = params.Until.Add(-exportSettleDelay)
+	}
+	if options.Span != "" {
+		period, err := parseSpan(options.Span, now)
+		if err != nil {
+			return 0, err
+		}
+		if !period.IsAllTime() {
+			params.Since = period.StartAt()
+		}
+	}
+
+	w := &exportWriter{out: bufio.NewWriter(out), format: options.Format}
+	result, err := h.exportCommand.Execute(context.Background(), params, w.write)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.close(); err != nil {
+		return 0, err
+	}
+	return result.Exported, nil
+}
+
+// exportWriter encodes the pages of an export in the format as they are read
+type exportWriter struct {
+	out     *bufio.Writer
+	format  string
+	written int
+}
+
+// write encodes the requests after the ones already written and flushes them to the output
+func (w *exportWriter) write(requests []entity.APIRequest) error {
+	for _, req := range requests {
+		if err := w.writeRecord(newExportRecord(req)); err != nil {
+			return err
+		}
+		w.written++
+	}
+	return w.out.Flush()
+}
+
+// writeRecord encodes a record, preceded by the CSV header or the opening of the JSON array for the first one
+func (w *exportWriter) writeRecord(record exportRecord) error {
+	switch w.format {
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w.out)
+		if w.written == 0 {
+			if err := writer.Write(exportCSVHeader); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(record.csvRow()); err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	case ExportFormatJSON:
+		data, err := json.MarshalIndent(record, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		separator := ",\n  "
+		if w.written == 0 {
+			separator = "[\n  "
+		}
+		if _, err := w.out.WriteString(separator); err != nil {
+			return err
+		}
+		_, err = w.out.Write(data)
+		return err
+	default:
+		return json.NewEncoder(w.out).Encode(record)
+	}
+}
+
+// close completes the output, an empty CSV keeps its header and an empty JSON array is still valid
+func (w *exportWriter) close() error {
+	switch {
+	case w.format == ExportFormatCSV && w.written == 0:
+		writer := csv.NewWriter(w.out)
+		if err := writer.Write(exportCSVHeader); err != nil {
+			return err
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	case w.format == ExportFormatJSON && w.written == 0:
+		if _, err := w.out.WriteString("[]\n"); err != nil {
+			return err
+		}
+	case w.format == ExportFormatJSON:
+		if _, err := w.out.WriteString("\n]\n"); err != nil {
+			return err
+		}
+	}
+	return w.out.Flush()
+}
+
+// newExportRecord maps an API request to its export record
+func newExportRecord(req entity.APIRequest) exportRecord {
+	tokens := req.Tokens()
+	return exportRecord{
+		ID:                  req.ID(),
+		SessionID:           req.SessionID(),
+		Timestamp:           req.Timestamp().UTC(),
+		Model:               req.Model().String(),
+		InputTokens:         tokens.Input(),
+		OutputTokens:        tokens.Output(),
+		CacheReadTokens:     tokens.CacheRead(),
+		CacheCreationTokens: tokens.CacheCreation(),
+		ToolUseTokens:       tokens.ToolUse(),
+		TotalTokens:         tokens.Total(),
+		CostUSD:             req.Cost().Amount(),
+		DurationMS:          req.DurationMS(),
+		Source:              req.Source(),
+		Origin:              req.Origin(),
+		User:                req.User(),
+		Project:             req.Project(),
+	}
+}
+
+// csvRow returns the record as CSV fields, costs keep their full precision for spreadsheets to sum
+func (r exportRecord) csvRow() []string {
+	return []string{
+		r.ID,
+		r.SessionID,
+		r.Timestamp.Format(time.RFC3339Nano),
+		r.Model,
+		strconv.FormatInt(r.InputTokens, 10),
+		strconv.FormatInt(r.OutputTokens, 10),
+		strconv.FormatInt(r.CacheReadTokens, 10),
+		strconv.FormatInt(r.CacheCreationTokens, 10),
+		strconv.FormatInt(r.ToolUseTokens, 10),
+		strconv.FormatInt(r.TotalTokens, 10),
+		strconv.FormatFloat(r.CostUSD, 'f', -1, 64),
+		strconv.FormatInt(r.DurationMS, 10),
+		r.Source,
+		r.Origin,
+		r.User,
+		r.Project,
+	}
+}
@@ -0,0 +1,145 @@
+package cli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestExportHandler_HandleExport(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-sonnet-4-20250514", 1000, 500, 1.25).WithUser("alice"),
+		// Within the settle delay, left for the next differential export
+		testutil.CreateTestAPIRequest("session-2", now.Add(-10*time.Second), "claude-sonnet-4-20250514", 2000, 1000, 2.50),
+	})
+	stateRepo := repository.NewFileExportStateRepository(filepath.Join(t.TempDir(), ".ccmon-export.state"))
+	handler := cli.NewExportHandler(usecase.NewExportApiRequestsCommand(repo, stateRepo))
+
+	var out bytes.Buffer
+	exported, err := handler.HandleExport(cli.ExportOptions{SinceLast: true}, now, &out)
+	if err != nil {
+		t.Fatalf("HandleExport() error = %v", err)
+	}
+	if exported != 1 {
+		t.Fatalf("HandleExport() exported %d records, want 1", exported)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("HandleExport() wrote invalid JSON %q: %v", out.String(), err)
+	}
+	if record["session_id"] != "session-1" || record["user"] != "alice" || record["total_tokens"] != float64(1500) {
+		t.Errorf("HandleExport() wrote %v", record)
+	}
+	if !strings.HasSuffix(record["id"].(string), "_session-1") {
+		t.Errorf("HandleExport() wrote id %v, want the request ID", record["id"])
+	}
+
+	// The next run picks up the settled request only
+	out.Reset()
+	exported, err = handler.HandleExport(cli.ExportOptions{SinceLast: true}, now.Add(time.Minute), &out)
+	if err != nil {
+		t.Fatalf("HandleExport() error = %v", err)
+	}
+	if exported != 1 || !strings.Contains(out.String(), `"session_id":"session-2"`) {
+		t.Errorf("HandleExport() exported %d records %q, want only session-2", exported, out.String())
+	}
+}
+
+func TestExportHandler_HandleExportFormats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-40*24*time.Hour), "claude-sonnet-4-20250514", 1000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", 2000, 1000, 0.123456),
+	}
+
+	tests := []struct {
+		name     string
+		options  cli.ExportOptions
+		exported int
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "csv of the last 30 days",
+			options:  cli.ExportOptions{Format: cli.ExportFormatCSV, Span: "30d"},
+			exported: 1,
+			expected: []string{
+				"id,session_id,timestamp,model,input_tokens,output_tokens,cache_read_tokens,cache_creation_tokens,tool_use_tokens,total_tokens,cost_usd,duration_ms,source,origin,user,project\n",
+				"2025-06-30T10:00:00Z_session-2,session-2,2025-06-30T10:00:00Z,claude-sonnet-4-20250514,2000,1000,0,0,0,3000,0.123456,",
+			},
+		},
+		{
+			name:     "json array of all requests",
+			options:  cli.ExportOptions{Format: cli.ExportFormatJSON, Span: "all"},
+			exported: 2,
+			expected: []string{"[\n", `"session_id": "session-1"`, `"session_id": "session-2"`},
+		},
+		{
+			name:     "empty csv keeps the header",
+			options:  cli.ExportOptions{Format: cli.ExportFormatCSV, Span: "1h"},
+			expected: []string{"id,session_id,"},
+		},
+		{
+			name:    "unsupported format",
+			options: cli.ExportOptions{Format: "xlsx"},
+			errMsg:  `unsupported export format "xlsx"`,
+		},
+		{
+			name:    "invalid span",
+			options: cli.ExportOptions{Span: "month"},
+			errMsg:  `invalid span "month"`,
+		},
+		{
+			name:    "span with a differential export",
+			options: cli.ExportOptions{Span: "30d", SinceLast: true},
+			errMsg:  "cannot be combined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(requests)
+			stateRepo := repository.NewFileExportStateRepository(filepath.Join(t.TempDir(), ".ccmon-export.state"))
+			handler := cli.NewExportHandler(usecase.NewExportApiRequestsCommand(repo, stateRepo))
+
+			var out bytes.Buffer
+			exported, err := handler.HandleExport(tt.options, now, &out)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("HandleExport() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HandleExport() error = %v", err)
+			}
+			if exported != tt.exported {
+				t.Errorf("HandleExport() exported %d records, want %d", exported, tt.exported)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("HandleExport() output does not contain %q:\n%s", expected, out.String())
+				}
+			}
+		})
+	}
+}
@@ -385,6 +385,7 @@ func TestVariableSubstitutionEdgeCases(t *testing.T) {
 		name           string
 		formatString   string
 		expectedOutput string
+		wantErr        bool
 	}{
 		{
 			name:           "no variables in format string",
@@ -426,6 +427,11 @@ func TestVariableSubstitutionEdgeCases(t *testing.T) {
 			formatString:   "@daily_cost",
 			expectedOutput: "$30.0",
 		},
+		{
+			name:           "email address is not a variable",
+			formatString:   "alice@example.com: @daily_cost",
+			expectedOutput: "alice@example.com: $30.0",
+		},
 		{
 			name:           "unknown variable should not be substituted",
 			formatString:   "@unknown_variable remains @unknown_variable",
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// MetricDefaultPrecision lets each metric use its own number of decimals
+const MetricDefaultPrecision = -1
+
+// metricUnit tells how a metric value is decorated when it is not printed raw
+type metricUnit int
+
+const (
+	metricUnitCost    metricUnit = iota // dollars, e.g. "$1.20"
+	metricUnitCount                     // whole numbers with thousands separators, e.g. "12,345"
+	metricUnitPercent                   // percentages, e.g. "15.0%"
+)
+
+// metric reads a single value from the stats of a period
+type metric struct {
+	unit  metricUnit
+	value func(stats entity.Stats, block *entity.Block) float64
+}
+
+// metrics of the query metric command by name, each is read from the stats of the period
+var metrics = map[string]metric{
+	"cost": {metricUnitCost, func(stats entity.Stats, _ *entity.Block) float64 {
+		return stats.TotalCost().Amount()
+	}},
+	"tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalTokens().Total())
+	}},
+	"input_tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalTokens().Input())
+	}},
+	"output_tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalTokens().Output())
+	}},
+	"cache_read_tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalTokens().CacheRead())
+	}},
+	"cache_creation_tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalTokens().CacheCreation())
+	}},
+	"premium_tokens": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.PremiumTokens().Total())
+	}},
+	"requests": {metricUnitCount, func(stats entity.Stats, _ *entity.Block) float64 {
+		return float64(stats.TotalRequests())
+	}},
+	"block_usage": {metricUnitPercent, func(stats entity.Stats, block *entity.Block) float64 {
+		return block.CalculateProgress(stats.RateLimitedTokens())
+	}},
+}
+
+// metricPeriodPrefixes are the prefixes of shorthand metric names and their period, e.g. daily_cost
+var metricPeriodPrefixes = map[string]string{
+	"hourly_":  StatsPeriodHour,
+	"daily_":   StatsPeriodDay,
+	"weekly_":  StatsPeriodWeek,
+	"monthly_": StatsPeriodMonth,
+}
+
+// MetricHandler prints a single metric for scripts, e.g. the cost of today as a bare number
+type MetricHandler struct {
+	calculateStatsQuery *usecase.CalculateStatsQuery
+	timezone            *time.Location
+	block               *entity.Block
+	costFormat          entity.CostFormat
+	origin              string
+}
+
+// NewMetricHandler creates a new MetricHandler, block is optional and must contain the queried point in time
+func NewMetricHandler(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location, block *entity.Block, costFormat entity.CostFormat) *MetricHandler {
+	return &MetricHandler{
+		calculateStatsQuery: calculateStatsQuery,
+		timezone:            timezone,
+		block:               block,
+		costFormat:          costFormat,
+	}
+}
+
+// SetOrigin limits the metric to requests of the origin, e.g. entity.OriginLive to exclude imported records
+func (h *MetricHandler) SetOrigin(origin string) {
+	h.origin = origin
+}
+
+// HandleMetric writes the metric for the period as it was at the point in time to w
+// An empty period uses the period of a shorthand name like daily_cost, or the day
+// Raw values are bare numbers for shell arithmetic, MetricDefaultPrecision uses the decimals of the metric
+func (h *MetricHandler) HandleMetric(name string, period string, at time.Time, raw bool, precision int, w io.Writer) error {
+	result, err := h.Render(name, period, at, raw, precision)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, result)
+	return err
+}
+
+// Render builds the metric value for the period as it was at the point in time
+func (h *MetricHandler) Render(name string, period string, at time.Time, raw bool, precision int) (string, error) {
+	m, period, err := h.resolve(name, period)
+	if err != nil {
+		return "", err
+	}
+
+	statsPeriod, err := statsPeriodAt(period, at, h.timezone, h.block)
+	if err != nil {
+		return "", entity.NewError(entity.ErrorKindInvalidPeriod, err.Error(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: statsPeriod, Origin: h.origin})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate stats: %w", err)
+	}
+
+	return h.format(m, m.value(stats, h.block), raw, precision), nil
+}
+
+// resolve returns the metric of the name and the period it is read for
+func (h *MetricHandler) resolve(name string, period string) (metric, string, error) {
+	measure, namePeriod := name, ""
+	for prefix, prefixPeriod := range metricPeriodPrefixes {
+		if after, ok := strings.CutPrefix(name, prefix); ok {
+			measure, namePeriod = after, prefixPeriod
+			break
+		}
+	}
+
+	m, ok := metrics[measure]
+	if !ok || (namePeriod != "" && measure == "block_usage") {
+		return metric{}, "", entity.NewError(entity.ErrorKindInvalidFormat, fmt.Sprintf("unknown metric %q, expected one of %s", name, strings.Join(MetricNames(), ", ")), nil)
+	}
+
+	switch {
+	case measure == "block_usage":
+		// The usage is always the share of the current block limit
+		if h.block == nil || !h.block.HasLimit() {
+			return metric{}, "", fmt.Errorf("metric %q requires the --block start time and a token limit", name)
+		}
+		if period != "" && period != StatsPeriodBlock {
+			return metric{}, "", entity.NewError(entity.ErrorKindInvalidPeriod, fmt.Sprintf("metric %q only supports the block period", name), nil)
+		}
+		period = StatsPeriodBlock
+	case namePeriod != "":
+		if period != "" && period != namePeriod {
+			return metric{}, "", entity.NewError(entity.ErrorKindInvalidPeriod, fmt.Sprintf("metric %q is read for the %s, use %q with --period %s instead", name, namePeriod, measure, period), nil)
+		}
+		period = namePeriod
+	case period == "":
+		period = StatsPeriodDay
+	}
+
+	return m, period, nil
+}
+
+// format renders the value bare or decorated with its unit
+func (h *MetricHandler) format(m metric, value float64, raw bool, precision int) string {
+	if precision <= MetricDefaultPrecision {
+		switch m.unit {
+		case metricUnitCost:
+			precision = h.costFormat.Precision()
+		case metricUnitPercent:
+			precision = 1
+		default:
+			precision = 0
+		}
+	}
+
+	if raw {
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+
+	switch m.unit {
+	case metricUnitCost:
+		return entity.NewCostFormat(precision, h.costFormat.IsHumanized()).Format(entity.NewCost(value))
+	case metricUnitPercent:
+		return strconv.FormatFloat(value, 'f', precision, 64) + "%"
+	default:
+		if precision == 0 {
+			return formatInteger(int64(value))
+		}
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+}
+
+// MetricNames returns the names of the metrics with a period set by --period, sorted by name
+func MetricNames() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
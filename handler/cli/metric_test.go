@@ -0,0 +1,184 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestMetricHandler_Render(t *testing.T) {
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 1000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 2000, 1000, 2.50).WithOrigin(entity.OriginImport),
+		testutil.CreateTestAPIRequest("session-3", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 4000, 2000, 0.10),
+	}
+	endOfDay := time.Date(2025, 6, 1, 23, 59, 59, 999999999, time.UTC)
+	block := blockPtr(entity.NewBlockWithLimit(time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC), 10000))
+
+	tests := []struct {
+		name      string
+		metric    string
+		period    string
+		block     *entity.Block
+		origin    string
+		raw       bool
+		precision int
+		expected  string
+		errKind   entity.ErrorKind
+		errMsg    string
+	}{
+		{
+			name:      "raw daily cost",
+			metric:    "daily_cost",
+			raw:       true,
+			precision: cli.MetricDefaultPrecision,
+			expected:  "3.75",
+		},
+		{
+			name:      "raw cost with precision",
+			metric:    "daily_cost",
+			raw:       true,
+			precision: 4,
+			expected:  "3.7500",
+		},
+		{
+			name:      "decorated cost",
+			metric:    "daily_cost",
+			precision: cli.MetricDefaultPrecision,
+			expected:  "$3.75",
+		},
+		{
+			name:      "cost defaults to the day",
+			metric:    "cost",
+			raw:       true,
+			precision: cli.MetricDefaultPrecision,
+			expected:  "3.75",
+		},
+		{
+			name:      "cost with period",
+			metric:    "cost",
+			period:    cli.StatsPeriodAll,
+			raw:       true,
+			precision: 3,
+			expected:  "3.750",
+		},
+		{
+			name:      "decorated tokens",
+			metric:    "daily_tokens",
+			precision: cli.MetricDefaultPrecision,
+			expected:  "4,500",
+		},
+		{
+			name:      "raw tokens",
+			metric:    "daily_tokens",
+			raw:       true,
+			precision: cli.MetricDefaultPrecision,
+			expected:  "4500",
+		},
+		{
+			name:      "live requests",
+			metric:    "daily_requests",
+			origin:    entity.OriginLive,
+			raw:       true,
+			precision: cli.MetricDefaultPrecision,
+			expected:  "1",
+		},
+		{
+			name:      "block usage",
+			metric:    "block_usage",
+			block:     block,
+			raw:       true,
+			precision: cli.MetricDefaultPrecision,
+			expected:  "30.0",
+		},
+		{
+			name:      "decorated block usage",
+			metric:    "block_usage",
+			block:     block,
+			precision: 0,
+			expected:  "30%",
+		},
+		{
+			name:      "block usage without block",
+			metric:    "block_usage",
+			precision: cli.MetricDefaultPrecision,
+			errMsg:    "requires the --block start time",
+		},
+		{
+			name:      "unknown metric",
+			metric:    "dialy_cost",
+			precision: cli.MetricDefaultPrecision,
+			errKind:   entity.ErrorKindInvalidFormat,
+			errMsg:    "unknown metric",
+		},
+		{
+			name:      "conflicting period",
+			metric:    "daily_cost",
+			period:    cli.StatsPeriodMonth,
+			precision: cli.MetricDefaultPrecision,
+			errKind:   entity.ErrorKindInvalidPeriod,
+			errMsg:    `use "cost" with --period month`,
+		},
+		{
+			name:      "unsupported period",
+			metric:    "cost",
+			period:    "year",
+			precision: cli.MetricDefaultPrecision,
+			errKind:   entity.ErrorKindInvalidPeriod,
+			errMsg:    "unsupported stats period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			handler := cli.NewMetricHandler(calculateStatsQuery, time.UTC, tt.block, entity.DefaultCostFormat())
+			handler.SetOrigin(tt.origin)
+			result, err := handler.Render(tt.metric, tt.period, endOfDay, tt.raw, tt.precision)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Render() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				if kind := entity.ErrorKindOf(err); kind != tt.errKind {
+					t.Errorf("Render() error kind = %q, want %q", kind, tt.errKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() returned error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMetricHandler_Render_LongContextBlockUsage(t *testing.T) {
+	// 1M context tokens count against the block limit like in @block_usage and the monitor
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 19, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 1000, 500, 0.25),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514[1m]", 2000, 500, 1.50),
+	}
+	_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	block := blockPtr(entity.NewBlockWithLimit(time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC), 10000))
+
+	handler := cli.NewMetricHandler(calculateStatsQuery, time.UTC, block, entity.DefaultCostFormat())
+	result, err := handler.Render("block_usage", "", time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC), true, cli.MetricDefaultPrecision)
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	if result != "40.0" {
+		t.Errorf("Render() = %q, want %q", result, "40.0")
+	}
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page layout in points for the text PDF writer
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 40
+)
+
+// pdfLine is a single line of monospaced text, an empty text adds vertical space
+type pdfLine struct {
+	text string
+	size float64
+	bold bool
+}
+
+// renderTextPDF writes a minimal PDF 1.4 document of monospaced text lines using the built-in Courier fonts
+// It avoids a PDF dependency for simple documents like statements, so only ASCII text is supported
+func renderTextPDF(lines []pdfLine) []byte {
+	pages := paginatePDFLines(lines)
+
+	var buf bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are fixed, each page adds a page and a content object starting at 5
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		content := renderPDFPageContent(page)
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	return buf.Bytes()
+}
+
+// paginatePDFLines splits lines into pages by their line height
+func paginatePDFLines(lines []pdfLine) [][]pdfLine {
+	available := float64(pdfPageHeight - 2*pdfMargin)
+
+	pages := [][]pdfLine{{}}
+	used := 0.0
+	for _, line := range lines {
+		height := pdfLineHeight(line)
+		if used+height > available && len(pages[len(pages)-1]) > 0 {
+			pages = append(pages, []pdfLine{})
+			used = 0
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], line)
+		used += height
+	}
+
+	return pages
+}
+
+// renderPDFPageContent renders the content stream placing each line below the previous one
+func renderPDFPageContent(lines []pdfLine) string {
+	var content strings.Builder
+	y := float64(pdfPageHeight - pdfMargin)
+	for _, line := range lines {
+		y -= pdfLineHeight(line)
+		if line.text == "" {
+			continue
+		}
+
+		font := "F1"
+		if line.bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&content, "BT /%s %.1f Tf %d %.1f Td (%s) Tj ET\n", font, line.size, pdfMargin, y, escapePDFText(line.text))
+	}
+
+	return strings.TrimSuffix(content.String(), "\n")
+}
+
+// pdfLineHeight returns the vertical space taken by a line
+func pdfLineHeight(line pdfLine) float64 {
+	return line.size * 1.4
+}
+
+// escapePDFText escapes PDF string delimiters and replaces characters outside ASCII
+func escapePDFText(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		case r < 32 || r > 126:
+			escaped.WriteRune('?')
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+
+	return escaped.String()
+}
@@ -1,21 +1,79 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// ErrQuotaExceeded is returned after printing the output when the hard daily quota is exceeded
+var ErrQuotaExceeded = errors.New("hard daily quota exceeded")
+
+// Exit codes of the format query mode, so scripts can tell a stopped server from a typo in the format string
+const (
+	ExitCodeError         = 1 // unexpected errors
+	ExitCodeQuotaExceeded = 2 // the output is printed, but quota.hard_daily is exceeded
+	ExitCodeInvalidFormat = 3
+	ExitCodeConnection    = 4
+	ExitCodeAuth          = 5
+	ExitCodeNotFound      = 6
+	ExitCodeInvalidPeriod = 7
 )
 
+// ExitCode returns the exit code of the format query mode for the error, 0 without an error
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, ErrQuotaExceeded) {
+		return ExitCodeQuotaExceeded
+	}
+
+	switch entity.ErrorKindOf(err) {
+	case entity.ErrorKindInvalidFormat:
+		return ExitCodeInvalidFormat
+	case entity.ErrorKindConnection:
+		return ExitCodeConnection
+	case entity.ErrorKindAuth:
+		return ExitCodeAuth
+	case entity.ErrorKindNotFound:
+		return ExitCodeNotFound
+	case entity.ErrorKindInvalidPeriod:
+		return ExitCodeInvalidPeriod
+	default:
+		return ExitCodeError
+	}
+}
+
 type QueryHandler struct {
-	renderer *FormatRenderer
+	renderer     *FormatRenderer
+	quotaQuery   *usecase.CheckQuotaQuery
+	quotaWarning string
 }
 
 func NewQueryHandler(renderer *FormatRenderer) *QueryHandler {
+	return NewQueryHandlerWithQuota(renderer, nil, "")
+}
+
+// NewQueryHandlerWithQuota creates a QueryHandler which prefixes the output with the warning once the quota is exceeded
+func NewQueryHandlerWithQuota(renderer *FormatRenderer, quotaQuery *usecase.CheckQuotaQuery, quotaWarning string) *QueryHandler {
 	return &QueryHandler{
-		renderer: renderer,
+		renderer:     renderer,
+		quotaQuery:   quotaQuery,
+		quotaWarning: quotaWarning,
 	}
 }
 
 func (h *QueryHandler) HandleFormatQuery(formatString string) error {
 	result, err := h.processFormat(formatString)
+	if err == nil {
+		result, err = h.applyQuota(result)
+	}
 	h.outputResult(result, err)
 	return err
 }
@@ -25,11 +83,33 @@ func (h *QueryHandler) processFormat(formatString string) (string, error) {
 	return h.renderer.Render(formatString)
 }
 
-func (h *QueryHandler) outputResult(result string, err error) {
+// applyQuota prefixes the warning when the quota is exceeded, the output is still printed with ErrQuotaExceeded
+func (h *QueryHandler) applyQuota(result string) (string, error) {
+	if h.quotaQuery == nil {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := h.quotaQuery.Execute(ctx)
 	if err != nil {
+		return "", err
+	}
+
+	if status.Exceeded {
+		return h.quotaWarning + " " + result, ErrQuotaExceeded
+	}
+	return result, nil
+}
+
+func (h *QueryHandler) outputResult(result string, err error) {
+	if err != nil && !errors.Is(err, ErrQuotaExceeded) {
 		// Output consistent error message for all failure scenarios
 		// This provides graceful degradation as specified in requirements
 		fmt.Print("❌ ERROR")
+		// Status bars only show stdout, the explanation goes to stderr for people running it by hand
+		fmt.Fprintf(os.Stderr, "\n%s\n", entity.ErrorMessage(err))
 	} else {
 		fmt.Print(result)
 	}
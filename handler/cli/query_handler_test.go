@@ -0,0 +1,123 @@
+package cli_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	fn()
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close pipe: %v", err)
+	}
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	return string(output)
+}
+
+func TestQueryHandler_HardDailyQuota(t *testing.T) {
+	tests := []struct {
+		name           string
+		hardDaily      float64
+		expectedOutput string
+		expectedErr    error
+	}{
+		{
+			name:           "quota disabled",
+			hardDaily:      0,
+			expectedOutput: "$30.0",
+		},
+		{
+			name:           "below quota",
+			hardDaily:      50,
+			expectedOutput: "$30.0",
+		},
+		{
+			name:           "quota exceeded",
+			hardDaily:      25,
+			expectedOutput: "STOP $30.0",
+			expectedErr:    cli.ErrQuotaExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := []entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", time.Now().UTC(), "claude-sonnet-4-20250514", 1000, 500, 30.0),
+			}
+			_, mockStatsRepo := testutil.NewMockRepositoryWithData(requests)
+
+			periodFactory := service.NewTimePeriodFactory(time.UTC)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(mockStatsRepo, &service.NoOpStatsCache{})
+			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(calculateStatsQuery, testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))), periodFactory)
+
+			quota := entity.NewQuota(entity.NewCost(tt.hardDaily), "STOP")
+			checkQuotaQuery := usecase.NewCheckQuotaQuery(calculateStatsQuery, periodFactory, quota)
+			queryHandler := cli.NewQueryHandlerWithQuota(cli.NewFormatRenderer(usageVariablesQuery), checkQuotaQuery, quota.Warning())
+
+			var err error
+			output := captureStdout(t, func() {
+				err = queryHandler.HandleFormatQuery("@daily_cost")
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if output != tt.expectedOutput {
+				t.Errorf("Expected output %q, got %q", tt.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "success", err: nil, expected: 0},
+		{name: "quota exceeded", err: cli.ErrQuotaExceeded, expected: cli.ExitCodeQuotaExceeded},
+		{name: "bad format string", err: entity.NewError(entity.ErrorKindInvalidFormat, "unknown metric \"dialy_cost\"", nil), expected: cli.ExitCodeInvalidFormat},
+		{name: "server down", err: fmt.Errorf("failed to calculate daily stats: %w", entity.NewError(entity.ErrorKindConnection, "cannot connect to the ccmon server", errors.New("connection refused"))), expected: cli.ExitCodeConnection},
+		{name: "rejected credentials", err: entity.NewError(entity.ErrorKindAuth, "rejected", nil), expected: cli.ExitCodeAuth},
+		{name: "not found", err: entity.NewError(entity.ErrorKindNotFound, "not found", nil), expected: cli.ExitCodeNotFound},
+		{name: "invalid period", err: entity.NewError(entity.ErrorKindInvalidPeriod, "invalid period", nil), expected: cli.ExitCodeInvalidPeriod},
+		{name: "unexpected error", err: errors.New("disk full"), expected: cli.ExitCodeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := cli.ExitCode(tt.err); code != tt.expected {
+				t.Errorf("ExitCode() = %d, want %d", code, tt.expected)
+			}
+		})
+	}
+}
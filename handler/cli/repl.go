@@ -0,0 +1,348 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// REPL defaults when a command leaves out the span or the count
+const (
+	replPrompt          = "ccmon> "
+	replDefaultSpan     = "1d"
+	replDefaultSessions = 5
+	replDefaultRequests = 20
+)
+
+// replTimeLayout is used for request timestamps in the REPL output
+const replTimeLayout = "2006-01-02 15:04:05"
+
+// replHelp lists the REPL commands
+const replHelp = `Commands:
+  stats [span]                          usage summary, e.g. stats 7d
+  top sessions [count] [span]           most expensive sessions, e.g. top sessions 5 30d
+  requests [key=value...] [limit N] [span]
+                                        latest requests, e.g. requests model=opus limit 20
+  help                                  show this help
+  exit                                  leave the REPL
+
+Spans count back from now in d, h or m (e.g. 7d, 12h), "all" covers every request, default 1d.
+Request filters: model (part of the model name), session, source, origin, user, project.
+`
+
+// errReplExit is returned by Execute when the user leaves the REPL
+var errReplExit = errors.New("exit")
+
+// ReplHandler answers interactive queries with tables, sharing the queries of the monitor
+type ReplHandler struct {
+	calculateStatsQuery *usecase.CalculateStatsQuery
+	getFilteredQuery    *usecase.GetFilteredApiRequestsQuery
+	timezone            *time.Location
+	costFormat          entity.CostFormat
+}
+
+// NewReplHandler creates a new ReplHandler
+func NewReplHandler(calculateStatsQuery *usecase.CalculateStatsQuery, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, timezone *time.Location, costFormat entity.CostFormat) *ReplHandler {
+	return &ReplHandler{
+		calculateStatsQuery: calculateStatsQuery,
+		getFilteredQuery:    getFilteredQuery,
+		timezone:            timezone,
+		costFormat:          costFormat,
+	}
+}
+
+// HandleRepl reads commands from in until exit or end of input and writes the results to out
+// A failed command prints its error and the REPL keeps reading
+func (h *ReplHandler) HandleRepl(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		if _, err := fmt.Fprint(out, replPrompt); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			// End of input, leave the prompt on its own line
+			_, err := fmt.Fprintln(out)
+			if err == nil {
+				err = scanner.Err()
+			}
+			return err
+		}
+
+		result, err := h.Execute(scanner.Text(), time.Now())
+		if errors.Is(err, errReplExit) {
+			return nil
+		}
+		if err != nil {
+			result = fmt.Sprintf("Error: %v\n", err)
+		}
+		if _, err := fmt.Fprint(out, result); err != nil {
+			return err
+		}
+	}
+}
+
+// Execute runs a single REPL command as of now and returns its output
+func (h *ReplHandler) Execute(line string, now time.Time) (string, error) {
+	args := strings.Fields(line)
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	switch args[0] {
+	case "stats":
+		return h.stats(args[1:], now)
+	case "top":
+		if len(args) < 2 || args[1] != "sessions" {
+			return "", fmt.Errorf("unknown top resource, expected: top sessions [count] [span]")
+		}
+		return h.topSessions(args[2:], now)
+	case "requests":
+		return h.requests(args[1:], now)
+	case "help":
+		return replHelp, nil
+	case "exit", "quit":
+		return "", errReplExit
+	default:
+		return "", fmt.Errorf("unknown command %q, type help for the commands", args[0])
+	}
+}
+
+// stats renders the usage summary of the span
+func (h *ReplHandler) stats(args []string, now time.Time) (string, error) {
+	if len(args) > 1 {
+		return "", fmt.Errorf("usage: stats [span]")
+	}
+	span := replDefaultSpan
+	if len(args) == 1 {
+		span = args[0]
+	}
+	period, err := parseSpan(span, now)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: period})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate stats: %w", err)
+	}
+
+	table := statementTable{
+		headers: []string{"Category", "Requests", "Tokens", "Cost"},
+		rows: [][]string{
+			h.statsRow("Base", stats.BaseRequests(), stats.BaseTokens(), stats.BaseCost()),
+			h.statsRow("Premium", stats.PremiumRequests(), stats.PremiumTokens(), stats.PremiumCost()),
+		},
+		footer: h.statsRow("Total", stats.TotalRequests(), stats.TotalTokens(), stats.TotalCost()),
+	}
+	if stats.LongContextRequests() > 0 {
+		table.rows = append(table.rows, h.statsRow("Long context", stats.LongContextRequests(), stats.LongContextTokens(), stats.LongContextCost()))
+	}
+	return renderTextTable(table), nil
+}
+
+// topSessions renders the most expensive sessions of the span
+func (h *ReplHandler) topSessions(args []string, now time.Time) (string, error) {
+	if len(args) > 2 {
+		return "", fmt.Errorf("usage: top sessions [count] [span]")
+	}
+
+	count := replDefaultSessions
+	span := replDefaultSpan
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n <= 0 {
+				return "", fmt.Errorf("session count must be positive: %s", arg)
+			}
+			count = n
+			continue
+		}
+		span = arg
+	}
+	period, err := parseSpan(span, now)
+	if err != nil {
+		return "", err
+	}
+
+	requests, err := h.findRequests(entity.NewFilter(period))
+	if err != nil {
+		return "", err
+	}
+
+	table := statementTable{
+		headers: []string{"Session", "Requests", "Tokens", "Cost", "Last seen"},
+		empty:   "No sessions in this span",
+	}
+	for _, session := range entity.TopSessionsByCost(entity.NewSessionsFromRequests(requests), count) {
+		table.rows = append(table.rows, []string{
+			session.Name(),
+			formatInteger(int64(session.Requests())),
+			formatInteger(session.Tokens().Total()),
+			h.costFormat.Format(session.Cost()),
+			h.formatTime(session.LastSeen()),
+		})
+	}
+	return renderTextTable(table), nil
+}
+
+// requests renders the latest requests of the span matching the filters, newest first
+func (h *ReplHandler) requests(args []string, now time.Time) (string, error) {
+	limit := replDefaultRequests
+	span := replDefaultSpan
+	var model string
+	filter := entity.Filter{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "limit" {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("limit requires a number")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("limit must be a positive number: %s", args[i])
+			}
+			limit = n
+			continue
+		}
+
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			span = arg
+			continue
+		}
+		switch key {
+		case "model":
+			// Models are matched by part of their name, the filter only matches exact names
+			model = value
+		case "session":
+			filter = filter.WithSessionID(value)
+		case "source":
+			filter = filter.WithSource(value)
+		case "origin":
+			if _, err := entity.ParseOrigin(value); err != nil {
+				return "", err
+			}
+			filter = filter.WithOrigin(value)
+		case "user":
+			filter = filter.WithUser(value)
+		case "project":
+			filter = filter.WithProject(value)
+		default:
+			return "", fmt.Errorf("unknown request filter %q, expected model, session, source, origin, user or project", key)
+		}
+	}
+
+	period, err := parseSpan(span, now)
+	if err != nil {
+		return "", err
+	}
+
+	requests, err := h.findRequests(filter.WithPeriod(period))
+	if err != nil {
+		return "", err
+	}
+
+	table := statementTable{
+		headers: []string{"Time", "Model", "Session", "Tokens", "Cost"},
+		empty:   "No requests in this span",
+		labels:  3,
+	}
+	// Requests are in chronological order, list the newest first
+	for i := len(requests) - 1; i >= 0 && len(table.rows) < limit; i-- {
+		req := requests[i]
+		if model != "" && !strings.Contains(req.Model().String(), model) {
+			continue
+		}
+		table.rows = append(table.rows, []string{
+			h.formatTime(req.Timestamp()),
+			req.Model().String(),
+			req.SessionID(),
+			formatInteger(req.Tokens().Total()),
+			h.costFormat.Format(req.Cost()),
+		})
+	}
+	return renderTextTable(table), nil
+}
+
+// findRequests returns every request matching the filter
+func (h *ReplHandler) findRequests(filter entity.Filter) ([]entity.APIRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	requests, err := h.getFilteredQuery.Execute(ctx, usecase.GetFilteredApiRequestsParams{Filter: filter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requests: %w", err)
+	}
+	return requests, nil
+}
+
+// statsRow renders a stats category as a table row
+func (h *ReplHandler) statsRow(label string, requests int, tokens entity.Token, cost entity.Cost) []string {
+	return []string{label, formatInteger(int64(requests)), formatInteger(tokens.Total()), h.costFormat.Format(cost)}
+}
+
+// formatTime formats a timestamp in the configured timezone
+func (h *ReplHandler) formatTime(t time.Time) string {
+	return t.In(h.timezone).Format(replTimeLayout)
+}
+
+// parseSpan returns the period counting back from now, e.g. "7d", "12h" or "all"
+func parseSpan(span string, now time.Time) (entity.Period, error) {
+	now = now.UTC()
+	if span == "all" {
+		return entity.NewAllTimePeriod(now), nil
+	}
+
+	var duration time.Duration
+	if days, ok := strings.CutSuffix(span, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return entity.Period{}, fmt.Errorf("invalid span %q, expected e.g. 7d, 12h or all", span)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		duration, err = time.ParseDuration(span)
+		if err != nil {
+			return entity.Period{}, fmt.Errorf("invalid span %q, expected e.g. 7d, 12h or all", span)
+		}
+	}
+	if duration <= 0 {
+		return entity.Period{}, fmt.Errorf("span must be positive: %s", span)
+	}
+
+	return entity.NewPeriodFromDuration(now, duration), nil
+}
+
+// renderTextTable renders a table as aligned text columns with a header separator
+func renderTextTable(table statementTable) string {
+	if len(table.rows) == 0 && table.empty != "" {
+		return table.empty + "\n"
+	}
+
+	widths := columnWidths(table)
+	header := alignedRow(table.headers, widths, table.labels)
+
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	b.WriteString(strings.Repeat("-", len(header)) + "\n")
+	for _, row := range table.rows {
+		b.WriteString(alignedRow(row, widths, table.labels) + "\n")
+	}
+	if table.footer != nil {
+		b.WriteString(alignedRow(table.footer, widths, table.labels) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,211 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func newTestReplHandler(requests []entity.APIRequest) *cli.ReplHandler {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	return cli.NewReplHandler(
+		usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}),
+		usecase.NewGetFilteredApiRequestsQuery(apiRepo),
+		time.UTC,
+		entity.DefaultCostFormat(),
+	)
+}
+
+func TestReplHandler_Execute(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-old", now.Add(-20*24*time.Hour), "claude-opus-4-20250514", 1000, 500, 9.00),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-3*24*time.Hour), "claude-sonnet-4-20250514", 1000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", now.Add(-2*time.Hour), "claude-opus-4-20250514", 2000, 1000, 4.50).WithUser("alice@example.com").WithProject("ccmon"),
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Hour), "claude-3-5-haiku-20241022", 4000, 2000, 0.10),
+	}
+
+	tests := []struct {
+		name       string
+		line       string
+		expected   []string
+		unexpected []string
+		errMsg     string
+	}{
+		{
+			name:     "empty line",
+			line:     "   ",
+			expected: []string{},
+		},
+		{
+			name: "stats defaults to the last day",
+			line: "stats",
+			expected: []string{
+				"Category  Requests  Tokens   Cost",
+				"Base             1   6,000  $0.10",
+				"Premium          1   3,000  $4.50",
+				"Total            2   9,000  $4.60",
+			},
+		},
+		{
+			name:     "stats of a span",
+			line:     "stats 7d",
+			expected: []string{"Total            3  10,500  $5.85"},
+		},
+		{
+			name:     "stats of all time",
+			line:     "stats all",
+			expected: []string{"Total            4  12,000  $14.85"},
+		},
+		{
+			name: "top sessions by cost",
+			line: "top sessions 2 30d",
+			expected: []string{
+				"Session      Requests  Tokens   Cost            Last seen",
+				"session-old         1   1,500  $9.00  2025-05-21 12:00:00",
+				"session-2           1   3,000  $4.50  2025-06-10 10:00:00",
+			},
+			unexpected: []string{"session-1 "},
+		},
+		{
+			name:     "top sessions without sessions in the span",
+			line:     "top sessions 10m",
+			expected: []string{"No sessions in this span"},
+		},
+		{
+			name: "requests matching part of the model name",
+			line: "requests model=opus limit 20 all",
+			expected: []string{
+				"2025-06-10 10:00:00  claude-opus-4-20250514  session-2",
+				"2025-05-21 12:00:00  claude-opus-4-20250514  session-old",
+			},
+			unexpected: []string{"sonnet", "haiku"},
+		},
+		{
+			name:       "requests are newest first up to the limit",
+			line:       "requests limit 1 7d",
+			expected:   []string{"claude-3-5-haiku-20241022"},
+			unexpected: []string{"opus", "sonnet"},
+		},
+		{
+			name:       "requests by user",
+			line:       "requests user=alice@example.com",
+			expected:   []string{"session-2"},
+			unexpected: []string{"session-1"},
+		},
+		{
+			name:       "requests by project",
+			line:       "requests project=ccmon",
+			expected:   []string{"session-2"},
+			unexpected: []string{"session-1"},
+		},
+		{
+			name:     "help",
+			line:     "help",
+			expected: []string{"top sessions [count] [span]"},
+		},
+		{
+			name:   "unknown command",
+			line:   "delete everything",
+			errMsg: `unknown command "delete"`,
+		},
+		{
+			name:   "unknown top resource",
+			line:   "top models",
+			errMsg: "unknown top resource",
+		},
+		{
+			name:   "invalid span",
+			line:   "stats yesterday",
+			errMsg: `invalid span "yesterday"`,
+		},
+		{
+			name:   "negative span",
+			line:   "stats -1d",
+			errMsg: "span must be positive",
+		},
+		{
+			name:   "unknown request filter",
+			line:   "requests cost=1",
+			errMsg: `unknown request filter "cost"`,
+		},
+		{
+			name:   "limit without number",
+			line:   "requests limit",
+			errMsg: "limit requires a number",
+		},
+		{
+			name:   "invalid origin",
+			line:   "requests origin=replay",
+			errMsg: "replay",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestReplHandler(requests)
+			result, err := handler.Execute(tt.line, now)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Execute() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() returned error: %v", err)
+			}
+
+			for _, line := range tt.expected {
+				if !strings.Contains(result, line) {
+					t.Errorf("Execute() missing %q in:\n%s", line, result)
+				}
+			}
+			for _, line := range tt.unexpected {
+				if strings.Contains(result, line) {
+					t.Errorf("Execute() unexpectedly contains %q in:\n%s", line, result)
+				}
+			}
+		})
+	}
+}
+
+func TestReplHandler_HandleRepl(t *testing.T) {
+	handler := newTestReplHandler(nil)
+
+	var out strings.Builder
+	in := strings.NewReader("stats 1d\nbogus\nexit\nstats\n")
+	if err := handler.HandleRepl(in, &out); err != nil {
+		t.Fatalf("HandleRepl() returned error: %v", err)
+	}
+
+	result := out.String()
+	if !strings.Contains(result, "Total            0       0  $0.00") {
+		t.Errorf("HandleRepl() missing stats in:\n%s", result)
+	}
+	if !strings.Contains(result, `Error: unknown command "bogus"`) {
+		t.Errorf("HandleRepl() missing error in:\n%s", result)
+	}
+	// Commands after exit are not run
+	if got := strings.Count(result, "ccmon> "); got != 3 {
+		t.Errorf("HandleRepl() printed %d prompts, want 3 in:\n%s", got, result)
+	}
+}
+
+func TestReplHandler_HandleRepl_EndOfInput(t *testing.T) {
+	handler := newTestReplHandler(nil)
+
+	var out strings.Builder
+	if err := handler.HandleRepl(strings.NewReader("help\n"), &out); err != nil {
+		t.Fatalf("HandleRepl() returned error: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "ccmon> \n") {
+		t.Errorf("HandleRepl() should end with an empty prompt line, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Report formats
+const (
+	ReportFormatMarkdown = "md"
+)
+
+// DefaultReportDays is the number of days of the daily report when --days is not given
+const DefaultReportDays = 7
+
+// maxReportDays bounds the daily report, longer histories belong in the statement or an export
+const maxReportDays = 366
+
+// dailyReportHeaders match the full width columns of the daily usage tab
+var dailyReportHeaders = []string{"Date", "Requests", "Input", "Output", "Read Cache", "Creation Cache", "Total", "Burn Rate", "Premium Cost ($)", "$/1K Tok", "7d Avg", "30d Avg"}
+
+// ReportHandler renders usage reports for pasting into notes, standups or weekly reviews
+type ReportHandler struct {
+	getUsageQuery *usecase.GetUsageQuery
+	timezone      *time.Location
+	timeFormat    entity.TimeFormat
+	costFormat    entity.CostFormat
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(getUsageQuery *usecase.GetUsageQuery, timezone *time.Location, timeFormat entity.TimeFormat, costFormat entity.CostFormat) *ReportHandler {
+	return &ReportHandler{
+		getUsageQuery: getUsageQuery,
+		timezone:      timezone,
+		timeFormat:    timeFormat,
+		costFormat:    costFormat,
+	}
+}
+
+// ValidateReportFormat returns an error for unsupported report formats
+func ValidateReportFormat(format string) error {
+	switch format {
+	case ReportFormatMarkdown:
+		return nil
+	default:
+		return fmt.Errorf("unsupported report format %q, expected %s", format, ReportFormatMarkdown)
+	}
+}
+
+// ValidateReportDays returns an error for a number of days outside of 1 to a year
+func ValidateReportDays(days int) error {
+	if days < 1 || days > maxReportDays {
+		return fmt.Errorf("invalid report days %d, expected 1 to %d", days, maxReportDays)
+	}
+	return nil
+}
+
+// HandleDailyReport writes the usage of the last days, today included, to w
+func (h *ReportHandler) HandleDailyReport(days int, format string, w io.Writer) error {
+	if err := ValidateReportFormat(format); err != nil {
+		return err
+	}
+	if err := ValidateReportDays(days); err != nil {
+		return err
+	}
+
+	// The moving averages read 29 more days than shown, allow more time than quick queries
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	usage, err := h.getUsageQuery.ListByDay(ctx, days, h.timezone)
+	if err != nil {
+		return fmt.Errorf("failed to get daily usage: %w", err)
+	}
+
+	if _, err := io.WriteString(w, h.RenderDailyMarkdown(usage)); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// RenderDailyMarkdown renders the daily usage as a markdown table, newest day first like the daily usage tab
+func (h *ReportHandler) RenderDailyMarkdown(usage entity.Usage) string {
+	var b strings.Builder
+
+	b.WriteString(markdownRow(dailyReportHeaders))
+	separators := make([]string, len(dailyReportHeaders))
+	for i := range separators {
+		// The date is a label, the others are amounts
+		separators[i] = "---:"
+		if i == 0 {
+			separators[i] = "---"
+		}
+	}
+	b.WriteString(markdownRow(separators))
+
+	for i, stat := range usage.GetStats() {
+		if stat.Period().IsAllTime() {
+			continue
+		}
+		b.WriteString(markdownRow(h.dailyRow(stat, usage, i)))
+	}
+
+	return b.String()
+}
+
+// dailyRow formats the premium and 1M context usage of a day, the same amounts as a row of the daily usage tab
+func (h *ReportHandler) dailyRow(stat entity.Stats, usage entity.Usage, index int) []string {
+	tokens := stat.RateLimitedTokens()
+
+	costPerKiloToken := "-"
+	if cost, ok := stat.RateLimitedCost().PerKiloToken(tokens); ok {
+		costPerKiloToken = fmt.Sprintf("%.4f", cost.Amount())
+	}
+
+	shortAverage, longAverage := "-", "-"
+	if average, ok := usage.MovingAverageAt(index); ok {
+		shortAverage = h.costFormat.FormatAmount(average.Short().Amount())
+		longAverage = h.costFormat.FormatAmount(average.Long().Amount())
+	}
+
+	return []string{
+		stat.Period().StartAt().In(h.timezone).Format(h.timeFormat.DateLayout()),
+		fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.RateLimitedRequests()),
+		entity.FormatTokenCount(tokens.Input()),
+		entity.FormatTokenCount(tokens.Output()),
+		entity.FormatTokenCount(tokens.CacheRead()),
+		entity.FormatTokenCount(tokens.CacheCreation()),
+		entity.FormatTokenCount(tokens.Total()),
+		formatBurnRate(stat.RateLimitedTokenBurnRate()),
+		h.costFormat.FormatAmount(stat.RateLimitedCost().Amount()),
+		costPerKiloToken,
+		shortAverage,
+		longAverage,
+	}
+}
+
+// formatBurnRate formats tokens per minute compactly (e.g. "1.2K/min"), no burn shows "-"
+func formatBurnRate(tokensPerMinute float64) string {
+	switch {
+	case tokensPerMinute <= 0:
+		return "-"
+	case tokensPerMinute >= 1000000:
+		return fmt.Sprintf("%.2fM/min", tokensPerMinute/1000000)
+	case tokensPerMinute >= 1000:
+		return fmt.Sprintf("%.1fK/min", tokensPerMinute/1000)
+	default:
+		return fmt.Sprintf("%.1f/min", tokensPerMinute)
+	}
+}
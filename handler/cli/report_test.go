@@ -0,0 +1,93 @@
+package cli_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestReportHandler_RenderDailyMarkdown(t *testing.T) {
+	day := func(date time.Time, requests ...entity.APIRequest) entity.Stats {
+		return entity.NewStatsFromRequests(requests, entity.NewPeriod(date, date.AddDate(0, 0, 1)))
+	}
+	june10 := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	june9 := time.Date(2025, 6, 9, 0, 0, 0, 0, time.UTC)
+	usage := entity.NewUsage([]entity.Stats{
+		day(june10,
+			entity.NewAPIRequest("session-a", june10.Add(9*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(2000, 1000, 4000, 500), entity.NewCost(2.5), 1000),
+			entity.NewAPIRequest("session-a", june10.Add(9*time.Hour+10*time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.05), 500),
+		),
+		// 1M context usage counts with the premium models
+		day(june9,
+			entity.NewAPIRequest("session-b", june9.Add(9*time.Hour), "claude-sonnet-4-20250514[1m]", entity.NewToken(1000, 500, 0, 0), entity.NewCost(1.5), 1000),
+		),
+	}).WithMovingAverages([]entity.MovingAverage{entity.NewMovingAverage(entity.NewCost(1.25), entity.NewCost(0.5))})
+
+	timeFormat, err := entity.NewTimeFormat("DD/MM/YYYY", entity.ClockDefault)
+	if err != nil {
+		t.Fatalf("Failed to create time format: %v", err)
+	}
+	handler := cli.NewReportHandler(nil, time.UTC, timeFormat, entity.NewCostFormat(2, false))
+
+	got := handler.RenderDailyMarkdown(usage)
+	expected := []string{
+		"| Date | Requests | Input | Output | Read Cache | Creation Cache | Total | Burn Rate | Premium Cost ($) | $/1K Tok | 7d Avg | 30d Avg |\n",
+		"| --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n",
+		"| 10/06/2025 | 1/1 | 2.0K | 1.0K | 4.0K | 500 | 7.5K | 2.1/min | 2.50 | 0.3333 | 1.25 | 0.50 |\n",
+		"| 09/06/2025 | 0/1 | 1.0K | 500 | 0 | 0 | 1.5K | 1.0/min | 1.50 | 1.0000 | - | - |\n",
+	}
+	if got != strings.Join(expected, "") {
+		t.Errorf("Unexpected report:\n%s\nwant:\n%s", got, strings.Join(expected, ""))
+	}
+}
+
+func TestReportHandler_HandleDailyReport(t *testing.T) {
+	now := time.Now()
+	apiRepo, _ := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		entity.NewAPIRequest("session-a", now, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(1.5), 1000),
+	})
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+	handler := cli.NewReportHandler(getUsageQuery, time.UTC, entity.DefaultTimeFormat(), entity.NewCostFormat(2, false))
+
+	var out bytes.Buffer
+	if err := handler.HandleDailyReport(3, cli.ReportFormatMarkdown, &out); err != nil {
+		t.Fatalf("HandleDailyReport() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected header, separator and 3 days, got %d lines:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[2], now.UTC().Format("2006-01-02")) || !strings.Contains(lines[2], "| 1.50 |") {
+		t.Errorf("Expected today with its premium cost first, got %q", lines[2])
+	}
+}
+
+func TestReportHandler_Validate(t *testing.T) {
+	handler := cli.NewReportHandler(nil, time.UTC, entity.DefaultTimeFormat(), entity.NewCostFormat(2, false))
+
+	tests := []struct {
+		name   string
+		days   int
+		format string
+	}{
+		{name: "unsupported format", days: 7, format: "csv"},
+		{name: "no days", days: 0, format: cli.ReportFormatMarkdown},
+		{name: "more than a year", days: 400, format: cli.ReportFormatMarkdown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := handler.HandleDailyReport(tt.days, tt.format, &bytes.Buffer{}); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
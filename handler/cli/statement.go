@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Statement output formats
+const (
+	StatementOutputMarkdown = "md"
+	StatementOutputPDF      = "pdf"
+)
+
+// PDF text sizes in points
+const (
+	statementTitleSize   = 16
+	statementHeadingSize = 11
+	statementBodySize    = 9
+)
+
+// statementTable is a section of the statement, rendered as a markdown table or aligned text columns
+type statementTable struct {
+	title   string
+	headers []string
+	rows    [][]string
+	footer  []string // total row, optional
+	empty   string   // shown instead of the table when there are no rows
+	labels  int      // leading columns aligned left in text output, 0 means only the first
+}
+
+// statementDocument is the format independent content of a statement
+type statementDocument struct {
+	title   string
+	details []string
+	tables  []statementTable
+}
+
+// StatementHandler renders monthly usage statements for expense reports
+type StatementHandler struct {
+	getStatementQuery *usecase.GetStatementQuery
+	costFormat        entity.CostFormat
+}
+
+// NewStatementHandler creates a new StatementHandler
+func NewStatementHandler(getStatementQuery *usecase.GetStatementQuery, costFormat entity.CostFormat) *StatementHandler {
+	return &StatementHandler{
+		getStatementQuery: getStatementQuery,
+		costFormat:        costFormat,
+	}
+}
+
+// ValidateStatementOutput returns an error for unsupported output formats
+func ValidateStatementOutput(output string) error {
+	switch output {
+	case StatementOutputMarkdown, StatementOutputPDF:
+		return nil
+	default:
+		return fmt.Errorf("unsupported statement output %q, expected %s or %s", output, StatementOutputMarkdown, StatementOutputPDF)
+	}
+}
+
+// HandleStatement builds the statement for the month and writes it to w
+func (h *StatementHandler) HandleStatement(monthStart time.Time, output string, w io.Writer) error {
+	if err := ValidateStatementOutput(output); err != nil {
+		return err
+	}
+
+	// A full month of requests is fetched, allow more time than quick queries
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	statement, err := h.getStatementQuery.Execute(ctx, usecase.GetStatementParams{MonthStart: monthStart})
+	if err != nil {
+		return fmt.Errorf("failed to build statement: %w", err)
+	}
+
+	var content []byte
+	if output == StatementOutputPDF {
+		content = h.RenderPDF(statement, time.Now())
+	} else {
+		content = []byte(h.RenderMarkdown(statement, time.Now()))
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("failed to write statement: %w", err)
+	}
+	return nil
+}
+
+// RenderMarkdown renders the statement as a markdown document
+func (h *StatementHandler) RenderMarkdown(statement entity.Statement, generatedAt time.Time) string {
+	doc := h.buildDocument(statement, generatedAt)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", doc.title)
+	for _, detail := range doc.details {
+		fmt.Fprintf(&b, "%s  \n", detail)
+	}
+
+	for _, table := range doc.tables {
+		fmt.Fprintf(&b, "\n## %s\n\n", table.title)
+		if len(table.rows) == 0 && table.empty != "" {
+			fmt.Fprintf(&b, "_%s_\n", table.empty)
+			continue
+		}
+
+		b.WriteString(markdownRow(table.headers))
+		separators := make([]string, len(table.headers))
+		for i := range separators {
+			// The first column is a label, the others are amounts
+			separators[i] = "---:"
+			if i == 0 {
+				separators[i] = "---"
+			}
+		}
+		b.WriteString(markdownRow(separators))
+		for _, row := range table.rows {
+			b.WriteString(markdownRow(row))
+		}
+		if table.footer != nil {
+			bold := make([]string, len(table.footer))
+			for i, cell := range table.footer {
+				if cell != "" {
+					bold[i] = "**" + cell + "**"
+				}
+			}
+			b.WriteString(markdownRow(bold))
+		}
+	}
+
+	return b.String()
+}
+
+// RenderPDF renders the statement as a PDF document with aligned text columns
+func (h *StatementHandler) RenderPDF(statement entity.Statement, generatedAt time.Time) []byte {
+	doc := h.buildDocument(statement, generatedAt)
+	blank := pdfLine{size: statementBodySize}
+
+	lines := []pdfLine{{text: doc.title, size: statementTitleSize, bold: true}, blank}
+	for _, detail := range doc.details {
+		lines = append(lines, pdfLine{text: detail, size: statementBodySize})
+	}
+
+	for _, table := range doc.tables {
+		lines = append(lines, blank, pdfLine{text: table.title, size: statementHeadingSize, bold: true})
+		if len(table.rows) == 0 && table.empty != "" {
+			lines = append(lines, pdfLine{text: table.empty, size: statementBodySize})
+			continue
+		}
+
+		widths := columnWidths(table)
+		lines = append(lines,
+			pdfLine{text: alignedRow(table.headers, widths, table.labels), size: statementBodySize, bold: true},
+			pdfLine{text: strings.Repeat("-", len(alignedRow(table.headers, widths, table.labels))), size: statementBodySize},
+		)
+		for _, row := range table.rows {
+			lines = append(lines, pdfLine{text: alignedRow(row, widths, table.labels), size: statementBodySize})
+		}
+		if table.footer != nil {
+			lines = append(lines, pdfLine{text: alignedRow(table.footer, widths, table.labels), size: statementBodySize, bold: true})
+		}
+	}
+
+	return renderTextPDF(lines)
+}
+
+// buildDocument collects the statement sections shared by all output formats
+func (h *StatementHandler) buildDocument(statement entity.Statement, generatedAt time.Time) statementDocument {
+	monthStart := statement.MonthStart()
+	location := monthStart.Location()
+	monthEnd := monthStart.AddDate(0, 1, -1)
+	total := statement.Total()
+
+	doc := statementDocument{
+		title: fmt.Sprintf("Claude Usage Statement - %s", monthStart.Format("January 2006")),
+		details: []string{
+			fmt.Sprintf("Period: %s to %s (%s)", monthStart.Format("2006-01-02"), monthEnd.Format("2006-01-02"), location),
+			fmt.Sprintf("Generated: %s", generatedAt.In(location).Format("2006-01-02 15:04 MST")),
+		},
+	}
+
+	// Summary by model category
+	summary := statementTable{
+		title:   "Summary",
+		headers: []string{"Category", "Requests", "Tokens", "Cost"},
+		rows: [][]string{
+			h.statsRow("Base (Haiku)", total.BaseRequests(), total.BaseTokens(), total.BaseCost()),
+			h.statsRow("Premium (Sonnet/Opus)", total.PremiumRequests(), total.PremiumTokens(), total.PremiumCost()),
+		},
+		footer: h.statsRow("Total", total.TotalRequests(), total.TotalTokens(), total.TotalCost()),
+	}
+	if total.LongContextRequests() > 0 {
+		summary.rows = append(summary.rows, h.statsRow("Long context (1M)", total.LongContextRequests(), total.LongContextTokens(), total.LongContextCost()))
+	}
+	doc.tables = append(doc.tables, summary)
+
+	doc.tables = append(doc.tables, h.planTable(statement))
+
+	// Daily line items with the trailing average cost, days without usage are left out
+	daily := statementTable{
+		title:   "Daily Usage",
+		headers: []string{"Date", "Requests", "Tokens", "Cost", "7d Avg", "30d Avg"},
+		footer:  append(h.statsRow("Total", total.TotalRequests(), total.TotalTokens(), total.TotalCost()), "", ""),
+		empty:   "No usage recorded in this month.",
+	}
+	for i, day := range statement.Days() {
+		if day.TotalRequests() == 0 {
+			continue
+		}
+		date := day.Period().StartAt().In(location).Format("2006-01-02 Mon")
+		row := h.statsRow(date, day.TotalRequests(), day.TotalTokens(), day.TotalCost())
+		if average, ok := statement.MovingAverageAt(i); ok {
+			row = append(row, h.costFormat.Format(average.Short()), h.costFormat.Format(average.Long()))
+		} else {
+			row = append(row, "-", "-")
+		}
+		daily.rows = append(daily.rows, row)
+	}
+	if len(daily.rows) == 0 {
+		daily.footer = nil
+	}
+	doc.tables = append(doc.tables, daily)
+
+	sessions := statementTable{
+		title:   "Top Sessions",
+		headers: []string{"Session", "Requests", "Tokens", "Cost"},
+		empty:   "No sessions in this month.",
+	}
+	for _, session := range statement.TopSessions() {
+		sessions.rows = append(sessions.rows, h.statsRow(session.Name(), session.Requests(), session.Tokens(), session.Cost()))
+	}
+	doc.tables = append(doc.tables, sessions)
+
+	return doc
+}
+
+// planTable compares the month's cost with the configured subscription plan
+func (h *StatementHandler) planTable(statement entity.Statement) statementTable {
+	table := statementTable{
+		title:   "Plan Utilization",
+		headers: []string{"Plan", "Monthly Price", "Usage Cost", "Utilization"},
+		empty:   "No subscription plan configured.",
+	}
+
+	plan := statement.Plan()
+	if plan.Name() == "" || plan.Name() == "unset" {
+		return table
+	}
+
+	table.rows = [][]string{{
+		plan.Name(),
+		h.costFormat.Format(plan.Price()),
+		h.costFormat.Format(statement.Total().TotalCost()),
+		fmt.Sprintf("%d%%", statement.PlanUtilization()),
+	}}
+	return table
+}
+
+// statsRow formats a labeled row of request, token and cost amounts
+func (h *StatementHandler) statsRow(label string, requests int, tokens entity.Token, cost entity.Cost) []string {
+	return []string{label, formatInteger(int64(requests)), formatInteger(tokens.Total()), h.costFormat.Format(cost)}
+}
+
+// markdownRow renders cells as a markdown table row
+func markdownRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |\n"
+}
+
+// columnWidths returns the widest cell of every column
+func columnWidths(table statementTable) []int {
+	widths := make([]int, len(table.headers))
+	rows := append([][]string{table.headers, table.footer}, table.rows...)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(widths) && len(cell) > widths[i] {
+				widths[i] = len(cell)
+			}
+		}
+	}
+
+	return widths
+}
+
+// alignedRow pads cells to the column widths, the label columns are left aligned and amounts right aligned
+func alignedRow(cells []string, widths []int, labels int) string {
+	padded := make([]string, len(cells))
+	for i, cell := range cells {
+		if i == 0 || i < labels {
+			padded[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		} else {
+			padded[i] = fmt.Sprintf("%*s", widths[i], cell)
+		}
+	}
+
+	return strings.Join(padded, "  ")
+}
+
+// formatInteger formats a number with thousands separators (e.g. "1,234,567")
+func formatInteger(n int64) string {
+	if n < 0 {
+		return "-" + formatInteger(-n)
+	}
+	digits := fmt.Sprintf("%d", n)
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package cli_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+)
+
+// createTestStatement creates a June 2025 statement with two days of usage
+func createTestStatement(plan entity.Plan) entity.Statement {
+	monthStart := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-a", time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(1.25), 1000),
+		entity.NewAPIRequest("session-b", time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.05), 500),
+		entity.NewAPIRequest("session-a", time.Date(2025, 6, 10, 9, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(2000, 1000, 0, 0), entity.NewCost(2.70), 1500),
+	}
+
+	statement := entity.NewStatement(monthStart, requests, plan, 10)
+	costs := make([]entity.Cost, 0, len(statement.Days()))
+	for _, day := range statement.Days() {
+		costs = append(costs, day.TotalCost())
+	}
+	return statement.WithMovingAverages(entity.CalculateMovingAverages(costs, len(costs)))
+}
+
+func TestStatementHandler_RenderMarkdown(t *testing.T) {
+	handler := cli.NewStatementHandler(nil, entity.NewCostFormat(2, false))
+	generatedAt := time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		plan     entity.Plan
+		titles   entity.SessionTitles
+		contains []string
+	}{
+		{
+			name: "statement with plan",
+			plan: entity.NewPlan("pro", entity.NewCost(20)),
+			contains: []string{
+				"# Claude Usage Statement - June 2025\n",
+				"Period: 2025-06-01 to 2025-06-30 (UTC)  \n",
+				"Generated: 2025-07-01 09:30 UTC  \n",
+				"| Base (Haiku) | 1 | 300 | $0.05 |\n",
+				"| Premium (Sonnet/Opus) | 2 | 4,500 | $3.95 |\n",
+				"| **Total** | **3** | **4,800** | **$4.00** |\n",
+				"| pro | $20.00 | $4.00 | 20% |\n",
+				"| 2025-06-02 Mon | 2 | 1,800 | $1.30 | $0.65 | $0.65 |\n",
+				"| 2025-06-10 Tue | 1 | 3,000 | $2.70 | $0.39 | $0.40 |\n",
+				"| **Total** | **3** | **4,800** | **$4.00** |  |  |\n",
+				"| session-a | 2 | 4,500 | $3.95 |\n",
+				"| session-b | 1 | 300 | $0.05 |\n",
+			},
+		},
+		{
+			name: "statement without plan",
+			plan: entity.NewPlan("unset", entity.NewCost(0)),
+			contains: []string{
+				"## Plan Utilization\n\n_No subscription plan configured._\n",
+			},
+		},
+		{
+			name:   "statement with session titles",
+			plan:   entity.NewPlan("pro", entity.NewCost(20)),
+			titles: entity.SessionTitles{"session-a": entity.NewSessionTitle("Refactor billing module", "/home/alice/billing")},
+			contains: []string{
+				"| Refactor billing module | 2 | 4,500 | $3.95 |\n",
+				"| session-b | 1 | 300 | $0.05 |\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := handler.RenderMarkdown(createTestStatement(tt.plan).WithSessionTitles(tt.titles), generatedAt)
+
+			for _, expected := range tt.contains {
+				if !strings.Contains(result, expected) {
+					t.Errorf("Expected markdown to contain %q, got:\n%s", expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestStatementHandler_RenderMarkdown_Empty(t *testing.T) {
+	handler := cli.NewStatementHandler(nil, entity.NewCostFormat(2, false))
+	statement := entity.NewStatement(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), nil, entity.NewPlan("pro", entity.NewCost(20)), 10)
+
+	result := handler.RenderMarkdown(statement, time.Now())
+
+	for _, expected := range []string{"_No usage recorded in this month._", "_No sessions in this month._"} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected markdown to contain %q, got:\n%s", expected, result)
+		}
+	}
+}
+
+func TestStatementHandler_RenderPDF(t *testing.T) {
+	handler := cli.NewStatementHandler(nil, entity.NewCostFormat(2, false))
+
+	result := handler.RenderPDF(createTestStatement(entity.NewPlan("pro", entity.NewCost(20))), time.Date(2025, 7, 1, 9, 30, 0, 0, time.UTC))
+
+	if !bytes.HasPrefix(result, []byte("%PDF-1.4\n")) {
+		t.Fatalf("Expected PDF header, got %q", result[:min(len(result), 16)])
+	}
+	if !bytes.HasSuffix(result, []byte("%%EOF\n")) {
+		t.Errorf("Expected PDF trailer end marker")
+	}
+
+	// The cross-reference table offset must point at the xref section
+	trailer := result[bytes.LastIndex(result, []byte("startxref\n"))+len("startxref\n"):]
+	offset, err := strconv.Atoi(string(bytes.TrimSpace(bytes.TrimSuffix(bytes.TrimSpace(trailer), []byte("%%EOF")))))
+	if err != nil {
+		t.Fatalf("Failed to parse startxref: %v", err)
+	}
+	if !bytes.HasPrefix(result[offset:], []byte("xref\n")) {
+		t.Errorf("startxref %d does not point at the xref table", offset)
+	}
+
+	for _, expected := range []string{
+		"(Claude Usage Statement - June 2025) Tj",
+		"(session-a         2   4,500  $3.95) Tj",
+	} {
+		if !bytes.Contains(result, []byte(expected)) {
+			t.Errorf("Expected PDF to contain %q", expected)
+		}
+	}
+}
+
+func TestValidateStatementOutput(t *testing.T) {
+	tests := []struct {
+		output  string
+		wantErr bool
+	}{
+		{output: "md"},
+		{output: "pdf"},
+		{output: "html", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			err := cli.ValidateStatementOutput(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStatementOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			}
+		})
+	}
+}
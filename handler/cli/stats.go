@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Stats periods for the query stats command
+const (
+	StatsPeriodHour  = "hour"  // rolling hour ending at the point in time
+	StatsPeriodDay   = "day"   // calendar day containing the point in time
+	StatsPeriodWeek  = "week"  // rolling 7 days ending at the point in time
+	StatsPeriodMonth = "month" // calendar month containing the point in time
+	StatsPeriodBlock = "block" // block containing the point in time, requires --block
+	StatsPeriodAll   = "all"   // everything up to the point in time
+)
+
+// statsTimeLayout is used for period boundaries in the stats output
+const statsTimeLayout = "2006-01-02 15:04:05 MST"
+
+// StatsHandler recomputes stats for a period as they were at a point in time
+type StatsHandler struct {
+	calculateStatsQuery *usecase.CalculateStatsQuery
+	timezone            *time.Location
+	block               *entity.Block
+	costFormat          entity.CostFormat
+	origin              string
+}
+
+// NewStatsHandler creates a new StatsHandler, block is optional and must contain the queried point in time
+func NewStatsHandler(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location, block *entity.Block, costFormat entity.CostFormat) *StatsHandler {
+	return &StatsHandler{
+		calculateStatsQuery: calculateStatsQuery,
+		timezone:            timezone,
+		block:               block,
+		costFormat:          costFormat,
+	}
+}
+
+// SetOrigin limits the stats to requests of the origin, e.g. entity.OriginLive to exclude imported records
+func (h *StatsHandler) SetOrigin(origin string) {
+	h.origin = origin
+}
+
+// ValidateStatsPeriod returns an error for unsupported stats periods
+func ValidateStatsPeriod(period string) error {
+	switch period {
+	case StatsPeriodHour, StatsPeriodDay, StatsPeriodWeek, StatsPeriodMonth, StatsPeriodBlock, StatsPeriodAll:
+		return nil
+	default:
+		return fmt.Errorf("unsupported stats period %q, expected hour, day, week, month, block or all", period)
+	}
+}
+
+// HandleStats writes the stats for the period as they were at the point in time to w
+func (h *StatsHandler) HandleStats(period string, at time.Time, w io.Writer) error {
+	result, err := h.Render(period, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(w, result)
+	return err
+}
+
+// Render builds the stats summary for the period as it was at the point in time
+func (h *StatsHandler) Render(period string, at time.Time) (string, error) {
+	statsPeriod, err := h.periodAt(period, at)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: statsPeriod, Origin: h.origin})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate stats: %w", err)
+	}
+
+	var b strings.Builder
+	if period == StatsPeriodAll {
+		fmt.Fprintf(&b, "Period:   %s (until %s)\n", period, h.formatTime(statsPeriod.EndAt()))
+	} else {
+		fmt.Fprintf(&b, "Period:   %s (%s - %s)\n", period, h.formatTime(statsPeriod.StartAt()), h.formatTime(statsPeriod.EndAt()))
+	}
+	if h.origin != "" {
+		fmt.Fprintf(&b, "Origin:   %s\n", h.origin)
+	}
+	fmt.Fprintf(&b, "Requests: %s (base %s, premium %s, long context %s)\n",
+		formatInteger(int64(stats.TotalRequests())),
+		formatInteger(int64(stats.BaseRequests())),
+		formatInteger(int64(stats.PremiumRequests())),
+		formatInteger(int64(stats.LongContextRequests())))
+
+	tokens := stats.TotalTokens()
+	fmt.Fprintf(&b, "Tokens:   %s (input %s, output %s, cache read %s, cache creation %s)\n",
+		formatInteger(tokens.Total()),
+		formatInteger(tokens.Input()),
+		formatInteger(tokens.Output()),
+		formatInteger(tokens.CacheRead()),
+		formatInteger(tokens.CacheCreation()))
+	fmt.Fprintf(&b, "Cost:     %s\n", h.costFormat.Format(stats.TotalCost()))
+
+	if period == StatsPeriodBlock && h.block.HasLimit() {
+		fmt.Fprintf(&b, "Block:    %.1f%% of %s token limit\n",
+			h.block.CalculateProgress(stats.RateLimitedTokens()), formatInteger(int64(h.block.TokenLimit())))
+	}
+
+	return b.String(), nil
+}
+
+// periodAt returns the stats period as it was at the point in time
+func (h *StatsHandler) periodAt(period string, at time.Time) (entity.Period, error) {
+	return statsPeriodAt(period, at, h.timezone, h.block)
+}
+
+// statsPeriodAt returns the stats period as it was at the point in time, block is only required by the block period
+func statsPeriodAt(period string, at time.Time, timezone *time.Location, block *entity.Block) (entity.Period, error) {
+	if err := ValidateStatsPeriod(period); err != nil {
+		return entity.Period{}, err
+	}
+
+	at = at.UTC()
+
+	switch period {
+	case StatsPeriodHour:
+		return entity.NewPeriodFromDuration(at, time.Hour), nil
+	case StatsPeriodDay:
+		return entity.NewDayPeriod(at, timezone).Until(at), nil
+	case StatsPeriodWeek:
+		return entity.NewPeriodFromDuration(at, 7*24*time.Hour), nil
+	case StatsPeriodMonth:
+		return entity.NewMonthPeriod(at, timezone).Until(at), nil
+	case StatsPeriodBlock:
+		if block == nil {
+			return entity.Period{}, fmt.Errorf("stats period %q requires the --block start time", period)
+		}
+		return block.Period().Until(at), nil
+	default:
+		return entity.NewAllTimePeriod(time.Now().UTC()).Until(at), nil
+	}
+}
+
+// formatTime formats a period boundary in the configured timezone
+func (h *StatsHandler) formatTime(t time.Time) string {
+	return t.In(h.timezone).Format(statsTimeLayout)
+}
@@ -0,0 +1,162 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestStatsHandler_Render(t *testing.T) {
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 1000, 500, 1.25),
+		testutil.CreateTestAPIRequest("session-2", time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 2000, 1000, 2.50).WithOrigin(entity.OriginImport),
+		testutil.CreateTestAPIRequest("session-3", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), "claude-3-5-haiku-20241022", 4000, 2000, 0.10),
+	}
+	endOfDay := time.Date(2025, 6, 1, 23, 59, 59, 999999999, time.UTC)
+
+	tests := []struct {
+		name     string
+		period   string
+		at       time.Time
+		block    *entity.Block
+		origin   string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:   "day as of end of day includes the whole day",
+			period: cli.StatsPeriodDay,
+			at:     endOfDay,
+			expected: []string{
+				"Period:   day (2025-06-01 00:00:00 UTC - 2025-06-01 23:59:59 UTC)",
+				"Requests: 2 (base 0, premium 2, long context 0)",
+				"Tokens:   4,500 (input 3,000, output 1,500, cache read 0, cache creation 0)",
+				"Cost:     $3.75",
+			},
+		},
+		{
+			name:   "day as of afternoon excludes later requests",
+			period: cli.StatsPeriodDay,
+			at:     time.Date(2025, 6, 1, 15, 0, 0, 0, time.UTC),
+			expected: []string{
+				"Period:   day (2025-06-01 00:00:00 UTC - 2025-06-01 15:00:00 UTC)",
+				"Requests: 1 (base 0, premium 1, long context 0)",
+				"Cost:     $1.25",
+			},
+		},
+		{
+			name:   "month excludes requests after the point in time",
+			period: cli.StatsPeriodMonth,
+			at:     endOfDay,
+			expected: []string{
+				"Period:   month (2025-06-01 00:00:00 UTC - 2025-06-01 23:59:59 UTC)",
+				"Requests: 2 (base 0, premium 2, long context 0)",
+			},
+		},
+		{
+			name:   "all time up to the point in time",
+			period: cli.StatsPeriodAll,
+			at:     time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+			expected: []string{
+				"Period:   all (until 2025-06-02 12:00:00 UTC)",
+				"Requests: 3 (base 1, premium 2, long context 0)",
+				"Cost:     $3.85",
+			},
+		},
+		{
+			name:   "all time excludes requests after the point in time",
+			period: cli.StatsPeriodAll,
+			at:     endOfDay,
+			expected: []string{
+				"Requests: 2 (base 0, premium 2, long context 0)",
+			},
+		},
+		{
+			name:   "block containing the point in time",
+			period: cli.StatsPeriodBlock,
+			at:     endOfDay,
+			block:  blockPtr(entity.NewBlockWithLimit(time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC), 10000)),
+			expected: []string{
+				"Period:   block (2025-06-01 18:00:00 UTC - 2025-06-01 23:00:00 UTC)",
+				"Requests: 1 (base 0, premium 1, long context 0)",
+				"Block:    30.0% of 10,000 token limit",
+			},
+		},
+		{
+			name:   "live origin excludes imported requests",
+			period: cli.StatsPeriodDay,
+			at:     endOfDay,
+			origin: entity.OriginLive,
+			expected: []string{
+				"Origin:   live",
+				"Requests: 1 (base 0, premium 1, long context 0)",
+				"Cost:     $1.25",
+			},
+		},
+		{
+			name:   "block period without block",
+			period: cli.StatsPeriodBlock,
+			at:     endOfDay,
+			errMsg: "requires the --block start time",
+		},
+		{
+			name:   "unsupported period",
+			period: "year",
+			at:     endOfDay,
+			errMsg: "unsupported stats period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			handler := cli.NewStatsHandler(calculateStatsQuery, time.UTC, tt.block, entity.DefaultCostFormat())
+			handler.SetOrigin(tt.origin)
+			result, err := handler.Render(tt.period, tt.at)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Render() error = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() returned error: %v", err)
+			}
+
+			for _, line := range tt.expected {
+				if !strings.Contains(result, line) {
+					t.Errorf("Render() missing %q in:\n%s", line, result)
+				}
+			}
+		})
+	}
+}
+
+func TestStatsHandler_Render_LongContextBlock(t *testing.T) {
+	// 1M context tokens count against the block limit like in the other block views
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 19, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", 1000, 500, 0.25),
+		testutil.CreateTestAPIRequest("session-1", time.Date(2025, 6, 1, 20, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514[1m]", 2000, 500, 1.50),
+	}
+	_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	block := blockPtr(entity.NewBlockWithLimit(time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC), 10000))
+
+	handler := cli.NewStatsHandler(calculateStatsQuery, time.UTC, block, entity.DefaultCostFormat())
+	result, err := handler.Render(cli.StatsPeriodBlock, time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	if expected := "Block:    40.0% of 10,000 token limit"; !strings.Contains(result, expected) {
+		t.Errorf("Render() missing %q in:\n%s", expected, result)
+	}
+}
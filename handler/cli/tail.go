@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// tailRetryInterval is how long the tail waits before watching again after the server became unreachable
+const tailRetryInterval = 5 * time.Second
+
+// TailHandler prints the API requests stored by the server as plain log lines, e.g. to pipe into grep or a log shipper
+type TailHandler struct {
+	watchQuery *usecase.WatchApiRequestsQuery
+	timezone   *time.Location
+}
+
+// NewTailHandler creates a new TailHandler printing timestamps in the timezone
+func NewTailHandler(watchQuery *usecase.WatchApiRequestsQuery, timezone *time.Location) *TailHandler {
+	return &TailHandler{
+		watchQuery: watchQuery,
+		timezone:   timezone,
+	}
+}
+
+// HandleTail prints a line to out for each request stored from now on until ctx is done
+// The tail keeps running while the server restarts, the retries are reported to errOut so out only holds requests
+func (h *TailHandler) HandleTail(ctx context.Context, out, errOut io.Writer) error {
+	for {
+		err := h.watchQuery.Execute(ctx, entity.NewFilter(entity.NewAllTimePeriod(time.Now())), func(requests []entity.APIRequest) error {
+			for _, req := range requests {
+				if _, err := fmt.Fprintln(out, FormatTailLine(req, h.timezone)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		// Other errors won't go away by waiting, e.g. a rejected token or a server predating the stream
+		if entity.ErrorKindOf(err) != entity.ErrorKindConnection {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(errOut, "%s, retrying in %s\n", entity.ErrorMessage(err), tailRetryInterval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailRetryInterval):
+		}
+	}
+}
+
+// FormatTailLine formats the request as a timestamp followed by key=value fields
+// Numbers are bare, without separators or currency sign, so the fields can be parsed; project and user are left out when unknown
+func FormatTailLine(req entity.APIRequest, timezone *time.Location) string {
+	tokens := req.Tokens()
+	fields := []string{
+		req.Timestamp().In(timezone).Format(time.RFC3339),
+		"model=" + req.Model().String(),
+		fmt.Sprintf("input=%d", tokens.Input()),
+		fmt.Sprintf("output=%d", tokens.Output()),
+		fmt.Sprintf("cache_read=%d", tokens.CacheRead()),
+		fmt.Sprintf("cache_creation=%d", tokens.CacheCreation()),
+		fmt.Sprintf("cost=%.6f", req.Cost().Amount()),
+		fmt.Sprintf("duration_ms=%d", req.DurationMS()),
+		"session=" + req.SessionID(),
+	}
+	if req.Project() != "" {
+		fields = append(fields, "project="+req.Project())
+	}
+	if req.User() != "" {
+		fields = append(fields, "user="+req.User())
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,107 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestFormatTailLine(t *testing.T) {
+	timestamp := time.Date(2025, 6, 1, 2, 30, 0, 0, time.UTC)
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+
+	tests := []struct {
+		name     string
+		request  entity.APIRequest
+		expected string
+	}{
+		{
+			name:     "bare request",
+			request:  entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1200, 350, 40000, 0), entity.NewCost(0.0345), 2500),
+			expected: "2025-06-01T10:30:00+08:00 model=claude-sonnet-4-20250514 input=1200 output=350 cache_read=40000 cache_creation=0 cost=0.034500 duration_ms=2500 session=session-1",
+		},
+		{
+			name: "project and user",
+			request: entity.NewAPIRequest("session-2", timestamp, "claude-opus-4-20250514", entity.NewToken(1, 2, 3, 4), entity.NewCost(1.5), 0).
+				WithProject("billing").WithUser("alice@example.com"),
+			expected: "2025-06-01T10:30:00+08:00 model=claude-opus-4-20250514 input=1 output=2 cache_read=3 cache_creation=4 cost=1.500000 duration_ms=0 session=session-2 project=billing user=alice@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cli.FormatTailLine(tt.request, taipei); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTailHandler_HandleTail(t *testing.T) {
+	timestamp := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestWatchRepository(
+		[]entity.APIRequest{entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1, 2, 0, 0), entity.NewCost(0.01), 100)},
+		[]entity.APIRequest{
+			entity.NewAPIRequest("session-1", timestamp.Add(time.Second), "claude-sonnet-4-20250514", entity.NewToken(3, 4, 0, 0), entity.NewCost(0.02), 100),
+			entity.NewAPIRequest("session-2", timestamp.Add(2*time.Second), "claude-haiku-3-5-20241022", entity.NewToken(5, 6, 0, 0), entity.NewCost(0.03), 100),
+		},
+	)
+
+	var out, errOut bytes.Buffer
+	handler := cli.NewTailHandler(usecase.NewWatchApiRequestsQuery(repo), time.UTC)
+	if err := handler.HandleTail(context.Background(), &out, &errOut); err != nil {
+		t.Fatalf("HandleTail() failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a line per request, got %d:\n%s", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[2], "2025-06-01T10:00:02Z model=claude-haiku-3-5-20241022 input=5 output=6") {
+		t.Errorf("Expected the requests in the pushed order, got %q", lines[2])
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("Expected nothing on stderr, got %q", errOut.String())
+	}
+}
+
+func TestTailHandler_HandleTail_Errors(t *testing.T) {
+	t.Run("connection errors are retried", func(t *testing.T) {
+		repo := testutil.NewMockAPIRequestWatchRepository()
+		repo.SetError(entity.NewError(entity.ErrorKindConnection, "cannot connect to the ccmon server", errors.New("connection refused")))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var out, errOut bytes.Buffer
+		handler := cli.NewTailHandler(usecase.NewWatchApiRequestsQuery(repo), time.UTC)
+		if err := handler.HandleTail(ctx, &out, &errOut); err != nil {
+			t.Fatalf("Expected the tail to stop without an error when interrupted, got %v", err)
+		}
+		if !strings.Contains(errOut.String(), "cannot connect to the ccmon server, retrying in 5s") {
+			t.Errorf("Expected the retry on stderr, got %q", errOut.String())
+		}
+		if out.Len() != 0 {
+			t.Errorf("Expected nothing on stdout, got %q", out.String())
+		}
+	})
+
+	t.Run("other errors stop the tail", func(t *testing.T) {
+		repo := testutil.NewMockAPIRequestWatchRepository()
+		repo.SetError(entity.NewError(entity.ErrorKindAuth, "the ccmon server rejected the credentials", nil))
+
+		var out, errOut bytes.Buffer
+		handler := cli.NewTailHandler(usecase.NewWatchApiRequestsQuery(repo), time.UTC)
+		if err := handler.HandleTail(context.Background(), &out, &errOut); entity.ErrorKindOf(err) != entity.ErrorKindAuth {
+			t.Errorf("Expected the auth error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Block usage thresholds (percentage) for the tmux segment colors
+const (
+	tmuxWarningThreshold  = 50.0
+	tmuxCriticalThreshold = 80.0
+)
+
+// tmuxErrorSegment is printed when usage data cannot be fetched (e.g. server offline)
+const tmuxErrorSegment = "#[fg=red]ccmon ✗#[default]"
+
+// tmuxCostPrecision is the decimals of the daily cost when no precision is configured
+const tmuxCostPrecision = 2
+
+// TmuxStatusHandler renders a color-coded tmux status segment with block usage and daily cost
+type TmuxStatusHandler struct {
+	calculateStatsQuery *usecase.CalculateStatsQuery
+	periodFactory       usecase.PeriodFactory
+	block               *entity.Block
+	costFormat          entity.CostFormat
+}
+
+// NewTmuxStatusHandler creates a new TmuxStatusHandler, block is optional
+func NewTmuxStatusHandler(calculateStatsQuery *usecase.CalculateStatsQuery, periodFactory usecase.PeriodFactory, block *entity.Block, costFormat entity.CostFormat) *TmuxStatusHandler {
+	return &TmuxStatusHandler{
+		calculateStatsQuery: calculateStatsQuery,
+		periodFactory:       periodFactory,
+		block:               block,
+		costFormat:          costFormat.OrPrecision(tmuxCostPrecision),
+	}
+}
+
+// HandleTmuxStatus prints the tmux status segment to stdout
+func (h *TmuxStatusHandler) HandleTmuxStatus() error {
+	result, err := h.Render(time.Now())
+	if err != nil {
+		fmt.Print(tmuxErrorSegment)
+		return err
+	}
+
+	fmt.Print(result)
+	return nil
+}
+
+// Render builds the tmux status segment using tmux style escapes (e.g. "#[fg=green]")
+func (h *TmuxStatusHandler) Render(now time.Time) (string, error) {
+	// Create context with timeout to prevent hanging the tmux status line
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var segments []string
+
+	if h.block != nil {
+		block := h.block.NextBlock(now)
+		blockStats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: block.Period()})
+		if err != nil {
+			return "", fmt.Errorf("failed to calculate block stats: %w", err)
+		}
+		segments = append(segments, h.renderBlock(block, blockStats, now))
+	}
+
+	dailyStats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: h.periodFactory.CreateDaily()})
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+	segments = append(segments, h.costFormat.Format(dailyStats.TotalCost()))
+
+	return strings.Join(segments, " "), nil
+}
+
+// renderBlock renders the block usage part of the segment
+func (h *TmuxStatusHandler) renderBlock(block entity.Block, stats entity.Stats, now time.Time) string {
+	remaining := formatRemaining(block.EndAt().Sub(now))
+
+	if !block.HasLimit() {
+		// Without a limit only the used tokens can be shown
+		return fmt.Sprintf("#[fg=colour244]%s tok %s#[default]", entity.FormatTokenCount(stats.RateLimitedTokens().Limited()), remaining)
+	}
+
+	percentage := block.CalculateProgress(stats.RateLimitedTokens())
+	return fmt.Sprintf("#[fg=%s]%.0f%% %s#[default]", tmuxColor(percentage), percentage, remaining)
+}
+
+// tmuxColor returns the tmux color name for the given block usage percentage
+func tmuxColor(percentage float64) string {
+	switch {
+	case percentage >= tmuxCriticalThreshold:
+		return "red"
+	case percentage >= tmuxWarningThreshold:
+		return "yellow"
+	default:
+		return "green"
+	}
+}
+
+// formatRemaining formats the time left in the block as a compact duration (e.g. "2h13m")
+func formatRemaining(d time.Duration) string {
+	if d <= 0 {
+		return "0m"
+	}
+
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours > 0 {
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
@@ -0,0 +1,98 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestTmuxStatusHandler_Render(t *testing.T) {
+	now := time.Now().UTC()
+	blockStart := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		tokens   int64
+		block    *entity.Block
+		expected string
+	}{
+		{
+			name:     "daily cost only without block",
+			tokens:   1000,
+			block:    nil,
+			expected: "$1.50",
+		},
+		{
+			name:     "low block usage is green",
+			tokens:   1000,
+			block:    blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			expected: "#[fg=green]10% 4h00m#[default] $1.50",
+		},
+		{
+			name:     "medium block usage is yellow",
+			tokens:   6000,
+			block:    blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			expected: "#[fg=yellow]60% 4h00m#[default] $1.50",
+		},
+		{
+			name:     "high block usage is red",
+			tokens:   9000,
+			block:    blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			expected: "#[fg=red]90% 4h00m#[default] $1.50",
+		},
+		{
+			name:     "block without limit shows tokens",
+			tokens:   12300,
+			block:    blockPtr(entity.NewBlock(blockStart)),
+			expected: "#[fg=colour244]12.3K tok 4h00m#[default] $1.50",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := []entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", now.Add(-time.Minute), "claude-sonnet-4-20250514", tt.tokens, 0, 1.50),
+			}
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			periodFactory := service.NewTimePeriodFactory(time.UTC)
+
+			handler := cli.NewTmuxStatusHandler(calculateStatsQuery, periodFactory, tt.block, entity.DefaultCostFormat())
+			result, err := handler.Render(now)
+			if err != nil {
+				t.Fatalf("Render() returned error: %v", err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTmuxStatusHandler_Render_Error(t *testing.T) {
+	apiRepo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "connection refused"})
+	statsRepo := testutil.NewMockStatsRepository(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+
+	handler := cli.NewTmuxStatusHandler(calculateStatsQuery, periodFactory, nil, entity.DefaultCostFormat())
+	_, err := handler.Render(time.Now())
+	if err == nil {
+		t.Fatal("Render() expected error, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "failed to calculate daily stats") {
+		t.Errorf("Render() error = %v, want daily stats error", err)
+	}
+}
+
+func blockPtr(block entity.Block) *entity.Block {
+	return &block
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Alerts evaluates the budget alert rules as requests arrive
+type Alerts struct {
+	Command    *usecase.EvaluateAlertsCommand // nil disables the alerts
+	CostFormat entity.CostFormat
+}
+
+// IsEnabled returns true if the alerts are evaluated
+func (a Alerts) IsEnabled() bool {
+	return a.Command != nil
+}
+
+// startAlertEvaluator evaluates the rules right away and then after each stored batch of requests
+// Batches arriving during an evaluation are coalesced into the next one
+func startAlertEvaluator(ctx context.Context, alerts Alerts, watchQuery *usecase.WatchApiRequestsQuery) {
+	log.Println("Starting budget alert evaluator")
+
+	arrived := make(chan struct{}, 1)
+	go func() {
+		err := watchQuery.Execute(ctx, entity.Filter{}, func([]entity.APIRequest) error {
+			select {
+			case arrived <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Budget alert evaluator stopped watching requests: %v", err)
+		}
+	}()
+
+	go func() {
+		evaluator := &alertEvaluator{alerts: alerts}
+		evaluator.evaluate(ctx, time.Now())
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Budget alert evaluator stopped")
+				return
+			case <-arrived:
+				evaluator.evaluate(ctx, time.Now())
+			}
+		}
+	}()
+}
+
+// alertEvaluator logs changes of the delivery state instead of every failure, requests usually arrive in bursts
+type alertEvaluator struct {
+	alerts  Alerts
+	failing bool
+}
+
+// evaluate fires the alerts of now, a failed delivery is retried after the next batch
+func (e *alertEvaluator) evaluate(ctx context.Context, now time.Time) {
+	fired, err := e.alerts.Command.Execute(ctx, now)
+	for _, alert := range fired {
+		log.Printf("Budget alert: %s", alert.Message(e.alerts.CostFormat))
+	}
+
+	if err != nil {
+		if !e.failing {
+			log.Printf("Budget alert failed, retrying when requests arrive: %v", err)
+		}
+		e.failing = true
+		return
+	}
+	if e.failing {
+		log.Println("Budget alerts delivered again")
+		e.failing = false
+	}
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/grpc/replication"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// publicServices never require a token, probes must work without one and replicas present their snapshot token
+var publicServices = []string{
+	"grpc.health.v1.Health",
+	"grpc.reflection.v1.ServerReflection",
+	"grpc.reflection.v1alpha.ServerReflection",
+	pb.ReplicationService_ServiceDesc.ServiceName,
+}
+
+// readServices only answer queries, every other service requires a write token
+// Services added later are mutating unless listed here, so read tokens never gain rights by accident
+var readServices = []string{
+	pb.QueryService_ServiceDesc.ServiceName,
+}
+
+// Authorizer checks the bearer token of the calls against the role each service requires
+type Authorizer struct {
+	read  *replication.Tokens
+	write *replication.Tokens
+}
+
+// NewAuthorizer reads the token files and returns an authorizer accepting the tokens
+func NewAuthorizer(tokens []entity.AccessToken) (*Authorizer, error) {
+	var read, write []entity.AccessToken
+	for _, token := range tokens {
+		if token.Role == entity.TokenRoleWrite {
+			write = append(write, token)
+		} else {
+			read = append(read, token)
+		}
+	}
+
+	readTokens, err := replication.NewTokens(read)
+	if err != nil {
+		return nil, err
+	}
+	writeTokens, err := replication.NewTokens(write)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authorizer{read: readTokens, write: writeTokens}, nil
+}
+
+// Reload reads the token files again, a file which can't be read keeps its previous token
+func (a *Authorizer) Reload() error {
+	return errors.Join(a.read.Reload(), a.write.Reload())
+}
+
+// HasFiles returns true if any token is read from a file, only those change on Reload
+func (a *Authorizer) HasFiles() bool {
+	return a.read.HasFiles() || a.write.HasFiles()
+}
+
+// Authorize returns the label of the token when it grants the role
+// Unauthenticated is returned without a known token, PermissionDenied when a read token asks for write
+func (a *Authorizer) Authorize(authorization []string, role entity.TokenRole) (string, error) {
+	for _, value := range authorization {
+		token, found := strings.CutPrefix(value, "Bearer ")
+		if !found {
+			continue
+		}
+		// Write tokens grant everything a read token does
+		if label, ok := a.write.Match(token); ok {
+			return label, nil
+		}
+		if label, ok := a.read.Match(token); ok {
+			if role == entity.TokenRoleWrite {
+				return label, status.Errorf(codes.PermissionDenied, "token %s is read-only", label)
+			}
+			return label, nil
+		}
+	}
+
+	return "", status.Error(codes.Unauthenticated, "missing or invalid access token")
+}
+
+// UnaryServerInterceptor rejects calls without a token granting the role of the service
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := a.authorizeCall(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects streams without a token granting the role of the service, e.g. watching requests
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorizeCall(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// HTTPMiddleware rejects requests without a token granting the role
+// The OTLP/HTTP export paths need a write token, the HTTP API a read token
+func (a *Authorizer) HTTPMiddleware(next http.Handler, role entity.TokenRole) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, err := a.Authorize(req.Header.Values("Authorization"), role); err != nil {
+			log.Printf("Rejected %s %s from %s: %s", req.Method, req.URL.Path, req.RemoteAddr, status.Convert(err).Message())
+			if status.Code(err) == codes.PermissionDenied {
+				http.Error(w, status.Convert(err).Message(), http.StatusForbidden)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, status.Convert(err).Message(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// authorizeCall checks the bearer token in the call metadata against the role of the method's service
+func (a *Authorizer) authorizeCall(ctx context.Context, fullMethod string) error {
+	role, ok := requiredRole(fullMethod)
+	if !ok {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if _, err := a.Authorize(md.Get("authorization"), role); err != nil {
+		log.Printf("Rejected %s from %s: %s", fullMethod, peerAddress(ctx), status.Convert(err).Message())
+		return err
+	}
+	return nil
+}
+
+// requiredRole returns the role the method's service requires, false when the service is public
+func requiredRole(fullMethod string) (entity.TokenRole, bool) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	switch {
+	case slices.Contains(publicServices, service):
+		return "", false
+	case slices.Contains(readServices, service):
+		return entity.TokenRoleRead, true
+	default:
+		return entity.TokenRoleWrite, true
+	}
+}
+
+// peerAddress returns the address of the caller for the logs
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown peer"
+}
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAuthorizer(t *testing.T) *Authorizer {
+	t.Helper()
+
+	authorizer, err := NewAuthorizer([]entity.AccessToken{
+		{Label: "dashboard", Token: "read-secret", Role: entity.TokenRoleRead},
+		{Label: "claude-code", Token: "write-secret", Role: entity.TokenRoleWrite},
+	})
+	if err != nil {
+		t.Fatalf("NewAuthorizer() failed: %v", err)
+	}
+	return authorizer
+}
+
+func TestAuthorizer_UnaryServerInterceptor(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+	interceptor := authorizer.UnaryServerInterceptor()
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		expectedCode  codes.Code
+	}{
+		{name: "read token queries", method: "/ccmon.v1.QueryService/GetStats", authorization: "Bearer read-secret", expectedCode: codes.OK},
+		{name: "write token queries", method: "/ccmon.v1.QueryService/GetAPIRequests", authorization: "Bearer write-secret", expectedCode: codes.OK},
+		{name: "missing token", method: "/ccmon.v1.QueryService/GetStats", expectedCode: codes.Unauthenticated},
+		{name: "unknown token", method: "/ccmon.v1.QueryService/GetStats", authorization: "Bearer guess", expectedCode: codes.Unauthenticated},
+		{name: "token without bearer prefix", method: "/ccmon.v1.QueryService/GetStats", authorization: "read-secret", expectedCode: codes.Unauthenticated},
+		{name: "read token exports", method: "/opentelemetry.proto.collector.logs.v1.LogsService/Export", authorization: "Bearer read-secret", expectedCode: codes.PermissionDenied},
+		{name: "write token exports", method: "/opentelemetry.proto.collector.logs.v1.LogsService/Export", authorization: "Bearer write-secret", expectedCode: codes.OK},
+		{name: "read token stars", method: "/ccmon.v1.StarService/SetStar", authorization: "Bearer read-secret", expectedCode: codes.PermissionDenied},
+		{name: "unknown services need write", method: "/ccmon.v1.FutureService/Delete", authorization: "Bearer read-secret", expectedCode: codes.PermissionDenied},
+		{name: "health checks are public", method: "/grpc.health.v1.Health/Check", expectedCode: codes.OK},
+		{name: "replicas use the snapshot token", method: "/ccmon.v1.ReplicationService/GetSnapshot", expectedCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			called := false
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(context.Context, any) (any, error) {
+				called = true
+				return nil, nil
+			})
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (%v)", tt.expectedCode, status.Code(err), err)
+			}
+			if called != (tt.expectedCode == codes.OK) {
+				t.Errorf("Expected the handler to be called only when authorized, called = %v", called)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_HTTPMiddleware(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		role           entity.TokenRole
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "write token", role: entity.TokenRoleWrite, authorization: "Bearer write-secret", expectedStatus: http.StatusOK},
+		{name: "read token", role: entity.TokenRoleWrite, authorization: "Bearer read-secret", expectedStatus: http.StatusForbidden},
+		{name: "missing token", role: entity.TokenRoleWrite, expectedStatus: http.StatusUnauthorized},
+		{name: "read token for read", role: entity.TokenRoleRead, authorization: "Bearer read-secret", expectedStatus: http.StatusOK},
+		{name: "write token for read", role: entity.TokenRoleRead, authorization: "Bearer write-secret", expectedStatus: http.StatusOK},
+		{name: "missing token for read", role: entity.TokenRoleRead, expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := authorizer.HTTPMiddleware(ok, tt.role)
+			req := httptest.NewRequest(http.MethodPost, "/v1/logs", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dashboard.token")
+	if err := os.WriteFile(path, []byte("first-secret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	authorizer, err := NewAuthorizer([]entity.AccessToken{{Label: "dashboard", File: path, Role: entity.TokenRoleRead}})
+	if err != nil {
+		t.Fatalf("NewAuthorizer() failed: %v", err)
+	}
+	if !authorizer.HasFiles() {
+		t.Error("Expected the authorizer to have token files")
+	}
+
+	if err := os.WriteFile(path, []byte("second-secret"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	if err := authorizer.Reload(); err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+
+	if _, err := authorizer.Authorize([]string{"Bearer first-secret"}, entity.TokenRoleRead); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected the previous token to be rejected, got %v", err)
+	}
+	if label, err := authorizer.Authorize([]string{"Bearer second-secret"}, entity.TokenRoleRead); err != nil || label != "dashboard" {
+		t.Errorf("Expected the reloaded token to be accepted as dashboard, got %q, %v", label, err)
+	}
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Backup periodically replaces the database backup, which a corrupt database is restored from on startup
+type Backup struct {
+	Command  *usecase.BackupDatabaseCommand // nil disables backups
+	Interval time.Duration
+}
+
+// IsEnabled returns true if backups are scheduled
+func (b Backup) IsEnabled() bool {
+	return b.Command != nil && b.Interval > 0
+}
+
+// startBackupScheduler writes a backup on startup and then at every interval
+func startBackupScheduler(ctx context.Context, backup Backup) {
+	log.Printf("Starting database backup scheduler: interval=%v", backup.Interval)
+
+	go func() {
+		ticker := time.NewTicker(backup.Interval)
+		defer ticker.Stop()
+
+		runBackup(ctx, backup)
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Database backup scheduler stopped")
+				return
+			case <-ticker.C:
+				runBackup(ctx, backup)
+			}
+		}
+	}()
+}
+
+// runBackup writes a single backup and logs the outcome, a failed backup keeps the previous one
+func runBackup(ctx context.Context, backup Backup) {
+	start := time.Now()
+	size, err := backup.Command.Execute(ctx)
+	if err != nil {
+		log.Printf("Database backup failed, the previous backup is kept: %v", err)
+		return
+	}
+	log.Printf("Database backup completed: %d bytes in %v", size, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+const (
+	// cleanupBusyRetry is how long a cleanup waits before checking the receiver queue again
+	cleanupBusyRetry = 30 * time.Second
+	// cleanupMaxDelay is how long a cleanup is postponed at most under write load
+	cleanupMaxDelay = 10 * time.Minute
+)
+
+// cleanupSchedule tracks when the cleanup scheduler runs next
+// It implements usecase.RetentionRepository so clients can preview what the next run removes
+type cleanupSchedule struct {
+	retention time.Duration
+
+	mu        sync.RWMutex
+	nextRunAt time.Time
+}
+
+// newCleanupSchedule creates a schedule for the given retention, zero means records are kept forever
+func newCleanupSchedule(retention time.Duration) *cleanupSchedule {
+	return &cleanupSchedule{retention: retention}
+}
+
+// GetRetention returns the retention policy and when the next cleanup runs
+func (s *cleanupSchedule) GetRetention() (entity.Retention, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return entity.NewRetention(s.retention, s.nextRunAt), nil
+}
+
+// scheduleNext records when the next cleanup runs
+func (s *cleanupSchedule) scheduleNext(nextRunAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextRunAt = nextRunAt
+}
@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// DailySummary sends the end-of-day usage summary at a local time every day
+type DailySummary struct {
+	Command    *usecase.SendDailySummaryCommand // nil disables the summary
+	Schedule   entity.DailySummarySchedule
+	CostFormat entity.CostFormat // used for the summary written to the server log
+}
+
+// IsEnabled returns true if the summary is scheduled
+func (d DailySummary) IsEnabled() bool {
+	return d.Command != nil && d.Schedule.IsEnabled()
+}
+
+// startDailySummaryScheduler sends the summary at the scheduled time of every day
+// The summary is always written to the server log, a failed delivery is retried the next day
+func startDailySummaryScheduler(ctx context.Context, dailySummary DailySummary) {
+	log.Printf("Starting daily summary scheduler: at=%s", dailySummary.Schedule)
+
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(dailySummary.Schedule.NextAt(time.Now())))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				log.Println("Daily summary scheduler stopped")
+				return
+			case <-timer.C:
+				sendDailySummary(ctx, dailySummary)
+			}
+		}
+	}()
+}
+
+// sendDailySummary sends a single summary and logs the outcome
+func sendDailySummary(ctx context.Context, dailySummary DailySummary) {
+	result, err := dailySummary.Command.Execute(ctx)
+	if result != nil {
+		log.Printf("Daily summary: %s", result.Summary.Message(dailySummary.CostFormat))
+	}
+	if err != nil {
+		log.Printf("Daily summary failed: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFdsStart is the first file descriptor passed by systemd socket activation
+const listenFdsStart = 3
+
+// newListener returns the listener inherited via systemd socket activation if present,
+// otherwise it binds a new TCP listener on the given address
+func newListener(address string) (net.Listener, error) {
+	lis, err := systemdListener()
+	if err != nil {
+		return nil, err
+	}
+	if lis != nil {
+		log.Printf("Using socket activated listener on %s (server.address ignored)", lis.Addr())
+		return lis, nil
+	}
+
+	lis, err = net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	return lis, nil
+}
+
+// systemdListener returns the first socket passed by systemd (LISTEN_PID/LISTEN_FDS)
+// Returns nil without error when the process was not socket activated
+func systemdListener() (net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, nil
+	}
+
+	// Unset the variables so they are not inherited by child processes
+	_ = os.Unsetenv("LISTEN_PID")
+	_ = os.Unsetenv("LISTEN_FDS")
+	_ = os.Unsetenv("LISTEN_FDNAMES")
+
+	if fds > 1 {
+		log.Printf("Warning: received %d sockets from systemd, only the first one is used", fds)
+	}
+
+	return activatedListener(listenFdsStart)
+}
+
+// activatedListener converts an inherited file descriptor into a net.Listener
+func activatedListener(fd uintptr) (net.Listener, error) {
+	file := os.NewFile(fd, "LISTEN_FD_"+strconv.Itoa(int(fd)))
+	if file == nil {
+		return nil, fmt.Errorf("invalid socket activation file descriptor: %d", fd)
+	}
+	defer func() {
+		// net.FileListener duplicates the descriptor, so the original can be closed
+		if err := file.Close(); err != nil {
+			log.Printf("Error closing activated socket descriptor: %v", err)
+		}
+	}()
+
+	lis, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use socket activation file descriptor %d: %w", fd, err)
+	}
+	return lis, nil
+}
@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestSystemdListener_NotActivated(t *testing.T) {
+	tests := []struct {
+		name      string
+		listenPID string
+		listenFDs string
+	}{
+		{
+			name: "no environment variables",
+		},
+		{
+			name:      "pid belongs to another process",
+			listenPID: strconv.Itoa(os.Getpid() + 1),
+			listenFDs: "1",
+		},
+		{
+			name:      "no file descriptors passed",
+			listenPID: strconv.Itoa(os.Getpid()),
+			listenFDs: "0",
+		},
+		{
+			name:      "invalid file descriptor count",
+			listenPID: strconv.Itoa(os.Getpid()),
+			listenFDs: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LISTEN_PID", tt.listenPID)
+			t.Setenv("LISTEN_FDS", tt.listenFDs)
+
+			lis, err := systemdListener()
+			if err != nil {
+				t.Fatalf("systemdListener() unexpected error = %v", err)
+			}
+			if lis != nil {
+				t.Errorf("systemdListener() = %v, want nil listener", lis.Addr())
+			}
+		})
+	}
+}
+
+func TestActivatedListener(t *testing.T) {
+	original, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	defer func() {
+		if err := original.Close(); err != nil {
+			t.Logf("Error closing listener: %v", err)
+		}
+	}()
+
+	file, err := original.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatalf("failed to get listener file: %v", err)
+	}
+
+	lis, err := activatedListener(file.Fd())
+	if err != nil {
+		t.Fatalf("activatedListener() unexpected error = %v", err)
+	}
+	defer func() {
+		if err := lis.Close(); err != nil {
+			t.Logf("Error closing activated listener: %v", err)
+		}
+	}()
+
+	if lis.Addr().String() != original.Addr().String() {
+		t.Errorf("activatedListener() addr = %s, want %s", lis.Addr(), original.Addr())
+	}
+}
+
+func TestNewListener_FallbackToAddress(t *testing.T) {
+	t.Setenv("LISTEN_PID", "")
+	t.Setenv("LISTEN_FDS", "")
+
+	lis, err := newListener("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("newListener() unexpected error = %v", err)
+	}
+	defer func() {
+		if err := lis.Close(); err != nil {
+			t.Logf("Error closing listener: %v", err)
+		}
+	}()
+
+	if _, ok := lis.(*net.TCPListener); !ok {
+		t.Errorf("newListener() = %T, want *net.TCPListener", lis)
+	}
+}
+
+func TestDropPrivileges(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantErr  bool
+	}{
+		{
+			name:     "empty user keeps current privileges",
+			username: "",
+			wantErr:  false,
+		},
+		{
+			name:     "unknown user",
+			username: "ccmon-nonexistent-user",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dropPrivileges(tt.username)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("dropPrivileges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
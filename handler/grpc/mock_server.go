@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/grpc/query"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc"
+)
+
+// MockTraffic keeps adding synthetic requests to the mock server as if Claude Code was running
+type MockTraffic struct {
+	Command  *usecase.GenerateMockRequestsCommand
+	Interval time.Duration
+}
+
+// RunMockServer runs the query service backed by synthetic requests for developing clients
+// There is no OTLP receiver, database or background job, only the generated traffic changes the data
+func RunMockServer(address string, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUserUsageQuery *usecase.GetUserUsageQuery, watchQuery *usecase.WatchApiRequestsQuery, traffic MockTraffic, httpHandler http.Handler, serverConfig ServerConfig) error {
+	log.Println("Starting ccmon in mock mode, serving synthetic data...")
+
+	// The history is generated before clients connect, so the first query already sees it
+	result, err := traffic.Command.Execute(context.Background(), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to generate mock history: %w", err)
+	}
+	log.Printf("Generated %d mock requests", result.Saved)
+
+	queryService := query.NewService(getFilteredQuery, calculateStatsQuery)
+	queryService.SetRetentionQuery(usecase.NewGetRetentionQuery(newCleanupSchedule(0)))
+	queryService.SetUserUsageQuery(getUserUsageQuery)
+	queryService.SetWatchQuery(watchQuery)
+
+	var httpLis net.Listener
+	if httpAddress := serverConfig.GetHTTPAddress(); httpAddress != "" && httpHandler != nil {
+		httpLis, err = net.Listen("tcp", httpAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", httpAddress, err)
+		}
+	}
+
+	lis, err := listen(address, serverConfig)
+	if err != nil {
+		closeListeners(httpLis)
+		return err
+	}
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterQueryServiceServer(grpcServer, queryService)
+
+	return serve(grpcServer, lis, "gRPC mock server (Query)", func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			queryService.StopWatching()
+		}()
+
+		if httpLis != nil {
+			startHTTPServer(ctx, httpLis, httpHandler, "HTTP API")
+		}
+
+		startMockTraffic(ctx, traffic)
+	})
+}
+
+// startMockTraffic stores the requests generated since the previous run every interval
+func startMockTraffic(ctx context.Context, traffic MockTraffic) {
+	log.Printf("Starting mock traffic: interval=%v", traffic.Interval)
+
+	go func() {
+		ticker := time.NewTicker(traffic.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Mock traffic stopped")
+				return
+			case now := <-ticker.C:
+				if _, err := traffic.Command.Execute(ctx, now); err != nil {
+					log.Printf("Mock traffic failed: %v", err)
+				}
+			}
+		}
+	}()
+}
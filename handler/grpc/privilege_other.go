@@ -0,0 +1,13 @@
+//go:build !unix
+
+package grpc
+
+import "fmt"
+
+// dropPrivileges is not supported on non-unix platforms
+func dropPrivileges(username string) error {
+	if username == "" {
+		return nil
+	}
+	return fmt.Errorf("switching to user %s is not supported on this platform", username)
+}
@@ -0,0 +1,55 @@
+//go:build unix
+
+package grpc
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// dropPrivileges switches the process to the given user and its primary group
+// It must be called after binding the listener so privileged ports remain usable
+func dropPrivileges(username string) error {
+	if username == "" {
+		return nil
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil {
+		return fmt.Errorf("failed to lookup user %s: %w", username, err)
+	}
+
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return fmt.Errorf("invalid uid for user %s: %s", username, u.Uid)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return fmt.Errorf("invalid gid for user %s: %s", username, u.Gid)
+	}
+
+	// Already running as the target user, nothing to drop
+	if os.Getuid() == uid && os.Getgid() == gid {
+		return nil
+	}
+
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("cannot switch to user %s: not running as root", username)
+	}
+
+	// Order matters: groups must be changed while we still have root privileges
+	if err := syscall.Setgroups([]int{gid}); err != nil {
+		return fmt.Errorf("failed to set supplementary groups: %w", err)
+	}
+	if err := syscall.Setgid(gid); err != nil {
+		return fmt.Errorf("failed to set gid %d: %w", gid, err)
+	}
+	if err := syscall.Setuid(uid); err != nil {
+		return fmt.Errorf("failed to set uid %d: %w", uid, err)
+	}
+
+	return nil
+}
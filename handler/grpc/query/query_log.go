@@ -0,0 +1,125 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// QueryLog logs query service calls, calls slower than the threshold are also written to the slow query log and counted
+// It helps operators find the dashboards or clients issuing pathological range scans
+type QueryLog struct {
+	access        *log.Logger // nil disables the access log
+	slow          *log.Logger // nil disables the slow query log
+	slowThreshold time.Duration
+	slowQueries   atomic.Int64
+}
+
+// NewQueryLog creates a new QueryLog, a nil logger disables its log
+func NewQueryLog(access *log.Logger, slow *log.Logger, slowThreshold time.Duration) *QueryLog {
+	return &QueryLog{
+		access:        access,
+		slow:          slow,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// SlowQueries returns the number of calls slower than the threshold since the server started
+func (l *QueryLog) SlowQueries() int64 {
+	return l.slowQueries.Load()
+}
+
+// UnaryServerInterceptor logs the query service calls, other services are passed through
+func (l *QueryLog) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	prefix := "/" + pb.QueryService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+
+		startAt := time.Now()
+		resp, err := handler(ctx, req)
+		l.Record(ctx, strings.TrimPrefix(info.FullMethod, prefix), req, resp, err, time.Since(startAt))
+
+		return resp, err
+	}
+}
+
+// Record logs a single query service call
+func (l *QueryLog) Record(ctx context.Context, method string, req any, resp any, err error, duration time.Duration) {
+	isSlow := l.slow != nil && duration >= l.slowThreshold
+	if l.access == nil && !isSlow {
+		return
+	}
+
+	line := describeQuery(ctx, method, req, resp, err, duration)
+	if l.access != nil {
+		l.access.Println(line)
+	}
+	if isSlow {
+		l.slowQueries.Add(1)
+		l.slow.Println(line)
+	}
+}
+
+// describeQuery formats the call with its period span and result size, e.g. "GetAPIRequests 1.2s span=720h0m0s results=5000 peer=127.0.0.1:51234"
+func describeQuery(ctx context.Context, method string, req any, resp any, err error, duration time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", method, duration.Round(time.Millisecond))
+
+	switch req := req.(type) {
+	case *pb.GetStatsRequest:
+		fmt.Fprintf(&b, " span=%s", querySpan(req.StartTime, req.EndTime))
+	case *pb.GetAPIRequestsRequest:
+		fmt.Fprintf(&b, " span=%s limit=%d offset=%d", querySpan(req.StartTime, req.EndTime), req.Limit, req.Offset)
+		if req.PageToken != "" {
+			b.WriteString(" paged") // continues from a page token
+		}
+	case *pb.GetUserUsageRequest:
+		fmt.Fprintf(&b, " span=%s", querySpan(req.StartTime, req.EndTime))
+	case *pb.CountAPIRequestsRequest:
+		fmt.Fprintf(&b, " span=%s", querySpan(req.StartTime, req.EndTime))
+	}
+
+	switch resp := resp.(type) {
+	case *pb.GetStatsResponse:
+		fmt.Fprintf(&b, " results=%d", resp.GetStats().GetTotalRequests()) // requests aggregated into the stats
+	case *pb.GetAPIRequestsResponse:
+		fmt.Fprintf(&b, " results=%d", len(resp.Requests))
+	case *pb.GetUserUsageResponse:
+		fmt.Fprintf(&b, " results=%d", len(resp.Users))
+	case *pb.CountAPIRequestsResponse:
+		fmt.Fprintf(&b, " results=%d", resp.Count) // requests counted
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fmt.Fprintf(&b, " peer=%s", p.Addr)
+	}
+	if err != nil {
+		fmt.Fprintf(&b, " error=%q", err.Error())
+	}
+
+	return b.String()
+}
+
+// querySpan returns the duration between the request timestamps, "all" when the start is not set
+func querySpan(start, end *timestamppb.Timestamp) string {
+	if start == nil {
+		return "all"
+	}
+
+	endAt := time.Now()
+	if end != nil {
+		endAt = end.AsTime()
+	}
+	return endAt.Sub(start.AsTime()).Round(time.Second).String()
+}
@@ -0,0 +1,124 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestQueryLog_UnaryServerInterceptor(t *testing.T) {
+	baseTime := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	getAPIRequests := &pb.GetAPIRequestsRequest{
+		StartTime: timestamppb.New(baseTime),
+		EndTime:   timestamppb.New(baseTime.Add(30 * 24 * time.Hour)),
+		Limit:     100,
+	}
+	getAPIRequestsResp := &pb.GetAPIRequestsResponse{Requests: make([]*pb.APIRequest, 3)}
+
+	tests := []struct {
+		name            string
+		method          string
+		req             any
+		resp            any
+		err             error
+		delay           time.Duration
+		expectedAccess  []string
+		expectedSlow    []string
+		expectedCounted int64
+	}{
+		{
+			name:           "fast query is only in the access log",
+			method:         "/ccmon.v1.QueryService/GetAPIRequests",
+			req:            getAPIRequests,
+			resp:           getAPIRequestsResp,
+			expectedAccess: []string{"GetAPIRequests", "span=720h0m0s limit=100 offset=0", "results=3"},
+		},
+		{
+			name:            "slow query is logged and counted",
+			method:          "/ccmon.v1.QueryService/GetStats",
+			req:             &pb.GetStatsRequest{},
+			resp:            &pb.GetStatsResponse{Stats: &pb.Stats{TotalRequests: 42}},
+			delay:           20 * time.Millisecond,
+			expectedAccess:  []string{"GetStats"},
+			expectedSlow:    []string{"GetStats", "span=all", "results=42"},
+			expectedCounted: 1,
+		},
+		{
+			name:            "failed slow query includes the error",
+			method:          "/ccmon.v1.QueryService/GetUserUsage",
+			req:             &pb.GetUserUsageRequest{},
+			err:             errors.New("database locked"),
+			delay:           20 * time.Millisecond,
+			expectedAccess:  []string{"GetUserUsage"},
+			expectedSlow:    []string{"GetUserUsage", `error="database locked"`},
+			expectedCounted: 1,
+		},
+		{
+			name:   "other services are not logged",
+			method: "/opentelemetry.proto.collector.logs.v1.LogsService/Export",
+			delay:  20 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var access, slow bytes.Buffer
+			queryLog := NewQueryLog(log.New(&access, "", 0), log.New(&slow, "", 0), 10*time.Millisecond)
+
+			handler := func(ctx context.Context, req any) (any, error) {
+				time.Sleep(tt.delay)
+				return tt.resp, tt.err
+			}
+			_, err := queryLog.UnaryServerInterceptor()(context.Background(), tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+
+			assertLog(t, "access", access.String(), tt.expectedAccess)
+			assertLog(t, "slow", slow.String(), tt.expectedSlow)
+			if got := queryLog.SlowQueries(); got != tt.expectedCounted {
+				t.Errorf("Expected %d slow queries, got %d", tt.expectedCounted, got)
+			}
+		})
+	}
+}
+
+func assertLog(t *testing.T, name string, got string, expected []string) {
+	t.Helper()
+
+	if len(expected) == 0 {
+		if got != "" {
+			t.Errorf("Expected empty %s log, got %q", name, got)
+		}
+		return
+	}
+	for _, want := range expected {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %s log to contain %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestQueryService_GetServerMetrics_SlowQueries(t *testing.T) {
+	queryLog := NewQueryLog(nil, log.New(&bytes.Buffer{}, "", 0), 0)
+	queryLog.Record(context.Background(), "GetStats", &pb.GetStatsRequest{}, nil, nil, time.Second)
+
+	service := NewService(nil, nil)
+	service.SetQueryLog(queryLog)
+
+	resp, err := service.GetServerMetrics(context.Background(), &pb.GetServerMetricsRequest{})
+	if err != nil {
+		t.Fatalf("GetServerMetrics failed: %v", err)
+	}
+	if resp.SlowQueries != 1 {
+		t.Errorf("Expected 1 slow query, got %d", resp.SlowQueries)
+	}
+}
@@ -2,12 +2,18 @@ package query
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
 	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -16,78 +22,312 @@ type Service struct {
 	pb.UnimplementedQueryServiceServer
 	getFilteredQuery    *usecase.GetFilteredApiRequestsQuery
 	calculateStatsQuery *usecase.CalculateStatsQuery
+	ingestionLagQuery   *usecase.GetIngestionLagQuery
+	ingestStatsQuery    *usecase.GetIngestStatsQuery
+	retentionQuery      *usecase.GetRetentionQuery
+	userUsageQuery      *usecase.GetUserUsageQuery
+	watchQuery          *usecase.WatchApiRequestsQuery
+	watchDone           chan struct{}
+	stopWatching        sync.Once
+	queryLog            *QueryLog
 }
 
 // NewService creates a new query service instance
 func NewService(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery) *Service {
+	return NewServiceWithIngestionLag(getFilteredQuery, calculateStatsQuery, nil)
+}
+
+// NewServiceWithIngestionLag creates a new query service instance that also reports ingestion lag metrics
+func NewServiceWithIngestionLag(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, ingestionLagQuery *usecase.GetIngestionLagQuery) *Service {
 	return &Service{
 		getFilteredQuery:    getFilteredQuery,
 		calculateStatsQuery: calculateStatsQuery,
+		ingestionLagQuery:   ingestionLagQuery,
 	}
 }
 
+// SetRetentionQuery enables reporting the retention policy in the server metrics
+func (s *Service) SetRetentionQuery(retentionQuery *usecase.GetRetentionQuery) {
+	s.retentionQuery = retentionQuery
+}
+
+// SetIngestStatsQuery enables reporting the outcomes of the received API request events
+func (s *Service) SetIngestStatsQuery(ingestStatsQuery *usecase.GetIngestStatsQuery) {
+	s.ingestStatsQuery = ingestStatsQuery
+}
+
+// SetQueryLog enables reporting the number of slow queries in the server metrics
+func (s *Service) SetQueryLog(queryLog *QueryLog) {
+	s.queryLog = queryLog
+}
+
+// SetUserUsageQuery enables reporting the usage of each user with their quota status
+func (s *Service) SetUserUsageQuery(userUsageQuery *usecase.GetUserUsageQuery) {
+	s.userUsageQuery = userUsageQuery
+}
+
+// SetWatchQuery enables streaming API requests to monitors as they are stored
+func (s *Service) SetWatchQuery(watchQuery *usecase.WatchApiRequestsQuery) {
+	s.watchQuery = watchQuery
+	s.watchDone = make(chan struct{})
+}
+
+// StopWatching ends every open watch stream, a graceful stop waits for them otherwise
+func (s *Service) StopWatching() {
+	if s.watchDone == nil {
+		return
+	}
+	s.stopWatching.Do(func() { close(s.watchDone) })
+}
+
 // GetStats returns aggregated statistics based on time range
 func (s *Service) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
 	// Convert proto timestamps to entity.Period
 	period := convertTimestampsToPeriod(req.StartTime, req.EndTime)
+	if req.At != nil {
+		period = period.Until(req.At.AsTime())
+	}
+	if err := period.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	// Get stats via usecase
-	params := usecase.CalculateStatsParams{Period: period}
+	params := usecase.CalculateStatsParams{Period: period, Origin: req.Origin, Project: req.Project, User: req.User, Approximate: req.Approximate, Fresh: req.Fresh}
 	stats, err := s.calculateStatsQuery.Execute(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
 
-	// Convert to protobuf response
-	pbStats := &pb.Stats{
-		BaseRequests:    int32(stats.BaseRequests()),
-		PremiumRequests: int32(stats.PremiumRequests()),
-		TotalRequests:   int32(stats.TotalRequests()),
-		BaseTokens:      convertTokenToProto(stats.BaseTokens()),
-		PremiumTokens:   convertTokenToProto(stats.PremiumTokens()),
-		TotalTokens:     convertTokenToProto(stats.TotalTokens()),
-		BaseCost:        convertCostToProto(stats.BaseCost()),
-		PremiumCost:     convertCostToProto(stats.PremiumCost()),
-		TotalCost:       convertCostToProto(stats.TotalCost()),
+	resp := &pb.GetStatsResponse{
+		Stats:       convertStatsToProto(stats),
+		Approximate: stats.IsApproximate(),
 	}
-
-	return &pb.GetStatsResponse{
-		Stats: pbStats,
-	}, nil
+	if stats.IsCached() {
+		resp.CachedAt = timestamppb.New(stats.CachedAt())
+	}
+	return resp, nil
 }
 
 // GetAPIRequests returns API request records based on filters
 func (s *Service) GetAPIRequests(ctx context.Context, req *pb.GetAPIRequestsRequest) (*pb.GetAPIRequestsResponse, error) {
-	// Convert proto timestamps to entity.Period
-	period := convertTimestampsToPeriod(req.StartTime, req.EndTime)
+	// Convert proto timestamps and filter to entity.Filter
+	filter := convertProtoToFilter(convertTimestampsToPeriod(req.StartTime, req.EndTime), req.Filter)
+	if err := filter.Period().Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fields, err := apiRequestFields(req.Fields)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.PageToken != "" && req.Offset > 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_token cannot be combined with offset")
+	}
+	cursor, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	// Get requests via usecase with limit and offset
 	params := usecase.GetFilteredApiRequestsParams{
-		Period: period,
+		Filter: filter,
 		Limit:  int(req.Limit),
 		Offset: int(req.Offset),
+		Cursor: cursor,
+	}
+
+	// Limited pages without offset continue from a cursor, so older pages don't walk the newer records again
+	var requests []entity.APIRequest
+	var next string
+	if req.PageToken != "" || (req.Limit > 0 && req.Offset == 0) {
+		requests, next, err = s.getFilteredQuery.ExecutePage(ctx, params)
+	} else {
+		requests, err = s.getFilteredQuery.Execute(ctx, params)
+	}
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
 	}
-	requests, err := s.getFilteredQuery.Execute(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get requests: %w", err)
 	}
 
 	// Note: TotalCount is now the count of returned records since pagination
-	// is handled at repository level. For true total count, we'd need a separate query.
+	// is handled at repository level. The true total count is served by CountAPIRequests.
 	totalCount := len(requests)
 
 	// Convert to protobuf messages
 	pbRequests := make([]*pb.APIRequest, len(requests))
 	for i, apiReq := range requests {
-		pbRequests[i] = convertAPIRequestToProto(apiReq)
+		pbRequests[i] = maskAPIRequest(convertAPIRequestToProto(apiReq), fields)
 	}
 
 	return &pb.GetAPIRequestsResponse{
-		Requests:   pbRequests,
-		TotalCount: int32(totalCount),
+		Requests:      pbRequests,
+		TotalCount:    int32(totalCount),
+		NextPageToken: encodePageToken(next),
+	}, nil
+}
+
+// CountAPIRequests returns the number of API requests matching the period and the filter
+func (s *Service) CountAPIRequests(ctx context.Context, req *pb.CountAPIRequestsRequest) (*pb.CountAPIRequestsResponse, error) {
+	filter := convertProtoToFilter(convertTimestampsToPeriod(req.StartTime, req.EndTime), req.Filter)
+	if err := filter.Period().Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	count, err := s.getFilteredQuery.Count(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count requests: %w", err)
+	}
+
+	return &pb.CountAPIRequestsResponse{
+		Count: int64(count),
+	}, nil
+}
+
+// WatchAPIRequests streams the API requests matching the filter dimensions as they are stored
+func (s *Service) WatchAPIRequests(req *pb.WatchAPIRequestsRequest, stream pb.QueryService_WatchAPIRequestsServer) error {
+	// Replicas only see requests when a snapshot is restored, monitors keep polling them
+	if s.watchQuery == nil {
+		return s.UnimplementedQueryServiceServer.WatchAPIRequests(req, stream)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.watchDone:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	filter := convertProtoToFilter(entity.NewAllTimePeriod(time.Now()), req.Filter)
+
+	return s.watchQuery.Execute(ctx, filter, func(requests []entity.APIRequest) error {
+		pbRequests := make([]*pb.APIRequest, len(requests))
+		for i, apiReq := range requests {
+			pbRequests[i] = convertAPIRequestToProto(apiReq)
+		}
+
+		if err := stream.Send(&pb.WatchAPIRequestsResponse{Requests: pbRequests}); err != nil {
+			return fmt.Errorf("failed to send requests: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetServerMetrics returns server-side ingestion metrics
+func (s *Service) GetServerMetrics(ctx context.Context, req *pb.GetServerMetricsRequest) (*pb.GetServerMetricsResponse, error) {
+	// Servers without lag tracking report empty metrics
+	var lag entity.IngestionLag
+	if s.ingestionLagQuery != nil {
+		var err error
+		lag, err = s.ingestionLagQuery.Execute(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ingestion lag: %w", err)
+		}
+	}
+
+	resp := &pb.GetServerMetricsResponse{
+		IngestionLag: &pb.IngestionLag{
+			Samples:   lag.Samples(),
+			AverageMs: lag.Average().Milliseconds(),
+			MaxMs:     lag.Max().Milliseconds(),
+		},
+	}
+
+	// Replicas leave retention to the primary and do not report it
+	if s.retentionQuery != nil {
+		retention, err := s.retentionQuery.Execute(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get retention: %w", err)
+		}
+		resp.Retention = convertRetentionToProto(retention)
+	}
+
+	if s.queryLog != nil {
+		resp.SlowQueries = s.queryLog.SlowQueries()
+	}
+
+	return resp, nil
+}
+
+// GetIngestStats returns the outcomes of the API request events received over the last hour and day
+func (s *Service) GetIngestStats(ctx context.Context, req *pb.GetIngestStatsRequest) (*pb.GetIngestStatsResponse, error) {
+	// Replicas never receive events and do not report them
+	if s.ingestStatsQuery == nil {
+		return s.UnimplementedQueryServiceServer.GetIngestStats(ctx, req)
+	}
+
+	stats, err := s.ingestStatsQuery.Execute(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingest stats: %w", err)
+	}
+
+	return &pb.GetIngestStatsResponse{
+		LastHour: convertIngestCountsToProto(stats.LastHour()),
+		LastDay:  convertIngestCountsToProto(stats.LastDay()),
+		Since:    timestamppb.New(stats.Since()),
 	}, nil
 }
 
+// convertIngestCountsToProto converts entity.IngestCounts to protobuf IngestCounts
+func convertIngestCountsToProto(counts entity.IngestCounts) *pb.IngestCounts {
+	return &pb.IngestCounts{
+		Accepted:   counts.Accepted(),
+		Ignored:    counts.Ignored(),
+		Dropped:    counts.Dropped(),
+		Malformed:  counts.Malformed(),
+		Duplicated: counts.Duplicated(),
+		Failed:     counts.Failed(),
+	}
+}
+
+// GetUserUsage returns the daily and block usage of each user with their quota status
+func (s *Service) GetUserUsage(ctx context.Context, req *pb.GetUserUsageRequest) (*pb.GetUserUsageResponse, error) {
+	// Replicas leave user quotas to the primary and do not report them
+	if s.userUsageQuery == nil {
+		return s.UnimplementedQueryServiceServer.GetUserUsage(ctx, req)
+	}
+
+	params := usecase.GetUserUsageParams{Daily: convertTimestampsToPeriod(req.StartTime, req.EndTime)}
+	if req.BlockStartTime != nil && req.BlockEndTime != nil {
+		block := entity.NewPeriod(req.BlockStartTime.AsTime(), req.BlockEndTime.AsTime())
+		params.Block = &block
+	}
+
+	usages, err := s.userUsageQuery.Execute(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user usage: %w", err)
+	}
+
+	pbUsers := make([]*pb.UserUsage, len(usages))
+	for i, usage := range usages {
+		pbUsers[i] = &pb.UserUsage{
+			User:       usage.User(),
+			Daily:      convertStatsToProto(usage.Daily()),
+			Block:      convertStatsToProto(usage.Block()),
+			DailyQuota: convertCostToProto(usage.Quota()),
+			Status:     string(usage.Status()),
+		}
+	}
+
+	return &pb.GetUserUsageResponse{
+		Users: pbUsers,
+	}, nil
+}
+
+// convertRetentionToProto converts entity.Retention to protobuf Retention
+func convertRetentionToProto(retention entity.Retention) *pb.Retention {
+	pbRetention := &pb.Retention{
+		DurationMs: retention.Duration().Milliseconds(),
+	}
+	if !retention.NextCleanupAt().IsZero() {
+		pbRetention.NextCleanupAt = timestamppb.New(retention.NextCleanupAt())
+	}
+	return pbRetention
+}
+
 // convertTimestampsToPeriod converts protobuf timestamps to entity.Period
 func convertTimestampsToPeriod(startTime, endTime *timestamppb.Timestamp) entity.Period {
 	// Handle nil timestamps - use all time period
@@ -114,6 +354,57 @@ func convertTimestampsToPeriod(startTime, endTime *timestamppb.Timestamp) entity
 	return entity.NewPeriod(start, end)
 }
 
+// convertProtoToFilter converts the period and protobuf RequestFilter to entity.Filter, a nil filter keeps the whole period
+func convertProtoToFilter(period entity.Period, filter *pb.RequestFilter) entity.Filter {
+	return entity.NewFilter(period).
+		WithModel(filter.GetModel()).
+		WithSessionID(filter.GetSessionId()).
+		WithSource(filter.GetSource()).
+		WithOrigin(filter.GetOrigin()).
+		WithUser(filter.GetUser()).
+		WithProject(filter.GetProject()).
+		WithSearch(filter.GetSearch())
+}
+
+// convertStatsToProto converts entity.Stats to protobuf Stats
+func convertStatsToProto(stats entity.Stats) *pb.Stats {
+	return &pb.Stats{
+		BaseRequests:    int32(stats.BaseRequests()),
+		PremiumRequests: int32(stats.PremiumRequests()),
+		TotalRequests:   int32(stats.TotalRequests()),
+		BaseTokens:      convertTokenToProto(stats.BaseTokens()),
+		PremiumTokens:   convertTokenToProto(stats.PremiumTokens()),
+		TotalTokens:     convertTokenToProto(stats.TotalTokens()),
+		BaseCost:        convertCostToProto(stats.BaseCost()),
+		PremiumCost:     convertCostToProto(stats.PremiumCost()),
+		TotalCost:       convertCostToProto(stats.TotalCost()),
+
+		LongContextRequests: int32(stats.LongContextRequests()),
+		LongContextTokens:   convertTokenToProto(stats.LongContextTokens()),
+		LongContextCost:     convertCostToProto(stats.LongContextCost()),
+
+		Models: convertModelStatsToProto(stats.Models()),
+	}
+}
+
+// convertModelStatsToProto converts the usage of each model to protobuf ModelStats
+func convertModelStatsToProto(models []entity.ModelStats) []*pb.ModelStats {
+	if len(models) == 0 {
+		return nil
+	}
+
+	pbModels := make([]*pb.ModelStats, 0, len(models))
+	for _, model := range models {
+		pbModels = append(pbModels, &pb.ModelStats{
+			Model:    model.Model(),
+			Requests: int32(model.Requests()),
+			Tokens:   convertTokenToProto(model.Tokens()),
+			Cost:     convertCostToProto(model.Cost()),
+		})
+	}
+	return pbModels
+}
+
 // convertTokenToProto converts entity.Token to protobuf Token
 func convertTokenToProto(token entity.Token) *pb.Token {
 	return &pb.Token{
@@ -124,6 +415,7 @@ func convertTokenToProto(token entity.Token) *pb.Token {
 		CacheCreation: token.CacheCreation(),
 		Limited:       token.Limited(),
 		Cache:         token.Cache(),
+		ToolUse:       token.ToolUse(),
 	}
 }
 
@@ -144,8 +436,76 @@ func convertAPIRequestToProto(req entity.APIRequest) *pb.APIRequest {
 		OutputTokens:        req.Tokens().Output(),
 		CacheReadTokens:     req.Tokens().CacheRead(),
 		CacheCreationTokens: req.Tokens().CacheCreation(),
+		ToolUseTokens:       req.Tokens().ToolUse(),
 		TotalTokens:         req.Tokens().Total(),
 		CostUsd:             req.Cost().Amount(),
 		DurationMs:          req.DurationMS(),
+		Source:              req.Source(),
+		Origin:              req.Origin(),
+		User:                req.User(),
+		Project:             req.Project(),
+		Star:                convertStarScopeToProto(req.Star()),
+	}
+}
+
+// encodePageToken wraps the cursor of a page into an opaque page token, empty when no page remains
+func encodePageToken(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// decodePageToken restores the cursor from a page token, an empty token starts at the newest request
+func decodePageToken(token string) (string, error) {
+	cursor, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || (token != "" && len(cursor) == 0) {
+		return "", errors.New("invalid page_token")
+	}
+	return string(cursor), nil
+}
+
+// apiRequestFields resolves the requested APIRequest field names, nil keeps every field
+func apiRequestFields(names []string) (map[protoreflect.Name]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	descriptor := (&pb.APIRequest{}).ProtoReflect().Descriptor().Fields()
+	fields := make(map[protoreflect.Name]bool, len(names))
+	for _, name := range names {
+		if descriptor.ByName(protoreflect.Name(name)) == nil {
+			return nil, fmt.Errorf("unknown APIRequest field %q", name)
+		}
+		fields[protoreflect.Name(name)] = true
+	}
+	return fields, nil
+}
+
+// maskAPIRequest clears the fields which are not requested, so they are left out of the response
+func maskAPIRequest(req *pb.APIRequest, fields map[protoreflect.Name]bool) *pb.APIRequest {
+	if fields == nil {
+		return req
+	}
+
+	message := req.ProtoReflect()
+	message.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !fields[field.Name()] {
+			message.Clear(field)
+		}
+		return true
+	})
+	return req
+}
+
+// convertStarScopeToProto converts entity.StarScope to protobuf StarScope
+func convertStarScopeToProto(scope entity.StarScope) pb.StarScope {
+	switch scope {
+	case entity.StarRequest:
+		return pb.StarScope_STAR_SCOPE_REQUEST
+	case entity.StarSession:
+		return pb.StarScope_STAR_SCOPE_SESSION
+	default:
+		return pb.StarScope_STAR_SCOPE_UNSPECIFIED
 	}
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
 	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -27,9 +29,73 @@ func TestQueryService_GetStats(t *testing.T) {
 		requests      []entity.APIRequest
 		startTime     *time.Time
 		endTime       *time.Time
+		at            *time.Time
+		origin        string
 		expectedStats func(t *testing.T, stats *pb.Stats)
 		expectError   bool
 	}{
+		{
+			name: "at_excludes_later_requests",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"earlier", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(1.00),
+					1500,
+				),
+				mustCreateAPIRequest(
+					"later", baseTime.Add(2*time.Hour), // After the point in time
+					"claude-3-sonnet-20240229",
+					entity.NewToken(300, 150, 30, 15),
+					entity.NewCost(1.50),
+					2000,
+				),
+			},
+			startTime: nil, // All time
+			endTime:   func() *time.Time { t := baseTime.Add(24 * time.Hour); return &t }(),
+			at:        func() *time.Time { t := baseTime.Add(time.Hour); return &t }(),
+			expectedStats: func(t *testing.T, stats *pb.Stats) {
+				if stats.TotalRequests != 1 {
+					t.Errorf("Expected 1 total request, got %d", stats.TotalRequests)
+				}
+				if stats.TotalCost.Amount != 1.00 {
+					t.Errorf("Expected $1.00 total cost, got $%.2f", stats.TotalCost.Amount)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "origin_excludes_imported_requests",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"live", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(1.00),
+					1500,
+				),
+				mustCreateAPIRequest(
+					"imported", baseTime.Add(time.Hour),
+					"claude-3-sonnet-20240229",
+					entity.NewToken(300, 150, 30, 15),
+					entity.NewCost(1.50),
+					2000,
+				).WithOrigin(entity.OriginImport),
+			},
+			startTime: nil, // All time
+			endTime:   nil,
+			origin:    entity.OriginLive,
+			expectedStats: func(t *testing.T, stats *pb.Stats) {
+				if stats.TotalRequests != 1 {
+					t.Errorf("Expected 1 total request, got %d", stats.TotalRequests)
+				}
+				if stats.TotalCost.Amount != 1.00 {
+					t.Errorf("Expected $1.00 total cost, got $%.2f", stats.TotalCost.Amount)
+				}
+			},
+			expectError: false,
+		},
 		{
 			name: "mixed_requests_all_time",
 			requests: []entity.APIRequest{
@@ -228,6 +294,48 @@ func TestQueryService_GetStats(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "long_context_models",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"sonnet", baseTime,
+					"claude-sonnet-4-20250514",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(1.00),
+					1500,
+				),
+				mustCreateAPIRequest(
+					"sonnet-1m", baseTime.Add(time.Hour),
+					"claude-sonnet-4-20250514[1m]",
+					entity.NewToken(4000, 200, 400, 20),
+					entity.NewCost(6.00),
+					2500,
+				),
+			},
+			startTime: nil,
+			endTime:   nil,
+			expectedStats: func(t *testing.T, stats *pb.Stats) {
+				if stats.PremiumRequests != 1 {
+					t.Errorf("Expected 1 premium request, got %d", stats.PremiumRequests)
+				}
+				if stats.LongContextRequests != 1 {
+					t.Errorf("Expected 1 long context request, got %d", stats.LongContextRequests)
+				}
+				if stats.TotalRequests != 2 {
+					t.Errorf("Expected 2 total requests, got %d", stats.TotalRequests)
+				}
+				if stats.LongContextTokens.Total != 4620 {
+					t.Errorf("Expected 4620 long context tokens, got %d", stats.LongContextTokens.Total)
+				}
+				if stats.LongContextCost.Amount != 6.00 {
+					t.Errorf("Expected $6.00 long context cost, got $%.2f", stats.LongContextCost.Amount)
+				}
+				if stats.TotalCost.Amount != 7.00 {
+					t.Errorf("Expected $7.00 total cost, got $%.2f", stats.TotalCost.Amount)
+				}
+			},
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -244,13 +352,16 @@ func TestQueryService_GetStats(t *testing.T) {
 			service := NewService(nil, calculateStatsQuery) // getFilteredQuery not needed for this test
 
 			// Create request
-			req := &pb.GetStatsRequest{}
+			req := &pb.GetStatsRequest{Origin: tt.origin}
 			if tt.startTime != nil {
 				req.StartTime = timestamppb.New(*tt.startTime)
 			}
 			if tt.endTime != nil {
 				req.EndTime = timestamppb.New(*tt.endTime)
 			}
+			if tt.at != nil {
+				req.At = timestamppb.New(*tt.at)
+			}
 
 			// Call service
 			ctx := context.Background()
@@ -278,6 +389,128 @@ func TestQueryService_GetStats(t *testing.T) {
 	}
 }
 
+func TestQueryService_GetStats_Approximate(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("session", baseTime, "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(1.00), 1500),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{})
+	service := NewService(nil, calculateStatsQuery)
+
+	for _, approximate := range []bool{false, true} {
+		resp, err := service.GetStats(context.Background(), &pb.GetStatsRequest{Approximate: approximate})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resp.Approximate != approximate {
+			t.Errorf("Expected approximate %v in the response, got %v", approximate, resp.Approximate)
+		}
+		if resp.Stats.TotalRequests != 1 {
+			t.Errorf("Expected 1 total request, got %d", resp.Stats.TotalRequests)
+		}
+	}
+}
+
+func TestQueryService_GetStats_Models(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("session", baseTime, "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.50), 1500),
+		mustCreateAPIRequest("session", baseTime, "claude-opus-4-20250514", entity.NewToken(200, 100, 0, 0).WithToolUse(40), entity.NewCost(2.50), 1500),
+		mustCreateAPIRequest("session", baseTime, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.25), 1500),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{})
+	service := NewService(nil, calculateStatsQuery)
+
+	resp, err := service.GetStats(context.Background(), &pb.GetStatsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Both are premium, only the breakdown tells them apart
+	models := resp.Stats.GetModels()
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+	if models[0].Model != "claude-opus-4-20250514" || models[0].Requests != 1 || models[0].Cost.Amount != 2.50 || models[0].Tokens.ToolUse != 40 {
+		t.Errorf("Expected opus first with 1 request, $2.50 and 40 tool use tokens, got %v", models[0])
+	}
+	if models[1].Model != "claude-sonnet-4-20250514" || models[1].Requests != 2 || models[1].Tokens.Total != 450 {
+		t.Errorf("Expected sonnet with 2 requests and 450 tokens, got %v", models[1])
+	}
+}
+
+func TestQueryService_GetStats_Fresh(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("session", baseTime, "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(1.00), 1500),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), service.NewInMemoryStatsCache(time.Minute))
+	service := NewService(nil, calculateStatsQuery)
+
+	// The first request calculates the stats, the second one is served from the cache
+	for i, wantCached := range []bool{false, true} {
+		resp, err := service.GetStats(context.Background(), &pb.GetStatsRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if (resp.CachedAt != nil) != wantCached {
+			t.Errorf("Request %d: expected cached %v, got cached_at %v", i, wantCached, resp.CachedAt)
+		}
+	}
+
+	resp, err := service.GetStats(context.Background(), &pb.GetStatsRequest{Fresh: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.CachedAt != nil {
+		t.Errorf("Expected fresh stats without cached_at, got %v", resp.CachedAt)
+	}
+	if resp.Stats.TotalRequests != 1 {
+		t.Errorf("Expected 1 total request, got %d", resp.Stats.TotalRequests)
+	}
+}
+
+func TestQueryService_GetStats_User(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("alice", baseTime, "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(1.00), 1500).WithUser("alice@example.com"),
+		mustCreateAPIRequest("bob", baseTime, "claude-3-sonnet-20240229", entity.NewToken(200, 100, 0, 0), entity.NewCost(2.00), 1500).WithUser("bob@example.com"),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{})
+	service := NewService(nil, calculateStatsQuery)
+
+	resp, err := service.GetStats(context.Background(), &pb.GetStatsRequest{User: "bob@example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Stats.TotalRequests != 1 || resp.Stats.TotalCost.Amount != 2.00 {
+		t.Errorf("Expected only the request of bob, got %d requests costing %f", resp.Stats.TotalRequests, resp.Stats.TotalCost.Amount)
+	}
+}
+
+func TestQueryService_InvalidPeriod(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{})
+	service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), calculateStatsQuery)
+
+	start := timestamppb.New(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC))
+	end := timestamppb.New(time.Date(2024, 6, 29, 0, 0, 0, 0, time.UTC))
+
+	_, err := service.GetStats(context.Background(), &pb.GetStatsRequest{StartTime: start, EndTime: end})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument from GetStats, got %v", err)
+	}
+
+	_, err = service.GetAPIRequests(context.Background(), &pb.GetAPIRequestsRequest{StartTime: start, EndTime: end})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument from GetAPIRequests, got %v", err)
+	}
+}
+
 func TestQueryService_GetAPIRequests(t *testing.T) {
 	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
 
@@ -327,6 +560,160 @@ func TestQueryService_GetAPIRequests(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "filtered_by_model_and_session",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"session1", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(100, 50, 10, 5),
+					entity.NewCost(0.50),
+					1000,
+				),
+				mustCreateAPIRequest(
+					"session1", baseTime.Add(time.Hour),
+					"claude-3-haiku-20240307",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(0.25),
+					800,
+				),
+				mustCreateAPIRequest(
+					"session2", baseTime.Add(2*time.Hour),
+					"claude-3-haiku-20240307",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(0.25),
+					800,
+				),
+			},
+			requestParams: &pb.GetAPIRequestsRequest{
+				Filter: &pb.RequestFilter{
+					Model:     "claude-3-haiku-20240307",
+					SessionId: "session1",
+				},
+			},
+			expectedCount: 1,
+			validateFirstReq: func(t *testing.T, req *pb.APIRequest) {
+				if req.SessionId != "session1" || req.Model != "claude-3-haiku-20240307" {
+					t.Errorf("Expected session1 haiku request, got %s %s", req.SessionId, req.Model)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "filtered_by_search",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"session1", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(100, 50, 10, 5),
+					entity.NewCost(0.50),
+					1000,
+				),
+				mustCreateAPIRequest(
+					"session2", baseTime.Add(time.Hour),
+					"claude-3-haiku-20240307",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(0.25),
+					800,
+				),
+			},
+			requestParams: &pb.GetAPIRequestsRequest{
+				Filter: &pb.RequestFilter{
+					Search: "Haiku",
+				},
+			},
+			expectedCount: 1,
+			validateFirstReq: func(t *testing.T, req *pb.APIRequest) {
+				if req.SessionId != "session2" {
+					t.Errorf("Expected the haiku request of session2, got %s %s", req.SessionId, req.Model)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "filtered_by_origin",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"live", baseTime,
+					"claude-3-haiku-20240307",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(0.25),
+					800,
+				),
+				mustCreateAPIRequest(
+					"imported", baseTime.Add(time.Hour),
+					"claude-3-haiku-20240307",
+					entity.NewToken(200, 100, 20, 10),
+					entity.NewCost(0.25),
+					800,
+				).WithOrigin(entity.OriginImport),
+			},
+			requestParams: &pb.GetAPIRequestsRequest{
+				Filter: &pb.RequestFilter{
+					Origin: entity.OriginImport,
+				},
+			},
+			expectedCount: 1,
+			validateFirstReq: func(t *testing.T, req *pb.APIRequest) {
+				if req.SessionId != "imported" || req.Origin != entity.OriginImport {
+					t.Errorf("Expected imported request, got %s with origin %q", req.SessionId, req.Origin)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "starred_request",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"session1", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(100, 50, 10, 5),
+					entity.NewCost(0.50),
+					1000,
+				).WithStar(entity.StarSession),
+			},
+			requestParams: &pb.GetAPIRequestsRequest{},
+			expectedCount: 1,
+			validateFirstReq: func(t *testing.T, req *pb.APIRequest) {
+				if req.Star != pb.StarScope_STAR_SCOPE_SESSION {
+					t.Errorf("Expected session star, got %v", req.Star)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "selected_fields",
+			requests: []entity.APIRequest{
+				mustCreateAPIRequest(
+					"session1", baseTime,
+					"claude-3-sonnet-20240229",
+					entity.NewToken(100, 50, 10, 5),
+					entity.NewCost(0.50),
+					1000,
+				),
+			},
+			requestParams: &pb.GetAPIRequestsRequest{
+				Fields: []string{"timestamp", "cost_usd"},
+			},
+			expectedCount: 1,
+			validateFirstReq: func(t *testing.T, req *pb.APIRequest) {
+				if !req.Timestamp.AsTime().Equal(baseTime) || req.CostUsd != 0.50 {
+					t.Errorf("Expected timestamp and cost to be returned, got %v and %f", req.Timestamp, req.CostUsd)
+				}
+				if req.SessionId != "" || req.Model != "" || req.TotalTokens != 0 || req.DurationMs != 0 {
+					t.Errorf("Expected other fields to be left out, got %v", req)
+				}
+			},
+			expectError: false,
+		},
+		{
+			name:     "unknown_field",
+			requests: []entity.APIRequest{},
+			requestParams: &pb.GetAPIRequestsRequest{
+				Fields: []string{"timestamp", "price"},
+			},
+			expectError: true,
+		},
 		{
 			name: "pagination_with_limit",
 			requests: func() []entity.APIRequest {
@@ -577,3 +964,283 @@ func TestQueryService_ConvertTimestampsToPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryService_GetServerMetrics(t *testing.T) {
+	tests := []struct {
+		name              string
+		ingestionLagQuery *usecase.GetIngestionLagQuery
+		expectedSamples   int64
+		expectedAverageMs int64
+		expectedMaxMs     int64
+	}{
+		{
+			name:              "server without lag tracking",
+			ingestionLagQuery: nil,
+		},
+		{
+			name:              "server with recorded lag",
+			ingestionLagQuery: usecase.NewGetIngestionLagQuery(testutil.NewMockIngestionLagRepository(entity.NewIngestionLag(4, 10*time.Second, 6*time.Second))),
+			expectedSamples:   4,
+			expectedAverageMs: 2500,
+			expectedMaxMs:     6000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewServiceWithIngestionLag(nil, nil, tt.ingestionLagQuery)
+
+			resp, err := service.GetServerMetrics(context.Background(), &pb.GetServerMetricsRequest{})
+			if err != nil {
+				t.Fatalf("GetServerMetrics failed: %v", err)
+			}
+
+			lag := resp.IngestionLag
+			if lag.Samples != tt.expectedSamples {
+				t.Errorf("Expected %d samples, got %d", tt.expectedSamples, lag.Samples)
+			}
+			if lag.AverageMs != tt.expectedAverageMs {
+				t.Errorf("Expected average %dms, got %dms", tt.expectedAverageMs, lag.AverageMs)
+			}
+			if lag.MaxMs != tt.expectedMaxMs {
+				t.Errorf("Expected max %dms, got %dms", tt.expectedMaxMs, lag.MaxMs)
+			}
+		})
+	}
+}
+
+func TestQueryService_GetServerMetrics_Error(t *testing.T) {
+	repo := testutil.NewMockIngestionLagRepository(entity.IngestionLag{})
+	repo.SetError(fmt.Errorf("metrics unavailable"))
+	service := NewServiceWithIngestionLag(nil, nil, usecase.NewGetIngestionLagQuery(repo))
+
+	if _, err := service.GetServerMetrics(context.Background(), &pb.GetServerMetricsRequest{}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestQueryService_GetServerMetrics_Retention(t *testing.T) {
+	nextCleanupAt := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		retentionQuery    *usecase.GetRetentionQuery
+		expectRetention   bool
+		expectedDuration  int64
+		expectedNextRunAt time.Time
+	}{
+		{
+			name:            "replica without retention",
+			retentionQuery:  nil,
+			expectRetention: false,
+		},
+		{
+			name:             "retention disabled",
+			retentionQuery:   usecase.NewGetRetentionQuery(testutil.NewMockRetentionRepository(entity.NewRetention(0, time.Time{}))),
+			expectRetention:  true,
+			expectedDuration: 0,
+		},
+		{
+			name:              "retention scheduled",
+			retentionQuery:    usecase.NewGetRetentionQuery(testutil.NewMockRetentionRepository(entity.NewRetention(30*24*time.Hour, nextCleanupAt))),
+			expectRetention:   true,
+			expectedDuration:  (30 * 24 * time.Hour).Milliseconds(),
+			expectedNextRunAt: nextCleanupAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewServiceWithIngestionLag(nil, nil, nil)
+			if tt.retentionQuery != nil {
+				service.SetRetentionQuery(tt.retentionQuery)
+			}
+
+			resp, err := service.GetServerMetrics(context.Background(), &pb.GetServerMetricsRequest{})
+			if err != nil {
+				t.Fatalf("GetServerMetrics failed: %v", err)
+			}
+
+			if !tt.expectRetention {
+				if resp.Retention != nil {
+					t.Errorf("Expected no retention, got %v", resp.Retention)
+				}
+				return
+			}
+			if resp.Retention == nil {
+				t.Fatal("Expected retention, got nil")
+			}
+			if resp.Retention.DurationMs != tt.expectedDuration {
+				t.Errorf("Expected duration %dms, got %dms", tt.expectedDuration, resp.Retention.DurationMs)
+			}
+			if tt.expectedNextRunAt.IsZero() {
+				if resp.Retention.NextCleanupAt != nil {
+					t.Errorf("Expected no next cleanup time, got %v", resp.Retention.NextCleanupAt.AsTime())
+				}
+				return
+			}
+			if got := resp.Retention.NextCleanupAt.AsTime(); !got.Equal(tt.expectedNextRunAt) {
+				t.Errorf("Expected next cleanup at %v, got %v", tt.expectedNextRunAt, got)
+			}
+		})
+	}
+}
+
+func TestQueryService_GetUserUsage(t *testing.T) {
+	dayStart := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("session-1", dayStart.Add(2*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(4), 1000).WithUser("alice@example.com"),
+		mustCreateAPIRequest("session-1", dayStart.Add(11*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(3), 1000).WithUser("alice@example.com"),
+	})
+	quotas := entity.NewUserQuotas(entity.NewCost(5), map[string]entity.Cost{"bob@example.com": entity.NewCost(10)})
+
+	service := NewServiceWithIngestionLag(nil, nil, nil)
+	service.SetUserUsageQuery(usecase.NewGetUserUsageQuery(repo, quotas))
+
+	resp, err := service.GetUserUsage(context.Background(), &pb.GetUserUsageRequest{
+		StartTime:      timestamppb.New(dayStart),
+		EndTime:        timestamppb.New(dayStart.Add(24*time.Hour - time.Nanosecond)),
+		BlockStartTime: timestamppb.New(dayStart.Add(10 * time.Hour)),
+		BlockEndTime:   timestamppb.New(dayStart.Add(15 * time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("GetUserUsage failed: %v", err)
+	}
+
+	expected := []struct {
+		user        string
+		dailyCost   float64
+		blockTokens int64
+		quota       float64
+		status      string
+	}{
+		{user: "alice@example.com", dailyCost: 7, blockTokens: 300, quota: 5, status: "exceeded"},
+		{user: "bob@example.com", dailyCost: 0, blockTokens: 0, quota: 10, status: "ok"},
+	}
+
+	if len(resp.Users) != len(expected) {
+		t.Fatalf("Expected %d users, got %d", len(expected), len(resp.Users))
+	}
+	for i, want := range expected {
+		got := resp.Users[i]
+		if got.User != want.user {
+			t.Errorf("users[%d]: expected user %q, got %q", i, want.user, got.User)
+		}
+		if got.Daily.TotalCost.Amount != want.dailyCost {
+			t.Errorf("users[%d]: expected daily cost %v, got %v", i, want.dailyCost, got.Daily.TotalCost.Amount)
+		}
+		if got.Block.TotalTokens.Limited != want.blockTokens {
+			t.Errorf("users[%d]: expected block tokens %d, got %d", i, want.blockTokens, got.Block.TotalTokens.Limited)
+		}
+		if got.DailyQuota.Amount != want.quota {
+			t.Errorf("users[%d]: expected quota %v, got %v", i, want.quota, got.DailyQuota.Amount)
+		}
+		if got.Status != want.status {
+			t.Errorf("users[%d]: expected status %q, got %q", i, want.status, got.Status)
+		}
+	}
+}
+
+func TestQueryService_GetUserUsage_Unimplemented(t *testing.T) {
+	service := NewServiceWithIngestionLag(nil, nil, nil)
+
+	if _, err := service.GetUserUsage(context.Background(), &pb.GetUserUsageRequest{}); err == nil {
+		t.Error("Expected error from a server without user usage, got nil")
+	}
+}
+
+func TestQueryService_GetIngestStats(t *testing.T) {
+	since := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+	stats := entity.NewIngestStats(
+		entity.NewIngestCounts(8, 2, 0, 1, 0, 0),
+		entity.NewIngestCounts(40, 5, 1, 3, 2, 1),
+		since,
+	)
+
+	service := NewServiceWithIngestionLag(nil, nil, nil)
+	service.SetIngestStatsQuery(usecase.NewGetIngestStatsQuery(testutil.NewMockIngestStatsRepository(stats)))
+
+	resp, err := service.GetIngestStats(context.Background(), &pb.GetIngestStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetIngestStats failed: %v", err)
+	}
+
+	if resp.LastHour.Accepted != 8 || resp.LastHour.Ignored != 2 || resp.LastHour.Malformed != 1 {
+		t.Errorf("Unexpected last hour counts: %v", resp.LastHour)
+	}
+	day := resp.LastDay
+	if day.Accepted != 40 || day.Ignored != 5 || day.Dropped != 1 || day.Malformed != 3 || day.Duplicated != 2 || day.Failed != 1 {
+		t.Errorf("Unexpected last day counts: %v", day)
+	}
+	if !resp.Since.AsTime().Equal(since) {
+		t.Errorf("Expected since %v, got %v", since, resp.Since.AsTime())
+	}
+}
+
+func TestQueryService_GetIngestStats_Error(t *testing.T) {
+	repo := testutil.NewMockIngestStatsRepository(entity.IngestStats{})
+	repo.SetError(fmt.Errorf("unavailable"))
+
+	service := NewServiceWithIngestionLag(nil, nil, nil)
+	service.SetIngestStatsQuery(usecase.NewGetIngestStatsQuery(repo))
+
+	if _, err := service.GetIngestStats(context.Background(), &pb.GetIngestStatsRequest{}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+func TestQueryService_GetIngestStats_Unimplemented(t *testing.T) {
+	service := NewServiceWithIngestionLag(nil, nil, nil)
+
+	if _, err := service.GetIngestStats(context.Background(), &pb.GetIngestStatsRequest{}); err == nil {
+		t.Error("Expected error from a server without ingest stats, got nil")
+	}
+}
+
+func TestQueryService_GetAPIRequests_PageToken(t *testing.T) {
+	baseTime := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	for i := 0; i < 5; i++ {
+		_ = mockRepo.Save(mustCreateAPIRequest(fmt.Sprintf("session%d", i), baseTime.Add(time.Duration(i)*time.Hour), "claude-3-sonnet-20240229", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.50), 1000))
+	}
+	service := NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), nil)
+
+	req := &pb.GetAPIRequestsRequest{Limit: 2}
+	var pages [][]string
+	for len(pages) < 5 {
+		resp, err := service.GetAPIRequests(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GetAPIRequests failed: %v", err)
+		}
+
+		var sessions []string
+		for _, apiReq := range resp.Requests {
+			sessions = append(sessions, apiReq.SessionId)
+		}
+		pages = append(pages, sessions)
+
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	expected := [][]string{{"session0", "session1"}, {"session2", "session3"}, {"session4"}}
+	if fmt.Sprint(pages) != fmt.Sprint(expected) {
+		t.Errorf("Expected pages %v, got %v", expected, pages)
+	}
+
+	invalid := []*pb.GetAPIRequestsRequest{
+		{Limit: 2, PageToken: "!not base64!"},
+		{Limit: 2, PageToken: "bm90LWEtY3Vyc29y"}, // "not-a-cursor"
+		{Limit: 2, Offset: 2, PageToken: req.PageToken},
+	}
+	for _, invalidReq := range invalid {
+		_, err := service.GetAPIRequests(context.Background(), invalidReq)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for %v, got %v", invalidReq, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package receiver
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsdata "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// claudeCodeAPIRequestEvent is the log body of Claude Code API request events
+const claudeCodeAPIRequestEvent = "claude_code.api_request"
+
+// ClaudeCodeParser parses Claude Code telemetry, it is registered on every receiver by default
+type ClaudeCodeParser struct{}
+
+// NewClaudeCodeParser creates a new Claude Code parser
+func NewClaudeCodeParser() *ClaudeCodeParser {
+	return &ClaudeCodeParser{}
+}
+
+// Source returns the Claude Code source identifier
+func (p *ClaudeCodeParser) Source() string {
+	return entity.SourceClaudeCode
+}
+
+// Parse extracts API request data from a Claude Code api_request log record
+func (p *ClaudeCodeParser) Parse(logRecord *logsdata.LogRecord) (entity.APIRequest, bool) {
+	if !isEventBody(logRecord, claudeCodeAPIRequestEvent) {
+		return entity.APIRequest{}, false
+	}
+
+	var sessionID, timestampStr, model, email, accountUUID string
+	var inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens, toolUseTokens int64
+	var costUSD float64
+	var durationMS int64
+
+	for _, attr := range logRecord.Attributes {
+		switch attr.Key {
+		case "session.id":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				sessionID = v.StringValue
+			}
+		case "event.timestamp":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				timestampStr = v.StringValue
+			}
+		case "model":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				model = v.StringValue
+			}
+		case "user.email":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				email = v.StringValue
+			}
+		case "user.account_uuid":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				accountUUID = v.StringValue
+			}
+		case "input_tokens":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &inputTokens); err != nil {
+					log.Printf("Warning: failed to parse input_tokens '%s': %v", v.StringValue, err)
+				}
+			}
+		case "output_tokens":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &outputTokens); err != nil {
+					log.Printf("Warning: failed to parse output_tokens '%s': %v", v.StringValue, err)
+				}
+			}
+		case "cache_read_tokens":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &cacheReadTokens); err != nil {
+					log.Printf("Warning: failed to parse cache_read_tokens '%s': %v", v.StringValue, err)
+				}
+			}
+		case "cache_creation_tokens":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &cacheCreationTokens); err != nil {
+					log.Printf("Warning: failed to parse cache_creation_tokens '%s': %v", v.StringValue, err)
+				}
+			}
+		case "tool_use_tokens":
+			// Optional, only reported by telemetry splitting tool call output from message text
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &toolUseTokens); err != nil {
+					log.Printf("Warning: failed to parse tool_use_tokens '%s': %v", v.StringValue, err)
+				}
+			}
+		case "cost_usd":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%f", &costUSD); err != nil {
+					log.Printf("Warning: failed to parse cost_usd '%s': %v", v.StringValue, err)
+				}
+			}
+		case "duration_ms":
+			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
+				if _, err := fmt.Sscanf(v.StringValue, "%d", &durationMS); err != nil {
+					log.Printf("Warning: failed to parse duration_ms '%s': %v", v.StringValue, err)
+				}
+			}
+		}
+	}
+
+	// Parse timestamp
+	timestamp, err := time.Parse(time.RFC3339, timestampStr)
+	if err != nil {
+		timestamp = time.Now().UTC()
+	}
+
+	tokens := entity.NewToken(inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens).WithToolUse(toolUseTokens)
+	cost := entity.NewCost(costUSD)
+	// Email identifies users across machines, the account UUID is reported without OAuth email too
+	user := email
+	if user == "" {
+		user = accountUUID
+	}
+
+	return entity.NewAPIRequest(sessionID, timestamp, model, tokens, cost, durationMS).WithUser(user), true
+}
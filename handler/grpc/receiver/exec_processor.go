@@ -0,0 +1,224 @@
+package receiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// processorPayload is the JSON line exchanged with a plugin for each API request
+type processorPayload struct {
+	SessionID           string    `json:"session_id"`
+	Timestamp           time.Time `json:"timestamp"`
+	Model               string    `json:"model"`
+	InputTokens         int64     `json:"input_tokens"`
+	OutputTokens        int64     `json:"output_tokens"`
+	CacheReadTokens     int64     `json:"cache_read_tokens"`
+	CacheCreationTokens int64     `json:"cache_creation_tokens"`
+	ToolUseTokens       int64     `json:"tool_use_tokens"`
+	CostUSD             float64   `json:"cost_usd"`
+	DurationMS          int64     `json:"duration_ms"`
+	Source              string    `json:"source"`
+	Origin              string    `json:"origin"`
+	User                string    `json:"user"`
+	Project             string    `json:"project"`
+	Drop                bool      `json:"drop,omitempty"` // only set in replies, drops the request
+}
+
+// newProcessorPayload maps an API request to its plugin payload
+func newProcessorPayload(apiReq entity.APIRequest) processorPayload {
+	tokens := apiReq.Tokens()
+	return processorPayload{
+		SessionID:           apiReq.SessionID(),
+		Timestamp:           apiReq.Timestamp(),
+		Model:               string(apiReq.Model()),
+		InputTokens:         tokens.Input(),
+		OutputTokens:        tokens.Output(),
+		CacheReadTokens:     tokens.CacheRead(),
+		CacheCreationTokens: tokens.CacheCreation(),
+		ToolUseTokens:       tokens.ToolUse(),
+		CostUSD:             apiReq.Cost().Amount(),
+		DurationMS:          apiReq.DurationMS(),
+		Source:              apiReq.Source(),
+		Origin:              apiReq.Origin(),
+		User:                apiReq.User(),
+		Project:             apiReq.Project(),
+	}
+}
+
+// apiRequest maps the payload back to an API request, keeping the star of the original request
+func (p processorPayload) apiRequest(original entity.APIRequest) entity.APIRequest {
+	tokens := entity.NewToken(p.InputTokens, p.OutputTokens, p.CacheReadTokens, p.CacheCreationTokens).WithToolUse(p.ToolUseTokens)
+	return entity.NewAPIRequest(p.SessionID, p.Timestamp, p.Model, tokens, entity.NewCost(p.CostUSD), p.DurationMS).
+		WithSource(p.Source).
+		WithOrigin(p.Origin).
+		WithUser(p.User).
+		WithProject(p.Project).
+		WithStar(original.Star())
+}
+
+// ExecProcessor runs a plugin as a subprocess exchanging one JSON object per line
+// Each request is written to the plugin's stdin, and the plugin replies with one line on stdout:
+// the fields to change (e.g. {"user":"alice"}), {} to keep the request or {"drop":true} to drop it.
+// The plugin is started on the first request and restarted after it fails or exits.
+type ExecProcessor struct {
+	name    string
+	command []string
+	timeout time.Duration
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	replies chan []byte // closed when the plugin closes its stdout
+}
+
+// NewExecProcessor creates a processor running the command, waiting up to timeout for each reply
+func NewExecProcessor(name string, command []string, timeout time.Duration) *ExecProcessor {
+	return &ExecProcessor{
+		name:    name,
+		command: command,
+		timeout: timeout,
+	}
+}
+
+// Name returns the plugin name
+func (p *ExecProcessor) Name() string {
+	return p.name
+}
+
+// Process sends the request to the plugin and applies its reply
+// Requests are sent one at a time, so a slow plugin delays ingestion up to its timeout
+func (p *ExecProcessor) Process(ctx context.Context, apiReq entity.APIRequest) (entity.APIRequest, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		if err := p.start(); err != nil {
+			return apiReq, true, err
+		}
+	}
+
+	payload := newProcessorPayload(apiReq)
+	line, err := json.Marshal(payload)
+	if err != nil {
+		return apiReq, true, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		p.stop()
+		return apiReq, true, fmt.Errorf("failed to write to plugin: %w", err)
+	}
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	var reply []byte
+	select {
+	case r, ok := <-p.replies:
+		if !ok {
+			p.stop()
+			return apiReq, true, errors.New("plugin exited")
+		}
+		reply = r
+	case <-timer.C:
+		// The reply may still arrive later, restart the plugin to keep replies in order
+		p.stop()
+		return apiReq, true, fmt.Errorf("no reply within %v", p.timeout)
+	case <-ctx.Done():
+		p.stop()
+		return apiReq, true, ctx.Err()
+	}
+
+	// Fields left out of the reply keep their values
+	if err := json.Unmarshal(reply, &payload); err != nil {
+		return apiReq, true, fmt.Errorf("invalid reply %q: %w", reply, err)
+	}
+	if payload.Drop {
+		return apiReq, false, nil
+	}
+	return payload.apiRequest(apiReq), true, nil
+}
+
+// Close closes the plugin's stdin and waits up to the timeout for it to exit before killing it
+func (p *ExecProcessor) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cmd == nil {
+		return nil
+	}
+
+	cmd := p.cmd
+	_ = p.stdin.Close()
+	go drainReplies(p.replies)
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(p.timeout):
+		_ = cmd.Process.Kill()
+		err = <-done
+	}
+	p.cmd = nil
+	return err
+}
+
+// start launches the plugin, its stderr goes to the server log
+func (p *ExecProcessor) start() error {
+	if len(p.command) == 0 {
+		return errors.New("no command configured")
+	}
+
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	replies := make(chan []byte)
+	go func() {
+		defer close(replies)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			replies <- append([]byte(nil), scanner.Bytes()...)
+		}
+	}()
+
+	p.cmd = cmd
+	p.stdin = stdin
+	p.replies = replies
+	return nil
+}
+
+// stop kills the plugin, the next request starts it again
+func (p *ExecProcessor) stop() {
+	_ = p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	go drainReplies(p.replies)
+	_ = p.cmd.Wait()
+	p.cmd = nil
+}
+
+// drainReplies discards late replies so the stdout reader can finish once the plugin is gone
+func drainReplies(replies chan []byte) {
+	for range replies {
+	}
+}
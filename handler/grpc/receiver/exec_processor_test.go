@@ -0,0 +1,182 @@
+package receiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// TestExecProcessorHelper is the plugin run by the exec processor tests, it does nothing as a regular test
+// It relabels requests of the "relabel" session, drops the "drop" session, stalls on "stall" and exits on "exit"
+func TestExecProcessorHelper(t *testing.T) {
+	if os.Getenv("CCMON_TEST_PLUGIN") != "1" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Println("not json")
+			continue
+		}
+		switch req["session_id"] {
+		case "relabel":
+			fmt.Printf(`{"user":"alice@example.com","model":"%s-relabeled"}`+"\n", req["model"])
+		case "drop":
+			fmt.Println(`{"drop":true}`)
+		case "invalid":
+			fmt.Println("not json")
+		case "stall":
+			time.Sleep(time.Minute)
+		case "exit":
+			os.Exit(0)
+		default:
+			fmt.Println(`{}`)
+		}
+	}
+	os.Exit(0)
+}
+
+// newTestExecProcessor runs this test binary as the plugin
+func newTestExecProcessor(t *testing.T, timeout time.Duration) *ExecProcessor {
+	t.Helper()
+	t.Setenv("CCMON_TEST_PLUGIN", "1")
+
+	processor := NewExecProcessor("test", []string{os.Args[0], "-test.run=^TestExecProcessorHelper$"}, timeout)
+	t.Cleanup(func() { _ = processor.Close() })
+	return processor
+}
+
+func TestExecProcessor_Process(t *testing.T) {
+	timestamp := time.Date(2025, 6, 15, 10, 30, 0, 0, time.UTC)
+	newRequest := func(sessionID string) entity.APIRequest {
+		tokens := entity.NewToken(100, 50, 10, 5).WithToolUse(20)
+		return entity.NewAPIRequest(sessionID, timestamp, "claude-sonnet-4-20250514", tokens, entity.NewCost(0.01), 1000).
+			WithSource(entity.SourceClaudeCode).
+			WithUser("bob@example.com")
+	}
+
+	tests := []struct {
+		name          string
+		sessionID     string
+		expectedKeep  bool
+		expectedUser  string
+		expectedModel string
+		expectedErr   string
+	}{
+		{
+			name:          "empty reply keeps the request",
+			sessionID:     "keep",
+			expectedKeep:  true,
+			expectedUser:  "bob@example.com",
+			expectedModel: "claude-sonnet-4-20250514",
+		},
+		{
+			name:          "reply changes the given fields",
+			sessionID:     "relabel",
+			expectedKeep:  true,
+			expectedUser:  "alice@example.com",
+			expectedModel: "claude-sonnet-4-20250514-relabeled",
+		},
+		{
+			name:         "reply drops the request",
+			sessionID:    "drop",
+			expectedKeep: false,
+		},
+		{
+			name:          "invalid reply keeps the request",
+			sessionID:     "invalid",
+			expectedKeep:  true,
+			expectedUser:  "bob@example.com",
+			expectedModel: "claude-sonnet-4-20250514",
+			expectedErr:   "invalid reply",
+		},
+		{
+			name:          "missing reply times out",
+			sessionID:     "stall",
+			expectedKeep:  true,
+			expectedUser:  "bob@example.com",
+			expectedModel: "claude-sonnet-4-20250514",
+			expectedErr:   "no reply within",
+		},
+		{
+			name:          "exited plugin keeps the request",
+			sessionID:     "exit",
+			expectedKeep:  true,
+			expectedUser:  "bob@example.com",
+			expectedModel: "claude-sonnet-4-20250514",
+			expectedErr:   "plugin exited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := newTestExecProcessor(t, 2*time.Second)
+			original := newRequest(tt.sessionID)
+
+			processed, keep, err := processor.Process(context.Background(), original)
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Process failed: %v", err)
+			}
+
+			if keep != tt.expectedKeep {
+				t.Fatalf("Expected keep %v, got %v", tt.expectedKeep, keep)
+			}
+			if !keep {
+				return
+			}
+			if processed.User() != tt.expectedUser {
+				t.Errorf("Expected user %q, got %q", tt.expectedUser, processed.User())
+			}
+			if string(processed.Model()) != tt.expectedModel {
+				t.Errorf("Expected model %q, got %q", tt.expectedModel, processed.Model())
+			}
+			// Fields left out of the reply keep their values
+			if !processed.Timestamp().Equal(timestamp) || processed.Tokens() != original.Tokens() || processed.Cost() != original.Cost() || processed.Source() != original.Source() {
+				t.Errorf("Expected unchanged fields to be kept, got %+v", processed)
+			}
+		})
+	}
+}
+
+func TestExecProcessor_RestartsAfterFailure(t *testing.T) {
+	processor := newTestExecProcessor(t, 2*time.Second)
+	request := entity.NewAPIRequest("exit", time.Now(), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+
+	if _, _, err := processor.Process(context.Background(), request); err == nil {
+		t.Fatal("Expected the exited plugin to fail the request")
+	}
+
+	processed, keep, err := processor.Process(context.Background(), entity.NewAPIRequest("relabel", time.Now(), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000))
+	if err != nil {
+		t.Fatalf("Expected the plugin to be restarted, got %v", err)
+	}
+	if !keep || processed.User() != "alice@example.com" {
+		t.Errorf("Expected the restarted plugin to relabel the request, got keep=%v user=%q", keep, processed.User())
+	}
+}
+
+func TestExecProcessor_MissingCommand(t *testing.T) {
+	processor := NewExecProcessor("missing", []string{"/nonexistent/ccmon-plugin"}, time.Second)
+	request := entity.NewAPIRequest("session-1", time.Now(), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+
+	processed, keep, err := processor.Process(context.Background(), request)
+	if err == nil {
+		t.Fatal("Expected an error for a missing plugin")
+	}
+	if !keep || processed.SessionID() != "session-1" {
+		t.Errorf("Expected the request to be kept unchanged, got keep=%v session=%q", keep, processed.SessionID())
+	}
+}
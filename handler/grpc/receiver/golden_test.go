@@ -0,0 +1,155 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+	"go.etcd.io/bbolt"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "rewrite the golden files of the OTLP fixtures")
+
+// goldenAPIRequest is the persisted API request as recorded in the golden files
+type goldenAPIRequest struct {
+	ID                  string  `json:"id"`
+	Source              string  `json:"source"`
+	User                string  `json:"user,omitempty"`
+	Project             string  `json:"project,omitempty"`
+	SessionID           string  `json:"session_id"`
+	Timestamp           string  `json:"timestamp"`
+	Model               string  `json:"model"`
+	InputTokens         int64   `json:"input_tokens"`
+	OutputTokens        int64   `json:"output_tokens"`
+	CacheReadTokens     int64   `json:"cache_read_tokens"`
+	CacheCreationTokens int64   `json:"cache_creation_tokens"`
+	CostUSD             float64 `json:"cost_usd"`
+	DurationMS          int64   `json:"duration_ms"`
+}
+
+// TestReceiver_GoldenFixtures replays captured Claude Code OTLP payloads through the receiver into BoltDB
+// Fixtures live in testdata/otlp, run with -update-golden after intended parser changes
+func TestReceiver_GoldenFixtures(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "otlp", "*.json*"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+
+	var payloads []string
+	for _, fixture := range fixtures {
+		if !strings.HasSuffix(fixture, ".golden.json") {
+			payloads = append(payloads, fixture)
+		}
+	}
+	if len(payloads) == 0 {
+		t.Fatal("Expected OTLP fixtures in testdata/otlp")
+	}
+
+	for _, payload := range payloads {
+		name := strings.TrimSuffix(filepath.Base(payload), filepath.Ext(payload))
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(payload)
+			if err != nil {
+				t.Fatalf("Failed to read fixture: %v", err)
+			}
+
+			reqs, err := DecodeExportLogsRequests(data)
+			if err != nil {
+				t.Fatalf("Failed to decode fixture: %v", err)
+			}
+
+			repo := createGoldenRepository(t)
+			receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(repo), entity.IgnoreRules{})
+			for _, req := range reqs {
+				if _, err := receiver.GetLogsServiceServer().Export(context.Background(), req); err != nil {
+					t.Fatalf("Failed to export fixture: %v", err)
+				}
+			}
+
+			stored, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("Failed to read persisted requests: %v", err)
+			}
+
+			got := encodeGolden(t, stored)
+			goldenPath := filepath.Join("testdata", "otlp", name+".golden.json")
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatalf("Failed to write golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update-golden to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Persisted requests differ from %s (run with -update-golden if intended)\ngot:\n%s\nwant:\n%s", goldenPath, got, want)
+			}
+		})
+	}
+}
+
+// createGoldenRepository creates a BoltDB repository in a temporary database
+func createGoldenRepository(t *testing.T) *repository.BoltDBAPIRequestRepository {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "golden.db"), 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	})
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte("requests"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	return repository.NewBoltDBAPIRequestRepository(db)
+}
+
+// encodeGolden encodes persisted requests in a stable order for comparison
+func encodeGolden(t *testing.T, requests []entity.APIRequest) []byte {
+	records := make([]goldenAPIRequest, 0, len(requests))
+	for _, req := range requests {
+		records = append(records, goldenAPIRequest{
+			ID:                  req.ID(),
+			Source:              req.Source(),
+			User:                req.User(),
+			Project:             req.Project(),
+			SessionID:           req.SessionID(),
+			Timestamp:           req.Timestamp().UTC().Format(time.RFC3339Nano),
+			Model:               req.Model().String(),
+			InputTokens:         req.Tokens().Input(),
+			OutputTokens:        req.Tokens().Output(),
+			CacheReadTokens:     req.Tokens().CacheRead(),
+			CacheCreationTokens: req.Tokens().CacheCreation(),
+			CostUSD:             req.Cost().Amount(),
+			DurationMS:          req.DurationMS(),
+		})
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to encode golden records: %v", err)
+	}
+	return append(data, '\n')
+}
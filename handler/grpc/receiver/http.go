@@ -0,0 +1,161 @@
+package receiver
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+
+	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	tracesv1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// OTLP/HTTP content types, binary protobuf is the default of most exporters
+const (
+	otlpContentTypeProtobuf = "application/x-protobuf"
+	otlpContentTypeJSON     = "application/json"
+)
+
+// maxHTTPExportSize limits the decompressed body of an OTLP/HTTP export
+const maxHTTPExportSize = 16 * 1024 * 1024
+
+// otlpRetryAfter is the Retry-After of rejected exports in seconds, exporters back off at least this long
+const otlpRetryAfter = "5"
+
+// HTTPHandler returns the OTLP/HTTP handler serving POST /v1/logs, /v1/metrics and /v1/traces
+// Exports are binary protobuf or JSON and may be gzip compressed, the response uses the content type of the request
+func (r *Receiver) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/logs", func(w http.ResponseWriter, req *http.Request) {
+		export := &logsv1.ExportLogsServiceRequest{}
+		contentType, ok := decodeHTTPExport(w, req, export)
+		if !ok {
+			return
+		}
+
+		// Shares the workers, the queue limit and the rate limit with gRPC exports
+		resp, err := r.GetLogsServiceServer().Export(httpExportContext(req), export)
+		if err != nil {
+			writeHTTPExportError(w, err)
+			return
+		}
+		writeHTTPExport(w, contentType, resp)
+	})
+	mux.HandleFunc("POST /v1/metrics", func(w http.ResponseWriter, req *http.Request) {
+		// Ignored like gRPC metric exports, the body is still validated so misconfigured exporters see an error
+		if contentType, ok := decodeHTTPExport(w, req, &metricsv1.ExportMetricsServiceRequest{}); ok {
+			writeHTTPExport(w, contentType, &metricsv1.ExportMetricsServiceResponse{})
+		}
+	})
+	mux.HandleFunc("POST /v1/traces", func(w http.ResponseWriter, req *http.Request) {
+		if contentType, ok := decodeHTTPExport(w, req, &tracesv1.ExportTraceServiceRequest{}); ok {
+			writeHTTPExport(w, contentType, &tracesv1.ExportTraceServiceResponse{})
+		}
+	})
+
+	return mux
+}
+
+// decodeHTTPExport reads the export request body into msg and returns its content type
+// Failures are answered on w, the handler only continues when ok is true
+func decodeHTTPExport(w http.ResponseWriter, req *http.Request, msg proto.Message) (string, bool) {
+	contentType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || (contentType != otlpContentTypeProtobuf && contentType != otlpContentTypeJSON) {
+		http.Error(w, fmt.Sprintf("unsupported content type, expected %s or %s", otlpContentTypeProtobuf, otlpContentTypeJSON), http.StatusUnsupportedMediaType)
+		return "", false
+	}
+
+	body := io.Reader(req.Body)
+	switch req.Header.Get("Content-Encoding") {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid gzip body: %v", err), http.StatusBadRequest)
+			return "", false
+		}
+		defer func() {
+			_ = gz.Close()
+		}()
+		body = gz
+	default:
+		http.Error(w, "unsupported content encoding, expected gzip", http.StatusUnsupportedMediaType)
+		return "", false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxHTTPExportSize+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read body: %v", err), http.StatusBadRequest)
+		return "", false
+	}
+	if len(data) > maxHTTPExportSize {
+		http.Error(w, "export too large", http.StatusRequestEntityTooLarge)
+		return "", false
+	}
+
+	if contentType == otlpContentTypeJSON {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, msg)
+	} else {
+		err = proto.Unmarshal(data, msg)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode export: %v", err), http.StatusBadRequest)
+		return "", false
+	}
+
+	return contentType, true
+}
+
+// writeHTTPExport writes the export response in the content type of the request
+func writeHTTPExport(w http.ResponseWriter, contentType string, resp proto.Message) {
+	var data []byte
+	var err error
+	if contentType == otlpContentTypeJSON {
+		data, err = protojson.Marshal(resp)
+	} else {
+		data, err = proto.Marshal(resp)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(data); err != nil {
+		log.Printf("Failed to write OTLP/HTTP response: %v", err)
+	}
+}
+
+// httpExportContext returns the context of the export with the client address, so it shares the rate limit of the client
+func httpExportContext(req *http.Request) context.Context {
+	addr, err := net.ResolveTCPAddr("tcp", req.RemoteAddr)
+	if err != nil {
+		return req.Context()
+	}
+	return peer.NewContext(req.Context(), &peer.Peer{Addr: addr})
+}
+
+// writeHTTPExportError answers a rejected export, a full receiver queue or the rate limit asks the exporter to retry later
+func writeHTTPExportError(w http.ResponseWriter, err error) {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		w.Header().Set("Retry-After", otlpRetryAfter)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case codes.ResourceExhausted:
+		w.Header().Set("Retry-After", retryAfter(err))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,156 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReceiver_HTTPHandler(t *testing.T) {
+	timestamp := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	export := createClaudeCodeLogRequest("http-session", timestamp, "claude-sonnet-4-20250514", 1000, 500, 0, 0, 0.25, 1000)
+
+	protobufBody, err := proto.Marshal(export)
+	if err != nil {
+		t.Fatalf("Failed to marshal export: %v", err)
+	}
+	jsonBody, err := protojson.Marshal(export)
+	if err != nil {
+		t.Fatalf("Failed to marshal export: %v", err)
+	}
+	var gzipBody bytes.Buffer
+	gz := gzip.NewWriter(&gzipBody)
+	_, _ = gz.Write(protobufBody)
+	_ = gz.Close()
+
+	tests := []struct {
+		name          string
+		path          string
+		contentType   string
+		encoding      string
+		body          []byte
+		expectedCode  int
+		expectedSaved int
+	}{
+		{name: "protobuf logs", path: "/v1/logs", contentType: "application/x-protobuf", body: protobufBody, expectedCode: http.StatusOK, expectedSaved: 1},
+		{name: "json logs", path: "/v1/logs", contentType: "application/json; charset=utf-8", body: jsonBody, expectedCode: http.StatusOK, expectedSaved: 1},
+		{name: "gzip logs", path: "/v1/logs", contentType: "application/x-protobuf", encoding: "gzip", body: gzipBody.Bytes(), expectedCode: http.StatusOK, expectedSaved: 1},
+		{name: "metrics ignored", path: "/v1/metrics", contentType: "application/x-protobuf", body: []byte{}, expectedCode: http.StatusOK},
+		{name: "traces ignored", path: "/v1/traces", contentType: "application/json", body: []byte("{}"), expectedCode: http.StatusOK},
+		{name: "unsupported content type", path: "/v1/logs", contentType: "text/plain", body: protobufBody, expectedCode: http.StatusUnsupportedMediaType},
+		{name: "unsupported encoding", path: "/v1/logs", contentType: "application/x-protobuf", encoding: "br", body: protobufBody, expectedCode: http.StatusUnsupportedMediaType},
+		{name: "malformed body", path: "/v1/logs", contentType: "application/json", body: []byte("{not json"), expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			handler := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo)).HTTPHandler()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.expectedSaved {
+				t.Errorf("Expected %d saved requests, got %d", tt.expectedSaved, len(requests))
+			}
+		})
+	}
+}
+
+func TestReceiver_HTTPHandlerResponse(t *testing.T) {
+	handler := NewReceiver(nil, nil, nil).HTTPHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected the content type of the request, got %q", got)
+	}
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &logsv1.ExportLogsServiceResponse{}); err != nil {
+		t.Errorf("Expected an export response, got %q: %v", rec.Body.String(), err)
+	}
+
+	// Only exports are accepted
+	req = httptest.NewRequest(http.MethodGet, "/v1/logs", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d for GET, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestReceiver_HTTPHandlerDuringShutdown(t *testing.T) {
+	repo := &blockingBatchRepository{started: make(chan struct{}, 1), release: make(chan struct{})}
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(repo), entity.IgnoreRules{})
+	receiver.StartWorkers(1, 1)
+	handler := receiver.HTTPHandler()
+
+	timestamp := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	exportHTTP := func(sessionID string) int {
+		body, err := proto.Marshal(createClaudeCodeLogRequest(sessionID, timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500))
+		if err != nil {
+			t.Errorf("Failed to marshal export: %v", err)
+			return 0
+		}
+		req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// The first export occupies the worker, the second one fills the queue
+	if code := exportHTTP("session-1"); code != http.StatusOK {
+		t.Fatalf("Expected the first export to be queued, got %d", code)
+	}
+	<-repo.started
+	if code := exportHTTP("session-2"); code != http.StatusOK {
+		t.Fatalf("Expected the second export to be queued, got %d", code)
+	}
+
+	// The third export waits for a free slot while the server shuts down
+	waiting := make(chan int)
+	go func() {
+		waiting <- exportHTTP("session-3")
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		receiver.StopWorkers()
+		close(stopped)
+	}()
+
+	if code := <-waiting; code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the waiting export to be rejected as unavailable, got %d", code)
+	}
+	close(repo.release)
+	<-stopped
+
+	// Exports arriving after the shutdown are rejected instead of sent to the closed queue
+	if code := exportHTTP("session-4"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected an export after the shutdown to be rejected as unavailable, got %d", code)
+	}
+}
@@ -0,0 +1,66 @@
+package receiver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ingestStatsBuckets is the number of one minute buckets kept, the longest reported window is a day
+const ingestStatsBuckets = 24 * 60
+
+// ingestStatsBucket holds the outcomes of the events received within one minute
+type ingestStatsBucket struct {
+	minute int64 // minutes since the unix epoch, identifies which minute a reused bucket belongs to
+	counts entity.IngestCounts
+}
+
+// ingestStats counts the outcomes of received events in a ring of one minute buckets
+type ingestStats struct {
+	mu      sync.Mutex
+	since   time.Time
+	buckets [ingestStatsBuckets]ingestStatsBucket
+}
+
+// newIngestStats creates an empty counter starting at since
+func newIngestStats(since time.Time) *ingestStats {
+	return &ingestStats{since: since}
+}
+
+// record adds the outcomes of an export received at the given time
+func (s *ingestStats) record(at time.Time, counts entity.IngestCounts) {
+	if counts.Total() == 0 {
+		return
+	}
+
+	minute := at.Unix() / 60
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket := &s.buckets[minute%ingestStatsBuckets]
+	if bucket.minute != minute {
+		*bucket = ingestStatsBucket{minute: minute}
+	}
+	bucket.counts = bucket.counts.Add(counts)
+}
+
+// stats returns the outcomes of the last hour and day before now
+func (s *ingestStats) stats(now time.Time) entity.IngestStats {
+	minute := now.Unix() / 60
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lastHour, lastDay entity.IngestCounts
+	for _, bucket := range s.buckets {
+		age := minute - bucket.minute
+		if age < 0 || age >= ingestStatsBuckets {
+			continue
+		}
+		lastDay = lastDay.Add(bucket.counts)
+		if age < 60 {
+			lastHour = lastHour.Add(bucket.counts)
+		}
+	}
+	return entity.NewIngestStats(lastHour, lastDay, s.since)
+}
@@ -0,0 +1,44 @@
+package receiver
+
+import (
+	"github.com/elct9620/ccmon/entity"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsdata "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+// Parser maps OTLP log records from a specific AI CLI into API requests
+// Implement it to monitor other tools (e.g. Gemini CLI, Codex CLI) and register it with Receiver.RegisterParser
+type Parser interface {
+	// Source returns the tool identifier stored on parsed requests (e.g. "gemini_cli")
+	Source() string
+
+	// Parse extracts the API request from the log record
+	// Returns false when the record is not an API request event of this tool
+	Parse(logRecord *logsdata.LogRecord) (entity.APIRequest, bool)
+}
+
+// isEventBody returns true if the log record body is the given event name
+func isEventBody(logRecord *logsdata.LogRecord, event string) bool {
+	if logRecord.Body == nil {
+		return false
+	}
+
+	body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue)
+	return ok && body.StringValue == event
+}
+
+// eventIDAttribute is the OpenTelemetry attribute giving a log record a unique ID, a retried export repeats it
+const eventIDAttribute = "log.record.uid"
+
+// recordEventID returns the unique ID of the log record, empty when the exporter sets none
+func recordEventID(logRecord *logsdata.LogRecord) string {
+	for _, attr := range logRecord.Attributes {
+		if attr.Key != eventIDAttribute {
+			continue
+		}
+		if v, ok := attr.GetValue().GetValue().(*commonv1.AnyValue_StringValue); ok {
+			return v.StringValue
+		}
+	}
+	return ""
+}
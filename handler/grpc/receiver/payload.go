@@ -0,0 +1,57 @@
+package receiver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+
+	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxPayloadLineSize limits a single JSON line, exports of large sessions can exceed the bufio default
+const maxPayloadLineSize = 16 * 1024 * 1024
+
+// DecodeExportLogsRequests decodes captured OTLP log export payloads
+// Accepts a single OTLP/JSON request, JSON lines as written by the collector file exporter or a binary protobuf request
+func DecodeExportLogsRequests(data []byte) ([]*logsv1.ExportLogsServiceRequest, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("empty payload")
+	}
+
+	if trimmed[0] != '{' {
+		req := &logsv1.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(data, req); err != nil {
+			return nil, fmt.Errorf("failed to decode protobuf payload: %w", err)
+		}
+		return []*logsv1.ExportLogsServiceRequest{req}, nil
+	}
+
+	req := &logsv1.ExportLogsServiceRequest{}
+	if err := protojson.Unmarshal(trimmed, req); err == nil {
+		return []*logsv1.ExportLogsServiceRequest{req}, nil
+	}
+
+	var reqs []*logsv1.ExportLogsServiceRequest
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPayloadLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		req := &logsv1.ExportLogsServiceRequest{}
+		if err := protojson.Unmarshal(text, req); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON payload at line %d: %w", line, err)
+		}
+		reqs = append(reqs, req)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSON lines payload: %w", err)
+	}
+
+	return reqs, nil
+}
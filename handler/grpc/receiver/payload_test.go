@@ -0,0 +1,78 @@
+package receiver
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodeExportLogsRequests(t *testing.T) {
+	req := createClaudeCodeLogRequest("session-1", "2025-06-21T08:15:03.481Z", "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 1000)
+
+	binary, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	jsonPayload, err := protojson.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		expectError   bool
+		expectedCount int
+	}{
+		{
+			name:          "single JSON request",
+			data:          jsonPayload,
+			expectedCount: 1,
+		},
+		{
+			name:          "JSON lines",
+			data:          append(append(append(append([]byte{}, jsonPayload...), '\n', '\n'), jsonPayload...), '\n'),
+			expectedCount: 2,
+		},
+		{
+			name:          "binary protobuf",
+			data:          binary,
+			expectedCount: 1,
+		},
+		{
+			name:        "empty payload",
+			data:        []byte("  \n"),
+			expectError: true,
+		},
+		{
+			name:        "invalid JSON line",
+			data:        append(append(append([]byte{}, jsonPayload...), '\n'), []byte(`{"resourceLogs": [`)...),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqs, err := DecodeExportLogsRequests(tt.data)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(reqs) != tt.expectedCount {
+				t.Fatalf("Expected %d requests, got %d", tt.expectedCount, len(reqs))
+			}
+			for _, decoded := range reqs {
+				if !proto.Equal(decoded, req) {
+					t.Errorf("Decoded request differs from the original")
+				}
+			}
+		})
+	}
+}
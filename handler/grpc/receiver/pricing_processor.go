@@ -0,0 +1,35 @@
+package receiver
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// PricingProcessor fills the cost of API requests reported without one, e.g. when Claude Code reports a zero cost
+// Reported costs are kept, models missing from the price table stay at zero
+type PricingProcessor struct {
+	table entity.PriceTable
+}
+
+// NewPricingProcessor creates a new PricingProcessor pricing requests from the table
+func NewPricingProcessor(table entity.PriceTable) *PricingProcessor {
+	return &PricingProcessor{table: table}
+}
+
+// Name implements Processor
+func (p *PricingProcessor) Name() string {
+	return "pricing"
+}
+
+// Process implements Processor
+func (p *PricingProcessor) Process(ctx context.Context, apiReq entity.APIRequest) (entity.APIRequest, bool, error) {
+	if apiReq.Cost().Amount() > 0 {
+		return apiReq, true, nil
+	}
+
+	if cost, ok := p.table.CostOf(apiReq); ok {
+		return apiReq.WithCost(cost), true, nil
+	}
+	return apiReq, true, nil
+}
@@ -0,0 +1,41 @@
+package receiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestPricingProcessor_Process(t *testing.T) {
+	processor := NewPricingProcessor(entity.NewPriceTable(map[string]entity.ModelPrice{
+		"claude-sonnet-4": entity.NewModelPrice(3, 15, 0.3, 3.75),
+	}))
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		model    string
+		cost     float64
+		expected float64
+	}{
+		{name: "zero cost is priced", model: "claude-sonnet-4-20250514", cost: 0, expected: 4.5},
+		{name: "reported cost is kept", model: "claude-sonnet-4-20250514", cost: 1.25, expected: 1.25},
+		{name: "unknown model stays at zero", model: "gpt-5", cost: 0, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := entity.NewAPIRequest("session", now, tt.model, entity.NewToken(1_000_000, 100_000, 0, 0), entity.NewCost(tt.cost), 1000)
+
+			processed, keep, err := processor.Process(context.Background(), req)
+			if err != nil || !keep {
+				t.Fatalf("Process() = %v, %v, want the request kept", keep, err)
+			}
+			if processed.Cost().Amount() != tt.expected {
+				t.Errorf("Expected cost %.2f, got %.2f", tt.expected, processed.Cost().Amount())
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package receiver
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// Processor transforms API requests after parsing and before they are stored
+// Implement it to enrich, relabel or forward requests and register it with Receiver.RegisterProcessor
+type Processor interface {
+	// Name identifies the processor in the server log
+	Name() string
+
+	// Process returns the request to store, false drops it
+	// A failed processor keeps the request unchanged, so a broken integration never loses usage data
+	Process(ctx context.Context, apiReq entity.APIRequest) (entity.APIRequest, bool, error)
+}
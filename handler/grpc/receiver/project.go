@@ -0,0 +1,51 @@
+package receiver
+
+import (
+	"strings"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+// Resource attributes identifying the project, set with OTEL_RESOURCE_ATTRIBUTES or OTEL_SERVICE_NAME
+const (
+	workingDirectoryAttribute = "process.working_directory"
+	serviceNameAttribute      = "service.name"
+)
+
+// defaultServiceNames are reported when OTEL_SERVICE_NAME is not set, they name the tool rather than a project
+var defaultServiceNames = map[string]struct{}{
+	"claude-code": {},
+}
+
+// resourceProject returns the project of the resource, empty when telemetry does not identify it
+// The last element of the working directory wins over the service name, which only counts when it is changed from the default
+func resourceProject(resource *resourcev1.Resource) string {
+	var workingDirectory, serviceName string
+	for _, attr := range resource.GetAttributes() {
+		v, ok := attr.GetValue().GetValue().(*commonv1.AnyValue_StringValue)
+		if !ok {
+			continue
+		}
+		switch attr.Key {
+		case workingDirectoryAttribute:
+			workingDirectory = v.StringValue
+		case serviceNameAttribute:
+			serviceName = v.StringValue
+		}
+	}
+
+	if project := lastPathElement(workingDirectory); project != "" {
+		return project
+	}
+	if _, ok := defaultServiceNames[serviceName]; ok || strings.HasPrefix(serviceName, "unknown_service") {
+		return ""
+	}
+	return serviceName
+}
+
+// lastPathElement returns the last element of a Unix or Windows path, telemetry may come from another OS than the server
+func lastPathElement(path string) string {
+	path = strings.TrimRight(path, `/\`)
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
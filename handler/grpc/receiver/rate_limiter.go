@@ -0,0 +1,150 @@
+package receiver
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// rateLimiterIdleTimeout is how long a client keeps its bucket without exports, idle buckets are full again anyway
+const rateLimiterIdleTimeout = time.Minute
+
+// tokenBucket holds the exports a client may still send, refilled at the limit rate up to the burst
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter limits the exports of each client, identified by the host of its address
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // exports per second
+	burst     float64 // exports a client may send at once after being idle
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a limiter allowing rate exports per second to each client with bursts of up to burst exports
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		clients: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes an export from the bucket of the client, returns the time until the next export is allowed otherwise
+func (l *rateLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.clients[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = bucket
+	}
+
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := (1 - bucket.tokens) / l.rate
+	return false, time.Duration(wait * float64(time.Second))
+}
+
+// sweep forgets the clients idle for longer than rateLimiterIdleTimeout, at most once per timeout
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterIdleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for client, bucket := range l.clients {
+		if now.Sub(bucket.last) >= rateLimiterIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// SetRateLimit limits the log exports of each client to requestsPerSecond with bursts of up to burst exports
+// Clients are identified by the host of their address, so exporters reconnecting from another port share a limit.
+// Exports over the limit are rejected with ResourceExhausted and the delay to retry after, which OTLP exporters
+// honor before retrying. A rate of 0 disables the limit, and it must be set before the receiver starts serving
+func (r *Receiver) SetRateLimit(requestsPerSecond float64, burst int) {
+	if requestsPerSecond <= 0 {
+		r.limiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	r.limiter = newRateLimiter(requestsPerSecond, burst)
+}
+
+// RateLimitedCount returns the total number of log exports rejected by the rate limit
+func (r *Receiver) RateLimitedCount() int64 {
+	return r.rateLimitedCount.Load()
+}
+
+// limitExport returns a ResourceExhausted error when the client of the export is over the rate limit
+func (r *Receiver) limitExport(ctx context.Context, now time.Time) error {
+	if r.limiter == nil {
+		return nil
+	}
+
+	client := exportClient(ctx)
+	ok, wait := r.limiter.allow(client, now)
+	if ok {
+		return nil
+	}
+
+	// Only every 100th rejection is logged, so a flooding exporter does not flood the log as well
+	if total := r.rateLimitedCount.Add(1); total%100 == 1 {
+		log.Printf("Rejected log export: client %s is over the rate limit (total rejected: %d)", client, total)
+	}
+
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded, retry after %v", wait.Round(time.Millisecond))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// exportClient returns the host of the client address, empty when the address is unknown
+func exportClient(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// retryAfter returns the Retry-After header value of a rejected export, rounded up to whole seconds
+func retryAfter(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return strconv.Itoa(int(math.Ceil(math.Max(info.GetRetryDelay().AsDuration().Seconds(), 1))))
+		}
+	}
+	return otlpRetryAfter
+}
@@ -0,0 +1,150 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(2, 3)
+
+	// The burst is available right away
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.allow("10.0.0.1", now); !ok {
+			t.Fatalf("Expected export %d within the burst to be allowed", i+1)
+		}
+	}
+	ok, wait := limiter.allow("10.0.0.1", now)
+	if ok {
+		t.Fatal("Expected the export after the burst to be rejected")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("Expected to retry after 500ms, got %v", wait)
+	}
+
+	// Other clients have their own bucket
+	if ok, _ := limiter.allow("10.0.0.2", now); !ok {
+		t.Error("Expected another client to be allowed")
+	}
+
+	// The bucket refills at the rate
+	if ok, _ := limiter.allow("10.0.0.1", now.Add(500*time.Millisecond)); !ok {
+		t.Error("Expected an export to be allowed after the refill")
+	}
+	if ok, _ := limiter.allow("10.0.0.1", now.Add(500*time.Millisecond)); ok {
+		t.Error("Expected only one export to be refilled")
+	}
+
+	// Idle clients are forgotten
+	limiter.allow("10.0.0.3", now.Add(2*rateLimiterIdleTimeout))
+	if len(limiter.clients) != 1 {
+		t.Errorf("Expected idle clients to be forgotten, got %d clients", len(limiter.clients))
+	}
+}
+
+func TestOTLPReceiver_RateLimit(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(mockRepo), entity.IgnoreRules{})
+	receiver.SetRateLimit(0.1, 1)
+
+	timestamp := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	export := func(addr string, sessionID string) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 50000}})
+		request := createClaudeCodeLogRequest(sessionID, timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+		_, err := receiver.GetLogsServiceServer().Export(ctx, request)
+		return err
+	}
+
+	if err := export("10.0.0.1", "session-1"); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	err := export("10.0.0.1", "session-2")
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted over the rate limit, got %v", err)
+	}
+	// OTLP exporters only retry ResourceExhausted with a retry delay
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	if retryInfo == nil || retryInfo.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("Expected a retry delay, got %v", retryInfo)
+	}
+
+	if err := export("10.0.0.2", "session-3"); err != nil {
+		t.Errorf("Expected another client to be allowed, got %v", err)
+	}
+
+	if receiver.RateLimitedCount() != 1 {
+		t.Errorf("Expected 1 rate limited export, got %d", receiver.RateLimitedCount())
+	}
+	requests, _ := mockRepo.FindAll()
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 stored requests, got %d", len(requests))
+	}
+}
+
+func TestOTLPReceiver_RateLimitDisabled(t *testing.T) {
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(testutil.NewMockAPIRequestRepository()), entity.IgnoreRules{})
+	receiver.SetRateLimit(0, 1)
+
+	timestamp := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	for i := 0; i < 10; i++ {
+		request := createClaudeCodeLogRequest("session", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+		if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed without a rate limit: %v", err)
+		}
+	}
+}
+
+func TestReceiver_HTTPHandlerRateLimit(t *testing.T) {
+	receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(testutil.NewMockAPIRequestRepository()))
+	receiver.SetRateLimit(0.5, 1)
+	handler := receiver.HTTPHandler()
+
+	timestamp := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	body, err := proto.Marshal(createClaudeCodeLogRequest("http-session", timestamp, "claude-sonnet-4-20250514", 1000, 500, 0, 0, 0.25, 1000))
+	if err != nil {
+		t.Fatalf("Failed to marshal export: %v", err)
+	}
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/logs", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("10.0.0.1:40000"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	// Another port of the same host shares the limit
+	rec := send("10.0.0.1:40001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status %d over the rate limit, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After of 2 seconds, got %q", got)
+	}
+}
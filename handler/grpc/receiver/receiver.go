@@ -2,8 +2,13 @@ package receiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -18,18 +23,212 @@ import (
 
 // Receiver handles OTLP message processing
 type Receiver struct {
-	requestChan   chan entity.APIRequest
-	program       *tea.Program
-	appendCommand *usecase.AppendApiRequestCommand
+	requestChan     chan entity.APIRequest
+	program         *tea.Program
+	appendCommand   *usecase.AppendApiRequestCommand
+	appendBatch     *usecase.AppendApiRequestBatchCommand
+	ignoreRules     entity.IgnoreRules
+	ignoredCount    atomic.Int64
+	parsers         []Parser
+	processors      []Processor
+	droppedCount    atomic.Int64
+	duplicatedCount atomic.Int64
+	origin          string
+
+	lagMu        sync.Mutex
+	ingestionLag entity.IngestionLag
+
+	clockSkew         entity.ClockSkewPolicy
+	skewMu            sync.Mutex
+	skewWarnedSession map[string]struct{}
+	skewedCount       atomic.Int64
+
+	lastEventAt atomic.Int64 // unix nanoseconds of the last received log event
+
+	ingest *ingestStats // outcomes of the received API request events over the last day
+
+	pool *workerPool // nil processes exports in the gRPC handler goroutine
+
+	limiter          *rateLimiter // nil accepts every export
+	rateLimitedCount atomic.Int64
 }
 
 // NewReceiver creates a new OTLP receiver
 func NewReceiver(requestChan chan entity.APIRequest, program *tea.Program, appendCommand *usecase.AppendApiRequestCommand) *Receiver {
+	return NewReceiverWithIgnoreRules(requestChan, program, appendCommand, entity.IgnoreRules{})
+}
+
+// NewReceiverWithIgnoreRules creates a new OTLP receiver that drops API requests matching the ignore rules
+func NewReceiverWithIgnoreRules(requestChan chan entity.APIRequest, program *tea.Program, appendCommand *usecase.AppendApiRequestCommand, ignoreRules entity.IgnoreRules) *Receiver {
 	return &Receiver{
 		requestChan:   requestChan,
 		program:       program,
 		appendCommand: appendCommand,
+		ignoreRules:   ignoreRules,
+		parsers:       []Parser{NewClaudeCodeParser()},
+		ingest:        newIngestStats(time.Now()),
+
+		skewWarnedSession: make(map[string]struct{}),
+	}
+}
+
+// NewReceiverWithBatch creates a new OTLP receiver that persists all API requests of an export in one batch
+func NewReceiverWithBatch(requestChan chan entity.APIRequest, program *tea.Program, appendBatch *usecase.AppendApiRequestBatchCommand, ignoreRules entity.IgnoreRules) *Receiver {
+	r := NewReceiverWithIgnoreRules(requestChan, program, nil, ignoreRules)
+	r.appendBatch = appendBatch
+	return r
+}
+
+// RegisterParser adds a parser for telemetry from another tool
+// Parsers are tried in registration order after the built-in Claude Code parser,
+// and must be registered before the receiver starts serving
+func (r *Receiver) RegisterParser(parser Parser) {
+	r.parsers = append(r.parsers, parser)
+}
+
+// RegisterProcessor adds a processor applied to every accepted API request before it is stored
+// Processors run in registration order, and must be registered before the receiver starts serving
+func (r *Receiver) RegisterProcessor(processor Processor) {
+	r.processors = append(r.processors, processor)
+}
+
+// CloseProcessors closes the registered processors holding resources, e.g. running plugins
+func (r *Receiver) CloseProcessors() {
+	for _, processor := range r.processors {
+		closer, ok := processor.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			log.Printf("Error closing processor %s: %v", processor.Name(), err)
+		}
+	}
+}
+
+// DroppedCount returns the total number of API requests dropped by processors
+func (r *Receiver) DroppedCount() int64 {
+	return r.droppedCount.Load()
+}
+
+// DuplicatedCount returns the total number of API requests dropped as duplicates, repeated within an export or re-sent within the dedupe window
+func (r *Receiver) DuplicatedCount() int64 {
+	return r.duplicatedCount.Load()
+}
+
+// applyProcessors runs the request through the processors, returns false if a processor drops it
+func (r *Receiver) applyProcessors(apiReq entity.APIRequest) (entity.APIRequest, bool) {
+	for _, processor := range r.processors {
+		processed, keep, err := processor.Process(context.Background(), apiReq)
+		if err != nil {
+			log.Printf("Processor %s failed, keeping the request unchanged: %v", processor.Name(), err)
+			continue
+		}
+		if !keep {
+			return entity.APIRequest{}, false
+		}
+		apiReq = processed
+	}
+	return apiReq, true
+}
+
+// SetOrigin marks every received API request with the origin, e.g. entity.OriginImport for backfills
+// Requests are live until an origin is set, which must be set before the receiver starts serving
+func (r *Receiver) SetOrigin(origin string) {
+	r.origin = origin
+}
+
+// SetClockSkewPolicy sets how requests timestamped ahead of the server clock are handled
+// Detection is disabled until a policy is set, and must be set before the receiver starts serving
+func (r *Receiver) SetClockSkewPolicy(policy entity.ClockSkewPolicy) {
+	r.clockSkew = policy
+}
+
+// SkewedCount returns the total number of API requests clamped or dropped for clock skew
+func (r *Receiver) SkewedCount() int64 {
+	return r.skewedCount.Load()
+}
+
+// handleClockSkew records a skewed request, returns false if the request is dropped
+func (r *Receiver) handleClockSkew(apiReq entity.APIRequest, receivedAt time.Time) bool {
+	r.skewedCount.Add(1)
+	r.warnClockSkew(apiReq, receivedAt)
+
+	return r.clockSkew.Action() != entity.ClockSkewDrop
+}
+
+// warnClockSkew logs a clock skew warning once per session to avoid flooding the log
+func (r *Receiver) warnClockSkew(apiReq entity.APIRequest, receivedAt time.Time) {
+	r.skewMu.Lock()
+	defer r.skewMu.Unlock()
+
+	if _, warned := r.skewWarnedSession[apiReq.SessionID()]; warned {
+		return
+	}
+	r.skewWarnedSession[apiReq.SessionID()] = struct{}{}
+
+	log.Printf("Clock skew detected: session=%s timestamp is %v ahead of server time (tolerance %v), %s requests from this session",
+		apiReq.SessionID(), r.clockSkew.Skew(apiReq, receivedAt).Round(time.Second), r.clockSkew.Tolerance(), clockSkewVerb(r.clockSkew.Action()))
+}
+
+// clockSkewVerb describes the clock skew action for log messages
+func clockSkewVerb(action entity.ClockSkewAction) string {
+	if action == entity.ClockSkewDrop {
+		return "dropping"
+	}
+	return "clamping"
+}
+
+// parseRecord returns the API request from the first parser handling the log record, made in the project of its resource
+// The unique ID of the record is kept, so the record is recognized when the exporter sends it again
+func (r *Receiver) parseRecord(logRecord *logsdata.LogRecord, project string) (entity.APIRequest, bool) {
+	for _, parser := range r.parsers {
+		if apiReq, ok := parser.Parse(logRecord); ok {
+			if eventID := recordEventID(logRecord); eventID != "" {
+				apiReq = apiReq.WithEventID(eventID)
+			}
+			return apiReq.WithSource(parser.Source()).WithOrigin(r.origin).WithProject(project), true
+		}
+	}
+	return entity.APIRequest{}, false
+}
+
+// LastEventAt returns when the last log event was received, zero if none arrived yet
+// Every log event counts, not only the ones stored as API requests
+func (r *Receiver) LastEventAt() time.Time {
+	nanos := r.lastEventAt.Load()
+	if nanos == 0 {
+		return time.Time{}
 	}
+	return time.Unix(0, nanos)
+}
+
+// IgnoredCount returns the total number of API requests dropped by ignore rules
+func (r *Receiver) IgnoredCount() int64 {
+	return r.ignoredCount.Load()
+}
+
+// GetIngestionLag returns the lag between event timestamps and receive time of accepted API requests
+func (r *Receiver) GetIngestionLag() (entity.IngestionLag, error) {
+	r.lagMu.Lock()
+	defer r.lagMu.Unlock()
+	return r.ingestionLag, nil
+}
+
+// GetIngestStats returns how many received API request events were stored or not stored over the last hour and day
+func (r *Receiver) GetIngestStats() (entity.IngestStats, error) {
+	return r.ingest.stats(time.Now()), nil
+}
+
+// RecordFailedWrites counts API requests which were accepted but dropped as they could not be written later, e.g. by write-behind
+func (r *Receiver) RecordFailedWrites(count int) {
+	r.ingest.record(time.Now(), entity.IngestCounts{}.Record(entity.IngestFailed, int64(count)))
+}
+
+// recordIngestionLag adds a lag sample for a received API request
+func (r *Receiver) recordIngestionLag(lag time.Duration) {
+	r.lagMu.Lock()
+	defer r.lagMu.Unlock()
+	r.ingestionLag = r.ingestionLag.Record(lag)
 }
 
 // GetTraceServiceServer returns the trace service implementation
@@ -73,125 +272,223 @@ type logsReceiver struct {
 	receiver *Receiver
 }
 
+// Export reports the records which are not stored as a partial success, exporters do not retry them
+// With workers only the records rejected before queueing are known when the export returns
 func (r *logsReceiver) Export(ctx context.Context, req *logsv1.ExportLogsServiceRequest) (*logsv1.ExportLogsServiceResponse, error) {
+	receivedAt := time.Now()
+
+	if err := r.receiver.limitExport(ctx, receivedAt); err != nil {
+		return nil, err
+	}
+
+	job := r.receiver.parse(req, receivedAt)
+	if r.receiver.pool != nil {
+		if err := r.receiver.pool.enqueue(ctx, job); err != nil {
+			return nil, err
+		}
+		return newExportLogsResponse(job.counts), nil
+	}
+
+	return newExportLogsResponse(r.receiver.store(job)), nil
+}
+
+// newExportLogsResponse reports the malformed, dropped and failed records as rejected, nil partial success when all are handled
+// Ignored and duplicated records are left out, they are not stored on purpose
+func newExportLogsResponse(counts entity.IngestCounts) *logsv1.ExportLogsServiceResponse {
+	malformed := counts.Count(entity.IngestMalformed)
+	dropped := counts.Count(entity.IngestDropped)
+	failed := counts.Count(entity.IngestFailed)
+	if malformed+dropped+failed == 0 {
+		return &logsv1.ExportLogsServiceResponse{}
+	}
+
+	var reasons []string
+	if malformed > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d malformed", malformed))
+	}
+	if dropped > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d dropped by clock skew or processors", dropped))
+	}
+	if failed > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d failed to store", failed))
+	}
+
+	return &logsv1.ExportLogsServiceResponse{
+		PartialSuccess: &logsv1.ExportLogsPartialSuccess{
+			RejectedLogRecords: malformed + dropped + failed,
+			ErrorMessage:       "API requests not stored: " + strings.Join(reasons, ", "),
+		},
+	}
+}
+
+// parse reads the API requests of a log export, malformed, ignored and clock skewed requests are counted and left out
+func (r *Receiver) parse(req *logsv1.ExportLogsServiceRequest, receivedAt time.Time) logsJob {
+	var ignored, malformed int64
+	var clamped int
+	var counts entity.IngestCounts
+	var requests []entity.APIRequest
 	for _, rl := range req.ResourceLogs {
+		project := resourceProject(rl.Resource)
 		for _, sl := range rl.ScopeLogs {
 			for _, logRecord := range sl.LogRecords {
 				// Skip if body is nil
 				if logRecord.Body == nil {
 					continue
 				}
+				r.lastEventAt.Store(receivedAt.UnixNano())
 
-				// Check if this is an API request log
-				if body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue); ok && body.StringValue == "claude_code.api_request" {
-					apiReq := r.parseAPIRequest(logRecord)
-					if apiReq != nil {
-						log.Printf("Received API request: session=%s, model=%s, tokens=%d, cost=$%.4f",
-							apiReq.SessionID(), apiReq.Model(), apiReq.Tokens().Total(), apiReq.Cost().Amount())
-
-						// Save via usecase command
-						if r.receiver.appendCommand != nil {
-							params := usecase.AppendApiRequestParams{
-								SessionID:  apiReq.SessionID(),
-								Timestamp:  apiReq.Timestamp(),
-								Model:      string(apiReq.Model()),
-								Tokens:     apiReq.Tokens(),
-								Cost:       apiReq.Cost(),
-								DurationMS: apiReq.DurationMS(),
-							}
-							if err := r.receiver.appendCommand.Execute(context.Background(), params); err != nil {
-								log.Printf("Failed to save request via usecase: %v", err)
-							}
-						}
-
-						// Send to channel (non-blocking) - only used in old architecture
-						if r.receiver.requestChan != nil {
-							select {
-							case r.receiver.requestChan <- *apiReq:
-							default:
-								// Channel is full, drop the request
-							}
-						}
-					}
-				} else if body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue); ok && body.StringValue != "" {
+				apiReq, ok := r.parseRecord(logRecord, project)
+				if !ok {
 					// Log unsupported event types for analysis
-					log.Printf("Unsupported log event: %s", body.StringValue)
+					if body, ok := logRecord.Body.Value.(*commonv1.AnyValue_StringValue); ok && body.StringValue != "" {
+						log.Printf("Unsupported log event: %s", body.StringValue)
+					}
+					continue
+				}
+
+				// Malformed events are rejected before any rule sees them, they would be stored under a broken ID
+				if err := validateAPIRequest(apiReq); err != nil {
+					malformed++
+					counts = counts.Record(entity.IngestMalformed, 1)
+					log.Printf("Rejected malformed API request: source=%s, session=%q: %v", apiReq.Source(), apiReq.SessionID(), err)
+					continue
+				}
+
+				if r.ignoreRules.Matches(apiReq) {
+					ignored++
+					counts = counts.Record(entity.IngestIgnored, 1)
+					continue
+				}
+
+				if r.clockSkew.IsSkewed(apiReq, receivedAt) {
+					if !r.handleClockSkew(apiReq, receivedAt) {
+						counts = counts.Record(entity.IngestDropped, 1)
+						continue
+					}
+					// A retried export is clamped to another time, the reported time keeps identifying the record
+					if apiReq.EventID() == "" {
+						apiReq = apiReq.WithEventID(apiReq.DedupeKey())
+					}
+					// Offset each clamped request by a nanosecond so requests of a session keep distinct IDs
+					apiReq = apiReq.WithTimestamp(receivedAt.Add(time.Duration(clamped)))
+					clamped++
 				}
+
+				r.recordIngestionLag(receivedAt.Sub(apiReq.Timestamp()))
+				requests = append(requests, apiReq)
 			}
 		}
 	}
 
-	return &logsv1.ExportLogsServiceResponse{}, nil
+	if ignored > 0 {
+		total := r.ignoredCount.Add(ignored)
+		log.Printf("Ignored %d API requests matching ignore rules (total: %d)", ignored, total)
+	}
+
+	if malformed > 0 {
+		log.Printf("Rejected %d malformed API requests, check the exporter of the logged sessions", malformed)
+	}
+
+	return logsJob{requests: requests, counts: counts, receivedAt: receivedAt}
 }
 
-// parseAPIRequest extracts API request data from a log record
-func (r *logsReceiver) parseAPIRequest(logRecord *logsdata.LogRecord) *entity.APIRequest {
-	var sessionID, timestampStr, model string
-	var inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens int64
-	var costUSD float64
-	var durationMS int64
+// store applies the processors to the parsed API requests and persists them, returns the outcomes of the whole export
+func (r *Receiver) store(job logsJob) entity.IngestCounts {
+	var dropped, duplicated int64
+	counts := job.counts
+	var batch []usecase.AppendApiRequestParams
+	for _, apiReq := range job.requests {
+		lag := job.receivedAt.Sub(apiReq.Timestamp())
 
-	for _, attr := range logRecord.Attributes {
-		switch attr.Key {
-		case "session.id":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				sessionID = v.StringValue
-			}
-		case "event.timestamp":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				timestampStr = v.StringValue
-			}
-		case "model":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				model = v.StringValue
-			}
-		case "input_tokens":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%d", &inputTokens); err != nil {
-					log.Printf("Warning: failed to parse input_tokens '%s': %v", v.StringValue, err)
-				}
-			}
-		case "output_tokens":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%d", &outputTokens); err != nil {
-					log.Printf("Warning: failed to parse output_tokens '%s': %v", v.StringValue, err)
-				}
-			}
-		case "cache_read_tokens":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%d", &cacheReadTokens); err != nil {
-					log.Printf("Warning: failed to parse cache_read_tokens '%s': %v", v.StringValue, err)
-				}
-			}
-		case "cache_creation_tokens":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%d", &cacheCreationTokens); err != nil {
-					log.Printf("Warning: failed to parse cache_creation_tokens '%s': %v", v.StringValue, err)
-				}
+		var ok bool
+		if len(r.processors) > 0 {
+			if apiReq, ok = r.applyProcessors(apiReq); !ok {
+				dropped++
+				counts = counts.Record(entity.IngestDropped, 1)
+				continue
 			}
-		case "cost_usd":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%f", &costUSD); err != nil {
-					log.Printf("Warning: failed to parse cost_usd '%s': %v", v.StringValue, err)
-				}
+		}
+
+		log.Printf("Received API request: source=%s, session=%s, model=%s, tokens=%d, cost=$%.4f, lag=%v",
+			apiReq.Source(), apiReq.SessionID(), apiReq.Model(), apiReq.Tokens().Total(), apiReq.Cost().Amount(), lag.Round(time.Millisecond))
+
+		params := usecase.AppendApiRequestParams{
+			SessionID:  apiReq.SessionID(),
+			Timestamp:  apiReq.Timestamp(),
+			Model:      string(apiReq.Model()),
+			Tokens:     apiReq.Tokens(),
+			Cost:       apiReq.Cost(),
+			DurationMS: apiReq.DurationMS(),
+			Source:     apiReq.Source(),
+			Origin:     apiReq.Origin(),
+			User:       apiReq.User(),
+			Project:    apiReq.Project(),
+			EventID:    apiReq.EventID(),
+		}
+
+		// Save via usecase command, or collect for a single batch write
+		if r.appendBatch != nil {
+			batch = append(batch, params)
+		} else if r.appendCommand != nil {
+			if err := r.appendCommand.Execute(context.Background(), params); errors.Is(err, usecase.ErrDuplicateAPIRequest) {
+				duplicated++
+				counts = counts.Record(entity.IngestDuplicated, 1)
+			} else if err != nil {
+				log.Printf("Failed to save request via usecase: %v", err)
+				counts = counts.Record(entity.IngestFailed, 1)
+			} else {
+				counts = counts.Record(entity.IngestAccepted, 1)
 			}
-		case "duration_ms":
-			if v, ok := attr.Value.Value.(*commonv1.AnyValue_StringValue); ok {
-				if _, err := fmt.Sscanf(v.StringValue, "%d", &durationMS); err != nil {
-					log.Printf("Warning: failed to parse duration_ms '%s': %v", v.StringValue, err)
-				}
+		} else {
+			counts = counts.Record(entity.IngestAccepted, 1)
+		}
+
+		// Send to channel (non-blocking) - only used in old architecture
+		if r.requestChan != nil {
+			select {
+			case r.requestChan <- apiReq:
+			default:
+				// Channel is full, drop the request
 			}
 		}
 	}
 
-	// Parse timestamp
-	timestamp, err := time.Parse(time.RFC3339, timestampStr)
-	if err != nil {
-		timestamp = time.Now().UTC()
+	if len(batch) > 0 {
+		result, err := r.appendBatch.Execute(context.Background(), batch)
+		if err != nil {
+			log.Printf("Failed to save %d requests via usecase: %v", len(batch), err)
+			counts = counts.Record(entity.IngestFailed, int64(len(batch)))
+		} else {
+			counts = counts.Record(entity.IngestAccepted, int64(result.Saved)).Record(entity.IngestDuplicated, int64(result.Duplicates))
+			duplicated += int64(result.Duplicates)
+		}
+	}
+	if duplicated > 0 {
+		total := r.duplicatedCount.Add(duplicated)
+		log.Printf("Dropped %d duplicated API requests in export batch (total: %d)", duplicated, total)
+	}
+	r.ingest.record(job.receivedAt, counts)
+
+	if dropped > 0 {
+		total := r.droppedCount.Add(dropped)
+		log.Printf("Dropped %d API requests by processors (total: %d)", dropped, total)
+	}
+
+	return counts
+}
+
+// validateAPIRequest returns why a parsed API request cannot be stored, nil when it is valid
+func validateAPIRequest(apiReq entity.APIRequest) error {
+	if apiReq.SessionID() == "" {
+		return errors.New("missing session.id")
 	}
 
-	tokens := entity.NewToken(inputTokens, outputTokens, cacheReadTokens, cacheCreationTokens)
-	cost := entity.NewCost(costUSD)
-	req := entity.NewAPIRequest(sessionID, timestamp, model, tokens, cost, durationMS)
-	return &req
+	tokens := apiReq.Tokens()
+	if tokens.Input() < 0 || tokens.Output() < 0 || tokens.CacheRead() < 0 || tokens.CacheCreation() < 0 {
+		return errors.New("negative token count")
+	}
+	if apiReq.Cost().Amount() < 0 {
+		return errors.New("negative cost")
+	}
+	return nil
 }
@@ -0,0 +1,81 @@
+package receiver
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+)
+
+// benchmarkBatchSize is the number of log records in a benchmark export request
+const benchmarkBatchSize = 100
+
+// discardBatchRepository drops saved batches so repeated exports don't grow the benchmark heap
+type discardBatchRepository struct{}
+
+func (discardBatchRepository) SaveBatch(reqs []entity.APIRequest) error {
+	return nil
+}
+
+// createBenchmarkExportRequest merges benchmarkBatchSize api_request log records into a single export request
+func createBenchmarkExportRequest() *logsv1.ExportLogsServiceRequest {
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	request := createClaudeCodeLogRequest("bench-session", timestamp, "claude-sonnet-4-20250514", 1000, 500, 200, 100, 0.01, 1000)
+	scopeLogs := request.ResourceLogs[0].ScopeLogs[0]
+	for len(scopeLogs.LogRecords) < benchmarkBatchSize {
+		single := createClaudeCodeLogRequest("bench-session", timestamp, "claude-sonnet-4-20250514", 1000, 500, 200, 100, 0.01, 1000)
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, single.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	}
+	return request
+}
+
+// newBenchmarkReceiver creates a receiver writing to a discarding batch repository, with logging silenced
+func newBenchmarkReceiver(tb testing.TB) *Receiver {
+	originalOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(originalOutput) })
+
+	return NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(discardBatchRepository{}), entity.IgnoreRules{})
+}
+
+func BenchmarkClaudeCodeParser_Parse(b *testing.B) {
+	parser := NewClaudeCodeParser()
+	logRecord := createBenchmarkExportRequest().ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, ok := parser.Parse(logRecord); !ok {
+			b.Fatal("Expected the log record to be parsed")
+		}
+	}
+}
+
+func BenchmarkLogsReceiver_Export(b *testing.B) {
+	logsServer := newBenchmarkReceiver(b).GetLogsServiceServer()
+	request := createBenchmarkExportRequest()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := logsServer.Export(context.Background(), request); err != nil {
+			b.Fatalf("Export failed: %v", err)
+		}
+	}
+}
+
+func TestLogsReceiver_Export_AllocationBudget(t *testing.T) {
+	logsServer := newBenchmarkReceiver(t).GetLogsServiceServer()
+	request := createBenchmarkExportRequest()
+
+	// Parsing attributes allocates per record, the budget allows about 40 allocations per record
+	testutil.CheckAllocationBudget(t, 40*benchmarkBatchSize, func() {
+		if _, err := logsServer.Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package receiver
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
 	"github.com/elct9620/ccmon/usecase"
 	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
@@ -20,6 +22,8 @@ import (
 	metricsdata "go.opentelemetry.io/proto/otlp/metrics/v1"
 	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
 	tracesdata "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Helper function to create OTLP log request with Claude Code API request data
@@ -640,3 +644,997 @@ func TestOTLPReceiver_UnsupportedEventLogging(t *testing.T) {
 		})
 	}
 }
+
+func TestOTLPReceiver_IgnoreRules(t *testing.T) {
+	validTimestamp := time.Now().Format(time.RFC3339)
+
+	tests := []struct {
+		name               string
+		modelPatterns      []string
+		sessionPrefixes    []string
+		sessionID          string
+		model              string
+		expectedSavedCount int
+		expectedLog        string
+	}{
+		{
+			name:               "model matching pattern is ignored",
+			modelPatterns:      []string{"haiku"},
+			sessionID:          "session-1",
+			model:              "claude-3-5-haiku-20241022",
+			expectedSavedCount: 0,
+			expectedLog:        "Ignored 1 API requests matching ignore rules (total: 1)",
+		},
+		{
+			name:               "session matching prefix is ignored",
+			sessionPrefixes:    []string{"test-"},
+			sessionID:          "test-session",
+			model:              "claude-sonnet-4-20250514",
+			expectedSavedCount: 0,
+			expectedLog:        "Ignored 1 API requests matching ignore rules (total: 1)",
+		},
+		{
+			name:               "non-matching request is saved",
+			modelPatterns:      []string{"haiku"},
+			sessionPrefixes:    []string{"test-"},
+			sessionID:          "session-1",
+			model:              "claude-sonnet-4-20250514",
+			expectedSavedCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			rules, err := entity.NewIgnoreRules(tt.modelPatterns, tt.sessionPrefixes)
+			if err != nil {
+				t.Fatalf("NewIgnoreRules failed: %v", err)
+			}
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			appendCommand := usecase.NewAppendApiRequestCommand(mockRepo)
+			receiver := NewReceiverWithIgnoreRules(nil, nil, appendCommand, rules)
+
+			request := createClaudeCodeLogRequest(tt.sessionID, validTimestamp, tt.model, 100, 50, 0, 0, 0.01, 500)
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.expectedSavedCount {
+				t.Errorf("Expected %d requests in repository, got %d", tt.expectedSavedCount, len(requests))
+			}
+
+			logOutput := buf.String()
+			if tt.expectedLog == "" {
+				if strings.Contains(logOutput, "Ignored") {
+					t.Errorf("Expected no ignored log, but got: %s", logOutput)
+				}
+			} else if !strings.Contains(logOutput, tt.expectedLog) {
+				t.Errorf("Expected log '%s' not found in captured logs: %s", tt.expectedLog, logOutput)
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_IgnoredCountAccumulates(t *testing.T) {
+	rules, err := entity.NewIgnoreRules([]string{"haiku"}, nil)
+	if err != nil {
+		t.Fatalf("NewIgnoreRules failed: %v", err)
+	}
+
+	receiver := NewReceiverWithIgnoreRules(nil, nil, nil, rules)
+	request := createClaudeCodeLogRequest("session-1", time.Now().Format(time.RFC3339), "claude-3-5-haiku-20241022", 100, 50, 0, 0, 0.01, 500)
+
+	for i := 0; i < 3; i++ {
+		if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+
+	if receiver.IgnoredCount() != 3 {
+		t.Errorf("Expected ignored count 3, got %d", receiver.IgnoredCount())
+	}
+}
+
+func TestOTLPReceiver_IngestionLag(t *testing.T) {
+	rules, err := entity.NewIgnoreRules([]string{"haiku"}, nil)
+	if err != nil {
+		t.Fatalf("NewIgnoreRules failed: %v", err)
+	}
+
+	receiver := NewReceiverWithIgnoreRules(nil, nil, nil, rules)
+
+	lag, err := receiver.GetIngestionLag()
+	if err != nil {
+		t.Fatalf("GetIngestionLag failed: %v", err)
+	}
+	if !lag.IsEmpty() {
+		t.Errorf("Expected empty lag before any export, got %d samples", lag.Samples())
+	}
+
+	now := time.Now()
+	requests := []*logsv1.ExportLogsServiceRequest{
+		createClaudeCodeLogRequest("session-1", now.Add(-2*time.Minute).Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+		createClaudeCodeLogRequest("session-1", now.Add(-10*time.Minute).Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+		// Ignored requests are not part of the lag metric
+		createClaudeCodeLogRequest("session-1", now.Add(-time.Hour).Format(time.RFC3339), "claude-3-5-haiku-20241022", 100, 50, 0, 0, 0.01, 500),
+	}
+
+	for _, request := range requests {
+		if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+
+	lag, err = receiver.GetIngestionLag()
+	if err != nil {
+		t.Fatalf("GetIngestionLag failed: %v", err)
+	}
+
+	if lag.Samples() != 2 {
+		t.Errorf("Expected 2 lag samples, got %d", lag.Samples())
+	}
+
+	// Timestamps are truncated to seconds by RFC3339, allow a small margin
+	if lag.Max() < 10*time.Minute || lag.Max() > 10*time.Minute+5*time.Second {
+		t.Errorf("Expected max lag around 10m, got %v", lag.Max())
+	}
+	if lag.Average() < 6*time.Minute || lag.Average() > 6*time.Minute+5*time.Second {
+		t.Errorf("Expected average lag around 6m, got %v", lag.Average())
+	}
+}
+
+func TestOTLPReceiver_LastEventAt(t *testing.T) {
+	rules, err := entity.NewIgnoreRules([]string{"haiku"}, nil)
+	if err != nil {
+		t.Fatalf("NewIgnoreRules failed: %v", err)
+	}
+
+	receiver := NewReceiverWithIgnoreRules(nil, nil, nil, rules)
+	if !receiver.LastEventAt().IsZero() {
+		t.Errorf("Expected zero last event time before any export, got %v", receiver.LastEventAt())
+	}
+
+	// Ignored requests still prove the exporter is working
+	before := time.Now()
+	request := createClaudeCodeLogRequest("session-1", before.Format(time.RFC3339), "claude-3-5-haiku-20241022", 100, 50, 0, 0, 0.01, 500)
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	if receiver.LastEventAt().Before(before) || receiver.LastEventAt().After(time.Now()) {
+		t.Errorf("Expected last event time between %v and now, got %v", before, receiver.LastEventAt())
+	}
+}
+
+func TestOTLPReceiver_Origin(t *testing.T) {
+	tests := []struct {
+		name           string
+		origin         string
+		expectedOrigin string
+	}{
+		{name: "live by default", origin: "", expectedOrigin: entity.OriginLive},
+		{name: "imported", origin: entity.OriginImport, expectedOrigin: entity.OriginImport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+			receiver.SetOrigin(tt.origin)
+
+			request := createClaudeCodeLogRequest("session-1", time.Now().Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != 1 {
+				t.Fatalf("Expected 1 request in repository, got %d", len(requests))
+			}
+			if requests[0].Origin() != tt.expectedOrigin {
+				t.Errorf("Expected origin %q, got %q", tt.expectedOrigin, requests[0].Origin())
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_Project(t *testing.T) {
+	tests := []struct {
+		name            string
+		attributes      map[string]string
+		expectedProject string
+	}{
+		{name: "attributes absent", expectedProject: ""},
+		{name: "default service name", attributes: map[string]string{"service.name": "claude-code"}, expectedProject: ""},
+		{name: "unknown service name", attributes: map[string]string{"service.name": "unknown_service:node"}, expectedProject: ""},
+		{name: "service name", attributes: map[string]string{"service.name": "ccmon"}, expectedProject: "ccmon"},
+		{name: "working directory", attributes: map[string]string{"process.working_directory": "/home/alice/src/ccmon/"}, expectedProject: "ccmon"},
+		{name: "windows working directory", attributes: map[string]string{"process.working_directory": `C:\src\ccmon`}, expectedProject: "ccmon"},
+		{
+			name:            "working directory preferred over service name",
+			attributes:      map[string]string{"service.name": "backend", "process.working_directory": "/src/ccmon"},
+			expectedProject: "ccmon",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiver(nil, nil, usecase.NewAppendApiRequestCommand(mockRepo))
+
+			request := createClaudeCodeLogRequest("session-1", time.Now().Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			for key, value := range tt.attributes {
+				request.ResourceLogs[0].Resource.Attributes = append(request.ResourceLogs[0].Resource.Attributes, &commonv1.KeyValue{
+					Key:   key,
+					Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+				})
+			}
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != 1 {
+				t.Fatalf("Expected 1 request in repository, got %d", len(requests))
+			}
+			if requests[0].Project() != tt.expectedProject {
+				t.Errorf("Expected project %q, got %q", tt.expectedProject, requests[0].Project())
+			}
+		})
+	}
+}
+
+func TestClaudeCodeParser_ToolUseTokens(t *testing.T) {
+	tests := []struct {
+		name            string
+		toolUseTokens   string // empty when the attribute is absent
+		expectedToolUse int64
+		expectedText    int64
+	}{
+		{
+			name:            "attribute absent",
+			expectedToolUse: 0,
+			expectedText:    50,
+		},
+		{
+			name:            "attribute present",
+			toolUseTokens:   "30",
+			expectedToolUse: 30,
+			expectedText:    20,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := createClaudeCodeLogRequest("session-1", time.Now().Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			logRecord := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+			if tt.toolUseTokens != "" {
+				logRecord.Attributes = append(logRecord.Attributes, &commonv1.KeyValue{
+					Key:   "tool_use_tokens",
+					Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: tt.toolUseTokens}},
+				})
+			}
+
+			apiReq, ok := NewClaudeCodeParser().Parse(logRecord)
+			if !ok {
+				t.Fatal("Expected the log record to be parsed")
+			}
+
+			if apiReq.Tokens().ToolUse() != tt.expectedToolUse {
+				t.Errorf("ToolUse() = %d, want %d", apiReq.Tokens().ToolUse(), tt.expectedToolUse)
+			}
+			if apiReq.Tokens().TextOutput() != tt.expectedText {
+				t.Errorf("TextOutput() = %d, want %d", apiReq.Tokens().TextOutput(), tt.expectedText)
+			}
+		})
+	}
+}
+
+func TestClaudeCodeParser_User(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string // empty when the attribute is absent
+		accountUUID  string // empty when the attribute is absent
+		expectedUser string
+	}{
+		{
+			name:         "attributes absent",
+			expectedUser: "",
+		},
+		{
+			name:         "email preferred over account",
+			email:        "alice@example.com",
+			accountUUID:  "0f8c3a52-7a5e-4d0c-9c4e-2b1d6f3e8a90",
+			expectedUser: "alice@example.com",
+		},
+		{
+			name:         "account without email",
+			accountUUID:  "0f8c3a52-7a5e-4d0c-9c4e-2b1d6f3e8a90",
+			expectedUser: "0f8c3a52-7a5e-4d0c-9c4e-2b1d6f3e8a90",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := createClaudeCodeLogRequest("session-1", time.Now().Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			logRecord := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+			for key, value := range map[string]string{"user.email": tt.email, "user.account_uuid": tt.accountUUID} {
+				if value != "" {
+					logRecord.Attributes = append(logRecord.Attributes, &commonv1.KeyValue{
+						Key:   key,
+						Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: value}},
+					})
+				}
+			}
+
+			apiReq, ok := NewClaudeCodeParser().Parse(logRecord)
+			if !ok {
+				t.Fatal("Expected the log record to be parsed")
+			}
+
+			if apiReq.User() != tt.expectedUser {
+				t.Errorf("User() = %q, want %q", apiReq.User(), tt.expectedUser)
+			}
+		})
+	}
+}
+
+// stubParser maps a fixed log body into an API request for parser registration tests
+type stubParser struct {
+	source string
+	event  string
+}
+
+func (p *stubParser) Source() string {
+	return p.source
+}
+
+func (p *stubParser) Parse(logRecord *logsdata.LogRecord) (entity.APIRequest, bool) {
+	if !isEventBody(logRecord, p.event) {
+		return entity.APIRequest{}, false
+	}
+
+	tokens := entity.NewToken(10, 20, 0, 0)
+	return entity.NewAPIRequest("stub-session", time.Now(), "gemini-2.5-pro", tokens, entity.NewCost(0.01), 500), true
+}
+
+func TestOTLPReceiver_RegisterParser(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        *logsv1.ExportLogsServiceRequest
+		expectedSource string
+	}{
+		{
+			name:           "claude code events use built-in parser",
+			request:        createClaudeCodeLogRequest("claude-session", "2025-06-15T10:30:00Z", "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 1000),
+			expectedSource: entity.SourceClaudeCode,
+		},
+		{
+			name: "registered parser handles other tools",
+			request: &logsv1.ExportLogsServiceRequest{
+				ResourceLogs: []*logsdata.ResourceLogs{
+					{
+						ScopeLogs: []*logsdata.ScopeLogs{
+							{
+								LogRecords: []*logsdata.LogRecord{
+									{
+										Body: &commonv1.AnyValue{
+											Value: &commonv1.AnyValue_StringValue{
+												StringValue: "gemini_cli.api_response",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedSource: "gemini_cli",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			appendCommand := usecase.NewAppendApiRequestCommand(mockRepo)
+			receiver := NewReceiver(nil, nil, appendCommand)
+			receiver.RegisterParser(&stubParser{source: "gemini_cli", event: "gemini_cli.api_response"})
+
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), tt.request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != 1 {
+				t.Fatalf("Expected 1 request in repository, got %d", len(requests))
+			}
+			if requests[0].Source() != tt.expectedSource {
+				t.Errorf("Expected source %q, got %q", tt.expectedSource, requests[0].Source())
+			}
+		})
+	}
+}
+
+// stubProcessor relabels, drops or fails on every request for processor registration tests
+type stubProcessor struct {
+	user string
+	drop bool
+	err  error
+}
+
+func (p *stubProcessor) Name() string {
+	return "stub"
+}
+
+func (p *stubProcessor) Process(ctx context.Context, apiReq entity.APIRequest) (entity.APIRequest, bool, error) {
+	if p.err != nil {
+		return apiReq, true, p.err
+	}
+	if p.drop {
+		return apiReq, false, nil
+	}
+	return apiReq.WithUser(p.user), true, nil
+}
+
+func TestOTLPReceiver_RegisterProcessor(t *testing.T) {
+	tests := []struct {
+		name          string
+		processors    []Processor
+		expectedCount int
+		expectedUser  string
+		expectedLog   string
+	}{
+		{
+			name:          "no processors stores the request",
+			expectedCount: 1,
+		},
+		{
+			name:          "processor relabels the request",
+			processors:    []Processor{&stubProcessor{user: "alice@example.com"}},
+			expectedCount: 1,
+			expectedUser:  "alice@example.com",
+		},
+		{
+			name:          "processors run in registration order",
+			processors:    []Processor{&stubProcessor{user: "alice@example.com"}, &stubProcessor{user: "bob@example.com"}},
+			expectedCount: 1,
+			expectedUser:  "bob@example.com",
+		},
+		{
+			name:          "processor drops the request",
+			processors:    []Processor{&stubProcessor{drop: true}},
+			expectedCount: 0,
+			expectedLog:   "Dropped 1 API requests by processors (total: 1)",
+		},
+		{
+			name:          "failed processor keeps the request unchanged",
+			processors:    []Processor{&stubProcessor{err: fmt.Errorf("plugin exited")}, &stubProcessor{user: "bob@example.com"}},
+			expectedCount: 1,
+			expectedUser:  "bob@example.com",
+			expectedLog:   "Processor stub failed, keeping the request unchanged: plugin exited",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(mockRepo), entity.IgnoreRules{})
+			for _, processor := range tt.processors {
+				receiver.RegisterProcessor(processor)
+			}
+
+			request := createClaudeCodeLogRequest("session-1", "2025-06-15T10:30:00Z", "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 1000)
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.expectedCount {
+				t.Fatalf("Expected %d requests in repository, got %d", tt.expectedCount, len(requests))
+			}
+			if tt.expectedCount > 0 && requests[0].User() != tt.expectedUser {
+				t.Errorf("Expected user %q, got %q", tt.expectedUser, requests[0].User())
+			}
+			if tt.expectedLog != "" && !strings.Contains(buf.String(), tt.expectedLog) {
+				t.Errorf("Expected log '%s' not found in captured logs: %s", tt.expectedLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_BatchExport(t *testing.T) {
+	baseTime := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name               string
+		sessionIDs         []string
+		expectedSavedCount int
+		expectedCallCount  int
+		expectedLog        string
+	}{
+		{
+			name:               "all records of an export saved in one batch",
+			sessionIDs:         []string{"session-1", "session-2", "session-3"},
+			expectedSavedCount: 3,
+			expectedCallCount:  1,
+		},
+		{
+			name:               "duplicated records saved once",
+			sessionIDs:         []string{"session-1", "session-1", "session-2"},
+			expectedSavedCount: 2,
+			expectedCallCount:  1,
+			expectedLog:        "Dropped 1 duplicated API requests in export batch",
+		},
+		{
+			name:               "export without records skips the batch",
+			sessionIDs:         nil,
+			expectedSavedCount: 0,
+			expectedCallCount:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			appendBatch := usecase.NewAppendApiRequestBatchCommand(mockRepo)
+			receiver := NewReceiverWithBatch(nil, nil, appendBatch, entity.IgnoreRules{})
+
+			// Merge the log records into a single export request
+			request := createClaudeCodeLogRequest("placeholder", baseTime.Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			scopeLogs := request.ResourceLogs[0].ScopeLogs[0]
+			scopeLogs.LogRecords = nil
+			for _, sessionID := range tt.sessionIDs {
+				single := createClaudeCodeLogRequest(sessionID, baseTime.Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+				scopeLogs.LogRecords = append(scopeLogs.LogRecords, single.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+			}
+
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.expectedSavedCount {
+				t.Errorf("Expected %d requests in repository, got %d", tt.expectedSavedCount, len(requests))
+			}
+			if mockRepo.SaveBatchCalls() != tt.expectedCallCount {
+				t.Errorf("Expected %d SaveBatch calls, got %d", tt.expectedCallCount, mockRepo.SaveBatchCalls())
+			}
+
+			if tt.expectedLog != "" && !strings.Contains(buf.String(), tt.expectedLog) {
+				t.Errorf("Expected log '%s' not found in captured logs: %s", tt.expectedLog, buf.String())
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_Dedupe(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	appendBatch := usecase.NewAppendApiRequestBatchCommandWithOptions(mockRepo, usecase.AppendApiRequestBatchOptions{
+		Duplicates: service.NewInMemoryDuplicateFilter(service.DefaultDedupeWindow),
+	})
+	receiver := NewReceiverWithBatch(nil, nil, appendBatch, entity.IgnoreRules{})
+	timestamp := time.Now().Format(time.RFC3339Nano)
+
+	// An exporter retrying a failed export sends the same records again
+	for i := 0; i < 2; i++ {
+		request := createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+		if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+
+	// Records with the same log.record.uid are the same event, even when the usage differs
+	for _, inputTokens := range []int64{200, 300} {
+		request := createClaudeCodeLogRequest("session-2", time.Now().Format(time.RFC3339Nano), "claude-sonnet-4-20250514", inputTokens, 50, 0, 0, 0.01, 500)
+		logRecord := request.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+		logRecord.Attributes = append(logRecord.Attributes, &commonv1.KeyValue{
+			Key:   "log.record.uid",
+			Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "event-1"}},
+		})
+		if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+			t.Fatalf("Export failed: %v", err)
+		}
+	}
+
+	if mockRepo.SaveBatchCalls() != 2 {
+		t.Errorf("Expected the re-sent exports to skip the batch, got %d SaveBatch calls", mockRepo.SaveBatchCalls())
+	}
+	if receiver.DuplicatedCount() != 2 {
+		t.Errorf("Expected 2 duplicated requests, got %d", receiver.DuplicatedCount())
+	}
+
+	stats, err := receiver.GetIngestStats()
+	if err != nil {
+		t.Fatalf("GetIngestStats failed: %v", err)
+	}
+	if counts := stats.LastHour(); counts.Accepted() != 2 || counts.Duplicated() != 2 {
+		t.Errorf("Expected 2 accepted and 2 duplicated requests, got %+v", counts)
+	}
+}
+
+func TestOTLPReceiver_IngestStats(t *testing.T) {
+	rules, err := entity.NewIgnoreRules([]string{"haiku"}, nil)
+	if err != nil {
+		t.Fatalf("NewIgnoreRules failed: %v", err)
+	}
+
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(mockRepo), rules)
+	timestamp := time.Now().Format(time.RFC3339)
+
+	// One export holding a stored, a duplicated, an ignored and a malformed request
+	request := createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+	scopeLogs := request.ResourceLogs[0].ScopeLogs[0]
+	for _, extra := range []*logsv1.ExportLogsServiceRequest{
+		createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+		createClaudeCodeLogRequest("session-1", timestamp, "claude-3-5-haiku-20241022", 100, 50, 0, 0, 0.01, 500),
+		createClaudeCodeLogRequest("", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+	} {
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, extra.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	}
+
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	stats, err := receiver.GetIngestStats()
+	if err != nil {
+		t.Fatalf("GetIngestStats failed: %v", err)
+	}
+
+	for _, counts := range []entity.IngestCounts{stats.LastHour(), stats.LastDay()} {
+		if counts != entity.NewIngestCounts(1, 1, 0, 1, 1, 0) {
+			t.Errorf("Expected 1 accepted, ignored, malformed and duplicated request, got %+v", counts)
+		}
+	}
+
+	// Malformed requests are never stored
+	requests, _ := mockRepo.FindAll()
+	if len(requests) != 1 {
+		t.Errorf("Expected 1 stored request, got %d", len(requests))
+	}
+}
+
+func TestOTLPReceiver_RecordFailedWrites(t *testing.T) {
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(mockRepo), entity.IgnoreRules{})
+	timestamp := time.Now().Format(time.RFC3339)
+
+	request := createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+	if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	// Write-behind drops the accepted request after failing to write it
+	receiver.RecordFailedWrites(1)
+
+	stats, err := receiver.GetIngestStats()
+	if err != nil {
+		t.Fatalf("GetIngestStats failed: %v", err)
+	}
+	for _, counts := range []entity.IngestCounts{stats.LastHour(), stats.LastDay()} {
+		if counts != entity.NewIngestCounts(1, 0, 0, 0, 0, 1) {
+			t.Errorf("Expected 1 accepted and 1 failed request, got %+v", counts)
+		}
+	}
+}
+
+func TestOTLPReceiver_PartialSuccess(t *testing.T) {
+	rules, err := entity.NewIgnoreRules([]string{"haiku"}, nil)
+	if err != nil {
+		t.Fatalf("NewIgnoreRules failed: %v", err)
+	}
+	timestamp := time.Now().Format(time.RFC3339)
+
+	tests := []struct {
+		name             string
+		workers          int
+		extra            []*logsv1.ExportLogsServiceRequest
+		saveErr          error
+		expectedRejected int64
+		expectedMessage  string
+	}{
+		{
+			name: "stored, ignored and duplicated requests are not rejected",
+			extra: []*logsv1.ExportLogsServiceRequest{
+				createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+				createClaudeCodeLogRequest("session-1", timestamp, "claude-3-5-haiku-20241022", 100, 50, 0, 0, 0.01, 500),
+			},
+		},
+		{
+			name: "malformed requests are rejected",
+			extra: []*logsv1.ExportLogsServiceRequest{
+				createClaudeCodeLogRequest("", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+				createClaudeCodeLogRequest("session-2", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, -0.01, 500),
+			},
+			expectedRejected: 2,
+			expectedMessage:  "API requests not stored: 2 malformed",
+		},
+		{
+			name:    "malformed requests are rejected before queueing",
+			workers: 1,
+			extra: []*logsv1.ExportLogsServiceRequest{
+				createClaudeCodeLogRequest("", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500),
+			},
+			expectedRejected: 1,
+			expectedMessage:  "API requests not stored: 1 malformed",
+		},
+		{
+			name:             "failed writes are rejected",
+			saveErr:          errors.New("database is locked"),
+			expectedRejected: 1,
+			expectedMessage:  "API requests not stored: 1 failed to store",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			mockRepo.SetError(tt.saveErr)
+			receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(mockRepo), rules)
+			receiver.StartWorkers(tt.workers, 1)
+			defer receiver.StopWorkers()
+
+			request := createClaudeCodeLogRequest("session-1", timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			scopeLogs := request.ResourceLogs[0].ScopeLogs[0]
+			for _, extra := range tt.extra {
+				scopeLogs.LogRecords = append(scopeLogs.LogRecords, extra.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+			}
+
+			resp, err := receiver.GetLogsServiceServer().Export(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			if tt.expectedRejected == 0 {
+				if resp.PartialSuccess != nil {
+					t.Errorf("Expected no partial success, got %v", resp.PartialSuccess)
+				}
+				return
+			}
+			if resp.PartialSuccess.GetRejectedLogRecords() != tt.expectedRejected {
+				t.Errorf("Expected %d rejected records, got %d", tt.expectedRejected, resp.PartialSuccess.GetRejectedLogRecords())
+			}
+			if resp.PartialSuccess.GetErrorMessage() != tt.expectedMessage {
+				t.Errorf("Expected error message %q, got %q", tt.expectedMessage, resp.PartialSuccess.GetErrorMessage())
+			}
+		})
+	}
+}
+
+func TestIngestStats_Windows(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	stats := newIngestStats(now.Add(-48 * time.Hour))
+	one := entity.IngestCounts{}.Record(entity.IngestAccepted, 1)
+
+	stats.record(now.Add(-10*time.Minute), one)
+	stats.record(now.Add(-3*time.Hour), one)
+	stats.record(now.Add(-25*time.Hour), one) // outside of both windows
+
+	result := stats.stats(now)
+	if result.LastHour().Accepted() != 1 {
+		t.Errorf("Expected 1 accepted request in the last hour, got %d", result.LastHour().Accepted())
+	}
+	if result.LastDay().Accepted() != 2 {
+		t.Errorf("Expected 2 accepted requests in the last day, got %d", result.LastDay().Accepted())
+	}
+
+	// A day later the bucket of the same minute is reused instead of summed
+	stats.record(now.Add(24*time.Hour-10*time.Minute), one)
+	result = stats.stats(now.Add(24 * time.Hour))
+	if result.LastHour().Accepted() != 1 || result.LastDay().Accepted() != 1 {
+		t.Errorf("Expected only the new request after a day, got %+v", result.LastDay())
+	}
+}
+
+func TestOTLPReceiver_ClockSkew(t *testing.T) {
+	tests := []struct {
+		name               string
+		action             entity.ClockSkewAction
+		offset             time.Duration
+		expectedSavedCount int
+		expectedSkewed     int64
+		expectedWarnings   int
+	}{
+		{
+			name:               "skewed request is clamped to receive time",
+			action:             entity.ClockSkewClamp,
+			offset:             2 * time.Hour,
+			expectedSavedCount: 2,
+			expectedSkewed:     2,
+			expectedWarnings:   1,
+		},
+		{
+			name:               "skewed request is dropped",
+			action:             entity.ClockSkewDrop,
+			offset:             2 * time.Hour,
+			expectedSavedCount: 0,
+			expectedSkewed:     2,
+			expectedWarnings:   1,
+		},
+		{
+			name:               "request within tolerance is kept as is",
+			action:             entity.ClockSkewClamp,
+			offset:             time.Minute,
+			expectedSavedCount: 2,
+			expectedSkewed:     0,
+			expectedWarnings:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			originalOutput := log.Writer()
+			log.SetOutput(&buf)
+			defer log.SetOutput(originalOutput)
+
+			policy, err := entity.NewClockSkewPolicy(5*time.Minute, tt.action)
+			if err != nil {
+				t.Fatalf("NewClockSkewPolicy failed: %v", err)
+			}
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			appendBatch := usecase.NewAppendApiRequestBatchCommand(mockRepo)
+			receiver := NewReceiverWithBatch(nil, nil, appendBatch, entity.IgnoreRules{})
+			receiver.SetClockSkewPolicy(policy)
+
+			// Two records of the same session in one export, the warning is only logged once
+			timestamp := time.Now().Add(tt.offset)
+			request := createClaudeCodeLogRequest("skewed-session", timestamp.Format(time.RFC3339Nano), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			second := createClaudeCodeLogRequest("skewed-session", timestamp.Add(time.Second).Format(time.RFC3339Nano), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+			scopeLogs := request.ResourceLogs[0].ScopeLogs[0]
+			scopeLogs.LogRecords = append(scopeLogs.LogRecords, second.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+
+			if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.expectedSavedCount {
+				t.Errorf("Expected %d requests in repository, got %d", tt.expectedSavedCount, len(requests))
+			}
+			if receiver.SkewedCount() != tt.expectedSkewed {
+				t.Errorf("Expected skewed count %d, got %d", tt.expectedSkewed, receiver.SkewedCount())
+			}
+
+			if tt.expectedSkewed > 0 && tt.action == entity.ClockSkewClamp {
+				for _, req := range requests {
+					if req.Timestamp().After(time.Now()) {
+						t.Errorf("Expected clamped timestamp, got %v in the future", req.Timestamp())
+					}
+				}
+			}
+
+			warnings := strings.Count(buf.String(), "Clock skew detected: session=skewed-session")
+			if warnings != tt.expectedWarnings {
+				t.Errorf("Expected %d clock skew warnings, got %d: %s", tt.expectedWarnings, warnings, buf.String())
+			}
+		})
+	}
+}
+
+// blockingBatchRepository holds every SaveBatch until released, simulating a slow database
+type blockingBatchRepository struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingBatchRepository) SaveBatch(reqs []entity.APIRequest) error {
+	r.started <- struct{}{}
+	<-r.release
+	return nil
+}
+
+func TestOTLPReceiver_Workers(t *testing.T) {
+	tests := []struct {
+		name      string
+		workers   int
+		queueSize int
+		exports   int
+	}{
+		{
+			name:      "exports processed by a single worker",
+			workers:   1,
+			queueSize: 4,
+			exports:   10,
+		},
+		{
+			name:      "exports processed by concurrent workers",
+			workers:   4,
+			queueSize: 16,
+			exports:   50,
+		},
+		{
+			name:      "exports processed in the handler without workers",
+			workers:   0,
+			queueSize: 0,
+			exports:   5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := testutil.NewMockAPIRequestRepository()
+			appendBatch := usecase.NewAppendApiRequestBatchCommand(mockRepo)
+			receiver := NewReceiverWithBatch(nil, nil, appendBatch, entity.IgnoreRules{})
+			receiver.StartWorkers(tt.workers, tt.queueSize)
+
+			baseTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+			for i := 0; i < tt.exports; i++ {
+				request := createClaudeCodeLogRequest(fmt.Sprintf("session-%d", i), baseTime.Format(time.RFC3339), "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+				if _, err := receiver.GetLogsServiceServer().Export(context.Background(), request); err != nil {
+					t.Fatalf("Export failed: %v", err)
+				}
+			}
+			receiver.StopWorkers()
+
+			requests, _ := mockRepo.FindAll()
+			if len(requests) != tt.exports {
+				t.Errorf("Expected %d requests in repository, got %d", tt.exports, len(requests))
+			}
+			if receiver.QueueLength() != 0 {
+				t.Errorf("Expected empty queue after stopping workers, got %d", receiver.QueueLength())
+			}
+		})
+	}
+}
+
+func TestOTLPReceiver_WorkersBackpressure(t *testing.T) {
+	var buf bytes.Buffer
+	originalOutput := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(originalOutput)
+
+	repo := &blockingBatchRepository{started: make(chan struct{}, 1), release: make(chan struct{})}
+	receiver := NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(repo), entity.IgnoreRules{})
+	receiver.StartWorkers(1, 1)
+
+	timestamp := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	export := func(ctx context.Context, sessionID string) error {
+		request := createClaudeCodeLogRequest(sessionID, timestamp, "claude-sonnet-4-20250514", 100, 50, 0, 0, 0.01, 500)
+		_, err := receiver.GetLogsServiceServer().Export(ctx, request)
+		return err
+	}
+
+	// The first export occupies the worker, the second one fills the queue
+	if err := export(context.Background(), "session-1"); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	<-repo.started
+	if err := export(context.Background(), "session-2"); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := export(ctx, "session-3")
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable when the queue stays full, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "Rejected log export") {
+		t.Errorf("Expected rejected export log, got: %s", buf.String())
+	}
+
+	// Releasing the worker drains the queued export
+	close(repo.release)
+	receiver.StopWorkers()
+}
@@ -0,0 +1,113 @@
+package receiver
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errWorkersStopped rejects exports arriving while the server shuts down, exporters retry them after the restart
+var errWorkersStopped = status.Error(codes.Unavailable, "receiver is shutting down, retry later")
+
+// logsJob holds the API requests parsed from a log export, waiting to be stored by a worker
+type logsJob struct {
+	requests   []entity.APIRequest
+	counts     entity.IngestCounts // outcomes of the records left out while parsing
+	receivedAt time.Time
+}
+
+// workerPool stores log exports outside of the gRPC handler goroutine
+// The queue is bounded, an export waits for a free slot until its RPC deadline
+type workerPool struct {
+	queue chan logsJob
+	wg    sync.WaitGroup
+
+	mu       sync.RWMutex  // held for reading while sending, so the queue is never closed during a send
+	stopped  bool          // exports arriving after the workers stopped are rejected
+	stopping chan struct{} // closed when the workers stop, wakes the exports waiting for a free slot
+	stopOnce sync.Once
+}
+
+// StartWorkers stores log exports with a pool of workers and a bounded queue
+// Export parses the export and returns once it is queued, and Unavailable when the queue stays full until the RPC deadline,
+// which OTLP exporters retry with backoff. Exports are processed in the handler until workers are started,
+// and workers must be started before the receiver starts serving
+func (r *Receiver) StartWorkers(workers int, queueSize int) {
+	if workers <= 0 {
+		return
+	}
+
+	pool := &workerPool{queue: make(chan logsJob, queueSize), stopping: make(chan struct{})}
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for job := range pool.queue {
+				r.store(job)
+			}
+		}()
+	}
+	r.pool = pool
+}
+
+// StopWorkers waits until the queued exports are processed and stops the workers
+// It should be called after the receiver stops serving, e.g. after the gRPC server is gracefully stopped,
+// exports still arriving afterwards are rejected as Unavailable so the exporters retry them
+func (r *Receiver) StopWorkers() {
+	if r.pool == nil {
+		return
+	}
+	r.pool.stop()
+}
+
+// QueueLength returns the number of log exports waiting for a worker
+func (r *Receiver) QueueLength() int {
+	if r.pool == nil {
+		return 0
+	}
+	return len(r.pool.queue)
+}
+
+// enqueue queues the export for a worker, blocking while the queue is full until the context is done
+func (p *workerPool) enqueue(ctx context.Context, job logsJob) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return errWorkersStopped
+	}
+
+	// Prefer a free slot over an expired context, select picks randomly when both are ready
+	select {
+	case p.queue <- job:
+		return nil
+	default:
+	}
+
+	select {
+	case p.queue <- job:
+		return nil
+	case <-p.stopping:
+		return errWorkersStopped
+	case <-ctx.Done():
+		log.Printf("Rejected log export: receiver queue stayed full (%d exports) until %v", cap(p.queue), ctx.Err())
+		return status.Errorf(codes.Unavailable, "receiver queue is full, retry later: %v", ctx.Err())
+	}
+}
+
+// stop closes the queue once no export is sending to it and waits until the workers processed the queued exports
+func (p *workerPool) stop() {
+	p.stopOnce.Do(func() {
+		// Exports waiting for a free slot hold the read lock, wake them before locking
+		close(p.stopping)
+		p.mu.Lock()
+		p.stopped = true
+		close(p.queue)
+		p.mu.Unlock()
+	})
+	p.wg.Wait()
+}
@@ -0,0 +1,102 @@
+package replication
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// maxChunkSize keeps snapshot messages well below the default 4MB gRPC message limit
+const maxChunkSize = 256 * 1024
+
+// Service implements the ReplicationService gRPC interface
+type Service struct {
+	pb.UnimplementedReplicationServiceServer
+	getSnapshotQuery *usecase.GetSnapshotQuery
+	tokens           *Tokens
+}
+
+// NewService creates a new replication service, callers must present the token as a bearer token
+func NewService(getSnapshotQuery *usecase.GetSnapshotQuery, token string) *Service {
+	tokens, _ := NewTokens([]entity.AccessToken{{Label: "snapshot_token", Token: token}}) // Without files nothing can fail
+	return NewServiceWithTokens(getSnapshotQuery, tokens)
+}
+
+// NewServiceWithTokens creates a new replication service accepting any of the tokens, the label of the matched one is logged
+func NewServiceWithTokens(getSnapshotQuery *usecase.GetSnapshotQuery, tokens *Tokens) *Service {
+	return &Service{
+		getSnapshotQuery: getSnapshotQuery,
+		tokens:           tokens,
+	}
+}
+
+// GetSnapshot streams a consistent database snapshot to an authenticated replica
+func (s *Service) GetSnapshot(req *pb.GetSnapshotRequest, stream pb.ReplicationService_GetSnapshotServer) error {
+	label, ok := s.authorized(stream.Context())
+	if !ok {
+		log.Printf("Rejected snapshot request from %s: invalid snapshot token", peerAddress(stream.Context()))
+		return status.Error(codes.Unauthenticated, "invalid snapshot token")
+	}
+	log.Printf("Snapshot requested by %s with token %s", peerAddress(stream.Context()), label)
+
+	writer := &chunkWriter{stream: stream}
+	if _, err := s.getSnapshotQuery.Execute(stream.Context(), writer); err != nil {
+		return status.Errorf(codes.Internal, "failed to get snapshot: %v", err)
+	}
+
+	return nil
+}
+
+// authorized checks the bearer token in the request metadata, returns the label of the matched token
+func (s *Service) authorized(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	for _, value := range md.Get("authorization") {
+		token, found := strings.CutPrefix(value, "Bearer ")
+		if !found {
+			continue
+		}
+		if label, ok := s.tokens.Match(token); ok {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
+// peerAddress returns the address of the caller for the logs
+func peerAddress(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown peer"
+}
+
+// chunkWriter sends written bytes as snapshot chunks
+type chunkWriter struct {
+	stream pb.ReplicationService_GetSnapshotServer
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		size := min(len(p), maxChunkSize)
+		if err := w.stream.Send(&pb.SnapshotChunk{Data: p[:size]}); err != nil {
+			return written, err
+		}
+		written += size
+		p = p[size:]
+	}
+	return written, nil
+}
@@ -0,0 +1,194 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// mockSnapshotRepository writes fixed snapshot data
+type mockSnapshotRepository struct {
+	data []byte
+}
+
+func (m *mockSnapshotRepository) WriteSnapshot(ctx context.Context, w io.Writer) (int64, error) {
+	n, err := w.Write(m.data)
+	return int64(n), err
+}
+
+// createReplicationClient starts a replication service on an in-memory listener
+func createReplicationClient(t *testing.T, data []byte, token string) pb.ReplicationServiceClient {
+	getSnapshotQuery := usecase.NewGetSnapshotQuery(&mockSnapshotRepository{data: data})
+	return createServiceClient(t, NewService(getSnapshotQuery, token))
+}
+
+// createServiceClient serves the replication service on an in-memory listener
+func createServiceClient(t *testing.T, service *Service) pb.ReplicationServiceClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterReplicationServiceServer(server, service)
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return pb.NewReplicationServiceClient(conn)
+}
+
+// receiveSnapshot reads all chunks and returns the assembled data and chunk count
+func receiveSnapshot(ctx context.Context, client pb.ReplicationServiceClient) ([]byte, int, error) {
+	stream, err := client.GetSnapshot(ctx, &pb.GetSnapshotRequest{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var data bytes.Buffer
+	chunks := 0
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return data.Bytes(), chunks, nil
+		}
+		if err != nil {
+			return nil, chunks, err
+		}
+		data.Write(chunk.Data)
+		chunks++
+	}
+}
+
+func TestService_GetSnapshot(t *testing.T) {
+	snapshot := bytes.Repeat([]byte("ccmon"), maxChunkSize) // spans multiple chunks
+
+	tests := []struct {
+		name           string
+		serverToken    string
+		authorization  string
+		expectedCode   codes.Code
+		expectedChunks int
+	}{
+		{
+			name:           "valid token",
+			serverToken:    "secret",
+			authorization:  "Bearer secret",
+			expectedCode:   codes.OK,
+			expectedChunks: 5,
+		},
+		{
+			name:          "wrong token",
+			serverToken:   "secret",
+			authorization: "Bearer guess",
+			expectedCode:  codes.Unauthenticated,
+		},
+		{
+			name:         "missing token",
+			serverToken:  "secret",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:          "empty server token rejects everything",
+			serverToken:   "",
+			authorization: "Bearer ",
+			expectedCode:  codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := createReplicationClient(t, snapshot, tt.serverToken)
+
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+
+			data, chunks, err := receiveSnapshot(ctx, client)
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (%v)", tt.expectedCode, status.Code(err), err)
+			}
+			if tt.expectedCode != codes.OK {
+				return
+			}
+
+			if !bytes.Equal(data, snapshot) {
+				t.Errorf("Expected %d snapshot bytes, got %d", len(snapshot), len(data))
+			}
+			if chunks != tt.expectedChunks {
+				t.Errorf("Expected %d chunks, got %d", tt.expectedChunks, chunks)
+			}
+		})
+	}
+}
+
+func TestService_GetSnapshotRotatedTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+
+	tokens, err := NewTokens([]entity.AccessToken{
+		{Label: "old", Token: "old-secret"},
+		{Label: "new", Token: "new-secret"},
+		{Label: "file", File: path},
+	})
+	if err != nil {
+		t.Fatalf("NewTokens() failed: %v", err)
+	}
+	getSnapshotQuery := usecase.NewGetSnapshotQuery(&mockSnapshotRepository{data: []byte("ccmon")})
+	client := createServiceClient(t, NewServiceWithTokens(getSnapshotQuery, tokens))
+
+	pull := func(token string) codes.Code {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		_, _, err := receiveSnapshot(ctx, client)
+		return status.Code(err)
+	}
+
+	// Both the old and the new token are accepted while replicas move over
+	for _, token := range []string{"old-secret", "new-secret", "from-file"} {
+		if code := pull(token); code != codes.OK {
+			t.Errorf("Expected token %q to be accepted, got %v", token, code)
+		}
+	}
+
+	// A rotated file replaces its token on reload
+	if err := os.WriteFile(path, []byte("rotated"), 0600); err != nil {
+		t.Fatalf("Failed to write token file: %v", err)
+	}
+	if err := tokens.Reload(); err != nil {
+		t.Fatalf("Reload() failed: %v", err)
+	}
+	if code := pull("rotated"); code != codes.OK {
+		t.Errorf("Expected the rotated token to be accepted, got %v", code)
+	}
+	if code := pull("from-file"); code != codes.Unauthenticated {
+		t.Errorf("Expected the replaced token to be rejected, got %v", code)
+	}
+}
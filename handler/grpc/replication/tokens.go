@@ -0,0 +1,107 @@
+package replication
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// Tokens holds the accepted snapshot tokens, several can be valid at once so credentials rotate without rejecting replicas
+type Tokens struct {
+	sources []entity.AccessToken
+
+	mu     sync.RWMutex
+	tokens []entity.AccessToken // sources with the tokens of files read
+}
+
+// NewTokens reads the token files and returns the accepted tokens
+func NewTokens(sources []entity.AccessToken) (*Tokens, error) {
+	t := &Tokens{sources: sources}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload reads the token files again, a file which can't be read keeps its previous token
+// The other files are still reloaded, the returned error joins the failed ones
+func (t *Tokens) Reload() error {
+	t.mu.RLock()
+	previous := t.tokens
+	t.mu.RUnlock()
+
+	tokens := make([]entity.AccessToken, 0, len(t.sources))
+	var errs []error
+	for i, source := range t.sources {
+		if source.File == "" {
+			tokens = append(tokens, source)
+			continue
+		}
+
+		token, err := readTokenFile(source.File)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("token %s: %w", source.Label, err))
+			if previous != nil {
+				tokens = append(tokens, previous[i])
+			}
+			continue
+		}
+		source.Token = token
+		tokens = append(tokens, source)
+	}
+
+	// A file failing on the first load has no previous token, NewTokens reports it instead of accepting fewer tokens
+	if len(tokens) == len(t.sources) {
+		t.mu.Lock()
+		t.tokens = tokens
+		t.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// HasFiles returns true if any token is read from a file, only those change on Reload
+func (t *Tokens) HasFiles() bool {
+	for _, source := range t.sources {
+		if source.File != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the label of the accepted token, false when no token matches
+// Every token is compared, so the time taken doesn't tell which one is close
+func (t *Tokens) Match(token string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	label, matched := "", false
+	for _, accepted := range t.tokens {
+		// An empty token never matches, the service must not be reachable without one
+		if accepted.Token == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(accepted.Token)) == 1 && !matched {
+			label, matched = accepted.Label, true
+		}
+	}
+	return label, matched
+}
+
+// readTokenFile returns the token in the file, surrounding whitespace such as a trailing newline is ignored
+func readTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return token, nil
+}
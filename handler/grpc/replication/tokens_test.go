@@ -0,0 +1,113 @@
+package replication
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestTokens_Match(t *testing.T) {
+	tokens, err := NewTokens([]entity.AccessToken{
+		{Label: "primary", Token: "secret"},
+		{Label: "rotating", Token: "next-secret"},
+		{Label: "unset", Token: ""},
+	})
+	if err != nil {
+		t.Fatalf("NewTokens() failed: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedLabel string
+		expectedMatch bool
+	}{
+		{name: "first token", token: "secret", expectedLabel: "primary", expectedMatch: true},
+		{name: "second token", token: "next-secret", expectedLabel: "rotating", expectedMatch: true},
+		{name: "unknown token", token: "guess"},
+		{name: "empty token never matches", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, ok := tokens.Match(tt.token)
+			if ok != tt.expectedMatch || label != tt.expectedLabel {
+				t.Errorf("Match(%q) = %q, %v, want %q, %v", tt.token, label, ok, tt.expectedLabel, tt.expectedMatch)
+			}
+		})
+	}
+}
+
+func TestTokens_Reload(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.token")
+	second := filepath.Join(dir, "second.token")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	writeFile(first, "first-secret\n")
+	writeFile(second, "second-secret")
+
+	tokens, err := NewTokens([]entity.AccessToken{
+		{Label: "inline", Token: "inline-secret"},
+		{Label: "first", File: first},
+		{Label: "second", File: second},
+	})
+	if err != nil {
+		t.Fatalf("NewTokens() failed: %v", err)
+	}
+	if !tokens.HasFiles() {
+		t.Error("Expected tokens read from files to be reloadable")
+	}
+
+	// A file which can't be read keeps its previous token, the others are still reloaded
+	if err := os.Remove(first); err != nil {
+		t.Fatalf("Failed to remove %s: %v", first, err)
+	}
+	writeFile(second, "rotated-secret")
+	err = tokens.Reload()
+	if err == nil || !strings.Contains(err.Error(), "token first") {
+		t.Errorf("Expected the missing file to be reported, got %v", err)
+	}
+
+	for token, expected := range map[string]string{"inline-secret": "inline", "first-secret": "first", "rotated-secret": "second"} {
+		if label, ok := tokens.Match(token); !ok || label != expected {
+			t.Errorf("Match(%q) = %q, %v, want %q", token, label, ok, expected)
+		}
+	}
+	if _, ok := tokens.Match("second-secret"); ok {
+		t.Error("Expected the replaced token to be rejected")
+	}
+
+	// An empty file is rejected rather than accepting an empty token
+	writeFile(second, "\n")
+	if err := tokens.Reload(); err == nil {
+		t.Error("Expected an empty token file to fail")
+	}
+	if label, ok := tokens.Match("rotated-secret"); !ok || label != "second" {
+		t.Errorf("Expected the previous token to be kept, got %q, %v", label, ok)
+	}
+}
+
+func TestNewTokens_MissingFile(t *testing.T) {
+	_, err := NewTokens([]entity.AccessToken{{Label: "missing", File: filepath.Join(t.TempDir(), "missing.token")}})
+	if err == nil {
+		t.Fatal("Expected a missing token file to fail on startup")
+	}
+}
+
+func TestTokens_HasFiles(t *testing.T) {
+	tokens, err := NewTokens([]entity.AccessToken{{Label: "inline", Token: "secret"}})
+	if err != nil {
+		t.Fatalf("NewTokens() failed: %v", err)
+	}
+	if tokens.HasFiles() {
+		t.Error("Expected inline tokens not to need reloading")
+	}
+}
@@ -2,47 +2,203 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/grpc/auth"
 	"github.com/elct9620/ccmon/handler/grpc/query"
 	"github.com/elct9620/ccmon/handler/grpc/receiver"
+	"github.com/elct9620/ccmon/handler/grpc/replication"
+	"github.com/elct9620/ccmon/handler/grpc/star"
+	httpapi "github.com/elct9620/ccmon/handler/http"
 	pb "github.com/elct9620/ccmon/proto"
 	"github.com/elct9620/ccmon/usecase"
 	logsv1 "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	tracesv1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // ServerConfig interface to avoid import cycle
 type ServerConfig interface {
 	IsRetentionEnabled() bool
 	GetRetentionDuration() time.Duration
+	GetCleanupInterval() time.Duration
+	IsCleanupDryRun() bool
+	GetUser() string
+	GetSnapshotTokens() []entity.AccessToken
+	GetAccessTokens() []entity.AccessToken
+	GetHTTPAddress() string
+	GetPProfAddress() string
+	IsAccessLogEnabled() bool
+	GetSlowQueryThreshold() time.Duration
+	GetSlowQueryLogPath() string
+	GetRequestsPerSecond() float64
+	GetBurst() int
+	GetTLSCertFile() string
+	GetTLSKeyFile() string
+	GetTLSClientCAFile() string
+}
+
+// ReplicaConfig interface to avoid import cycle
+type ReplicaConfig interface {
+	GetPrimary() string
+	GetSyncInterval() time.Duration
+}
+
+// WriteBehind reports the requests acknowledged to exporters but dropped after failing to be written
+type WriteBehind interface {
+	SetWriteBehindDropHandler(fn func(count int))
+}
+
+// WorkersConfig interface to avoid import cycle
+type WorkersConfig interface {
+	GetCount() int
+	GetQueueSize() int
+}
+
+// ServerOptions contains the usecases and settings RunServer serves, optional collaborators are nil or disabled when not configured
+type ServerOptions struct {
+	Address             string
+	AppendBatchCommand  *usecase.AppendApiRequestBatchCommand
+	GetFilteredQuery    *usecase.GetFilteredApiRequestsQuery
+	CalculateStatsQuery *usecase.CalculateStatsQuery
+	CleanupCommand      *usecase.CleanupOldRecordsCommand
+	StarCommand         *usecase.StarApiRequestCommand // stars are not served when nil
+	GetSnapshotQuery    *usecase.GetSnapshotQuery      // replication is not served when nil
+	GetUserUsageQuery   *usecase.GetUserUsageQuery
+	WatchQuery          *usecase.WatchApiRequestsQuery // monitors poll and alerts are off when nil
+	IgnoreRules         entity.IgnoreRules
+	ClockSkew           entity.ClockSkewPolicy
+	TelemetryGap        entity.TelemetryGapPolicy
+	Processors          []receiver.Processor
+	DailySummary        DailySummary
+	ThrottleSignal      ThrottleSignal
+	Alerts              Alerts
+	Backup              Backup
+	Workers             WorkersConfig
+	WriteBehind         WriteBehind  // the requests it drops are counted as failed ingests, may be nil
+	HTTPHandler         http.Handler // the HTTP API is not served when nil
+	Config              ServerConfig
 }
 
 // RunServer runs the headless OTLP server mode
-func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, cleanupCommand *usecase.CleanupOldRecordsCommand, serverConfig ServerConfig) error {
+// The HTTP API is served alongside gRPC when an HTTP address is configured and HTTPHandler is not nil,
+// the same listener accepts OTLP/HTTP exports for clients which cannot reach the gRPC receiver
+// The pprof debug endpoints are served on their own listener when enabled
+func RunServer(opts ServerOptions) error {
 	log.Println("Starting ccmon in server mode...")
 
 	// Create the OTLP receiver
-	otlpReceiver := receiver.NewReceiver(nil, nil, appendCommand) // No channel or TUI program needed
+	otlpReceiver := receiver.NewReceiverWithBatch(nil, nil, opts.AppendBatchCommand, opts.IgnoreRules) // No channel or TUI program needed
+	if !opts.IgnoreRules.IsEmpty() {
+		log.Println("Ingestion ignore rules enabled")
+	}
+	otlpReceiver.SetClockSkewPolicy(opts.ClockSkew)
+	if opts.ClockSkew.IsEnabled() {
+		log.Printf("Clock skew detection enabled: tolerance %v, action %s", opts.ClockSkew.Tolerance(), opts.ClockSkew.Action())
+	}
+	for _, processor := range opts.Processors {
+		otlpReceiver.RegisterProcessor(processor)
+		log.Printf("Receiver plugin enabled: %s", processor.Name())
+	}
+	// Plugins are closed after the workers finish the queued exports
+	defer otlpReceiver.CloseProcessors()
+	// Workers decouple parsing and persisting from the Export RPC, so bursts don't exceed exporter deadlines
+	otlpReceiver.StartWorkers(opts.Workers.GetCount(), opts.Workers.GetQueueSize())
+	// Queued exports are processed after the gRPC server stops accepting new ones
+	defer otlpReceiver.StopWorkers()
+	if opts.Workers.GetCount() > 0 {
+		log.Printf("Receiver workers enabled: workers=%d, queue=%d", opts.Workers.GetCount(), opts.Workers.GetQueueSize())
+	}
+	// A misbehaving exporter is slowed down before its exports fill the queue for everyone
+	otlpReceiver.SetRateLimit(opts.Config.GetRequestsPerSecond(), opts.Config.GetBurst())
+	if opts.Config.GetRequestsPerSecond() > 0 {
+		log.Printf("Receiver rate limit enabled: %v exports per second per client", opts.Config.GetRequestsPerSecond())
+	}
 
 	// Create the query service
-	queryService := query.NewService(getFilteredQuery, calculateStatsQuery)
+	// The receiver tracks ingestion lag in memory, exposed through the query service
+	ingestionLagQuery := usecase.NewGetIngestionLagQuery(otlpReceiver)
+	queryService := query.NewServiceWithIngestionLag(opts.GetFilteredQuery, opts.CalculateStatsQuery, ingestionLagQuery)
+	// Ingest stats answer why received usage is missing, e.g. ignored or malformed events
+	queryService.SetIngestStatsQuery(usecase.NewGetIngestStatsQuery(otlpReceiver))
+	// Deferred writes are acknowledged as accepted, the requests they drop later still show up as failed
+	if opts.WriteBehind != nil {
+		opts.WriteBehind.SetWriteBehindDropHandler(otlpReceiver.RecordFailedWrites)
+	}
+
+	// The cleanup schedule lets clients preview which records the next cleanup removes
+	schedule := newCleanupSchedule(opts.Config.GetRetentionDuration())
+	queryService.SetRetentionQuery(usecase.NewGetRetentionQuery(schedule))
+	queryService.SetUserUsageQuery(opts.GetUserUsageQuery)
+	// Monitors receive the stored requests as they arrive instead of polling for them
+	if opts.WatchQuery != nil {
+		queryService.SetWatchQuery(opts.WatchQuery)
+	}
+
+	// Bind the HTTP API and pprof endpoints before privileges are dropped by the gRPC listener
+	var httpLis, pprofLis net.Listener
+	var err error
+	if httpAddress := opts.Config.GetHTTPAddress(); httpAddress != "" && opts.HTTPHandler != nil {
+		httpLis, err = net.Listen("tcp", httpAddress)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", httpAddress, err)
+		}
+	}
+	if pprofAddress := opts.Config.GetPProfAddress(); pprofAddress != "" {
+		pprofLis, err = net.Listen("tcp", pprofAddress)
+		if err != nil {
+			closeListeners(httpLis)
+			return fmt.Errorf("failed to listen on %s: %w", pprofAddress, err)
+		}
+	}
+
+	// The slow query log file is opened before privileges are dropped
+	queryLog, closeQueryLog, err := newQueryLog(opts.Config)
+	if err != nil {
+		closeListeners(httpLis, pprofLis)
+		return err
+	}
+	defer closeQueryLog()
+
+	tlsConfig, err := newTLSConfig(opts.Config)
+	if err != nil {
+		closeListeners(httpLis, pprofLis)
+		return err
+	}
+	// The HTTP API carries the same tokens and usage as gRPC, so it is served with the same certificate
+	if tlsConfig != nil && httpLis != nil {
+		httpLis = tls.NewListener(httpLis, tlsConfig)
+	}
 
-	// Set up gRPC server
-	lis, err := net.Listen("tcp", address)
+	lis, err := listen(opts.Address, opts.Config)
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		closeListeners(httpLis, pprofLis)
+		return err
 	}
 
-	grpcServer := grpc.NewServer()
+	authorizer, err := newAuthorizer(opts.Config)
+	if err != nil {
+		closeListeners(lis, httpLis, pprofLis)
+		return err
+	}
+
+	queryService.SetQueryLog(queryLog)
+	grpcServer := grpc.NewServer(serverOptions(queryLog, authorizer, tlsConfig)...)
 
 	// Register the OTLP services
 	tracesv1.RegisterTraceServiceServer(grpcServer, otlpReceiver.GetTraceServiceServer())
@@ -51,7 +207,258 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 
 	// Register the query service
 	pb.RegisterQueryServiceServer(grpcServer, queryService)
+	// Starred records are kept by the cleanup, replicas leave starring to the primary
+	if opts.StarCommand != nil {
+		pb.RegisterStarServiceServer(grpcServer, star.NewService(opts.StarCommand))
+	}
+	tokens, err := registerReplicationService(grpcServer, opts.GetSnapshotQuery, opts.Config)
+	if err != nil {
+		closeListeners(lis, httpLis, pprofLis)
+		return err
+	}
+
+	var waitHTTP func()
+	err = serve(grpcServer, lis, "gRPC server (OTLP + Query)", func(ctx context.Context) {
+		if tokens != nil && tokens.HasFiles() {
+			startTokenReloader(ctx, "Snapshot", tokens)
+		}
+		if authorizer != nil && authorizer.HasFiles() {
+			startTokenReloader(ctx, "Access", authorizer)
+		}
+
+		// Watch streams never end on their own, close them so the graceful stop can complete
+		go func() {
+			<-ctx.Done()
+			queryService.StopWatching()
+		}()
+
+		if httpLis != nil {
+			apiHandler, otlpHandler := opts.HTTPHandler, otlpReceiver.HTTPHandler()
+			// OTLP/HTTP exports need a write token like gRPC exports, the API a read token like the query service
+			if authorizer != nil {
+				apiHandler = authorizer.HTTPMiddleware(apiHandler, entity.TokenRoleRead)
+				otlpHandler = authorizer.HTTPMiddleware(otlpHandler, entity.TokenRoleWrite)
+			}
+			waitHTTP = startHTTPServer(ctx, httpLis, withOTLPHTTP(apiHandler, otlpHandler), "HTTP API (OTLP/HTTP + API)")
+		}
+		if pprofLis != nil {
+			startHTTPServer(ctx, pprofLis, httpapi.NewPProfHandler(), "pprof debug endpoints")
+		}
+
+		// Start cleanup scheduler if retention is enabled
+		if opts.Config.IsRetentionEnabled() {
+			// Cleanups wait for queued exports, so heavy write load is not slowed down further
+			startCleanupScheduler(ctx, opts.CleanupCommand, schedule, opts.Config, otlpReceiver.QueueLength)
+		}
+
+		if opts.TelemetryGap.IsEnabled() {
+			startTelemetryGapMonitor(ctx, otlpReceiver, opts.TelemetryGap)
+		}
+
+		if opts.DailySummary.IsEnabled() {
+			startDailySummaryScheduler(ctx, opts.DailySummary)
+		}
+
+		if opts.ThrottleSignal.IsEnabled() {
+			startThrottleSignalWriter(ctx, opts.ThrottleSignal)
+		}
+
+		if opts.Alerts.IsEnabled() && opts.WatchQuery != nil {
+			startAlertEvaluator(ctx, opts.Alerts, opts.WatchQuery)
+		}
+
+		if opts.Backup.IsEnabled() {
+			startBackupScheduler(ctx, opts.Backup)
+		}
+	})
+	// OTLP/HTTP exports share the receiver queue, which the deferred StopWorkers closes
+	if waitHTTP != nil {
+		waitHTTP()
+	}
+	return err
+}
+
+// RunReplicaServer runs a read-only server which periodically syncs a snapshot from the primary
+// OTLP ingestion and retention are left to the primary, the replica only serves queries
+func RunReplicaServer(address string, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, syncCommand *usecase.SyncSnapshotCommand, getSnapshotQuery *usecase.GetSnapshotQuery, replicaConfig ReplicaConfig, serverConfig ServerConfig) error {
+	log.Printf("Starting ccmon in replica mode of %s...", replicaConfig.GetPrimary())
+
+	queryService := query.NewService(getFilteredQuery, calculateStatsQuery)
+
+	queryLog, closeQueryLog, err := newQueryLog(serverConfig)
+	if err != nil {
+		return err
+	}
+	defer closeQueryLog()
+
+	tlsConfig, err := newTLSConfig(serverConfig)
+	if err != nil {
+		return err
+	}
+
+	lis, err := listen(address, serverConfig)
+	if err != nil {
+		return err
+	}
+
+	authorizer, err := newAuthorizer(serverConfig)
+	if err != nil {
+		closeListeners(lis)
+		return err
+	}
+
+	queryService.SetQueryLog(queryLog)
+	grpcServer := grpc.NewServer(serverOptions(queryLog, authorizer, tlsConfig)...)
+	pb.RegisterQueryServiceServer(grpcServer, queryService)
+	// Replicas can be chained by giving them a snapshot token too
+	tokens, err := registerReplicationService(grpcServer, getSnapshotQuery, serverConfig)
+	if err != nil {
+		closeListeners(lis)
+		return err
+	}
+
+	return serve(grpcServer, lis, "gRPC replica server (Query)", func(ctx context.Context) {
+		if tokens != nil && tokens.HasFiles() {
+			startTokenReloader(ctx, "Snapshot", tokens)
+		}
+		if authorizer != nil && authorizer.HasFiles() {
+			startTokenReloader(ctx, "Access", authorizer)
+		}
+
+		startSyncScheduler(ctx, syncCommand, replicaConfig.GetSyncInterval())
+	})
+}
+
+// newQueryLog creates the query service access and slow query logs, returns nil when both are disabled
+// The returned function closes the dedicated slow query log file
+func newQueryLog(serverConfig ServerConfig) (*query.QueryLog, func(), error) {
+	closeLog := func() {}
+
+	var access *log.Logger
+	if serverConfig.IsAccessLogEnabled() {
+		access = log.New(log.Writer(), "query: ", log.LstdFlags)
+		log.Println("Query access log enabled")
+	}
+
+	var slow *log.Logger
+	threshold := serverConfig.GetSlowQueryThreshold()
+	if threshold > 0 {
+		slow = log.New(log.Writer(), "slow query: ", log.LstdFlags)
+		if path := serverConfig.GetSlowQueryLogPath(); path != "" {
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to open slow query log %s: %w", path, err)
+			}
+			slow = log.New(file, "", log.LstdFlags)
+			closeLog = func() {
+				if err := file.Close(); err != nil {
+					log.Printf("Error closing slow query log: %v", err)
+				}
+			}
+		}
+		log.Printf("Slow query log enabled: threshold %v", threshold)
+	}
+
+	if access == nil && slow == nil {
+		return nil, closeLog, nil
+	}
+	return query.NewQueryLog(access, slow, threshold), closeLog, nil
+}
 
+// serverOptions checks the access tokens when configured and logs the query service calls when the query log is enabled
+// Rejected calls never reach the query log, the authorizer logs them instead
+// Calls are served over TLS when tlsConfig is not nil
+func serverOptions(queryLog *query.QueryLog, authorizer *auth.Authorizer, tlsConfig *tls.Config) []grpc.ServerOption {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if authorizer != nil {
+		unary = append(unary, authorizer.UnaryServerInterceptor())
+		stream = append(stream, authorizer.StreamServerInterceptor())
+	}
+	if queryLog != nil {
+		unary = append(unary, queryLog.UnaryServerInterceptor())
+	}
+
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...)}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	return opts
+}
+
+// newAuthorizer scopes the gRPC services and OTLP/HTTP exports to the access tokens, returns nil when none is configured
+// Token files are read after privileges are dropped, so a file the server can't reload fails on startup
+func newAuthorizer(serverConfig ServerConfig) (*auth.Authorizer, error) {
+	sources := serverConfig.GetAccessTokens()
+	if len(sources) == 0 {
+		return nil, nil
+	}
+
+	authorizer, err := auth.NewAuthorizer(sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access tokens: %w", err)
+	}
+
+	log.Printf("Access tokens required with %d tokens", len(sources))
+	return authorizer, nil
+}
+
+// registerReplicationService exposes snapshots to replicas, only when a snapshot token is configured
+// Token files are read after privileges are dropped, so a file the server can't reload fails on startup
+// Returns the accepted tokens, nil when the service is not registered
+func registerReplicationService(grpcServer *grpc.Server, getSnapshotQuery *usecase.GetSnapshotQuery, serverConfig ServerConfig) (*replication.Tokens, error) {
+	sources := serverConfig.GetSnapshotTokens()
+	if len(sources) == 0 || getSnapshotQuery == nil {
+		return nil, nil
+	}
+
+	tokens, err := replication.NewTokens(sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot tokens: %w", err)
+	}
+
+	pb.RegisterReplicationServiceServer(grpcServer, replication.NewServiceWithTokens(getSnapshotQuery, tokens))
+	log.Printf("Replication service enabled with %d snapshot tokens", len(sources))
+	return tokens, nil
+}
+
+// registerProbeServices registers the standard health checking and reflection services
+// Every registered service and the whole server ("") report SERVING, so Kubernetes probes and grpcurl work without configuration
+// Register it after the other services, the returned health server reports NOT_SERVING once shut down
+func registerProbeServices(grpcServer *grpc.Server) *health.Server {
+	healthServer := health.NewServer()
+	for name := range grpcServer.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	return healthServer
+}
+
+// listen sets up the listener (inherited from systemd socket activation when available)
+func listen(address string, serverConfig ServerConfig) (net.Listener, error) {
+	lis, err := newListener(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop privileges after binding so the server can use privileged ports with least privilege
+	if user := serverConfig.GetUser(); user != "" {
+		if err := dropPrivileges(user); err != nil {
+			if closeErr := lis.Close(); closeErr != nil {
+				log.Printf("Error closing listener: %v", closeErr)
+			}
+			return nil, fmt.Errorf("failed to drop privileges: %w", err)
+		}
+		log.Printf("Dropped privileges to user %s", user)
+	}
+
+	return lis, nil
+}
+
+// serve runs the gRPC server until an interrupt signal, background tasks are started with the server context
+func serve(grpcServer *grpc.Server, lis net.Listener, name string, startBackground func(ctx context.Context)) error {
 	// Create a context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -65,19 +472,19 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 		cancel()
 	}()
 
-	// Start cleanup scheduler if retention is enabled
-	if serverConfig.IsRetentionEnabled() {
-		startCleanupScheduler(ctx, cleanupCommand, serverConfig)
-	}
+	healthServer := registerProbeServices(grpcServer)
+
+	startBackground(ctx)
 
-	// Handle graceful shutdown
+	// Handle graceful shutdown, probes see NOT_SERVING while the open calls complete
 	go func() {
 		<-ctx.Done()
+		healthServer.Shutdown()
 		grpcServer.GracefulStop()
 	}()
 
 	// Start the gRPC server
-	log.Printf("gRPC server (OTLP + Query) listening on %s\n", address)
+	log.Printf("%s listening on %s\n", name, lis.Addr())
 	if err := grpcServer.Serve(lis); err != nil {
 		return fmt.Errorf("failed to start gRPC server: %w", err)
 	}
@@ -85,19 +492,114 @@ func RunServer(address string, appendCommand *usecase.AppendApiRequestCommand, g
 	return nil
 }
 
+// closeListeners closes the bound listeners when the server fails to start, nil listeners are skipped
+func closeListeners(listeners ...net.Listener) {
+	for _, lis := range listeners {
+		if lis == nil {
+			continue
+		}
+		if err := lis.Close(); err != nil {
+			log.Printf("Error closing listener: %v", err)
+		}
+	}
+}
+
+// startHTTPServer serves the handler in the background until the server context is done
+// The returned function waits until the shutdown finished, so no request is in flight anymore
+func startHTTPServer(ctx context.Context, lis net.Listener, handler http.Handler, name string) (wait func()) {
+	httpServer := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down HTTP server: %v", err)
+		}
+	}()
+
+	go func() {
+		log.Printf("%s listening on %s\n", name, lis.Addr())
+		if err := httpServer.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP server error: %v", err)
+		}
+	}()
+
+	return func() {
+		<-stopped
+	}
+}
+
+// withOTLPHTTP routes the OTLP/HTTP export paths to the receiver and everything else to the HTTP API
+func withOTLPHTTP(api http.Handler, otlp http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /v1/logs", otlp)
+	mux.Handle("POST /v1/metrics", otlp)
+	mux.Handle("POST /v1/traces", otlp)
+	mux.Handle("/", api)
+	return mux
+}
+
+// startSyncScheduler starts a background snapshot sync from the primary
+func startSyncScheduler(ctx context.Context, syncCommand *usecase.SyncSnapshotCommand, interval time.Duration) {
+	log.Printf("Starting snapshot sync scheduler: interval=%v", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// Run initial sync so the replica serves data as soon as possible
+		runSync(ctx, syncCommand)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Snapshot sync scheduler stopped")
+				return
+			case <-ticker.C:
+				runSync(ctx, syncCommand)
+			}
+		}
+	}()
+}
+
+// runSync performs a single snapshot sync, keeping the previous data on failure
+func runSync(ctx context.Context, syncCommand *usecase.SyncSnapshotCommand) {
+	start := time.Now()
+
+	result, err := syncCommand.Execute(ctx)
+	if err != nil {
+		log.Printf("Snapshot sync failed, serving previous data: %v", err)
+		return
+	}
+
+	log.Printf("Snapshot sync completed: %d bytes in %v", result.Size, time.Since(start).Round(time.Millisecond))
+}
+
 // startCleanupScheduler starts a background cleanup scheduler
-func startCleanupScheduler(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, serverConfig ServerConfig) {
+// The schedule is updated with the next run time after every cleanup
+// Every run waits while log exports are queued, see waitForIdleWrites
+func startCleanupScheduler(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, schedule *cleanupSchedule, serverConfig ServerConfig, queueLength func() int) {
 	retentionDuration := serverConfig.GetRetentionDuration()
-	cleanupInterval := 6 * time.Hour // Run cleanup every 6 hours
+	interval := serverConfig.GetCleanupInterval()
+	dryRun := serverConfig.IsCleanupDryRun()
 
-	log.Printf("Starting cleanup scheduler: retention=%v, interval=%v", retentionDuration, cleanupInterval)
+	log.Printf("Starting cleanup scheduler: retention=%v, interval=%v, dry_run=%v", retentionDuration, interval, dryRun)
 
 	go func() {
-		ticker := time.NewTicker(cleanupInterval)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		// Run initial cleanup
-		runCleanup(ctx, cleanupCommand, retentionDuration)
+		if waitForIdleWrites(ctx, queueLength, cleanupBusyRetry, cleanupMaxDelay) {
+			runCleanup(ctx, cleanupCommand, retentionDuration, dryRun)
+		}
+		schedule.scheduleNext(time.Now().Add(interval))
 
 		for {
 			select {
@@ -105,20 +607,57 @@ func startCleanupScheduler(ctx context.Context, cleanupCommand *usecase.CleanupO
 				log.Println("Cleanup scheduler stopped")
 				return
 			case <-ticker.C:
-				runCleanup(ctx, cleanupCommand, retentionDuration)
+				if !waitForIdleWrites(ctx, queueLength, cleanupBusyRetry, cleanupMaxDelay) {
+					continue
+				}
+				runCleanup(ctx, cleanupCommand, retentionDuration, dryRun)
+				schedule.scheduleNext(time.Now().Add(interval))
 			}
 		}
 	}()
 }
 
-// runCleanup performs a single cleanup operation
-func runCleanup(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, retentionDuration time.Duration) {
+// waitForIdleWrites postpones a cleanup while log exports are queued for the workers
+// The cleanup runs anyway after maxDelay, so a steady write load cannot keep old records forever
+// Returns false when the context is done before the cleanup may run
+func waitForIdleWrites(ctx context.Context, queueLength func() int, retry time.Duration, maxDelay time.Duration) bool {
+	if queueLength == nil {
+		return ctx.Err() == nil
+	}
+
+	deadline := time.Now().Add(maxDelay)
+	for {
+		queued := queueLength()
+		if queued == 0 {
+			return ctx.Err() == nil
+		}
+		if !time.Now().Before(deadline) {
+			log.Printf("Cleanup running under write load after waiting %v: %d exports queued", maxDelay, queued)
+			return ctx.Err() == nil
+		}
+
+		log.Printf("Cleanup paused: %d exports queued, retrying in %v", queued, retry)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retry):
+		}
+	}
+}
+
+// runCleanup performs a single cleanup operation, a dry run only logs what would be deleted
+func runCleanup(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCommand, retentionDuration time.Duration, dryRun bool) {
 	cutoffTime := time.Now().Add(-retentionDuration)
 
-	log.Printf("Running cleanup: deleting records older than %v", cutoffTime)
+	if dryRun {
+		log.Printf("Running cleanup dry run: counting records older than %v", cutoffTime)
+	} else {
+		log.Printf("Running cleanup: deleting records older than %v", cutoffTime)
+	}
 
 	params := usecase.CleanupOldRecordsParams{
 		CutoffTime: cutoffTime,
+		DryRun:     dryRun,
 	}
 
 	result, err := cleanupCommand.Execute(ctx, params)
@@ -127,9 +666,12 @@ func runCleanup(ctx context.Context, cleanupCommand *usecase.CleanupOldRecordsCo
 		return
 	}
 
-	if result.DeletedCount > 0 {
+	switch {
+	case dryRun:
+		log.Printf("Cleanup dry run completed: would delete %d records", result.DeletedCount)
+	case result.DeletedCount > 0:
 		log.Printf("Cleanup completed: deleted %d records", result.DeletedCount)
-	} else {
+	default:
 		log.Printf("Cleanup completed: no records to delete")
 	}
 }
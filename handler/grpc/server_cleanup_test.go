@@ -17,6 +17,11 @@ import (
 // MockServerConfig implements ServerConfig interface for testing
 type MockServerConfig struct {
 	retention string
+	interval  time.Duration
+	dryRun    bool
+	certFile  string
+	keyFile   string
+	clientCA  string
 }
 
 func (m MockServerConfig) IsRetentionEnabled() bool {
@@ -34,6 +39,69 @@ func (m MockServerConfig) GetRetentionDuration() time.Duration {
 	return duration
 }
 
+func (m MockServerConfig) GetCleanupInterval() time.Duration {
+	if m.interval == 0 {
+		return 6 * time.Hour
+	}
+	return m.interval
+}
+
+func (m MockServerConfig) IsCleanupDryRun() bool {
+	return m.dryRun
+}
+
+func (m MockServerConfig) GetUser() string {
+	return ""
+}
+
+func (m MockServerConfig) GetSnapshotTokens() []entity.AccessToken {
+	return nil
+}
+
+func (m MockServerConfig) GetAccessTokens() []entity.AccessToken {
+	return nil
+}
+
+func (m MockServerConfig) GetHTTPAddress() string {
+	return ""
+}
+
+func (m MockServerConfig) GetPProfAddress() string {
+	return ""
+}
+
+func (m MockServerConfig) IsAccessLogEnabled() bool {
+	return false
+}
+
+func (m MockServerConfig) GetSlowQueryThreshold() time.Duration {
+	return 0
+}
+
+func (m MockServerConfig) GetSlowQueryLogPath() string {
+	return ""
+}
+
+func (m MockServerConfig) GetRequestsPerSecond() float64 {
+	return 0
+}
+
+func (m MockServerConfig) GetBurst() int {
+	return 0
+}
+
+func (m MockServerConfig) GetTLSCertFile() string {
+	return m.certFile
+}
+
+func (m MockServerConfig) GetTLSKeyFile() string {
+	return m.keyFile
+}
+
+func (m MockServerConfig) GetTLSClientCAFile() string {
+	return m.clientCA
+}
+
 func TestCleanupSchedulerIntegration(t *testing.T) {
 	t.Parallel()
 
@@ -99,7 +167,7 @@ func TestCleanupSchedulerIntegration(t *testing.T) {
 
 			// Start cleanup scheduler
 			if tt.serverConfig.IsRetentionEnabled() {
-				startCleanupScheduler(ctx, cleanupCommand, tt.serverConfig)
+				startCleanupScheduler(ctx, cleanupCommand, newCleanupSchedule(tt.serverConfig.GetRetentionDuration()), tt.serverConfig, nil)
 			}
 
 			// Wait for cleanup to potentially run
@@ -180,7 +248,7 @@ func TestRunCleanupFunction(t *testing.T) {
 	}
 
 	// Run cleanup
-	runCleanup(ctx, cleanupCommand, retentionDuration)
+	runCleanup(ctx, cleanupCommand, retentionDuration, false)
 
 	// Verify cleanup results
 	remaining, err := repo.FindAll()
@@ -230,7 +298,7 @@ func TestCleanupSchedulerCancellation(t *testing.T) {
 
 	// This should return quickly due to cancelled context
 	start := time.Now()
-	startCleanupScheduler(ctx, cleanupCommand, serverConfig)
+	startCleanupScheduler(ctx, cleanupCommand, newCleanupSchedule(serverConfig.GetRetentionDuration()), serverConfig, nil)
 
 	// Give it a moment to process the cancellation
 	time.Sleep(50 * time.Millisecond)
@@ -299,3 +367,131 @@ func convertSchemaToEntity(s schema.APIRequest) entity.APIRequest {
 	cost := entity.NewCost(s.CostUSD)
 	return entity.NewAPIRequest(s.SessionID, s.Timestamp, s.Model, tokens, cost, s.DurationMS)
 }
+
+func TestCleanupSchedulerSchedulesNextRun(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDatabase(t, createTempDBFile(t), []schema.APIRequest{})
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	cleanupCommand := usecase.NewCleanupOldRecordsCommand(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serverConfig := MockServerConfig{retention: "24h"}
+	schedule := newCleanupSchedule(serverConfig.GetRetentionDuration())
+
+	retention, err := schedule.GetRetention()
+	if err != nil {
+		t.Fatalf("Failed to get retention: %v", err)
+	}
+	if retention.IsScheduled() {
+		t.Error("Expected retention not to be scheduled before the scheduler starts")
+	}
+
+	start := time.Now()
+	startCleanupScheduler(ctx, cleanupCommand, schedule, serverConfig, nil)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		retention, err = schedule.GetRetention()
+		if err != nil {
+			t.Fatalf("Failed to get retention: %v", err)
+		}
+		if retention.IsScheduled() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !retention.IsScheduled() {
+		t.Fatal("Expected next cleanup to be scheduled after the initial run")
+	}
+	if retention.Duration() != 24*time.Hour {
+		t.Errorf("Expected 24h retention, got %v", retention.Duration())
+	}
+	interval := serverConfig.GetCleanupInterval()
+	if next := retention.NextCleanupAt(); next.Before(start.Add(interval)) || next.After(time.Now().Add(interval)) {
+		t.Errorf("Expected next cleanup about %v after start, got %v", interval, next)
+	}
+}
+
+func TestRunCleanupDryRun(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDatabase(t, createTempDBFile(t), []schema.APIRequest{
+		createTestAPIRequest("old1", time.Now().Add(-48*time.Hour)),
+		createTestAPIRequest("new1", time.Now().Add(-12*time.Hour)),
+	})
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	runCleanup(context.Background(), usecase.NewCleanupOldRecordsCommand(repo), 24*time.Hour, true)
+
+	remaining, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("Failed to fetch remaining records: %v", err)
+	}
+	if len(remaining) != 2 {
+		t.Errorf("Expected a dry run to keep all 2 records, got %d", len(remaining))
+	}
+}
+
+func TestWaitForIdleWrites(t *testing.T) {
+	t.Parallel()
+
+	t.Run("idle", func(t *testing.T) {
+		t.Parallel()
+
+		if !waitForIdleWrites(context.Background(), func() int { return 0 }, time.Hour, time.Hour) {
+			t.Error("Expected cleanup to run without queued exports")
+		}
+	})
+
+	t.Run("waits until the queue drains", func(t *testing.T) {
+		t.Parallel()
+
+		checks := 0
+		queueLength := func() int {
+			checks++
+			if checks < 3 {
+				return 5
+			}
+			return 0
+		}
+		if !waitForIdleWrites(context.Background(), queueLength, time.Millisecond, time.Hour) {
+			t.Error("Expected cleanup to run after the queue drained")
+		}
+		if checks != 3 {
+			t.Errorf("Expected 3 queue checks, got %d", checks)
+		}
+	})
+
+	t.Run("runs after the max delay", func(t *testing.T) {
+		t.Parallel()
+
+		if !waitForIdleWrites(context.Background(), func() int { return 5 }, time.Millisecond, 20*time.Millisecond) {
+			t.Error("Expected cleanup to run under steady write load after the max delay")
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if waitForIdleWrites(ctx, func() int { return 5 }, time.Hour, time.Hour) {
+			t.Error("Expected cleanup not to run after the context is done")
+		}
+	})
+}
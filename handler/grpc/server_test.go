@@ -3,6 +3,8 @@ package grpc
 import (
 	"context"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -17,8 +19,12 @@ import (
 	metricsv1 "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	tracesv1 "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -240,6 +246,131 @@ func TestGRPCServer_QueryService_GetAPIRequests(t *testing.T) {
 	}
 }
 
+func TestGRPCServer_QueryService_CountAPIRequests(t *testing.T) {
+	_, _, client, mockRepo := setupTestServer(t)
+
+	now := time.Now()
+	mockRepo.SetMockData([]entity.APIRequest{
+		mustCreateAPIRequest("session1", now.Add(-time.Hour), "claude-3-sonnet-20240229", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.50), 1500),
+		mustCreateAPIRequest("session2", now.Add(-2*time.Hour), "claude-3-haiku-20240307", entity.NewToken(200, 100, 20, 10), entity.NewCost(0.25), 800),
+		mustCreateAPIRequest("session1", now.Add(-48*time.Hour), "claude-3-sonnet-20240229", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.50), 1500),
+	})
+
+	tests := []struct {
+		name     string
+		req      *pb.CountAPIRequestsRequest
+		expected int64
+	}{
+		{
+			name:     "period",
+			req:      &pb.CountAPIRequestsRequest{StartTime: timestamppb.New(now.Add(-24 * time.Hour)), EndTime: timestamppb.New(now)},
+			expected: 2,
+		},
+		{
+			name:     "period and filter",
+			req:      &pb.CountAPIRequestsRequest{StartTime: timestamppb.New(now.Add(-24 * time.Hour)), EndTime: timestamppb.New(now), Filter: &pb.RequestFilter{SessionId: "session1"}},
+			expected: 1,
+		},
+		{
+			name:     "all time",
+			req:      &pb.CountAPIRequestsRequest{EndTime: timestamppb.New(now)},
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.CountAPIRequests(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("CountAPIRequests failed: %v", err)
+			}
+			if resp.Count != tt.expected {
+				t.Errorf("Expected count %d, got %d", tt.expected, resp.Count)
+			}
+		})
+	}
+}
+
+func TestGRPCServer_QueryService_WatchAPIRequests(t *testing.T) {
+	lis := bufconn.Listen(1024 * 1024)
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	feed := service.NewInMemoryAPIRequestFeed(service.DefaultFeedBuffer)
+
+	queryService := query.NewService(usecase.NewGetFilteredApiRequestsQuery(mockRepo), usecase.NewCalculateStatsQuery(testutil.NewMockStatsRepository(mockRepo), &service.NoOpStatsCache{}))
+	queryService.SetWatchQuery(usecase.NewWatchApiRequestsQuery(feed))
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterQueryServiceServer(grpcServer, queryService)
+	go func() {
+		_ = grpcServer.Serve(lis) // Expected to fail when test completes
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client connection: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := pb.NewQueryServiceClient(conn).WatchAPIRequests(ctx, &pb.WatchAPIRequestsRequest{Filter: &pb.RequestFilter{SessionId: "session1"}})
+	if err != nil {
+		t.Fatalf("WatchAPIRequests failed: %v", err)
+	}
+
+	// The stream may not be watching yet, publish until the request arrives
+	now := time.Now()
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				feed.Publish([]entity.APIRequest{
+					mustCreateAPIRequest("session2", now, "claude-3-haiku-20240307", entity.NewToken(200, 100, 20, 10), entity.NewCost(0.25), 800),
+					mustCreateAPIRequest("session1", now, "claude-3-sonnet-20240229", entity.NewToken(100, 50, 10, 5), entity.NewCost(0.50), 1500),
+				})
+			}
+		}
+	}()
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive requests: %v", err)
+	}
+	if len(resp.Requests) != 1 || resp.Requests[0].SessionId != "session1" {
+		t.Fatalf("Expected only the request of session1, got %v", resp.Requests)
+	}
+
+	// Stopping closes the stream so a graceful stop does not wait for the watchers
+	queryService.StopWatching()
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+}
+
+func TestGRPCServer_QueryService_WatchAPIRequestsUnimplemented(t *testing.T) {
+	_, _, client, _ := setupTestServer(t)
+
+	stream, err := client.WatchAPIRequests(context.Background(), &pb.WatchAPIRequestsRequest{})
+	if err != nil {
+		t.Fatalf("WatchAPIRequests failed: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented without a watch query, got %v", err)
+	}
+}
+
 func TestGRPCServer_QueryService_AllTimeRequests(t *testing.T) {
 	_, _, client, mockRepo := setupTestServer(t)
 
@@ -291,3 +422,157 @@ func TestGRPCServer_ServiceRegistration(t *testing.T) {
 		t.Error("MetricsService not registered")
 	}
 }
+
+func TestWithOTLPHTTP(t *testing.T) {
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "api")
+	})
+	otlp := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "otlp")
+	})
+	handler := withOTLPHTTP(api, otlp)
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodPost, path: "/v1/logs", expected: "otlp"},
+		{method: http.MethodPost, path: "/v1/metrics", expected: "otlp"},
+		{method: http.MethodPost, path: "/v1/traces", expected: "otlp"},
+		{method: http.MethodGet, path: "/v1/now", expected: "api"},
+		{method: http.MethodGet, path: "/v1/users", expected: "api"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := rec.Header().Get("X-Handler"); got != tt.expected {
+			t.Errorf("%s %s was handled by %q, want %q", tt.method, tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestGRPCServer_ProbeServices(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	lis := bufconn.Listen(1024 * 1024)
+	pb.RegisterQueryServiceServer(grpcServer, query.NewService(nil, nil))
+	healthServer := registerProbeServices(grpcServer)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			t.Logf("Server stopped: %v", err)
+		}
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client connection: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("Error closing connection: %v", err)
+		}
+		grpcServer.Stop()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := healthpb.NewHealthClient(conn)
+	for _, service := range []string{"", "ccmon.v1.QueryService"} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Health check of %q failed: %v", service, err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			t.Errorf("Expected %q to be SERVING, got %v", service, resp.GetStatus())
+		}
+	}
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown.Service"}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for an unknown service, got %v", err)
+	}
+
+	// Reflection lists the registered services for grpcurl
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("Failed to open reflection stream: %v", err)
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive services: %v", err)
+	}
+	services := map[string]bool{}
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services[service.GetName()] = true
+	}
+	for _, name := range []string{"ccmon.v1.QueryService", "grpc.health.v1.Health"} {
+		if !services[name] {
+			t.Errorf("Expected reflection to list %s, got %v", name, services)
+		}
+	}
+
+	// Shutting down reports NOT_SERVING while open calls complete
+	healthServer.Shutdown()
+	resp2, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp2.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Expected NOT_SERVING after shutdown, got %v", resp2.GetStatus())
+	}
+}
+
+func TestStartHTTPServer_WaitsForRequestsInFlight(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := startHTTPServer(ctx, lis, handler, "test HTTP server")
+
+	go func() {
+		resp, err := http.Post("http://"+lis.Addr().String()+"/v1/logs", "application/x-protobuf", nil)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Expected the shutdown to wait for the request in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the shutdown to finish after the request completed")
+	}
+}
@@ -0,0 +1,55 @@
+package star
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Service implements the StarService gRPC interface
+type Service struct {
+	pb.UnimplementedStarServiceServer
+	starCommand *usecase.StarApiRequestCommand
+}
+
+// NewService creates a new star service instance
+func NewService(starCommand *usecase.StarApiRequestCommand) *Service {
+	return &Service{
+		starCommand: starCommand,
+	}
+}
+
+// SetStar stars the request or its whole session so the retention cleanup keeps it
+func (s *Service) SetStar(ctx context.Context, req *pb.SetStarRequest) (*pb.SetStarResponse, error) {
+	params := usecase.StarApiRequestParams{
+		RequestID: req.RequestId,
+		SessionID: req.SessionId,
+		Scope:     convertProtoToStarScope(req.Scope),
+	}
+
+	if err := s.starCommand.Execute(ctx, params); err != nil {
+		if errors.Is(err, usecase.ErrStarTargetMissing) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set star: %v", err)
+	}
+
+	return &pb.SetStarResponse{}, nil
+}
+
+// convertProtoToStarScope converts protobuf StarScope to entity.StarScope, unknown scopes remove the star
+func convertProtoToStarScope(scope pb.StarScope) entity.StarScope {
+	switch scope {
+	case pb.StarScope_STAR_SCOPE_REQUEST:
+		return entity.StarRequest
+	case pb.StarScope_STAR_SCOPE_SESSION:
+		return entity.StarSession
+	default:
+		return entity.StarNone
+	}
+}
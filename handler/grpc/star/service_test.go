@@ -0,0 +1,71 @@
+package star
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestService_SetStar(t *testing.T) {
+	request := entity.NewAPIRequest("session-1", time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+
+	tests := []struct {
+		name          string
+		req           *pb.SetStarRequest
+		repoErr       error
+		expectedCode  codes.Code
+		expectedScope entity.StarScope
+	}{
+		{
+			name:          "star request",
+			req:           &pb.SetStarRequest{RequestId: request.ID(), SessionId: request.SessionID(), Scope: pb.StarScope_STAR_SCOPE_REQUEST},
+			expectedCode:  codes.OK,
+			expectedScope: entity.StarRequest,
+		},
+		{
+			name:          "star session",
+			req:           &pb.SetStarRequest{RequestId: request.ID(), SessionId: request.SessionID(), Scope: pb.StarScope_STAR_SCOPE_SESSION},
+			expectedCode:  codes.OK,
+			expectedScope: entity.StarSession,
+		},
+		{
+			name:          "missing request ID",
+			req:           &pb.SetStarRequest{SessionId: request.SessionID(), Scope: pb.StarScope_STAR_SCOPE_REQUEST},
+			expectedCode:  codes.InvalidArgument,
+			expectedScope: entity.StarNone,
+		},
+		{
+			name:          "repository error",
+			req:           &pb.SetStarRequest{RequestId: request.ID(), SessionId: request.SessionID(), Scope: pb.StarScope_STAR_SCOPE_REQUEST},
+			repoErr:       &testutil.MockError{Message: "database is closed"},
+			expectedCode:  codes.Internal,
+			expectedScope: entity.StarNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockStarRepository()
+			repo.SetError(tt.repoErr)
+			service := NewService(usecase.NewStarApiRequestCommand(repo))
+
+			_, err := service.SetStar(context.Background(), tt.req)
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected code %v, got %v (%v)", tt.expectedCode, status.Code(err), err)
+			}
+
+			repo.SetError(nil)
+			stars, _ := repo.GetStars()
+			if got := stars.ScopeOf(request); got != tt.expectedScope {
+				t.Errorf("Expected scope %v, got %v", tt.expectedScope, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// telemetryGapCheckInterval is how often the server checks for a telemetry gap
+const telemetryGapCheckInterval = time.Minute
+
+// eventSource reports when the last telemetry event was received
+type eventSource interface {
+	LastEventAt() time.Time
+}
+
+// telemetryGapAlert raises a single alert per gap, until events arrive again
+type telemetryGapAlert struct {
+	policy entity.TelemetryGapPolicy
+	gapAt  time.Time // last event time of the reported gap, zero when no gap is reported
+}
+
+// newTelemetryGapAlert creates an alert for the given policy
+func newTelemetryGapAlert(policy entity.TelemetryGapPolicy) *telemetryGapAlert {
+	return &telemetryGapAlert{policy: policy}
+}
+
+// check returns the alert message when a gap starts or ends, empty otherwise
+func (a *telemetryGapAlert) check(lastEventAt, now time.Time) string {
+	if !a.gapAt.IsZero() {
+		if !lastEventAt.After(a.gapAt) {
+			return ""
+		}
+
+		gap := lastEventAt.Sub(a.gapAt).Round(time.Minute)
+		a.gapAt = time.Time{}
+		return fmt.Sprintf("Telemetry resumed: events are received again after a gap of %v", gap)
+	}
+
+	if !a.policy.IsGap(lastEventAt, now) {
+		return ""
+	}
+
+	a.gapAt = lastEventAt
+	return fmt.Sprintf("Telemetry gap alert: no Claude Code events received for %v (last at %s), check the OTEL_* environment variables and exporter settings of Claude Code",
+		now.Sub(lastEventAt).Round(time.Minute), lastEventAt.UTC().Format(time.RFC3339))
+}
+
+// startTelemetryGapMonitor logs an alert when a previously active exporter stops sending events
+func startTelemetryGapMonitor(ctx context.Context, source eventSource, policy entity.TelemetryGapPolicy) {
+	log.Printf("Starting telemetry gap monitor: threshold=%v", policy.Threshold())
+
+	alert := newTelemetryGapAlert(policy)
+
+	go func() {
+		ticker := time.NewTicker(telemetryGapCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Telemetry gap monitor stopped")
+				return
+			case now := <-ticker.C:
+				if message := alert.check(source.LastEventAt(), now); message != "" {
+					log.Println(message)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestTelemetryGapAlert_Check(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	type step struct {
+		lastEventAt    time.Time
+		now            time.Time
+		expectedPrefix string // empty when no message is expected
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "never active exporter is not reported",
+			steps: []step{
+				{lastEventAt: time.Time{}, now: start.Add(5 * time.Hour)},
+			},
+		},
+		{
+			name: "gap is reported once",
+			steps: []step{
+				{lastEventAt: start, now: start.Add(time.Hour)},
+				{lastEventAt: start, now: start.Add(2 * time.Hour), expectedPrefix: "Telemetry gap alert"},
+				{lastEventAt: start, now: start.Add(3 * time.Hour)},
+			},
+		},
+		{
+			name: "resumed events end the gap",
+			steps: []step{
+				{lastEventAt: start, now: start.Add(2 * time.Hour), expectedPrefix: "Telemetry gap alert"},
+				{lastEventAt: start.Add(4 * time.Hour), now: start.Add(4 * time.Hour), expectedPrefix: "Telemetry resumed"},
+				{lastEventAt: start.Add(4 * time.Hour), now: start.Add(5 * time.Hour)},
+				{lastEventAt: start.Add(4 * time.Hour), now: start.Add(6 * time.Hour), expectedPrefix: "Telemetry gap alert"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := entity.NewTelemetryGapPolicy(2*time.Hour, nil, time.UTC)
+			if err != nil {
+				t.Fatalf("NewTelemetryGapPolicy() returned error: %v", err)
+			}
+			alert := newTelemetryGapAlert(policy)
+
+			for i, s := range tt.steps {
+				message := alert.check(s.lastEventAt, s.now)
+				if s.expectedPrefix == "" {
+					if message != "" {
+						t.Errorf("Step %d: expected no message, got %q", i, message)
+					}
+					continue
+				}
+				if !strings.HasPrefix(message, s.expectedPrefix) {
+					t.Errorf("Step %d: expected message starting with %q, got %q", i, s.expectedPrefix, message)
+				}
+			}
+		})
+	}
+}
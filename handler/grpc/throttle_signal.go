@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// ThrottleSignal keeps the throttle signal file up to date for agent orchestrators
+type ThrottleSignal struct {
+	Command  *usecase.WriteThrottleSignalCommand // nil disables the signal
+	Interval time.Duration
+}
+
+// IsEnabled returns true if the signal is written
+func (t ThrottleSignal) IsEnabled() bool {
+	return t.Command != nil && t.Interval > 0
+}
+
+// startThrottleSignalWriter writes the signal right away and then every interval
+func startThrottleSignalWriter(ctx context.Context, throttleSignal ThrottleSignal) {
+	log.Printf("Starting throttle signal writer: interval=%v", throttleSignal.Interval)
+
+	go func() {
+		ticker := time.NewTicker(throttleSignal.Interval)
+		defer ticker.Stop()
+
+		writer := &throttleSignalWriter{command: throttleSignal.Command}
+		writer.write(ctx, time.Now())
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Throttle signal writer stopped")
+				return
+			case now := <-ticker.C:
+				writer.write(ctx, now)
+			}
+		}
+	}()
+}
+
+// throttleSignalWriter logs changes of the signal instead of every write, the interval is usually seconds
+type throttleSignalWriter struct {
+	command    *usecase.WriteThrottleSignalCommand
+	throttling bool
+	failing    bool
+}
+
+// write publishes the signal of now, a failed write keeps the previous file until the next interval
+func (w *throttleSignalWriter) write(ctx context.Context, now time.Time) {
+	signal, err := w.command.Execute(ctx, now)
+	if err != nil {
+		if !w.failing {
+			log.Printf("Throttle signal failed, retrying every interval: %v", err)
+		}
+		w.failing = true
+		return
+	}
+	if w.failing {
+		log.Println("Throttle signal written again")
+		w.failing = false
+	}
+
+	if signal.ShouldThrottle() != w.throttling {
+		w.throttling = signal.ShouldThrottle()
+		progress, _ := signal.Progress()
+		if w.throttling {
+			log.Printf("Throttle signal raised: block usage %.1f%% reached %.1f%%", progress, signal.Threshold())
+		} else {
+			log.Printf("Throttle signal cleared: block usage %.1f%%", progress)
+		}
+	}
+}
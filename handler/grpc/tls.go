@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+)
+
+// newTLSConfig loads the certificate served over TLS, returns nil when no certificate is configured
+// Clients must present a certificate signed by the client CA when one is configured
+// The files are read before privileges are dropped, so keys readable only by root can be used
+func newTLSConfig(serverConfig ServerConfig) (*tls.Config, error) {
+	certFile := serverConfig.GetTLSCertFile()
+	if certFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, serverConfig.GetTLSKeyFile())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile := serverConfig.GetTLSClientCAFile(); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in TLS client CA %s", caFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		log.Println("TLS enabled, client certificates required")
+		return config, nil
+	}
+
+	log.Println("TLS enabled")
+	return config, nil
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	files := testutil.WriteTLSFiles(t)
+
+	tests := []struct {
+		name       string
+		config     MockServerConfig
+		clientCert bool
+		disabled   bool
+		wantErr    bool
+	}{
+		{name: "disabled without certificate", config: MockServerConfig{}, disabled: true},
+		{name: "server certificate", config: MockServerConfig{certFile: files.CertFile, keyFile: files.KeyFile}},
+		{name: "client certificate required", config: MockServerConfig{certFile: files.CertFile, keyFile: files.KeyFile, clientCA: files.CAFile}, clientCert: true},
+		{name: "missing key", config: MockServerConfig{certFile: files.CertFile, keyFile: filepath.Join(t.TempDir(), "missing.pem")}, wantErr: true},
+		{name: "client CA file without certificate", config: MockServerConfig{certFile: files.CertFile, keyFile: files.KeyFile, clientCA: files.KeyFile}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := newTLSConfig(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.disabled {
+				if tlsConfig != nil {
+					t.Error("Expected TLS to be disabled")
+				}
+				return
+			}
+
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			grpcServer := grpc.NewServer(serverOptions(nil, nil, tlsConfig)...)
+			healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+			go func() {
+				_ = grpcServer.Serve(lis) // Expected to fail when test completes
+			}()
+			t.Cleanup(grpcServer.Stop)
+
+			certFile, keyFile := "", ""
+			if tt.clientCert {
+				certFile, keyFile = files.CertFile, files.KeyFile
+			}
+			tlsOpt, err := repository.WithTLS(files.CAFile, certFile, keyFile)
+			if err != nil {
+				t.Fatalf("Failed to configure client TLS: %v", err)
+			}
+			conn, err := grpc.NewClient(lis.Addr().String(), tlsOpt)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+
+			if _, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+				t.Errorf("Expected the health check over TLS to succeed, got: %v", err)
+			}
+		})
+	}
+}
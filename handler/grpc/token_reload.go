@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// reloadableTokens are tokens read from files, e.g. the snapshot or access tokens
+type reloadableTokens interface {
+	Reload() error
+}
+
+// startTokenReloader reads the token files again on SIGHUP, so credentials rotate without a restart
+// The name tells the kind of tokens in the logs, e.g. "Snapshot"
+func startTokenReloader(ctx context.Context, name string, tokens reloadableTokens) {
+	log.Printf("%s token files are reloaded on SIGHUP", name)
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangup)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reloadTokens(name, tokens)
+			}
+		}
+	}()
+}
+
+// reloadTokens reads the token files and logs the outcome, a file which can't be read keeps its previous token
+func reloadTokens(name string, tokens reloadableTokens) {
+	if err := tokens.Reload(); err != nil {
+		log.Printf("Failed to reload %s tokens, the previous ones are kept: %v", strings.ToLower(name), err)
+		return
+	}
+	log.Printf("%s tokens reloaded", name)
+}
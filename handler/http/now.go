@@ -0,0 +1,182 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// nowTimeout bounds the stats queries of a single request
+const nowTimeout = 5 * time.Second
+
+// burnRateWindow is the rolling window used for the burn rate
+const burnRateWindow = time.Hour
+
+// NowResponse is the JSON body of the /v1/now endpoint
+type NowResponse struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Timezone    string    `json:"timezone"`
+	Daily       NowDaily  `json:"daily"`
+	Block       *NowBlock `json:"block"`     // null unless the block query parameter is given
+	BurnRate    float64   `json:"burn_rate"` // rate limited tokens per minute over the last hour
+}
+
+// NowDaily is the usage of the current day
+type NowDaily struct {
+	StartAt  time.Time `json:"start_at"`
+	Requests int       `json:"requests"`
+	Tokens   int64     `json:"tokens"`
+	Cost     float64   `json:"cost"`
+}
+
+// NowBlock is the usage of the current block
+type NowBlock struct {
+	StartAt          time.Time `json:"start_at"`
+	EndAt            time.Time `json:"end_at"`
+	Tokens           int64     `json:"tokens"`      // rate limited tokens
+	TokenLimit       int       `json:"token_limit"` // 0 when the plan has no limit
+	Progress         *float64  `json:"progress"`    // percentage of the token limit, null without a limit
+	Cost             float64   `json:"cost"`
+	RemainingSeconds int64     `json:"remaining_seconds"`
+}
+
+// errorResponse is the JSON body returned for failed requests
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NowHandler serves the current block usage, daily cost and burn rate for editor status bars
+type NowHandler struct {
+	calculateStatsQuery *usecase.CalculateStatsQuery
+	timezone            *time.Location
+	tokenLimit          int
+	blockDuration       time.Duration
+}
+
+// NewNowHandler creates a new NowHandler, timezone is used when the request does not give one
+func NewNowHandler(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location, tokenLimit int) *NowHandler {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return &NowHandler{
+		calculateStatsQuery: calculateStatsQuery,
+		timezone:            timezone,
+		tokenLimit:          tokenLimit,
+	}
+}
+
+// SetBlockDuration sets the length of the blocks given by the block parameter, 5 hours when not set
+func (h *NowHandler) SetBlockDuration(duration time.Duration) {
+	h.blockDuration = duration
+}
+
+// ServeHTTP implements http.Handler
+// Supported query parameters are block (e.g. "5am") and timezone (e.g. "Asia/Taipei")
+func (h *NowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	timezone := h.timezone
+	if name := r.URL.Query().Get("timezone"); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid timezone %q", name)})
+			return
+		}
+		timezone = location
+	}
+
+	now := time.Now()
+
+	var block *entity.Block
+	if blockTime := r.URL.Query().Get("block"); blockTime != "" {
+		startHour, err := entity.ParseBlockStartHour(blockTime)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid block %q: %v", blockTime, err)})
+			return
+		}
+		currentBlock := entity.NewCurrentBlockWithDuration(startHour, timezone, now, h.tokenLimit, h.blockDuration)
+		block = &currentBlock
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), nowTimeout)
+	defer cancel()
+
+	response, err := h.Now(ctx, now, timezone, block)
+	if err != nil {
+		log.Printf("Failed to serve /v1/now: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to calculate usage"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Now builds the usage at the point in time, block is optional
+func (h *NowHandler) Now(ctx context.Context, now time.Time, timezone *time.Location, block *entity.Block) (NowResponse, error) {
+	dailyPeriod := entity.NewDayPeriod(now, timezone)
+
+	dailyStats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: dailyPeriod})
+	if err != nil {
+		return NowResponse{}, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	burnRateStats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: entity.NewPeriodFromDuration(now.UTC(), burnRateWindow)})
+	if err != nil {
+		return NowResponse{}, fmt.Errorf("failed to calculate burn rate: %w", err)
+	}
+
+	response := NowResponse{
+		GeneratedAt: now.UTC(),
+		Timezone:    timezone.String(),
+		Daily: NowDaily{
+			StartAt:  dailyPeriod.StartAt(),
+			Requests: dailyStats.TotalRequests(),
+			Tokens:   dailyStats.TotalTokens().Total(),
+			Cost:     dailyStats.TotalCost().Amount(),
+		},
+		BurnRate: burnRateStats.RateLimitedTokenBurnRate(),
+	}
+
+	if block != nil {
+		blockStats, err := h.calculateStatsQuery.Execute(ctx, usecase.CalculateStatsParams{Period: block.Period()})
+		if err != nil {
+			return NowResponse{}, fmt.Errorf("failed to calculate block stats: %w", err)
+		}
+		response.Block = newNowBlock(*block, blockStats, now)
+	}
+
+	return response, nil
+}
+
+// newNowBlock converts the block stats to the response representation
+func newNowBlock(block entity.Block, stats entity.Stats, now time.Time) *NowBlock {
+	nowBlock := &NowBlock{
+		StartAt:          block.StartAt().UTC(),
+		EndAt:            block.EndAt().UTC(),
+		Tokens:           stats.RateLimitedTokens().Limited(),
+		TokenLimit:       block.TokenLimit(),
+		Cost:             stats.TotalCost().Amount(),
+		RemainingSeconds: int64(max(block.EndAt().Sub(now), 0).Seconds()),
+	}
+
+	if block.HasLimit() {
+		progress := block.CalculateProgress(stats.RateLimitedTokens())
+		nowBlock.Progress = &progress
+	}
+
+	return nowBlock
+}
+
+// writeJSON writes the value as a JSON response with the status code
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("Failed to write JSON response: %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	httpapi "github.com/elct9620/ccmon/handler/http"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestNowHandler_ServeHTTP(t *testing.T) {
+	// Start the block at the current hour so the request falls into it
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+	blockTime := strings.ToLower(time.Now().In(taipei).Format("3pm"))
+
+	tests := []struct {
+		name             string
+		query            string
+		repoErr          error
+		expectedStatus   int
+		expectedTimezone string
+		expectBlock      bool
+		expectedProgress float64
+	}{
+		{
+			name:             "daily usage without block",
+			query:            "",
+			expectedStatus:   http.StatusOK,
+			expectedTimezone: "UTC",
+		},
+		{
+			name:             "block usage with token limit",
+			query:            "?block=" + blockTime + "&timezone=Asia/Taipei",
+			expectedStatus:   http.StatusOK,
+			expectedTimezone: "Asia/Taipei",
+			expectBlock:      true,
+			expectedProgress: 10,
+		},
+		{
+			name:           "invalid timezone",
+			query:          "?timezone=Mars/Olympus",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid block",
+			query:          "?block=25",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error",
+			query:          "",
+			repoErr:        &testutil.MockError{Message: "database error"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", time.Now(), "claude-sonnet-4-20250514", 1000, 0, 1.50),
+			})
+			if tt.repoErr != nil {
+				apiRepo.SetError(tt.repoErr)
+			}
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			handler := httpapi.NewHandler(httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 10000), nil, nil)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/now"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if rec.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", rec.Header().Get("Content-Type"))
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response httpapi.NowResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.Timezone != tt.expectedTimezone {
+				t.Errorf("Expected timezone %s, got %s", tt.expectedTimezone, response.Timezone)
+			}
+			if response.Daily.Cost != 1.50 {
+				t.Errorf("Expected daily cost 1.50, got %v", response.Daily.Cost)
+			}
+			if response.Daily.Requests != 1 {
+				t.Errorf("Expected 1 daily request, got %d", response.Daily.Requests)
+			}
+			if response.BurnRate <= 0 {
+				t.Errorf("Expected positive burn rate, got %v", response.BurnRate)
+			}
+
+			if !tt.expectBlock {
+				if response.Block != nil {
+					t.Errorf("Expected no block, got %+v", response.Block)
+				}
+				return
+			}
+
+			if response.Block == nil {
+				t.Fatal("Expected block, got nil")
+			}
+			if response.Block.Progress == nil || *response.Block.Progress != tt.expectedProgress {
+				t.Errorf("Expected block progress %v, got %v", tt.expectedProgress, response.Block.Progress)
+			}
+			if response.Block.TokenLimit != 10000 {
+				t.Errorf("Expected token limit 10000, got %d", response.Block.TokenLimit)
+			}
+			if response.Block.RemainingSeconds <= 0 || response.Block.RemainingSeconds > int64(entity.TimeBlockDuration.Seconds()) {
+				t.Errorf("Expected remaining seconds within the block, got %d", response.Block.RemainingSeconds)
+			}
+		})
+	}
+}
+
+func TestNowHandler_Now_BlockWithoutLimit(t *testing.T) {
+	now := time.Now().UTC()
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", now.Add(-time.Minute), "claude-sonnet-4-20250514", 1000, 0, 1.50),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	handler := httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0)
+
+	block := entity.NewBlock(now.Add(-time.Hour))
+	response, err := handler.Now(t.Context(), now, time.UTC, &block)
+	if err != nil {
+		t.Fatalf("Now() returned error: %v", err)
+	}
+
+	if response.Block.Progress != nil {
+		t.Errorf("Expected no progress without a token limit, got %v", *response.Block.Progress)
+	}
+	if response.Block.Tokens != 1000 {
+		t.Errorf("Expected 1000 block tokens, got %d", response.Block.Tokens)
+	}
+	if response.Block.RemainingSeconds != int64((4 * time.Hour).Seconds()) {
+		t.Errorf("Expected 4h remaining, got %ds", response.Block.RemainingSeconds)
+	}
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"net/http"
+	"net/http/pprof"
+)
+
+// NewPProfHandler creates the handler of the runtime profiling endpoints under /debug/pprof/
+// The endpoints expose internals of the process, they are only served when debugging is enabled
+func NewPProfHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	return mux
+}
@@ -0,0 +1,36 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpapi "github.com/elct9620/ccmon/handler/http"
+)
+
+func TestNewPProfHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "index", method: http.MethodGet, path: "/debug/pprof/", expectedStatus: http.StatusOK},
+		{name: "heap profile", method: http.MethodGet, path: "/debug/pprof/heap?debug=1", expectedStatus: http.StatusOK},
+		{name: "goroutine profile", method: http.MethodGet, path: "/debug/pprof/goroutine?debug=1", expectedStatus: http.StatusOK},
+		{name: "cmdline", method: http.MethodGet, path: "/debug/pprof/cmdline", expectedStatus: http.StatusOK},
+		{name: "outside the debug prefix", method: http.MethodGet, path: "/v1/now", expectedStatus: http.StatusNotFound},
+	}
+
+	handler := httpapi.NewPProfHandler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
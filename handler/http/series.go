@@ -0,0 +1,197 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// maxSeriesPoints bounds the periods of a single series request, e.g. 41 days of hours
+const maxSeriesPoints = 1000
+
+// Series intervals and the range charted when the request gives no from
+var seriesIntervals = map[string]time.Duration{
+	"hour": 48 * time.Hour,
+	"day":  30 * 24 * time.Hour,
+}
+
+// SeriesResponse is the JSON body of the /v1/series endpoint
+type SeriesResponse struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Timezone    string        `json:"timezone"`
+	Interval    string        `json:"interval"` // "hour" or "day"
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Points      []SeriesPoint `json:"points"` // oldest first
+}
+
+// SeriesPoint is the usage of a single hour or day, split by model tier
+type SeriesPoint struct {
+	Time                time.Time `json:"time"` // start of the hour or day
+	Requests            int       `json:"requests"`
+	BaseRequests        int       `json:"base_requests"`
+	PremiumRequests     int       `json:"premium_requests"`
+	LongContextRequests int       `json:"long_context_requests"`
+	Tokens              int64     `json:"tokens"`
+	BaseTokens          int64     `json:"base_tokens"`
+	PremiumTokens       int64     `json:"premium_tokens"`
+	LongContextTokens   int64     `json:"long_context_tokens"`
+	Cost                float64   `json:"cost"`
+	BaseCost            float64   `json:"base_cost"`
+	PremiumCost         float64   `json:"premium_cost"`
+	LongContextCost     float64   `json:"long_context_cost"`
+}
+
+// SeriesHandler serves the usage of consecutive hours or days, so dashboards such as Grafana can chart it
+type SeriesHandler struct {
+	usageQuery *usecase.GetUsageQuery
+	timezone   *time.Location
+}
+
+// NewSeriesHandler creates a new SeriesHandler, timezone is used when the request does not give one
+func NewSeriesHandler(usageQuery *usecase.GetUsageQuery, timezone *time.Location) *SeriesHandler {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return &SeriesHandler{
+		usageQuery: usageQuery,
+		timezone:   timezone,
+	}
+}
+
+// ServeHTTP implements http.Handler
+// Supported query parameters are interval ("hour" or "day"), from and to (RFC 3339 or Unix milliseconds as in
+// Grafana's ${__from} and ${__to}) and timezone (e.g. "Asia/Taipei") which days and hours follow
+func (h *SeriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	timezone := h.timezone
+	if name := query.Get("timezone"); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid timezone %q", name)})
+			return
+		}
+		timezone = location
+	}
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = "day"
+	}
+	defaultRange, ok := seriesIntervals[interval]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid interval %q, must be hour or day", interval)})
+		return
+	}
+
+	now := time.Now()
+	to := now
+	if value := query.Get("to"); value != "" {
+		parsed, err := parseSeriesTime(value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid to %q: %v", value, err)})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultRange)
+	if value := query.Get("from"); value != "" {
+		parsed, err := parseSeriesTime(value)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid from %q: %v", value, err)})
+			return
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "from must not be after to"})
+		return
+	}
+	// A day is at most 25 hours long when the clock changes
+	step := time.Hour
+	if interval == "day" {
+		step = 25 * time.Hour
+	}
+	if to.Sub(from) > maxSeriesPoints*step {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("range covers more than %d %ss", maxSeriesPoints, interval)})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), nowTimeout)
+	defer cancel()
+
+	response, err := h.Series(ctx, now, entity.NewPeriod(from, to), interval, timezone)
+	if err != nil {
+		log.Printf("Failed to serve /v1/series: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to calculate usage series"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Series builds the usage of the hours or days overlapping the period
+func (h *SeriesHandler) Series(ctx context.Context, now time.Time, period entity.Period, interval string, timezone *time.Location) (SeriesResponse, error) {
+	var usage entity.Usage
+	var err error
+	if interval == "hour" {
+		usage, err = h.usageQuery.ListByHourBetween(ctx, period, timezone)
+	} else {
+		usage, err = h.usageQuery.ListByDayBetween(ctx, period, timezone)
+	}
+	if err != nil {
+		return SeriesResponse{}, err
+	}
+
+	stats := usage.GetStats()
+	points := make([]SeriesPoint, len(stats))
+	for i, stat := range stats {
+		// Usage is listed newest first, charts expect the oldest point first
+		points[len(stats)-1-i] = newSeriesPoint(stat)
+	}
+
+	return SeriesResponse{
+		GeneratedAt: now.UTC(),
+		Timezone:    timezone.String(),
+		Interval:    interval,
+		From:        period.StartAt().UTC(),
+		To:          period.EndAt().UTC(),
+		Points:      points,
+	}, nil
+}
+
+// newSeriesPoint converts the stats of a period to the response representation
+func newSeriesPoint(stats entity.Stats) SeriesPoint {
+	return SeriesPoint{
+		Time:                stats.Period().StartAt().UTC(),
+		Requests:            stats.TotalRequests(),
+		BaseRequests:        stats.BaseRequests(),
+		PremiumRequests:     stats.PremiumRequests(),
+		LongContextRequests: stats.LongContextRequests(),
+		Tokens:              stats.TotalTokens().Total(),
+		BaseTokens:          stats.BaseTokens().Total(),
+		PremiumTokens:       stats.PremiumTokens().Total(),
+		LongContextTokens:   stats.LongContextTokens().Total(),
+		Cost:                stats.TotalCost().Amount(),
+		BaseCost:            stats.BaseCost().Amount(),
+		PremiumCost:         stats.PremiumCost().Amount(),
+		LongContextCost:     stats.LongContextCost().Amount(),
+	}
+}
+
+// parseSeriesTime parses an RFC 3339 time or Unix milliseconds
+func parseSeriesTime(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
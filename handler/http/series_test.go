@@ -0,0 +1,137 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	httpapi "github.com/elct9620/ccmon/handler/http"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestSeriesHandler_ServeHTTP(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	from := day.Format(time.RFC3339)
+	to := day.Add(2*24*time.Hour - time.Second).Format(time.RFC3339)
+
+	tests := []struct {
+		name           string
+		query          string
+		repoErr        error
+		expectedStatus int
+		expectedPoints int
+	}{
+		{
+			name:           "daily series",
+			query:          "?from=" + from + "&to=" + to,
+			expectedStatus: http.StatusOK,
+			expectedPoints: 2,
+		},
+		{
+			name:           "hourly series with Grafana milliseconds",
+			query:          "?interval=hour&from=1748736000000&to=1748746799000",
+			expectedStatus: http.StatusOK,
+			expectedPoints: 3,
+		},
+		{
+			name:           "invalid interval",
+			query:          "?interval=week",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid from",
+			query:          "?from=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "from after to",
+			query:          "?from=" + to + "&to=" + from,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many points",
+			query:          "?interval=hour&from=2020-01-01T00:00:00Z&to=2025-01-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid timezone",
+			query:          "?timezone=Mars/Olympus",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error",
+			query:          "?from=" + from + "&to=" + to,
+			repoErr:        &testutil.MockError{Message: "database error"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", day.Add(30*time.Minute), "claude-sonnet-4-20250514", 1000, 0, 1.50),
+				testutil.CreateTestAPIRequest("session-1", day.Add(40*time.Minute), "claude-3-5-haiku-20241022", 200, 0, 0.10),
+				testutil.CreateTestAPIRequest("session-2", day.Add(26*time.Hour), "claude-sonnet-4-20250514", 500, 0, 0.50),
+			})
+			if tt.repoErr != nil {
+				apiRepo.SetError(tt.repoErr)
+			}
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			usageQuery := usecase.NewGetUsageQueryWithStats(apiRepo, statsRepo, service.NewTimePeriodFactory(time.UTC))
+			handler := httpapi.NewHandlerWithOptions(httpapi.Handlers{
+				Now:    httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0),
+				Series: httpapi.NewSeriesHandler(usageQuery, time.UTC),
+			}, nil)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/series"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response httpapi.SeriesResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if len(response.Points) != tt.expectedPoints {
+				t.Fatalf("Expected %d points, got %d", tt.expectedPoints, len(response.Points))
+			}
+			first := response.Points[0]
+			if !first.Time.Equal(day) {
+				t.Errorf("Expected the oldest point first at %v, got %v", day, first.Time)
+			}
+			if first.Requests != 2 || first.BaseRequests != 1 || first.PremiumRequests != 1 {
+				t.Errorf("Expected 1 base and 1 premium request in the first point, got %+v", first)
+			}
+			if first.PremiumTokens != 1000 || first.BaseTokens != 200 || first.Tokens != 1200 {
+				t.Errorf("Expected tokens split by tier in the first point, got %+v", first)
+			}
+			if first.Cost != 1.60 || first.PremiumCost != 1.50 {
+				t.Errorf("Expected cost 1.60 with 1.50 premium in the first point, got %+v", first)
+			}
+		})
+	}
+}
+
+func TestNewHandler_WithoutSeries(t *testing.T) {
+	_, statsRepo := testutil.NewMockRepositoryPair()
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	handler := httpapi.NewHandler(httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0), nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/series", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
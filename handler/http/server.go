@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Handlers are the endpoints of the HTTP API, the optional ones are only served when not nil
+type Handlers struct {
+	Now    *NowHandler
+	Users  *UsersHandler  // optional
+	Series *SeriesHandler // optional
+}
+
+// NewHandler creates the HTTP API handler, allowing cross-origin requests from the given origins
+// An origin of "*" allows any origin, no origins disables CORS headers
+// The /v1/users endpoint is only served when usersHandler is not nil
+func NewHandler(nowHandler *NowHandler, usersHandler *UsersHandler, corsOrigins []string) http.Handler {
+	return NewHandlerWithOptions(Handlers{Now: nowHandler, Users: usersHandler}, corsOrigins)
+}
+
+// NewHandlerWithOptions creates the HTTP API handler serving the given endpoints
+func NewHandlerWithOptions(handlers Handlers, corsOrigins []string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /v1/now", handlers.Now)
+	if handlers.Users != nil {
+		mux.Handle("GET /v1/users", handlers.Users)
+	}
+	if handlers.Series != nil {
+		mux.Handle("GET /v1/series", handlers.Series)
+	}
+
+	return withCORS(mux, corsOrigins)
+}
+
+// withCORS adds CORS headers for allowed origins and answers preflight requests
+func withCORS(next http.Handler, origins []string) http.Handler {
+	allowAny := slices.Contains(origins, "*")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || (!allowAny && !slices.Contains(origins, origin)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Origin")
+		if allowAny {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,99 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpapi "github.com/elct9620/ccmon/handler/http"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestNewHandler_CORS(t *testing.T) {
+	tests := []struct {
+		name                string
+		origins             []string
+		method              string
+		origin              string
+		preflight           bool
+		expectedStatus      int
+		expectedAllowOrigin string
+	}{
+		{
+			name:           "same origin request without CORS",
+			origins:        nil,
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "origin not allowed",
+			origins:        []string{"vscode-webview://ccmon"},
+			method:         http.MethodGet,
+			origin:         "https://example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:                "allowed origin",
+			origins:             []string{"vscode-webview://ccmon"},
+			method:              http.MethodGet,
+			origin:              "vscode-webview://ccmon",
+			expectedStatus:      http.StatusOK,
+			expectedAllowOrigin: "vscode-webview://ccmon",
+		},
+		{
+			name:                "wildcard origin",
+			origins:             []string{"*"},
+			method:              http.MethodGet,
+			origin:              "https://example.com",
+			expectedStatus:      http.StatusOK,
+			expectedAllowOrigin: "*",
+		},
+		{
+			name:                "preflight request",
+			origins:             []string{"*"},
+			method:              http.MethodOptions,
+			origin:              "https://example.com",
+			preflight:           true,
+			expectedStatus:      http.StatusNoContent,
+			expectedAllowOrigin: "*",
+		},
+		{
+			name:           "unsupported method",
+			origins:        nil,
+			method:         http.MethodPost,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryPair()
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			handler := httpapi.NewHandler(httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0), nil, tt.origins)
+
+			req := httptest.NewRequest(tt.method, "/v1/now", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedAllowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.expectedAllowOrigin, got)
+			}
+			if tt.preflight && rec.Header().Get("Access-Control-Allow-Methods") == "" {
+				t.Error("Expected Access-Control-Allow-Methods on preflight")
+			}
+		})
+	}
+}
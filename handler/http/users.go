@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// UsersResponse is the JSON body of the /v1/users endpoint
+type UsersResponse struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	Timezone    string      `json:"timezone"`
+	Users       []UserUsage `json:"users"`
+	Block       *UsersBlock `json:"block"` // null unless the block query parameter is given
+}
+
+// UsersBlock is the block the usage of each user covers
+type UsersBlock struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// UserUsage is the usage of a single user in the current day and block
+type UserUsage struct {
+	User        string  `json:"user"` // empty for requests without user attribution
+	DailyCost   float64 `json:"daily_cost"`
+	DailyTokens int64   `json:"daily_tokens"`
+	BlockCost   float64 `json:"block_cost"`
+	BlockTokens int64   `json:"block_tokens"` // rate limited tokens
+	Quota       float64 `json:"quota"`        // 0 when the user is unlimited
+	Status      string  `json:"status"`       // "unlimited", "ok" or "exceeded"
+}
+
+// UsersHandler serves the usage of each user against their daily quota, so a team lead can watch individual spending
+type UsersHandler struct {
+	userUsageQuery *usecase.GetUserUsageQuery
+	timezone       *time.Location
+	blockDuration  time.Duration
+}
+
+// NewUsersHandler creates a new UsersHandler, timezone is used when the request does not give one
+func NewUsersHandler(userUsageQuery *usecase.GetUserUsageQuery, timezone *time.Location) *UsersHandler {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return &UsersHandler{
+		userUsageQuery: userUsageQuery,
+		timezone:       timezone,
+	}
+}
+
+// SetBlockDuration sets the length of the blocks given by the block parameter, 5 hours when not set
+func (h *UsersHandler) SetBlockDuration(duration time.Duration) {
+	h.blockDuration = duration
+}
+
+// ServeHTTP implements http.Handler
+// Supported query parameters are block (e.g. "5am") and timezone (e.g. "Asia/Taipei")
+func (h *UsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	timezone := h.timezone
+	if name := r.URL.Query().Get("timezone"); name != "" {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid timezone %q", name)})
+			return
+		}
+		timezone = location
+	}
+
+	now := time.Now()
+
+	var block *entity.Block
+	if blockTime := r.URL.Query().Get("block"); blockTime != "" {
+		startHour, err := entity.ParseBlockStartHour(blockTime)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid block %q: %v", blockTime, err)})
+			return
+		}
+		currentBlock := entity.NewCurrentBlockWithDuration(startHour, timezone, now, 0, h.blockDuration)
+		block = &currentBlock
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), nowTimeout)
+	defer cancel()
+
+	response, err := h.Users(ctx, now, timezone, block)
+	if err != nil {
+		log.Printf("Failed to serve /v1/users: %v", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to calculate user usage"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// Users builds the usage of each user at the point in time, block is optional
+func (h *UsersHandler) Users(ctx context.Context, now time.Time, timezone *time.Location, block *entity.Block) (UsersResponse, error) {
+	params := usecase.GetUserUsageParams{
+		Daily: entity.NewDayPeriod(now, timezone),
+	}
+
+	response := UsersResponse{
+		GeneratedAt: now.UTC(),
+		Timezone:    timezone.String(),
+	}
+
+	if block != nil {
+		blockPeriod := block.Period()
+		params.Block = &blockPeriod
+		response.Block = &UsersBlock{StartAt: block.StartAt().UTC(), EndAt: block.EndAt().UTC()}
+	}
+
+	usages, err := h.userUsageQuery.Execute(ctx, params)
+	if err != nil {
+		return UsersResponse{}, err
+	}
+
+	response.Users = make([]UserUsage, len(usages))
+	for i, usage := range usages {
+		response.Users[i] = UserUsage{
+			User:        usage.User(),
+			DailyCost:   usage.Daily().TotalCost().Amount(),
+			DailyTokens: usage.Daily().TotalTokens().Total(),
+			BlockCost:   usage.Block().TotalCost().Amount(),
+			BlockTokens: usage.Block().RateLimitedTokens().Limited(),
+			Quota:       usage.Quota().Amount(),
+			Status:      string(usage.Status()),
+		}
+	}
+
+	return response, nil
+}
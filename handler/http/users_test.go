@@ -0,0 +1,128 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	httpapi "github.com/elct9620/ccmon/handler/http"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestUsersHandler_ServeHTTP(t *testing.T) {
+	// Start the block at the current hour so the requests fall into it
+	blockTime := strings.ToLower(time.Now().UTC().Format("3pm"))
+
+	tests := []struct {
+		name           string
+		query          string
+		repoErr        error
+		expectedStatus int
+		expectBlock    bool
+	}{
+		{
+			name:           "daily usage without block",
+			query:          "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "daily and block usage",
+			query:          "?block=" + blockTime,
+			expectedStatus: http.StatusOK,
+			expectBlock:    true,
+		},
+		{
+			name:           "invalid timezone",
+			query:          "?timezone=Mars/Olympus",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid block",
+			query:          "?block=25",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repository error",
+			query:          "",
+			repoErr:        &testutil.MockError{Message: "database error"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+				testutil.CreateTestAPIRequest("session-1", time.Now(), "claude-sonnet-4-20250514", 1000, 0, 1.50).WithUser("alice@example.com"),
+				testutil.CreateTestAPIRequest("session-2", time.Now(), "claude-sonnet-4-20250514", 500, 0, 0.50).WithUser("bob@example.com"),
+			})
+			if tt.repoErr != nil {
+				apiRepo.SetError(tt.repoErr)
+			}
+			quotas := entity.NewUserQuotas(entity.NewCost(1), nil)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+			handler := httpapi.NewHandler(
+				httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0),
+				httpapi.NewUsersHandler(usecase.NewGetUserUsageQuery(apiRepo, quotas), time.UTC),
+				nil,
+			)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users"+tt.query, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response httpapi.UsersResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if len(response.Users) != 2 {
+				t.Fatalf("Expected 2 users, got %d", len(response.Users))
+			}
+			alice, bob := response.Users[0], response.Users[1]
+			if alice.User != "alice@example.com" || alice.DailyCost != 1.50 || alice.Status != "exceeded" {
+				t.Errorf("Expected alice to exceed the quota with 1.50, got %+v", alice)
+			}
+			if bob.User != "bob@example.com" || bob.DailyCost != 0.50 || bob.Status != "ok" {
+				t.Errorf("Expected bob within the quota with 0.50, got %+v", bob)
+			}
+
+			if !tt.expectBlock {
+				if response.Block != nil || alice.BlockTokens != 0 {
+					t.Errorf("Expected no block usage, got %+v and %d tokens", response.Block, alice.BlockTokens)
+				}
+				return
+			}
+			if response.Block == nil {
+				t.Fatal("Expected block, got nil")
+			}
+			if alice.BlockTokens != 1000 {
+				t.Errorf("Expected alice block tokens 1000, got %d", alice.BlockTokens)
+			}
+		})
+	}
+}
+
+func TestNewHandler_WithoutUsers(t *testing.T) {
+	_, statsRepo := testutil.NewMockRepositoryPair()
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	handler := httpapi.NewHandler(httpapi.NewNowHandler(calculateStatsQuery, time.UTC, 0), nil, nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
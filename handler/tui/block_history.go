@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// BlockHistoryModel lists the usage of past blocks against the token limit, so earlier blocks can be reviewed
+type BlockHistoryModel struct {
+	blocks []usecase.BlockUsage // newest first, the current block is the first
+	loaded bool
+	err    error
+	cursor int
+
+	timezone   *time.Location
+	costFormat entity.CostFormat
+	width      int
+	height     int
+}
+
+// NewBlockHistoryModel creates a new block history without blocks
+func NewBlockHistoryModel(timezone *time.Location) *BlockHistoryModel {
+	return &BlockHistoryModel{
+		timezone: timezone,
+	}
+}
+
+// Init initializes the block history
+func (m *BlockHistoryModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update keeps the latest blocks and handles the navigation keys, the last known blocks are kept when the lookup fails
+func (m *BlockHistoryModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	case BlockHistoryMsg:
+		m.err = msg.Err
+		if msg.Err == nil {
+			m.blocks = msg.Blocks
+			m.loaded = true
+			m.cursor = min(m.cursor, max(len(m.blocks)-1, 0))
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.blocks)-1 {
+				m.cursor++
+			}
+		}
+	}
+	return m, nil
+}
+
+// View renders the premium tokens, percent of the token limit and cost of each block, newest first
+func (m *BlockHistoryModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Block History") + "\n\n")
+
+	if m.err != nil {
+		b.WriteString(WarningStyle.Render("  Failed to load block history: "+entity.ErrorMessage(m.err)) + "\n\n")
+	}
+	if !m.loaded {
+		b.WriteString(HelpStyle.Render("  Loading block history...") + "\n")
+		return b.String()
+	}
+
+	b.WriteString(TableHeaderStyle.Render(fmt.Sprintf("  %-12s %-15s %14s %10s %10s", "Date", "Block", "Premium Tokens", "Limit", "Cost")) + "\n")
+	visible, start := m.visibleBlocks()
+	for i, usage := range visible {
+		marker := "  "
+		if start+i == m.cursor {
+			marker = "▶ "
+		}
+
+		label := FormatBlockTime(usage.Block, m.timezone)
+		if start+i == 0 {
+			label += " *"
+		}
+		line := fmt.Sprintf("%s%-12s %-15s %14s %10s %10s", marker, FormatDate(usage.Block.StartAt().In(m.timezone)), label,
+			FormatTokenCount(usage.Tokens.Limited()), formatBlockProgress(usage), formatCost(m.costFormat, usage.Cost.Amount()))
+		if usage.Block.IsLimitExceeded(usage.Tokens) {
+			b.WriteString(WarningStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(StatusStyle.Render(line) + "\n")
+		}
+	}
+
+	b.WriteString("\n" + HelpStyle.Render("  * current block, blocks over the token limit are highlighted") + "\n")
+	return b.String()
+}
+
+// formatBlockProgress formats the percent of the token limit used, "-" without a limit
+func formatBlockProgress(usage usecase.BlockUsage) string {
+	if !usage.Block.HasLimit() {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", usage.Block.CalculateProgress(usage.Tokens))
+}
+
+// visibleBlocks returns the blocks fitting the height and the index of the first one, the cursor is kept visible
+func (m *BlockHistoryModel) visibleBlocks() ([]usecase.BlockUsage, int) {
+	// Title, tabs, panel header, table header, legend, help and footers take about 13 lines
+	rows := m.height - 13
+	if rows <= 0 || rows >= len(m.blocks) {
+		return m.blocks, 0
+	}
+
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+	return m.blocks[start : start+rows], start
+}
+
+// SetCostFormat sets the display format of the block costs
+func (m *BlockHistoryModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+}
+
+// SetSize updates the size of the block history
+func (m *BlockHistoryModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Blocks returns the last known blocks, newest first
+func (m *BlockHistoryModel) Blocks() []usecase.BlockUsage {
+	return m.blocks
+}
+
+// Cursor returns the index of the selected block
+func (m *BlockHistoryModel) Cursor() int {
+	return m.cursor
+}
+
+// BlockHistoryMsg carries the usage of the current and past blocks for the block history
+type BlockHistoryMsg struct {
+	Blocks []usecase.BlockUsage
+	Err    error
+}
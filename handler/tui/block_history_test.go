@@ -0,0 +1,144 @@
+package tui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestBlockHistory_View(t *testing.T) {
+	t.Parallel()
+
+	current := entity.NewBlockWithLimit(time.Date(2025, 6, 2, 5, 0, 0, 0, time.UTC), 1000)
+	blocks := []usecase.BlockUsage{
+		{Block: current, Tokens: entity.NewToken(300, 100, 0, 0), Cost: entity.NewCost(1.5)},
+		{Block: current.PreviousBlock(), Tokens: entity.NewToken(1000, 500, 0, 0), Cost: entity.NewCost(6)},
+	}
+
+	tests := []struct {
+		name     string
+		msgs     []tea.Msg
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "loading",
+			contains: []string{"Loading block history..."},
+		},
+		{
+			name: "blocks of the limit",
+			msgs: []tea.Msg{tui.BlockHistoryMsg{Blocks: blocks}},
+			contains: []string{
+				"2025-06-02   5am - 10am *",
+				"2025-06-02   12am - 5am",
+				"400",
+				"40.0%",
+				"1.5K",
+				"150.0%",
+				"current block",
+			},
+			excludes: []string{"Failed to load"},
+		},
+		{
+			name: "blocks without a limit",
+			msgs: []tea.Msg{tui.BlockHistoryMsg{Blocks: []usecase.BlockUsage{
+				{Block: entity.NewBlock(current.StartAt()), Tokens: entity.NewToken(300, 100, 0, 0)},
+			}}},
+			contains: []string{"400"},
+			excludes: []string{"%"},
+		},
+		{
+			name: "keeps the last blocks when the lookup fails",
+			msgs: []tea.Msg{
+				tui.BlockHistoryMsg{Blocks: blocks},
+				tui.BlockHistoryMsg{Err: errors.New("connection refused")},
+			},
+			contains: []string{"Failed to load block history: connection refused", "40.0%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			history := tui.NewBlockHistoryModel(time.UTC)
+			for _, msg := range tt.msgs {
+				history.Update(msg)
+			}
+
+			view := history.View()
+			for _, want := range tt.contains {
+				if !strings.Contains(view, want) {
+					t.Errorf("Expected view to contain %q, got %q", want, view)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(view, unwanted) {
+					t.Errorf("Expected view not to contain %q, got %q", unwanted, view)
+				}
+			}
+		})
+	}
+}
+
+func TestViewModel_BlockHistory(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Without a block the key is not bound
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	if vm.ShowBlockHistory() {
+		t.Fatal("Expected the block history to be disabled without a block")
+	}
+	if strings.Contains(vm.View(), "B=blocks") {
+		t.Error("Expected no block history help without a block")
+	}
+
+	block := entity.NewBlockWithLimit(time.Now().Truncate(time.Hour), 1000)
+	vm.SetBlockHistoryQuery(usecase.NewGetBlockHistoryQuery(CreateTestUsageQuery(), block))
+	if !strings.Contains(vm.View(), "B=blocks") {
+		t.Error("Expected the block history help")
+	}
+
+	_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	if !vm.ShowBlockHistory() {
+		t.Fatal("Expected the block history to open")
+	}
+	if cmd == nil {
+		t.Fatal("Expected opening the block history to list the blocks")
+	}
+	vm.Update(cmd())
+
+	if blocks := vm.BlockHistory().Blocks(); len(blocks) != usecase.DefaultBlockHistoryBlocks || !blocks[0].Block.StartAt().Equal(block.StartAt()) {
+		t.Fatalf("Expected %d blocks starting with the current block, got %d", usecase.DefaultBlockHistoryBlocks, len(blocks))
+	}
+	if view := vm.View(); !strings.Contains(view, "Block History") || !strings.Contains(view, "B/esc: Close") {
+		t.Errorf("Expected the block history over the tab, got %q", view)
+	}
+
+	// Keys are handled by the block history while it is open
+	vm.Update(tea.KeyMsg{Type: tea.KeyDown})
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if vm.BlockHistory().Cursor() != 1 {
+		t.Errorf("Expected the cursor on the previous block, got %d", vm.BlockHistory().Cursor())
+	}
+	if !vm.ShowBlockHistory() {
+		t.Fatal("Expected the block history to stay open on unrelated keys")
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if vm.ShowBlockHistory() {
+		t.Error("Expected esc to close the block history")
+	}
+}
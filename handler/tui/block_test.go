@@ -181,7 +181,7 @@ func TestCalculateCurrentBlock(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			block := calculateCurrentBlock(tt.startHour, loc, tt.now, tt.tokenLimit)
+			block := calculateCurrentBlock(tt.startHour, loc, tt.now, tt.tokenLimit, 5*time.Hour)
 
 			if !block.StartAt().Equal(tt.wantStart) {
 				t.Errorf("calculateCurrentBlock() start = %v, want %v", block.StartAt(), tt.wantStart)
@@ -235,7 +235,7 @@ func TestCalculateCurrentBlock_TimezoneHandling(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			block := calculateCurrentBlock(tt.startHour, tt.timezone, tt.nowUTC, 7000)
+			block := calculateCurrentBlock(tt.startHour, tt.timezone, tt.nowUTC, 7000, 5*time.Hour)
 
 			// Convert block start time to the test timezone for verification
 			blockStartInTz := block.StartAt().In(tt.timezone)
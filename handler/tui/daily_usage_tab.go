@@ -15,8 +15,9 @@ import (
 // DailyUsageTabModel handles the daily usage tab that shows usage statistics over time and owns its data
 type DailyUsageTabModel struct {
 	// Data ownership
-	usage entity.Usage
-	table table.Model
+	usage  entity.Usage
+	table  table.Model
+	styles table.Styles
 
 	// Configuration
 	timezone *time.Location
@@ -26,15 +27,53 @@ type DailyUsageTabModel struct {
 	// Display mode configuration
 	displayMode DailyDisplayMode
 
+	// Date range navigation: number of days the window is shifted back from today
+	windowOffset int
+
+	// Granularity of the listed periods, the days of the window by default
+	granularity UsageGranularity
+
+	// Days costing more than the multiple of their 7-day baseline are marked
+	anomaly entity.CostAnomalyPolicy
+
+	// Display format of cost amounts
+	costFormat entity.CostFormat
+
 	// Business logic dependencies
 	getUsageQuery *usecase.GetUsageQuery
 }
 
+// dailyUsageWindowDays is the number of days shown per page in the daily usage tab
+const dailyUsageWindowDays = 30
+
+// hourlyUsageWindowHours is the number of hours shown in the hourly granularity of the daily usage tab
+const hourlyUsageWindowHours = 48
+
+// weeklyUsageWindowWeeks is the number of calendar weeks shown in the weekly granularity of the daily usage tab
+const weeklyUsageWindowWeeks = 12
+
+// monthlyUsageWindowMonths is the number of calendar months shown in the monthly granularity of the daily usage tab
+const monthlyUsageWindowMonths = 12
+
+// UsageGranularity is the length of the periods listed in the daily usage tab
+type UsageGranularity int
+
+const (
+	// GranularityDaily lists the days of a 30-day window with moving averages
+	GranularityDaily UsageGranularity = iota
+	// GranularityHourly lists the last hours, toggled with "g"
+	GranularityHourly
+	// GranularityWeekly lists calendar weeks starting on Monday with their change from the week before, cycled with "c"
+	GranularityWeekly
+	// GranularityMonthly lists calendar months with their change from the month before, cycled with "c"
+	GranularityMonthly
+)
+
 // DailyDisplayMode defines the table display mode based on available width
 type DailyDisplayMode int
 
 const (
-	// FullMode shows all 9 columns with complete token breakdown
+	// FullMode shows all 11 columns with complete token breakdown and moving averages
 	FullMode DailyDisplayMode = iota
 	// GroupedMode shows 4 main columns with grouped token details
 	GroupedMode
@@ -54,7 +93,10 @@ func NewDailyUsageTabModel(getUsageQuery *usecase.GetUsageQuery, timezone *time.
 		{Title: "Creation Cache", Width: 12},
 		{Title: "Total", Width: 8},
 		{Title: "Premium Cost ($)", Width: 16},
+		{Title: "$/1K Tok", Width: 9},
 		{Title: "Burn Rate", Width: 10},
+		{Title: "7d Avg", Width: 9},
+		{Title: "30d Avg", Width: 9},
 	}
 
 	t := table.New(
@@ -72,6 +114,7 @@ func NewDailyUsageTabModel(getUsageQuery *usecase.GetUsageQuery, timezone *time.
 	return &DailyUsageTabModel{
 		usage:         entity.Usage{},
 		table:         t,
+		styles:        s,
 		timezone:      timezone,
 		width:         120,
 		height:        30,
@@ -98,8 +141,50 @@ func (m *DailyUsageTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 		m.usage = msg.Usage
 		m.updateTableRows()
 	case tea.KeyMsg:
-		// Handle table navigation
-		m.table, cmd = m.table.Update(msg)
+		switch msg.String() {
+		case "g":
+			// Switch between daily and hourly granularity
+			if m.granularity == GranularityHourly {
+				m.granularity = GranularityDaily
+			} else {
+				m.granularity = GranularityHourly
+			}
+			m.resizeTableColumns()
+			return m, m.refreshUsage()
+		case "c":
+			// Cycle through the calendar granularities: days, weeks and months
+			switch m.granularity {
+			case GranularityWeekly:
+				m.granularity = GranularityMonthly
+			case GranularityMonthly:
+				m.granularity = GranularityDaily
+			default:
+				m.granularity = GranularityWeekly
+			}
+			m.resizeTableColumns()
+			return m, m.refreshUsage()
+		case "left":
+			// Page backward to the previous window, hours, weeks and months always end now
+			if m.granularity == GranularityDaily {
+				m.windowOffset += dailyUsageWindowDays
+				return m, m.refreshUsage()
+			}
+		case "right":
+			// Page forward, stopping at the window ending today
+			if m.granularity == GranularityDaily && m.windowOffset > 0 {
+				m.windowOffset -= dailyUsageWindowDays
+				return m, m.refreshUsage()
+			}
+		default:
+			// Handle table navigation
+			m.table, cmd = m.table.Update(msg)
+		}
+	case tea.MouseMsg:
+		// The Y of the message is the line of the tab view, the table starts below the headers and the box border
+		if len(m.usage.GetStats()) > 0 {
+			msg.Y -= m.tableTop()
+			updateTableMouse(&m.table, m.styles, msg)
+		}
 	}
 	return m, cmd
 }
@@ -107,9 +192,44 @@ func (m *DailyUsageTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 // View renders the daily usage tab
 func (m *DailyUsageTabModel) View() string {
 	var b strings.Builder
+	b.WriteString(m.headerView())
+
+	// Check if we have data
+	if len(m.usage.GetStats()) == 0 {
+		emptyContent := HelpStyle.Render("No usage data available")
+		dailyBox := BoxStyle.Width(m.width - 4).Render(emptyContent)
+		b.WriteString(dailyBox + "\n")
+		return b.String()
+	}
+
+	// Daily usage table - now using table.Model
+	dailyBox := BoxStyle.Width(m.width - 4).Render(renderHighlightMarkers(m.table.View(), m.styles))
+	b.WriteString(dailyBox + "\n")
+
+	return b.String()
+}
+
+// tableTop returns the line of the tab view where the table starts
+func (m *DailyUsageTabModel) tableTop() int {
+	return strings.Count(m.headerView(), "\n") + BoxStyle.GetBorderTopSize() + BoxStyle.GetPaddingTop()
+}
+
+// headerView renders the headers and the trend line above the usage box
+func (m *DailyUsageTabModel) headerView() string {
+	var b strings.Builder
 
 	// Daily usage header
-	dailyHeader := HeaderStyle.Render("Daily Usage Statistics (Last 30 Days)")
+	var dailyHeader string
+	switch m.granularity {
+	case GranularityHourly:
+		dailyHeader = HeaderStyle.Render(fmt.Sprintf("Hourly Usage Statistics (Last %d Hours)", hourlyUsageWindowHours))
+	case GranularityWeekly:
+		dailyHeader = HeaderStyle.Render(fmt.Sprintf("Weekly Usage Statistics (Last %d Weeks)", weeklyUsageWindowWeeks))
+	case GranularityMonthly:
+		dailyHeader = HeaderStyle.Render(fmt.Sprintf("Monthly Usage Statistics (Last %d Months)", monthlyUsageWindowMonths))
+	default:
+		dailyHeader = HeaderStyle.Render(fmt.Sprintf("Daily Usage Statistics (%s)", m.windowLabel()))
+	}
 	b.WriteString(dailyHeader + "\n")
 
 	// Subtitle explaining premium token focus
@@ -117,24 +237,65 @@ func (m *DailyUsageTabModel) View() string {
 	b.WriteString(subtitle + "\n")
 
 	// Legend explaining column meanings
-	legend := HelpStyle.Render("Requests: Base/Premium • Tokens: Premium only (Sonnet/Opus)")
+	legend := HelpStyle.Render("Requests: Base/Premium • Tokens: Premium only (Sonnet/Opus, 1M context included)")
 	b.WriteString(legend + "\n\n")
 
-	// Check if we have data
-	if len(m.usage.GetStats()) == 0 {
-		emptyContent := HelpStyle.Render("No usage data available")
-		dailyBox := BoxStyle.Width(m.width - 4).Render(emptyContent)
-		b.WriteString(dailyBox + "\n")
-		return b.String()
+	// Premium cost trend, oldest to newest, so spiky days read as a trend
+	if len(m.usage.GetStats()) > 0 {
+		if trend := m.trendLine(); trend != "" {
+			b.WriteString(HelpStyle.Render(trend) + "\n")
+		}
 	}
 
-	// Daily usage table - now using table.Model
-	dailyBox := BoxStyle.Width(m.width - 4).Render(m.table.View())
-	b.WriteString(dailyBox + "\n")
-
 	return b.String()
 }
 
+// trendLine renders the 7-day moving average sparkline and the latest averages of the window
+// Weeks and months render their premium and 1M context cost sparkline and the change of the current period instead
+func (m *DailyUsageTabModel) trendLine() string {
+	if m.isCalendar() {
+		return m.calendarTrendLine()
+	}
+
+	stats := m.usage.GetStats()
+	values := make([]float64, 0, len(stats))
+	for i := len(stats) - 1; i >= 0; i-- {
+		average, ok := m.usage.MovingAverageAt(i)
+		if !ok {
+			return ""
+		}
+		values = append(values, average.Short().Amount())
+	}
+
+	latest, _ := m.usage.MovingAverageAt(0)
+	trend := fmt.Sprintf("7d Avg Trend: %s • 7d Avg: $%s/day • 30d Avg: $%s/day",
+		FormatSparkline(values), formatCostAmount(m.costFormat, latest.Short().Amount()), formatCostAmount(m.costFormat, latest.Long().Amount()))
+	if m.anomaly.IsEnabled() {
+		trend += fmt.Sprintf(" • %s over %gx the prior 7d avg", highlightMarker, m.anomaly.Multiple())
+	}
+	return trend
+}
+
+// calendarTrendLine renders the premium and 1M context cost sparkline of the weeks or months and the change of the current one
+func (m *DailyUsageTabModel) calendarTrendLine() string {
+	stats := m.usage.GetStats()
+	values := make([]float64, 0, len(stats))
+	for i := len(stats) - 1; i >= 0; i-- {
+		values = append(values, stats[i].RateLimitedCost().Amount())
+	}
+
+	unit := "week"
+	if m.granularity == GranularityMonthly {
+		unit = "month"
+	}
+	trend := fmt.Sprintf("Cost Trend: %s • This %s: $%s", FormatSparkline(values), unit, formatCostAmount(m.costFormat, stats[0].RateLimitedCost().Amount()))
+	if change, ok := m.usage.ChangeAt(0); ok {
+		delta, percent := FormatCostChange(change, m.costFormat)
+		trend += fmt.Sprintf(" (%s, %s vs last %s)", delta, percent, unit)
+	}
+	return trend
+}
+
 // SetSize updates the size of the daily usage tab
 func (m *DailyUsageTabModel) SetSize(width, height int) {
 	m.width = width
@@ -143,6 +304,18 @@ func (m *DailyUsageTabModel) SetSize(width, height int) {
 	m.adjustTableHeight()
 }
 
+// SetCostFormat sets the display format of cost amounts
+func (m *DailyUsageTabModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+	m.updateTableRows()
+}
+
+// SetCostAnomalyPolicy marks the cost of days above the multiple of their 7-day baseline
+func (m *DailyUsageTabModel) SetCostAnomalyPolicy(policy entity.CostAnomalyPolicy) {
+	m.anomaly = policy
+	m.updateTableRows()
+}
+
 // UpdateUsage updates the usage data
 func (m *DailyUsageTabModel) UpdateUsage(usage entity.Usage) {
 	m.usage = usage
@@ -153,17 +326,17 @@ func (m *DailyUsageTabModel) UpdateUsage(usage entity.Usage) {
 func (m *DailyUsageTabModel) calculateDailyTableWidths(availableWidth int) []int {
 	// Account for table internal spacing - Bubble Tea table adds padding/borders
 	// Estimate ~2-3 chars per column for internal spacing/borders
-	tableOverhead := 9 * 3 // 9 columns * 3 chars overhead each
+	tableOverhead := 12 * 3 // 12 columns * 3 chars overhead each
 	usableWidth := availableWidth - tableOverhead
 
 	// Ensure we have minimum usable width for full mode
-	if usableWidth < 100 {
-		usableWidth = 100
+	if usableWidth < 115 {
+		usableWidth = 115
 	}
 
 	// Calculate proportional widths with better distribution
-	// Base widths: Date: 10, Requests: 10, Input: 8, Output: 8, Read Cache: 9, Creation Cache: 11, Total: 8, Burn Rate: 10, Premium Cost: remaining
-	baseWidths := []int{10, 10, 8, 8, 9, 11, 8, 10, 14} // Total base: 88
+	// Base widths: Date: 10, Requests: 10, Input: 8, Output: 8, Read Cache: 9, Creation Cache: 11, Total: 8, Burn Rate: 10, Premium Cost: 14, $/1K Tok: 9, 7d Avg: 9, 30d Avg: 9
+	baseWidths := []int{10, 10, 8, 8, 9, 11, 8, 10, 14, 9, 9, 9} // Total base: 115
 	totalBaseWidth := 0
 	for _, w := range baseWidths {
 		totalBaseWidth += w
@@ -172,8 +345,8 @@ func (m *DailyUsageTabModel) calculateDailyTableWidths(availableWidth int) []int
 	// If we have extra space, distribute it proportionally
 	if usableWidth > totalBaseWidth {
 		extraSpace := usableWidth - totalBaseWidth
-		// Distribute extra space: favor Premium Cost (40%), Date (15%), Burn Rate (15%), others get smaller amounts
-		distribution := []float64{0.15, 0.08, 0.06, 0.06, 0.06, 0.08, 0.06, 0.15, 0.30}
+		// Distribute extra space: favor Premium Cost (18%), Date (12%), Burn Rate (12%), moving averages (9% each), others get smaller amounts
+		distribution := []float64{0.12, 0.06, 0.05, 0.05, 0.05, 0.06, 0.05, 0.12, 0.18, 0.08, 0.09, 0.09}
 
 		for i := range baseWidths {
 			extra := int(float64(extraSpace) * distribution[i])
@@ -243,8 +416,19 @@ func (m *DailyUsageTabModel) refreshUsage() tea.Cmd {
 			return UsageDataMsg{Usage: entity.Usage{}}
 		}
 
-		// Fetch daily usage statistics (last 30 days)
-		usage, err := m.getUsageQuery.ListByDay(context.Background(), 30, m.timezone)
+		// Fetch the usage statistics of the granularity, days are read for the current 30-day window
+		var usage entity.Usage
+		var err error
+		switch m.granularity {
+		case GranularityHourly:
+			usage, err = m.getUsageQuery.ListByHour(context.Background(), hourlyUsageWindowHours, m.timezone)
+		case GranularityWeekly:
+			usage, err = m.getUsageQuery.ListByWeek(context.Background(), weeklyUsageWindowWeeks, m.timezone)
+		case GranularityMonthly:
+			usage, err = m.getUsageQuery.ListByMonth(context.Background(), monthlyUsageWindowMonths, m.timezone)
+		default:
+			usage, err = m.getUsageQuery.ListByDayRange(context.Background(), m.windowOffset, dailyUsageWindowDays, m.timezone)
+		}
 		if err != nil {
 			usage = entity.Usage{}
 		}
@@ -253,11 +437,48 @@ func (m *DailyUsageTabModel) refreshUsage() tea.Cmd {
 	})
 }
 
+// windowLabel describes the date range of the current window
+func (m *DailyUsageTabModel) windowLabel() string {
+	if m.windowOffset == 0 {
+		return fmt.Sprintf("Last %d Days", dailyUsageWindowDays)
+	}
+
+	today := time.Now().In(m.timezone)
+	newest := today.AddDate(0, 0, -m.windowOffset)
+	oldest := newest.AddDate(0, 0, -(dailyUsageWindowDays - 1))
+	return fmt.Sprintf("%s to %s", FormatDate(oldest), FormatDate(newest))
+}
+
+// Hourly returns true if the tab shows the usage of the last hours instead of days
+func (m *DailyUsageTabModel) Hourly() bool {
+	return m.granularity == GranularityHourly
+}
+
+// Granularity returns the length of the listed periods
+func (m *DailyUsageTabModel) Granularity() UsageGranularity {
+	return m.granularity
+}
+
+// isCalendar returns true if the tab lists weeks or months, which compare each period with the one before
+func (m *DailyUsageTabModel) isCalendar() bool {
+	return m.granularity == GranularityWeekly || m.granularity == GranularityMonthly
+}
+
+// WindowOffset returns the number of days the current window is shifted back from today
+func (m *DailyUsageTabModel) WindowOffset() int {
+	return m.windowOffset
+}
+
 // Usage returns the current usage (for compatibility)
 func (m *DailyUsageTabModel) Usage() entity.Usage {
 	return m.usage
 }
 
+// GetTable returns the underlying table model for integration with other components
+func (m *DailyUsageTabModel) GetTable() table.Model {
+	return m.table
+}
+
 // Focus sets focus on the table
 func (m *DailyUsageTabModel) Focus() {
 	m.table.Focus()
@@ -310,6 +531,51 @@ func max(a, b int) int {
 	return b
 }
 
+// dateColumnWidth returns the width of a date in the configured date format, e.g. 10 for "2006-01-02"
+func dateColumnWidth() int {
+	// Every date pattern token has a fixed width, so any date gives the width
+	return len(FormatDate(time.Date(2025, 9, 24, 0, 0, 0, 0, time.UTC)))
+}
+
+// labelColumn returns the title and width of the first column, the date of each day or the date and time of each hour
+// Weeks are labeled by the date of their Monday and months by their name
+func (m *DailyUsageTabModel) labelColumn() (string, int) {
+	switch m.granularity {
+	case GranularityHourly:
+		return "Hour", len(FormatDateShortTime(time.Date(2025, 9, 24, 0, 0, 0, 0, time.UTC)))
+	case GranularityWeekly:
+		return "Week", dateColumnWidth()
+	case GranularityMonthly:
+		return "Month", len(formatMonth(time.Date(2025, 9, 24, 0, 0, 0, 0, time.UTC)))
+	}
+	return "Date", dateColumnWidth()
+}
+
+// periodLabel returns the first column of a period starting at start in the granularity of the tab
+func (m *DailyUsageTabModel) periodLabel(start time.Time) string {
+	local := start.In(m.timezone)
+	switch m.granularity {
+	case GranularityHourly:
+		return FormatDateShortTime(local)
+	case GranularityMonthly:
+		return formatMonth(local)
+	}
+	return FormatDate(local)
+}
+
+// formatMonth formats the month of t, e.g. "Sep 2025"
+func formatMonth(t time.Time) string {
+	return t.Format("Jan 2006")
+}
+
+// comparisonColumns returns the titles of the last two columns, moving averages of days or the change of weeks and months
+func (m *DailyUsageTabModel) comparisonColumns() (string, string) {
+	if m.isCalendar() {
+		return "Change", "Change %"
+	}
+	return "7d Avg", "30d Avg"
+}
+
 // resizeTableColumns resizes table columns based on available width
 func (m *DailyUsageTabModel) resizeTableColumns() {
 	// Calculate available width for table (accounting for box padding)
@@ -318,13 +584,16 @@ func (m *DailyUsageTabModel) resizeTableColumns() {
 	// Determine display mode based on available width
 	var newDisplayMode DailyDisplayMode
 	var columns []table.Column
+	labelTitle, labelWidth := m.labelColumn()
+	shortTitle, longTitle := m.comparisonColumns()
 
-	if availableWidth >= 140 {
-		// Full mode: traditional 9-column layout
+	if availableWidth >= 150 {
+		// Full mode: 12-column layout with cost per 1K tokens and moving averages
 		newDisplayMode = FullMode
 		colWidths := m.calculateDailyTableWidths(availableWidth)
+		colWidths[0] = max(colWidths[0], labelWidth)
 		columns = []table.Column{
-			{Title: "Date", Width: colWidths[0]},
+			{Title: labelTitle, Width: colWidths[0]},
 			{Title: "Requests", Width: colWidths[1]},
 			{Title: "Input", Width: colWidths[2]},
 			{Title: "Output", Width: colWidths[3]},
@@ -333,13 +602,17 @@ func (m *DailyUsageTabModel) resizeTableColumns() {
 			{Title: "Total", Width: colWidths[6]},
 			{Title: "Burn Rate", Width: colWidths[7]},
 			{Title: "Premium Cost ($)", Width: colWidths[8]},
+			{Title: "$/1K Tok", Width: colWidths[9]},
+			{Title: shortTitle, Width: colWidths[10]},
+			{Title: longTitle, Width: colWidths[11]},
 		}
 	} else if availableWidth >= 80 {
 		// Grouped mode: 4 main columns with token details in sub-rows
 		newDisplayMode = GroupedMode
 		colWidths := m.calculateGroupedTableWidths(availableWidth)
+		colWidths[0] = max(colWidths[0], labelWidth)
 		columns = []table.Column{
-			{Title: "Date", Width: colWidths[0]},
+			{Title: labelTitle, Width: colWidths[0]},
 			{Title: "B/P Reqs", Width: colWidths[1]},
 			{Title: "Burn Rate", Width: colWidths[2]},
 			{Title: "Cost ($)", Width: colWidths[3]},
@@ -348,7 +621,7 @@ func (m *DailyUsageTabModel) resizeTableColumns() {
 		// Compact mode: 4 simplified columns
 		newDisplayMode = CompactMode
 		columns = []table.Column{
-			{Title: "Date", Width: 10},
+			{Title: labelTitle, Width: max(10, labelWidth)},
 			{Title: "Reqs", Width: 8},
 			{Title: "Rate/min", Width: 12},
 			{Title: "Cost", Width: 10},
@@ -369,69 +642,119 @@ func (m *DailyUsageTabModel) updateTableRows() {
 	stats := m.usage.GetStats()
 	rows := make([]table.Row, 0, len(stats)*2) // Pre-allocate for potential sub-rows
 
-	for _, stat := range stats {
+	for i, stat := range stats {
 		period := stat.Period()
 		if period.IsAllTime() {
 			continue // Skip all-time periods
 		}
 
-		date := period.StartAt().In(m.timezone).Format("2006-01-02")
-		rows = append(rows, m.createRowsForStat(stat, date)...)
+		shortComparison, longComparison := m.comparison(i)
+		rows = append(rows, m.createRowsForStat(stat, m.periodLabel(period.StartAt()), shortComparison, longComparison, m.isAnomaly(i))...)
 	}
 
 	m.table.SetRows(rows)
 }
 
+// comparison returns the last two columns of the period at index i, "-" when the period has nothing to compare
+func (m *DailyUsageTabModel) comparison(i int) (string, string) {
+	if m.isCalendar() {
+		if change, ok := m.usage.ChangeAt(i); ok {
+			return FormatCostChange(change, m.rowCostFormat())
+		}
+		return "-", "-"
+	}
+
+	if average, ok := m.usage.MovingAverageAt(i); ok {
+		format := m.rowCostFormat()
+		return format.FormatAmount(average.Short().Amount()), format.FormatAmount(average.Long().Amount())
+	}
+	return "-", "-"
+}
+
+// rowCostFormat returns the format of the cost columns, the narrower modes keep fewer decimals when no precision is configured
+func (m *DailyUsageTabModel) rowCostFormat() entity.CostFormat {
+	switch m.displayMode {
+	case GroupedMode:
+		return m.costFormat.OrPrecision(4)
+	case CompactMode:
+		return m.costFormat.OrPrecision(3)
+	default:
+		return m.costFormat.OrPrecision(costPrecision)
+	}
+}
+
+// isAnomaly returns true if the day at index i costs more than the multiple of its baseline
+// Hours, weeks and months carry no moving averages, so they are never marked
+func (m *DailyUsageTabModel) isAnomaly(i int) bool {
+	average, ok := m.usage.MovingAverageAt(i)
+	if !ok || m.isCalendar() {
+		return false
+	}
+	return m.anomaly.Detect(m.usage.GetStats()[i].RateLimitedCost(), average.Baseline()).IsAnomaly()
+}
+
 // createRowsForStat creates table rows for a single stat based on display mode
-func (m *DailyUsageTabModel) createRowsForStat(stat entity.Stats, date string) []table.Row {
+// The comparison is the moving averages of a day or the change of a week or month, shown in full mode and under the cost of grouped mode
+// The cost of an anomalous day is marked
+func (m *DailyUsageTabModel) createRowsForStat(stat entity.Stats, date string, shortComparison, longComparison string, anomaly bool) []table.Row {
+	cost := m.rowCostFormat().FormatAmount(stat.RateLimitedCost().Amount())
+	if anomaly {
+		cost = highlightMarker + cost
+	}
+
 	switch m.displayMode {
 	case FullMode:
-		// Traditional 9-column layout
-		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
-		input := FormatTokenCount(stat.PremiumTokens().Input())
-		output := FormatTokenCount(stat.PremiumTokens().Output())
-		readCache := FormatTokenCount(stat.PremiumTokens().CacheRead())
-		creationCache := FormatTokenCount(stat.PremiumTokens().CacheCreation())
-		total := FormatTokenCount(stat.PremiumTokens().Total())
-		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.6f", stat.PremiumCost().Amount())
-		return []table.Row{{date, requests, input, output, readCache, creationCache, total, burnRate, cost}}
+		// 12-column layout with the premium and 1M context cost per 1K tokens and moving averages
+		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.RateLimitedRequests())
+		input := FormatTokenCount(stat.RateLimitedTokens().Input())
+		output := FormatTokenCount(stat.RateLimitedTokens().Output())
+		readCache := FormatTokenCount(stat.RateLimitedTokens().CacheRead())
+		creationCache := FormatTokenCount(stat.RateLimitedTokens().CacheCreation())
+		total := FormatTokenCount(stat.RateLimitedTokens().Total())
+		burnRate := FormatBurnRate(stat.RateLimitedTokenBurnRate())
+		costPerKiloToken := FormatCostPerKiloToken(stat.RateLimitedCost().PerKiloToken(stat.RateLimitedTokens()))
+		return []table.Row{{date, requests, input, output, readCache, creationCache, total, burnRate, cost, costPerKiloToken, shortComparison, longComparison}}
 
 	case GroupedMode:
 		// 4 main columns with token details in sub-rows
-		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
-		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.4f", stat.PremiumCost().Amount())
+		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.RateLimitedRequests())
+		burnRate := FormatBurnRate(stat.RateLimitedTokenBurnRate())
 
 		// Main row
 		mainRow := table.Row{date, requests, burnRate, cost}
 
 		// Token detail sub-rows (formatted to show grouping)
-		input := FormatTokenCount(stat.PremiumTokens().Input())
-		output := FormatTokenCount(stat.PremiumTokens().Output())
-		readCache := FormatTokenCount(stat.PremiumTokens().CacheRead())
-		creationCache := FormatTokenCount(stat.PremiumTokens().CacheCreation())
+		input := FormatTokenCount(stat.RateLimitedTokens().Input())
+		output := FormatTokenCount(stat.RateLimitedTokens().Output())
+		readCache := FormatTokenCount(stat.RateLimitedTokens().CacheRead())
+		creationCache := FormatTokenCount(stat.RateLimitedTokens().CacheCreation())
 
 		// Create grouped token display in second column
 		tokenDetails := fmt.Sprintf("├─I:%s O:%s", input, output)
 		cacheDetails := fmt.Sprintf("└─CR:%s CC:%s", readCache, creationCache)
 
-		subRow1 := table.Row{"", tokenDetails, "", ""}
+		// Cost per 1K tokens sits under the cost it is derived from
+		costPerKiloToken := FormatCostPerKiloToken(stat.RateLimitedCost().PerKiloToken(stat.RateLimitedTokens())) + "/1K"
+
+		subRow1 := table.Row{"", tokenDetails, "", costPerKiloToken}
 		subRow2 := table.Row{"", cacheDetails, "", ""}
+		if m.isCalendar() {
+			// The change from the period before sits under the cost as well
+			subRow2[3] = longComparison
+		}
 
 		return []table.Row{mainRow, subRow1, subRow2}
 
 	case CompactMode:
 		// 4 simplified columns
-		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
-		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
-		cost := fmt.Sprintf("%.3f", stat.PremiumCost().Amount())
+		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.RateLimitedRequests())
+		burnRate := FormatBurnRate(stat.RateLimitedTokenBurnRate())
 		return []table.Row{{date, requests, burnRate, cost}}
 
 	default:
 		// Fallback
-		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.PremiumRequests())
-		burnRate := FormatBurnRate(stat.PremiumTokenBurnRate())
+		requests := fmt.Sprintf("%d/%d", stat.BaseRequests(), stat.RateLimitedRequests())
+		burnRate := FormatBurnRate(stat.RateLimitedTokenBurnRate())
 		return []table.Row{{date, requests, burnRate, "-"}}
 	}
 }
@@ -1,12 +1,14 @@
 package tui_test
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
@@ -170,6 +172,19 @@ func TestDailyUsageTab_NavigationFlow(t *testing.T) {
 			teatest.WithDuration(time.Millisecond*500),
 		)
 
+		// Switch to sessions tab
+		tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+
+		// Verify we're on sessions tab
+		teatest.WaitFor(
+			t, tm.Output(),
+			func(bts []byte) bool {
+				return strings.Contains(string(bts), "[Sessions]")
+			},
+			teatest.WithCheckInterval(time.Millisecond*100),
+			teatest.WithDuration(time.Millisecond*500),
+		)
+
 		// Switch back to current tab
 		tm.Send(tea.KeyMsg{Type: tea.KeyTab})
 
@@ -243,6 +258,19 @@ func TestDailyUsageTab_FocusManagement(t *testing.T) {
 		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
 		tm.Send(tea.KeyMsg{Type: tea.KeyUp})
 
+		// Switch to sessions tab
+		tm.Send(tea.KeyMsg{Type: tea.KeyTab})
+
+		// Verify we're on sessions tab
+		teatest.WaitFor(
+			t, tm.Output(),
+			func(bts []byte) bool {
+				return strings.Contains(string(bts), "[Sessions]")
+			},
+			teatest.WithCheckInterval(time.Millisecond*100),
+			teatest.WithDuration(time.Millisecond*500),
+		)
+
 		// Switch back to current tab
 		tm.Send(tea.KeyMsg{Type: tea.KeyTab})
 
@@ -334,3 +362,226 @@ func TestDailyUsageTab_KeyboardNavigation(t *testing.T) {
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 	})
 }
+
+// TestDailyUsageTab_DateRangeNavigation tests paging the daily window with left/right keys
+func TestDailyUsageTab_DateRangeNavigation(t *testing.T) {
+	apiRepo, _ := testutil.NewMockRepositoryWithTestData()
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+
+	model := tui.NewDailyUsageTabModel(getUsageQuery, time.UTC)
+	model.SetSize(140, 40)
+
+	if !strings.Contains(model.View(), "Last 30 Days") {
+		t.Errorf("Expected initial window label 'Last 30 Days', got:\n%s", model.View())
+	}
+
+	// Page forward at the newest window is a no-op
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRight}); cmd != nil {
+		t.Error("Expected no refresh when paging forward from the newest window")
+	}
+
+	// Page backward to the previous 30 days
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if cmd == nil {
+		t.Fatal("Expected refresh command when paging backward")
+	}
+	if model.WindowOffset() != 30 {
+		t.Errorf("Expected window offset 30, got %d", model.WindowOffset())
+	}
+
+	msg := cmd()
+	dataMsg, ok := msg.(tui.UsageDataMsg)
+	if !ok {
+		t.Fatalf("Expected UsageDataMsg, got %T", msg)
+	}
+	stats := dataMsg.Usage.GetStats()
+	if len(stats) != 30 {
+		t.Fatalf("Expected 30 days in window, got %d", len(stats))
+	}
+	expectedNewest := periodFactory.CreateDaily().StartAt().AddDate(0, 0, -30)
+	if !stats[0].Period().StartAt().Equal(expectedNewest) {
+		t.Errorf("Expected window to start at %v, got %v", expectedNewest, stats[0].Period().StartAt())
+	}
+
+	now := time.Now().UTC()
+	expectedLabel := now.AddDate(0, 0, -59).Format("2006-01-02") + " to " + now.AddDate(0, 0, -30).Format("2006-01-02")
+	if !strings.Contains(model.View(), expectedLabel) {
+		t.Errorf("Expected window label %q, got:\n%s", expectedLabel, model.View())
+	}
+
+	// Page forward back to the newest window
+	model.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if model.WindowOffset() != 0 {
+		t.Errorf("Expected window offset 0, got %d", model.WindowOffset())
+	}
+}
+
+// TestDailyUsageTab_HourlyGranularity tests switching the daily usage tab to the last 48 hours and back
+func TestDailyUsageTab_HourlyGranularity(t *testing.T) {
+	apiRepo, _ := testutil.NewMockRepositoryWithTestData()
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+	model := tui.NewDailyUsageTabModel(getUsageQuery, time.UTC)
+	model.SetSize(160, 40)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if cmd == nil {
+		t.Fatal("Expected refresh command when switching to hourly")
+	}
+	if !model.Hourly() {
+		t.Fatal("Expected hourly granularity")
+	}
+
+	msg := cmd()
+	dataMsg, ok := msg.(tui.UsageDataMsg)
+	if !ok {
+		t.Fatalf("Expected UsageDataMsg, got %T", msg)
+	}
+	if stats := dataMsg.Usage.GetStats(); len(stats) != 48 {
+		t.Fatalf("Expected 48 hours, got %d", len(stats))
+	}
+	model.Update(dataMsg)
+
+	view := model.View()
+	currentHour := time.Now().UTC().Truncate(time.Hour).Format("2006-01-02 15:04")
+	for _, text := range []string{"Hourly Usage Statistics (Last 48 Hours)", "Hour", currentHour} {
+		if !strings.Contains(view, text) {
+			t.Errorf("Expected hourly view to contain %q, got:\n%s", text, view)
+		}
+	}
+
+	// Hours always end now, paging only moves the days
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyLeft}); cmd != nil || model.WindowOffset() != 0 {
+		t.Error("Expected no paging in hourly granularity")
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if model.Hourly() {
+		t.Error("Expected daily granularity after switching back")
+	}
+	if !strings.Contains(model.View(), "Last 30 Days") {
+		t.Errorf("Expected the daily window label, got:\n%s", model.View())
+	}
+}
+
+// TestDailyUsageTab_MovingAverages tests the moving average columns and trend line of the daily tab
+func TestDailyUsageTab_MovingAverages(t *testing.T) {
+	apiRepo, _ := testutil.NewMockRepositoryWithTestData()
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+
+	model := tui.NewDailyUsageTabModel(getUsageQuery, time.UTC)
+	model.SetSize(160, 40)
+
+	usage, err := getUsageQuery.ListByDay(context.Background(), 30, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	model.Update(tui.UsageDataMsg{Usage: usage})
+
+	view := model.View()
+	for _, expected := range []string{"7d Avg Trend:", "$/1K Tok", "7d Avg", "30d Avg"} {
+		if !strings.Contains(view, expected) {
+			t.Errorf("Expected view to contain %q, got:\n%s", expected, view)
+		}
+	}
+}
+
+// TestDailyUsageTab_CostAnomaly tests marking the cost of days above the multiple of their 7-day baseline
+func TestDailyUsageTab_CostAnomaly(t *testing.T) {
+	setupTestEnvironment()
+
+	// Premium cost is $0.01 today and $0.02 yesterday, only today is above twice its baseline
+	usage := CreateTestUsage()
+	averages := make([]entity.MovingAverage, len(usage.GetStats()))
+	for i := range averages {
+		averages[i] = entity.NewMovingAverage(entity.NewCost(0.02), entity.NewCost(0.02)).WithBaseline(entity.NewCost(0.02))
+	}
+	averages[0] = averages[0].WithBaseline(entity.NewCost(0.004))
+	usage = usage.WithMovingAverages(averages)
+
+	model := tui.NewDailyUsageTabModel(CreateTestUsageQuery(), time.UTC)
+	model.SetSize(160, 40)
+	model.Update(tui.UsageDataMsg{Usage: usage})
+
+	if view := model.View(); strings.Contains(view, "▲") {
+		t.Errorf("Expected no marker without a policy, got:\n%s", view)
+	}
+
+	model.SetCostAnomalyPolicy(entity.NewCostAnomalyPolicy(2))
+	view := model.View()
+	if !strings.Contains(view, "over 2x the prior 7d avg") {
+		t.Errorf("Expected the trend line to explain the marker, got:\n%s", view)
+	}
+
+	today := tui.FormatDate(usage.GetStats()[0].Period().StartAt())
+	yesterday := tui.FormatDate(usage.GetStats()[1].Period().StartAt())
+	marked := false
+	for _, line := range strings.Split(view, "\n") {
+		switch {
+		case strings.Contains(line, today):
+			marked = strings.Contains(line, "▲0.01")
+		case strings.Contains(line, yesterday) && strings.Contains(line, "▲"):
+			t.Errorf("Expected yesterday's cost not to be marked, got %q", line)
+		}
+	}
+	if !marked {
+		t.Errorf("Expected today's cost to be marked, got:\n%s", view)
+	}
+}
+
+// TestDailyUsageTab_CalendarGranularity tests cycling through weekly and monthly usage with their changes
+func TestDailyUsageTab_CalendarGranularity(t *testing.T) {
+	apiRepo, _ := testutil.NewMockRepositoryWithTestData()
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+
+	model := tui.NewDailyUsageTabModel(getUsageQuery, time.UTC)
+	model.SetSize(160, 40)
+
+	tests := []struct {
+		granularity tui.UsageGranularity
+		periods     int
+		texts       []string
+	}{
+		{tui.GranularityWeekly, 12, []string{"Weekly Usage Statistics (Last 12 Weeks)", "Week", "Change %", "vs last week"}},
+		{tui.GranularityMonthly, 12, []string{"Monthly Usage Statistics (Last 12 Months)", "Month", time.Now().UTC().Format("Jan 2006"), "vs last month"}},
+	}
+
+	for _, tt := range tests {
+		_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+		if cmd == nil {
+			t.Fatal("Expected refresh command when cycling the granularity")
+		}
+		if model.Granularity() != tt.granularity {
+			t.Fatalf("Expected granularity %d, got %d", tt.granularity, model.Granularity())
+		}
+
+		msg := cmd()
+		dataMsg, ok := msg.(tui.UsageDataMsg)
+		if !ok {
+			t.Fatalf("Expected UsageDataMsg, got %T", msg)
+		}
+		if stats := dataMsg.Usage.GetStats(); len(stats) != tt.periods {
+			t.Fatalf("Expected %d periods, got %d", tt.periods, len(stats))
+		}
+		model.Update(dataMsg)
+
+		view := model.View()
+		for _, text := range tt.texts {
+			if !strings.Contains(view, text) {
+				t.Errorf("Expected view to contain %q, got:\n%s", text, view)
+			}
+		}
+
+		// Weeks and months always end now, paging only moves the days
+		if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyLeft}); cmd != nil || model.WindowOffset() != 0 {
+			t.Error("Expected no paging in calendar granularity")
+		}
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if model.Granularity() != tui.GranularityDaily {
+		t.Errorf("Expected daily granularity after a full cycle, got %d", model.Granularity())
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -32,9 +33,19 @@ var (
 	PremiumStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("214"))
 
+	LongContextStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("203"))
+
 	HelpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241"))
 
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("220"))
+
+	HighlightStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196"))
+
 	BoxStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("240")).
@@ -60,11 +71,66 @@ func FormatNumber(n int64) string {
 	return fmt.Sprintf("%d", n)
 }
 
+// FormatCount formats a count with thousands separators, e.g. "3,214"
+func FormatCount(n int64) string {
+	if n < 0 {
+		return "-" + FormatCount(-n)
+	}
+	digits := fmt.Sprintf("%d", n)
+
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+
+	return b.String()
+}
+
+// timeFormat is the display format for all dates and times in the TUI
+var timeFormat = entity.DefaultTimeFormat()
+
+// SetTimeFormat sets the display format used for dates, times and block labels
+func SetTimeFormat(format entity.TimeFormat) {
+	timeFormat = format
+}
+
+// FormatDate formats the date of t using the configured date format
+func FormatDate(t time.Time) string {
+	return t.Format(timeFormat.DateLayout())
+}
+
+// FormatDateTime formats t as date and time of day with seconds using the configured format
+func FormatDateTime(t time.Time) string {
+	return t.Format(timeFormat.DateTimeLayout())
+}
+
+// FormatDateShortTime formats t as date and time of day without seconds using the configured format
+func FormatDateShortTime(t time.Time) string {
+	return FormatDate(t) + " " + t.Format(timeFormat.ShortTimeLayout())
+}
+
+// costPrecision is the decimals of cost amounts in the TUI when no precision is configured
+const costPrecision = 6
+
+// FormatCost formats a cost amount with the default decimals, rendering zero as "-"
 func FormatCost(cost float64) string {
+	return formatCost(entity.CostFormat{}, cost)
+}
+
+// formatCost formats a cost amount in the format, rendering zero as "-"
+func formatCost(format entity.CostFormat, cost float64) string {
 	if cost == 0 {
 		return "-"
 	}
-	return fmt.Sprintf("%.6f", cost)
+	return formatCostAmount(format, cost)
+}
+
+// formatCostAmount formats a cost amount without currency sign in the format
+func formatCostAmount(format entity.CostFormat, cost float64) string {
+	return format.OrPrecision(costPrecision).FormatAmount(cost)
 }
 
 func FormatDuration(ms int64) string {
@@ -75,13 +141,7 @@ func FormatDuration(ms int64) string {
 }
 
 func FormatTokenCount(tokens int64) string {
-	if tokens < 1000 {
-		return fmt.Sprintf("%d", tokens)
-	} else if tokens < 1000000 {
-		return fmt.Sprintf("%.1fK", float64(tokens)/1000)
-	} else {
-		return fmt.Sprintf("%.2fM", float64(tokens)/1000000)
-	}
+	return entity.FormatTokenCount(tokens)
 }
 
 func FormatDurationFromTime(d time.Duration) string {
@@ -96,6 +156,73 @@ func FormatDurationFromTime(d time.Duration) string {
 	}
 }
 
+// FormatRetention formats a retention duration, whole days are shown as "30d"
+func FormatRetention(d time.Duration) string {
+	day := 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return fmt.Sprintf("%dd", int(d/day))
+	}
+	return FormatDurationFromTime(d)
+}
+
+// FormatClockShift describes a daylight saving time change of today, e.g. "DST: clocks go forward 1h, 23h day"
+// Regular days without a clock change return an empty string
+func FormatClockShift(shift time.Duration) string {
+	switch {
+	case shift > 0:
+		return fmt.Sprintf("DST: clocks go forward %s, %s day", formatShift(shift), formatShift(24*time.Hour-shift))
+	case shift < 0:
+		return fmt.Sprintf("DST: clocks go back %s, %s day", formatShift(-shift), formatShift(24*time.Hour-shift))
+	default:
+		return ""
+	}
+}
+
+// formatShift formats a clock change or a day length, e.g. "1h", "30m" or "23h 30m"
+func formatShift(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return FormatDurationFromTime(d)
+	}
+}
+
+// FormatRelativeTime formats the elapsed time since a timestamp, e.g. "2m ago"
+// Timestamps in the future (clock skew) are shown as "just now"
+func FormatRelativeTime(elapsed time.Duration) string {
+	switch {
+	case elapsed < time.Second:
+		return "just now"
+	case elapsed < time.Minute:
+		return fmt.Sprintf("%ds ago", int(elapsed.Seconds()))
+	case elapsed < time.Hour:
+		return fmt.Sprintf("%dm ago", int(elapsed.Minutes()))
+	case elapsed < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(elapsed.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(elapsed.Hours()/24))
+	}
+}
+
+// FormatStatsFreshness formats whether the stats are fresh or served from a cache, e.g. "Stats: cached 42s ago"
+func FormatStatsFreshness(stats entity.Stats, now time.Time) string {
+	if !stats.IsCached() {
+		return "Stats: fresh"
+	}
+	return "Stats: cached " + FormatRelativeTime(now.Sub(stats.CachedAt()))
+}
+
+// FormatCostPerKiloToken formats the cost of 1,000 tokens, which needs more decimals than regular costs
+func FormatCostPerKiloToken(cost entity.Cost, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.4f", cost.Amount())
+}
+
 func FormatBurnRate(tokensPerMinute float64) string {
 	if tokensPerMinute <= 0 {
 		return "-"
@@ -110,6 +237,47 @@ func FormatBurnRate(tokensPerMinute float64) string {
 	}
 }
 
+// sparklineLevels are the block characters of a sparkline, lowest first
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// FormatSparkline renders values as a sparkline scaled between zero and the highest value
+func FormatSparkline(values []float64) string {
+	highest := 0.0
+	for _, value := range values {
+		highest = math.Max(highest, value)
+	}
+
+	sparkline := make([]rune, len(values))
+	for i, value := range values {
+		level := 0
+		if highest > 0 && value > 0 {
+			level = int(math.Round(value / highest * float64(len(sparklineLevels)-1)))
+		}
+		sparkline[i] = sparklineLevels[level]
+	}
+	return string(sparkline)
+}
+
+// FormatCostChange formats the premium cost delta from the period before and its percentage, e.g. "+1.20" and "+15.0%"
+// A period after one without cost has no percentage, it is shown as "new" or "-" when neither cost anything
+func FormatCostChange(change entity.UsageChange, format entity.CostFormat) (string, string) {
+	delta := change.Delta().Amount()
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+	}
+	deltaText := sign + formatCostAmount(format, math.Abs(delta))
+
+	percent, ok := change.Percent()
+	switch {
+	case ok:
+		return deltaText, fmt.Sprintf("%+.1f%%", percent)
+	case delta > 0:
+		return deltaText, "new"
+	}
+	return "-", "-"
+}
+
 // Layout helper functions
 func PadRight(s string, width int) string {
 	// Account for ANSI escape codes when calculating padding
@@ -122,7 +290,7 @@ func PadRight(s string, width int) string {
 
 func CalculateStatsColumnWidths(availableWidth int) []int {
 	// Base minimum widths for each column
-	minWidths := []int{12, 5, 8, 6, 8, 10, 10} // Model Tier, Reqs, Limited, Cache, Total, Cost, Burn Rate
+	minWidths := []int{12, 5, 8, 6, 8, 10, 9, 10} // Model Tier, Reqs, Limited, Cache, Total, Cost, $/1K Tok, Burn Rate
 
 	// Calculate total minimum width
 	totalMinWidth := 0
@@ -134,7 +302,7 @@ func CalculateStatsColumnWidths(availableWidth int) []int {
 	if availableWidth > totalMinWidth {
 		extraSpace := availableWidth - totalMinWidth
 		// Distribute extra space: favor first column and burn rate column
-		distribution := []float64{0.25, 0.1, 0.15, 0.1, 0.15, 0.1, 0.15}
+		distribution := []float64{0.2, 0.1, 0.15, 0.1, 0.1, 0.1, 0.1, 0.15}
 
 		for i := range minWidths {
 			extra := int(float64(extraSpace) * distribution[i])
@@ -179,26 +347,67 @@ func CalculateTableColumnWidths(availableWidth int) []int {
 	return minWidths
 }
 
+// CalculateSessionsColumnWidths returns the sessions tab column widths for the available width
+func CalculateSessionsColumnWidths(availableWidth int) []int {
+	// Session, Requests, Tokens, Cost, First Seen, Last Seen, Span
+	minWidths := []int{20, 8, 8, 9, 16, 16, 8}
+
+	// Account for borders, padding, and separators (approximately 2 chars per column)
+	overhead := len(minWidths) * 2
+	usableWidth := availableWidth - overhead
+
+	totalMinWidth := 0
+	for _, w := range minWidths {
+		totalMinWidth += w
+	}
+
+	// Session names are titles or IDs, give them most of the extra space
+	if usableWidth > totalMinWidth {
+		extraSpace := usableWidth - totalMinWidth
+		distribution := []float64{0.6, 0.05, 0.05, 0.05, 0.1, 0.1, 0.05}
+
+		for i := range minWidths {
+			minWidths[i] += int(float64(extraSpace) * distribution[i])
+		}
+	}
+
+	return minWidths
+}
+
+// CalculateLeaderboardColumnWidths calculates the leaderboard table column widths for the available width
+func CalculateLeaderboardColumnWidths(availableWidth int) []int {
+	// Rank, User, Requests, Tokens, Cost, Share
+	minWidths := []int{6, 24, 8, 8, 9, 7}
+
+	// Account for borders, padding, and separators (approximately 2 chars per column)
+	overhead := len(minWidths) * 2
+	usableWidth := availableWidth - overhead
+
+	totalMinWidth := 0
+	for _, w := range minWidths {
+		totalMinWidth += w
+	}
+
+	// User emails are long, give them most of the extra space
+	if usableWidth > totalMinWidth {
+		extraSpace := usableWidth - totalMinWidth
+		distribution := []float64{0, 0.5, 0.1, 0.15, 0.15, 0.1}
+
+		for i := range minWidths {
+			minWidths[i] += int(float64(extraSpace) * distribution[i])
+		}
+	}
+
+	return minWidths
+}
+
 // FormatBlockTime formats the block period for display in the given timezone
 func FormatBlockTime(block entity.Block, timezone *time.Location) string {
 	startLocal := block.StartAt().In(timezone)
 	endLocal := block.EndAt().In(timezone)
 
-	startStr := formatHour(startLocal.Hour())
-	endStr := formatHour(endLocal.Hour())
+	startStr := timeFormat.FormatHour(startLocal.Hour())
+	endStr := timeFormat.FormatHour(endLocal.Hour())
 
 	return fmt.Sprintf("%s - %s", startStr, endStr)
 }
-
-// formatHour formats hour (0-23) into 12-hour format with am/pm
-func formatHour(hour int) string {
-	if hour == 0 {
-		return "12am"
-	} else if hour < 12 {
-		return fmt.Sprintf("%dam", hour)
-	} else if hour == 12 {
-		return "12pm"
-	} else {
-		return fmt.Sprintf("%dpm", hour-12)
-	}
-}
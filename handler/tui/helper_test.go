@@ -52,3 +52,180 @@ func TestFormatBlockTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatWithTimeFormat(t *testing.T) {
+	format, err := entity.NewTimeFormat("DD/MM/YYYY", entity.Clock24Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	SetTimeFormat(format)
+	defer SetTimeFormat(entity.DefaultTimeFormat())
+
+	at := time.Date(2025, 1, 31, 9, 5, 7, 0, time.UTC)
+	if got := FormatDate(at); got != "31/01/2025" {
+		t.Errorf("FormatDate() = %q, want %q", got, "31/01/2025")
+	}
+	if got := FormatDateTime(at); got != "31/01/2025 09:05:07" {
+		t.Errorf("FormatDateTime() = %q, want %q", got, "31/01/2025 09:05:07")
+	}
+	if got := FormatDateShortTime(at); got != "31/01/2025 09:05" {
+		t.Errorf("FormatDateShortTime() = %q, want %q", got, "31/01/2025 09:05")
+	}
+	if got := FormatBlockTime(entity.NewBlock(at.Add(-4*time.Hour)), time.UTC); got != "05:00 - 10:00" {
+		t.Errorf("FormatBlockTime() = %q, want %q", got, "05:00 - 10:00")
+	}
+	if got := dateColumnWidth(); got != 10 {
+		t.Errorf("dateColumnWidth() = %d, want 10", got)
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    string
+	}{
+		{name: "future timestamp", elapsed: -5 * time.Second, want: "just now"},
+		{name: "under a second", elapsed: 500 * time.Millisecond, want: "just now"},
+		{name: "seconds", elapsed: 42 * time.Second, want: "42s ago"},
+		{name: "minutes", elapsed: 2*time.Minute + 30*time.Second, want: "2m ago"},
+		{name: "hours", elapsed: 3*time.Hour + 59*time.Minute, want: "3h ago"},
+		{name: "days", elapsed: 50 * time.Hour, want: "2d ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatRelativeTime(tt.elapsed)
+			if got != tt.want {
+				t.Errorf("FormatRelativeTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatStatsFreshness(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	period := entity.NewPeriod(now.Add(-time.Hour), now)
+
+	fresh := entity.NewStatsFromRequests(nil, period)
+	if got := FormatStatsFreshness(fresh, now); got != "Stats: fresh" {
+		t.Errorf("FormatStatsFreshness() = %q, want %q", got, "Stats: fresh")
+	}
+
+	cached := fresh.WithCachedAt(now.Add(-42 * time.Second))
+	if got := FormatStatsFreshness(cached, now); got != "Stats: cached 42s ago" {
+		t.Errorf("FormatStatsFreshness() = %q, want %q", got, "Stats: cached 42s ago")
+	}
+}
+
+func TestFormatRetention(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		want     string
+	}{
+		{name: "whole days", duration: 30 * 24 * time.Hour, want: "30d"},
+		{name: "single day", duration: 24 * time.Hour, want: "1d"},
+		{name: "partial day", duration: 36 * time.Hour, want: "36h 0m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatRetention(tt.duration)
+			if got != tt.want {
+				t.Errorf("FormatRetention() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int64
+		want  string
+	}{
+		{name: "zero", count: 0, want: "0"},
+		{name: "below a thousand", count: 100, want: "100"},
+		{name: "thousands", count: 3214, want: "3,214"},
+		{name: "millions", count: 1234567, want: "1,234,567"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatCount(tt.count)
+			if got != tt.want {
+				t.Errorf("FormatCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatClockShift(t *testing.T) {
+	tests := []struct {
+		name  string
+		shift time.Duration
+		want  string
+	}{
+		{name: "regular day", shift: 0, want: ""},
+		{name: "spring forward", shift: time.Hour, want: "DST: clocks go forward 1h, 23h day"},
+		{name: "fall back", shift: -time.Hour, want: "DST: clocks go back 1h, 25h day"},
+		{name: "half hour change", shift: 30 * time.Minute, want: "DST: clocks go forward 30m, 23h 30m day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatClockShift(tt.shift)
+			if got != tt.want {
+				t.Errorf("FormatClockShift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCostChange(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     float64
+		previous    float64
+		wantDelta   string
+		wantPercent string
+	}{
+		{name: "increase", current: 11.5, previous: 10, wantDelta: "+1.50", wantPercent: "+15.0%"},
+		{name: "decrease", current: 5, previous: 10, wantDelta: "-5.00", wantPercent: "-50.0%"},
+		{name: "unchanged", current: 10, previous: 10, wantDelta: "+0.00", wantPercent: "+0.0%"},
+		{name: "after a period without cost", current: 3, previous: 0, wantDelta: "+3.00", wantPercent: "new"},
+		{name: "no cost in either period", current: 0, previous: 0, wantDelta: "-", wantPercent: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, percent := FormatCostChange(entity.NewUsageChange(entity.NewCost(tt.current), entity.NewCost(tt.previous)), entity.DefaultCostFormat())
+			if delta != tt.wantDelta || percent != tt.wantPercent {
+				t.Errorf("FormatCostChange() = %q, %q, want %q, %q", delta, percent, tt.wantDelta, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestFormatSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{name: "rising", values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, want: "▁▂▃▄▅▆▇█"},
+		{name: "spike", values: []float64{1, 7, 1}, want: "▂█▂"},
+		{name: "all zero", values: []float64{0, 0}, want: "▁▁"},
+		{name: "empty", values: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatSparkline(tt.values)
+			if got != tt.want {
+				t.Errorf("FormatSparkline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
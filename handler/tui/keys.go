@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// KeyAction is a monitor action which can be bound to other keys in the [monitor.keys] config section
+type KeyAction string
+
+const (
+	KeyQuit          KeyAction = "quit"
+	KeyTabNext       KeyAction = "tab_next"
+	KeyRefresh       KeyAction = "refresh"
+	KeySortToggle    KeyAction = "sort_toggle"
+	KeySearch        KeyAction = "search"
+	KeyNotifications KeyAction = "notifications"
+	KeyServerPanel   KeyAction = "server_panel"
+	KeyBlockHistory  KeyAction = "block_history"
+	KeyFilterAll     KeyAction = "filter_all"
+	KeyFilterHour    KeyAction = "filter_hour"
+	KeyFilterDay     KeyAction = "filter_day"
+	KeyFilterWeek    KeyAction = "filter_week"
+	KeyFilterMonth   KeyAction = "filter_month"
+	KeyFilterBlock   KeyAction = "filter_block"
+)
+
+// defaultKeys are the keys of each action when the config does not rebind it
+var defaultKeys = map[KeyAction][]string{
+	KeyQuit:          {"q"},
+	KeyTabNext:       {"tab"},
+	KeyRefresh:       {"r"},
+	KeySortToggle:    {"o"},
+	KeySearch:        {"/"},
+	KeyNotifications: {"n"},
+	KeyServerPanel:   {"i"},
+	KeyBlockHistory:  {"B"},
+	KeyFilterAll:     {"a"},
+	KeyFilterHour:    {"h"},
+	KeyFilterDay:     {"d"},
+	KeyFilterWeek:    {"w"},
+	KeyFilterMonth:   {"m"},
+	KeyFilterBlock:   {"b"},
+}
+
+// reservedKeys are handled by the tabs and panels, binding an action to them would hide their function
+// ctrl+c always quits, so a broken key map can't lock the monitor
+var reservedKeys = []string{
+	"ctrl+c", "esc", "enter", "up", "down", "left", "right", "pgup", "pgdown", "home", "end", "j", "k",
+	"s", "t", "v", "*", "g", "c", "p", "x",
+}
+
+// KeyMap maps the keys pressed in the monitor to their actions
+type KeyMap struct {
+	keys    map[KeyAction][]string
+	actions map[string]KeyAction
+}
+
+// DefaultKeyMap returns the built-in keys of the monitor
+func DefaultKeyMap() KeyMap {
+	keyMap, _ := NewKeyMap(nil) // The defaults never conflict
+	return keyMap
+}
+
+// NewKeyMap returns the default keys with the actions of the bindings replaced, keys are named like "H", "ctrl+r" or "f5"
+// Every action keeps a key and no key triggers two actions, unknown actions and reserved keys are rejected
+func NewKeyMap(bindings map[string][]string) (KeyMap, error) {
+	keys := make(map[KeyAction][]string, len(defaultKeys))
+	for action, defaults := range defaultKeys {
+		keys[action] = defaults
+	}
+
+	// Sorted, so the same config always reports the same error
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		action := KeyAction(name)
+		if _, ok := defaultKeys[action]; !ok {
+			return KeyMap{}, fmt.Errorf("unknown action %q, must be one of %s", name, strings.Join(keyActionNames(), ", "))
+		}
+
+		bound := make([]string, 0, len(bindings[name]))
+		for _, key := range bindings[name] {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				continue
+			}
+			if slices.Contains(reservedKeys, key) {
+				return KeyMap{}, fmt.Errorf("%s: key %q is reserved by the monitor", name, key)
+			}
+			bound = append(bound, key)
+		}
+		if len(bound) == 0 {
+			return KeyMap{}, fmt.Errorf("%s: at least one key is required", name)
+		}
+		keys[action] = bound
+	}
+
+	actions := make(map[string]KeyAction)
+	for _, action := range sortedKeyActions() {
+		for _, key := range keys[action] {
+			if other, ok := actions[key]; ok {
+				return KeyMap{}, fmt.Errorf("key %q is bound to both %s and %s", key, other, action)
+			}
+			actions[key] = action
+		}
+	}
+
+	return KeyMap{keys: keys, actions: actions}, nil
+}
+
+// Action returns the action of the pressed key, false when the key has no action
+func (k KeyMap) Action(key string) (KeyAction, bool) {
+	action, ok := k.actions[key]
+	return action, ok
+}
+
+// Is returns true if the pressed key triggers the action
+func (k KeyMap) Is(key string, action KeyAction) bool {
+	bound, ok := k.actions[key]
+	return ok && bound == action
+}
+
+// Help returns the first key of the action for the help text
+func (k KeyMap) Help(action KeyAction) string {
+	return k.keys[action][0]
+}
+
+// sortedKeyActions returns the actions in a fixed order
+func sortedKeyActions() []KeyAction {
+	actions := make([]KeyAction, 0, len(defaultKeys))
+	for action := range defaultKeys {
+		actions = append(actions, action)
+	}
+	slices.Sort(actions)
+	return actions
+}
+
+// keyActionNames returns the names of the actions for error messages
+func keyActionNames() []string {
+	names := make([]string, 0, len(defaultKeys))
+	for _, action := range sortedKeyActions() {
+		names = append(names, string(action))
+	}
+	return names
+}
@@ -0,0 +1,139 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]string
+		expected map[string]tui.KeyAction // keys and the action they trigger
+		unbound  []string
+		wantErr  string
+	}{
+		{
+			name:     "defaults",
+			expected: map[string]tui.KeyAction{"h": tui.KeyFilterHour, "q": tui.KeyQuit, "tab": tui.KeyTabNext, "/": tui.KeySearch},
+		},
+		{
+			name:     "rebound filters",
+			bindings: map[string][]string{"filter_hour": {"H"}, "filter_day": {"D", "f2"}},
+			expected: map[string]tui.KeyAction{"H": tui.KeyFilterHour, "D": tui.KeyFilterDay, "f2": tui.KeyFilterDay, "w": tui.KeyFilterWeek},
+			unbound:  []string{"h", "d"},
+		},
+		{
+			name:     "swapped keys",
+			bindings: map[string][]string{"filter_hour": {"d"}, "filter_day": {"h"}},
+			expected: map[string]tui.KeyAction{"d": tui.KeyFilterHour, "h": tui.KeyFilterDay},
+		},
+		{
+			name:     "unknown action",
+			bindings: map[string][]string{"filter_year": {"y"}},
+			wantErr:  `unknown action "filter_year"`,
+		},
+		{
+			name:     "conflicting keys",
+			bindings: map[string][]string{"quit": {"h"}},
+			wantErr:  `key "h" is bound to both filter_hour and quit`,
+		},
+		{
+			name:     "reserved key",
+			bindings: map[string][]string{"quit": {"ctrl+c", "x"}},
+			wantErr:  `key "ctrl+c" is reserved`,
+		},
+		{
+			name:     "empty keys",
+			bindings: map[string][]string{"refresh": {""}},
+			wantErr:  "at least one key is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := tui.NewKeyMap(tt.bindings)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NewKeyMap() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewKeyMap() returned error: %v", err)
+			}
+
+			for key, expected := range tt.expected {
+				if action, ok := keys.Action(key); !ok || action != expected {
+					t.Errorf("Action(%q) = %q, %v, want %q", key, action, ok, expected)
+				}
+			}
+			for _, key := range tt.unbound {
+				if action, ok := keys.Action(key); ok {
+					t.Errorf("Expected %q to be unbound, got %q", key, action)
+				}
+			}
+		})
+	}
+}
+
+func TestViewModel_KeyMap(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	keys, err := tui.NewKeyMap(map[string][]string{
+		"filter_hour": {"H"},
+		"tab_next":    {"l"},
+		"quit":        {"Q"},
+	})
+	if err != nil {
+		t.Fatalf("NewKeyMap() returned error: %v", err)
+	}
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.SetKeyMap(keys)
+	vm.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+
+	// The default key no longer filters
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if vm.GetTimeFilterString() != "All Time" {
+		t.Errorf("Expected the unbound h to be ignored, got %q", vm.GetTimeFilterString())
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	if vm.GetTimeFilterString() != "Last Hour" {
+		t.Errorf("Expected H to filter the last hour, got %q", vm.GetTimeFilterString())
+	}
+
+	// The help shows the rebound keys
+	view := vm.View()
+	for _, help := range []string{"H=hour", "l: Switch tabs", "Q: Quit"} {
+		if !strings.Contains(view, help) {
+			t.Errorf("Expected the help to contain %q", help)
+		}
+	}
+
+	if _, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Error("Expected the unbound q not to quit")
+		}
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if vm.CurrentTab() != tui.TabDaily {
+		t.Errorf("Expected l to switch to the daily tab, got %v", vm.CurrentTab())
+	}
+	_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Q")})
+	if cmd == nil {
+		t.Fatal("Expected Q to quit")
+	}
+	if _, quit := cmd().(tea.QuitMsg); !quit {
+		t.Error("Expected Q to quit")
+	}
+}
@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// leaderboardPeriod is how far back the leaderboard ranks the users
+const leaderboardPeriod = 7 * 24 * time.Hour
+
+// leaderboardViewerMarker suffixes the user of the viewer in the leaderboard table
+const leaderboardViewerMarker = " (you)"
+
+// LeaderboardTabModel handles the leaderboard tab that ranks the users by their weekly cost and owns its data
+type LeaderboardTabModel struct {
+	// Data ownership
+	table       table.Model
+	leaderboard entity.Leaderboard
+
+	// Configuration
+	width      int
+	height     int
+	costFormat entity.CostFormat
+
+	// Viewer is the user of this monitor, private shows only the viewer's rank
+	viewer  string
+	private bool
+
+	// Business logic dependencies
+	getLeaderboardQuery *usecase.GetLeaderboardQuery
+}
+
+// NewLeaderboardTabModel creates a new leaderboard tab model with usecase dependency
+func NewLeaderboardTabModel(getLeaderboardQuery *usecase.GetLeaderboardQuery, viewer string, private bool) *LeaderboardTabModel {
+	t := table.New(
+		table.WithColumns(leaderboardTableColumns(CalculateLeaderboardColumnWidths(120))),
+		table.WithFocused(false),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.Bold(true)
+	s.Selected = s.Selected.Bold(false)
+	t.SetStyles(s)
+
+	return &LeaderboardTabModel{
+		table:               t,
+		width:               120,
+		height:              30,
+		viewer:              viewer,
+		private:             private,
+		getLeaderboardQuery: getLeaderboardQuery,
+	}
+}
+
+// Init initializes the leaderboard tab model
+func (m *LeaderboardTabModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m *LeaderboardTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case ResizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	case LeaderboardRefreshMsg:
+		return m, m.refreshLeaderboard()
+	case LeaderboardDataMsg:
+		if msg.Err != nil {
+			// Keep the last known ranking when the server is unreachable
+			return m, nil
+		}
+		m.leaderboard = msg.Leaderboard
+		m.updateTableRows()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "p":
+			// Toggle between the full ranking and only the viewer's rank
+			m.private = !m.private
+			m.updateTableRows()
+			return m, nil
+		}
+		// Handle table navigation
+		m.table, cmd = m.table.Update(msg)
+	}
+
+	return m, cmd
+}
+
+// View renders the leaderboard tab
+func (m *LeaderboardTabModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(HeaderStyle.Render("Team Leaderboard (Last 7 Days)") + "\n")
+	subtitle := "Users ranked by cost • Share: part of the team cost"
+	if m.private {
+		subtitle = "Private: only your rank is shown"
+	}
+	b.WriteString(HelpStyle.Render(subtitle) + "\n\n")
+
+	entries := m.leaderboard.Entries()
+	if len(entries) == 0 {
+		emptyContent := HelpStyle.Render("No requests with user attribution in this period")
+		b.WriteString(BoxStyle.Width(m.width-4).Render(emptyContent) + "\n")
+		return b.String()
+	}
+
+	if m.private && !m.hasViewer() {
+		emptyContent := HelpStyle.Render("Set monitor.leaderboard.user to your email to see your rank")
+		if m.viewer != "" {
+			emptyContent = HelpStyle.Render(fmt.Sprintf("No requests by %s in this period", m.viewer))
+		}
+		b.WriteString(BoxStyle.Width(m.width-4).Render(emptyContent) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(m.table.View() + "\n")
+	summary := fmt.Sprintf("  %d users", len(entries))
+	if entry, ok := m.leaderboard.Find(m.viewer); ok {
+		summary += fmt.Sprintf(" • Your rank: %d of %d", entry.Rank(), len(entries))
+	}
+	b.WriteString(HelpStyle.Render(summary) + "\n")
+	return b.String()
+}
+
+// SetCostFormat sets the display format of the costs in the leaderboard
+func (m *LeaderboardTabModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+}
+
+// SetSize updates the table size and recalculates column widths
+func (m *LeaderboardTabModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Clear rows before setting new columns to avoid index out of range
+	m.table.SetRows([]table.Row{})
+	m.table.SetColumns(leaderboardTableColumns(CalculateLeaderboardColumnWidths(width)))
+	m.updateTableRows()
+
+	// Title, tabs, header, summary, help and footers take about 13 lines
+	tableHeight := height - 13
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.table.SetHeight(tableHeight)
+}
+
+// Leaderboard returns the current ranking
+func (m *LeaderboardTabModel) Leaderboard() entity.Leaderboard {
+	return m.leaderboard
+}
+
+// Private returns true if only the viewer's rank is shown
+func (m *LeaderboardTabModel) Private() bool {
+	return m.private
+}
+
+// GetTable returns the underlying table model for integration with other components
+func (m *LeaderboardTabModel) GetTable() table.Model {
+	return m.table
+}
+
+// Focus sets focus on the leaderboard table
+func (m *LeaderboardTabModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur removes focus from the leaderboard table
+func (m *LeaderboardTabModel) Blur() {
+	m.table.Blur()
+}
+
+// Focused returns whether the leaderboard table is focused
+func (m *LeaderboardTabModel) Focused() bool {
+	return m.table.Focused()
+}
+
+// hasViewer returns true if the viewer is ranked
+func (m *LeaderboardTabModel) hasViewer() bool {
+	_, ok := m.leaderboard.Find(m.viewer)
+	return ok
+}
+
+// updateTableRows lists every ranked user, or only the viewer when private
+func (m *LeaderboardTabModel) updateTableRows() {
+	entries := m.leaderboard.Entries()
+
+	var teamCost float64
+	for _, entry := range entries {
+		teamCost += entry.Stats().TotalCost().Amount()
+	}
+
+	rows := make([]table.Row, 0, len(entries))
+	for _, entry := range entries {
+		isViewer := m.viewer != "" && strings.EqualFold(entry.User(), m.viewer)
+		if m.private && !isViewer {
+			continue
+		}
+
+		user := entry.User()
+		if isViewer {
+			user += leaderboardViewerMarker
+		}
+
+		share := "-"
+		if teamCost > 0 {
+			share = fmt.Sprintf("%.0f%%", entry.Stats().TotalCost().Amount()/teamCost*100)
+		}
+
+		rows = append(rows, table.Row{
+			fmt.Sprintf("#%d", entry.Rank()),
+			user,
+			fmt.Sprintf("%d", entry.Stats().TotalRequests()),
+			FormatTokenCount(entry.Stats().TotalTokens().Total()),
+			formatCost(m.costFormat, entry.Stats().TotalCost().Amount()),
+			share,
+		})
+	}
+
+	// Keep the cursor within the rows when users leave the period
+	if cursor := m.table.Cursor(); cursor >= len(rows) && len(rows) > 0 {
+		m.table.SetCursor(len(rows) - 1)
+	}
+	m.table.SetRows(rows)
+}
+
+// refreshLeaderboard ranks the users by their usage of the last 7 days
+func (m *LeaderboardTabModel) refreshLeaderboard() tea.Cmd {
+	query := m.getLeaderboardQuery
+	return func() tea.Msg {
+		if query == nil {
+			return LeaderboardDataMsg{}
+		}
+
+		period := entity.NewPeriodFromDuration(time.Now().UTC(), leaderboardPeriod)
+		leaderboard, err := query.Execute(context.Background(), usecase.GetLeaderboardParams{Period: period})
+		return LeaderboardDataMsg{Leaderboard: leaderboard, Err: err}
+	}
+}
+
+// leaderboardTableColumns returns the leaderboard table columns
+func leaderboardTableColumns(widths []int) []table.Column {
+	return []table.Column{
+		{Title: "Rank", Width: widths[0]},
+		{Title: "User", Width: widths[1]},
+		{Title: "Requests", Width: widths[2]},
+		{Title: "Tokens", Width: widths[3]},
+		{Title: "Cost ($)", Width: widths[4]},
+		{Title: "Share", Width: widths[5]},
+	}
+}
+
+// Message types for LeaderboardTabModel
+type LeaderboardRefreshMsg struct{}
+
+// LeaderboardDataMsg carries the users ranked by their weekly usage
+type LeaderboardDataMsg struct {
+	Leaderboard entity.Leaderboard
+	Err         error // set when the server is unreachable
+}
@@ -0,0 +1,150 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// createTestLeaderboard ranks bob, alice and carol by their cost
+func createTestLeaderboard() entity.Leaderboard {
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(3), 1000).WithUser("alice@example.com"),
+		entity.NewAPIRequest("session-2", timestamp, "claude-opus-4-20250514", entity.NewToken(3000, 1000, 0, 0), entity.NewCost(6), 1000).WithUser("bob@example.com"),
+		entity.NewAPIRequest("session-3", timestamp, "claude-sonnet-4-20250514", entity.NewToken(500, 500, 0, 0), entity.NewCost(1), 1000).WithUser("carol@example.com"),
+	}
+	return entity.NewLeaderboard(requests, entity.NewPeriodFromDuration(timestamp.Add(time.Hour), 7*24*time.Hour))
+}
+
+func TestLeaderboardTab_RanksUsers(t *testing.T) {
+	t.Parallel()
+
+	model := tui.NewLeaderboardTabModel(nil, "Alice@example.com", false)
+	model.SetSize(120, 40)
+	model.SetCostFormat(entity.DefaultCostFormat())
+	model.Update(tui.LeaderboardDataMsg{Leaderboard: createTestLeaderboard()})
+
+	rows := model.GetTable().Rows()
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 ranked users, got %d", len(rows))
+	}
+	expected := []string{"#1", "bob@example.com", "1", "4.0K", "6.00", "60%"}
+	for i, cell := range expected {
+		if rows[0][i] != cell {
+			t.Errorf("Expected cell %d to be %q, got %q", i, cell, rows[0][i])
+		}
+	}
+	if rows[1][1] != "alice@example.com (you)" {
+		t.Errorf("Expected the viewer to be marked, got %q", rows[1][1])
+	}
+
+	if view := model.View(); !strings.Contains(view, "3 users • Your rank: 2 of 3") {
+		t.Errorf("Expected the viewer's rank in view, got:\n%s", view)
+	}
+}
+
+func TestLeaderboardTab_Private(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		viewer       string
+		expectedRows int
+		expectedText string
+	}{
+		{
+			name:         "only the viewer's rank",
+			viewer:       "alice@example.com",
+			expectedRows: 1,
+			expectedText: "Your rank: 2 of 3",
+		},
+		{
+			name:         "viewer without requests",
+			viewer:       "dave@example.com",
+			expectedText: "No requests by dave@example.com in this period",
+		},
+		{
+			name:         "viewer not configured",
+			expectedText: "Set monitor.leaderboard.user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewLeaderboardTabModel(nil, tt.viewer, true)
+			model.SetSize(120, 40)
+			model.Update(tui.LeaderboardDataMsg{Leaderboard: createTestLeaderboard()})
+
+			if rows := model.GetTable().Rows(); len(rows) != tt.expectedRows {
+				t.Errorf("Expected %d rows, got %d", tt.expectedRows, len(rows))
+			}
+			if view := model.View(); !strings.Contains(view, tt.expectedText) {
+				t.Errorf("Expected view to contain %q, got:\n%s", tt.expectedText, view)
+			}
+		})
+	}
+}
+
+func TestLeaderboardTab_TogglePrivate(t *testing.T) {
+	t.Parallel()
+
+	model := tui.NewLeaderboardTabModel(nil, "alice@example.com", false)
+	model.SetSize(120, 40)
+	model.Update(tui.LeaderboardDataMsg{Leaderboard: createTestLeaderboard()})
+
+	p := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}
+	model.Update(p)
+	if !model.Private() || len(model.GetTable().Rows()) != 1 {
+		t.Errorf("Expected only the viewer's rank after toggling private, got %d rows", len(model.GetTable().Rows()))
+	}
+
+	model.Update(p)
+	if model.Private() || len(model.GetTable().Rows()) != 3 {
+		t.Errorf("Expected every rank after toggling private again, got %d rows", len(model.GetTable().Rows()))
+	}
+}
+
+func TestViewModel_LeaderboardTab(t *testing.T) {
+	t.Parallel()
+
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", time.Now().Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(3), 1000).WithUser("alice@example.com"),
+	}
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	vm := tui.NewViewModel(usecase.NewGetFilteredApiRequestsQuery(apiRepo), usecase.NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache()), CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.SetLeaderboard(usecase.NewGetLeaderboardQuery(apiRepo), "alice@example.com", false)
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Current, Daily Usage, Sessions and then Leaderboard
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+	var cmd tea.Cmd
+	for i := 0; i < 3; i++ {
+		_, cmd = vm.Update(tab)
+	}
+	if vm.CurrentTab() != tui.TabLeaderboard {
+		t.Fatalf("Expected the leaderboard tab, got %v", vm.CurrentTab())
+	}
+
+	// The tab refreshes through the view model, then loads the ranking
+	_, cmd = vm.Update(cmd())
+	vm.Update(cmd())
+
+	view := vm.View()
+	for _, text := range []string{"[Leaderboard]", "Team Leaderboard (Last 7 Days)", "alice@example.com (you)", "p=private"} {
+		if !strings.Contains(view, text) {
+			t.Errorf("Expected view to contain %q, got:\n%s", text, view)
+		}
+	}
+
+	vm.Update(tab)
+	if vm.CurrentTab() != tui.TabCurrent {
+		t.Errorf("Expected the current tab after the leaderboard, got %v", vm.CurrentTab())
+	}
+}
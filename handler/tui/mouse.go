@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mouseWheelRows is the number of rows a wheel notch moves the table cursor
+const mouseWheelRows = 3
+
+// updateTableMouse scrolls the table with the wheel and moves the cursor to the clicked row
+// The Y of the message is the line of the table view, the header included. Returns true if the cursor moved
+func updateTableMouse(t *table.Model, styles table.Styles, msg tea.MouseMsg) bool {
+	// Like the keys, a blurred table ignores the mouse
+	if !t.Focused() || len(t.Rows()) == 0 || msg.Action != tea.MouseActionPress {
+		return false
+	}
+
+	cursor := t.Cursor()
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		t.MoveUp(mouseWheelRows)
+	case tea.MouseButtonWheelDown:
+		t.MoveDown(mouseWheelRows)
+	case tea.MouseButtonLeft:
+		row, ok := tableRowAt(*t, styles, msg.Y)
+		if !ok {
+			return false
+		}
+		// Moving by the distance keeps the rows in place, setting the cursor would scroll the clicked row to the edge
+		if row < cursor {
+			t.MoveUp(cursor - row)
+		} else {
+			t.MoveDown(row - cursor)
+		}
+	default:
+		return false
+	}
+	return t.Cursor() != cursor
+}
+
+// tableRowAt returns the index of the row rendered on the line of the table view, false for the header and blank lines
+// The table does not expose how far it is scrolled, so the line of the selected row is located in its view instead
+func tableRowAt(t table.Model, styles table.Styles, line int) (int, bool) {
+	rows := t.Rows()
+	cursor := t.Cursor()
+	if cursor < 0 || cursor >= len(rows) {
+		return 0, false
+	}
+
+	// A table of the selected row alone renders it the same way
+	selected := table.New(
+		table.WithColumns(t.Columns()),
+		table.WithRows([]table.Row{rows[cursor]}),
+		table.WithStyles(styles),
+		table.WithWidth(t.Width()),
+	)
+	selectedLines := strings.Split(selected.View(), "\n")
+	headerHeight := len(selectedLines) - selected.Height()
+	if headerHeight < 0 || headerHeight >= len(selectedLines) || line < headerHeight {
+		return 0, false
+	}
+
+	lines := strings.Split(t.View(), "\n")
+	for i := headerHeight; i < len(lines); i++ {
+		if lines[i] != selectedLines[headerHeight] {
+			continue
+		}
+
+		row := cursor + line - i
+		if row < 0 || row >= len(rows) || line >= len(lines) {
+			return 0, false
+		}
+		return row, true
+	}
+	return 0, false
+}
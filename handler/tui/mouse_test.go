@@ -0,0 +1,152 @@
+package tui_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// screenPosition returns the line and column of the first line of the view containing text
+func screenPosition(t *testing.T, view, text string) (int, int) {
+	t.Helper()
+
+	for y, line := range strings.Split(view, "\n") {
+		if x := strings.Index(line, text); x >= 0 {
+			return y, x
+		}
+	}
+	t.Fatalf("Expected the view to contain %q, got:\n%s", text, view)
+	return 0, 0
+}
+
+// mouseRequests returns requests told apart by their input tokens, newest first
+func mouseRequests(count int) []entity.APIRequest {
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	requests := make([]entity.APIRequest, 0, count)
+	for i := 0; i < count; i++ {
+		requests = append(requests, entity.NewAPIRequest(fmt.Sprintf("session-%d", i), baseTime.Add(-time.Duration(i)*time.Minute),
+			"claude-sonnet-4-20250514", entity.NewToken(int64(10000+i), 500000, 0, 0), entity.NewCost(0.01), 1000))
+	}
+	return requests
+}
+
+func newMouseTestViewModel() *tui.ViewModel {
+	setupTestEnvironment()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	vm := tui.NewViewModel(usecase.NewGetFilteredApiRequestsQuery(apiRepo), usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Init()
+	vm.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	return vm
+}
+
+func press(x, y int, button tea.MouseButton) tea.MouseMsg {
+	return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: button}
+}
+
+func TestViewModel_MouseTabs(t *testing.T) {
+	vm := newMouseTestViewModel()
+
+	tests := []struct {
+		label    string
+		expected tui.Tab
+	}{
+		{label: "Daily Usage", expected: tui.TabDaily},
+		{label: "Sessions", expected: tui.TabSessions},
+		{label: "Current", expected: tui.TabCurrent},
+	}
+
+	for _, tt := range tests {
+		y, x := screenPosition(t, vm.View(), tt.label)
+		vm.Update(press(x, y, tea.MouseButtonLeft))
+		if vm.CurrentTab() != tt.expected {
+			t.Errorf("Expected clicking %q to switch to tab %v, got %v", tt.label, tt.expected, vm.CurrentTab())
+		}
+	}
+
+	// Only the left button switches, and the gap between the labels is no tab
+	y, x := screenPosition(t, vm.View(), "Daily Usage")
+	vm.Update(press(x, y, tea.MouseButtonRight))
+	vm.Update(press(x-2, y, tea.MouseButtonLeft))
+	vm.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+	if vm.CurrentTab() != tui.TabCurrent {
+		t.Errorf("Expected to stay on the current tab, got %v", vm.CurrentTab())
+	}
+}
+
+func TestViewModel_MouseRequestsTable(t *testing.T) {
+	vm := newMouseTestViewModel()
+	requests := mouseRequests(30)
+	vm.Update(tui.RequestsDataMsg{Requests: requests})
+
+	// The wheel moves the cursor wherever the pointer is
+	vm.Update(press(0, 0, tea.MouseButtonWheelDown))
+	if cursor := vm.Table().Cursor(); cursor != 3 {
+		t.Errorf("Expected the wheel to move the cursor to row 3, got %d", cursor)
+	}
+
+	// A click selects the row under the pointer, the rows stay in place
+	view := vm.View()
+	y, x := screenPosition(t, view, " 10008")
+	vm.Update(press(x, y, tea.MouseButtonLeft))
+	if cursor := vm.Table().Cursor(); cursor != 8 {
+		t.Errorf("Expected clicking the row to move the cursor to row 8, got %d", cursor)
+	}
+	if clickedY, _ := screenPosition(t, vm.View(), " 10008"); clickedY != y {
+		t.Errorf("Expected the clicked row to stay on line %d, got %d", y, clickedY)
+	}
+	y, x = screenPosition(t, vm.View(), " 10001")
+	vm.Update(press(x, y, tea.MouseButtonLeft))
+	if cursor := vm.Table().Cursor(); cursor != 1 {
+		t.Errorf("Expected clicking the row to move the cursor to row 1, got %d", cursor)
+	}
+
+	// Clicks outside the rows are ignored
+	y, x = screenPosition(t, vm.View(), "Recent API Requests")
+	vm.Update(press(x, y, tea.MouseButtonLeft))
+	vm.Update(press(x, y+1, tea.MouseButtonLeft)) // the table header
+	if cursor := vm.Table().Cursor(); cursor != 1 {
+		t.Errorf("Expected clicks above the rows to keep the cursor on row 1, got %d", cursor)
+	}
+
+	vm.Update(press(0, 0, tea.MouseButtonWheelUp))
+	if cursor := vm.Table().Cursor(); cursor != 0 {
+		t.Errorf("Expected the wheel to stop at the first row, got %d", cursor)
+	}
+
+	// Panels over the tab ignore the mouse
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	vm.Update(press(0, 0, tea.MouseButtonWheelDown))
+	if cursor := vm.Table().Cursor(); cursor != 0 {
+		t.Errorf("Expected the wheel to be ignored while the notification center is open, got row %d", cursor)
+	}
+}
+
+func TestViewModel_MouseDailyUsageTable(t *testing.T) {
+	vm := newMouseTestViewModel()
+
+	y, x := screenPosition(t, vm.View(), "Daily Usage")
+	vm.Update(press(x, y, tea.MouseButtonLeft))
+	usage := CreateTestUsage()
+	vm.Update(tui.UsageDataMsg{Usage: usage})
+
+	date := tui.FormatDate(usage.GetStats()[3].Period().StartAt())
+	y, x = screenPosition(t, vm.View(), date)
+	vm.Update(press(x, y, tea.MouseButtonLeft))
+	if cursor := vm.DailyUsageTab().GetTable().Cursor(); cursor != 3 {
+		t.Errorf("Expected clicking %s to move the cursor to row 3, got %d", date, cursor)
+	}
+
+	vm.Update(press(x, y, tea.MouseButtonWheelUp))
+	if cursor := vm.DailyUsageTab().GetTable().Cursor(); cursor != 0 {
+		t.Errorf("Expected the wheel to move the cursor to row 0, got %d", cursor)
+	}
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxNotifications is the number of notifications kept, older ones are dropped first
+const maxNotifications = 50
+
+// NotificationLevel represents how a notification is rendered
+type NotificationLevel int
+
+const (
+	NotificationInfo    NotificationLevel = iota // Informational event, e.g. a cleanup run
+	NotificationWarning                          // Event needing attention, e.g. an alert
+)
+
+// Notification represents an event shown in the notification center
+type Notification struct {
+	At      time.Time
+	Level   NotificationLevel
+	Message string
+}
+
+// NotificationCenterModel keeps the recent events so transient banners are not lost
+type NotificationCenterModel struct {
+	notifications []Notification // newest first
+	cursor        int
+	unread        int
+
+	timezone *time.Location
+	width    int
+	height   int
+}
+
+// NewNotificationCenterModel creates a new empty notification center
+func NewNotificationCenterModel(timezone *time.Location) *NotificationCenterModel {
+	return &NotificationCenterModel{
+		timezone: timezone,
+	}
+}
+
+// Init initializes the notification center
+func (m *NotificationCenterModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles navigation and dismiss keys of the notification center
+func (m *NotificationCenterModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	case NotificationMsg:
+		m.Notify(msg.Notification)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.notifications)-1 {
+				m.cursor++
+			}
+		case "x":
+			m.Dismiss()
+		case "c":
+			m.Clear()
+		}
+	}
+	return m, nil
+}
+
+// View renders the notifications, newest first
+func (m *NotificationCenterModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Notifications") + "\n\n")
+
+	if len(m.notifications) == 0 {
+		b.WriteString(HelpStyle.Render("  No notifications") + "\n")
+		return b.String()
+	}
+
+	visible, start := m.visibleNotifications()
+	for i, notification := range visible {
+		marker := "  "
+		if start+i == m.cursor {
+			marker = "▶ "
+		}
+
+		line := marker + FormatDateTime(notification.At.In(m.timezone)) + "  " + notification.Message
+		if notification.Level == NotificationWarning {
+			b.WriteString(WarningStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(StatusStyle.Render(line) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// visibleNotifications returns the notifications fitting the height and the index of the first one, the cursor is kept visible
+func (m *NotificationCenterModel) visibleNotifications() ([]Notification, int) {
+	// Title, tabs, panel header, help and footers take about 10 lines
+	rows := m.height - 10
+	if rows <= 0 || rows >= len(m.notifications) {
+		return m.notifications, 0
+	}
+
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+	return m.notifications[start : start+rows], start
+}
+
+// SetSize updates the size of the notification center
+func (m *NotificationCenterModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Notify adds a notification, counted as unread until the center is opened
+func (m *NotificationCenterModel) Notify(notification Notification) {
+	m.notifications = append([]Notification{notification}, m.notifications...)
+	if len(m.notifications) > maxNotifications {
+		m.notifications = m.notifications[:maxNotifications]
+	}
+	if m.cursor > 0 {
+		// Keep the cursor on the same notification
+		m.cursor = min(m.cursor+1, len(m.notifications)-1)
+	}
+	m.unread = min(m.unread+1, len(m.notifications))
+}
+
+// Dismiss removes the selected notification
+func (m *NotificationCenterModel) Dismiss() {
+	if len(m.notifications) == 0 {
+		return
+	}
+
+	m.notifications = append(m.notifications[:m.cursor], m.notifications[m.cursor+1:]...)
+	if m.cursor >= len(m.notifications) && m.cursor > 0 {
+		m.cursor--
+	}
+	m.unread = min(m.unread, len(m.notifications))
+}
+
+// Clear removes every notification
+func (m *NotificationCenterModel) Clear() {
+	m.notifications = nil
+	m.cursor = 0
+	m.unread = 0
+}
+
+// MarkRead marks every notification as read, called when the center is opened
+func (m *NotificationCenterModel) MarkRead() {
+	m.unread = 0
+}
+
+// Notifications returns the notifications, newest first
+func (m *NotificationCenterModel) Notifications() []Notification {
+	return m.notifications
+}
+
+// Unread returns the number of notifications added since the center was last opened
+func (m *NotificationCenterModel) Unread() int {
+	return m.unread
+}
+
+// NotificationMsg adds a notification to the notification center
+type NotificationMsg struct {
+	Notification Notification
+}
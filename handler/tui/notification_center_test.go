@@ -0,0 +1,146 @@
+package tui_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestNotificationCenter_DismissAndClear(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	center := tui.NewNotificationCenterModel(time.UTC)
+	center.SetSize(120, 40)
+	for i := 0; i < 3; i++ {
+		center.Notify(tui.Notification{At: at.Add(time.Duration(i) * time.Minute), Message: fmt.Sprintf("event %d", i)})
+	}
+
+	if center.Unread() != 3 {
+		t.Errorf("Expected 3 unread notifications, got %d", center.Unread())
+	}
+	if view := center.View(); !strings.Contains(view, "▶ 2025-01-01 10:02:00  event 2") {
+		t.Errorf("Expected the newest notification to be selected first, got %q", view)
+	}
+
+	// Dismiss the middle notification
+	center.Update(tea.KeyMsg{Type: tea.KeyDown})
+	center.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+	notifications := center.Notifications()
+	if len(notifications) != 2 || notifications[0].Message != "event 2" || notifications[1].Message != "event 0" {
+		t.Fatalf("Expected event 1 to be dismissed, got %v", notifications)
+	}
+	if view := center.View(); !strings.Contains(view, "▶ 2025-01-01 10:00:00  event 0") {
+		t.Errorf("Expected the cursor to move to the next notification, got %q", view)
+	}
+
+	center.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	if len(center.Notifications()) != 0 || center.Unread() != 0 {
+		t.Errorf("Expected every notification to be cleared, got %d", len(center.Notifications()))
+	}
+	if view := center.View(); !strings.Contains(view, "No notifications") {
+		t.Errorf("Expected the empty state, got %q", view)
+	}
+
+	// Dismissing without notifications is a no-op
+	center.Dismiss()
+}
+
+func TestNotificationCenter_KeepsRecentNotifications(t *testing.T) {
+	t.Parallel()
+
+	center := tui.NewNotificationCenterModel(time.UTC)
+	for i := 0; i < 60; i++ {
+		center.Notify(tui.Notification{At: time.Now(), Message: fmt.Sprintf("event %d", i)})
+	}
+
+	notifications := center.Notifications()
+	if len(notifications) != 50 {
+		t.Fatalf("Expected 50 notifications to be kept, got %d", len(notifications))
+	}
+	if notifications[0].Message != "event 59" || notifications[49].Message != "event 10" {
+		t.Errorf("Expected the latest notifications newest first, got %q to %q", notifications[0].Message, notifications[49].Message)
+	}
+	if center.Unread() != 50 {
+		t.Errorf("Expected unread count capped at 50, got %d", center.Unread())
+	}
+}
+
+func TestViewModel_Notifications(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	day := 24 * time.Hour
+	firstCleanupAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	messages := []tea.Msg{
+		tui.IngestionLagMsg{Lag: entity.NewIngestionLag(1, time.Second, time.Second)},
+		tui.IngestionLagMsg{Err: errors.New("connection refused")},
+		tui.RetentionMsg{Err: errors.New("connection refused")}, // already notified
+		tui.IngestionLagMsg{Lag: entity.NewIngestionLag(1, 2*time.Minute, 2*time.Minute)},
+		tui.IngestionLagMsg{Lag: entity.NewIngestionLag(2, 4*time.Minute, 3*time.Minute)}, // still above the threshold
+		tui.RetentionMsg{Retention: entity.NewRetention(30*day, firstCleanupAt)},
+		tui.RetentionMsg{Retention: entity.NewRetention(30*day, firstCleanupAt.Add(day))},
+		tui.StarredMsg{RequestID: "request-1", Err: errors.New("permission denied")},
+	}
+	for _, msg := range messages {
+		vm.Update(msg)
+	}
+
+	expected := []string{
+		"Star failed: permission denied",
+		"Retention cleanup ran, records before 2024-12-03 00:00 removed",
+		"Ingestion lag alert: avg 2m 0s, exporter may be buffering",
+		"Server reconnected",
+		"Server unreachable: connection refused",
+	}
+	notifications := vm.NotificationCenter().Notifications()
+	if len(notifications) != len(expected) {
+		t.Fatalf("Expected %d notifications, got %d: %v", len(expected), len(notifications), notifications)
+	}
+	for i, notification := range notifications {
+		if notification.Message != expected[i] {
+			t.Errorf("Notification %d: expected %q, got %q", i, expected[i], notification.Message)
+		}
+	}
+	if vm.IngestionLag().Samples() != 2 {
+		t.Errorf("Expected the latest ingestion lag to be kept, got %d samples", vm.IngestionLag().Samples())
+	}
+
+	if view := vm.View(); !strings.Contains(view, "🔔 5") {
+		t.Errorf("Expected the unread badge in the tab navigation")
+	}
+
+	// Opening the panel marks the notifications read and shows them over the tab
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if !vm.ShowNotifications() {
+		t.Fatal("Expected the notification center to open")
+	}
+	view := vm.View()
+	if !strings.Contains(view, "Server reconnected") || strings.Contains(view, "🔔") {
+		t.Errorf("Expected the open panel with read notifications, got %q", view)
+	}
+
+	// Keys are handled by the panel while it is open
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if len(vm.NotificationCenter().Notifications()) != len(expected)-1 {
+		t.Errorf("Expected the selected notification to be dismissed")
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if vm.ShowNotifications() {
+		t.Error("Expected esc to close the notification center")
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -9,18 +10,28 @@ import (
 	"github.com/elct9620/ccmon/usecase"
 )
 
+// hotSessionsLimit is the number of fastest-burning sessions listed in the overview
+const hotSessionsLimit = 3
+
 // OverviewTabModel handles the current/overview tab that shows stats and requests table
 type OverviewTabModel struct {
 	statsModel         *StatsModel
 	requestsTableModel *RequestsTableModel
 	width              int
 	height             int
+
+	// Session titles from Claude Code transcripts, hot sessions are listed by ID without them
+	sessionTitlesQuery *usecase.GetSessionTitlesQuery
+	sessionTitles      entity.SessionTitles
 }
 
 // NewOverviewTabModel creates a new overview tab model
 func NewOverviewTabModel(calculateStatsQuery *usecase.CalculateStatsQuery, getFilteredQuery *usecase.GetFilteredApiRequestsQuery, timezone *time.Location, block *entity.Block) *OverviewTabModel {
+	statsModel := NewStatsModel(calculateStatsQuery, timezone, block)
+	statsModel.SetFilteredQuery(getFilteredQuery)
+
 	return &OverviewTabModel{
-		statsModel:         NewStatsModel(calculateStatsQuery, timezone, block),
+		statsModel:         statsModel,
 		requestsTableModel: NewRequestsTableModel(getFilteredQuery, timezone),
 		width:              120,
 		height:             30,
@@ -65,12 +76,41 @@ func (m *OverviewTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 		}
 
 	case RequestsDataMsg:
+		// Hot sessions are derived from the requests shown in the table
+		hotSessions := entity.HotSessions(entity.NewSessionsFromRequests(msg.Requests), hotSessionsLimit)
+		m.statsModel.SetHotSessions(m.sessionTitles.Apply(hotSessions))
+		if cmd := m.refreshSessionTitles(hotSessions); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 		// Forward requests data to table model
 		_, cmd := m.requestsTableModel.Update(msg)
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
 
+	case SessionTitlesMsg:
+		// Titles are cosmetic, keep the last known ones when the lookup fails
+		if msg.Err == nil {
+			m.sessionTitles = msg.Titles
+			m.statsModel.SetHotSessions(m.sessionTitles.Apply(m.statsModel.HotSessions()))
+		}
+
+	case StarredMsg:
+		// Forward star outcome to table model
+		_, cmd := m.requestsTableModel.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case tea.MouseMsg:
+		// The Y of the message is the line of the tab view, the table starts below the stats box
+		msg.Y -= m.tableTop()
+		_, cmd := m.requestsTableModel.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case tea.KeyMsg:
 		// Handle keyboard input - mainly for table navigation
 		switch msg.String() {
@@ -81,6 +121,8 @@ func (m *OverviewTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 			} else {
 				m.requestsTableModel.Focus()
 			}
+		case "s":
+			m.statsModel.ToggleBreakdown()
 		default:
 			// Forward other key messages to table model
 			_, cmd := m.requestsTableModel.Update(msg)
@@ -96,6 +138,23 @@ func (m *OverviewTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 // View renders the overview tab
 func (m *OverviewTabModel) View() string {
 	var b strings.Builder
+	b.WriteString(m.headerView())
+
+	// Table
+	tableView := m.requestsTableModel.View()
+	b.WriteString(tableView + "\n")
+
+	return b.String()
+}
+
+// tableTop returns the line of the tab view where the requests table starts
+func (m *OverviewTabModel) tableTop() int {
+	return strings.Count(m.headerView(), "\n")
+}
+
+// headerView renders the stats box and the requests header above the table
+func (m *OverviewTabModel) headerView() string {
+	var b strings.Builder
 
 	// Statistics box
 	statsContent := m.statsModel.View()
@@ -106,10 +165,6 @@ func (m *OverviewTabModel) View() string {
 	requestsHeader := HeaderStyle.Render("Recent API Requests")
 	b.WriteString(requestsHeader + "\n")
 
-	// Table
-	tableView := m.requestsTableModel.View()
-	b.WriteString(tableView + "\n")
-
 	return b.String()
 }
 
@@ -123,20 +178,72 @@ func (m *OverviewTabModel) SetSize(width, height int) {
 	m.requestsTableModel.SetSize(width, height)
 }
 
-// RefreshStats triggers a stats refresh with the given period
-func (m *OverviewTabModel) RefreshStats(period entity.Period) tea.Cmd {
-	msg := StatsRefreshMsg{Period: period}
+// RefreshStats triggers a stats refresh with the given period, approximate allows estimated stats first
+func (m *OverviewTabModel) RefreshStats(period entity.Period, approximate bool) tea.Cmd {
+	msg := StatsRefreshMsg{Period: period, Approximate: approximate}
+	_, cmd := m.statsModel.Update(msg)
+	return cmd
+}
+
+// ForceRefreshStats triggers a stats refresh with the given period which skips the cached stats
+func (m *OverviewTabModel) ForceRefreshStats(period entity.Period) tea.Cmd {
+	msg := StatsRefreshMsg{Period: period, Fresh: true}
 	_, cmd := m.statsModel.Update(msg)
 	return cmd
 }
 
-// RefreshRequests triggers a requests refresh with the given period and sort order
-func (m *OverviewTabModel) RefreshRequests(period entity.Period, sortOrder SortOrder) tea.Cmd {
-	msg := RequestsRefreshMsg{Period: period, SortOrder: sortOrder}
+// RefreshRequests triggers a requests refresh with the given filter and sort order
+func (m *OverviewTabModel) RefreshRequests(filter entity.Filter, sortOrder SortOrder) tea.Cmd {
+	msg := RequestsRefreshMsg{Filter: filter, SortOrder: sortOrder}
 	_, cmd := m.requestsTableModel.Update(msg)
 	return cmd
 }
 
+// SetHighlight sets the thresholds above which requests are highlighted in the table
+func (m *OverviewTabModel) SetHighlight(highlight entity.Highlight) {
+	m.requestsTableModel.SetHighlight(highlight)
+}
+
+// SetCostFormat sets the display format of the costs in the stats box and the requests table
+func (m *OverviewTabModel) SetCostFormat(format entity.CostFormat) {
+	m.statsModel.SetCostFormat(format)
+	m.requestsTableModel.SetCostFormat(format)
+}
+
+// SetStreak updates the daily goal streak shown in the stats box
+func (m *OverviewTabModel) SetStreak(goal entity.Goal, streak entity.Streak) {
+	m.statsModel.SetGoal(goal)
+	m.statsModel.SetStreak(streak)
+}
+
+// SetStarCommand enables starring requests in the requests table
+func (m *OverviewTabModel) SetStarCommand(starCommand *usecase.StarApiRequestCommand) {
+	m.requestsTableModel.SetStarCommand(starCommand)
+}
+
+// SetSessionTitlesQuery enables listing hot sessions by their transcript titles
+func (m *OverviewTabModel) SetSessionTitlesQuery(sessionTitlesQuery *usecase.GetSessionTitlesQuery) {
+	m.sessionTitlesQuery = sessionTitlesQuery
+}
+
+// refreshSessionTitles looks up the titles of the sessions in the background
+func (m *OverviewTabModel) refreshSessionTitles(sessions []entity.Session) tea.Cmd {
+	if m.sessionTitlesQuery == nil || len(sessions) == 0 {
+		return nil
+	}
+
+	sessionIDs := make([]string, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID()
+	}
+
+	query := m.sessionTitlesQuery
+	return func() tea.Msg {
+		titles, err := query.Execute(context.Background(), sessionIDs)
+		return SessionTitlesMsg{Titles: titles, Err: err}
+	}
+}
+
 // GetRequestsTable returns the requests table model for external access
 func (m *OverviewTabModel) GetRequestsTable() *RequestsTableModel {
 	return m.requestsTableModel
@@ -156,3 +263,9 @@ func (m *OverviewTabModel) Blur() {
 func (m *OverviewTabModel) Focused() bool {
 	return m.requestsTableModel.Focused()
 }
+
+// SessionTitlesMsg carries the titles of the hot sessions read from Claude Code transcripts
+type SessionTitlesMsg struct {
+	Titles entity.SessionTitles
+	Err    error
+}
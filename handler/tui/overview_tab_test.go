@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
@@ -213,3 +214,382 @@ func TestOverviewTab_TimeFiltering(t *testing.T) {
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 	})
 }
+
+// TestOverviewTab_HotSessions tests that the fastest-burning sessions are listed below the stats
+func TestOverviewTab_HotSessions(t *testing.T) {
+	baseTime := time.Now().Add(-10 * time.Minute)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("hot-session-1", baseTime, "claude-sonnet-4-20250514", entity.NewToken(5000, 1000, 0, 0), entity.NewCost(0.5), 1000),
+		entity.NewAPIRequest("hot-session-1", baseTime.Add(2*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(5000, 1000, 0, 0), entity.NewCost(0.5), 1000),
+		entity.NewAPIRequest("cool-session", baseTime, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+		entity.NewAPIRequest("cool-session", baseTime.Add(8*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		requests []entity.APIRequest
+		expected []string
+		hidden   bool
+	}{
+		{
+			name:     "sessions sorted by burn rate",
+			requests: requests,
+			expected: []string{"Hot Sessions:", "hot-s...", "6.0K/min"},
+		},
+		{
+			name:     "no requests hides hot sessions",
+			requests: []entity.APIRequest{},
+			hidden:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewOverviewTabModel(nil, nil, time.UTC, nil)
+			model.SetSize(140, 40)
+			model.Update(tui.RequestsDataMsg{Requests: tt.requests})
+
+			view := model.View()
+			if tt.hidden {
+				if strings.Contains(view, "Hot Sessions:") {
+					t.Errorf("Expected no hot sessions in view, got:\n%s", view)
+				}
+				return
+			}
+
+			for _, want := range tt.expected {
+				if !strings.Contains(view, want) {
+					t.Errorf("Expected view to contain %q, got:\n%s", want, view)
+				}
+			}
+			if strings.Index(view, "hot-s...") > strings.Index(view, "cool-...") {
+				t.Errorf("Expected hot session to be listed before cool session")
+			}
+		})
+	}
+}
+
+func TestOverviewTab_OutputSplit(t *testing.T) {
+	now := time.Now()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+
+	tests := []struct {
+		name     string
+		requests []entity.APIRequest
+		expected string
+		hidden   bool
+	}{
+		{
+			name: "tool use tokens reported",
+			requests: []entity.APIRequest{
+				entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(5000, 4000, 0, 0).WithToolUse(3000), entity.NewCost(0.5), 1000),
+			},
+			expected: "3.0K tool use • 1.0K text (75% tool use)",
+		},
+		{
+			name: "no tool use tokens hides the split",
+			requests: []entity.APIRequest{
+				entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(5000, 4000, 0, 0), entity.NewCost(0.5), 1000),
+			},
+			hidden: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewOverviewTabModel(nil, nil, time.UTC, nil)
+			model.SetSize(140, 40)
+			model.Update(tui.StatsDataMsg{Stats: entity.NewStatsFromRequests(tt.requests, period)})
+
+			view := model.View()
+			if tt.hidden {
+				if strings.Contains(view, "tool use") {
+					t.Errorf("Expected no output split in view, got:\n%s", view)
+				}
+				return
+			}
+
+			if !strings.Contains(view, tt.expected) {
+				t.Errorf("Expected view to contain %q, got:\n%s", tt.expected, view)
+			}
+		})
+	}
+}
+
+func TestOverviewTab_ModelBreakdown(t *testing.T) {
+	now := time.Now()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(5000, 4000, 0, 0), entity.NewCost(0.5), 1000),
+		entity.NewAPIRequest("session-1", now, "claude-opus-4-20250514", entity.NewToken(5000, 4000, 0, 0), entity.NewCost(2.5), 1000),
+	}
+
+	tests := []struct {
+		name     string
+		width    int
+		stats    entity.Stats
+		expected []string
+	}{
+		{
+			name:     "models listed by cost",
+			width:    140,
+			stats:    entity.NewStatsFromRequests(requests, period).WithModels(entity.NewModelStatsFromRequests(requests)),
+			expected: []string{"Model ", "claude-opus-4-20250514", "claude-sonnet-4-20250514"},
+		},
+		{
+			name:     "compact models",
+			width:    60,
+			stats:    entity.NewStatsFromRequests(requests, period).WithModels(entity.NewModelStatsFromRequests(requests)),
+			expected: []string{"claude-opus-4-20250514: 1 reqs, 9.0K tokens, $2.50"},
+		},
+		{
+			name:     "server without the breakdown",
+			width:    140,
+			stats:    entity.NewStatsFromRequests(requests, period),
+			expected: []string{"The server predates the per-model breakdown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewOverviewTabModel(nil, nil, time.UTC, nil)
+			model.SetSize(tt.width, 40)
+			model.Update(tui.StatsDataMsg{Stats: tt.stats})
+
+			if strings.Contains(model.View(), "claude-opus-4-20250514") {
+				t.Error("Expected model tiers before toggling the breakdown")
+			}
+
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+			view := model.View()
+			for _, expected := range tt.expected {
+				if !strings.Contains(view, expected) {
+					t.Errorf("Expected view to contain %q, got:\n%s", expected, view)
+				}
+			}
+			opus, sonnet := strings.Index(view, "claude-opus-4-20250514"), strings.Index(view, "claude-sonnet-4-20250514")
+			if opus > sonnet {
+				t.Error("Expected the most expensive model first")
+			}
+
+			model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+			if !strings.Contains(model.View(), "Premium") {
+				t.Error("Expected model tiers after toggling the breakdown back")
+			}
+		})
+	}
+}
+
+func TestOverviewTab_Goal(t *testing.T) {
+	now := time.Now()
+	day := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	dayStats := func(cost float64) entity.Stats {
+		request := entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(cost), 1000)
+		return entity.NewStatsFromRequests([]entity.APIRequest{request}, day)
+	}
+
+	tests := []struct {
+		name       string
+		goal       entity.Goal
+		dailyStats []entity.Stats
+		expected   []string
+		hidden     bool
+	}{
+		{
+			name:       "streak under the goal",
+			goal:       entity.NewGoal(entity.NewCost(5), 0),
+			dailyStats: []entity.Stats{dayStats(1.2), dayStats(3), dayStats(8)},
+			expected:   []string{"Daily Goal:", "$1.20 of $5.00 today", "🔥 2 days under goal"},
+		},
+		{
+			name:       "token goal progress",
+			goal:       entity.NewGoal(entity.NewCost(5), 10000),
+			dailyStats: []entity.Stats{dayStats(1.2)},
+			expected:   []string{"$1.20 of $5.00 • 1.5K of 10.0K tokens today"},
+		},
+		{
+			name:       "today over the goal",
+			goal:       entity.NewGoal(entity.NewCost(5), 0),
+			dailyStats: []entity.Stats{dayStats(6), dayStats(1)},
+			expected:   []string{"$6.00 of $5.00 today", "goal exceeded, streak reset"},
+		},
+		{
+			name:   "no goal hides the widget",
+			goal:   entity.NewGoal(entity.NewCost(0), 0),
+			hidden: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewOverviewTabModel(nil, nil, time.UTC, nil)
+			model.SetSize(140, 40)
+			model.SetCostFormat(entity.DefaultCostFormat())
+			model.SetStreak(tt.goal, tt.goal.CalculateStreak(tt.dailyStats))
+
+			view := model.View()
+			if tt.hidden {
+				if strings.Contains(view, "Daily Goal:") {
+					t.Errorf("Expected no goal widget in view, got:\n%s", view)
+				}
+				return
+			}
+
+			for _, want := range tt.expected {
+				if !strings.Contains(view, want) {
+					t.Errorf("Expected view to contain %q, got:\n%s", want, view)
+				}
+			}
+		})
+	}
+}
+
+func TestOverviewTab_ApproximateStats(t *testing.T) {
+	now := time.Now()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.5), 1000),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	model := tui.NewOverviewTabModel(calculateStatsQuery, nil, time.UTC, nil)
+	model.SetSize(140, 40)
+
+	msg := model.RefreshStats(period, true)()
+	data, ok := msg.(tui.StatsDataMsg)
+	if !ok || !data.Approximate {
+		t.Fatalf("Expected approximate StatsDataMsg, got %#v", msg)
+	}
+
+	_, refine := model.Update(data)
+	if !strings.Contains(model.View(), "estimating") {
+		t.Errorf("Expected estimating marker in view, got:\n%s", model.View())
+	}
+	if refine == nil {
+		t.Fatal("Expected an exact refresh after approximate stats")
+	}
+
+	exact, ok := refine().(tui.StatsDataMsg)
+	if !ok || exact.Approximate {
+		t.Fatalf("Expected exact StatsDataMsg, got %#v", exact)
+	}
+	model.Update(exact)
+	if strings.Contains(model.View(), "estimating") {
+		t.Errorf("Expected no estimating marker after refinement, got:\n%s", model.View())
+	}
+
+	// Estimates of an outdated refresh are dropped
+	stale := data
+	stale.Period = entity.NewPeriodFromDuration(now, time.Hour)
+	if _, cmd := model.Update(stale); cmd != nil {
+		t.Error("Expected no refresh for outdated estimates")
+	}
+	if strings.Contains(model.View(), "estimating") {
+		t.Errorf("Expected outdated estimates to be ignored, got:\n%s", model.View())
+	}
+}
+
+func TestOverviewTab_HotSessionTitles(t *testing.T) {
+	baseTime := time.Now().Add(-10 * time.Minute)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("5f3a1c2e-hot", baseTime, "claude-sonnet-4-20250514", entity.NewToken(5000, 1000, 0, 0), entity.NewCost(0.5), 1000),
+		entity.NewAPIRequest("5f3a1c2e-hot", baseTime.Add(2*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(5000, 1000, 0, 0), entity.NewCost(0.5), 1000),
+	}
+	titleRepo := testutil.NewMockSessionTitleRepository(entity.SessionTitles{
+		"5f3a1c2e-hot": entity.NewSessionTitle("Refactor billing module", "/home/alice/billing"),
+	})
+
+	model := tui.NewOverviewTabModel(nil, nil, time.UTC, nil)
+	model.SetSize(140, 40)
+	model.SetSessionTitlesQuery(usecase.NewGetSessionTitlesQuery(titleRepo))
+
+	_, cmd := model.Update(tui.RequestsDataMsg{Requests: requests})
+	if cmd == nil {
+		t.Fatal("Expected a session titles lookup")
+	}
+	if !strings.Contains(model.View(), "5f3a1...") {
+		t.Errorf("Expected the session ID before titles are loaded, got:\n%s", model.View())
+	}
+
+	titlesMsg, ok := cmd().(tui.SessionTitlesMsg)
+	if !ok {
+		t.Fatal("Expected a SessionTitlesMsg")
+	}
+
+	model.Update(titlesMsg)
+	if !strings.Contains(model.View(), "Refactor billing module") {
+		t.Errorf("Expected the session title in hot sessions, got:\n%s", model.View())
+	}
+}
+
+func TestOverviewTab_BlockModelBreakdown(t *testing.T) {
+	now := time.Now()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-10*time.Minute), "claude-opus-4-1-20250805", entity.NewToken(6000, 3000, 0, 0), entity.NewCost(0.5), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-5*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(2000, 1000, 0, 0), entity.NewCost(0.1), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-5*time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(9000, 9000, 0, 0), entity.NewCost(0.01), 1000),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	block := entity.NewBlockWithLimit(now.Add(-time.Hour), 24000)
+
+	model := tui.NewOverviewTabModel(calculateStatsQuery, getFilteredQuery, time.UTC, &block)
+	model.SetSize(140, 40)
+
+	data, ok := model.RefreshStats(period, false)().(tui.StatsDataMsg)
+	if !ok {
+		t.Fatal("Expected StatsDataMsg")
+	}
+	if len(data.BlockModels) != 2 {
+		t.Fatalf("Expected 2 model families in the block, got %d", len(data.BlockModels))
+	}
+	model.Update(data)
+
+	view := model.View()
+	for _, want := range []string{"■ opus 9.0K (75%)", "■ sonnet 3.0K (25%)", "50.0% (12.0K/24.0K tokens)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q, got:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "haiku") {
+		t.Errorf("Expected base models to be left out of the breakdown, got:\n%s", view)
+	}
+}
+
+func TestOverviewTab_BlockProjection(t *testing.T) {
+	now := time.Now()
+	period := entity.NewPeriodFromDuration(now, 24*time.Hour)
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-10*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(8000, 4000, 0, 0), entity.NewCost(0.1), 1000),
+	})
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		// 12K in the first hour is 200 tokens a minute, 60K by the block end
+		{name: "over the limit", limit: 24000, want: "projected 250% at block end • limit in"},
+		{name: "within the limit", limit: 120000, want: "Burn rate: 200.0/min • projected 50% at block end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := entity.NewBlockWithLimit(now.Add(-time.Hour), tt.limit)
+			model := tui.NewOverviewTabModel(calculateStatsQuery, nil, time.UTC, &block)
+			model.SetSize(140, 40)
+
+			data, ok := model.RefreshStats(period, false)().(tui.StatsDataMsg)
+			if !ok {
+				t.Fatal("Expected StatsDataMsg")
+			}
+			model.Update(data)
+
+			if view := model.View(); !strings.Contains(view, tt.want) {
+				t.Errorf("Expected view to contain %q, got:\n%s", tt.want, view)
+			}
+		})
+	}
+}
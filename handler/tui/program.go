@@ -16,10 +16,28 @@ type MonitorConfig struct {
 	RefreshInterval string
 	TokenLimit      int
 	BlockTime       string
+	BlockDuration   time.Duration // length of the blocks, 0 uses 5 hours
+	AltScreen       bool
+	Mouse           bool // wheel scrolling and clicking rows and tabs
+	CostFormat      entity.CostFormat
+	TimeFormat      entity.TimeFormat
+	Highlight       entity.Highlight
+	Filter          entity.Filter
+	Goal            entity.Goal
+	CostAnomaly     entity.CostAnomalyPolicy // days above the multiple of their 7-day baseline are marked
+	Leaderboard     MonitorLeaderboard
+	Keys            map[string][]string // keys of the rebound actions, e.g. "filter_hour" to ["H"]
+}
+
+// MonitorLeaderboard represents the leaderboard tab configuration, the tab is hidden unless enabled
+type MonitorLeaderboard struct {
+	Enabled bool
+	Viewer  string // user of this monitor, highlighted in the ranking
+	Private bool   // start with only the viewer's rank shown
 }
 
 // RunMonitor runs the TUI monitor mode with usecases and config
-func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, monitorConfig MonitorConfig) error {
+func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, getIngestionLagQuery *usecase.GetIngestionLagQuery, getIngestStatsQuery *usecase.GetIngestStatsQuery, getRetentionQuery *usecase.GetRetentionQuery, watchQuery *usecase.WatchApiRequestsQuery, starCommand *usecase.StarApiRequestCommand, getSessionTitlesQuery *usecase.GetSessionTitlesQuery, getLeaderboardQuery *usecase.GetLeaderboardQuery, monitorConfig MonitorConfig) error {
 	// Load timezone for monitor mode
 	timezone, err := time.LoadLocation(monitorConfig.Timezone)
 	if err != nil {
@@ -53,15 +71,52 @@ func RunMonitor(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculate
 		}
 
 		// Create current block with token limit based on user's start hour
-		blockEntity := calculateCurrentBlock(startHour, timezone, time.Now(), monitorConfig.TokenLimit)
+		blockEntity := calculateCurrentBlock(startHour, timezone, time.Now(), monitorConfig.TokenLimit, monitorConfig.BlockDuration)
 		block = &blockEntity
 	}
 
+	keys, err := NewKeyMap(monitorConfig.Keys)
+	if err != nil {
+		return fmt.Errorf("invalid monitor keys: %w", err)
+	}
+
+	// Apply the time display format to all components
+	SetTimeFormat(monitorConfig.TimeFormat)
+
 	// Create the view model (which now implements tea.Model directly)
 	model := NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, timezone, block, refreshInterval)
+	model.SetIngestionLagQuery(getIngestionLagQuery)
+	model.SetIngestStatsQuery(getIngestStatsQuery)
+	model.SetRetentionQuery(getRetentionQuery)
+	model.SetWatchQuery(watchQuery)
+	model.SetStarCommand(starCommand)
+	model.SetSessionTitlesQuery(getSessionTitlesQuery)
+	model.SetAltScreen(monitorConfig.AltScreen)
+	model.SetKeyMap(keys)
+	model.SetHighlight(monitorConfig.Highlight)
+	model.SetCostFormat(monitorConfig.CostFormat)
+	model.SetRequestFilter(monitorConfig.Filter)
+	model.SetStreakQuery(usecase.NewGetStreakQuery(getUsageQuery, monitorConfig.Goal, timezone))
+	model.SetCostAnomalyPolicy(monitorConfig.CostAnomaly)
+	if block != nil {
+		model.SetBlockHistoryQuery(usecase.NewGetBlockHistoryQuery(getUsageQuery, *block))
+	}
+	if monitorConfig.Leaderboard.Enabled {
+		model.SetLeaderboard(getLeaderboardQuery, monitorConfig.Leaderboard.Viewer, monitorConfig.Leaderboard.Private)
+	}
+
+	// Inline mode keeps the output in the terminal scrollback
+	var options []tea.ProgramOption
+	if monitorConfig.AltScreen {
+		options = append(options, tea.WithAltScreen())
+	}
+	// Cell motion only reports the mouse while a button is held, idle movement doesn't wake the monitor
+	if monitorConfig.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
 
 	// Create and run the Bubble Tea program
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	p := tea.NewProgram(model, options...)
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("error running TUI: %w", err)
 	}
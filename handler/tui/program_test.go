@@ -2,6 +2,8 @@ package tui_test
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -441,8 +443,8 @@ func TestHelperFunctions(t *testing.T) {
 
 	t.Run("CalculateStatsColumnWidths", func(t *testing.T) {
 		widths := tui.CalculateStatsColumnWidths(100)
-		if len(widths) != 7 {
-			t.Errorf("Expected 7 column widths, got %d", len(widths))
+		if len(widths) != 8 {
+			t.Errorf("Expected 8 column widths, got %d", len(widths))
 		}
 	})
 
@@ -510,6 +512,323 @@ func TestViewModel_GetterMethods(t *testing.T) {
 	}
 }
 
+// TestViewModel_AltScreen tests the alternate screen setting
+func TestViewModel_AltScreen(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	if !vm.AltScreen() {
+		t.Error("Expected alt screen to be enabled by default")
+	}
+
+	vm.SetAltScreen(false)
+	if vm.AltScreen() {
+		t.Error("Expected alt screen to be disabled")
+	}
+
+	// Inline mode still renders the full monitor
+	tm := teatest.NewTestModel(t, vm, teatest.WithInitialTermSize(120, 40))
+	teatest.WaitFor(t, tm.Output(), func(bts []byte) bool {
+		return bytes.Contains(bts, []byte("Claude Code Monitor"))
+	}, teatest.WithCheckInterval(50*time.Millisecond), teatest.WithDuration(3*time.Second))
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
+// TestViewModel_IngestionLagFooter tests the ingestion lag footer rendering
+func TestViewModel_IngestionLagFooter(t *testing.T) {
+	testCases := []struct {
+		name            string
+		lag             entity.IngestionLag
+		expectedText    []string
+		notExpectedText []string
+	}{
+		{
+			name:            "no lag reported",
+			lag:             entity.IngestionLag{},
+			notExpectedText: []string{"Ingestion lag"},
+		},
+		{
+			name:            "small lag",
+			lag:             entity.NewIngestionLag(2, 4*time.Second, 3*time.Second),
+			expectedText:    []string{"Ingestion lag: avg 2s • max 3s"},
+			notExpectedText: []string{"exporter may be buffering"},
+		},
+		{
+			name:         "large lag warns about buffering",
+			lag:          entity.NewIngestionLag(2, 10*time.Minute, 8*time.Minute),
+			expectedText: []string{"Ingestion lag: avg 5m 0s • max 8m 0s", "exporter may be buffering"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+			vm.SetIngestionLagQuery(usecase.NewGetIngestionLagQuery(testutil.NewMockIngestionLagRepository(tc.lag)))
+			vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			vm.Update(tui.IngestionLagMsg{Lag: tc.lag})
+
+			if vm.IngestionLag().Samples() != tc.lag.Samples() {
+				t.Errorf("Expected %d lag samples, got %d", tc.lag.Samples(), vm.IngestionLag().Samples())
+			}
+
+			view := vm.View()
+			for _, text := range tc.expectedText {
+				if !strings.Contains(view, text) {
+					t.Errorf("Expected view to contain %q", text)
+				}
+			}
+			for _, text := range tc.notExpectedText {
+				if strings.Contains(view, text) {
+					t.Errorf("Expected view not to contain %q", text)
+				}
+			}
+		})
+	}
+}
+
+// TestViewModel_RetentionFooter tests the retention preview footer rendering
+func TestViewModel_RetentionFooter(t *testing.T) {
+	// Half a minute of slack keeps the countdown stable while the test runs
+	nextCleanupAt := time.Now().Add(3*time.Hour + 30*time.Second)
+	cutoff := nextCleanupAt.Add(-30 * 24 * time.Hour).UTC().Format("2006-01-02 15:04")
+
+	testCases := []struct {
+		name            string
+		retention       entity.Retention
+		expectedText    []string
+		notExpectedText []string
+	}{
+		{
+			name:            "retention disabled",
+			retention:       entity.NewRetention(0, nextCleanupAt),
+			notExpectedText: []string{"Retention:"},
+		},
+		{
+			name:         "first cleanup pending",
+			retention:    entity.NewRetention(30*24*time.Hour, time.Time{}),
+			expectedText: []string{"Retention: 30d • first cleanup pending"},
+		},
+		{
+			name:         "next cleanup scheduled",
+			retention:    entity.NewRetention(30*24*time.Hour, nextCleanupAt),
+			expectedText: []string{"Retention: 30d • records before " + cutoff + " will be removed in 3h 0m"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+			getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+			calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+			vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+			vm.SetRetentionQuery(usecase.NewGetRetentionQuery(testutil.NewMockRetentionRepository(tc.retention)))
+			vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			vm.Update(tui.RetentionMsg{Retention: tc.retention})
+
+			if vm.Retention().Duration() != tc.retention.Duration() {
+				t.Errorf("Expected retention %v, got %v", tc.retention.Duration(), vm.Retention().Duration())
+			}
+
+			view := vm.View()
+			for _, text := range tc.expectedText {
+				if !strings.Contains(view, text) {
+					t.Errorf("Expected view to contain %q", text)
+				}
+			}
+			for _, text := range tc.notExpectedText {
+				if strings.Contains(view, text) {
+					t.Errorf("Expected view not to contain %q", text)
+				}
+			}
+		})
+	}
+}
+
+// TestViewModel_WatchRequests tests refreshing on pushed requests and falling back to polling
+func TestViewModel_WatchRequests(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	watchRepo := testutil.NewMockAPIRequestWatchRepository(CreateTestRequestsSet())
+	watchRepo.SetError(errors.New("method WatchAPIRequests not implemented"))
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.SetWatchQuery(usecase.NewWatchApiRequestsQuery(watchRepo))
+	vm.Init()
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if !vm.Watching() {
+		t.Fatal("Expected the monitor to watch pushed requests")
+	}
+	if !strings.Contains(vm.View(), "| Live") {
+		t.Error("Expected the status line to show live updates")
+	}
+
+	// Pushed requests keep waiting for the stream and schedule a refresh
+	if _, cmd := vm.Update(tui.RequestsPushedMsg{Count: 3}); cmd == nil {
+		t.Error("Expected a command after pushed requests")
+	}
+
+	// The stream ended, the monitor polls again
+	vm.Update(tui.WatchEndedMsg{Err: errors.New("method WatchAPIRequests not implemented")})
+	if vm.Watching() {
+		t.Error("Expected the monitor to stop watching after the stream ended")
+	}
+	if strings.Contains(vm.View(), "| Live") {
+		t.Error("Expected the status line not to show live updates")
+	}
+}
+
+// TestViewModel_ForceRefresh tests refreshing the stats past the cache and showing their freshness
+func TestViewModel_ForceRefresh(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData(CreateTestRequestsSet())
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+
+	stale := CreateEmptyStats().WithCachedAt(time.Now().Add(-42 * time.Second))
+	cache := testutil.NewMockStatsCacheWithData(func(entity.Period) *entity.Stats { return &stale })
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, cache)
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	// runCmd executes the command and feeds the resulting messages back like the program does
+	var runCmd func(cmd tea.Cmd)
+	runCmd = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case nil:
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				runCmd(cmd)
+			}
+		default:
+			_, next := vm.Update(msg)
+			runCmd(next)
+		}
+	}
+
+	_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	runCmd(cmd)
+	if !vm.Stats().IsCached() {
+		t.Fatal("Expected the stats to be served from the cache")
+	}
+	if !strings.Contains(vm.View(), "Stats: cached 42s ago") {
+		t.Error("Expected the status line to show the age of the cached stats")
+	}
+
+	_, cmd = vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	runCmd(cmd)
+	if vm.Stats().IsCached() || vm.Stats().TotalRequests() == 0 {
+		t.Errorf("Expected fresh stats of the requests, got cached=%v requests=%d", vm.Stats().IsCached(), vm.Stats().TotalRequests())
+	}
+	if !strings.Contains(vm.View(), "Stats: fresh") {
+		t.Error("Expected the status line to show fresh stats")
+	}
+}
+
+// TestViewModel_Search tests narrowing the requests table by a model or session typed after "/"
+func TestViewModel_Search(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithData(CreateTestRequestsSet())
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+
+	// runCmd executes the command and feeds the resulting messages back like the program does
+	var runCmd func(cmd tea.Cmd)
+	runCmd = func(cmd tea.Cmd) {
+		if cmd == nil {
+			return
+		}
+		switch msg := cmd().(type) {
+		case nil:
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				runCmd(cmd)
+			}
+		default:
+			_, next := vm.Update(msg)
+			runCmd(next)
+		}
+	}
+	typeKeys := func(keys string) {
+		for _, key := range keys {
+			_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+			runCmd(cmd)
+		}
+	}
+
+	_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	runCmd(cmd)
+	if len(vm.Requests()) != 4 {
+		t.Fatalf("Expected 4 requests before searching, got %d", len(vm.Requests()))
+	}
+
+	// Keys are typed into the prompt instead of switching the time filter
+	typeKeys("/haiKu")
+	if !vm.Searching() {
+		t.Fatal("Expected the search prompt to be open")
+	}
+	if !strings.Contains(vm.View(), "Search model or session: haiKu") {
+		t.Error("Expected the prompt to show the typed search")
+	}
+	if vm.GetTimeFilterString() != "All Time" {
+		t.Errorf("Expected the typed keys not to change the time filter, got %q", vm.GetTimeFilterString())
+	}
+
+	_, cmd = vm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+	if vm.Searching() || vm.Search() != "haiKu" {
+		t.Fatalf("Expected the search to be applied, got searching=%v search=%q", vm.Searching(), vm.Search())
+	}
+	if len(vm.Requests()) != 2 {
+		t.Errorf("Expected the 2 haiku requests, got %d", len(vm.Requests()))
+	}
+	if !strings.Contains(vm.View(), "search=haiKu") {
+		t.Error("Expected the status line to show the search")
+	}
+
+	// A session ID prefix matches as well, esc cancels the edit and keeps the applied search
+	typeKeys("/")
+	_, _ = vm.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeKeys("session3")
+	_, _ = vm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if vm.Search() != "haiKu" {
+		t.Errorf("Expected esc to keep the applied search, got %q", vm.Search())
+	}
+
+	typeKeys("/")
+	_, _ = vm.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	typeKeys("session3")
+	_, cmd = vm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+	if len(vm.Requests()) != 1 || vm.Requests()[0].SessionID() != "session3" {
+		t.Errorf("Expected the request of session3, got %d requests", len(vm.Requests()))
+	}
+
+	// An empty search shows every request again
+	typeKeys("/")
+	_, _ = vm.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	_, cmd = vm.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+	if vm.Search() != "" || len(vm.Requests()) != 4 {
+		t.Errorf("Expected every request after clearing the search, got search=%q with %d requests", vm.Search(), len(vm.Requests()))
+	}
+}
+
 // TestViewModel_FilterStateCoverage tests different filter states to improve coverage
 func TestViewModel_FilterStateCoverage(t *testing.T) {
 	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+)
+
+// RequestDetailModel shows every field of a single request, the values are unstyled and untruncated for copying
+type RequestDetailModel struct {
+	request entity.APIRequest
+
+	timezone *time.Location
+	width    int
+	height   int
+}
+
+// NewRequestDetailModel creates a new request detail pane
+func NewRequestDetailModel(timezone *time.Location) *RequestDetailModel {
+	return &RequestDetailModel{
+		timezone: timezone,
+	}
+}
+
+// Init initializes the request detail pane
+func (m *RequestDetailModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles resizing of the request detail pane
+func (m *RequestDetailModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if msg, ok := msg.(ResizeMsg); ok {
+		m.SetSize(msg.Width, msg.Height)
+	}
+	return m, nil
+}
+
+// View renders the fields of the request, one per line
+func (m *RequestDetailModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Request Detail") + "\n\n")
+
+	for _, field := range m.Fields() {
+		b.WriteString(fmt.Sprintf("  %-16s %s\n", field[0]+":", field[1]))
+	}
+	return b.String()
+}
+
+// Fields returns the label and the exact value of each field of the request
+// Numbers are not abbreviated and costs keep every decimal, so the values can be copied as they are
+func (m *RequestDetailModel) Fields() [][2]string {
+	req := m.request
+	tokens := req.Tokens()
+
+	return [][2]string{
+		{"ID", req.ID()},
+		{"Session ID", req.SessionID()},
+		{"Timestamp", req.Timestamp().UTC().Format(time.RFC3339Nano)},
+		{"Local Time", req.Timestamp().In(m.timezone).Format("2006-01-02 15:04:05.000 MST")},
+		{"Model", req.Model().String()},
+		{"Input", strconv.FormatInt(tokens.Input(), 10)},
+		{"Output", fmt.Sprintf("%d (tool use %d)", tokens.Output(), tokens.ToolUse())},
+		{"Cache Read", strconv.FormatInt(tokens.CacheRead(), 10)},
+		{"Cache Creation", strconv.FormatInt(tokens.CacheCreation(), 10)},
+		{"Total Tokens", strconv.FormatInt(tokens.Total(), 10)},
+		{"Cost (USD)", strconv.FormatFloat(req.Cost().Amount(), 'f', -1, 64)},
+		{"Duration", fmt.Sprintf("%d ms", req.DurationMS())},
+		{"Source", orDash(req.Source())},
+		{"Origin", orDash(req.Origin())},
+		{"User", orDash(req.User())},
+		{"Project", orDash(req.Project())},
+		{"Starred", req.Star().String()},
+	}
+}
+
+// SetRequest sets the request shown in the pane
+func (m *RequestDetailModel) SetRequest(request entity.APIRequest) {
+	m.request = request
+}
+
+// Request returns the request shown in the pane
+func (m *RequestDetailModel) Request() entity.APIRequest {
+	return m.request
+}
+
+// SetSize updates the size of the request detail pane
+func (m *RequestDetailModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// orDash returns the value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
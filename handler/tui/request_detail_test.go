@@ -0,0 +1,109 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestRequestDetail_Fields(t *testing.T) {
+	t.Parallel()
+
+	taipei, err := time.LoadLocation("Asia/Taipei")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	timestamp := time.Date(2025, 6, 1, 10, 0, 0, 123456789, time.UTC)
+	request := entity.NewAPIRequest("session-with-a-very-long-identifier", timestamp, "claude-sonnet-4-20250514", entity.NewToken(12345, 678, 90000, 1234).WithToolUse(12), entity.NewCost(0.123456), 3200).
+		WithSource("claude_code").WithUser("alice@example.com").WithStar(entity.StarSession)
+
+	model := tui.NewRequestDetailModel(taipei)
+	model.SetRequest(request)
+
+	expected := map[string]string{
+		"ID":             request.ID(),
+		"Session ID":     "session-with-a-very-long-identifier",
+		"Timestamp":      "2025-06-01T10:00:00.123456789Z",
+		"Local Time":     "2025-06-01 18:00:00.123 CST",
+		"Input":          "12345",
+		"Output":         "678 (tool use 12)",
+		"Cache Read":     "90000",
+		"Cache Creation": "1234",
+		"Total Tokens":   "104257",
+		"Cost (USD)":     "0.123456",
+		"Duration":       "3200 ms",
+		"Source":         "claude_code",
+		"Origin":         "live",
+		"User":           "alice@example.com",
+		"Project":        "-",
+		"Starred":        "session",
+	}
+	fields := model.Fields()
+	for _, field := range fields {
+		want, ok := expected[field[0]]
+		if !ok {
+			continue
+		}
+		if field[1] != want {
+			t.Errorf("Expected %s to be %q, got %q", field[0], want, field[1])
+		}
+		delete(expected, field[0])
+	}
+	for label := range expected {
+		t.Errorf("Expected a %s field", label)
+	}
+
+	if view := model.View(); !strings.Contains(view, "Session ID:      session-with-a-very-long-identifier") {
+		t.Errorf("Expected aligned untruncated fields, got:\n%s", view)
+	}
+}
+
+func TestViewModel_RequestDetail(t *testing.T) {
+	t.Parallel()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	vm := tui.NewViewModel(usecase.NewGetFilteredApiRequestsQuery(apiRepo), usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	// Nothing to show before the requests are loaded
+	vm.Update(enter)
+	if vm.ShowRequestDetail() {
+		t.Fatal("Expected no request detail without requests")
+	}
+
+	requests := CreateTestRequestsSet()
+	vm.Update(tui.RequestsDataMsg{Requests: requests})
+	vm.Update(tea.KeyMsg{Type: tea.KeyDown})
+	vm.Update(enter)
+	if !vm.ShowRequestDetail() {
+		t.Fatal("Expected enter to open the request detail")
+	}
+	if vm.RequestDetail().Request().ID() != requests[1].ID() {
+		t.Errorf("Expected the detail of the request under the cursor %s, got %s", requests[1].ID(), vm.RequestDetail().Request().ID())
+	}
+
+	view := vm.View()
+	if !strings.Contains(view, "Request Detail") || !strings.Contains(view, requests[1].SessionID()) || strings.Contains(view, "Recent API Requests") {
+		t.Errorf("Expected the full-screen request detail, got:\n%s", view)
+	}
+
+	// Keys are handled by the detail while it is open
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if vm.GetTimeFilterString() == "Last Hour" || !vm.ShowRequestDetail() {
+		t.Error("Expected keys to be ignored while the request detail is open")
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if vm.ShowRequestDetail() {
+		t.Error("Expected esc to close the request detail")
+	}
+}
@@ -18,19 +18,37 @@ var (
 		Foreground(lipgloss.Color("241"))
 )
 
+// highlightMarker prefixes cost and total cells exceeding the highlight thresholds, and the cost of anomalous days
+const highlightMarker = "▲"
+
+// Star markers prefix the model cell of requests kept from the retention cleanup
+const (
+	starRequestMarker = "★ "
+	starSessionMarker = "✦ "
+)
+
 // RequestsTableModel handles the requests table display and interaction and owns its data
 type RequestsTableModel struct {
 	// Data ownership
 	table    table.Model
+	styles   table.Styles
 	requests []entity.APIRequest
+	counts   *RequestCounts // nil when the repository cannot count the requests
 
 	// Configuration
-	timezone *time.Location
-	width    int
-	height   int
+	timezone    *time.Location
+	timeDisplay TimeDisplay
+	highlight   entity.Highlight
+	costFormat  entity.CostFormat
+	anchorID    string // request ID where the visual selection starts, empty when not selecting
+	starNotice  string // outcome of the last star toggle, cleared when the requests refresh
+	now         func() time.Time
+	width       int
+	height      int
 
 	// Business logic dependencies
 	getFilteredQuery *usecase.GetFilteredApiRequestsQuery
+	starCommand      *usecase.StarApiRequestCommand // optional, starring is disabled when nil
 }
 
 // NewRequestsTableModel creates a new requests table model with usecase dependency
@@ -61,8 +79,11 @@ func NewRequestsTableModel(getFilteredQuery *usecase.GetFilteredApiRequestsQuery
 
 	return &RequestsTableModel{
 		table:            t,
+		styles:           s,
 		requests:         []entity.APIRequest{},
 		timezone:         timezone,
+		timeDisplay:      TimeDisplayAbsolute,
+		now:              time.Now,
 		width:            120,
 		height:           10,
 		getFilteredQuery: getFilteredQuery,
@@ -82,13 +103,30 @@ func (m *RequestsTableModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 	case ResizeMsg:
 		m.SetSize(msg.Width, msg.Height)
 	case RequestsRefreshMsg:
-		return m, m.refreshRequests(msg.Period, msg.SortOrder)
+		return m, m.refreshRequests(msg.Filter, msg.SortOrder)
 	case RequestsDataMsg:
 		m.requests = msg.Requests
+		m.counts = msg.Counts
+		m.starNotice = ""
 		m.updateTableRows()
+	case StarredMsg:
+		m.applyStar(msg)
 	case tea.KeyMsg:
+		switch msg.String() {
+		case "t":
+			m.ToggleTimeDisplay()
+			return m, nil
+		case "v":
+			m.ToggleSelection()
+			return m, nil
+		case "*":
+			return m, m.toggleStar()
+		}
 		// Handle table navigation
 		m.table, cmd = m.table.Update(msg)
+	case tea.MouseMsg:
+		// The Y of the message is the line of the table view
+		updateTableMouse(&m.table, m.styles, msg)
 	}
 
 	return m, cmd
@@ -105,10 +143,27 @@ func (m *RequestsTableModel) View() string {
 		b.WriteString(helpStyle.Render("    export OTEL_LOGS_EXPORTER=otlp\n"))
 		b.WriteString(helpStyle.Render("    export OTEL_EXPORTER_OTLP_PROTOCOL=grpc\n"))
 		b.WriteString(helpStyle.Render("    export OTEL_EXPORTER_OTLP_ENDPOINT=http://localhost:4317\n"))
+		// Requests hidden by the filter are not waiting, tell how many exist
+		if m.counts != nil && m.counts.Stored > 0 {
+			b.WriteString("\n" + m.renderCountsLine())
+		}
 		return b.String()
 	}
 
-	return m.table.View()
+	view := m.table.View()
+	if m.highlight.IsEnabled() {
+		view = renderHighlightMarkers(view, m.styles)
+	}
+	if counts := m.renderCountsLine(); counts != "" {
+		view += "\n" + counts
+	}
+	if footer := m.renderSelectionFooter(); footer != "" {
+		view += "\n" + footer
+	}
+	if m.starNotice != "" {
+		view += "\n" + HelpStyle.Render("  "+m.starNotice)
+	}
+	return view
 }
 
 // SetSize updates the table size and recalculates column widths
@@ -125,6 +180,71 @@ func (m *RequestsTableModel) UpdateRequests(requests []entity.APIRequest) {
 	m.updateTableRows()
 }
 
+// SetHighlight sets the thresholds above which a request is highlighted
+func (m *RequestsTableModel) SetHighlight(highlight entity.Highlight) {
+	m.highlight = highlight
+	m.updateTableRows()
+}
+
+// SetCostFormat sets the display format of the request costs
+func (m *RequestsTableModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+	m.updateTableRows()
+}
+
+// SetStarCommand enables starring the request under the cursor with the "*" key
+func (m *RequestsTableModel) SetStarCommand(starCommand *usecase.StarApiRequestCommand) {
+	m.starCommand = starCommand
+}
+
+// ToggleTimeDisplay switches the Time column between absolute and relative time
+func (m *RequestsTableModel) ToggleTimeDisplay() {
+	if m.timeDisplay == TimeDisplayAbsolute {
+		m.timeDisplay = TimeDisplayRelative
+	} else {
+		m.timeDisplay = TimeDisplayAbsolute
+	}
+	m.updateTableRows()
+}
+
+// TimeDisplay returns how the Time column is currently rendered
+func (m *RequestsTableModel) TimeDisplay() TimeDisplay {
+	return m.timeDisplay
+}
+
+// ToggleSelection starts a visual selection at the cursor row, or clears the active selection
+// Moving the cursor afterwards extends the selection from the starting row
+func (m *RequestsTableModel) ToggleSelection() {
+	if m.anchorID != "" {
+		m.anchorID = ""
+		return
+	}
+
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) {
+		return
+	}
+	m.anchorID = m.requests[cursor].ID()
+}
+
+// Selection returns the requests between the selection start and the cursor, or nil when not selecting
+func (m *RequestsTableModel) Selection() []entity.APIRequest {
+	start, end, ok := m.selectionRange()
+	if !ok {
+		return nil
+	}
+	return m.requests[start : end+1]
+}
+
+// SelectedRequest returns the request under the cursor, false when the table is empty
+func (m *RequestsTableModel) SelectedRequest() (entity.APIRequest, bool) {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) {
+		return entity.APIRequest{}, false
+	}
+	return m.requests[cursor], true
+}
+
 // GetTable returns the underlying table model for integration with other components
 func (m *RequestsTableModel) GetTable() table.Model {
 	return m.table
@@ -148,35 +268,46 @@ func (m *RequestsTableModel) Focused() bool {
 // updateTableRows updates the table rows based on current requests data
 func (m *RequestsTableModel) updateTableRows() {
 	rows := make([]table.Row, 0, len(m.requests))
+	now := m.now()
 	for _, req := range m.requests {
-		// Format timestamp in configured timezone
-		timestamp := req.Timestamp().In(m.timezone).Format("15:04:05 2006-01-02")
+		timestamp := m.formatTimestamp(req.Timestamp(), now)
+
+		cost := formatCost(m.costFormat, req.Cost().Amount())
+		if m.highlight.IsCostExceeded(req) {
+			cost = highlightMarker + cost
+		}
+		total := FormatNumber(req.Tokens().Total())
+		if m.highlight.IsTokensExceeded(req) {
+			total = highlightMarker + total
+		}
+
+		model := starMarker(req.Star()) + req.Model().String() // Don't truncate - let auto-width handle it
 
 		if m.width < 80 {
 			// Compact mode: combine cache and total tokens
 			cacheAndTotal := fmt.Sprintf("%s/%s",
 				FormatNumber(req.Tokens().Cache()),
-				FormatNumber(req.Tokens().Total()))
+				total)
 
 			rows = append(rows, table.Row{
 				timestamp,
-				req.Model().String(), // Don't truncate - let auto-width handle it
+				model,
 				FormatNumber(req.Tokens().Input()),
 				FormatNumber(req.Tokens().Output()),
 				cacheAndTotal,
-				FormatCost(req.Cost().Amount()),
+				cost,
 				FormatDuration(req.DurationMS()),
 			})
 		} else {
 			// Normal mode: separate columns
 			rows = append(rows, table.Row{
 				timestamp,
-				req.Model().String(), // Don't truncate - let auto-width handle it
+				model,
 				FormatNumber(req.Tokens().Input()),
 				FormatNumber(req.Tokens().Output()),
 				FormatNumber(req.Tokens().Cache()),
-				FormatNumber(req.Tokens().Total()),
-				FormatCost(req.Cost().Amount()),
+				total,
+				cost,
 				FormatDuration(req.DurationMS()),
 			})
 		}
@@ -184,6 +315,171 @@ func (m *RequestsTableModel) updateTableRows() {
 	m.table.SetRows(rows)
 }
 
+// renderHighlightMarkers colors the highlight markers in a rendered table, e.g. of expensive requests or anomalous days
+// Table cells are truncated by width before rendering, so colors can only be applied afterwards
+func renderHighlightMarkers(view string, styles table.Styles) string {
+	if !strings.Contains(view, highlightMarker) {
+		return view
+	}
+
+	marker := HighlightStyle.Render(highlightMarker)
+
+	// The marker style resets the terminal attributes, restore them on the selected row
+	selected := styles.Selected.Render(highlightMarker)
+	selectedPrefix := selected[:strings.Index(selected, highlightMarker)]
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if selectedPrefix != "" && strings.HasPrefix(line, selectedPrefix) {
+			lines[i] = strings.ReplaceAll(line, highlightMarker, marker+selectedPrefix)
+			continue
+		}
+		lines[i] = strings.ReplaceAll(line, highlightMarker, marker)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// selectionRange returns the inclusive row range of the visual selection
+// The selection start follows its request when rows shift after a refresh and is dropped once the request leaves the table
+func (m *RequestsTableModel) selectionRange() (int, int, bool) {
+	if m.anchorID == "" {
+		return 0, 0, false
+	}
+
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) {
+		return 0, 0, false
+	}
+
+	for i, req := range m.requests {
+		if req.ID() != m.anchorID {
+			continue
+		}
+		if i > cursor {
+			return cursor, i, true
+		}
+		return i, cursor, true
+	}
+
+	m.anchorID = ""
+	return 0, 0, false
+}
+
+// renderSelectionFooter summarizes tokens and cost of the selected rows
+func (m *RequestsTableModel) renderSelectionFooter() string {
+	start, end, ok := m.selectionRange()
+	if !ok {
+		return ""
+	}
+
+	var tokens entity.Token
+	var cost entity.Cost
+	for _, req := range m.requests[start : end+1] {
+		tokens = tokens.Add(req.Tokens())
+		cost = cost.Add(req.Cost())
+	}
+
+	return HighlightStyle.Render(fmt.Sprintf("  Selection: %d requests (rows %d-%d) • %s tokens • $%s • v=clear",
+		end-start+1, start+1, end+1, FormatTokenCount(tokens.Total()), formatCostAmount(m.costFormat, cost.Amount())))
+}
+
+// renderCountsLine tells how many requests the table shows out of those the filter and the display limit hide
+// e.g. "Showing 100 of 3,214 in range; 98.2K total stored"
+func (m *RequestsTableModel) renderCountsLine() string {
+	if m.counts == nil {
+		return ""
+	}
+
+	line := "  Showing " + FormatCount(int64(len(m.requests)))
+	if m.counts.Filtered {
+		line += fmt.Sprintf(" of %s matching; %s in range", FormatCount(int64(m.counts.Matching)), FormatCount(int64(m.counts.InRange)))
+	} else {
+		line += fmt.Sprintf(" of %s in range", FormatCount(int64(m.counts.InRange)))
+	}
+	line += fmt.Sprintf("; %s total stored", FormatTokenCount(int64(m.counts.Stored)))
+
+	return HelpStyle.Render(line)
+}
+
+// toggleStar returns a command which moves the star of the request under the cursor to the next scope
+// Stars cycle from none to the request, then to its whole session and back to none
+func (m *RequestsTableModel) toggleStar() tea.Cmd {
+	if m.starCommand == nil {
+		return nil
+	}
+
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.requests) {
+		return nil
+	}
+
+	req := m.requests[cursor]
+	params := usecase.StarApiRequestParams{
+		RequestID: req.ID(),
+		SessionID: req.SessionID(),
+		Scope:     req.Star().Next(),
+	}
+	starCommand := m.starCommand
+
+	return func() tea.Msg {
+		err := starCommand.Execute(context.Background(), params)
+		return StarredMsg{RequestID: params.RequestID, SessionID: params.SessionID, Scope: params.Scope, Err: err}
+	}
+}
+
+// applyStar marks the starred rows without waiting for the next refresh
+func (m *RequestsTableModel) applyStar(msg StarredMsg) {
+	if msg.Err != nil {
+		m.starNotice = "Star failed: " + msg.Err.Error()
+		return
+	}
+
+	for i, req := range m.requests {
+		switch {
+		case req.ID() == msg.RequestID:
+			m.requests[i] = req.WithStar(msg.Scope)
+		case req.SessionID() == msg.SessionID && msg.Scope == entity.StarSession:
+			m.requests[i] = req.WithStar(entity.StarSession)
+		case req.SessionID() == msg.SessionID && req.Star() == entity.StarSession:
+			// Removing the session star leaves other requests of the session unstarred
+			m.requests[i] = req.WithStar(entity.StarNone)
+		}
+	}
+
+	switch msg.Scope {
+	case entity.StarRequest:
+		m.starNotice = "Starred request, kept by the retention cleanup • *=star session"
+	case entity.StarSession:
+		m.starNotice = "Starred session " + msg.SessionID + ", kept by the retention cleanup • *=unstar"
+	default:
+		m.starNotice = "Unstarred, the retention cleanup may remove it"
+	}
+	m.updateTableRows()
+}
+
+// starMarker returns the model cell prefix of a starred request
+func starMarker(star entity.StarScope) string {
+	switch star {
+	case entity.StarRequest:
+		return starRequestMarker
+	case entity.StarSession:
+		return starSessionMarker
+	default:
+		return ""
+	}
+}
+
+// formatTimestamp formats a request timestamp for the Time column
+// Relative times are recomputed on every refresh tick as new rows arrive
+func (m *RequestsTableModel) formatTimestamp(timestamp time.Time, now time.Time) string {
+	if m.timeDisplay == TimeDisplayRelative {
+		return FormatRelativeTime(now.Sub(timestamp))
+	}
+
+	// Format timestamp in configured timezone
+	return FormatDateTime(timestamp.In(m.timezone))
+}
+
 // resizeTableColumns resizes table columns based on available width
 func (m *RequestsTableModel) resizeTableColumns() {
 	// Calculate auto-width columns based on available terminal width
@@ -241,12 +537,16 @@ func (m *RequestsTableModel) adjustTableHeight() {
 	// - Stats box: varies (8-12 lines with borders and content)
 	// - Table header: 1 line
 	// - Help text: 2 lines (newline + help)
+	// - Counts line: 1 line
+	// - Selection footer: 1 line
+	// - Ingestion lag footer: 1 line
+	// - Retention footer: 1 line
 	// - Safety margin: 2 lines
 
-	fixedHeight := 9 // Title, status, table header, help, margins
+	fixedHeight := 13 // Title, status, table header, counts, selection, help, lag and retention footers, margins
 
 	// Calculate stats section height more accurately
-	statsHeight := 10 // Conservative estimate for stats box with borders
+	statsHeight := 13 // Conservative estimate for stats box with borders (tier rows and hot sessions)
 
 	// For compact stats, reduce height
 	if m.width < 60 {
@@ -267,7 +567,7 @@ func (m *RequestsTableModel) adjustTableHeight() {
 }
 
 // refreshRequests handles data fetching for the requests table model
-func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder SortOrder) tea.Cmd {
+func (m *RequestsTableModel) refreshRequests(filter entity.Filter, sortOrder SortOrder) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		if m.getFilteredQuery == nil {
 			return RequestsDataMsg{Requests: []entity.APIRequest{}}
@@ -275,7 +575,7 @@ func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder Sor
 
 		// Query for display requests (limit to 100 for TUI display)
 		displayParams := usecase.GetFilteredApiRequestsParams{
-			Period: period,
+			Filter: filter,
 			Limit:  100,
 			Offset: 0,
 		}
@@ -290,10 +590,40 @@ func (m *RequestsTableModel) refreshRequests(period entity.Period, sortOrder Sor
 			m.reverseRequests(requests)
 		}
 
-		return RequestsDataMsg{Requests: requests}
+		// Counts are informational, servers predating counts leave the counts line hidden
+		counts, _ := m.countRequests(filter)
+
+		return RequestsDataMsg{Requests: requests, Counts: counts}
 	})
 }
 
+// countRequests counts the requests matching the filter, those of its period and every stored request
+func (m *RequestsTableModel) countRequests(filter entity.Filter) (*RequestCounts, error) {
+	ctx := context.Background()
+	period := filter.Period()
+
+	inRange, err := m.getFilteredQuery.Count(ctx, entity.NewFilter(period))
+	if err != nil {
+		return nil, err
+	}
+	counts := &RequestCounts{Matching: inRange, InRange: inRange, Stored: inRange}
+
+	if filter.HasDimensions() {
+		counts.Filtered = true
+		if counts.Matching, err = m.getFilteredQuery.Count(ctx, filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if !period.IsAllTime() {
+		if counts.Stored, err = m.getFilteredQuery.Count(ctx, entity.NewFilter(entity.NewAllTimePeriod(m.now()))); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
 // reverseRequests reverses the order of requests slice
 func (m *RequestsTableModel) reverseRequests(requests []entity.APIRequest) {
 	for i, j := 0, len(requests)-1; i < j; i, j = i+1, j-1 {
@@ -308,10 +638,27 @@ func (m *RequestsTableModel) Requests() []entity.APIRequest {
 
 // Message types for RequestsTableModel
 type RequestsRefreshMsg struct {
-	Period    entity.Period
+	Filter    entity.Filter
 	SortOrder SortOrder
 }
 
 type RequestsDataMsg struct {
 	Requests []entity.APIRequest
+	Counts   *RequestCounts // nil when the requests cannot be counted
+}
+
+// RequestCounts tells how many requests exist beyond those shown in the table
+type RequestCounts struct {
+	Matching int  // Requests matching the period and the dimensions of the filter
+	InRange  int  // Requests of the period, regardless of the dimensions
+	Stored   int  // Every stored request
+	Filtered bool // True when the filter has dimensions
+}
+
+// StarredMsg carries the outcome of starring a request
+type StarredMsg struct {
+	RequestID string
+	SessionID string
+	Scope     entity.StarScope
+	Err       error
 }
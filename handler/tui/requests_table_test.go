@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
@@ -170,3 +171,415 @@ func TestRequestsTable_Navigation(t *testing.T) {
 		tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
 	})
 }
+
+func TestRequestsTable_RelativeTimeToggle(t *testing.T) {
+	setupTestEnvironment()
+	t.Parallel()
+
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, periodFactory)
+
+	model := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, getUsageQuery, time.UTC, nil, time.Hour)
+
+	tm := teatest.NewTestModel(
+		t, model,
+		teatest.WithInitialTermSize(120, 40),
+	)
+
+	// Absolute timestamps are shown by default
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return strings.Contains(string(bts), "t=relative time") && !strings.Contains(string(bts), " ago")
+		},
+		teatest.WithCheckInterval(time.Millisecond*100),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{
+		Type:  tea.KeyRunes,
+		Runes: []rune("t"),
+	})
+
+	teatest.WaitFor(
+		t, tm.Output(),
+		func(bts []byte) bool {
+			return strings.Contains(string(bts), " ago")
+		},
+		teatest.WithCheckInterval(time.Millisecond*100),
+		teatest.WithDuration(time.Second),
+	)
+
+	tm.Send(tea.KeyMsg{
+		Type:  tea.KeyRunes,
+		Runes: []rune("q"),
+	})
+
+	tm.WaitFinished(t, teatest.WithFinalTimeout(time.Second*3))
+}
+
+func TestRequestsTable_Highlight(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+		entity.NewAPIRequest("session-1", timestamp.Add(time.Minute), "claude-opus-4-20250514", entity.NewToken(150000, 2000, 0, 0), entity.NewCost(0.75), 5000),
+	}
+
+	tests := []struct {
+		name        string
+		highlight   entity.Highlight
+		width       int
+		costMarked  []bool
+		totalMarked []bool
+	}{
+		{
+			name:        "disabled highlight leaves rows unmarked",
+			highlight:   entity.NewHighlight(entity.NewCost(0), 0),
+			width:       120,
+			costMarked:  []bool{false, false},
+			totalMarked: []bool{false, false},
+		},
+		{
+			name:        "cost above threshold is marked",
+			highlight:   entity.NewHighlight(entity.NewCost(0.50), 0),
+			width:       120,
+			costMarked:  []bool{false, true},
+			totalMarked: []bool{false, false},
+		},
+		{
+			name:        "tokens above threshold are marked",
+			highlight:   entity.NewHighlight(entity.NewCost(0), 100000),
+			width:       120,
+			costMarked:  []bool{false, false},
+			totalMarked: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := tui.NewRequestsTableModel(nil, time.UTC)
+			model.SetSize(tt.width, 40)
+			model.SetHighlight(tt.highlight)
+			model.UpdateRequests(requests)
+
+			rows := model.GetTable().Rows()
+			if len(rows) != len(requests) {
+				t.Fatalf("Expected %d rows, got %d", len(requests), len(rows))
+			}
+
+			for i, row := range rows {
+				if got := strings.HasPrefix(row[6], "▲"); got != tt.costMarked[i] {
+					t.Errorf("Row %d: expected cost marked %v, got %q", i, tt.costMarked[i], row[6])
+				}
+				if got := strings.HasPrefix(row[5], "▲"); got != tt.totalMarked[i] {
+					t.Errorf("Row %d: expected total marked %v, got %q", i, tt.totalMarked[i], row[5])
+				}
+			}
+		})
+	}
+}
+
+func TestRequestsTable_SelectionQuickStats(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+		entity.NewAPIRequest("session-1", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(2000, 500, 0, 0), entity.NewCost(0.10), 1000),
+		entity.NewAPIRequest("session-1", timestamp.Add(2*time.Minute), "claude-opus-4-20250514", entity.NewToken(3000, 1000, 0, 0), entity.NewCost(0.25), 1000),
+	}
+
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	visual := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")}
+
+	tests := []struct {
+		name           string
+		keys           []tea.KeyMsg
+		expectedCount  int
+		expectedFooter string
+	}{
+		{
+			name:          "no selection by default",
+			keys:          []tea.KeyMsg{down},
+			expectedCount: 0,
+		},
+		{
+			name:           "single row selection",
+			keys:           []tea.KeyMsg{visual},
+			expectedCount:  1,
+			expectedFooter: "Selection: 1 requests (rows 1-1) • 1.5K tokens • $0.05",
+		},
+		{
+			name:           "selection extends with cursor",
+			keys:           []tea.KeyMsg{visual, down, down},
+			expectedCount:  3,
+			expectedFooter: "Selection: 3 requests (rows 1-3) • 8.0K tokens • $0.40",
+		},
+		{
+			name:           "selection extends upwards",
+			keys:           []tea.KeyMsg{down, down, visual, up},
+			expectedCount:  2,
+			expectedFooter: "Selection: 2 requests (rows 2-3) • 6.5K tokens • $0.35",
+		},
+		{
+			name:          "toggle clears selection",
+			keys:          []tea.KeyMsg{visual, down, visual},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := tui.NewRequestsTableModel(nil, time.UTC)
+			model.SetSize(120, 40)
+			model.UpdateRequests(requests)
+
+			for _, key := range tt.keys {
+				model.Update(key)
+			}
+
+			if got := len(model.Selection()); got != tt.expectedCount {
+				t.Errorf("Expected %d selected requests, got %d", tt.expectedCount, got)
+			}
+
+			view := model.View()
+			if tt.expectedFooter == "" {
+				if strings.Contains(view, "Selection:") {
+					t.Errorf("Expected no selection footer, got:\n%s", view)
+				}
+				return
+			}
+			if !strings.Contains(view, tt.expectedFooter) {
+				t.Errorf("Expected footer %q, got:\n%s", tt.expectedFooter, view)
+			}
+		})
+	}
+}
+
+func TestRequestsTable_SelectionFollowsRefresh(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	first := entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 0, 0, 0), entity.NewCost(0.05), 1000)
+	second := entity.NewAPIRequest("session-1", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(2000, 0, 0, 0), entity.NewCost(0.10), 1000)
+	newer := entity.NewAPIRequest("session-1", timestamp.Add(2*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(4000, 0, 0, 0), entity.NewCost(0.20), 1000)
+
+	model := tui.NewRequestsTableModel(nil, time.UTC)
+	model.SetSize(120, 40)
+	model.UpdateRequests([]entity.APIRequest{first, second})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+
+	// A newer request arrives at the top, the selection start stays on its request
+	model.UpdateRequests([]entity.APIRequest{newer, first, second})
+	selection := model.Selection()
+	if len(selection) != 2 || selection[0].ID() != newer.ID() || selection[1].ID() != first.ID() {
+		t.Errorf("Expected selection from cursor to the starting request, got %d requests", len(selection))
+	}
+
+	// The selection is dropped once its starting request leaves the table
+	model.UpdateRequests([]entity.APIRequest{newer, second})
+	if selection := model.Selection(); selection != nil {
+		t.Errorf("Expected selection to be cleared, got %d requests", len(selection))
+	}
+}
+
+func TestRequestsTable_StarCycle(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	first := entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 0, 0, 0), entity.NewCost(0.05), 1000)
+	sibling := entity.NewAPIRequest("session-1", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(2000, 0, 0, 0), entity.NewCost(0.10), 1000)
+	other := entity.NewAPIRequest("session-2", timestamp.Add(2*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(4000, 0, 0, 0), entity.NewCost(0.20), 1000)
+
+	starRepo := testutil.NewMockStarRepository()
+	model := tui.NewRequestsTableModel(nil, time.UTC)
+	model.SetStarCommand(usecase.NewStarApiRequestCommand(starRepo))
+	model.SetSize(120, 40)
+	model.UpdateRequests([]entity.APIRequest{first, sibling, other})
+
+	star := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")}
+	expectedScopes := [][]entity.StarScope{
+		{entity.StarRequest, entity.StarNone, entity.StarNone},
+		{entity.StarSession, entity.StarSession, entity.StarNone},
+		{entity.StarNone, entity.StarNone, entity.StarNone},
+	}
+	expectedNotices := []string{"Starred request", "Starred session session-1", "Unstarred"}
+
+	for i, expected := range expectedScopes {
+		_, cmd := model.Update(star)
+		if cmd == nil {
+			t.Fatalf("Expected a star command on press %d", i+1)
+		}
+		model.Update(cmd())
+
+		stars, _ := starRepo.GetStars()
+		for j, req := range model.Requests() {
+			if req.Star() != expected[j] {
+				t.Errorf("Press %d: expected row %d scope %v, got %v", i+1, j+1, expected[j], req.Star())
+			}
+			if stars.ScopeOf(req) != expected[j] {
+				t.Errorf("Press %d: expected stored row %d scope %v, got %v", i+1, j+1, expected[j], stars.ScopeOf(req))
+			}
+		}
+		if view := model.View(); !strings.Contains(view, expectedNotices[i]) {
+			t.Errorf("Press %d: expected notice %q, got:\n%s", i+1, expectedNotices[i], view)
+		}
+	}
+
+	// A star failure is reported without changing the rows
+	starRepo.SetError(&testutil.MockError{Message: "server is a replica"})
+	_, cmd := model.Update(star)
+	model.Update(cmd())
+	if model.Requests()[0].IsStarred() {
+		t.Error("Expected the request to stay unstarred after a failure")
+	}
+	if view := model.View(); !strings.Contains(view, "Star failed: server is a replica") {
+		t.Errorf("Expected failure notice, got:\n%s", view)
+	}
+}
+
+func TestRequestsTable_StarDisabled(t *testing.T) {
+	t.Parallel()
+
+	model := tui.NewRequestsTableModel(nil, time.UTC)
+	model.SetSize(120, 40)
+	model.UpdateRequests([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", entity.NewToken(1000, 0, 0, 0), entity.NewCost(0.05), 1000),
+	})
+
+	if _, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("*")}); cmd != nil {
+		t.Error("Expected no star command without a star usecase")
+	}
+}
+
+func TestRequestsTable_Filter(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+		entity.NewAPIRequest("session-2", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+		entity.NewAPIRequest("session-1", timestamp.Add(2*time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.01), 1000),
+	})
+
+	model := tui.NewRequestsTableModel(usecase.NewGetFilteredApiRequestsQuery(repo), time.UTC)
+	model.SetSize(120, 40)
+
+	filter := entity.NewFilter(entity.NewAllTimePeriod(timestamp.Add(time.Hour))).WithSessionID("session-1")
+	_, cmd := model.Update(tui.RequestsRefreshMsg{Filter: filter, SortOrder: tui.SortAscending})
+	if cmd == nil {
+		t.Fatal("Expected a refresh command")
+	}
+	model.Update(cmd())
+
+	requests := model.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests of session-1, got %d", len(requests))
+	}
+	for _, req := range requests {
+		if req.SessionID() != "session-1" {
+			t.Errorf("Expected only session-1 requests, got %s", req.SessionID())
+		}
+	}
+}
+
+func TestRequestsTable_Counts(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(session string, offset time.Duration) entity.APIRequest {
+		return entity.NewAPIRequest(session, timestamp.Add(offset), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000)
+	}
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		newRequest("session-1", -48*time.Hour),
+		newRequest("session-1", 0),
+		newRequest("session-2", time.Minute),
+		newRequest("session-1", 2*time.Minute),
+	})
+	today := entity.NewPeriod(timestamp.Add(-time.Hour), timestamp.Add(time.Hour))
+
+	tests := []struct {
+		name     string
+		filter   entity.Filter
+		expected string
+	}{
+		{
+			name:     "period only",
+			filter:   entity.NewFilter(today),
+			expected: "Showing 3 of 3 in range; 4 total stored",
+		},
+		{
+			name:     "dimensions",
+			filter:   entity.NewFilter(today).WithSessionID("session-1"),
+			expected: "Showing 2 of 2 matching; 3 in range; 4 total stored",
+		},
+		{
+			name:     "all time",
+			filter:   entity.NewFilter(entity.NewAllTimePeriod(timestamp.Add(time.Hour))),
+			expected: "Showing 4 of 4 in range; 4 total stored",
+		},
+		{
+			name:     "filter hides every request",
+			filter:   entity.NewFilter(today).WithSessionID("session-3"),
+			expected: "Showing 0 of 0 matching; 3 in range; 4 total stored",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := tui.NewRequestsTableModel(usecase.NewGetFilteredApiRequestsQuery(repo), time.UTC)
+			model.SetSize(120, 40)
+
+			_, cmd := model.Update(tui.RequestsRefreshMsg{Filter: tt.filter, SortOrder: tui.SortAscending})
+			if cmd == nil {
+				t.Fatal("Expected a refresh command")
+			}
+			model.Update(cmd())
+
+			if view := model.View(); !strings.Contains(view, tt.expected) {
+				t.Errorf("Expected the view to contain %q, got %q", tt.expected, view)
+			}
+		})
+	}
+}
+
+func TestRequestsTable_CountsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	model := tui.NewRequestsTableModel(nil, time.UTC)
+	model.SetSize(120, 40)
+	model.Update(tui.RequestsDataMsg{Requests: []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+	}})
+
+	if view := model.View(); strings.Contains(view, "Showing") {
+		t.Errorf("Expected no counts line without counts, got %q", view)
+	}
+}
+
+func TestViewModel_RequestFilterStatus(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.SetRequestFilter(entity.NewFilter(entity.Period{}).WithModel("claude-sonnet-4-20250514"))
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if view := vm.View(); !strings.Contains(view, "Filter: All Time (model=claude-sonnet-4-20250514)") {
+		t.Errorf("Expected the status line to show the filter dimensions, got %q", view)
+	}
+}
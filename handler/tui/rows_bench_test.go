@@ -0,0 +1,92 @@
+package tui_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// benchmarkRows is the number of requests in the benchmark requests table
+const benchmarkRows = 1000
+
+// createBenchmarkTableRequests creates requests for the requests table, one per minute
+func createBenchmarkTableRequests() []entity.APIRequest {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	requests := make([]entity.APIRequest, 0, benchmarkRows)
+	for i := 0; i < benchmarkRows; i++ {
+		requests = append(requests, entity.NewAPIRequest("session", start.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 200, 100), entity.NewCost(0.01), 1000))
+	}
+	return requests
+}
+
+// createBenchmarkUsage creates a 30 day usage window with moving averages
+func createBenchmarkUsage() entity.Usage {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	stats := make([]entity.Stats, 0, 30)
+	costs := make([]entity.Cost, 0, 30)
+	for day := 29; day >= 0; day-- {
+		dayStart := start.AddDate(0, 0, day)
+		stats = append(stats, entity.NewStats(10, 20, entity.NewToken(1000, 500, 0, 0), entity.NewToken(20000, 10000, 5000, 1000), entity.NewCost(0.1), entity.NewCost(2), entity.NewPeriod(dayStart, dayStart.Add(24*time.Hour))))
+		costs = append(costs, entity.NewCost(2))
+	}
+	return entity.NewUsage(stats).WithMovingAverages(entity.CalculateMovingAverages(costs, len(costs)))
+}
+
+func BenchmarkRequestsTable_UpdateRequests(b *testing.B) {
+	model := tui.NewRequestsTableModel(nil, time.UTC)
+	model.SetSize(140, 40)
+	requests := createBenchmarkTableRequests()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		model.UpdateRequests(requests)
+	}
+}
+
+func BenchmarkDailyUsageTab_UpdateUsage(b *testing.B) {
+	model := tui.NewDailyUsageTabModel(nil, time.UTC)
+	model.SetSize(160, 40)
+	usage := createBenchmarkUsage()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		model.UpdateUsage(usage)
+	}
+}
+
+func TestRowBuilding_AllocationBudget(t *testing.T) {
+	requestsTable := tui.NewRequestsTableModel(nil, time.UTC)
+	requestsTable.SetSize(140, 40)
+	requests := createBenchmarkTableRequests()
+
+	dailyTab := tui.NewDailyUsageTabModel(nil, time.UTC)
+	dailyTab.SetSize(160, 40)
+	usage := createBenchmarkUsage()
+
+	// Setting rows renders the visible table, budgets allow for the formatting and styling of each row
+	tests := []struct {
+		name   string
+		budget float64
+		update func()
+	}{
+		{
+			name:   "requests table",
+			budget: 20 * benchmarkRows,
+			update: func() { requestsTable.UpdateRequests(requests) },
+		},
+		{
+			name:   "daily usage tab",
+			budget: 200 * 30,
+			update: func() { dailyTab.UpdateUsage(usage) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.CheckAllocationBudget(t, tt.budget, tt.update)
+		})
+	}
+}
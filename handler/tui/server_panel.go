@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+)
+
+// serverPanelOutcomes lists the outcomes in the order shown, with why the events are not stored
+var serverPanelOutcomes = []struct {
+	outcome entity.IngestOutcome
+	label   string
+	reason  string
+}{
+	{entity.IngestAccepted, "Accepted", "stored"},
+	{entity.IngestIgnored, "Ignored", "matched an ignore rule"},
+	{entity.IngestDropped, "Dropped", "dropped by a processor or for clock skew"},
+	{entity.IngestMalformed, "Malformed", "missing session.id or negative usage"},
+	{entity.IngestDuplicated, "Duplicated", "repeated or re-sent within the dedupe window"},
+	{entity.IngestFailed, "Failed", "database write failed"},
+}
+
+// ServerPanelModel shows what happened to the events received by the server, answering why usage is missing
+type ServerPanelModel struct {
+	stats    entity.IngestStats
+	loaded   bool
+	err      error
+	timezone *time.Location
+}
+
+// NewServerPanelModel creates a new server panel without stats
+func NewServerPanelModel(timezone *time.Location) *ServerPanelModel {
+	return &ServerPanelModel{
+		timezone: timezone,
+	}
+}
+
+// Init initializes the server panel
+func (m *ServerPanelModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update keeps the latest ingest stats, the last known stats are kept when the server is unreachable
+func (m *ServerPanelModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	if msg, ok := msg.(IngestStatsMsg); ok {
+		m.err = msg.Err
+		if msg.Err == nil {
+			m.stats = msg.Stats
+			m.loaded = true
+		}
+	}
+	return m, nil
+}
+
+// View renders the outcomes of the last hour and day
+func (m *ServerPanelModel) View() string {
+	var b strings.Builder
+	b.WriteString(HeaderStyle.Render("Server Ingestion") + "\n\n")
+
+	if m.err != nil {
+		b.WriteString(WarningStyle.Render("  Failed to load ingest stats: "+entity.ErrorMessage(m.err)) + "\n\n")
+	}
+	if !m.loaded {
+		b.WriteString(HelpStyle.Render("  Loading ingest stats...") + "\n")
+		return b.String()
+	}
+	// Replicas and servers predating the stats report nothing
+	if m.stats.Since().IsZero() {
+		b.WriteString(HelpStyle.Render("  Ingest stats are not reported by this server") + "\n")
+		return b.String()
+	}
+
+	lastHour, lastDay := m.stats.LastHour(), m.stats.LastDay()
+	b.WriteString(TableHeaderStyle.Render(fmt.Sprintf("  %-12s %10s %10s  %s", "Outcome", "Last hour", "Last 24h", "Reason")) + "\n")
+	for _, row := range serverPanelOutcomes {
+		line := fmt.Sprintf("  %-12s %10s %10s  %s", row.label, FormatCount(lastHour.Count(row.outcome)), FormatCount(lastDay.Count(row.outcome)), row.reason)
+		if row.outcome != entity.IngestAccepted && lastDay.Count(row.outcome) > 0 {
+			b.WriteString(WarningStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(StatusStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString(StatStyle.Render(fmt.Sprintf("  %-12s %10s %10s", "Not stored", FormatCount(lastHour.Missing()), FormatCount(lastDay.Missing()))) + "\n\n")
+
+	// Counts are kept in memory, a restarted server reports shorter windows
+	b.WriteString(HelpStyle.Render("  Counting since "+FormatDateTime(m.stats.Since().In(m.timezone))) + "\n")
+	return b.String()
+}
+
+// Stats returns the last known ingest stats
+func (m *ServerPanelModel) Stats() entity.IngestStats {
+	return m.stats
+}
+
+// IngestStatsMsg carries the server ingest stats for the server panel
+type IngestStatsMsg struct {
+	Stats entity.IngestStats
+	Err   error // set when the server is unreachable
+}
@@ -0,0 +1,139 @@
+package tui_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+func TestServerPanel_View(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		msgs     []tea.Msg
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "loading",
+			contains: []string{"Loading ingest stats..."},
+		},
+		{
+			name: "server reports stats",
+			msgs: []tea.Msg{tui.IngestStatsMsg{Stats: entity.NewIngestStats(
+				entity.NewIngestCounts(12, 1, 0, 0, 0, 0),
+				entity.NewIngestCounts(1200, 30, 0, 4, 2, 0),
+				since,
+			)}},
+			contains: []string{
+				"Accepted",
+				"1,200",
+				"matched an ignore rule",
+				"missing session.id or negative usage",
+				"Not stored",
+				"Counting since 2025-01-01 10:00:00",
+			},
+			excludes: []string{"Failed to load"},
+		},
+		{
+			name:     "server without stats",
+			msgs:     []tea.Msg{tui.IngestStatsMsg{}},
+			contains: []string{"Ingest stats are not reported by this server"},
+		},
+		{
+			name: "keeps the last stats when unreachable",
+			msgs: []tea.Msg{
+				tui.IngestStatsMsg{Stats: entity.NewIngestStats(entity.NewIngestCounts(1, 0, 0, 0, 0, 0), entity.NewIngestCounts(1, 0, 0, 0, 0, 0), since)},
+				tui.IngestStatsMsg{Err: errors.New("connection refused")},
+			},
+			contains: []string{"Failed to load ingest stats: connection refused", "Counting since"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			panel := tui.NewServerPanelModel(time.UTC)
+			for _, msg := range tt.msgs {
+				panel.Update(msg)
+			}
+
+			view := panel.View()
+			for _, want := range tt.contains {
+				if !strings.Contains(view, want) {
+					t.Errorf("Expected view to contain %q, got %q", want, view)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(view, unwanted) {
+					t.Errorf("Expected view not to contain %q, got %q", unwanted, view)
+				}
+			}
+		})
+	}
+}
+
+func TestViewModel_ServerPanel(t *testing.T) {
+	apiRepo, statsRepo := testutil.NewMockRepositoryWithTestData()
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	vm := tui.NewViewModel(getFilteredQuery, calculateStatsQuery, CreateTestUsageQuery(), time.UTC, nil, 5*time.Second)
+	vm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Without the query the key is not bound
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if vm.ShowServerPanel() {
+		t.Fatal("Expected the server panel to be disabled without an ingest stats query")
+	}
+	if strings.Contains(vm.View(), "i=ingest") {
+		t.Error("Expected no server panel help without an ingest stats query")
+	}
+
+	stats := entity.NewIngestStats(
+		entity.NewIngestCounts(5, 2, 0, 1, 0, 0),
+		entity.NewIngestCounts(5, 2, 0, 1, 0, 0),
+		time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	)
+	vm.SetIngestStatsQuery(usecase.NewGetIngestStatsQuery(testutil.NewMockIngestStatsRepository(stats)))
+	if !strings.Contains(vm.View(), "i=ingest") {
+		t.Error("Expected the server panel help")
+	}
+
+	_, cmd := vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	if !vm.ShowServerPanel() {
+		t.Fatal("Expected the server panel to open")
+	}
+	if cmd == nil {
+		t.Fatal("Expected opening the server panel to fetch the ingest stats")
+	}
+	vm.Update(cmd())
+
+	if vm.ServerPanel().Stats().LastHour().Missing() != 3 {
+		t.Errorf("Expected 3 missing events in the last hour, got %d", vm.ServerPanel().Stats().LastHour().Missing())
+	}
+	if view := vm.View(); !strings.Contains(view, "Server Ingestion") || !strings.Contains(view, "i/esc: Close") {
+		t.Errorf("Expected the server panel over the tab, got %q", view)
+	}
+
+	// Keys are handled by the panel while it is open
+	vm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	if !vm.ShowServerPanel() {
+		t.Fatal("Expected the server panel to stay open on unrelated keys")
+	}
+	vm.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if vm.ShowServerPanel() {
+		t.Error("Expected esc to close the server panel")
+	}
+}
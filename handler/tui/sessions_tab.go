@@ -0,0 +1,342 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// Expansion markers prefix the session cell, requests of an expanded session are listed below it
+const (
+	sessionCollapsedMarker = "▸ "
+	sessionExpandedMarker  = "▾ "
+	sessionRequestMarker   = "  └ "
+)
+
+// SessionsTabModel handles the sessions tab that groups requests by session and owns its data
+type SessionsTabModel struct {
+	// Data ownership
+	table    table.Model
+	sessions []entity.Session               // most expensive first
+	requests map[string][]entity.APIRequest // requests of each session in chronological order
+	expanded map[string]bool
+	rows     []sessionRow // what each table row shows, follows the table rows
+
+	// Configuration
+	timezone   *time.Location
+	costFormat entity.CostFormat
+	width      int
+	height     int
+
+	// Session titles from Claude Code transcripts, sessions are listed by ID without them
+	sessionTitlesQuery *usecase.GetSessionTitlesQuery
+	sessionTitles      entity.SessionTitles
+
+	// Business logic dependencies
+	getFilteredQuery *usecase.GetFilteredApiRequestsQuery
+}
+
+// sessionRow identifies the session of a table row
+type sessionRow struct {
+	sessionID string
+	request   bool // false for the session totals row, true for the rows listing its requests
+}
+
+// NewSessionsTabModel creates a new sessions tab model with usecase dependency
+func NewSessionsTabModel(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, timezone *time.Location) *SessionsTabModel {
+	t := table.New(
+		table.WithColumns(sessionsTableColumns(CalculateSessionsColumnWidths(120))),
+		table.WithFocused(false),
+		table.WithHeight(10),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.Bold(true)
+	s.Selected = s.Selected.Bold(false)
+	t.SetStyles(s)
+
+	return &SessionsTabModel{
+		table:            t,
+		requests:         map[string][]entity.APIRequest{},
+		expanded:         map[string]bool{},
+		timezone:         timezone,
+		width:            120,
+		height:           30,
+		getFilteredQuery: getFilteredQuery,
+	}
+}
+
+// Init initializes the sessions tab model
+func (m *SessionsTabModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m *SessionsTabModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case ResizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	case SessionsRefreshMsg:
+		return m, m.refreshSessions(msg.Filter)
+	case SessionsDataMsg:
+		if msg.Err != nil {
+			// Keep the last known sessions when the server is unreachable
+			return m, nil
+		}
+		m.UpdateRequests(msg.Requests)
+		return m, m.refreshSessionTitles()
+	case SessionsTitlesMsg:
+		// Titles are cosmetic, keep the last known ones when the lookup fails
+		if msg.Err == nil {
+			m.sessionTitles = msg.Titles
+			m.sessions = m.sessionTitles.Apply(m.sessions)
+			m.updateTableRows()
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			m.ToggleExpanded()
+			return m, nil
+		}
+		// Handle table navigation
+		m.table, cmd = m.table.Update(msg)
+	}
+
+	return m, cmd
+}
+
+// View renders the sessions tab
+func (m *SessionsTabModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(HeaderStyle.Render("Sessions by Cost") + "\n")
+	b.WriteString(HelpStyle.Render("Expand a session to list its requests • Span: time between its first and latest request") + "\n\n")
+
+	if len(m.sessions) == 0 {
+		emptyContent := HelpStyle.Render("No sessions in this period")
+		b.WriteString(BoxStyle.Width(m.width-4).Render(emptyContent) + "\n")
+		return b.String()
+	}
+
+	b.WriteString(m.table.View() + "\n")
+	b.WriteString(HelpStyle.Render(fmt.Sprintf("  %d sessions • %d requests", len(m.sessions), m.requestCount())) + "\n")
+	return b.String()
+}
+
+// SetSize updates the table size and recalculates column widths
+func (m *SessionsTabModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Clear rows before setting new columns to avoid index out of range
+	m.table.SetRows([]table.Row{})
+	m.table.SetColumns(sessionsTableColumns(CalculateSessionsColumnWidths(width)))
+	m.updateTableRows()
+
+	// Title, tabs, status, header, summary, help and footers take about 14 lines
+	tableHeight := height - 14
+	if tableHeight < 3 {
+		tableHeight = 3
+	}
+	m.table.SetHeight(tableHeight)
+}
+
+// SetCostFormat sets the display format of the session and request costs
+func (m *SessionsTabModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+}
+
+// SetSessionTitlesQuery enables listing sessions by their transcript titles
+func (m *SessionsTabModel) SetSessionTitlesQuery(sessionTitlesQuery *usecase.GetSessionTitlesQuery) {
+	m.sessionTitlesQuery = sessionTitlesQuery
+}
+
+// UpdateRequests groups the requests into sessions, expanded sessions stay expanded
+func (m *SessionsTabModel) UpdateRequests(requests []entity.APIRequest) {
+	m.requests = make(map[string][]entity.APIRequest)
+	for _, req := range requests {
+		m.requests[req.SessionID()] = append(m.requests[req.SessionID()], req)
+	}
+
+	m.sessions = m.sessionTitles.Apply(entity.TopSessionsByCost(entity.NewSessionsFromRequests(requests), 0))
+	m.updateTableRows()
+}
+
+// ToggleExpanded expands the session under the cursor to list its requests, or collapses it
+// On a request row the session it belongs to is collapsed
+func (m *SessionsTabModel) ToggleExpanded() {
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(m.rows) {
+		return
+	}
+
+	sessionID := m.rows[cursor].sessionID
+	if m.expanded[sessionID] {
+		delete(m.expanded, sessionID)
+	} else {
+		m.expanded[sessionID] = true
+	}
+	m.updateTableRows()
+
+	// Keep the cursor on the session row, it moves up when collapsing from one of its requests
+	for i, row := range m.rows {
+		if row.sessionID == sessionID && !row.request {
+			m.table.SetCursor(i)
+			break
+		}
+	}
+}
+
+// IsExpanded returns whether the requests of the session are listed
+func (m *SessionsTabModel) IsExpanded(sessionID string) bool {
+	return m.expanded[sessionID]
+}
+
+// Sessions returns the sessions of the period, most expensive first
+func (m *SessionsTabModel) Sessions() []entity.Session {
+	return m.sessions
+}
+
+// GetTable returns the underlying table model for integration with other components
+func (m *SessionsTabModel) GetTable() table.Model {
+	return m.table
+}
+
+// Focus sets focus on the sessions table
+func (m *SessionsTabModel) Focus() {
+	m.table.Focus()
+}
+
+// Blur removes focus from the sessions table
+func (m *SessionsTabModel) Blur() {
+	m.table.Blur()
+}
+
+// Focused returns whether the sessions table is focused
+func (m *SessionsTabModel) Focused() bool {
+	return m.table.Focused()
+}
+
+// updateTableRows lists each session with its totals, followed by its requests when expanded
+func (m *SessionsTabModel) updateTableRows() {
+	rows := make([]table.Row, 0, len(m.sessions))
+	m.rows = m.rows[:0]
+
+	for _, session := range m.sessions {
+		marker := sessionCollapsedMarker
+		if m.expanded[session.ID()] {
+			marker = sessionExpandedMarker
+		}
+
+		rows = append(rows, table.Row{
+			marker + session.Name(),
+			fmt.Sprintf("%d", session.Requests()),
+			FormatTokenCount(session.Tokens().Total()),
+			formatCost(m.costFormat, session.Cost().Amount()),
+			FormatDateShortTime(session.FirstSeen().In(m.timezone)),
+			FormatDateShortTime(session.LastSeen().In(m.timezone)),
+			FormatDurationFromTime(session.ActiveDuration()),
+		})
+		m.rows = append(m.rows, sessionRow{sessionID: session.ID()})
+
+		if !m.expanded[session.ID()] {
+			continue
+		}
+		for _, req := range m.requests[session.ID()] {
+			rows = append(rows, table.Row{
+				sessionRequestMarker + req.Model().String(),
+				"",
+				FormatTokenCount(req.Tokens().Total()),
+				formatCost(m.costFormat, req.Cost().Amount()),
+				FormatDateTime(req.Timestamp().In(m.timezone)),
+				"",
+				FormatDuration(req.DurationMS()),
+			})
+			m.rows = append(m.rows, sessionRow{sessionID: session.ID(), request: true})
+		}
+	}
+
+	// Keep the cursor within the rows when sessions leave the period
+	if cursor := m.table.Cursor(); cursor >= len(rows) && len(rows) > 0 {
+		m.table.SetCursor(len(rows) - 1)
+	}
+	m.table.SetRows(rows)
+}
+
+// requestCount returns the number of requests across all sessions
+func (m *SessionsTabModel) requestCount() int {
+	count := 0
+	for _, session := range m.sessions {
+		count += session.Requests()
+	}
+	return count
+}
+
+// refreshSessions fetches every request of the filter, sessions need all of their requests for the totals
+func (m *SessionsTabModel) refreshSessions(filter entity.Filter) tea.Cmd {
+	query := m.getFilteredQuery
+	return func() tea.Msg {
+		if query == nil {
+			return SessionsDataMsg{Requests: []entity.APIRequest{}}
+		}
+
+		requests, err := query.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{Filter: filter})
+		return SessionsDataMsg{Requests: requests, Err: err}
+	}
+}
+
+// refreshSessionTitles looks up the titles of the listed sessions in the background
+func (m *SessionsTabModel) refreshSessionTitles() tea.Cmd {
+	if m.sessionTitlesQuery == nil || len(m.sessions) == 0 {
+		return nil
+	}
+
+	sessionIDs := make([]string, len(m.sessions))
+	for i, session := range m.sessions {
+		sessionIDs[i] = session.ID()
+	}
+
+	query := m.sessionTitlesQuery
+	return func() tea.Msg {
+		titles, err := query.Execute(context.Background(), sessionIDs)
+		return SessionsTitlesMsg{Titles: titles, Err: err}
+	}
+}
+
+// sessionsTableColumns returns the sessions table columns, request rows reuse them for their own values
+func sessionsTableColumns(widths []int) []table.Column {
+	return []table.Column{
+		{Title: "Session", Width: widths[0]},
+		{Title: "Requests", Width: widths[1]},
+		{Title: "Tokens", Width: widths[2]},
+		{Title: "Cost ($)", Width: widths[3]},
+		{Title: "First Seen", Width: widths[4]},
+		{Title: "Last Seen", Width: widths[5]},
+		{Title: "Span", Width: widths[6]},
+	}
+}
+
+// Message types for SessionsTabModel
+type SessionsRefreshMsg struct {
+	Filter entity.Filter
+}
+
+// SessionsDataMsg carries every request of the period for grouping into sessions
+type SessionsDataMsg struct {
+	Requests []entity.APIRequest
+	Err      error // set when the server is unreachable
+}
+
+// SessionsTitlesMsg carries the titles of the listed sessions read from Claude Code transcripts
+type SessionsTitlesMsg struct {
+	Titles entity.SessionTitles
+	Err    error
+}
@@ -0,0 +1,142 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/tui"
+)
+
+func TestSessionsTab_GroupsRequestsBySession(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-cheap", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.05), 1000),
+		entity.NewAPIRequest("session-costly", timestamp.Add(time.Minute), "claude-opus-4-20250514", entity.NewToken(3000, 1000, 0, 0), entity.NewCost(0.25), 1000),
+		entity.NewAPIRequest("session-costly", timestamp.Add(31*time.Minute), "claude-opus-4-20250514", entity.NewToken(2000, 1000, 0, 0), entity.NewCost(0.20), 1000),
+	}
+
+	model := tui.NewSessionsTabModel(nil, time.UTC)
+	model.SetSize(120, 40)
+	model.SetCostFormat(entity.DefaultCostFormat())
+	model.UpdateRequests(requests)
+
+	sessions := model.Sessions()
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].ID() != "session-costly" {
+		t.Errorf("Expected the most expensive session first, got %s", sessions[0].ID())
+	}
+
+	rows := model.GetTable().Rows()
+	if len(rows) != 2 {
+		t.Fatalf("Expected one row per collapsed session, got %d", len(rows))
+	}
+	expected := []string{"▸ session-costly", "2", "7.0K", "0.45", "2025-01-01 10:01", "2025-01-01 10:31", "30m 0s"}
+	for i, cell := range expected {
+		if rows[0][i] != cell {
+			t.Errorf("Expected cell %d to be %q, got %q", i, cell, rows[0][i])
+		}
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "2 sessions • 3 requests") {
+		t.Errorf("Expected session summary in view, got:\n%s", view)
+	}
+}
+
+func TestSessionsTab_ExpandSession(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.30), 1000),
+		entity.NewAPIRequest("session-2", timestamp.Add(time.Minute), "claude-opus-4-20250514", entity.NewToken(3000, 1000, 0, 0), entity.NewCost(0.10), 2500),
+		entity.NewAPIRequest("session-2", timestamp.Add(2*time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.01), 500),
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	tests := []struct {
+		name           string
+		keys           []tea.KeyMsg
+		expectedRows   []string
+		expectedCursor int
+	}{
+		{
+			name:         "sessions are collapsed by default",
+			expectedRows: []string{"▸ session-1", "▸ session-2"},
+		},
+		{
+			name:           "enter lists the requests of the session",
+			keys:           []tea.KeyMsg{down, enter},
+			expectedRows:   []string{"▸ session-1", "▾ session-2", "  └ claude-opus-4-20250514", "  └ claude-3-5-haiku-20241022"},
+			expectedCursor: 1,
+		},
+		{
+			name:           "enter on a request collapses its session",
+			keys:           []tea.KeyMsg{down, enter, down, down, enter},
+			expectedRows:   []string{"▸ session-1", "▸ session-2"},
+			expectedCursor: 1,
+		},
+		{
+			name:         "sessions expand independently",
+			keys:         []tea.KeyMsg{enter, down, down, enter},
+			expectedRows: []string{"▾ session-1", "  └ claude-sonnet-4-20250514", "▾ session-2", "  └ claude-opus-4-20250514", "  └ claude-3-5-haiku-20241022"},
+			// The cursor stays on the expanded session
+			expectedCursor: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := tui.NewSessionsTabModel(nil, time.UTC)
+			model.SetSize(120, 40)
+			model.Focus()
+			model.UpdateRequests(requests)
+
+			for _, key := range tt.keys {
+				model.Update(key)
+			}
+
+			rows := model.GetTable().Rows()
+			if len(rows) != len(tt.expectedRows) {
+				t.Fatalf("Expected %d rows, got %d: %v", len(tt.expectedRows), len(rows), rows)
+			}
+			for i, expected := range tt.expectedRows {
+				if rows[i][0] != expected {
+					t.Errorf("Expected row %d to be %q, got %q", i, expected, rows[i][0])
+				}
+			}
+			if cursor := model.GetTable().Cursor(); cursor != tt.expectedCursor {
+				t.Errorf("Expected cursor on row %d, got %d", tt.expectedCursor, cursor)
+			}
+		})
+	}
+
+	t.Run("expanded sessions stay expanded after a refresh", func(t *testing.T) {
+		t.Parallel()
+
+		model := tui.NewSessionsTabModel(nil, time.UTC)
+		model.SetSize(120, 40)
+		model.Focus()
+		model.UpdateRequests(requests)
+		model.Update(enter)
+
+		model.UpdateRequests(append(requests, entity.NewAPIRequest("session-1", timestamp.Add(3*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 300)))
+		if !model.IsExpanded("session-1") {
+			t.Fatal("Expected session-1 to stay expanded")
+		}
+		if rows := model.GetTable().Rows(); len(rows) != 4 {
+			t.Errorf("Expected the new request to be listed, got %d rows", len(rows))
+		}
+	})
+}
@@ -8,33 +8,56 @@ import (
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/usecase"
 )
 
+// hotSessionTitleWidth bounds a transcript title in the hot sessions line, IDs are shortened to 8 characters
+const hotSessionTitleWidth = 24
+
+// blockProgressWidth is the width of the block progress bar, matching the gradient bar of the progress model
+const blockProgressWidth = 40
+
+// modelSegmentStyles color the block progress segments of each model family, largest share first
+var modelSegmentStyles = []lipgloss.Style{
+	PremiumStyle,
+	LongContextStyle,
+	lipgloss.NewStyle().Foreground(lipgloss.Color("63")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("141")),
+}
+
 // StatsModel handles the rendering of usage statistics and owns its data
 type StatsModel struct {
 	// Data ownership
-	stats      entity.Stats
-	blockStats entity.Stats
-	block      *entity.Block
+	stats       entity.Stats
+	blockStats  entity.Stats
+	blockModels []entity.ModelUsage
+	block       *entity.Block
+	hotSessions []entity.Session
+	goal        entity.Goal
+	streak      entity.Streak
+	period      entity.Period // Period of the last requested refresh
 
 	// Configuration
-	timezone *time.Location
-	width    int
+	timezone   *time.Location
+	costFormat entity.CostFormat
+	width      int
+	breakdown  StatsBreakdown
 
 	// Progress bar components
 	progressModel progress.Model
 
 	// Business logic dependencies
 	calculateStatsQuery *usecase.CalculateStatsQuery
+	getFilteredQuery    *usecase.GetFilteredApiRequestsQuery // Optional: breaks the block progress down by model
 }
 
 // NewStatsModel creates a new statistics model with usecase dependency
 func NewStatsModel(calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location, block *entity.Block) *StatsModel {
 	// Initialize progress model with prettier green to red gradient
 	progressModel := progress.New(
-		progress.WithWidth(40),
+		progress.WithWidth(blockProgressWidth),
 		progress.WithGradient("#22c55e", "#ef4444"), // Tailwind green-500 to red-500
 		progress.WithoutPercentage(),
 	)
@@ -61,13 +84,23 @@ func (m *StatsModel) Update(msg tea.Msg) (ComponentModel, tea.Cmd) {
 	case ResizeMsg:
 		m.width = msg.Width
 	case StatsRefreshMsg:
-		return m, m.refreshStats(msg.Period)
+		m.period = msg.Period
+		return m, m.refreshStats(msg.Period, msg.Approximate, msg.Fresh)
 	case StatsDataMsg:
+		// Estimates of an outdated refresh would replace newer exact stats
+		if msg.Approximate && !m.isCurrentPeriod(msg.Period) {
+			return m, nil
+		}
 		m.stats = msg.Stats
 		m.blockStats = msg.BlockStats
+		m.blockModels = msg.BlockModels
 		if msg.Block != nil {
 			m.block = msg.Block
 		}
+		if msg.Approximate {
+			// Refine the estimate in the background
+			return m, m.refreshStats(msg.Period, false, false)
+		}
 	}
 	return m, nil
 }
@@ -77,17 +110,25 @@ func (m *StatsModel) View() string {
 	var b strings.Builder
 
 	// Header
-	b.WriteString(HeaderStyle.Render("Usage Statistics") + "\n\n")
+	header := HeaderStyle.Render("Usage Statistics")
+	if m.stats.IsApproximate() {
+		header += " " + HelpStyle.Render("(estimating…)")
+	}
+	b.WriteString(header + "\n\n")
 
 	// Calculate available width for stats table (account for box padding)
 	availableWidth := m.width - 6 // Leave margin for box borders and padding
-	if availableWidth < 60 {
+	if availableWidth < 68 {
 		// Render compact stats for narrow terminals
 		return m.renderCompact()
 	}
 
 	// Create table headers
-	headers := []string{"Model Tier", "Reqs", "Limited", "Cache", "Total", "Cost ($)", "Burn Rate"}
+	firstHeader := "Model Tier"
+	if m.breakdown == StatsBreakdownModel {
+		firstHeader = "Model"
+	}
+	headers := []string{firstHeader, "Reqs", "Limited", "Cache", "Total", "Cost ($)", "$/1K Tok", "Burn Rate"}
 
 	// Calculate dynamic column widths based on available space
 	colWidths := CalculateStatsColumnWidths(availableWidth)
@@ -105,6 +146,70 @@ func (m *StatsModel) View() string {
 	}
 	b.WriteString("\n")
 
+	if m.breakdown == StatsBreakdownModel {
+		m.renderModelRows(&b, colWidths)
+	} else {
+		m.renderTierRows(&b, colWidths)
+	}
+
+	// Separator before total
+	for _, width := range colWidths {
+		b.WriteString(strings.Repeat("─", width))
+	}
+	b.WriteString("\n")
+
+	// Total row (burn rate excludes base tokens since they don't count against limits)
+	totalRow := []string{
+		StatStyle.Bold(true).Render("Total"),
+		fmt.Sprintf("%d", m.stats.TotalRequests()),
+		FormatTokenCount(m.stats.TotalTokens().Limited()),
+		FormatTokenCount(m.stats.TotalTokens().Cache()),
+		FormatTokenCount(m.stats.TotalTokens().Total()),
+		formatCostAmount(m.costFormat, m.stats.TotalCost().Amount()),
+		FormatCostPerKiloToken(m.stats.CostPerKiloToken()),
+		FormatBurnRate(m.stats.RateLimitedTokenBurnRate()),
+	}
+	for i, cell := range totalRow {
+		if i == 0 {
+			b.WriteString(PadRight(cell, colWidths[i]))
+		} else {
+			b.WriteString(StatStyle.Render(PadRight(cell, colWidths[i])))
+		}
+	}
+
+	// Output split, only when telemetry reports tool call tokens
+	if m.stats.TotalTokens().ToolUse() > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(m.renderOutputSplit())
+	}
+
+	// Fastest-burning sessions pointing at the agent run eating the budget
+	if len(m.hotSessions) > 0 {
+		b.WriteString("\n\n")
+		b.WriteString(m.renderHotSessions())
+	}
+
+	// Daily goal progress and streak, only when a goal is configured
+	if m.goal.IsEnabled() {
+		b.WriteString("\n\n")
+		b.WriteString(m.renderGoal())
+	}
+
+	// Add progress bar section if block is configured with limit
+	if m.block != nil && m.block.HasLimit() {
+		b.WriteString("\n\n")
+		b.WriteString(m.renderBlockProgress())
+	} else if m.block == nil {
+		// Show help message if no block is configured
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("Use -b 5am to track token limits"))
+	}
+
+	return b.String()
+}
+
+// renderTierRows renders a row for each model tier
+func (m *StatsModel) renderTierRows(b *strings.Builder, colWidths []int) {
 	// Base (Haiku) row
 	baseRow := []string{
 		BaseStyle.Bold(true).Render("Base (Haiku)"),
@@ -112,7 +217,8 @@ func (m *StatsModel) View() string {
 		FormatTokenCount(m.stats.BaseTokens().Limited()),
 		FormatTokenCount(m.stats.BaseTokens().Cache()),
 		FormatTokenCount(m.stats.BaseTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.BaseCost().Amount()),
+		formatCostAmount(m.costFormat, m.stats.BaseCost().Amount()),
+		FormatCostPerKiloToken(m.stats.BaseCost().PerKiloToken(m.stats.BaseTokens())),
 		"-", // Base tokens don't count against limits
 	}
 	for i, cell := range baseRow {
@@ -131,7 +237,8 @@ func (m *StatsModel) View() string {
 		FormatTokenCount(m.stats.PremiumTokens().Limited()),
 		FormatTokenCount(m.stats.PremiumTokens().Cache()),
 		FormatTokenCount(m.stats.PremiumTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.PremiumCost().Amount()),
+		formatCostAmount(m.costFormat, m.stats.PremiumCost().Amount()),
+		FormatCostPerKiloToken(m.stats.PremiumCost().PerKiloToken(m.stats.PremiumTokens())),
 		FormatBurnRate(m.stats.PremiumTokenBurnRate()),
 	}
 	for i, cell := range premiumRow {
@@ -143,41 +250,77 @@ func (m *StatsModel) View() string {
 	}
 	b.WriteString("\n")
 
-	// Separator before total
-	for _, width := range colWidths {
-		b.WriteString(strings.Repeat("─", width))
+	// Long context (1M) row
+	longContextRow := []string{
+		LongContextStyle.Bold(true).Render("Long Ctx (1M)"),
+		fmt.Sprintf("%d", m.stats.LongContextRequests()),
+		FormatTokenCount(m.stats.LongContextTokens().Limited()),
+		FormatTokenCount(m.stats.LongContextTokens().Cache()),
+		FormatTokenCount(m.stats.LongContextTokens().Total()),
+		formatCostAmount(m.costFormat, m.stats.LongContextCost().Amount()),
+		FormatCostPerKiloToken(m.stats.LongContextCost().PerKiloToken(m.stats.LongContextTokens())),
+		FormatBurnRate(m.stats.LongContextTokenBurnRate()),
 	}
-	b.WriteString("\n")
-
-	// Total row (burn rate same as premium since base tokens don't count)
-	totalRow := []string{
-		StatStyle.Bold(true).Render("Total"),
-		fmt.Sprintf("%d", m.stats.TotalRequests()),
-		FormatTokenCount(m.stats.TotalTokens().Limited()),
-		FormatTokenCount(m.stats.TotalTokens().Cache()),
-		FormatTokenCount(m.stats.TotalTokens().Total()),
-		fmt.Sprintf("%.6f", m.stats.TotalCost().Amount()),
-		FormatBurnRate(m.stats.PremiumTokenBurnRate()),
-	}
-	for i, cell := range totalRow {
+	for i, cell := range longContextRow {
 		if i == 0 {
 			b.WriteString(PadRight(cell, colWidths[i]))
 		} else {
-			b.WriteString(StatStyle.Render(PadRight(cell, colWidths[i])))
+			b.WriteString(LongContextStyle.Render(PadRight(cell, colWidths[i])))
 		}
 	}
+	b.WriteString("\n")
+}
 
-	// Add progress bar section if block is configured with limit
-	if m.block != nil && m.block.HasLimit() {
-		b.WriteString("\n\n")
-		b.WriteString(m.renderBlockProgress())
-	} else if m.block == nil {
-		// Show help message if no block is configured
-		b.WriteString("\n\n")
-		b.WriteString(HelpStyle.Render("Use -b 5am to track token limits"))
+// renderModelRows renders a row for each model, the most expensive first
+func (m *StatsModel) renderModelRows(b *strings.Builder, colWidths []int) {
+	models := m.stats.Models()
+	if len(models) == 0 {
+		message := "No requests"
+		if m.stats.TotalRequests() > 0 {
+			message = "The server predates the per-model breakdown"
+		}
+		b.WriteString(HelpStyle.Render(message) + "\n")
+		return
 	}
 
-	return b.String()
+	for _, model := range models {
+		style := modelTierStyle(entity.NewModel(model.Model()))
+		burnRate := "-" // Base tokens don't count against limits
+		if !entity.NewModel(model.Model()).IsBase() {
+			burnRate = FormatBurnRate(m.stats.ModelTokenBurnRate(model))
+		}
+
+		row := []string{
+			style.Bold(true).Render(TruncateString(model.Model(), colWidths[0]-1)),
+			fmt.Sprintf("%d", model.Requests()),
+			FormatTokenCount(model.Tokens().Limited()),
+			FormatTokenCount(model.Tokens().Cache()),
+			FormatTokenCount(model.Tokens().Total()),
+			formatCostAmount(m.costFormat, model.Cost().Amount()),
+			FormatCostPerKiloToken(model.Cost().PerKiloToken(model.Tokens())),
+			burnRate,
+		}
+		for i, cell := range row {
+			if i == 0 {
+				b.WriteString(PadRight(cell, colWidths[i]))
+			} else {
+				b.WriteString(style.Render(PadRight(cell, colWidths[i])))
+			}
+		}
+		b.WriteString("\n")
+	}
+}
+
+// modelTierStyle returns the style of the tier the model belongs to
+func modelTierStyle(model entity.Model) lipgloss.Style {
+	switch {
+	case model.IsLongContext():
+		return LongContextStyle
+	case model.IsBase():
+		return BaseStyle
+	default:
+		return PremiumStyle
+	}
 }
 
 // renderCompact renders a compact version of stats for narrow terminals
@@ -195,29 +338,38 @@ func (m *StatsModel) renderCompact() string {
 	fmt.Fprintf(&b, "%s\n", FormatTokenCount(m.stats.TotalTokens().Total()))
 
 	b.WriteString(StatStyle.Render("Total Cost: "))
-	fmt.Fprintf(&b, "$%.6f\n", m.stats.TotalCost().Amount())
+	fmt.Fprintf(&b, "$%s\n", formatCostAmount(m.costFormat, m.stats.TotalCost().Amount()))
+
+	if costPerKiloToken, ok := m.stats.CostPerKiloToken(); ok {
+		b.WriteString(StatStyle.Render("Cost per 1K Tokens: "))
+		fmt.Fprintf(&b, "$%s\n", FormatCostPerKiloToken(costPerKiloToken, ok))
+	}
 
 	b.WriteString("\n")
-	b.WriteString(BaseStyle.Render("Base: "))
-	fmt.Fprintf(&b, "%d reqs, %s tokens, $%.6f\n",
-		m.stats.BaseRequests(),
-		FormatTokenCount(m.stats.BaseTokens().Total()),
-		m.stats.BaseCost().Amount())
+	if m.breakdown == StatsBreakdownModel {
+		m.renderCompactModels(&b)
+	} else {
+		m.renderCompactTiers(&b)
+	}
 
-	b.WriteString(PremiumStyle.Render("Premium: "))
-	fmt.Fprintf(&b, "%d reqs, %s tokens, $%.6f",
-		m.stats.PremiumRequests(),
-		FormatTokenCount(m.stats.PremiumTokens().Total()),
-		m.stats.PremiumCost().Amount())
+	if m.stats.TotalTokens().ToolUse() > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.renderOutputSplit())
+	}
 
 	// Add burn rate for compact view if not all-time period
-	burnRate := m.stats.PremiumTokenBurnRate()
+	burnRate := m.stats.RateLimitedTokenBurnRate()
 	if burnRate > 0 {
 		b.WriteString("\n")
 		b.WriteString(StatStyle.Render("Burn Rate: "))
 		b.WriteString(FormatBurnRate(burnRate))
 	}
 
+	if m.goal.IsEnabled() {
+		b.WriteString("\n")
+		b.WriteString(m.renderGoal())
+	}
+
 	// Add progress bar section if block is configured with limit
 	if m.block != nil && m.block.HasLimit() {
 		b.WriteString("\n\n")
@@ -231,12 +383,104 @@ func (m *StatsModel) renderCompact() string {
 	return b.String()
 }
 
+// renderCompactTiers renders a line for each model tier of the compact stats
+func (m *StatsModel) renderCompactTiers(b *strings.Builder) {
+	b.WriteString(BaseStyle.Render("Base: "))
+	fmt.Fprintf(b, "%d reqs, %s tokens, $%s\n",
+		m.stats.BaseRequests(),
+		FormatTokenCount(m.stats.BaseTokens().Total()),
+		formatCostAmount(m.costFormat, m.stats.BaseCost().Amount()))
+
+	b.WriteString(PremiumStyle.Render("Premium: "))
+	fmt.Fprintf(b, "%d reqs, %s tokens, $%s",
+		m.stats.PremiumRequests(),
+		FormatTokenCount(m.stats.PremiumTokens().Total()),
+		formatCostAmount(m.costFormat, m.stats.PremiumCost().Amount()))
+
+	if m.stats.LongContextRequests() > 0 {
+		b.WriteString("\n")
+		b.WriteString(LongContextStyle.Render("Long Ctx (1M): "))
+		fmt.Fprintf(b, "%d reqs, %s tokens, $%s",
+			m.stats.LongContextRequests(),
+			FormatTokenCount(m.stats.LongContextTokens().Total()),
+			formatCostAmount(m.costFormat, m.stats.LongContextCost().Amount()))
+	}
+}
+
+// renderCompactModels renders a line for each model of the compact stats, the most expensive first
+func (m *StatsModel) renderCompactModels(b *strings.Builder) {
+	models := m.stats.Models()
+	if len(models) == 0 {
+		b.WriteString(HelpStyle.Render("No per-model breakdown"))
+		return
+	}
+
+	for i, model := range models {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(modelTierStyle(entity.NewModel(model.Model())).Render(model.Model() + ": "))
+		fmt.Fprintf(b, "%d reqs, %s tokens, $%s",
+			model.Requests(),
+			FormatTokenCount(model.Tokens().Total()),
+			formatCostAmount(m.costFormat, model.Cost().Amount()))
+	}
+}
+
+// renderOutputSplit renders how the output tokens split between tool calls and message text
+func (m *StatsModel) renderOutputSplit() string {
+	tokens := m.stats.TotalTokens()
+	return StatStyle.Render("Output: ") + fmt.Sprintf("%s tool use • %s text (%.0f%% tool use)",
+		FormatTokenCount(tokens.ToolUse()),
+		FormatTokenCount(tokens.TextOutput()),
+		tokens.ToolUseShare())
+}
+
+// renderHotSessions renders the hot sessions line sorted by burn rate
+func (m *StatsModel) renderHotSessions() string {
+	var b strings.Builder
+
+	b.WriteString(HeaderStyle.Render("Hot Sessions:"))
+	for _, session := range m.hotSessions {
+		name := TruncateString(session.ID(), 8)
+		if label := session.Title().Label(); label != "" {
+			name = TruncateString(label, hotSessionTitleWidth)
+		}
+		fmt.Fprintf(&b, "  %s %s (%s, %s)",
+			PremiumStyle.Render(name),
+			FormatBurnRate(session.BurnRate()),
+			FormatTokenCount(session.Tokens().Total()),
+			formatCost(m.costFormat, session.Cost().Amount()))
+	}
+
+	return b.String()
+}
+
+// renderGoal renders today's usage against the daily goal and the streak of days meeting it
+func (m *StatsModel) renderGoal() string {
+	today := m.streak.Today()
+
+	var progress []string
+	if m.goal.DailyCost().Amount() > 0 {
+		progress = append(progress, fmt.Sprintf("$%s of $%s", formatCostAmount(m.costFormat, today.TotalCost().Amount()), formatCostAmount(m.costFormat, m.goal.DailyCost().Amount())))
+	}
+	if m.goal.DailyTokens() > 0 {
+		progress = append(progress, fmt.Sprintf("%s of %s tokens", FormatTokenCount(today.TotalTokens().Limited()), FormatTokenCount(m.goal.DailyTokens())))
+	}
+	line := strings.Join(progress, " • ") + " today"
+
+	if !m.goal.IsMet(today) {
+		return HeaderStyle.Render("Daily Goal: ") + WarningStyle.Render(line+" • goal exceeded, streak reset")
+	}
+	return HeaderStyle.Render("Daily Goal: ") + StatStyle.Render(line+" • 🔥 "+m.streak.String()+" under goal")
+}
+
 // renderBlockProgress renders the block progress bar section
 func (m *StatsModel) renderBlockProgress() string {
 	var b strings.Builder
 
 	// Calculate progress using Block entity method
-	percentage := m.block.CalculateProgress(m.blockStats.PremiumTokens())
+	percentage := m.block.CalculateProgress(m.blockStats.RateLimitedTokens())
 
 	if percentage > 100 {
 		percentage = 100
@@ -257,15 +501,25 @@ func (m *StatsModel) renderBlockProgress() string {
 	b.WriteString(HeaderStyle.Render(fmt.Sprintf("Block Progress (%s)", blockTime)))
 	b.WriteString("\n\n")
 
-	// Progress bar using calculated percentage
-
+	// Progress bar using calculated percentage, stacked by model when the breakdown is known
+	used := m.blockStats.RateLimitedTokens().Limited()
+	limit := int64(m.block.TokenLimit())
 	progressBar := "[" + m.progressModel.ViewAs(percentage/100) + "]"
+	if len(m.blockModels) > 0 {
+		progressBar = "[" + m.renderModelSegments(limit) + "]"
+	}
 	b.WriteString(progressBar)
 	b.WriteString(" ")
-	used := m.blockStats.PremiumTokens().Limited()
-	limit := int64(m.block.TokenLimit())
 	b.WriteString(StatStyle.Render(fmt.Sprintf("%.1f%% (%s/%s tokens)", percentage, FormatTokenCount(used), FormatTokenCount(limit))))
 	b.WriteString("\n")
+	if len(m.blockModels) > 0 {
+		b.WriteString(m.renderModelLegend(used))
+		b.WriteString("\n")
+	}
+	if projection := m.renderBlockProjection(now); projection != "" {
+		b.WriteString(projection)
+		b.WriteString("\n")
+	}
 
 	// Time remaining
 	if timeRemaining > 0 {
@@ -277,20 +531,85 @@ func (m *StatsModel) renderBlockProgress() string {
 	return b.String()
 }
 
+// renderBlockProjection renders the block burn rate and the progress it reaches at the block end
+// Returns empty before the block has run long enough to project and after it ended
+func (m *StatsModel) renderBlockProjection(now time.Time) string {
+	tokens := m.blockStats.RateLimitedTokens()
+	projected, ok := m.block.ProjectProgress(tokens, now)
+	if !ok || m.block.Remaining(now) == 0 {
+		return ""
+	}
+
+	line := fmt.Sprintf("Burn rate: %s • projected %.0f%% at block end", FormatBurnRate(m.block.BurnRate(tokens, now)), projected)
+	reachedAt, exceeded := m.block.LimitReachedAt(tokens, now)
+	if !exceeded {
+		return StatStyle.Render(line)
+	}
+	if reachedAt.After(now) {
+		line += " • limit in " + FormatDurationFromTime(reachedAt.Sub(now))
+	}
+	return WarningStyle.Render(line)
+}
+
+// renderModelSegments renders the block progress bar as one segment per model family, sized by its share of the limit
+func (m *StatsModel) renderModelSegments(limit int64) string {
+	var b strings.Builder
+
+	filled := 0
+	var cumulative int64
+	for i, usage := range m.blockModels {
+		// Round the running total so the segments always add up to the filled width
+		cumulative += usage.Tokens()
+		end := blockProgressWidth
+		if limit > 0 && cumulative < limit {
+			end = int(float64(cumulative) / float64(limit) * blockProgressWidth)
+		}
+		if end <= filled {
+			continue
+		}
+		style := modelSegmentStyles[i%len(modelSegmentStyles)]
+		b.WriteString(style.Render(strings.Repeat("█", end-filled)))
+		filled = end
+	}
+	b.WriteString(HelpStyle.Render(strings.Repeat("░", blockProgressWidth-filled)))
+
+	return b.String()
+}
+
+// renderModelLegend renders the tokens and share of each model family in the block
+func (m *StatsModel) renderModelLegend(used int64) string {
+	parts := make([]string, 0, len(m.blockModels))
+	for i, usage := range m.blockModels {
+		style := modelSegmentStyles[i%len(modelSegmentStyles)]
+		share := 0.0
+		if used > 0 {
+			share = float64(usage.Tokens()) / float64(used) * 100
+		}
+		parts = append(parts, style.Render("■ "+usage.Family())+fmt.Sprintf(" %s (%.0f%%)", FormatTokenCount(usage.Tokens()), share))
+	}
+	return strings.Join(parts, "  ")
+}
+
 // SetSize updates the model size
 func (m *StatsModel) SetSize(width, height int) {
 	m.width = width
 }
 
-// refreshStats handles data fetching for the stats model
-func (m *StatsModel) refreshStats(period entity.Period) tea.Cmd {
+// isCurrentPeriod reports whether the period is the one of the last requested refresh
+func (m *StatsModel) isCurrentPeriod(period entity.Period) bool {
+	return period.StartAt().Equal(m.period.StartAt()) && period.EndAt().Equal(m.period.EndAt())
+}
+
+// refreshStats handles data fetching for the stats model, approximate allows estimated stats for a fast first response
+// Fresh skips the cached stats of the monitor and the server
+func (m *StatsModel) refreshStats(period entity.Period, approximate bool, fresh bool) tea.Cmd {
 	return tea.Cmd(func() tea.Msg {
 		if m.calculateStatsQuery == nil {
-			return StatsDataMsg{Stats: entity.Stats{}, BlockStats: entity.Stats{}, Block: m.block}
+			return StatsDataMsg{Stats: entity.Stats{}, BlockStats: entity.Stats{}, Block: m.block, Period: period}
 		}
 
 		// Calculate filtered stats for display
-		statsParams := usecase.CalculateStatsParams{Period: period}
+		statsParams := usecase.CalculateStatsParams{Period: period, Approximate: approximate, Fresh: fresh}
 		stats, err := m.calculateStatsQuery.Execute(context.Background(), statsParams)
 		if err != nil {
 			stats = entity.Stats{}
@@ -308,6 +627,7 @@ func (m *StatsModel) refreshStats(period entity.Period) tea.Cmd {
 		if currentBlock != nil && m.calculateStatsQuery != nil {
 			blockStatsParams := usecase.CalculateStatsParams{
 				Period: currentBlock.Period(),
+				Fresh:  fresh,
 			}
 			calculatedBlockStats, err := m.calculateStatsQuery.Execute(context.Background(), blockStatsParams)
 			if err == nil {
@@ -315,14 +635,82 @@ func (m *StatsModel) refreshStats(period entity.Period) tea.Cmd {
 			}
 		}
 
+		// Break the block usage down by model for the stacked progress bar
+		var blockModels []entity.ModelUsage
+		if currentBlock != nil && currentBlock.HasLimit() && m.getFilteredQuery != nil {
+			requests, err := m.getFilteredQuery.Execute(context.Background(), usecase.GetFilteredApiRequestsParams{
+				Filter: entity.NewFilter(currentBlock.Period()),
+			})
+			if err == nil {
+				blockModels = entity.NewModelUsages(requests)
+			}
+		}
+
 		return StatsDataMsg{
-			Stats:      stats,
-			BlockStats: blockStats,
-			Block:      currentBlock,
+			Stats:       stats,
+			BlockStats:  blockStats,
+			BlockModels: blockModels,
+			Block:       currentBlock,
+			Period:      period,
+			Approximate: stats.IsApproximate(),
 		}
 	})
 }
 
+// ToggleBreakdown switches the usage statistics between model tiers and models
+func (m *StatsModel) ToggleBreakdown() {
+	if m.breakdown == StatsBreakdownTier {
+		m.breakdown = StatsBreakdownModel
+	} else {
+		m.breakdown = StatsBreakdownTier
+	}
+}
+
+// Breakdown returns how the usage statistics are currently broken down
+func (m *StatsModel) Breakdown() StatsBreakdown {
+	return m.breakdown
+}
+
+// SetFilteredQuery enables the per-model breakdown of the block progress
+func (m *StatsModel) SetFilteredQuery(getFilteredQuery *usecase.GetFilteredApiRequestsQuery) {
+	m.getFilteredQuery = getFilteredQuery
+}
+
+// BlockModels returns the per-model breakdown of the current block
+func (m *StatsModel) BlockModels() []entity.ModelUsage {
+	return m.blockModels
+}
+
+// SetCostFormat sets the display format of the costs
+func (m *StatsModel) SetCostFormat(format entity.CostFormat) {
+	m.costFormat = format
+}
+
+// SetHotSessions updates the fastest-burning sessions shown below the stats table
+func (m *StatsModel) SetHotSessions(sessions []entity.Session) {
+	m.hotSessions = sessions
+}
+
+// SetGoal sets the daily goal shown with its streak, a disabled goal hides the widget
+func (m *StatsModel) SetGoal(goal entity.Goal) {
+	m.goal = goal
+}
+
+// SetStreak updates the streak of days meeting the daily goal
+func (m *StatsModel) SetStreak(streak entity.Streak) {
+	m.streak = streak
+}
+
+// Streak returns the current streak
+func (m *StatsModel) Streak() entity.Streak {
+	return m.streak
+}
+
+// HotSessions returns the current hot sessions
+func (m *StatsModel) HotSessions() []entity.Session {
+	return m.hotSessions
+}
+
 // Stats returns the current stats (for compatibility)
 func (m *StatsModel) Stats() entity.Stats {
 	return m.stats
@@ -340,11 +728,16 @@ func (m *StatsModel) Block() *entity.Block {
 
 // Message types for StatsModel
 type StatsRefreshMsg struct {
-	Period entity.Period
+	Period      entity.Period
+	Approximate bool // Allows estimated stats, refined in the background once loaded
+	Fresh       bool // Skips the cached stats, e.g. when forced by the user
 }
 
 type StatsDataMsg struct {
-	Stats      entity.Stats
-	BlockStats entity.Stats
-	Block      *entity.Block
+	Stats       entity.Stats
+	BlockStats  entity.Stats
+	BlockModels []entity.ModelUsage // Rate limited tokens of the block by model, empty without a limit
+	Block       *entity.Block
+	Period      entity.Period
+	Approximate bool // Stats are estimated and an exact refresh follows
 }
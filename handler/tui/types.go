@@ -30,6 +30,22 @@ const (
 	SortAscending                   // Oldest first
 )
 
+// StatsBreakdown represents how the usage statistics are broken down
+type StatsBreakdown int
+
+const (
+	StatsBreakdownTier  StatsBreakdown = iota // Base, premium and 1M context tiers (default)
+	StatsBreakdownModel                       // Each model, the most expensive first
+)
+
+// TimeDisplay represents how the Time column of the requests table is rendered
+type TimeDisplay int
+
+const (
+	TimeDisplayAbsolute TimeDisplay = iota // Timestamp in configured timezone (default)
+	TimeDisplayRelative                    // Duration since the request, e.g. "2m ago"
+)
+
 // Message types for component communication
 type RefreshMsg struct{}
 type ResizeMsg struct {
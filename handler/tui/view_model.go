@@ -1,10 +1,14 @@
 package tui
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/usecase"
 )
@@ -25,15 +29,61 @@ const (
 type Tab int
 
 const (
-	TabCurrent Tab = iota // Current view (requests and stats)
-	TabDaily              // Daily usage view
+	TabCurrent     Tab = iota // Current view (requests and stats)
+	TabDaily                  // Daily usage view
+	TabSessions               // Requests grouped by session
+	TabLeaderboard            // Users ranked by their weekly cost, only when enabled
 )
 
+// monitorTitle is the first line of every screen
+const monitorTitle = "🖥️  Claude Code Monitor"
+
+// ingestionLagWarningThreshold is the average lag above which the footer is highlighted
+// as exporter buffering problems make recent usage look missing
+const ingestionLagWarningThreshold = time.Minute
+
+// watchRefreshInterval is how often the data is polled while the server pushes new requests,
+// keeping rolling time windows moving when no request arrives
+const watchRefreshInterval = time.Minute
+
+// watchRefreshDelay coalesces the requests pushed in a burst into a single refresh
+const watchRefreshDelay = time.Second
+
+// watchRetryInterval is how long the monitor polls before watching again after the stream ended
+const watchRetryInterval = time.Minute
+
 // ViewModel represents the refactored state of our TUI monitor application using component models
 type ViewModel struct {
 	// Tab models
 	overviewTab   *OverviewTabModel
 	dailyUsageTab *DailyUsageTabModel
+	sessionsTab   *SessionsTabModel
+
+	// Optional leaderboard tab for multi-user deployments
+	leaderboardTab *LeaderboardTabModel
+
+	// Notification center panel toggled with "n", shown over the current tab
+	notificationCenter *NotificationCenterModel
+	showNotifications  bool
+
+	// Optional server panel toggled with "i", showing the outcomes of the events received by the server
+	serverPanel      *ServerPanelModel
+	showServerPanel  bool
+	ingestStatsQuery *usecase.GetIngestStatsQuery
+
+	// Optional block history toggled with "B", listing the usage of past blocks against the token limit
+	blockHistory      *BlockHistoryModel
+	showBlockHistory  bool
+	blockHistoryQuery *usecase.GetBlockHistoryQuery
+
+	// Search of the requests table typed after "/", matching a model substring or session ID prefix
+	searching   bool
+	searchInput string
+	search      string
+
+	// Full-screen detail of the request opened with "enter" in the requests table
+	requestDetail     *RequestDetailModel
+	showRequestDetail bool
 
 	// Application state
 	currentTab      Tab
@@ -42,35 +92,170 @@ type ViewModel struct {
 	ready           bool
 	timeFilter      TimeFilter
 	sortOrder       SortOrder
+	requestFilter   entity.Filter
 	timezone        *time.Location
 	refreshInterval time.Duration
+	altScreen       bool
+	keys            KeyMap
+	costFormat      entity.CostFormat
+
+	// Optional server ingestion lag shown in the footer
+	ingestionLagQuery *usecase.GetIngestionLagQuery
+	ingestionLag      entity.IngestionLag
+
+	// Optional server retention preview shown in the footer
+	retentionQuery *usecase.GetRetentionQuery
+	retention      entity.Retention
+
+	// Optional starring of requests kept from the retention cleanup
+	starCommand *usecase.StarApiRequestCommand
+
+	// Optional daily goal streak shown in the stats box
+	streakQuery *usecase.GetStreakQuery
+
+	// Optional requests pushed by the server, polling falls back to the refresh interval when the stream ends
+	watchQuery     *usecase.WatchApiRequestsQuery
+	watchEvents    chan tea.Msg
+	refreshPending bool
+	lastRefreshAt  time.Time
+
+	// Last known server state, notified when it changes
+	serverUnreachable bool
+	ingestionLagAlert bool
 }
 
 // NewViewModel creates a new refactored ViewModel with component models
 func NewViewModel(getFilteredQuery *usecase.GetFilteredApiRequestsQuery, calculateStatsQuery *usecase.CalculateStatsQuery, getUsageQuery *usecase.GetUsageQuery, timezone *time.Location, block *entity.Block, refreshInterval time.Duration) *ViewModel {
 	return &ViewModel{
-		overviewTab:     NewOverviewTabModel(calculateStatsQuery, getFilteredQuery, timezone, block),
-		dailyUsageTab:   NewDailyUsageTabModel(getUsageQuery, timezone),
-		currentTab:      TabCurrent,
-		timeFilter:      FilterAll,
-		sortOrder:       SortDescending,
-		timezone:        timezone,
-		refreshInterval: refreshInterval,
+		overviewTab:        NewOverviewTabModel(calculateStatsQuery, getFilteredQuery, timezone, block),
+		dailyUsageTab:      NewDailyUsageTabModel(getUsageQuery, timezone),
+		sessionsTab:        NewSessionsTabModel(getFilteredQuery, timezone),
+		notificationCenter: NewNotificationCenterModel(timezone),
+		serverPanel:        NewServerPanelModel(timezone),
+		blockHistory:       NewBlockHistoryModel(timezone),
+		requestDetail:      NewRequestDetailModel(timezone),
+		currentTab:         TabCurrent,
+		timeFilter:         FilterAll,
+		sortOrder:          SortDescending,
+		timezone:           timezone,
+		refreshInterval:    refreshInterval,
+		altScreen:          true,
+		keys:               DefaultKeyMap(),
+	}
+}
+
+// SetKeyMap rebinds the keys of the monitor actions, e.g. filters and tab switching
+func (vm *ViewModel) SetKeyMap(keys KeyMap) {
+	vm.keys = keys
+}
+
+// SetAltScreen controls whether the monitor enters the alternate screen or renders inline
+func (vm *ViewModel) SetAltScreen(enabled bool) {
+	vm.altScreen = enabled
+}
+
+// SetHighlight sets the thresholds above which requests are highlighted in the requests table
+func (vm *ViewModel) SetHighlight(highlight entity.Highlight) {
+	vm.overviewTab.SetHighlight(highlight)
+}
+
+// SetCostFormat sets the display format of the costs in every tab and panel
+func (vm *ViewModel) SetCostFormat(format entity.CostFormat) {
+	vm.costFormat = format
+	vm.overviewTab.SetCostFormat(format)
+	vm.dailyUsageTab.SetCostFormat(format)
+	vm.sessionsTab.SetCostFormat(format)
+	vm.blockHistory.SetCostFormat(format)
+	if vm.leaderboardTab != nil {
+		vm.leaderboardTab.SetCostFormat(format)
 	}
 }
 
+// SetCostAnomalyPolicy marks the days of the daily usage tab costing more than the multiple of their 7-day baseline
+func (vm *ViewModel) SetCostAnomalyPolicy(policy entity.CostAnomalyPolicy) {
+	vm.dailyUsageTab.SetCostAnomalyPolicy(policy)
+}
+
+// SetRequestFilter narrows the requests table by the filter dimensions, the period follows the time filter
+func (vm *ViewModel) SetRequestFilter(filter entity.Filter) {
+	vm.requestFilter = filter
+}
+
+// SetIngestionLagQuery enables the ingestion lag footer using the given query
+func (vm *ViewModel) SetIngestionLagQuery(ingestionLagQuery *usecase.GetIngestionLagQuery) {
+	vm.ingestionLagQuery = ingestionLagQuery
+}
+
+// SetIngestStatsQuery enables the server panel using the given query
+func (vm *ViewModel) SetIngestStatsQuery(ingestStatsQuery *usecase.GetIngestStatsQuery) {
+	vm.ingestStatsQuery = ingestStatsQuery
+}
+
+// SetBlockHistoryQuery enables the block history using the given query
+func (vm *ViewModel) SetBlockHistoryQuery(blockHistoryQuery *usecase.GetBlockHistoryQuery) {
+	vm.blockHistoryQuery = blockHistoryQuery
+}
+
+// SetRetentionQuery enables the retention preview footer using the given query
+func (vm *ViewModel) SetRetentionQuery(retentionQuery *usecase.GetRetentionQuery) {
+	vm.retentionQuery = retentionQuery
+}
+
+// SetStreakQuery enables the daily goal widget using the given query, a disabled goal hides it
+func (vm *ViewModel) SetStreakQuery(streakQuery *usecase.GetStreakQuery) {
+	vm.streakQuery = streakQuery
+}
+
+// SetWatchQuery enables refreshing when the server pushes new requests instead of polling on every tick
+func (vm *ViewModel) SetWatchQuery(watchQuery *usecase.WatchApiRequestsQuery) {
+	vm.watchQuery = watchQuery
+}
+
+// SetSessionTitlesQuery enables listing hot sessions and the sessions tab by their transcript titles
+func (vm *ViewModel) SetSessionTitlesQuery(sessionTitlesQuery *usecase.GetSessionTitlesQuery) {
+	vm.overviewTab.SetSessionTitlesQuery(sessionTitlesQuery)
+	vm.sessionsTab.SetSessionTitlesQuery(sessionTitlesQuery)
+}
+
+// SetStarCommand enables starring the selected request with the "*" key
+func (vm *ViewModel) SetStarCommand(starCommand *usecase.StarApiRequestCommand) {
+	vm.starCommand = starCommand
+	vm.overviewTab.SetStarCommand(starCommand)
+}
+
+// SetLeaderboard enables the leaderboard tab, the viewer is highlighted and private shows only the viewer's rank
+func (vm *ViewModel) SetLeaderboard(getLeaderboardQuery *usecase.GetLeaderboardQuery, viewer string, private bool) {
+	vm.leaderboardTab = NewLeaderboardTabModel(getLeaderboardQuery, viewer, private)
+	vm.leaderboardTab.SetCostFormat(vm.costFormat)
+	vm.leaderboardTab.SetSize(vm.width, vm.height)
+}
+
 // Init is the Bubble Tea initialization function
 func (vm *ViewModel) Init() tea.Cmd {
 	// Ensure the current tab is focused on startup
 	vm.overviewTab.Focus()
 	vm.dailyUsageTab.Blur()
+	vm.sessionsTab.Blur()
+	if vm.leaderboardTab != nil {
+		vm.leaderboardTab.Blur()
+	}
+
+	var altScreenCmd tea.Cmd
+	if vm.altScreen {
+		altScreenCmd = tea.EnterAltScreen
+	}
 
 	return tea.Batch(
-		tea.EnterAltScreen,
+		altScreenCmd,
 		vm.overviewTab.Init(),
 		vm.dailyUsageTab.Init(),
-		vm.refreshStats, // Load initial data from database
-		vm.tick(),       // Start periodic refresh
+		vm.sessionsTab.Init(),
+		vm.refreshInitialStats, // Load initial data from database
+		vm.tick(),              // Start periodic refresh
+		vm.refreshIngestionLag(),
+		vm.refreshRetention(),
+		vm.refreshStreak(),
+		vm.startWatch(),
 	)
 }
 
@@ -80,30 +265,71 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if vm.showNotifications {
+			return vm, vm.updateNotificationCenter(msg)
+		}
+		if vm.showServerPanel {
+			return vm, vm.updateServerPanel(msg)
+		}
+		if vm.showBlockHistory {
+			return vm, vm.updateBlockHistory(msg)
+		}
+		if vm.showRequestDetail {
+			return vm, vm.updateRequestDetail(msg)
+		}
+		if vm.searching {
+			return vm, vm.updateSearch(msg)
+		}
+
+		if msg.String() == "ctrl+c" {
+			return vm, tea.Quit
+		}
+
+		action, _ := vm.keys.Action(msg.String())
+		switch action {
+		case KeyQuit:
 			return vm, tea.Quit
-		case "a":
+		case KeyNotifications:
+			vm.showNotifications = true
+			vm.notificationCenter.MarkRead()
+			return vm, nil
+		case KeyServerPanel:
+			if vm.ingestStatsQuery != nil {
+				vm.showServerPanel = true
+				return vm, vm.refreshIngestStats()
+			}
+		case KeyBlockHistory:
+			if vm.blockHistoryQuery != nil {
+				vm.showBlockHistory = true
+				return vm, vm.refreshBlockHistory()
+			}
+		case KeySearch:
+			if vm.currentTab == TabCurrent {
+				vm.searching = true
+				vm.searchInput = vm.search
+				return vm, nil
+			}
+		case KeyFilterAll:
 			vm.timeFilter = FilterAll
 			return vm, vm.refreshStats
-		case "h":
+		case KeyFilterHour:
 			vm.timeFilter = FilterHour
 			return vm, vm.refreshStats
-		case "d":
+		case KeyFilterDay:
 			vm.timeFilter = FilterDay
 			return vm, vm.refreshStats
-		case "w":
+		case KeyFilterWeek:
 			vm.timeFilter = FilterWeek
 			return vm, vm.refreshStats
-		case "m":
+		case KeyFilterMonth:
 			vm.timeFilter = FilterMonth
 			return vm, vm.refreshStats
-		case "b":
+		case KeyFilterBlock:
 			if vm.Block() != nil {
 				vm.timeFilter = FilterBlock
 				return vm, vm.refreshStats
 			}
-		case "o":
+		case KeySortToggle:
 			// Toggle sort order
 			if vm.sortOrder == SortDescending {
 				vm.sortOrder = SortAscending
@@ -111,25 +337,20 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				vm.sortOrder = SortDescending
 			}
 			return vm, vm.refreshStats
-		case "tab":
-			// Switch tabs
-			if vm.currentTab == TabCurrent {
-				// Blur current tab and focus daily tab
-				vm.overviewTab.Blur()
-				vm.currentTab = TabDaily
-				vm.dailyUsageTab.Focus()
-				return vm, vm.refreshUsage
-			} else {
-				// Blur daily tab and focus current tab
-				vm.dailyUsageTab.Blur()
-				vm.currentTab = TabCurrent
-				vm.overviewTab.Focus()
-				return vm, vm.refreshStats
-			}
+		case KeyRefresh:
+			// Force refresh when the numbers may be served from a cache
+			return vm, vm.forceRefreshCurrentTab()
+		case KeyTabNext:
+			// Switch tabs: Current, Daily Usage, Sessions and back to Current
+			return vm, vm.switchTab()
 		default:
 			// Forward key messages to active tab
 			switch vm.currentTab {
 			case TabCurrent:
+				if msg.String() == "enter" {
+					vm.openRequestDetail()
+					return vm, nil
+				}
 				_, cmd := vm.overviewTab.Update(msg)
 				if cmd != nil {
 					cmds = append(cmds, cmd)
@@ -139,9 +360,22 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if cmd != nil {
 					cmds = append(cmds, cmd)
 				}
+			case TabSessions:
+				_, cmd := vm.sessionsTab.Update(msg)
+				if cmd != nil {
+					cmds = append(cmds, cmd)
+				}
+			case TabLeaderboard:
+				_, cmd := vm.leaderboardTab.Update(msg)
+				if cmd != nil {
+					cmds = append(cmds, cmd)
+				}
 			}
 		}
 
+	case tea.MouseMsg:
+		return vm, vm.updateMouse(msg)
+
 	case tea.WindowSizeMsg:
 		vm.width = msg.Width
 		vm.height = msg.Height
@@ -151,6 +385,13 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		resizeMsg := ResizeMsg{Width: msg.Width, Height: msg.Height}
 		_, cmd1 := vm.overviewTab.Update(resizeMsg)
 		_, cmd2 := vm.dailyUsageTab.Update(resizeMsg)
+		vm.sessionsTab.Update(resizeMsg)
+		vm.notificationCenter.Update(resizeMsg)
+		vm.blockHistory.Update(resizeMsg)
+		vm.requestDetail.Update(resizeMsg)
+		if vm.leaderboardTab != nil {
+			vm.leaderboardTab.Update(resizeMsg)
+		}
 
 		if cmd1 != nil {
 			cmds = append(cmds, cmd1)
@@ -161,19 +402,91 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		// Periodic refresh - refresh based on current tab
-		if vm.currentTab == TabDaily {
-			return vm, tea.Batch(vm.tick(), vm.refreshUsage)
+		// Pushed requests refresh the data already, polling only keeps rolling time windows moving
+		// The ingest stats are only fetched while the server panel is shown
+		var ingestStatsCmd tea.Cmd
+		if vm.showServerPanel {
+			ingestStatsCmd = vm.refreshIngestStats()
+		}
+		if vm.Watching() && time.Time(msg).Sub(vm.lastRefreshAt) < watchRefreshInterval {
+			return vm, tea.Batch(vm.tick(), vm.refreshIngestionLag(), vm.refreshRetention(), ingestStatsCmd)
+		}
+		return vm, tea.Batch(vm.tick(), vm.refreshCurrentTab(), vm.refreshIngestionLag(), vm.refreshRetention(), ingestStatsCmd)
+
+	case RequestsPushedMsg:
+		// A burst of pushed requests is refreshed once after a short delay
+		cmds = append(cmds, vm.waitForWatch())
+		if !vm.refreshPending {
+			vm.refreshPending = true
+			cmds = append(cmds, tea.Tick(watchRefreshDelay, func(time.Time) tea.Msg {
+				return watchRefreshMsg{}
+			}))
+		}
+
+	case watchRefreshMsg:
+		vm.refreshPending = false
+		return vm, vm.refreshCurrentTab()
+
+	case WatchEndedMsg:
+		// Servers predating the stream and replicas never push, the monitor keeps polling and tries again later
+		vm.watchEvents = nil
+		return vm, tea.Tick(watchRetryInterval, func(time.Time) tea.Msg {
+			return watchRetryMsg{}
+		})
+
+	case watchRetryMsg:
+		return vm, vm.startWatch()
+
+	case IngestionLagMsg:
+		if msg.Err != nil {
+			// Keep the last known lag when the server is unreachable
+			vm.notifyServerUnreachable(msg.Err)
+			break
+		}
+		vm.notifyServerReachable()
+		vm.notifyIngestionLag(msg.Lag)
+		vm.ingestionLag = msg.Lag
+
+	case IngestStatsMsg:
+		if msg.Err != nil {
+			vm.notifyServerUnreachable(msg.Err)
 		} else {
-			return vm, tea.Batch(vm.tick(), vm.refreshStats)
+			vm.notifyServerReachable()
 		}
+		vm.serverPanel.Update(msg)
+
+	case BlockHistoryMsg:
+		vm.blockHistory.Update(msg)
+
+	case RetentionMsg:
+		if msg.Err != nil {
+			// Keep the last known retention when the server is unreachable
+			vm.notifyServerUnreachable(msg.Err)
+			break
+		}
+		vm.notifyServerReachable()
+		vm.notifyCleanup(msg.Retention)
+		vm.retention = msg.Retention
+
+	case StreakMsg:
+		// Keep the last known streak when the usage lookup fails
+		if msg.Err == nil {
+			vm.overviewTab.SetStreak(vm.streakQuery.Goal(), msg.Streak)
+		}
+
+	case NotificationMsg:
+		vm.notify(msg.Notification.Level, msg.Notification.Message)
 
 	case refreshStatsMsg:
 		// Send refresh messages to overview tab with current period
 		if vm.currentTab == TabCurrent {
 			period := vm.getTimePeriod()
 			// Refresh both stats and requests
-			statsCmd := vm.overviewTab.RefreshStats(period)
-			requestsCmd := vm.overviewTab.RefreshRequests(period, vm.sortOrder)
+			statsCmd := vm.overviewTab.RefreshStats(period, msg.approximate)
+			if msg.fresh {
+				statsCmd = vm.overviewTab.ForceRefreshStats(period)
+			}
+			requestsCmd := vm.overviewTab.RefreshRequests(vm.overviewFilter().WithPeriod(period), vm.sortOrder)
 			if statsCmd != nil {
 				cmds = append(cmds, statsCmd)
 			}
@@ -181,6 +494,13 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				cmds = append(cmds, requestsCmd)
 			}
 		}
+		// The sessions tab follows the time filter and request filter of the current tab
+		if vm.currentTab == TabSessions {
+			_, cmd := vm.sessionsTab.Update(SessionsRefreshMsg{Filter: vm.requestFilter.WithPeriod(vm.getTimePeriod())})
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
 	case refreshUsageMsg:
 		// Send refresh message to daily usage tab
 		if vm.currentTab == TabDaily {
@@ -190,6 +510,15 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case refreshLeaderboardMsg:
+		// Send refresh message to leaderboard tab
+		if vm.currentTab == TabLeaderboard {
+			_, cmd := vm.leaderboardTab.Update(LeaderboardRefreshMsg{})
+			if cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+
 	case StatsDataMsg:
 		// Forward stats data to overview tab
 		_, cmd := vm.overviewTab.Update(msg)
@@ -204,12 +533,58 @@ func (vm *ViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case SessionTitlesMsg:
+		// Forward session titles to overview tab
+		_, cmd := vm.overviewTab.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case StarredMsg:
+		if msg.Err != nil {
+			vm.notify(NotificationWarning, "Star failed: "+msg.Err.Error())
+		}
+
+		// Forward star outcome to overview tab
+		_, cmd := vm.overviewTab.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case SessionsDataMsg:
+		if msg.Err != nil {
+			vm.notifyServerUnreachable(msg.Err)
+		}
+
+		// Forward sessions data to sessions tab
+		_, cmd := vm.sessionsTab.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
+	case SessionsTitlesMsg:
+		// Forward session titles to sessions tab
+		_, cmd := vm.sessionsTab.Update(msg)
+		if cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case UsageDataMsg:
 		// Forward usage data to daily usage tab
 		_, cmd := vm.dailyUsageTab.Update(msg)
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+
+	case LeaderboardDataMsg:
+		if msg.Err != nil {
+			vm.notifyServerUnreachable(msg.Err)
+		}
+
+		// Forward the ranking to leaderboard tab
+		if vm.leaderboardTab != nil {
+			vm.leaderboardTab.Update(msg)
+		}
 	}
 
 	return vm, tea.Batch(cmds...)
@@ -222,66 +597,239 @@ func (vm *ViewModel) View() string {
 	}
 
 	// Common header
-	content := TitleStyle.Render("🖥️  Claude Code Monitor") + "\n"
+	content := TitleStyle.Render(monitorTitle) + "\n"
+
+	// The request detail takes the whole screen so no field is cut off
+	if vm.showRequestDetail {
+		content += "\n" + vm.requestDetail.View()
+		content += HelpStyle.Render("\n  esc/enter: Close • " + vm.keys.Help(KeyQuit) + ": Quit")
+		return content
+	}
+
 	content += vm.renderTabNavigation() + "\n"
 
 	// Tab-specific content
-	switch vm.currentTab {
-	case TabCurrent:
-		// Status line for current tab
-		content += StatusStyle.Render("Monitor Mode | Filter: "+vm.GetTimeFilterString()+" | Sort: "+vm.GetSortOrderString()) + "\n\n"
-		content += vm.overviewTab.View()
-	case TabDaily:
-		content += "\n" + vm.dailyUsageTab.View()
+	switch {
+	case vm.showNotifications:
+		content += "\n" + vm.notificationCenter.View()
+	case vm.showServerPanel:
+		content += "\n" + vm.serverPanel.View()
+	case vm.showBlockHistory:
+		content += "\n" + vm.blockHistory.View()
+	case vm.currentTab == TabCurrent:
+		content += vm.renderTabHeader() + vm.overviewTab.View()
+	case vm.currentTab == TabDaily:
+		content += vm.renderTabHeader() + vm.dailyUsageTab.View()
+	case vm.currentTab == TabSessions:
+		content += vm.renderTabHeader() + vm.sessionsTab.View()
+	case vm.currentTab == TabLeaderboard:
+		content += vm.renderTabHeader() + vm.leaderboardTab.View()
 	}
 
 	// Help text
 	content += vm.renderHelpText()
 
+	// Ingestion lag footer
+	content += vm.renderIngestionLag()
+
+	// Retention preview footer
+	content += vm.renderRetention()
+
 	return content
 }
 
+// tabNames are the labels of the tabs in the navigation bar
+var tabNames = map[Tab]string{
+	TabCurrent:     "Current",
+	TabDaily:       "Daily Usage",
+	TabSessions:    "Sessions",
+	TabLeaderboard: "Leaderboard",
+}
+
+// tabNavigationSeparator separates the tabs in the navigation bar
+const tabNavigationSeparator = "  "
+
+// tabs returns the tabs in the navigation bar, the leaderboard only when enabled
+func (vm *ViewModel) tabs() []Tab {
+	tabs := []Tab{TabCurrent, TabDaily, TabSessions}
+	if vm.leaderboardTab != nil {
+		tabs = append(tabs, TabLeaderboard)
+	}
+	return tabs
+}
+
+// renderTabLabel renders the label of the tab, the current tab in brackets
+func (vm *ViewModel) renderTabLabel(tab Tab) string {
+	if vm.currentTab == tab {
+		return StatStyle.Bold(true).Render("[" + tabNames[tab] + "]")
+	}
+	return HelpStyle.Render(" " + tabNames[tab] + " ")
+}
+
 // renderTabNavigation renders the tab navigation bar
 func (vm *ViewModel) renderTabNavigation() string {
-	currentTabStyle := StatStyle.Bold(true)
-	inactiveTabStyle := HelpStyle
+	labels := make([]string, 0, len(tabNames))
+	for _, tab := range vm.tabs() {
+		labels = append(labels, vm.renderTabLabel(tab))
+	}
+	content := strings.Join(labels, tabNavigationSeparator)
 
-	var content string
-	if vm.currentTab == TabCurrent {
-		content += currentTabStyle.Render("[Current]")
-	} else {
-		content += inactiveTabStyle.Render(" Current ")
+	if unread := vm.notificationCenter.Unread(); unread > 0 {
+		content += "  " + WarningStyle.Render(fmt.Sprintf("🔔 %d", unread))
 	}
 
-	content += "  "
+	return content
+}
 
-	if vm.currentTab == TabDaily {
-		content += currentTabStyle.Render("[Daily Usage]")
-	} else {
-		content += inactiveTabStyle.Render(" Daily Usage ")
+// renderTabHeader renders the lines between the tab navigation bar and the content of the current tab
+func (vm *ViewModel) renderTabHeader() string {
+	switch vm.currentTab {
+	case TabCurrent:
+		// Status line for current tab, the search prompt takes the blank line below it
+		header := StatusStyle.Render(vm.statusLine()) + "\n"
+		if vm.searching {
+			return header + StatStyle.Render("  Search model or session: "+vm.searchInput+"█") + "\n"
+		}
+		return header + "\n"
+	case TabSessions:
+		return StatusStyle.Render(vm.statusLine()) + "\n\n"
+	default:
+		return "\n"
 	}
+}
 
-	return content
+// tabNavigationLine returns the line of the screen showing the tab navigation bar
+func (vm *ViewModel) tabNavigationLine() int {
+	return strings.Count(TitleStyle.Render(monitorTitle)+"\n", "\n")
+}
+
+// tabContentTop returns the line of the screen where the content of the current tab starts
+func (vm *ViewModel) tabContentTop() int {
+	return vm.tabNavigationLine() + strings.Count(vm.renderTabNavigation()+"\n"+vm.renderTabHeader(), "\n")
+}
+
+// tabAt returns the tab whose label is at the column of the navigation bar
+func (vm *ViewModel) tabAt(x int) (Tab, bool) {
+	left := 0
+	for _, tab := range vm.tabs() {
+		right := left + lipgloss.Width(vm.renderTabLabel(tab))
+		if x >= left && x < right {
+			return tab, true
+		}
+		left = right + len(tabNavigationSeparator)
+	}
+	return TabCurrent, false
 }
 
 // renderHelpText renders the help text based on current tab
 func (vm *ViewModel) renderHelpText() string {
 	var helpText string
 
+	keys := vm.keys
+	quit := " • " + keys.Help(KeyQuit) + ": Quit"
+	if vm.showNotifications {
+		return HelpStyle.Render("\n  ↑/↓: Navigate • x=dismiss • c=clear all • " + keys.Help(KeyNotifications) + "/esc: Close" + quit)
+	}
+	if vm.showServerPanel {
+		return HelpStyle.Render("\n  " + keys.Help(KeyRefresh) + "=refresh • " + keys.Help(KeyServerPanel) + "/esc: Close" + quit)
+	}
+	if vm.showBlockHistory {
+		return HelpStyle.Render("\n  ↑/↓: Navigate • " + keys.Help(KeyRefresh) + "=refresh • " + keys.Help(KeyBlockHistory) + "/esc: Close" + quit)
+	}
+	if vm.searching {
+		return HelpStyle.Render("\n  enter: Apply (empty clears) • ctrl+u: Clear input • esc: Cancel")
+	}
+
+	// Keys shared by every tab
+	common := " • " + keys.Help(KeyRefresh) + "=refresh • " + keys.Help(KeyNotifications) + "=notifications" + vm.serverPanelHelp() + vm.blockHistoryHelp() + " • " + tabKeyHelp(keys.Help(KeyTabNext)) + ": Switch tabs" + quit
+
 	switch vm.currentTab {
 	case TabCurrent:
-		helpText = "\n  ↑/↓: Navigate • Time: h=hour d=day w=week m=month a=all"
-		if vm.Block() != nil {
-			helpText += " b=block"
+		helpText = "\n  ↑/↓: Navigate • " + vm.timeFilterHelp()
+		helpText += " • " + keys.Help(KeySortToggle) + "=sort • s=by model • " + keys.Help(KeySearch) + "=search • t=relative time • v=select • enter=detail"
+		if vm.starCommand != nil {
+			helpText += " • *=star"
 		}
-		helpText += " • o=sort • Tab: Switch tabs • q: Quit"
+		helpText += common
 	case TabDaily:
-		helpText = "\n  ↑/↓: Navigate • Tab: Switch tabs • q: Quit"
+		helpText = "\n  ↑/↓: Navigate • ←/→: Previous/next 30 days • g=hourly/daily • c=daily/weekly/monthly" + common
+	case TabSessions:
+		helpText = "\n  ↑/↓: Navigate • enter=expand/collapse • " + vm.timeFilterHelp() + common
+	case TabLeaderboard:
+		helpText = "\n  ↑/↓: Navigate • p=private" + common
 	}
 
 	return HelpStyle.Render(helpText)
 }
 
+// timeFilterHelp returns the help of the time filter keys, the block filter only with a block
+func (vm *ViewModel) timeFilterHelp() string {
+	keys := vm.keys
+	help := "Time: " + keys.Help(KeyFilterHour) + "=hour " + keys.Help(KeyFilterDay) + "=day " + keys.Help(KeyFilterWeek) + "=week " +
+		keys.Help(KeyFilterMonth) + "=month " + keys.Help(KeyFilterAll) + "=all"
+	if vm.Block() != nil {
+		help += " " + keys.Help(KeyFilterBlock) + "=block"
+	}
+	return help
+}
+
+// tabKeyHelp returns the help of the tab switching key, the default "tab" is shown as "Tab"
+func tabKeyHelp(key string) string {
+	if key == "tab" {
+		return "Tab"
+	}
+	return key
+}
+
+// serverPanelHelp returns the help of the server panel key, empty when the panel is disabled
+func (vm *ViewModel) serverPanelHelp() string {
+	if vm.ingestStatsQuery == nil {
+		return ""
+	}
+	return " • " + vm.keys.Help(KeyServerPanel) + "=ingest"
+}
+
+// blockHistoryHelp returns the help of the block history key, empty without a block
+func (vm *ViewModel) blockHistoryHelp() string {
+	if vm.blockHistoryQuery == nil {
+		return ""
+	}
+	return " • " + vm.keys.Help(KeyBlockHistory) + "=blocks"
+}
+
+// renderIngestionLag renders the server ingestion lag footer, empty until lag is reported
+func (vm *ViewModel) renderIngestionLag() string {
+	if vm.ingestionLag.IsEmpty() {
+		return ""
+	}
+
+	lagText := "\n  Ingestion lag: avg " + FormatDurationFromTime(vm.ingestionLag.Average()) +
+		" • max " + FormatDurationFromTime(vm.ingestionLag.Max())
+	if vm.ingestionLag.Average() >= ingestionLagWarningThreshold {
+		return WarningStyle.Render(lagText + " (exporter may be buffering)")
+	}
+	return StatusStyle.Render(lagText)
+}
+
+// renderRetention renders the server retention preview footer, empty unless the server purges old records
+func (vm *ViewModel) renderRetention() string {
+	if !vm.retention.IsEnabled() {
+		return ""
+	}
+
+	retentionText := "\n  Retention: " + FormatRetention(vm.retention.Duration())
+	if !vm.retention.IsScheduled() {
+		return StatusStyle.Render(retentionText + " • first cleanup pending")
+	}
+
+	cutoff := FormatDateShortTime(vm.retention.NextCutoff().In(vm.timezone))
+	until := vm.retention.TimeUntilCleanup(time.Now())
+	if until == 0 {
+		return StatusStyle.Render(retentionText + " • records before " + cutoff + " will be removed shortly")
+	}
+	return StatusStyle.Render(retentionText + " • records before " + cutoff + " will be removed in " + FormatDurationFromTime(until))
+}
+
 // Business logic methods
 func (vm *ViewModel) GetTimeFilterString() string {
 	switch vm.timeFilter {
@@ -314,6 +862,35 @@ func (vm *ViewModel) GetSortOrderString() string {
 	}
 }
 
+// statusLine renders the filter and sort order of the current tab, including the request filter dimensions
+func (vm *ViewModel) statusLine() string {
+	// Only the requests table of the current tab is searched
+	filter := vm.requestFilter
+	if vm.currentTab == TabCurrent {
+		filter = vm.overviewFilter()
+	}
+
+	status := "Monitor Mode | Filter: " + vm.GetTimeFilterString()
+	if filter.HasDimensions() {
+		status += " (" + filter.String() + ")"
+	}
+	status += " | Sort: " + vm.GetSortOrderString()
+	if vm.Watching() {
+		status += " | Live"
+	}
+	if vm.currentTab == TabCurrent {
+		status += " | " + FormatStatsFreshness(vm.overviewTab.statsModel.Stats(), time.Now())
+	}
+
+	// Daily totals cover a 23 or 25 hour day when the clocks change
+	if vm.timezone != nil {
+		if note := FormatClockShift(entity.ClockShift(time.Now(), vm.timezone)); note != "" {
+			status += " | " + note
+		}
+	}
+	return status
+}
+
 func (vm *ViewModel) getTimePeriod() entity.Period {
 	switch vm.timeFilter {
 	case FilterHour:
@@ -334,14 +911,379 @@ func (vm *ViewModel) getTimePeriod() entity.Period {
 	}
 }
 
+// switchTab moves focus to the next tab and refreshes its data
+func (vm *ViewModel) switchTab() tea.Cmd {
+	tabs := vm.tabs()
+	for i, tab := range tabs {
+		if tab == vm.currentTab {
+			return vm.selectTab(tabs[(i+1)%len(tabs)])
+		}
+	}
+	return vm.selectTab(TabCurrent)
+}
+
+// selectTab moves focus to the tab and refreshes its data
+func (vm *ViewModel) selectTab(tab Tab) tea.Cmd {
+	switch vm.currentTab {
+	case TabDaily:
+		vm.dailyUsageTab.Blur()
+	case TabSessions:
+		vm.sessionsTab.Blur()
+	case TabLeaderboard:
+		vm.leaderboardTab.Blur()
+	default:
+		vm.overviewTab.Blur()
+	}
+
+	vm.currentTab = tab
+	switch tab {
+	case TabDaily:
+		vm.dailyUsageTab.Focus()
+		return vm.refreshUsage
+	case TabSessions:
+		vm.sessionsTab.Focus()
+		return vm.refreshStats
+	case TabLeaderboard:
+		vm.leaderboardTab.Focus()
+		return vm.refreshLeaderboard
+	default:
+		vm.overviewTab.Focus()
+		return vm.refreshStats
+	}
+}
+
+// refreshCurrentTab returns a command that refreshes the data shown in the current tab
+func (vm *ViewModel) refreshCurrentTab() tea.Cmd {
+	vm.lastRefreshAt = time.Now()
+
+	switch vm.currentTab {
+	case TabDaily:
+		return vm.refreshUsage
+	case TabSessions:
+		return vm.refreshStats
+	case TabLeaderboard:
+		return vm.refreshLeaderboard
+	default:
+		return tea.Batch(vm.refreshStats, vm.refreshStreak())
+	}
+}
+
+// forceRefreshCurrentTab returns a command that refreshes the current tab skipping the cached stats
+func (vm *ViewModel) forceRefreshCurrentTab() tea.Cmd {
+	vm.lastRefreshAt = time.Now()
+
+	switch vm.currentTab {
+	case TabDaily:
+		return vm.refreshUsage
+	case TabSessions:
+		return vm.refreshStats
+	case TabLeaderboard:
+		return vm.refreshLeaderboard
+	default:
+		return tea.Batch(vm.forceRefreshStats, vm.refreshStreak())
+	}
+}
+
 func (vm *ViewModel) refreshStats() tea.Msg {
 	return refreshStatsMsg{}
 }
 
+func (vm *ViewModel) forceRefreshStats() tea.Msg {
+	return refreshStatsMsg{fresh: true}
+}
+
+// refreshInitialStats loads estimated stats first, so huge databases show numbers before the exact scan completes
+func (vm *ViewModel) refreshInitialStats() tea.Msg {
+	return refreshStatsMsg{approximate: true}
+}
+
 func (vm *ViewModel) refreshUsage() tea.Msg {
 	return refreshUsageMsg{}
 }
 
+func (vm *ViewModel) refreshLeaderboard() tea.Msg {
+	return refreshLeaderboardMsg{}
+}
+
+// refreshIngestionLag returns a command that fetches the server ingestion lag, nil when disabled
+func (vm *ViewModel) refreshIngestionLag() tea.Cmd {
+	if vm.ingestionLagQuery == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		lag, err := vm.ingestionLagQuery.Execute(context.Background())
+		return IngestionLagMsg{Lag: lag, Err: err}
+	}
+}
+
+// refreshIngestStats returns a command that fetches the server ingest stats, nil when disabled
+func (vm *ViewModel) refreshIngestStats() tea.Cmd {
+	if vm.ingestStatsQuery == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		stats, err := vm.ingestStatsQuery.Execute(context.Background())
+		return IngestStatsMsg{Stats: stats, Err: err}
+	}
+}
+
+// refreshBlockHistory returns a command that lists the usage of the current and past blocks, nil when disabled
+func (vm *ViewModel) refreshBlockHistory() tea.Cmd {
+	if vm.blockHistoryQuery == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		blocks, err := vm.blockHistoryQuery.Execute(context.Background(), time.Now(), usecase.DefaultBlockHistoryBlocks)
+		return BlockHistoryMsg{Blocks: blocks, Err: err}
+	}
+}
+
+// refreshRetention returns a command that fetches the server retention policy, nil when disabled
+func (vm *ViewModel) refreshRetention() tea.Cmd {
+	if vm.retentionQuery == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		retention, err := vm.retentionQuery.Execute(context.Background())
+		return RetentionMsg{Retention: retention, Err: err}
+	}
+}
+
+// refreshStreak returns a command that counts the daily goal streak, nil when no goal is configured
+func (vm *ViewModel) refreshStreak() tea.Cmd {
+	if vm.streakQuery == nil || !vm.streakQuery.Goal().IsEnabled() {
+		return nil
+	}
+
+	return func() tea.Msg {
+		streak, err := vm.streakQuery.Execute(context.Background())
+		return StreakMsg{Streak: streak, Err: err}
+	}
+}
+
+// startWatch returns a command that waits for the requests pushed by the server, nil when disabled
+// Every request is watched as the stats cover all of them, not only the filtered requests table
+func (vm *ViewModel) startWatch() tea.Cmd {
+	if vm.watchQuery == nil {
+		return nil
+	}
+
+	// The pushed requests are only a signal to refresh, a pending signal covers the next ones
+	events := make(chan tea.Msg, 1)
+	vm.watchEvents = events
+	watchQuery := vm.watchQuery
+	go func() {
+		err := watchQuery.Execute(context.Background(), entity.NewFilter(entity.NewAllTimePeriod(time.Now())), func(requests []entity.APIRequest) error {
+			select {
+			case events <- RequestsPushedMsg{Count: len(requests)}:
+			default:
+			}
+			return nil
+		})
+		events <- WatchEndedMsg{Err: err}
+	}()
+
+	return vm.waitForWatch()
+}
+
+// waitForWatch returns a command that waits for the next message of the watch stream
+func (vm *ViewModel) waitForWatch() tea.Cmd {
+	events := vm.watchEvents
+	if events == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+// updateMouse switches tabs clicked in the navigation bar and forwards the wheel and clicks below it to the current tab
+func (vm *ViewModel) updateMouse(msg tea.MouseMsg) tea.Cmd {
+	// Panels and the search prompt cover the tab, they are only controlled with the keys
+	if vm.showNotifications || vm.showServerPanel || vm.showBlockHistory || vm.showRequestDetail || vm.searching {
+		return nil
+	}
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+
+	if msg.Y == vm.tabNavigationLine() {
+		if msg.Button != tea.MouseButtonLeft {
+			return nil
+		}
+		if tab, ok := vm.tabAt(msg.X); ok && tab != vm.currentTab {
+			return vm.selectTab(tab)
+		}
+		return nil
+	}
+
+	// The tabs locate their tables from the first line of their own view
+	top := vm.tabContentTop()
+	if msg.Y < top && !tea.MouseEvent(msg).IsWheel() {
+		return nil
+	}
+	msg.Y -= top
+
+	switch vm.currentTab {
+	case TabCurrent:
+		_, cmd := vm.overviewTab.Update(msg)
+		return cmd
+	case TabDaily:
+		_, cmd := vm.dailyUsageTab.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+// updateNotificationCenter handles keys while the notification center is open
+func (vm *ViewModel) updateNotificationCenter(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch {
+	case key == "ctrl+c" || vm.keys.Is(key, KeyQuit):
+		return tea.Quit
+	case key == "esc" || vm.keys.Is(key, KeyNotifications):
+		vm.showNotifications = false
+		vm.notificationCenter.MarkRead()
+		return nil
+	}
+
+	_, cmd := vm.notificationCenter.Update(msg)
+	return cmd
+}
+
+// updateSearch handles keys while the search of the requests table is typed
+// The search is only applied on enter, so the server is not queried for every key
+func (vm *ViewModel) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		vm.searching = false
+	case tea.KeyEnter:
+		vm.searching = false
+		vm.search = strings.TrimSpace(vm.searchInput)
+		return vm.refreshStats
+	case tea.KeyBackspace:
+		if runes := []rune(vm.searchInput); len(runes) > 0 {
+			vm.searchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlU:
+		vm.searchInput = ""
+	case tea.KeySpace:
+		vm.searchInput += " "
+	case tea.KeyRunes:
+		vm.searchInput += string(msg.Runes)
+	}
+	return nil
+}
+
+// overviewFilter returns the request filter of the requests table, narrowed by the search
+func (vm *ViewModel) overviewFilter() entity.Filter {
+	return vm.requestFilter.WithSearch(vm.search)
+}
+
+// updateServerPanel handles keys while the server panel is open
+func (vm *ViewModel) updateServerPanel(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch {
+	case key == "ctrl+c" || vm.keys.Is(key, KeyQuit):
+		return tea.Quit
+	case key == "esc" || vm.keys.Is(key, KeyServerPanel):
+		vm.showServerPanel = false
+	case vm.keys.Is(key, KeyRefresh):
+		return vm.refreshIngestStats()
+	}
+	return nil
+}
+
+// updateBlockHistory handles keys while the block history is open
+func (vm *ViewModel) updateBlockHistory(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch {
+	case key == "ctrl+c" || vm.keys.Is(key, KeyQuit):
+		return tea.Quit
+	case key == "esc" || vm.keys.Is(key, KeyBlockHistory):
+		vm.showBlockHistory = false
+	case vm.keys.Is(key, KeyRefresh):
+		return vm.refreshBlockHistory()
+	default:
+		vm.blockHistory.Update(msg)
+	}
+	return nil
+}
+
+// openRequestDetail shows every field of the request under the cursor of the requests table
+func (vm *ViewModel) openRequestDetail() {
+	request, ok := vm.overviewTab.requestsTableModel.SelectedRequest()
+	if !ok {
+		return
+	}
+	vm.requestDetail.SetRequest(request)
+	vm.showRequestDetail = true
+}
+
+// updateRequestDetail handles the keys while the request detail is shown
+func (vm *ViewModel) updateRequestDetail(msg tea.KeyMsg) tea.Cmd {
+	key := msg.String()
+	switch {
+	case key == "ctrl+c" || vm.keys.Is(key, KeyQuit):
+		return tea.Quit
+	case key == "esc" || key == "enter":
+		vm.showRequestDetail = false
+	}
+	return nil
+}
+
+// notify adds a notification, read right away while the notification center is open
+func (vm *ViewModel) notify(level NotificationLevel, message string) {
+	vm.notificationCenter.Notify(Notification{At: time.Now(), Level: level, Message: message})
+	if vm.showNotifications {
+		vm.notificationCenter.MarkRead()
+	}
+}
+
+// notifyServerUnreachable notifies when the server stops answering
+func (vm *ViewModel) notifyServerUnreachable(err error) {
+	if vm.serverUnreachable {
+		return
+	}
+	vm.serverUnreachable = true
+	vm.notify(NotificationWarning, "Server unreachable: "+entity.ErrorMessage(err))
+}
+
+// notifyServerReachable notifies when the server answers again after being unreachable
+func (vm *ViewModel) notifyServerReachable() {
+	if !vm.serverUnreachable {
+		return
+	}
+	vm.serverUnreachable = false
+	vm.notify(NotificationInfo, "Server reconnected")
+}
+
+// notifyIngestionLag notifies when the average ingestion lag crosses the warning threshold
+func (vm *ViewModel) notifyIngestionLag(lag entity.IngestionLag) {
+	alert := !lag.IsEmpty() && lag.Average() >= ingestionLagWarningThreshold
+	if alert && !vm.ingestionLagAlert {
+		vm.notify(NotificationWarning, "Ingestion lag alert: avg "+FormatDurationFromTime(lag.Average())+", exporter may be buffering")
+	}
+	vm.ingestionLagAlert = alert
+}
+
+// notifyCleanup notifies when the scheduled retention cleanup has run since the last refresh
+func (vm *ViewModel) notifyCleanup(retention entity.Retention) {
+	if !vm.retention.IsScheduled() || !retention.IsScheduled() || !retention.NextCleanupAt().After(vm.retention.NextCleanupAt()) {
+		return
+	}
+
+	cutoff := FormatDateShortTime(vm.retention.NextCutoff().In(vm.timezone))
+	vm.notify(NotificationInfo, "Retention cleanup ran, records before "+cutoff+" removed")
+}
+
 // tick returns a command that sends a tick message using the configured refresh interval
 func (vm *ViewModel) tick() tea.Cmd {
 	return tea.Tick(vm.refreshInterval, func(t time.Time) tea.Msg {
@@ -382,6 +1324,16 @@ func (vm *ViewModel) BlockStats() entity.Stats {
 	return vm.overviewTab.statsModel.BlockStats()
 }
 
+// DailyUsageTab returns the daily usage tab model
+func (vm *ViewModel) DailyUsageTab() *DailyUsageTabModel {
+	return vm.dailyUsageTab
+}
+
+// SessionsTab returns the sessions tab model
+func (vm *ViewModel) SessionsTab() *SessionsTabModel {
+	return vm.sessionsTab
+}
+
 func (vm *ViewModel) Requests() []entity.APIRequest {
 	// Return requests from overview tab requests table model
 	return vm.overviewTab.requestsTableModel.Requests()
@@ -392,6 +1344,69 @@ func (vm *ViewModel) Table() table.Model {
 	return vm.overviewTab.requestsTableModel.table
 }
 
+func (vm *ViewModel) AltScreen() bool {
+	return vm.altScreen
+}
+
+func (vm *ViewModel) NotificationCenter() *NotificationCenterModel {
+	return vm.notificationCenter
+}
+
+func (vm *ViewModel) ShowNotifications() bool {
+	return vm.showNotifications
+}
+
+func (vm *ViewModel) ServerPanel() *ServerPanelModel {
+	return vm.serverPanel
+}
+
+func (vm *ViewModel) ShowServerPanel() bool {
+	return vm.showServerPanel
+}
+
+func (vm *ViewModel) BlockHistory() *BlockHistoryModel {
+	return vm.blockHistory
+}
+
+func (vm *ViewModel) ShowBlockHistory() bool {
+	return vm.showBlockHistory
+}
+
+// Search returns the applied search of the requests table, empty when every request is shown
+func (vm *ViewModel) Search() string {
+	return vm.search
+}
+
+// Searching returns true while the search of the requests table is typed
+func (vm *ViewModel) Searching() bool {
+	return vm.searching
+}
+
+func (vm *ViewModel) RequestDetail() *RequestDetailModel {
+	return vm.requestDetail
+}
+
+func (vm *ViewModel) ShowRequestDetail() bool {
+	return vm.showRequestDetail
+}
+
+func (vm *ViewModel) IngestionLag() entity.IngestionLag {
+	return vm.ingestionLag
+}
+
+func (vm *ViewModel) Retention() entity.Retention {
+	return vm.retention
+}
+
+func (vm *ViewModel) Streak() entity.Streak {
+	return vm.overviewTab.statsModel.Streak()
+}
+
+// Watching returns true while the server pushes new requests
+func (vm *ViewModel) Watching() bool {
+	return vm.watchEvents != nil
+}
+
 func (vm *ViewModel) TokenLimit() int {
 	if vm.Block() != nil {
 		return vm.Block().TokenLimit()
@@ -401,5 +1416,39 @@ func (vm *ViewModel) TokenLimit() int {
 
 // Message types
 type tickMsg time.Time
-type refreshStatsMsg struct{}
+type refreshStatsMsg struct {
+	approximate bool
+	fresh       bool // skips the cached stats
+}
 type refreshUsageMsg struct{}
+type refreshLeaderboardMsg struct{}
+type watchRefreshMsg struct{}
+type watchRetryMsg struct{}
+
+// RequestsPushedMsg signals requests pushed by the server since the last message
+type RequestsPushedMsg struct {
+	Count int
+}
+
+// WatchEndedMsg signals the server stopped pushing requests
+type WatchEndedMsg struct {
+	Err error // set when the server is unreachable or does not support watching
+}
+
+// IngestionLagMsg carries the server ingestion lag for the footer
+type IngestionLagMsg struct {
+	Lag entity.IngestionLag
+	Err error // set when the server is unreachable
+}
+
+// RetentionMsg carries the server retention policy for the footer
+type RetentionMsg struct {
+	Retention entity.Retention
+	Err       error // set when the server is unreachable
+}
+
+// StreakMsg carries the daily goal streak for the stats box
+type StreakMsg struct {
+	Streak entity.Streak
+	Err    error // set when the usage lookup fails
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runImportTranscripts backfills the database from Claude Code transcripts and returns the exit code
+// Requests already stored are skipped, so importing ~/.claude/projects again only adds the new messages
+func runImportTranscripts(config *Config, path string) int {
+	path = expandPath(path)
+
+	pricingRepository, err := repository.NewEmbeddedPricingRepository(dataFS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize pricing repository: %v\n", err)
+		return 1
+	}
+
+	// The database is locked while the server runs, stop it before importing
+	db, err := NewDatabase(config.Database.Path)
+	if errors.Is(err, ErrDatabaseLocked) {
+		fmt.Fprintf(os.Stderr, "A running ccmon server is using %s.\n", config.Database.Path)
+		fmt.Fprintf(os.Stderr, "Stop the server before importing, or use --database-path for a scratch database.\n")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	// Imported requests are mixed into the history, keep the database as it was for db undo-last
+	if !writeMaintenanceSnapshot(db, config.Database.Path, "import") {
+		return 1
+	}
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	command := usecase.NewImportTranscriptsCommand(repository.NewClaudeTranscriptUsageRepository(), repo, pricingRepository)
+	result, err := command.Execute(context.Background(), usecase.ImportTranscriptsParams{Path: path})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to import %s: %v\n", path, err)
+		return 1
+	}
+
+	fmt.Printf("Imported %d requests from %s into %s (%d found, %d already stored)\n", result.Imported, path, config.Database.Path, result.Found, result.Duplicates)
+	if result.Unpriced > 0 {
+		fmt.Printf("%d requests of models without a price were imported with a zero cost\n", result.Unpriced)
+	}
+	if result.Imported > 0 {
+		fmt.Printf("Run ccmon db undo-last to remove the imported requests\n")
+	}
+	return 0
+}
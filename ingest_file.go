@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/grpc/receiver"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runIngestFile replays a captured OTLP log export file into the database and returns the exit code
+// It reuses the receiver of server mode, so ignore rules, clock skew handling and plugins apply like a live export
+func runIngestFile(config *Config, path string) int {
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Missing file, usage: ccmon ingest-file <path>\n")
+		return 1
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", path, err)
+		return 1
+	}
+
+	reqs, err := receiver.DecodeExportLogsRequests(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decode %s: %v\n", path, err)
+		return 1
+	}
+
+	ignoreRules, err := config.Receiver.GetIgnoreRules()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid ignore rules: %v\n", err)
+		return 1
+	}
+	clockSkew, err := config.Receiver.GetClockSkewPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid clock skew policy: %v\n", err)
+		return 1
+	}
+	processors, err := createProcessors(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid receiver plugins: %v\n", err)
+		return 1
+	}
+
+	// The database is locked while the server runs, stop it or use --database-path for a scratch database
+	db, err := NewDatabase(config.Database.Path)
+	if errors.Is(err, ErrDatabaseLocked) {
+		fmt.Fprintf(os.Stderr, "A running ccmon server is using %s.\n", config.Database.Path)
+		fmt.Fprintf(os.Stderr, "Stop the server before replaying, or use --database-path for a scratch database.\n")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	otlpReceiver := receiver.NewReceiverWithBatch(nil, nil, usecase.NewAppendApiRequestBatchCommand(repo), ignoreRules)
+	otlpReceiver.SetClockSkewPolicy(clockSkew)
+	otlpReceiver.SetOrigin(entity.OriginImport)
+	// Plugins see the import origin, so forwarding plugins can skip backfills
+	for _, processor := range processors {
+		otlpReceiver.RegisterProcessor(processor)
+	}
+	defer otlpReceiver.CloseProcessors()
+
+	for _, req := range reqs {
+		if _, err := otlpReceiver.GetLogsServiceServer().Export(context.Background(), req); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to ingest %s: %v\n", path, err)
+			return 1
+		}
+	}
+
+	fmt.Printf("Ingested %d export requests from %s into %s\n", len(reqs), path, config.Database.Path)
+	return 0
+}
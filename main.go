@@ -2,13 +2,18 @@ package main
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/handler/cli"
 	grpcserver "github.com/elct9620/ccmon/handler/grpc"
+	"github.com/elct9620/ccmon/handler/grpc/receiver"
+	httpapi "github.com/elct9620/ccmon/handler/http"
 	"github.com/elct9620/ccmon/handler/tui"
 	"github.com/elct9620/ccmon/repository"
 	"github.com/elct9620/ccmon/service"
@@ -40,16 +45,213 @@ func createStatsCache(cacheConfig CacheStats) usecase.StatsCache {
 	return service.NewInMemoryStatsCache(ttl)
 }
 
+// createSessionTitleRepository creates the repository reading session titles from Claude Code transcripts, returns nil when disabled
+func createSessionTitleRepository(claudeConfig Claude) usecase.SessionTitleRepository {
+	if claudeConfig.Transcripts == "" {
+		return nil
+	}
+
+	return repository.NewClaudeTranscriptRepository(claudeConfig.Transcripts)
+}
+
+// createDailySummary creates the end-of-day summary of server mode, the command is nil when no time is set
+func createDailySummary(config *Config, calculateStatsQuery *usecase.CalculateStatsQuery, timezone *time.Location) (grpcserver.DailySummary, error) {
+	schedule, err := config.Server.DailySummary.GetSchedule(timezone)
+	if err != nil {
+		return grpcserver.DailySummary{}, fmt.Errorf("invalid daily summary time: %w", err)
+	}
+	if !schedule.IsEnabled() {
+		return grpcserver.DailySummary{}, nil
+	}
+
+	planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
+	if err != nil {
+		return grpcserver.DailySummary{}, fmt.Errorf("failed to initialize plan repository: %w", err)
+	}
+
+	costFormat := config.Display.GetCostFormat()
+	var notifier usecase.DailySummaryNotifier
+	if config.Server.DailySummary.Webhook != "" {
+		notifier = service.NewWebhookNotifier(config.Server.DailySummary.Webhook, costFormat)
+	}
+
+	// Today follows the monitor timezone, unlike the UTC periods of the rest of server mode
+	command := usecase.NewSendDailySummaryCommand(calculateStatsQuery, planRepository, service.NewTimePeriodFactory(timezone), notifier)
+	return grpcserver.DailySummary{Command: command, Schedule: schedule, CostFormat: costFormat}, nil
+}
+
+// createThrottleSignal creates the throttle signal writer of server mode, the command is nil when no path is set
+func createThrottleSignal(config *Config, statsRepo usecase.StatsRepository, timezone *time.Location) (grpcserver.ThrottleSignal, error) {
+	if !config.Server.Throttle.IsEnabled() {
+		return grpcserver.ThrottleSignal{}, nil
+	}
+
+	policy, err := config.Server.Throttle.GetPolicy(timezone, config.Claude.GetTokenLimit())
+	if err != nil {
+		return grpcserver.ThrottleSignal{}, fmt.Errorf("invalid throttle signal: %w", err)
+	}
+	policy = policy.WithBlockDuration(config.Claude.GetBlockDuration())
+	interval, err := config.Server.Throttle.GetInterval()
+	if err != nil {
+		return grpcserver.ThrottleSignal{}, fmt.Errorf("invalid throttle signal: %w", err)
+	}
+
+	// The block period stays the same for hours, cached stats would hold the signal back for the cache TTL
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	command := usecase.NewWriteThrottleSignalCommand(calculateStatsQuery, repository.NewFileThrottleSignalRepository(config.Server.Throttle.Path), policy)
+	return grpcserver.ThrottleSignal{Command: command, Interval: interval}, nil
+}
+
+// createAlerts creates the budget alert evaluator of server mode, the command is nil when no rule is set
+func createAlerts(config *Config, statsRepo usecase.StatsRepository, timezone *time.Location) (grpcserver.Alerts, error) {
+	if !config.Alerts.IsEnabled() {
+		return grpcserver.Alerts{}, nil
+	}
+
+	policy, err := config.Alerts.GetPolicy(timezone, config.Claude.GetTokenLimit())
+	if err != nil {
+		return grpcserver.Alerts{}, fmt.Errorf("invalid alerts: %w", err)
+	}
+	policy = policy.WithBlockDuration(config.Claude.GetBlockDuration())
+	webhooks, err := config.Alerts.GetWebhooks()
+	if err != nil {
+		return grpcserver.Alerts{}, fmt.Errorf("invalid alerts: %w", err)
+	}
+
+	// Rules are evaluated right after each batch is stored, cached stats would miss it
+	costFormat := config.Display.GetCostFormat()
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	command := usecase.NewEvaluateAlertsCommand(calculateStatsQuery, policy, service.NewAlertWebhookNotifier(webhooks, costFormat))
+	return grpcserver.Alerts{Command: command, CostFormat: costFormat}, nil
+}
+
+// createBackup creates the database backup scheduler of server mode, the command is nil when no backup path is set
+func createBackup(config *Config, snapshotRepo usecase.SnapshotRepository) (grpcserver.Backup, error) {
+	if !config.Database.Backup.IsEnabled() {
+		return grpcserver.Backup{}, nil
+	}
+
+	interval, err := config.Database.Backup.GetInterval()
+	if err != nil {
+		return grpcserver.Backup{}, fmt.Errorf("invalid database backup: %w", err)
+	}
+
+	// Backups are written from a read transaction, so ingestion continues while they run
+	backupRepo := repository.NewFileBackupRepository(config.Database.Backup.Path)
+	command := usecase.NewBackupDatabaseCommand(snapshotRepo, backupRepo)
+	return grpcserver.Backup{Command: command, Interval: interval}, nil
+}
+
+// createProcessors returns the receiver processors, the built-in pricing runs first so plugins see the filled costs
+func createProcessors(config *Config) ([]receiver.Processor, error) {
+	plugins, err := config.Receiver.GetPlugins()
+	if err != nil {
+		return nil, err
+	}
+	// Plugins are not started until the first request arrives
+	processors := make([]receiver.Processor, 0, len(plugins))
+	for _, plugin := range plugins {
+		processors = append(processors, receiver.NewExecProcessor(plugin.Name(), plugin.Command(), plugin.Timeout()))
+	}
+	if !config.Receiver.FillCosts {
+		return processors, nil
+	}
+
+	pricingRepository, err := repository.NewEmbeddedPricingRepository(dataFS)
+	if err != nil {
+		return nil, err
+	}
+	table, err := pricingRepository.GetPriceTable()
+	if err != nil {
+		return nil, err
+	}
+	return append([]receiver.Processor{receiver.NewPricingProcessor(table)}, processors...), nil
+}
+
+// detectProject returns the project of the @project_* format variables, the current directory name unless --project is given
+// It matches the project of requests reporting their working directory, so a status bar shows the cost of the repository it runs in
+func detectProject(project string) string {
+	if project != "" {
+		return project
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(dir)
+}
+
+// createBlock creates the current block from the --block flag, returns nil when not set
+func createBlock(blockTime string, timezone *time.Location, tokenLimit int, duration time.Duration) (*entity.Block, error) {
+	return createBlockAt(blockTime, timezone, tokenLimit, duration, time.Now())
+}
+
+// createBlockAt creates the block containing the given time from the --block flag, returns nil when not set
+// Blocks last the configured claude.block_duration
+func createBlockAt(blockTime string, timezone *time.Location, tokenLimit int, duration time.Duration, at time.Time) (*entity.Block, error) {
+	if blockTime == "" {
+		return nil, nil
+	}
+
+	startHour, err := entity.ParseBlockStartHour(blockTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block time format %s: %w", blockTime, err)
+	}
+
+	block := entity.NewCurrentBlockWithDuration(startHour, timezone, at, tokenLimit, duration)
+	return &block, nil
+}
+
 func main() {
 	// Parse command line flags using pflag
 	var serverMode bool
 	var blockTime string
 	var showVersion bool
 	var formatString string
+	var formatProject string
+	var statementMonth string
+	var statementOutput string
+	var statsPeriod string
+	var statsAt string
+	var statsOrigin string
+	var metricRaw bool
+	var metricPrecision int
+	var configWrite bool
+	var exportSinceLast bool
+	var exportStateFile string
+	var localDatabase bool
+	var mockMode bool
+	var reportDays int
+	var recalculateCosts bool
+	var overwriteCosts bool
+	var importPath string
+	var tailMode bool
+	var confirmYes bool
+	var checkDatabase bool
 	pflag.BoolVarP(&serverMode, "server", "s", false, "Run as OTLP server (headless mode)")
 	pflag.StringVarP(&blockTime, "block", "b", "", "Set block start time for token tracking (e.g., '5am', '11pm')")
 	pflag.BoolVarP(&showVersion, "version", "v", false, "Show version information")
-	pflag.StringVar(&formatString, "format", "", "Format string for quick query (e.g., '@daily_cost')")
+	pflag.StringVar(&formatString, "format", "", "Format string for quick query (e.g., '@daily_cost'), or the output format for the report command (md)")
+	pflag.StringVar(&formatProject, "project", "", "Project for the @project_* format variables (default current directory name)")
+	pflag.StringVar(&statementMonth, "month", "", "Month for the statement command (e.g., '2025-06', default current month)")
+	pflag.StringVar(&statementOutput, "output", cli.StatementOutputMarkdown, "Output format for the statement command (md, pdf), or the file to write for the export command (default stdout)")
+	pflag.StringVar(&statsPeriod, "period", cli.StatsPeriodDay, "Period for the query stats and metric commands (hour, day, week, month, block, all), or span for the export command (e.g. '30d', default all)")
+	pflag.StringVar(&statsAt, "at", "", "Point in time for the query stats and metric commands (e.g., '2025-06-01', default now)")
+	pflag.StringVar(&statsOrigin, "origin", "", "Only count live or imported records in the query stats and metric commands (live, import)")
+	pflag.BoolVar(&metricRaw, "raw", false, "Print only the bare number in the query metric command (no currency sign or separators)")
+	pflag.IntVar(&metricPrecision, "precision", cli.MetricDefaultPrecision, "Number of decimals in the query metric command (default depends on the metric)")
+	pflag.BoolVar(&configWrite, "write", false, "Rewrite the config files in the config migrate command")
+	pflag.BoolVar(&exportSinceLast, "since-last", false, "Only export records added since the previous export command")
+	pflag.StringVar(&exportStateFile, "state-file", ".ccmon-export.state", "File remembering the last exported record for the export command")
+	pflag.BoolVar(&localDatabase, "local", false, "Read the database file instead of the server in monitor, format query and export modes (the server must be stopped)")
+	pflag.IntVar(&reportDays, "days", cli.DefaultReportDays, "Number of days for the report daily command, today included")
+	pflag.BoolVar(&recalculateCosts, "recalculate-costs", false, "Fill the zero costs of stored requests from the embedded prices (the server must be stopped)")
+	pflag.BoolVar(&overwriteCosts, "overwrite-costs", false, "Also replace the costs reported by Claude Code in --recalculate-costs")
+	pflag.StringVar(&importPath, "import", "", "Backfill the database from Claude Code transcripts, a .jsonl file or a directory (e.g. '~/.claude/projects', the server must be stopped)")
+	pflag.BoolVarP(&confirmYes, "yes", "y", false, "Run destructive db commands without asking for confirmation")
+	pflag.BoolVar(&tailMode, "tail", false, "Print the requests the server stores from now on as log lines, until interrupted")
+	pflag.BoolVar(&checkDatabase, "check-database", false, "Verify every page of the database before the server starts, instead of only its header")
+	pflag.BoolVar(&mockMode, "mock", false, "Serve generated synthetic data in server mode, without OTLP or the database (e.g. 'ccmon serve --mock')")
 
 	// Add help flag
 	pflag.BoolP("help", "h", false, "Show help")
@@ -81,13 +283,101 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Subcommands are given as the first positional argument (e.g. `ccmon tmux-status -b 5am`)
+	switch pflag.Arg(0) {
+	case "":
+		// No subcommand, fall through to server or monitor mode
+	case "serve":
+		// Same as -s, reads better with --mock
+		serverMode = true
+	case "tmux-status":
+		os.Exit(runTmuxStatus(config, blockTime))
+	case "statement":
+		os.Exit(runStatement(config, statementMonth, statementOutput))
+	case "query":
+		// The period of a metric defaults to the one in its name, e.g. daily_cost, so only a given --period counts
+		metricOptions := queryMetricOptions{name: pflag.Arg(2), raw: metricRaw, precision: metricPrecision}
+		if pflag.CommandLine.Changed("period") {
+			metricOptions.period = statsPeriod
+		}
+		os.Exit(runQuery(config, pflag.Arg(1), blockTime, statsPeriod, statsAt, statsOrigin, metricOptions))
+	case "repl":
+		os.Exit(runRepl(config))
+	case "ingest-file":
+		os.Exit(runIngestFile(config, pflag.Arg(1)))
+	case "config":
+		os.Exit(runConfig(config, pflag.Arg(1), configWrite))
+	case "report":
+		// --format is the format string of the quick query, the report defaults to markdown
+		reportFormat := formatString
+		if reportFormat == "" {
+			reportFormat = cli.ReportFormatMarkdown
+		}
+		os.Exit(runReport(config, pflag.Arg(1), reportFormat, reportDays))
+	case "export":
+		// --period and --output have other defaults for the stats and statement commands, only values given for the export count
+		params := exportParams{
+			options:   cli.ExportOptions{Format: pflag.Arg(1), SinceLast: exportSinceLast},
+			stateFile: exportStateFile,
+			local:     localDatabase,
+		}
+		if pflag.CommandLine.Changed("period") {
+			params.options.Span = statsPeriod
+		}
+		if pflag.CommandLine.Changed("output") {
+			params.output = statementOutput
+		}
+		os.Exit(runExport(config, params))
+	case "db":
+		os.Exit(runDB(config, dbParams{action: pflag.Arg(1), file: pflag.Arg(2), yes: confirmYes}))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", pflag.Arg(0))
+		os.Exit(1)
+	}
+
+	if recalculateCosts {
+		os.Exit(runRecalculateCosts(config, overwriteCosts))
+	}
+
+	if importPath != "" {
+		os.Exit(runImportTranscripts(config, importPath))
+	}
+
+	if tailMode {
+		os.Exit(runTail(config))
+	}
+
+	if mockMode {
+		if !serverMode {
+			fmt.Fprintf(os.Stderr, "--mock is only supported in server mode, run ccmon serve --mock\n")
+			os.Exit(1)
+		}
+		os.Exit(runMockServer(config))
+	}
+
 	if serverMode {
 		// Server mode: Use BoltDB repository
-		db, err := NewDatabase(config.Database.Path)
+		db, recovery, err := OpenDatabaseWithRecovery(config.Database.Path, config.Database.Backup.Path, checkDatabase)
+		if errors.Is(err, ErrDatabaseLocked) {
+			// Only one server can own the database, a second one is usually meant to be a monitor
+			fmt.Fprintf(os.Stderr, "Another ccmon server is already using %s.\n", config.Database.Path)
+			fmt.Fprintf(os.Stderr, "Run ccmon without -s to monitor it, the monitor connects to %s through gRPC. Use --database-path to start a second server.\n", config.Monitor.Server)
+			os.Exit(1)
+		}
+		if errors.Is(err, ErrDatabaseCorrupt) {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			if config.Database.Backup.Path == "" {
+				fmt.Fprintf(os.Stderr, "Set database.backup.path to let the server restore the last backup automatically.\n")
+			}
+			os.Exit(1)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
 			os.Exit(1)
 		}
+		if recovery != nil {
+			log.Printf("WARNING: %s", recovery)
+		}
 		defer func() {
 			if err := db.Close(); err != nil {
 				log.Printf("Error closing database: %v", err)
@@ -95,6 +385,14 @@ func main() {
 		}()
 
 		repo := repository.NewBoltDBAPIRequestRepository(db)
+		// Databases predating the daily aggregates are scanned once, usage queries scan the requests until then
+		if built, err := repo.BuildDailyStats(); err != nil {
+			log.Printf("Failed to build daily stats, usage queries will scan every request: %v", err)
+		} else if built {
+			log.Printf("Built daily stats of %s", config.Database.Path)
+		}
+		starRepo := repository.NewBoltDBStarRepository(db)
+		snapshotRepo := repository.NewBoltDBSnapshotRepository(db)
 
 		// Create cache
 		statsCache := createStatsCache(config.Server.Cache.Stats)
@@ -103,58 +401,178 @@ func main() {
 		statsRepo := repository.NewBoltDBStatsRepository(repo)
 
 		// Create usecases
-		appendCommand := usecase.NewAppendApiRequestCommand(repo)
-		getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(repo)
+		// Stored requests are pushed to watching monitors through the feed
+		feed := service.NewInMemoryAPIRequestFeed(service.DefaultFeedBuffer)
+		// Cached stats covering the stored requests are evicted, so new usage shows up before the TTL expires
+		// Requests re-sent by retrying exporters within the window are dropped before they are stored again
+		var duplicates usecase.DuplicateFilter
+		if window, _ := config.Receiver.GetDedupeWindow(); window > 0 {
+			duplicates = service.NewInMemoryDuplicateFilter(window)
+			log.Printf("Receiver deduplication enabled: window %s", window)
+		}
+		appendBatchCommand := usecase.NewAppendApiRequestBatchCommandWithOptions(repo, usecase.AppendApiRequestBatchOptions{Feed: feed, StatsCache: statsCache, Duplicates: duplicates})
+		watchQuery := usecase.NewWatchApiRequestsQuery(feed)
+		getFilteredQuery := usecase.NewGetFilteredApiRequestsQueryWithStars(repo, starRepo)
 		calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, statsCache)
-		cleanupCommand := usecase.NewCleanupOldRecordsCommand(repo)
-		// Note: getUsageQuery would be used if we add usage endpoints to gRPC server
-		// Server mode uses UTC timezone for consistency
-		periodFactory := service.NewTimePeriodFactory(time.UTC)
-		_ = usecase.NewGetUsageQuery(repo, periodFactory) // Avoid unused variable
+		cleanupCommand := usecase.NewCleanupOldRecordsCommandWithStars(repo, starRepo)
+		starCommand := usecase.NewStarApiRequestCommand(starRepo)
+		getSnapshotQuery := usecase.NewGetSnapshotQuery(snapshotRepo)
 
-		// Run server with usecases
-		if err := grpcserver.RunServer(config.Server.Address, appendCommand, getFilteredQuery, calculateStatsQuery, cleanupCommand, &config.Server); err != nil {
-			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		// Replica mode: serve read-only queries from snapshots of the primary
+		if config.Server.Replica.IsEnabled() {
+			tlsOpts, err := tlsDialOptions(config.Server.Replica.TLS)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to configure TLS of the primary: %v\n", err)
+				os.Exit(1)
+			}
+			primaryRepo, err := repository.NewGRPCSnapshotRepository(config.Server.Replica.Primary, config.Server.Replica.Token, tlsOpts...)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to initialize primary snapshot repository: %v\n", err)
+				os.Exit(1)
+			}
+			defer func() {
+				if err := primaryRepo.Close(); err != nil {
+					log.Printf("Error closing primary snapshot repository: %v", err)
+				}
+			}()
+
+			syncCommand := usecase.NewSyncSnapshotCommand(primaryRepo, snapshotRepo)
+			if err := grpcserver.RunReplicaServer(config.Server.Address, getFilteredQuery, calculateStatsQuery, syncCommand, getSnapshotQuery, &config.Server.Replica, &config.Server); err != nil {
+				fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		// Ingestion ignore rules are validated when the config is loaded
+		ignoreRules, err := config.Receiver.GetIgnoreRules()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid ignore rules: %v\n", err)
 			os.Exit(1)
 		}
-	} else {
-		// Monitor mode: Use gRPC repository
-		repo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server)
+		clockSkew, err := config.Receiver.GetClockSkewPolicy()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Invalid clock skew policy: %v\n", err)
 			os.Exit(1)
 		}
-		defer func() {
-			if err := repo.Close(); err != nil {
-				log.Printf("Error closing gRPC repository: %v", err)
-			}
-		}()
 
-		// Create cache
+		processors, err := createProcessors(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid receiver plugins: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Telemetry gap days, the HTTP API daily usage, the daily summary, throttle signal and alert periods follow the monitor timezone
+		timezone, err := time.LoadLocation(config.Monitor.Timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+			os.Exit(1)
+		}
+		telemetryGap, err := config.Receiver.GetTelemetryGapPolicy(timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid telemetry gap policy: %v\n", err)
+			os.Exit(1)
+		}
+		// Usage of each user is checked against their quota, requests without a user are never capped
+		getUserUsageQuery := usecase.NewGetUserUsageQuery(repo, config.Quota.GetUserQuotas())
+		nowHandler := httpapi.NewNowHandler(calculateStatsQuery, timezone, config.Claude.GetTokenLimit())
+		usersHandler := httpapi.NewUsersHandler(getUserUsageQuery, timezone)
+		nowHandler.SetBlockDuration(config.Claude.GetBlockDuration())
+		usersHandler.SetBlockDuration(config.Claude.GetBlockDuration())
+		// Usage series are read from the daily aggregates, so charts of months don't scan every request
+		getUsageQuery := usecase.NewGetUsageQueryWithStats(repo, statsRepo, service.NewTimePeriodFactory(timezone))
+		seriesHandler := httpapi.NewSeriesHandler(getUsageQuery, timezone)
+		httpHandler := httpapi.NewHandlerWithOptions(httpapi.Handlers{Now: nowHandler, Users: usersHandler, Series: seriesHandler}, config.Server.HTTP.CORSOrigins)
+
+		dailySummary, err := createDailySummary(config, calculateStatsQuery, timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		throttleSignal, err := createThrottleSignal(config, statsRepo, timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		alerts, err := createAlerts(config, statsRepo, timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		backup, err := createBackup(config, snapshotRepo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		writeBehindInterval, err := config.Database.WriteBehind.GetInterval()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid database write-behind: %v\n", err)
+			os.Exit(1)
+		}
+		// Stored requests are batched into fewer write transactions, reads write the pending requests first
+		repo.StartWriteBehind(repository.WriteBehindOptions{Interval: writeBehindInterval, Size: config.Database.WriteBehind.Size})
+
+		// Run server with usecases
+		err = grpcserver.RunServer(grpcserver.ServerOptions{
+			Address:             config.Server.Address,
+			AppendBatchCommand:  appendBatchCommand,
+			GetFilteredQuery:    getFilteredQuery,
+			CalculateStatsQuery: calculateStatsQuery,
+			CleanupCommand:      cleanupCommand,
+			StarCommand:         starCommand,
+			GetSnapshotQuery:    getSnapshotQuery,
+			GetUserUsageQuery:   getUserUsageQuery,
+			WatchQuery:          watchQuery,
+			IgnoreRules:         ignoreRules,
+			ClockSkew:           clockSkew,
+			TelemetryGap:        telemetryGap,
+			Processors:          processors,
+			DailySummary:        dailySummary,
+			ThrottleSignal:      throttleSignal,
+			Alerts:              alerts,
+			Backup:              backup,
+			Workers:             &config.Receiver.Workers,
+			WriteBehind:         repo,
+			HTTPHandler:         httpHandler,
+			Config:              &config.Server,
+		})
+		// Pending requests are written before exiting, os.Exit skips the deferred database close
+		if stopErr := repo.StopWriteBehind(); stopErr != nil {
+			log.Printf("Failed to write pending requests: %v", stopErr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		// Monitor mode: Read through the server's gRPC service, or the database file with --local
+		// Create cache, requests pushed by the server evict the cached stats covering them
 		statsCache := createStatsCache(config.Server.Cache.Stats)
 
-		// Create gRPC stats repository for TUI mode
-		tuiStatsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server)
+		backend, closeBackend, err := openMonitorBackend(config, localDatabase, statsCache)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to initialize gRPC stats repository: %v\n", err)
+			fmt.Fprintf(os.Stderr, "%v\n", err)
 			os.Exit(1)
 		}
-		defer func() {
-			if err := tuiStatsRepo.Close(); err != nil {
-				log.Printf("Error closing TUI stats repository: %v", err)
-			}
-		}()
+		defer closeBackend()
+
+		repo := backend.requests
 
 		// Create query usecases (no append command needed for monitor)
-		getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(repo)
-		calculateStatsQuery := usecase.NewCalculateStatsQuery(tuiStatsRepo, statsCache)
+		getFilteredQuery := usecase.NewGetFilteredApiRequestsQueryWithStars(repo, backend.stars)
+		calculateStatsQuery := usecase.NewCalculateStatsQuery(backend.stats, statsCache)
 		timezone, err := time.LoadLocation(config.Monitor.Timezone)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
 			os.Exit(1)
 		}
 		periodFactory := service.NewTimePeriodFactory(timezone)
-		getUsageQuery := usecase.NewGetUsageQuery(repo, periodFactory)
+		// Usage is calculated by the server or from the daily aggregates instead of transferring every request
+		getUsageQuery := usecase.NewGetUsageQueryWithStats(repo, backend.stats, periodFactory)
 
 		// Convert config to TUI-specific struct
 		// Handle format query mode - bypass TUI and output directly to stdout
@@ -166,36 +584,56 @@ func main() {
 				os.Exit(1)
 			}
 
-			// Create gRPC stats repository for efficient stats retrieval
-			statsRepo, err := repository.NewGRPCStatsRepository(config.Monitor.Server)
+			// Block variables are only available when --block is given
+			block, err := createBlock(blockTime, timezone, config.Claude.GetTokenLimit(), config.Claude.GetBlockDuration())
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to initialize stats repository: %v\n", err)
+				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
-			defer func() {
-				if err := statsRepo.Close(); err != nil {
-					log.Printf("Error closing stats repository: %v", err)
-				}
-			}()
-
-			// Create CalculateStatsQuery that uses gRPC StatsRepository
-			formatCalculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, statsCache)
 
 			// Create GetUsageVariablesQuery with format-optimized dependencies
-			usageVariablesQuery := usecase.NewGetUsageVariablesQuery(
-				formatCalculateStatsQuery,
+			usageVariablesQuery := usecase.NewGetUsageVariablesQueryWithOptions(
+				calculateStatsQuery,
 				planRepository,
 				periodFactory,
+				usecase.UsageVariablesOptions{
+					Block:       block,
+					CostFormat:  config.Display.GetCostFormat(),
+					Budget:      config.Budget.GetBudget(),
+					Streak:      usecase.NewGetStreakQuery(getUsageQuery, config.Goal.GetGoal(), timezone),
+					CostAnomaly: usecase.NewGetCostAnomalyQuery(getUsageQuery, config.Anomaly.GetCostAnomalyPolicy(), timezone),
+					Project:     detectProject(formatProject),
+					User:        config.Claude.User,
+				},
 			)
 
 			// Create format renderer and query handler
 			renderer := cli.NewFormatRenderer(usageVariablesQuery)
-			queryHandler := cli.NewQueryHandler(renderer)
-
-			if err := queryHandler.HandleFormatQuery(formatString); err != nil {
-				os.Exit(1)
+			// Quota is checked against today's cost so hooks can stop agent loops from spending further
+			quota := config.Quota.GetQuota()
+			var checkQuotaQuery *usecase.CheckQuotaQuery
+			if quota.IsEnabled() {
+				checkQuotaQuery = usecase.NewCheckQuotaQuery(calculateStatsQuery, periodFactory, quota)
 			}
-			os.Exit(0)
+			queryHandler := cli.NewQueryHandlerWithQuota(renderer, checkQuotaQuery, quota.Warning())
+
+			// Scripts can tell the kind of failure from the exit code
+			os.Exit(cli.ExitCode(queryHandler.HandleFormatQuery(formatString)))
+		}
+
+		// Session titles are read from the local Claude Code transcripts, not from the server
+		var getSessionTitlesQuery *usecase.GetSessionTitlesQuery
+		if sessionTitleRepo := createSessionTitleRepository(config.Claude); sessionTitleRepo != nil {
+			getSessionTitlesQuery = usecase.NewGetSessionTitlesQuery(sessionTitleRepo)
+		}
+
+		// Users are ranked from the requests of the server, grouped by the monitor
+		getLeaderboardQuery := usecase.NewGetLeaderboardQuery(repo)
+
+		timeFormat, err := config.Display.GetTimeFormat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid display format: %v\n", err)
+			os.Exit(1)
 		}
 
 		monitorConfig := tui.MonitorConfig{
@@ -204,10 +642,25 @@ func main() {
 			RefreshInterval: config.Monitor.RefreshInterval,
 			TokenLimit:      config.Claude.GetTokenLimit(),
 			BlockTime:       blockTime,
+			BlockDuration:   config.Claude.GetBlockDuration(),
+			AltScreen:       config.Monitor.AltScreen,
+			Mouse:           config.Monitor.Mouse,
+			CostFormat:      config.Display.GetCostFormat(),
+			TimeFormat:      timeFormat,
+			Highlight:       config.Monitor.Highlight.GetHighlight(),
+			Filter:          config.Monitor.Filter.GetFilter(),
+			Goal:            config.Goal.GetGoal(),
+			CostAnomaly:     config.Anomaly.GetCostAnomalyPolicy(),
+			Leaderboard: tui.MonitorLeaderboard{
+				Enabled: config.Monitor.Leaderboard.Enabled,
+				Viewer:  config.Monitor.Leaderboard.User,
+				Private: config.Monitor.Leaderboard.Private,
+			},
+			Keys: config.Monitor.Keys,
 		}
 
 		// Run monitor with usecases and config - TUI handler owns block logic
-		if err := tui.RunMonitor(getFilteredQuery, calculateStatsQuery, getUsageQuery, monitorConfig); err != nil {
+		if err := tui.RunMonitor(getFilteredQuery, calculateStatsQuery, getUsageQuery, backend.ingestionLag, backend.ingestStats, backend.retention, backend.watch, backend.starCommand, getSessionTitlesQuery, getLeaderboardQuery, monitorConfig); err != nil {
 			fmt.Fprintf(os.Stderr, "Monitor error: %v\n", err)
 			os.Exit(1)
 		}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	grpcserver "github.com/elct9620/ccmon/handler/grpc"
+	httpapi "github.com/elct9620/ccmon/handler/http"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+const (
+	mockSeed     = 1                   // the same seed serves the same requests for the same hours on every run
+	mockHistory  = 30 * 24 * time.Hour // requests generated before the server starts
+	mockInterval = 5 * time.Second     // how often new requests are added
+)
+
+// runMockServer serves synthetic requests through the query service and returns the exit code
+// Nothing is read from or written to the database, so it can run next to a real server with another address
+func runMockServer(config *Config) int {
+	// Working hours of the generated sessions, day and block periods follow the monitor timezone
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	repo := repository.NewInMemoryAPIRequestRepository()
+	statsRepo := repository.NewBoltDBStatsRepository(repo)
+
+	feed := service.NewInMemoryAPIRequestFeed(service.DefaultFeedBuffer)
+	appendBatchCommand := usecase.NewAppendApiRequestBatchCommandWithFeed(repo, feed)
+	watchQuery := usecase.NewWatchApiRequestsQuery(feed)
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(repo)
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+
+	generator := service.NewMockRequestGenerator(mockSeed, timezone)
+	command := usecase.NewGenerateMockRequestsCommand(generator, appendBatchCommand, time.Now().Add(-mockHistory))
+	traffic := grpcserver.MockTraffic{Command: command, Interval: mockInterval}
+
+	getUserUsageQuery := usecase.NewGetUserUsageQuery(repo, config.Quota.GetUserQuotas())
+	nowHandler := httpapi.NewNowHandler(calculateStatsQuery, timezone, config.Claude.GetTokenLimit())
+	usersHandler := httpapi.NewUsersHandler(getUserUsageQuery, timezone)
+	nowHandler.SetBlockDuration(config.Claude.GetBlockDuration())
+	usersHandler.SetBlockDuration(config.Claude.GetBlockDuration())
+	getUsageQuery := usecase.NewGetUsageQueryWithStats(repo, statsRepo, service.NewTimePeriodFactory(timezone))
+	seriesHandler := httpapi.NewSeriesHandler(getUsageQuery, timezone)
+	httpHandler := httpapi.NewHandlerWithOptions(httpapi.Handlers{Now: nowHandler, Users: usersHandler, Series: seriesHandler}, config.Server.HTTP.CORSOrigins)
+
+	if err := grpcserver.RunMockServer(config.Server.Address, getFilteredQuery, calculateStatsQuery, getUserUsageQuery, watchQuery, traffic, httpHandler, &config.Server); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		return 1
+	}
+	return 0
+}
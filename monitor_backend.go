@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+	"google.golang.org/grpc"
+)
+
+// monitorBackend holds what the monitor and format query modes read usage from
+// Queries only a running server can answer, like the ingestion lag or new requests pushed as they arrive, are nil for the database file
+type monitorBackend struct {
+	requests     usecase.APIRequestRepository
+	stats        usecase.StatsRepository
+	stars        usecase.StarRepository // marks starred requests when the server does not
+	ingestionLag *usecase.GetIngestionLagQuery
+	ingestStats  *usecase.GetIngestStatsQuery
+	retention    *usecase.GetRetentionQuery
+	watch        *usecase.WatchApiRequestsQuery
+	starCommand  *usecase.StarApiRequestCommand
+}
+
+// dialMonitorServer connects to monitor.server with monitor.token, over TLS when monitor.tls is enabled
+func dialMonitorServer(config *Config) (*repository.GRPCConnection, error) {
+	opts, err := tlsDialOptions(config.Monitor.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewGRPCConnection(config.Monitor.Server, append(opts, repository.WithToken(config.Monitor.Token))...)
+}
+
+// tlsDialOptions returns the TLS credentials of a connection to a ccmon server, none keeps the insecure default
+func tlsDialOptions(clientTLS ClientTLS) ([]grpc.DialOption, error) {
+	if !clientTLS.Enabled {
+		return nil, nil
+	}
+	opt, err := repository.WithTLS(clientTLS.CAFile, clientTLS.CertFile, clientTLS.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{opt}, nil
+}
+
+// openMonitorBackend connects to the server at monitor.server, or opens the database file read-only when local is set
+// Requests pushed by the server evict the stats they change from statsCache
+// The returned function closes the connection or the database
+func openMonitorBackend(config *Config, local bool, statsCache usecase.StatsCache) (*monitorBackend, func(), error) {
+	if local {
+		return openLocalMonitorBackend(config)
+	}
+
+	// Repositories share a single connection to the server
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize gRPC connection: %w", err)
+	}
+	closeConn := func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}
+
+	repo := repository.NewGRPCAPIRequestRepositoryWithConnection(conn)
+	return &monitorBackend{
+		requests: repo,
+		// Fall back to client-side aggregation if the server predates GetStats
+		stats: repository.NegotiateStatsRepository(repository.NewGRPCStatsRepositoryWithConnection(conn), repo),
+		// Ingestion lag, ingest stats and the retention preview are tracked by the server
+		ingestionLag: usecase.NewGetIngestionLagQuery(repository.NewGRPCIngestionLagRepositoryWithConnection(conn)),
+		ingestStats:  usecase.NewGetIngestStatsQuery(repository.NewGRPCIngestStatsRepositoryWithConnection(conn)),
+		retention:    usecase.NewGetRetentionQuery(repository.NewGRPCRetentionRepositoryWithConnection(conn)),
+		// New requests are pushed by the server, the refresh interval only applies when it cannot push them
+		watch: usecase.NewWatchApiRequestsQueryWithStatsCache(repo, statsCache),
+		// Starred requests and sessions are kept by the server retention cleanup
+		starCommand: usecase.NewStarApiRequestCommand(repository.NewGRPCStarRepositoryWithConnection(conn)),
+	}, closeConn, nil
+}
+
+// openLocalMonitorBackend reads the database file directly, for inspecting historical usage while the server is stopped
+// The file is opened read-only, stars cannot be changed and the usage is reloaded on each refresh
+func openLocalMonitorBackend(config *Config) (*monitorBackend, func(), error) {
+	db, err := NewDatabaseReadOnly(config.Database.Path)
+	if errors.Is(err, ErrDatabaseLocked) {
+		return nil, nil, fmt.Errorf("a running ccmon server is using %s, run without --local to read it through the server", config.Database.Path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	closeDB := func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}
+
+	repo := repository.NewBoltDBAPIRequestRepository(db)
+	return &monitorBackend{
+		requests: repo,
+		stats:    repository.NewBoltDBStatsRepository(repo),
+		stars:    repository.NewBoltDBStarRepository(db),
+	}, closeDB, nil
+}
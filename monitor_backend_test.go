@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/elct9620/ccmon/service"
+)
+
+func TestOpenMonitorBackend_Local(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	createTestDatabase(t, dbPath)
+	config := &Config{Database: Database{Path: dbPath}}
+
+	backend, closeBackend, err := openMonitorBackend(config, true, &service.NoOpStatsCache{})
+	if err != nil {
+		t.Fatalf("Failed to open local backend: %v", err)
+	}
+	defer closeBackend()
+
+	if backend.requests == nil || backend.stats == nil || backend.stars == nil {
+		t.Error("Expected the requests, stats and stars to be read from the database")
+	}
+	// Only a running server tracks ingestion and pushes new requests, and the file is read-only
+	if backend.ingestionLag != nil || backend.ingestStats != nil || backend.retention != nil || backend.watch != nil || backend.starCommand != nil {
+		t.Error("Expected the server-only queries to be disabled")
+	}
+	if _, err := backend.stars.GetStars(); err != nil {
+		t.Errorf("Expected stars to be readable, got %v", err)
+	}
+}
+
+func TestOpenMonitorBackend_LocalLocked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+	db, err := NewDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("Failed to close database: %v", err)
+		}
+	}()
+
+	// The server holds the lock while it runs
+	_, _, err = openMonitorBackend(&Config{Database: Database{Path: dbPath}}, true, &service.NoOpStatsCache{})
+	if err == nil || !strings.Contains(err.Error(), "without --local") {
+		t.Errorf("Expected a hint to read through the server, got %v", err)
+	}
+}
+
+func TestOpenMonitorBackend_LocalMissing(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "ccmon.db")
+
+	if _, _, err := openMonitorBackend(&Config{Database: Database{Path: dbPath}}, true, &service.NoOpStatsCache{}); err == nil {
+		t.Error("Expected an error for a missing database file")
+	}
+}
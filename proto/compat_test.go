@@ -0,0 +1,124 @@
+package queryv1
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// apiLockPath records the published ccmon.v1 surface, entries may be added but never changed or removed
+const apiLockPath = "testdata/ccmon_v1.lock"
+
+var updateAPILock = flag.Bool("update-api-lock", false, "record new ccmon.v1 API entries in the lock file")
+
+// describeAPI lists every service, method, message and field which clients may depend on
+func describeAPI() []string {
+	// Published ccmon.v1 proto files
+	files := []protoreflect.FileDescriptor{
+		File_api_v1_query_proto,
+		File_api_v1_replication_proto,
+		File_api_v1_star_proto,
+	}
+
+	var entries []string
+	for _, file := range files {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			service := services.Get(i)
+			entries = append(entries, fmt.Sprintf("service %s", service.FullName()))
+
+			methods := service.Methods()
+			for j := 0; j < methods.Len(); j++ {
+				method := methods.Get(j)
+				entries = append(entries, fmt.Sprintf("rpc %s(%s%s) returns (%s%s)",
+					method.FullName(),
+					streamPrefix(method.IsStreamingClient()), method.Input().FullName(),
+					streamPrefix(method.IsStreamingServer()), method.Output().FullName()))
+			}
+		}
+
+		messages := file.Messages()
+		for i := 0; i < messages.Len(); i++ {
+			entries = append(entries, describeMessage(messages.Get(i))...)
+		}
+	}
+
+	sort.Strings(entries)
+	return entries
+}
+
+// describeMessage lists the message and its fields, field numbers and types are the wire contract
+func describeMessage(message protoreflect.MessageDescriptor) []string {
+	entries := []string{fmt.Sprintf("message %s", message.FullName())}
+
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldType := field.Kind().String()
+		if field.Message() != nil {
+			fieldType = string(field.Message().FullName())
+		}
+		entries = append(entries, fmt.Sprintf("field %s = %d %s %s", field.FullName(), field.Number(), field.Cardinality(), fieldType))
+	}
+
+	return entries
+}
+
+func streamPrefix(streaming bool) string {
+	if streaming {
+		return "stream "
+	}
+	return ""
+}
+
+func TestAPICompatibility(t *testing.T) {
+	content, err := os.ReadFile(apiLockPath)
+	if err != nil {
+		t.Fatalf("Failed to read API lock: %v", err)
+	}
+
+	locked := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			locked[line] = true
+		}
+	}
+
+	current := describeAPI()
+	published := make(map[string]bool)
+	var added []string
+	for _, entry := range current {
+		published[entry] = true
+		if !locked[entry] {
+			added = append(added, entry)
+		}
+	}
+
+	// Removed or changed entries break existing clients, they need a new API version instead
+	for entry := range locked {
+		if !published[entry] {
+			t.Errorf("Breaking change to ccmon.v1, no longer published: %s", entry)
+		}
+	}
+	if t.Failed() {
+		return
+	}
+
+	if len(added) == 0 {
+		return
+	}
+
+	if !*updateAPILock {
+		t.Fatalf("New ccmon.v1 entries are not recorded, run `go test ./proto -run TestAPICompatibility -update-api-lock`:\n%s", strings.Join(added, "\n"))
+	}
+
+	header := "# ccmon.v1 published API, generated by TestAPICompatibility\n# Entries may be added, but changing or removing one breaks existing clients\n"
+	if err := os.WriteFile(apiLockPath, []byte(header+strings.Join(current, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to update API lock: %v", err)
+	}
+}
@@ -2,7 +2,7 @@
 // versions:
 // 	protoc-gen-go v1.28.1
 // 	protoc        v6.30.2
-// source: proto/query.proto
+// source: api/v1/query.proto
 
 package queryv1
 
@@ -21,20 +21,76 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// StarScope represents which records a star keeps from the retention cleanup
+type StarScope int32
+
+const (
+	StarScope_STAR_SCOPE_UNSPECIFIED StarScope = 0 // Not starred
+	StarScope_STAR_SCOPE_REQUEST     StarScope = 1 // Only the starred request is kept
+	StarScope_STAR_SCOPE_SESSION     StarScope = 2 // Every request of the starred session is kept
+)
+
+// Enum value maps for StarScope.
+var (
+	StarScope_name = map[int32]string{
+		0: "STAR_SCOPE_UNSPECIFIED",
+		1: "STAR_SCOPE_REQUEST",
+		2: "STAR_SCOPE_SESSION",
+	}
+	StarScope_value = map[string]int32{
+		"STAR_SCOPE_UNSPECIFIED": 0,
+		"STAR_SCOPE_REQUEST":     1,
+		"STAR_SCOPE_SESSION":     2,
+	}
+)
+
+func (x StarScope) Enum() *StarScope {
+	p := new(StarScope)
+	*p = x
+	return p
+}
+
+func (x StarScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StarScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_v1_query_proto_enumTypes[0].Descriptor()
+}
+
+func (StarScope) Type() protoreflect.EnumType {
+	return &file_api_v1_query_proto_enumTypes[0]
+}
+
+func (x StarScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StarScope.Descriptor instead.
+func (StarScope) EnumDescriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{0}
+}
+
 // GetStatsRequest specifies time range for statistics
 type GetStatsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Optional: if not set, includes all time from beginning
-	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Optional: if not set, includes up to current time
+	StartTime   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Optional: if not set, includes all time from beginning
+	EndTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Optional: if not set, includes up to current time
+	At          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"`                                // Optional: stats as they were at this time, requests after it are excluded
+	Origin      string                 `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`                        // Optional: only requests of this origin, e.g. "live" to exclude imported records
+	Approximate bool                   `protobuf:"varint,5,opt,name=approximate,proto3" json:"approximate,omitempty"`             // Optional: allows an estimate from a sample of the requests for a fast first response
+	Project     string                 `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`                      // Optional: only requests of this project, ignored by servers predating projects
+	Fresh       bool                   `protobuf:"varint,7,opt,name=fresh,proto3" json:"fresh,omitempty"`                         // Optional: skips cached stats, servers predating it may return cached stats
+	User        string                 `protobuf:"bytes,8,opt,name=user,proto3" json:"user,omitempty"`                            // Optional: only requests of this user, ignored by servers predating per-user stats
 }
 
 func (x *GetStatsRequest) Reset() {
 	*x = GetStatsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[0]
+		mi := &file_api_v1_query_proto_msgTypes[0]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -47,7 +103,7 @@ func (x *GetStatsRequest) String() string {
 func (*GetStatsRequest) ProtoMessage() {}
 
 func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[0]
+	mi := &file_api_v1_query_proto_msgTypes[0]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,7 +116,7 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{0}
+	return file_api_v1_query_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *GetStatsRequest) GetStartTime() *timestamppb.Timestamp {
@@ -77,19 +133,63 @@ func (x *GetStatsRequest) GetEndTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GetStatsRequest) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *GetStatsRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *GetStatsRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *GetStatsRequest) GetFresh() bool {
+	if x != nil {
+		return x.Fresh
+	}
+	return false
+}
+
+func (x *GetStatsRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
 // GetStatsResponse contains aggregated statistics
 type GetStatsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Stats *Stats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Stats       *Stats                 `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Approximate bool                   `protobuf:"varint,2,opt,name=approximate,proto3" json:"approximate,omitempty"`          // True when the stats are estimated, servers predating estimates always return exact stats
+	CachedAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=cached_at,json=cachedAt,proto3" json:"cached_at,omitempty"` // When the stats were calculated if served from a cache, unset for freshly calculated stats
 }
 
 func (x *GetStatsResponse) Reset() {
 	*x = GetStatsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[1]
+		mi := &file_api_v1_query_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -102,7 +202,7 @@ func (x *GetStatsResponse) String() string {
 func (*GetStatsResponse) ProtoMessage() {}
 
 func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[1]
+	mi := &file_api_v1_query_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -115,7 +215,7 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{1}
+	return file_api_v1_query_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *GetStatsResponse) GetStats() *Stats {
@@ -125,6 +225,20 @@ func (x *GetStatsResponse) GetStats() *Stats {
 	return nil
 }
 
+func (x *GetStatsResponse) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *GetStatsResponse) GetCachedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CachedAt
+	}
+	return nil
+}
+
 // GetAPIRequestsRequest specifies filters for API requests
 type GetAPIRequestsRequest struct {
 	state         protoimpl.MessageState
@@ -135,12 +249,15 @@ type GetAPIRequestsRequest struct {
 	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Optional: if not set, includes up to current time
 	Limit     int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                         // Optional limit for number of results
 	Offset    int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`                       // Optional offset for pagination
+	Filter    *RequestFilter         `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`                        // Optional: narrows the period by request dimensions, ignored by servers predating it
+	Fields    []string               `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty"`                        // Optional: APIRequest field names to return, every field when empty or from servers predating it
+	PageToken string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Optional: next_page_token of the previous page, continues with older requests and cannot be combined with offset
 }
 
 func (x *GetAPIRequestsRequest) Reset() {
 	*x = GetAPIRequestsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[2]
+		mi := &file_api_v1_query_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -153,7 +270,7 @@ func (x *GetAPIRequestsRequest) String() string {
 func (*GetAPIRequestsRequest) ProtoMessage() {}
 
 func (x *GetAPIRequestsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[2]
+	mi := &file_api_v1_query_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -166,7 +283,7 @@ func (x *GetAPIRequestsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAPIRequestsRequest.ProtoReflect.Descriptor instead.
 func (*GetAPIRequestsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{2}
+	return file_api_v1_query_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetAPIRequestsRequest) GetStartTime() *timestamppb.Timestamp {
@@ -197,20 +314,138 @@ func (x *GetAPIRequestsRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *GetAPIRequestsRequest) GetFilter() *RequestFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetAPIRequestsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *GetAPIRequestsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// RequestFilter narrows API requests by their dimensions, empty fields match every request
+type RequestFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Model     string `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`                          // Exact model name
+	SessionId string `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Exact session ID
+	Source    string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                        // Exact telemetry source, e.g. "claude_code"
+	Origin    string `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`                        // Exact origin, "live" excludes imported records and "import" isolates them
+	User      string `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`                            // Exact user, e.g. "alice@example.com"
+	Project   string `protobuf:"bytes,6,opt,name=project,proto3" json:"project,omitempty"`                      // Exact project, e.g. "ccmon"
+	Search    string `protobuf:"bytes,7,opt,name=search,proto3" json:"search,omitempty"`                        // Model substring ignoring case or session ID prefix, e.g. "opus"
+}
+
+func (x *RequestFilter) Reset() {
+	*x = RequestFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestFilter) ProtoMessage() {}
+
+func (x *RequestFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestFilter.ProtoReflect.Descriptor instead.
+func (*RequestFilter) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RequestFilter) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *RequestFilter) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
 // GetAPIRequestsResponse contains API request records
 type GetAPIRequestsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Requests   []*APIRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
-	TotalCount int32         `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // Total count without pagination
+	Requests      []*APIRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	TotalCount    int32         `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`           // Total count without pagination
+	NextPageToken string        `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Set when older requests remain, empty on the last page and from servers predating it
 }
 
 func (x *GetAPIRequestsResponse) Reset() {
 	*x = GetAPIRequestsResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[3]
+		mi := &file_api_v1_query_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -223,7 +458,7 @@ func (x *GetAPIRequestsResponse) String() string {
 func (*GetAPIRequestsResponse) ProtoMessage() {}
 
 func (x *GetAPIRequestsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[3]
+	mi := &file_api_v1_query_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -236,7 +471,7 @@ func (x *GetAPIRequestsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAPIRequestsResponse.ProtoReflect.Descriptor instead.
 func (*GetAPIRequestsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{3}
+	return file_api_v1_query_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetAPIRequestsResponse) GetRequests() []*APIRequest {
@@ -253,40 +488,37 @@ func (x *GetAPIRequestsResponse) GetTotalCount() int32 {
 	return 0
 }
 
-// Stats represents aggregated statistics
-type Stats struct {
+func (x *GetAPIRequestsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetServerMetricsRequest is empty, metrics cover the server lifetime
+type GetServerMetricsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	BaseRequests    int32  `protobuf:"varint,1,opt,name=base_requests,json=baseRequests,proto3" json:"base_requests,omitempty"`
-	PremiumRequests int32  `protobuf:"varint,2,opt,name=premium_requests,json=premiumRequests,proto3" json:"premium_requests,omitempty"`
-	TotalRequests   int32  `protobuf:"varint,3,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
-	BaseTokens      *Token `protobuf:"bytes,4,opt,name=base_tokens,json=baseTokens,proto3" json:"base_tokens,omitempty"`
-	PremiumTokens   *Token `protobuf:"bytes,5,opt,name=premium_tokens,json=premiumTokens,proto3" json:"premium_tokens,omitempty"`
-	TotalTokens     *Token `protobuf:"bytes,6,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
-	BaseCost        *Cost  `protobuf:"bytes,7,opt,name=base_cost,json=baseCost,proto3" json:"base_cost,omitempty"`
-	PremiumCost     *Cost  `protobuf:"bytes,8,opt,name=premium_cost,json=premiumCost,proto3" json:"premium_cost,omitempty"`
-	TotalCost       *Cost  `protobuf:"bytes,9,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
 }
 
-func (x *Stats) Reset() {
-	*x = Stats{}
+func (x *GetServerMetricsRequest) Reset() {
+	*x = GetServerMetricsRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[4]
+		mi := &file_api_v1_query_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Stats) String() string {
+func (x *GetServerMetricsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Stats) ProtoMessage() {}
+func (*GetServerMetricsRequest) ProtoMessage() {}
 
-func (x *Stats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[4]
+func (x *GetServerMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -297,106 +529,103 @@ func (x *Stats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
-func (*Stats) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetServerMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetServerMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Stats) GetBaseRequests() int32 {
-	if x != nil {
-		return x.BaseRequests
-	}
-	return 0
-}
+// GetServerMetricsResponse contains server-side ingestion metrics
+type GetServerMetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *Stats) GetPremiumRequests() int32 {
-	if x != nil {
-		return x.PremiumRequests
-	}
-	return 0
+	IngestionLag *IngestionLag `protobuf:"bytes,1,opt,name=ingestion_lag,json=ingestionLag,proto3" json:"ingestion_lag,omitempty"`
+	Retention    *Retention    `protobuf:"bytes,2,opt,name=retention,proto3" json:"retention,omitempty"`                         // Not set by servers predating retention preview
+	SlowQueries  int64         `protobuf:"varint,3,opt,name=slow_queries,json=slowQueries,proto3" json:"slow_queries,omitempty"` // Query service calls slower than the slow query threshold, zero when the slow query log is disabled
 }
 
-func (x *Stats) GetTotalRequests() int32 {
-	if x != nil {
-		return x.TotalRequests
+func (x *GetServerMetricsResponse) Reset() {
+	*x = GetServerMetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *Stats) GetBaseTokens() *Token {
-	if x != nil {
-		return x.BaseTokens
-	}
-	return nil
+func (x *GetServerMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Stats) GetPremiumTokens() *Token {
-	if x != nil {
-		return x.PremiumTokens
+func (*GetServerMetricsResponse) ProtoMessage() {}
+
+func (x *GetServerMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *Stats) GetTotalTokens() *Token {
-	if x != nil {
-		return x.TotalTokens
-	}
-	return nil
+// Deprecated: Use GetServerMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetServerMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Stats) GetBaseCost() *Cost {
+func (x *GetServerMetricsResponse) GetIngestionLag() *IngestionLag {
 	if x != nil {
-		return x.BaseCost
+		return x.IngestionLag
 	}
 	return nil
 }
 
-func (x *Stats) GetPremiumCost() *Cost {
+func (x *GetServerMetricsResponse) GetRetention() *Retention {
 	if x != nil {
-		return x.PremiumCost
+		return x.Retention
 	}
 	return nil
 }
 
-func (x *Stats) GetTotalCost() *Cost {
+func (x *GetServerMetricsResponse) GetSlowQueries() int64 {
 	if x != nil {
-		return x.TotalCost
+		return x.SlowQueries
 	}
-	return nil
+	return 0
 }
 
-// Token represents token usage statistics
-type Token struct {
+// IngestionLag represents the delay between event timestamps and server receive time
+type IngestionLag struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Total         int64 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
-	Input         int64 `protobuf:"varint,2,opt,name=input,proto3" json:"input,omitempty"`
-	Output        int64 `protobuf:"varint,3,opt,name=output,proto3" json:"output,omitempty"`
-	CacheRead     int64 `protobuf:"varint,4,opt,name=cache_read,json=cacheRead,proto3" json:"cache_read,omitempty"`
-	CacheCreation int64 `protobuf:"varint,5,opt,name=cache_creation,json=cacheCreation,proto3" json:"cache_creation,omitempty"`
-	Limited       int64 `protobuf:"varint,6,opt,name=limited,proto3" json:"limited,omitempty"`
-	Cache         int64 `protobuf:"varint,7,opt,name=cache,proto3" json:"cache,omitempty"`
+	Samples   int64 `protobuf:"varint,1,opt,name=samples,proto3" json:"samples,omitempty"`
+	AverageMs int64 `protobuf:"varint,2,opt,name=average_ms,json=averageMs,proto3" json:"average_ms,omitempty"`
+	MaxMs     int64 `protobuf:"varint,3,opt,name=max_ms,json=maxMs,proto3" json:"max_ms,omitempty"`
 }
 
-func (x *Token) Reset() {
-	*x = Token{}
+func (x *IngestionLag) Reset() {
+	*x = IngestionLag{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[5]
+		mi := &file_api_v1_query_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Token) String() string {
+func (x *IngestionLag) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Token) ProtoMessage() {}
+func (*IngestionLag) ProtoMessage() {}
 
-func (x *Token) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[5]
+func (x *IngestionLag) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,48 +636,319 @@ func (x *Token) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Token.ProtoReflect.Descriptor instead.
-func (*Token) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use IngestionLag.ProtoReflect.Descriptor instead.
+func (*IngestionLag) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *Token) GetTotal() int64 {
+func (x *IngestionLag) GetSamples() int64 {
 	if x != nil {
-		return x.Total
+		return x.Samples
 	}
 	return 0
 }
 
-func (x *Token) GetInput() int64 {
+func (x *IngestionLag) GetAverageMs() int64 {
 	if x != nil {
-		return x.Input
+		return x.AverageMs
 	}
 	return 0
 }
 
-func (x *Token) GetOutput() int64 {
+func (x *IngestionLag) GetMaxMs() int64 {
 	if x != nil {
-		return x.Output
+		return x.MaxMs
 	}
 	return 0
 }
 
-func (x *Token) GetCacheRead() int64 {
-	if x != nil {
-		return x.CacheRead
-	}
-	return 0
+// Retention represents the effective retention policy and the next cleanup run
+type Retention struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DurationMs    int64                  `protobuf:"varint,1,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`           // Zero when records are kept forever
+	NextCleanupAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=next_cleanup_at,json=nextCleanupAt,proto3" json:"next_cleanup_at,omitempty"` // Not set until the cleanup scheduler has run
 }
 
-func (x *Token) GetCacheCreation() int64 {
-	if x != nil {
-		return x.CacheCreation
+func (x *Retention) Reset() {
+	*x = Retention{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *Token) GetLimited() int64 {
-	if x != nil {
+func (x *Retention) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Retention) ProtoMessage() {}
+
+func (x *Retention) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Retention.ProtoReflect.Descriptor instead.
+func (*Retention) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Retention) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *Retention) GetNextCleanupAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextCleanupAt
+	}
+	return nil
+}
+
+// Stats represents aggregated statistics
+type Stats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BaseRequests    int32  `protobuf:"varint,1,opt,name=base_requests,json=baseRequests,proto3" json:"base_requests,omitempty"`
+	PremiumRequests int32  `protobuf:"varint,2,opt,name=premium_requests,json=premiumRequests,proto3" json:"premium_requests,omitempty"`
+	TotalRequests   int32  `protobuf:"varint,3,opt,name=total_requests,json=totalRequests,proto3" json:"total_requests,omitempty"`
+	BaseTokens      *Token `protobuf:"bytes,4,opt,name=base_tokens,json=baseTokens,proto3" json:"base_tokens,omitempty"`
+	PremiumTokens   *Token `protobuf:"bytes,5,opt,name=premium_tokens,json=premiumTokens,proto3" json:"premium_tokens,omitempty"`
+	TotalTokens     *Token `protobuf:"bytes,6,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	BaseCost        *Cost  `protobuf:"bytes,7,opt,name=base_cost,json=baseCost,proto3" json:"base_cost,omitempty"`
+	PremiumCost     *Cost  `protobuf:"bytes,8,opt,name=premium_cost,json=premiumCost,proto3" json:"premium_cost,omitempty"`
+	TotalCost       *Cost  `protobuf:"bytes,9,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// 1M context beta models, tracked separately from premium
+	LongContextRequests int32  `protobuf:"varint,10,opt,name=long_context_requests,json=longContextRequests,proto3" json:"long_context_requests,omitempty"`
+	LongContextTokens   *Token `protobuf:"bytes,11,opt,name=long_context_tokens,json=longContextTokens,proto3" json:"long_context_tokens,omitempty"`
+	LongContextCost     *Cost  `protobuf:"bytes,12,opt,name=long_context_cost,json=longContextCost,proto3" json:"long_context_cost,omitempty"`
+	// Usage of each model, the most expensive first
+	Models []*ModelStats `protobuf:"bytes,13,rep,name=models,proto3" json:"models,omitempty"`
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Stats) GetBaseRequests() int32 {
+	if x != nil {
+		return x.BaseRequests
+	}
+	return 0
+}
+
+func (x *Stats) GetPremiumRequests() int32 {
+	if x != nil {
+		return x.PremiumRequests
+	}
+	return 0
+}
+
+func (x *Stats) GetTotalRequests() int32 {
+	if x != nil {
+		return x.TotalRequests
+	}
+	return 0
+}
+
+func (x *Stats) GetBaseTokens() *Token {
+	if x != nil {
+		return x.BaseTokens
+	}
+	return nil
+}
+
+func (x *Stats) GetPremiumTokens() *Token {
+	if x != nil {
+		return x.PremiumTokens
+	}
+	return nil
+}
+
+func (x *Stats) GetTotalTokens() *Token {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return nil
+}
+
+func (x *Stats) GetBaseCost() *Cost {
+	if x != nil {
+		return x.BaseCost
+	}
+	return nil
+}
+
+func (x *Stats) GetPremiumCost() *Cost {
+	if x != nil {
+		return x.PremiumCost
+	}
+	return nil
+}
+
+func (x *Stats) GetTotalCost() *Cost {
+	if x != nil {
+		return x.TotalCost
+	}
+	return nil
+}
+
+func (x *Stats) GetLongContextRequests() int32 {
+	if x != nil {
+		return x.LongContextRequests
+	}
+	return 0
+}
+
+func (x *Stats) GetLongContextTokens() *Token {
+	if x != nil {
+		return x.LongContextTokens
+	}
+	return nil
+}
+
+func (x *Stats) GetLongContextCost() *Cost {
+	if x != nil {
+		return x.LongContextCost
+	}
+	return nil
+}
+
+func (x *Stats) GetModels() []*ModelStats {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+// Token represents token usage statistics
+type Token struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Total         int64 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	Input         int64 `protobuf:"varint,2,opt,name=input,proto3" json:"input,omitempty"`
+	Output        int64 `protobuf:"varint,3,opt,name=output,proto3" json:"output,omitempty"`
+	CacheRead     int64 `protobuf:"varint,4,opt,name=cache_read,json=cacheRead,proto3" json:"cache_read,omitempty"`
+	CacheCreation int64 `protobuf:"varint,5,opt,name=cache_creation,json=cacheCreation,proto3" json:"cache_creation,omitempty"`
+	Limited       int64 `protobuf:"varint,6,opt,name=limited,proto3" json:"limited,omitempty"`
+	Cache         int64 `protobuf:"varint,7,opt,name=cache,proto3" json:"cache,omitempty"`
+	ToolUse       int64 `protobuf:"varint,8,opt,name=tool_use,json=toolUse,proto3" json:"tool_use,omitempty"` // Part of output spent on tool calls, zero when telemetry does not report it
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Token) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Token) GetInput() int64 {
+	if x != nil {
+		return x.Input
+	}
+	return 0
+}
+
+func (x *Token) GetOutput() int64 {
+	if x != nil {
+		return x.Output
+	}
+	return 0
+}
+
+func (x *Token) GetCacheRead() int64 {
+	if x != nil {
+		return x.CacheRead
+	}
+	return 0
+}
+
+func (x *Token) GetCacheCreation() int64 {
+	if x != nil {
+		return x.CacheCreation
+	}
+	return 0
+}
+
+func (x *Token) GetLimited() int64 {
+	if x != nil {
 		return x.Limited
 	}
 	return 0
@@ -461,6 +961,13 @@ func (x *Token) GetCache() int64 {
 	return 0
 }
 
+func (x *Token) GetToolUse() int64 {
+	if x != nil {
+		return x.ToolUse
+	}
+	return 0
+}
+
 // Cost represents cost information
 type Cost struct {
 	state         protoimpl.MessageState
@@ -473,7 +980,7 @@ type Cost struct {
 func (x *Cost) Reset() {
 	*x = Cost{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[6]
+		mi := &file_api_v1_query_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -486,7 +993,7 @@ func (x *Cost) String() string {
 func (*Cost) ProtoMessage() {}
 
 func (x *Cost) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[6]
+	mi := &file_api_v1_query_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -497,53 +1004,728 @@ func (x *Cost) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Cost.ProtoReflect.Descriptor instead.
-func (*Cost) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Cost.ProtoReflect.Descriptor instead.
+func (*Cost) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Cost) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+// APIRequest represents a single API request record
+type APIRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId           string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Timestamp           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Model               string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	InputTokens         int64                  `protobuf:"varint,4,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	OutputTokens        int64                  `protobuf:"varint,5,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
+	CacheReadTokens     int64                  `protobuf:"varint,6,opt,name=cache_read_tokens,json=cacheReadTokens,proto3" json:"cache_read_tokens,omitempty"`
+	CacheCreationTokens int64                  `protobuf:"varint,7,opt,name=cache_creation_tokens,json=cacheCreationTokens,proto3" json:"cache_creation_tokens,omitempty"`
+	TotalTokens         int64                  `protobuf:"varint,8,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	CostUsd             float64                `protobuf:"fixed64,9,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	DurationMs          int64                  `protobuf:"varint,10,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Source              string                 `protobuf:"bytes,11,opt,name=source,proto3" json:"source,omitempty"`                                       // Tool that reported the request, empty from servers predating sources
+	ToolUseTokens       int64                  `protobuf:"varint,12,opt,name=tool_use_tokens,json=toolUseTokens,proto3" json:"tool_use_tokens,omitempty"` // Part of output_tokens spent on tool calls, zero when telemetry does not report it
+	Star                StarScope              `protobuf:"varint,13,opt,name=star,proto3,enum=ccmon.v1.StarScope" json:"star,omitempty"`                  // Why the request is kept from the retention cleanup, unspecified when not starred
+	Origin              string                 `protobuf:"bytes,14,opt,name=origin,proto3" json:"origin,omitempty"`                                       // How the record entered the database ("live" or "import"), empty from servers predating origins
+	User                string                 `protobuf:"bytes,15,opt,name=user,proto3" json:"user,omitempty"`                                           // Who made the request, empty when telemetry does not identify the user
+	Project             string                 `protobuf:"bytes,16,opt,name=project,proto3" json:"project,omitempty"`                                     // Which project the request was made in, empty when telemetry does not identify the project
+}
+
+func (x *APIRequest) Reset() {
+	*x = APIRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *APIRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIRequest) ProtoMessage() {}
+
+func (x *APIRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIRequest.ProtoReflect.Descriptor instead.
+func (*APIRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *APIRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *APIRequest) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *APIRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *APIRequest) GetInputTokens() int64 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetOutputTokens() int64 {
+	if x != nil {
+		return x.OutputTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetCacheReadTokens() int64 {
+	if x != nil {
+		return x.CacheReadTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetCacheCreationTokens() int64 {
+	if x != nil {
+		return x.CacheCreationTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *APIRequest) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *APIRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *APIRequest) GetToolUseTokens() int64 {
+	if x != nil {
+		return x.ToolUseTokens
+	}
+	return 0
+}
+
+func (x *APIRequest) GetStar() StarScope {
+	if x != nil {
+		return x.Star
+	}
+	return StarScope_STAR_SCOPE_UNSPECIFIED
+}
+
+func (x *APIRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *APIRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *APIRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+// GetUserUsageRequest specifies the day and the block to report the usage of each user
+type GetUserUsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartTime      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                  // Start of the day the quota applies to
+	EndTime        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                        // End of the day the quota applies to
+	BlockStartTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=block_start_time,json=blockStartTime,proto3" json:"block_start_time,omitempty"` // Optional: block usage is empty when not set
+	BlockEndTime   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=block_end_time,json=blockEndTime,proto3" json:"block_end_time,omitempty"`       // Optional: block usage is empty when not set
+}
+
+func (x *GetUserUsageRequest) Reset() {
+	*x = GetUserUsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserUsageRequest) ProtoMessage() {}
+
+func (x *GetUserUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUserUsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetUserUsageRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetUserUsageRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetUserUsageRequest) GetBlockStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockStartTime
+	}
+	return nil
+}
+
+func (x *GetUserUsageRequest) GetBlockEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockEndTime
+	}
+	return nil
+}
+
+// GetUserUsageResponse contains the usage of each user, sorted by user
+type GetUserUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users []*UserUsage `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+}
+
+func (x *GetUserUsageResponse) Reset() {
+	*x = GetUserUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserUsageResponse) ProtoMessage() {}
+
+func (x *GetUserUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUserUsageResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetUserUsageResponse) GetUsers() []*UserUsage {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+// UserUsage represents the daily and block usage of a single user
+type UserUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User       string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"` // Empty for requests without user attribution
+	Daily      *Stats `protobuf:"bytes,2,opt,name=daily,proto3" json:"daily,omitempty"`
+	Block      *Stats `protobuf:"bytes,3,opt,name=block,proto3" json:"block,omitempty"`                             // Empty when the request does not set a block
+	DailyQuota *Cost  `protobuf:"bytes,4,opt,name=daily_quota,json=dailyQuota,proto3" json:"daily_quota,omitempty"` // Zero when the user is unlimited
+	Status     string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                           // "unlimited", "ok" or "exceeded"
+}
+
+func (x *UserUsage) Reset() {
+	*x = UserUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUsage) ProtoMessage() {}
+
+func (x *UserUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUsage.ProtoReflect.Descriptor instead.
+func (*UserUsage) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UserUsage) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *UserUsage) GetDaily() *Stats {
+	if x != nil {
+		return x.Daily
+	}
+	return nil
+}
+
+func (x *UserUsage) GetBlock() *Stats {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *UserUsage) GetDailyQuota() *Cost {
+	if x != nil {
+		return x.DailyQuota
+	}
+	return nil
+}
+
+func (x *UserUsage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// CountAPIRequestsRequest specifies the period and the dimensions of the API requests to count
+type CountAPIRequestsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Optional: if not set, includes all time from beginning
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Optional: if not set, includes up to current time
+	Filter    *RequestFilter         `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`                        // Optional: only requests matching the dimensions are counted
+}
+
+func (x *CountAPIRequestsRequest) Reset() {
+	*x = CountAPIRequestsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountAPIRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountAPIRequestsRequest) ProtoMessage() {}
+
+func (x *CountAPIRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountAPIRequestsRequest.ProtoReflect.Descriptor instead.
+func (*CountAPIRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CountAPIRequestsRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *CountAPIRequestsRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *CountAPIRequestsRequest) GetFilter() *RequestFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// CountAPIRequestsResponse contains the number of matching API requests
+type CountAPIRequestsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *CountAPIRequestsResponse) Reset() {
+	*x = CountAPIRequestsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountAPIRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountAPIRequestsResponse) ProtoMessage() {}
+
+func (x *CountAPIRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountAPIRequestsResponse.ProtoReflect.Descriptor instead.
+func (*CountAPIRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CountAPIRequestsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// WatchAPIRequestsRequest specifies the dimensions of the API requests to watch
+type WatchAPIRequestsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *RequestFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *WatchAPIRequestsRequest) Reset() {
+	*x = WatchAPIRequestsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchAPIRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAPIRequestsRequest) ProtoMessage() {}
+
+func (x *WatchAPIRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAPIRequestsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAPIRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchAPIRequestsRequest) GetFilter() *RequestFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// WatchAPIRequestsResponse contains the API requests received since the last response
+type WatchAPIRequestsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*APIRequest `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *WatchAPIRequestsResponse) Reset() {
+	*x = WatchAPIRequestsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchAPIRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchAPIRequestsResponse) ProtoMessage() {}
+
+func (x *WatchAPIRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchAPIRequestsResponse.ProtoReflect.Descriptor instead.
+func (*WatchAPIRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchAPIRequestsResponse) GetRequests() []*APIRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// GetIngestStatsRequest requests the outcomes of the received API request events
+type GetIngestStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetIngestStatsRequest) Reset() {
+	*x = GetIngestStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIngestStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIngestStatsRequest) ProtoMessage() {}
+
+func (x *GetIngestStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIngestStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetIngestStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{20}
+}
+
+// GetIngestStatsResponse contains the outcomes of the API request events received over the last hour and day
+type GetIngestStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LastHour *IngestCounts          `protobuf:"bytes,1,opt,name=last_hour,json=lastHour,proto3" json:"last_hour,omitempty"`
+	LastDay  *IngestCounts          `protobuf:"bytes,2,opt,name=last_day,json=lastDay,proto3" json:"last_day,omitempty"`
+	Since    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"` // When the server started counting, windows are shorter before a day has passed
+}
+
+func (x *GetIngestStatsResponse) Reset() {
+	*x = GetIngestStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIngestStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIngestStatsResponse) ProtoMessage() {}
+
+func (x *GetIngestStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIngestStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetIngestStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetIngestStatsResponse) GetLastHour() *IngestCounts {
+	if x != nil {
+		return x.LastHour
+	}
+	return nil
+}
+
+func (x *GetIngestStatsResponse) GetLastDay() *IngestCounts {
+	if x != nil {
+		return x.LastDay
+	}
+	return nil
 }
 
-func (x *Cost) GetAmount() float64 {
+func (x *GetIngestStatsResponse) GetSince() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Amount
+		return x.Since
 	}
-	return 0
+	return nil
 }
 
-// APIRequest represents a single API request record
-type APIRequest struct {
+// IngestCounts counts the received API request events by their outcome
+type IngestCounts struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionId           string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Timestamp           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Model               string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
-	InputTokens         int64                  `protobuf:"varint,4,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
-	OutputTokens        int64                  `protobuf:"varint,5,opt,name=output_tokens,json=outputTokens,proto3" json:"output_tokens,omitempty"`
-	CacheReadTokens     int64                  `protobuf:"varint,6,opt,name=cache_read_tokens,json=cacheReadTokens,proto3" json:"cache_read_tokens,omitempty"`
-	CacheCreationTokens int64                  `protobuf:"varint,7,opt,name=cache_creation_tokens,json=cacheCreationTokens,proto3" json:"cache_creation_tokens,omitempty"`
-	TotalTokens         int64                  `protobuf:"varint,8,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
-	CostUsd             float64                `protobuf:"fixed64,9,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
-	DurationMs          int64                  `protobuf:"varint,10,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Accepted   int64 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`     // Stored
+	Ignored    int64 `protobuf:"varint,2,opt,name=ignored,proto3" json:"ignored,omitempty"`       // Matched an ignore rule
+	Dropped    int64 `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`       // Dropped by a processor or for clock skew
+	Malformed  int64 `protobuf:"varint,4,opt,name=malformed,proto3" json:"malformed,omitempty"`   // Rejected for missing required data, e.g. the session ID
+	Duplicated int64 `protobuf:"varint,5,opt,name=duplicated,proto3" json:"duplicated,omitempty"` // Repeated within the same export
+	Failed     int64 `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`         // The database write failed
 }
 
-func (x *APIRequest) Reset() {
-	*x = APIRequest{}
+func (x *IngestCounts) Reset() {
+	*x = IngestCounts{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_query_proto_msgTypes[7]
+		mi := &file_api_v1_query_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *APIRequest) String() string {
+func (x *IngestCounts) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*APIRequest) ProtoMessage() {}
+func (*IngestCounts) ProtoMessage() {}
 
-func (x *APIRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_query_proto_msgTypes[7]
+func (x *IngestCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -554,89 +1736,352 @@ func (x *APIRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use APIRequest.ProtoReflect.Descriptor instead.
-func (*APIRequest) Descriptor() ([]byte, []int) {
-	return file_proto_query_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use IngestCounts.ProtoReflect.Descriptor instead.
+func (*IngestCounts) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *APIRequest) GetSessionId() string {
+func (x *IngestCounts) GetAccepted() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.Accepted
 	}
-	return ""
+	return 0
 }
 
-func (x *APIRequest) GetTimestamp() *timestamppb.Timestamp {
+func (x *IngestCounts) GetIgnored() int64 {
 	if x != nil {
-		return x.Timestamp
+		return x.Ignored
 	}
-	return nil
+	return 0
 }
 
-func (x *APIRequest) GetModel() string {
+func (x *IngestCounts) GetDropped() int64 {
 	if x != nil {
-		return x.Model
+		return x.Dropped
 	}
-	return ""
+	return 0
 }
 
-func (x *APIRequest) GetInputTokens() int64 {
+func (x *IngestCounts) GetMalformed() int64 {
 	if x != nil {
-		return x.InputTokens
+		return x.Malformed
 	}
 	return 0
 }
 
-func (x *APIRequest) GetOutputTokens() int64 {
+func (x *IngestCounts) GetDuplicated() int64 {
 	if x != nil {
-		return x.OutputTokens
+		return x.Duplicated
 	}
 	return 0
 }
 
-func (x *APIRequest) GetCacheReadTokens() int64 {
+func (x *IngestCounts) GetFailed() int64 {
 	if x != nil {
-		return x.CacheReadTokens
+		return x.Failed
 	}
 	return 0
 }
 
-func (x *APIRequest) GetCacheCreationTokens() int64 {
-	if x != nil {
-		return x.CacheCreationTokens
+// ModelStats represents the usage of a single model
+type ModelStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Model    string `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Requests int32  `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
+	Tokens   *Token `protobuf:"bytes,3,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Cost     *Cost  `protobuf:"bytes,4,opt,name=cost,proto3" json:"cost,omitempty"`
+}
+
+func (x *ModelStats) Reset() {
+	*x = ModelStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_query_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *APIRequest) GetTotalTokens() int64 {
+func (x *ModelStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelStats) ProtoMessage() {}
+
+func (x *ModelStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_query_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelStats.ProtoReflect.Descriptor instead.
+func (*ModelStats) Descriptor() ([]byte, []int) {
+	return file_api_v1_query_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ModelStats) GetModel() string {
 	if x != nil {
-		return x.TotalTokens
+		return x.Model
 	}
-	return 0
+	return ""
 }
 
-func (x *APIRequest) GetCostUsd() float64 {
+func (x *ModelStats) GetRequests() int32 {
 	if x != nil {
-		return x.CostUsd
+		return x.Requests
 	}
 	return 0
 }
 
-func (x *APIRequest) GetDurationMs() int64 {
+func (x *ModelStats) GetTokens() *Token {
 	if x != nil {
-		return x.DurationMs
+		return x.Tokens
 	}
-	return 0
+	return nil
 }
 
-var File_proto_query_proto protoreflect.FileDescriptor
+func (x *ModelStats) GetCost() *Cost {
+	if x != nil {
+		return x.Cost
+	}
+	return nil
+}
 
-var file_proto_query_proto_rawDesc = []byte{
-	0x0a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x08, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x83,
-	0x01, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+var File_api_v1_query_proto protoreflect.FileDescriptor
+
+var file_api_v1_query_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x1f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xad, 0x02, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35,
+	0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e,
+	0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x02, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x61,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x70, 0x70,
+	0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x72, 0x65, 0x73, 0x68, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22,
+	0x94, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x78, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x12, 0x37, 0x0a,
+	0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0x9f, 0x02, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x41, 0x50,
+	0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65,
+	0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x2f, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xba, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x22, 0x93, 0x01, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x41, 0x50, 0x49,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x50,
+	0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65,
+	0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x19, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xad, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x53, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a, 0x0d, 0x69, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x6c, 0x61, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x63, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4c,
+	0x61, 0x67, 0x52, 0x0c, 0x69, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x61, 0x67,
+	0x12, 0x31, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x74, 0x65, 0x6e, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x6c, 0x6f, 0x77, 0x5f, 0x71, 0x75, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x6c, 0x6f, 0x77, 0x51,
+	0x75, 0x65, 0x72, 0x69, 0x65, 0x73, 0x22, 0x5e, 0x0a, 0x0c, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74,
+	0x69, 0x6f, 0x6e, 0x4c, 0x61, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4d, 0x73, 0x12,
+	0x15, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x05, 0x6d, 0x61, 0x78, 0x4d, 0x73, 0x22, 0x70, 0x0a, 0x09, 0x52, 0x65, 0x74, 0x65, 0x6e, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x42, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x6c, 0x65,
+	0x61, 0x6e, 0x75, 0x70, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x43,
+	0x6c, 0x65, 0x61, 0x6e, 0x75, 0x70, 0x41, 0x74, 0x22, 0x8a, 0x05, 0x0a, 0x05, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x62, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x65, 0x6d, 0x69,
+	0x75, 0x6d, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0f, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x62, 0x61, 0x73,
+	0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52,
+	0x0a, 0x62, 0x61, 0x73, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x0e, 0x70,
+	0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x09, 0x62, 0x61, 0x73, 0x65, 0x5f,
+	0x63, 0x6f, 0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65,
+	0x43, 0x6f, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x5f,
+	0x63, 0x6f, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x6d,
+	0x69, 0x75, 0x6d, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x09, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x15, 0x6c, 0x6f, 0x6e, 0x67, 0x5f, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x6c, 0x6f, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x3f, 0x0a, 0x13, 0x6c, 0x6f,
+	0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x11, 0x6c, 0x6f, 0x6e, 0x67, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x3a, 0x0a, 0x11, 0x6c,
+	0x6f, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x6f, 0x73, 0x74,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x0f, 0x6c, 0x6f, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x78, 0x74, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x06, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x73, 0x22, 0xdc, 0x01, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x72, 0x65, 0x61,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65,
+	0x61, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x6f,
+	0x6c, 0x5f, 0x75, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74, 0x6f, 0x6f,
+	0x6c, 0x55, 0x73, 0x65, 0x22, 0x1e, 0x0a, 0x04, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb1, 0x04, 0x0a, 0x0a, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x61, 0x64,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x63, 0x61, 0x63, 0x68, 0x65, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x19, 0x0a,
+	0x08, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x75, 0x73, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x07, 0x63, 0x6f, 0x73, 0x74, 0x55, 0x73, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x75, 0x73, 0x65, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x6f, 0x6f, 0x6c,
+	0x55, 0x73, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x73, 0x74, 0x61,
+	0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x52, 0x04, 0x73, 0x74,
+	0x61, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x18,
+	0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x22, 0x8f, 0x02, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65,
+	0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x40, 0x0a, 0x0e, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x45, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x41, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x29, 0x0a, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73, 0x65, 0x72, 0x73, 0x22, 0xb6, 0x01,
+	0x0a, 0x09, 0x55, 0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12,
+	0x25, 0x0a, 0x05, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x05, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x12, 0x25, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2f, 0x0a,
+	0x0b, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x5f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x73, 0x74, 0x52, 0x0a, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x17, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
@@ -644,94 +2089,61 @@ var file_proto_query_proto_rawDesc = []byte{
 	0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
 	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64,
-	0x54, 0x69, 0x6d, 0x65, 0x22, 0x39, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
-	0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x22,
-	0xb7, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61,
-	0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74,
-	0x54, 0x69, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
-	0x6d, 0x70, 0x52, 0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
-	0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69,
-	0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x6b, 0x0a, 0x16, 0x47, 0x65, 0x74,
-	0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31,
-	0x2e, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61,
-	0x6c, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xab, 0x03, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x73,
-	0x12, 0x23, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d,
-	0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x0f, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
-	0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x62, 0x61, 0x73, 0x65, 0x5f,
-	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63,
-	0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x0a, 0x62,
-	0x61, 0x73, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x36, 0x0a, 0x0e, 0x70, 0x72, 0x65,
-	0x6d, 0x69, 0x75, 0x6d, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b,
-	0x65, 0x6e, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
-	0x73, 0x12, 0x32, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
-	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
-	0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54,
-	0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x2b, 0x0a, 0x09, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x63, 0x6f,
-	0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x08, 0x62, 0x61, 0x73, 0x65, 0x43, 0x6f,
-	0x73, 0x74, 0x12, 0x31, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75, 0x6d, 0x5f, 0x63, 0x6f,
-	0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e,
-	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x6d, 0x69, 0x75,
-	0x6d, 0x43, 0x6f, 0x73, 0x74, 0x12, 0x2d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63,
-	0x6f, 0x73, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x63, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c,
-	0x43, 0x6f, 0x73, 0x74, 0x22, 0xc1, 0x01, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14,
-	0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x74,
-	0x6f, 0x74, 0x61, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70,
-	0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x61, 0x63, 0x68, 0x65, 0x52, 0x65, 0x61,
-	0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x61, 0x63, 0x68, 0x65,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x6d, 0x69,
-	0x74, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6c, 0x69, 0x6d, 0x69, 0x74,
-	0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x05, 0x63, 0x61, 0x63, 0x68, 0x65, 0x22, 0x1e, 0x0a, 0x04, 0x43, 0x6f, 0x73, 0x74,
-	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x82, 0x03, 0x0a, 0x0a, 0x41, 0x50, 0x49,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f,
-	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e,
-	0x70, 0x75, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x75, 0x74,
-	0x70, 0x75, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x2a,
-	0x0a, 0x11, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65,
-	0x52, 0x65, 0x61, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x63, 0x61,
-	0x63, 0x68, 0x65, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b,
-	0x65, 0x6e, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x63, 0x61, 0x63, 0x68, 0x65,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x21,
-	0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
-	0x73, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x75, 0x73, 0x64, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x07, 0x63, 0x6f, 0x73, 0x74, 0x55, 0x73, 0x64, 0x12, 0x1f, 0x0a, 0x0b,
-	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x32, 0xa6, 0x01,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x30, 0x0a, 0x18, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50,
+	0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x4a, 0x0a, 0x17, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06, 0x66, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x22, 0x4c, 0x0a, 0x18, 0x57, 0x61, 0x74, 0x63, 0x68, 0x41, 0x50, 0x49, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x30, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x50, 0x49,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x22, 0x17, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xb2, 0x01, 0x0a, 0x16, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x6f,
+	0x75, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x6f, 0x75, 0x72, 0x12, 0x31, 0x0a, 0x08, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x64, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63,
+	0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x73, 0x52, 0x07, 0x6c, 0x61, 0x73, 0x74, 0x44, 0x61, 0x79, 0x12, 0x30, 0x0a,
+	0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x69, 0x6e, 0x63, 0x65, 0x22,
+	0xb4, 0x01, 0x0a, 0x0c, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x69,
+	0x67, 0x6e, 0x6f, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x61, 0x6c, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x6d, 0x61, 0x6c, 0x66, 0x6f, 0x72, 0x6d, 0x65, 0x64, 0x12, 0x1e,
+	0x0a, 0x0a, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06,
+	0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x22, 0x8b, 0x01, 0x0a, 0x0a, 0x4d, 0x6f, 0x64, 0x65, 0x6c,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73,
+	0x12, 0x22, 0x0a, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x73, 0x74, 0x52, 0x04,
+	0x63, 0x6f, 0x73, 0x74, 0x2a, 0x57, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x72, 0x53, 0x63, 0x6f, 0x70,
+	0x65, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x54, 0x41, 0x52, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a,
+	0x12, 0x53, 0x54, 0x41, 0x52, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x52, 0x45, 0x51, 0x55,
+	0x45, 0x53, 0x54, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x52, 0x5f, 0x53, 0x43,
+	0x4f, 0x50, 0x45, 0x5f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x32, 0xdd, 0x04,
 	0x0a, 0x0c, 0x51, 0x75, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x41,
 	0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x19, 0x2e, 0x63, 0x63, 0x6d,
 	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
@@ -742,68 +2154,151 @@ var file_proto_query_proto_rawDesc = []byte{
 	0x65, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
 	0x47, 0x65, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x63, 0x74, 0x39, 0x36, 0x32, 0x30, 0x2f, 0x63, 0x63,
-	0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x76,
-	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x21, 0x2e, 0x63, 0x63, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e,
+	0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4d, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x1d, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x55, 0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1e, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x59, 0x0a, 0x10, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x12, 0x21, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x10, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12,
+	0x21, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x41, 0x50, 0x49, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x53, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x67, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x63, 0x63, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x63, 0x63,
+	0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x67, 0x65, 0x73, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29, 0x5a,
+	0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x63, 0x74,
+	0x39, 0x36, 0x32, 0x30, 0x2f, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x3b, 0x71, 0x75, 0x65, 0x72, 0x79, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
-	file_proto_query_proto_rawDescOnce sync.Once
-	file_proto_query_proto_rawDescData = file_proto_query_proto_rawDesc
+	file_api_v1_query_proto_rawDescOnce sync.Once
+	file_api_v1_query_proto_rawDescData = file_api_v1_query_proto_rawDesc
 )
 
-func file_proto_query_proto_rawDescGZIP() []byte {
-	file_proto_query_proto_rawDescOnce.Do(func() {
-		file_proto_query_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_query_proto_rawDescData)
+func file_api_v1_query_proto_rawDescGZIP() []byte {
+	file_api_v1_query_proto_rawDescOnce.Do(func() {
+		file_api_v1_query_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_v1_query_proto_rawDescData)
 	})
-	return file_proto_query_proto_rawDescData
-}
-
-var file_proto_query_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
-var file_proto_query_proto_goTypes = []interface{}{
-	(*GetStatsRequest)(nil),        // 0: ccmon.v1.GetStatsRequest
-	(*GetStatsResponse)(nil),       // 1: ccmon.v1.GetStatsResponse
-	(*GetAPIRequestsRequest)(nil),  // 2: ccmon.v1.GetAPIRequestsRequest
-	(*GetAPIRequestsResponse)(nil), // 3: ccmon.v1.GetAPIRequestsResponse
-	(*Stats)(nil),                  // 4: ccmon.v1.Stats
-	(*Token)(nil),                  // 5: ccmon.v1.Token
-	(*Cost)(nil),                   // 6: ccmon.v1.Cost
-	(*APIRequest)(nil),             // 7: ccmon.v1.APIRequest
-	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
-}
-var file_proto_query_proto_depIdxs = []int32{
-	8,  // 0: ccmon.v1.GetStatsRequest.start_time:type_name -> google.protobuf.Timestamp
-	8,  // 1: ccmon.v1.GetStatsRequest.end_time:type_name -> google.protobuf.Timestamp
-	4,  // 2: ccmon.v1.GetStatsResponse.stats:type_name -> ccmon.v1.Stats
-	8,  // 3: ccmon.v1.GetAPIRequestsRequest.start_time:type_name -> google.protobuf.Timestamp
-	8,  // 4: ccmon.v1.GetAPIRequestsRequest.end_time:type_name -> google.protobuf.Timestamp
-	7,  // 5: ccmon.v1.GetAPIRequestsResponse.requests:type_name -> ccmon.v1.APIRequest
-	5,  // 6: ccmon.v1.Stats.base_tokens:type_name -> ccmon.v1.Token
-	5,  // 7: ccmon.v1.Stats.premium_tokens:type_name -> ccmon.v1.Token
-	5,  // 8: ccmon.v1.Stats.total_tokens:type_name -> ccmon.v1.Token
-	6,  // 9: ccmon.v1.Stats.base_cost:type_name -> ccmon.v1.Cost
-	6,  // 10: ccmon.v1.Stats.premium_cost:type_name -> ccmon.v1.Cost
-	6,  // 11: ccmon.v1.Stats.total_cost:type_name -> ccmon.v1.Cost
-	8,  // 12: ccmon.v1.APIRequest.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 13: ccmon.v1.QueryService.GetStats:input_type -> ccmon.v1.GetStatsRequest
-	2,  // 14: ccmon.v1.QueryService.GetAPIRequests:input_type -> ccmon.v1.GetAPIRequestsRequest
-	1,  // 15: ccmon.v1.QueryService.GetStats:output_type -> ccmon.v1.GetStatsResponse
-	3,  // 16: ccmon.v1.QueryService.GetAPIRequests:output_type -> ccmon.v1.GetAPIRequestsResponse
-	15, // [15:17] is the sub-list for method output_type
-	13, // [13:15] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
-}
-
-func init() { file_proto_query_proto_init() }
-func file_proto_query_proto_init() {
-	if File_proto_query_proto != nil {
+	return file_api_v1_query_proto_rawDescData
+}
+
+var file_api_v1_query_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_v1_query_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_api_v1_query_proto_goTypes = []interface{}{
+	(StarScope)(0),                   // 0: ccmon.v1.StarScope
+	(*GetStatsRequest)(nil),          // 1: ccmon.v1.GetStatsRequest
+	(*GetStatsResponse)(nil),         // 2: ccmon.v1.GetStatsResponse
+	(*GetAPIRequestsRequest)(nil),    // 3: ccmon.v1.GetAPIRequestsRequest
+	(*RequestFilter)(nil),            // 4: ccmon.v1.RequestFilter
+	(*GetAPIRequestsResponse)(nil),   // 5: ccmon.v1.GetAPIRequestsResponse
+	(*GetServerMetricsRequest)(nil),  // 6: ccmon.v1.GetServerMetricsRequest
+	(*GetServerMetricsResponse)(nil), // 7: ccmon.v1.GetServerMetricsResponse
+	(*IngestionLag)(nil),             // 8: ccmon.v1.IngestionLag
+	(*Retention)(nil),                // 9: ccmon.v1.Retention
+	(*Stats)(nil),                    // 10: ccmon.v1.Stats
+	(*Token)(nil),                    // 11: ccmon.v1.Token
+	(*Cost)(nil),                     // 12: ccmon.v1.Cost
+	(*APIRequest)(nil),               // 13: ccmon.v1.APIRequest
+	(*GetUserUsageRequest)(nil),      // 14: ccmon.v1.GetUserUsageRequest
+	(*GetUserUsageResponse)(nil),     // 15: ccmon.v1.GetUserUsageResponse
+	(*UserUsage)(nil),                // 16: ccmon.v1.UserUsage
+	(*CountAPIRequestsRequest)(nil),  // 17: ccmon.v1.CountAPIRequestsRequest
+	(*CountAPIRequestsResponse)(nil), // 18: ccmon.v1.CountAPIRequestsResponse
+	(*WatchAPIRequestsRequest)(nil),  // 19: ccmon.v1.WatchAPIRequestsRequest
+	(*WatchAPIRequestsResponse)(nil), // 20: ccmon.v1.WatchAPIRequestsResponse
+	(*GetIngestStatsRequest)(nil),    // 21: ccmon.v1.GetIngestStatsRequest
+	(*GetIngestStatsResponse)(nil),   // 22: ccmon.v1.GetIngestStatsResponse
+	(*IngestCounts)(nil),             // 23: ccmon.v1.IngestCounts
+	(*ModelStats)(nil),               // 24: ccmon.v1.ModelStats
+	(*timestamppb.Timestamp)(nil),    // 25: google.protobuf.Timestamp
+}
+var file_api_v1_query_proto_depIdxs = []int32{
+	25, // 0: ccmon.v1.GetStatsRequest.start_time:type_name -> google.protobuf.Timestamp
+	25, // 1: ccmon.v1.GetStatsRequest.end_time:type_name -> google.protobuf.Timestamp
+	25, // 2: ccmon.v1.GetStatsRequest.at:type_name -> google.protobuf.Timestamp
+	10, // 3: ccmon.v1.GetStatsResponse.stats:type_name -> ccmon.v1.Stats
+	25, // 4: ccmon.v1.GetStatsResponse.cached_at:type_name -> google.protobuf.Timestamp
+	25, // 5: ccmon.v1.GetAPIRequestsRequest.start_time:type_name -> google.protobuf.Timestamp
+	25, // 6: ccmon.v1.GetAPIRequestsRequest.end_time:type_name -> google.protobuf.Timestamp
+	4,  // 7: ccmon.v1.GetAPIRequestsRequest.filter:type_name -> ccmon.v1.RequestFilter
+	13, // 8: ccmon.v1.GetAPIRequestsResponse.requests:type_name -> ccmon.v1.APIRequest
+	8,  // 9: ccmon.v1.GetServerMetricsResponse.ingestion_lag:type_name -> ccmon.v1.IngestionLag
+	9,  // 10: ccmon.v1.GetServerMetricsResponse.retention:type_name -> ccmon.v1.Retention
+	25, // 11: ccmon.v1.Retention.next_cleanup_at:type_name -> google.protobuf.Timestamp
+	11, // 12: ccmon.v1.Stats.base_tokens:type_name -> ccmon.v1.Token
+	11, // 13: ccmon.v1.Stats.premium_tokens:type_name -> ccmon.v1.Token
+	11, // 14: ccmon.v1.Stats.total_tokens:type_name -> ccmon.v1.Token
+	12, // 15: ccmon.v1.Stats.base_cost:type_name -> ccmon.v1.Cost
+	12, // 16: ccmon.v1.Stats.premium_cost:type_name -> ccmon.v1.Cost
+	12, // 17: ccmon.v1.Stats.total_cost:type_name -> ccmon.v1.Cost
+	11, // 18: ccmon.v1.Stats.long_context_tokens:type_name -> ccmon.v1.Token
+	12, // 19: ccmon.v1.Stats.long_context_cost:type_name -> ccmon.v1.Cost
+	24, // 20: ccmon.v1.Stats.models:type_name -> ccmon.v1.ModelStats
+	25, // 21: ccmon.v1.APIRequest.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 22: ccmon.v1.APIRequest.star:type_name -> ccmon.v1.StarScope
+	25, // 23: ccmon.v1.GetUserUsageRequest.start_time:type_name -> google.protobuf.Timestamp
+	25, // 24: ccmon.v1.GetUserUsageRequest.end_time:type_name -> google.protobuf.Timestamp
+	25, // 25: ccmon.v1.GetUserUsageRequest.block_start_time:type_name -> google.protobuf.Timestamp
+	25, // 26: ccmon.v1.GetUserUsageRequest.block_end_time:type_name -> google.protobuf.Timestamp
+	16, // 27: ccmon.v1.GetUserUsageResponse.users:type_name -> ccmon.v1.UserUsage
+	10, // 28: ccmon.v1.UserUsage.daily:type_name -> ccmon.v1.Stats
+	10, // 29: ccmon.v1.UserUsage.block:type_name -> ccmon.v1.Stats
+	12, // 30: ccmon.v1.UserUsage.daily_quota:type_name -> ccmon.v1.Cost
+	25, // 31: ccmon.v1.CountAPIRequestsRequest.start_time:type_name -> google.protobuf.Timestamp
+	25, // 32: ccmon.v1.CountAPIRequestsRequest.end_time:type_name -> google.protobuf.Timestamp
+	4,  // 33: ccmon.v1.CountAPIRequestsRequest.filter:type_name -> ccmon.v1.RequestFilter
+	4,  // 34: ccmon.v1.WatchAPIRequestsRequest.filter:type_name -> ccmon.v1.RequestFilter
+	13, // 35: ccmon.v1.WatchAPIRequestsResponse.requests:type_name -> ccmon.v1.APIRequest
+	23, // 36: ccmon.v1.GetIngestStatsResponse.last_hour:type_name -> ccmon.v1.IngestCounts
+	23, // 37: ccmon.v1.GetIngestStatsResponse.last_day:type_name -> ccmon.v1.IngestCounts
+	25, // 38: ccmon.v1.GetIngestStatsResponse.since:type_name -> google.protobuf.Timestamp
+	11, // 39: ccmon.v1.ModelStats.tokens:type_name -> ccmon.v1.Token
+	12, // 40: ccmon.v1.ModelStats.cost:type_name -> ccmon.v1.Cost
+	1,  // 41: ccmon.v1.QueryService.GetStats:input_type -> ccmon.v1.GetStatsRequest
+	3,  // 42: ccmon.v1.QueryService.GetAPIRequests:input_type -> ccmon.v1.GetAPIRequestsRequest
+	6,  // 43: ccmon.v1.QueryService.GetServerMetrics:input_type -> ccmon.v1.GetServerMetricsRequest
+	14, // 44: ccmon.v1.QueryService.GetUserUsage:input_type -> ccmon.v1.GetUserUsageRequest
+	17, // 45: ccmon.v1.QueryService.CountAPIRequests:input_type -> ccmon.v1.CountAPIRequestsRequest
+	19, // 46: ccmon.v1.QueryService.WatchAPIRequests:input_type -> ccmon.v1.WatchAPIRequestsRequest
+	21, // 47: ccmon.v1.QueryService.GetIngestStats:input_type -> ccmon.v1.GetIngestStatsRequest
+	2,  // 48: ccmon.v1.QueryService.GetStats:output_type -> ccmon.v1.GetStatsResponse
+	5,  // 49: ccmon.v1.QueryService.GetAPIRequests:output_type -> ccmon.v1.GetAPIRequestsResponse
+	7,  // 50: ccmon.v1.QueryService.GetServerMetrics:output_type -> ccmon.v1.GetServerMetricsResponse
+	15, // 51: ccmon.v1.QueryService.GetUserUsage:output_type -> ccmon.v1.GetUserUsageResponse
+	18, // 52: ccmon.v1.QueryService.CountAPIRequests:output_type -> ccmon.v1.CountAPIRequestsResponse
+	20, // 53: ccmon.v1.QueryService.WatchAPIRequests:output_type -> ccmon.v1.WatchAPIRequestsResponse
+	22, // 54: ccmon.v1.QueryService.GetIngestStats:output_type -> ccmon.v1.GetIngestStatsResponse
+	48, // [48:55] is the sub-list for method output_type
+	41, // [41:48] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_query_proto_init() }
+func file_api_v1_query_proto_init() {
+	if File_api_v1_query_proto != nil {
 		return
 	}
 	if !protoimpl.UnsafeEnabled {
-		file_proto_query_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetStatsRequest); i {
 			case 0:
 				return &v.state
@@ -815,7 +2310,7 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetStatsResponse); i {
 			case 0:
 				return &v.state
@@ -827,7 +2322,7 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetAPIRequestsRequest); i {
 			case 0:
 				return &v.state
@@ -839,7 +2334,19 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GetAPIRequestsResponse); i {
 			case 0:
 				return &v.state
@@ -851,7 +2358,55 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetServerMetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetServerMetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IngestionLag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Retention); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Stats); i {
 			case 0:
 				return &v.state
@@ -863,7 +2418,7 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Token); i {
 			case 0:
 				return &v.state
@@ -875,7 +2430,7 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Cost); i {
 			case 0:
 				return &v.state
@@ -887,7 +2442,7 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
-		file_proto_query_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_api_v1_query_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*APIRequest); i {
 			case 0:
 				return &v.state
@@ -899,23 +2454,156 @@ func file_proto_query_proto_init() {
 				return nil
 			}
 		}
+		file_api_v1_query_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserUsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountAPIRequestsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CountAPIRequestsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchAPIRequestsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchAPIRequestsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIngestStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIngestStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IngestCounts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_query_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModelStats); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_proto_query_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   8,
+			RawDescriptor: file_api_v1_query_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
-		GoTypes:           file_proto_query_proto_goTypes,
-		DependencyIndexes: file_proto_query_proto_depIdxs,
-		MessageInfos:      file_proto_query_proto_msgTypes,
+		GoTypes:           file_api_v1_query_proto_goTypes,
+		DependencyIndexes: file_api_v1_query_proto_depIdxs,
+		EnumInfos:         file_api_v1_query_proto_enumTypes,
+		MessageInfos:      file_api_v1_query_proto_msgTypes,
 	}.Build()
-	File_proto_query_proto = out.File
-	file_proto_query_proto_rawDesc = nil
-	file_proto_query_proto_goTypes = nil
-	file_proto_query_proto_depIdxs = nil
+	File_api_v1_query_proto = out.File
+	file_api_v1_query_proto_rawDesc = nil
+	file_api_v1_query_proto_goTypes = nil
+	file_api_v1_query_proto_depIdxs = nil
 }
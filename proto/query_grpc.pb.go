@@ -2,7 +2,7 @@
 // versions:
 // - protoc-gen-go-grpc v1.2.0
 // - protoc             v6.30.2
-// source: proto/query.proto
+// source: api/v1/query.proto
 
 package queryv1
 
@@ -26,6 +26,16 @@ type QueryServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	// GetAPIRequests returns API request records
 	GetAPIRequests(ctx context.Context, in *GetAPIRequestsRequest, opts ...grpc.CallOption) (*GetAPIRequestsResponse, error)
+	// GetServerMetrics returns server-side ingestion metrics
+	GetServerMetrics(ctx context.Context, in *GetServerMetricsRequest, opts ...grpc.CallOption) (*GetServerMetricsResponse, error)
+	// GetUserUsage returns the daily and block usage of each user with their quota status
+	GetUserUsage(ctx context.Context, in *GetUserUsageRequest, opts ...grpc.CallOption) (*GetUserUsageResponse, error)
+	// CountAPIRequests returns the number of API requests matching the period and the filter
+	CountAPIRequests(ctx context.Context, in *CountAPIRequestsRequest, opts ...grpc.CallOption) (*CountAPIRequestsResponse, error)
+	// WatchAPIRequests streams API requests matching the filter as they are received
+	WatchAPIRequests(ctx context.Context, in *WatchAPIRequestsRequest, opts ...grpc.CallOption) (QueryService_WatchAPIRequestsClient, error)
+	// GetIngestStats returns the outcomes of the API request events received over the last hour and day
+	GetIngestStats(ctx context.Context, in *GetIngestStatsRequest, opts ...grpc.CallOption) (*GetIngestStatsResponse, error)
 }
 
 type queryServiceClient struct {
@@ -54,6 +64,74 @@ func (c *queryServiceClient) GetAPIRequests(ctx context.Context, in *GetAPIReque
 	return out, nil
 }
 
+func (c *queryServiceClient) GetServerMetrics(ctx context.Context, in *GetServerMetricsRequest, opts ...grpc.CallOption) (*GetServerMetricsResponse, error) {
+	out := new(GetServerMetricsResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetServerMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) GetUserUsage(ctx context.Context, in *GetUserUsageRequest, opts ...grpc.CallOption) (*GetUserUsageResponse, error) {
+	out := new(GetUserUsageResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetUserUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) CountAPIRequests(ctx context.Context, in *CountAPIRequestsRequest, opts ...grpc.CallOption) (*CountAPIRequestsResponse, error) {
+	out := new(CountAPIRequestsResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/CountAPIRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) WatchAPIRequests(ctx context.Context, in *WatchAPIRequestsRequest, opts ...grpc.CallOption) (QueryService_WatchAPIRequestsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], "/ccmon.v1.QueryService/WatchAPIRequests", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceWatchAPIRequestsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func (c *queryServiceClient) GetIngestStats(ctx context.Context, in *GetIngestStatsRequest, opts ...grpc.CallOption) (*GetIngestStatsResponse, error) {
+	out := new(GetIngestStatsResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.QueryService/GetIngestStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type QueryService_WatchAPIRequestsClient interface {
+	Recv() (*WatchAPIRequestsResponse, error)
+	grpc.ClientStream
+}
+
+type queryServiceWatchAPIRequestsClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceWatchAPIRequestsClient) Recv() (*WatchAPIRequestsResponse, error) {
+	m := new(WatchAPIRequestsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 // All implementations must embed UnimplementedQueryServiceServer
 // for forward compatibility
@@ -62,6 +140,16 @@ type QueryServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	// GetAPIRequests returns API request records
 	GetAPIRequests(context.Context, *GetAPIRequestsRequest) (*GetAPIRequestsResponse, error)
+	// GetServerMetrics returns server-side ingestion metrics
+	GetServerMetrics(context.Context, *GetServerMetricsRequest) (*GetServerMetricsResponse, error)
+	// GetUserUsage returns the daily and block usage of each user with their quota status
+	GetUserUsage(context.Context, *GetUserUsageRequest) (*GetUserUsageResponse, error)
+	// CountAPIRequests returns the number of API requests matching the period and the filter
+	CountAPIRequests(context.Context, *CountAPIRequestsRequest) (*CountAPIRequestsResponse, error)
+	// WatchAPIRequests streams API requests matching the filter as they are received
+	WatchAPIRequests(*WatchAPIRequestsRequest, QueryService_WatchAPIRequestsServer) error
+	// GetIngestStats returns the outcomes of the API request events received over the last hour and day
+	GetIngestStats(context.Context, *GetIngestStatsRequest) (*GetIngestStatsResponse, error)
 	mustEmbedUnimplementedQueryServiceServer()
 }
 
@@ -75,6 +163,21 @@ func (UnimplementedQueryServiceServer) GetStats(context.Context, *GetStatsReques
 func (UnimplementedQueryServiceServer) GetAPIRequests(context.Context, *GetAPIRequestsRequest) (*GetAPIRequestsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAPIRequests not implemented")
 }
+func (UnimplementedQueryServiceServer) GetServerMetrics(context.Context, *GetServerMetricsRequest) (*GetServerMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServerMetrics not implemented")
+}
+func (UnimplementedQueryServiceServer) GetUserUsage(context.Context, *GetUserUsageRequest) (*GetUserUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserUsage not implemented")
+}
+func (UnimplementedQueryServiceServer) CountAPIRequests(context.Context, *CountAPIRequestsRequest) (*CountAPIRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountAPIRequests not implemented")
+}
+func (UnimplementedQueryServiceServer) WatchAPIRequests(*WatchAPIRequestsRequest, QueryService_WatchAPIRequestsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAPIRequests not implemented")
+}
+func (UnimplementedQueryServiceServer) GetIngestStats(context.Context, *GetIngestStatsRequest) (*GetIngestStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIngestStats not implemented")
+}
 func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
 
 // UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -124,6 +227,99 @@ func _QueryService_GetAPIRequests_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_GetServerMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetServerMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetServerMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetServerMetrics(ctx, req.(*GetServerMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_GetUserUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetUserUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetUserUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetUserUsage(ctx, req.(*GetUserUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_CountAPIRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountAPIRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).CountAPIRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/CountAPIRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).CountAPIRequests(ctx, req.(*CountAPIRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_WatchAPIRequests_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAPIRequestsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).WatchAPIRequests(m, &queryServiceWatchAPIRequestsServer{stream})
+}
+
+type QueryService_WatchAPIRequestsServer interface {
+	Send(*WatchAPIRequestsResponse) error
+	grpc.ServerStream
+}
+
+type queryServiceWatchAPIRequestsServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceWatchAPIRequestsServer) Send(m *WatchAPIRequestsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _QueryService_GetIngestStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIngestStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).GetIngestStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.QueryService/GetIngestStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).GetIngestStats(ctx, req.(*GetIngestStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -139,7 +335,29 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAPIRequests",
 			Handler:    _QueryService_GetAPIRequests_Handler,
 		},
+		{
+			MethodName: "GetServerMetrics",
+			Handler:    _QueryService_GetServerMetrics_Handler,
+		},
+		{
+			MethodName: "GetUserUsage",
+			Handler:    _QueryService_GetUserUsage_Handler,
+		},
+		{
+			MethodName: "CountAPIRequests",
+			Handler:    _QueryService_CountAPIRequests_Handler,
+		},
+		{
+			MethodName: "GetIngestStats",
+			Handler:    _QueryService_GetIngestStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAPIRequests",
+			Handler:       _QueryService_WatchAPIRequests_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/query.proto",
+	Metadata: "api/v1/query.proto",
 }
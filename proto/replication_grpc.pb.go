@@ -0,0 +1,134 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v6.30.2
+// source: api/v1/replication.proto
+
+package queryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ReplicationServiceClient is the client API for ReplicationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReplicationServiceClient interface {
+	// GetSnapshot streams the database snapshot in chunks
+	GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (ReplicationService_GetSnapshotClient, error)
+}
+
+type replicationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReplicationServiceClient(cc grpc.ClientConnInterface) ReplicationServiceClient {
+	return &replicationServiceClient{cc}
+}
+
+func (c *replicationServiceClient) GetSnapshot(ctx context.Context, in *GetSnapshotRequest, opts ...grpc.CallOption) (ReplicationService_GetSnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ReplicationService_ServiceDesc.Streams[0], "/ccmon.v1.ReplicationService/GetSnapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &replicationServiceGetSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReplicationService_GetSnapshotClient interface {
+	Recv() (*SnapshotChunk, error)
+	grpc.ClientStream
+}
+
+type replicationServiceGetSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *replicationServiceGetSnapshotClient) Recv() (*SnapshotChunk, error) {
+	m := new(SnapshotChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReplicationServiceServer is the server API for ReplicationService service.
+// All implementations must embed UnimplementedReplicationServiceServer
+// for forward compatibility
+type ReplicationServiceServer interface {
+	// GetSnapshot streams the database snapshot in chunks
+	GetSnapshot(*GetSnapshotRequest, ReplicationService_GetSnapshotServer) error
+	mustEmbedUnimplementedReplicationServiceServer()
+}
+
+// UnimplementedReplicationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedReplicationServiceServer struct {
+}
+
+func (UnimplementedReplicationServiceServer) GetSnapshot(*GetSnapshotRequest, ReplicationService_GetSnapshotServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetSnapshot not implemented")
+}
+func (UnimplementedReplicationServiceServer) mustEmbedUnimplementedReplicationServiceServer() {}
+
+// UnsafeReplicationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReplicationServiceServer will
+// result in compilation errors.
+type UnsafeReplicationServiceServer interface {
+	mustEmbedUnimplementedReplicationServiceServer()
+}
+
+func RegisterReplicationServiceServer(s grpc.ServiceRegistrar, srv ReplicationServiceServer) {
+	s.RegisterService(&ReplicationService_ServiceDesc, srv)
+}
+
+func _ReplicationService_GetSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReplicationServiceServer).GetSnapshot(m, &replicationServiceGetSnapshotServer{stream})
+}
+
+type ReplicationService_GetSnapshotServer interface {
+	Send(*SnapshotChunk) error
+	grpc.ServerStream
+}
+
+type replicationServiceGetSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *replicationServiceGetSnapshotServer) Send(m *SnapshotChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ReplicationService_ServiceDesc is the grpc.ServiceDesc for ReplicationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReplicationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ccmon.v1.ReplicationService",
+	HandlerType: (*ReplicationServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetSnapshot",
+			Handler:       _ReplicationService_GetSnapshot_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/v1/replication.proto",
+}
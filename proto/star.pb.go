@@ -0,0 +1,231 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v6.30.2
+// source: api/v1/star.proto
+
+package queryv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SetStarRequest identifies the request to star
+type SetStarRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId string    `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // ID of the API request, as derived from its timestamp and session
+	SessionId string    `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Session of the API request, starred with STAR_SCOPE_SESSION
+	Scope     StarScope `protobuf:"varint,3,opt,name=scope,proto3,enum=ccmon.v1.StarScope" json:"scope,omitempty"`
+}
+
+func (x *SetStarRequest) Reset() {
+	*x = SetStarRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_star_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetStarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStarRequest) ProtoMessage() {}
+
+func (x *SetStarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_star_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStarRequest.ProtoReflect.Descriptor instead.
+func (*SetStarRequest) Descriptor() ([]byte, []int) {
+	return file_api_v1_star_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetStarRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SetStarRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SetStarRequest) GetScope() StarScope {
+	if x != nil {
+		return x.Scope
+	}
+	return StarScope_STAR_SCOPE_UNSPECIFIED
+}
+
+// SetStarResponse is empty, the star is stored when the call succeeds
+type SetStarResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetStarResponse) Reset() {
+	*x = SetStarResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_v1_star_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetStarResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetStarResponse) ProtoMessage() {}
+
+func (x *SetStarResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_v1_star_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetStarResponse.ProtoReflect.Descriptor instead.
+func (*SetStarResponse) Descriptor() ([]byte, []int) {
+	return file_api_v1_star_proto_rawDescGZIP(), []int{1}
+}
+
+var File_api_v1_star_proto protoreflect.FileDescriptor
+
+var file_api_v1_star_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x74, 0x61, 0x72, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x08, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x1a, 0x12, 0x61,
+	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x79, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49,
+	0x64, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x61, 0x72,
+	0x53, 0x63, 0x6f, 0x70, 0x65, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x22, 0x11, 0x0a, 0x0f,
+	0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32,
+	0x4d, 0x0a, 0x0b, 0x53, 0x74, 0x61, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e,
+	0x0a, 0x07, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x12, 0x18, 0x2e, 0x63, 0x63, 0x6d, 0x6f,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29,
+	0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x6c, 0x63,
+	0x74, 0x39, 0x36, 0x32, 0x30, 0x2f, 0x63, 0x63, 0x6d, 0x6f, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x3b, 0x71, 0x75, 0x65, 0x72, 0x79, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_api_v1_star_proto_rawDescOnce sync.Once
+	file_api_v1_star_proto_rawDescData = file_api_v1_star_proto_rawDesc
+)
+
+func file_api_v1_star_proto_rawDescGZIP() []byte {
+	file_api_v1_star_proto_rawDescOnce.Do(func() {
+		file_api_v1_star_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_v1_star_proto_rawDescData)
+	})
+	return file_api_v1_star_proto_rawDescData
+}
+
+var file_api_v1_star_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_v1_star_proto_goTypes = []interface{}{
+	(*SetStarRequest)(nil),  // 0: ccmon.v1.SetStarRequest
+	(*SetStarResponse)(nil), // 1: ccmon.v1.SetStarResponse
+	(StarScope)(0),          // 2: ccmon.v1.StarScope
+}
+var file_api_v1_star_proto_depIdxs = []int32{
+	2, // 0: ccmon.v1.SetStarRequest.scope:type_name -> ccmon.v1.StarScope
+	0, // 1: ccmon.v1.StarService.SetStar:input_type -> ccmon.v1.SetStarRequest
+	1, // 2: ccmon.v1.StarService.SetStar:output_type -> ccmon.v1.SetStarResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_api_v1_star_proto_init() }
+func file_api_v1_star_proto_init() {
+	if File_api_v1_star_proto != nil {
+		return
+	}
+	file_api_v1_query_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_api_v1_star_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetStarRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_v1_star_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetStarResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_v1_star_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_v1_star_proto_goTypes,
+		DependencyIndexes: file_api_v1_star_proto_depIdxs,
+		MessageInfos:      file_api_v1_star_proto_msgTypes,
+	}.Build()
+	File_api_v1_star_proto = out.File
+	file_api_v1_star_proto_rawDesc = nil
+	file_api_v1_star_proto_goTypes = nil
+	file_api_v1_star_proto_depIdxs = nil
+}
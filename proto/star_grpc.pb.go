@@ -0,0 +1,107 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v6.30.2
+// source: api/v1/star.proto
+
+package queryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// StarServiceClient is the client API for StarService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StarServiceClient interface {
+	// SetStar stars the request or its whole session, STAR_SCOPE_UNSPECIFIED removes both stars
+	SetStar(ctx context.Context, in *SetStarRequest, opts ...grpc.CallOption) (*SetStarResponse, error)
+}
+
+type starServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStarServiceClient(cc grpc.ClientConnInterface) StarServiceClient {
+	return &starServiceClient{cc}
+}
+
+func (c *starServiceClient) SetStar(ctx context.Context, in *SetStarRequest, opts ...grpc.CallOption) (*SetStarResponse, error) {
+	out := new(SetStarResponse)
+	err := c.cc.Invoke(ctx, "/ccmon.v1.StarService/SetStar", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StarServiceServer is the server API for StarService service.
+// All implementations must embed UnimplementedStarServiceServer
+// for forward compatibility
+type StarServiceServer interface {
+	// SetStar stars the request or its whole session, STAR_SCOPE_UNSPECIFIED removes both stars
+	SetStar(context.Context, *SetStarRequest) (*SetStarResponse, error)
+	mustEmbedUnimplementedStarServiceServer()
+}
+
+// UnimplementedStarServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStarServiceServer struct {
+}
+
+func (UnimplementedStarServiceServer) SetStar(context.Context, *SetStarRequest) (*SetStarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStar not implemented")
+}
+func (UnimplementedStarServiceServer) mustEmbedUnimplementedStarServiceServer() {}
+
+// UnsafeStarServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StarServiceServer will
+// result in compilation errors.
+type UnsafeStarServiceServer interface {
+	mustEmbedUnimplementedStarServiceServer()
+}
+
+func RegisterStarServiceServer(s grpc.ServiceRegistrar, srv StarServiceServer) {
+	s.RegisterService(&StarService_ServiceDesc, srv)
+}
+
+func _StarService_SetStar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StarServiceServer).SetStar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ccmon.v1.StarService/SetStar",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StarServiceServer).SetStar(ctx, req.(*SetStarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StarService_ServiceDesc is the grpc.ServiceDesc for StarService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StarService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ccmon.v1.StarService",
+	HandlerType: (*StarServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetStar",
+			Handler:    _StarService_SetStar_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/v1/star.proto",
+}
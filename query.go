@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// queryMetricOptions are the arguments of the `ccmon query metric <name>` command
+type queryMetricOptions struct {
+	name      string
+	period    string // empty uses the period of the metric name
+	raw       bool
+	precision int // cli.MetricDefaultPrecision uses the decimals of the metric
+}
+
+// runQuery runs a `ccmon query <resource>` command and returns the exit code
+func runQuery(config *Config, resource string, blockTime string, period string, at string, origin string, metric queryMetricOptions) int {
+	switch resource {
+	case "stats":
+		return runQueryStats(config, blockTime, period, at, origin)
+	case "metric":
+		return runQueryMetric(config, blockTime, at, origin, metric)
+	case "":
+		fmt.Fprintf(os.Stderr, "Missing query resource, expected: stats, metric\n")
+		return 1
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown query resource: %s\n", resource)
+		return 1
+	}
+}
+
+// runQueryStats prints the stats for the period as they were at the given point in time
+// An origin limits the stats to live or imported records, empty includes both
+func runQueryStats(config *Config, blockTime string, period string, at string, origin string) int {
+	if err := cli.ValidateStatsPeriod(period); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if origin != "" {
+		if _, err := entity.ParseOrigin(origin); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	// Default to the current stats
+	pointInTime := time.Now()
+	if at != "" {
+		pointInTime, err = entity.ParsePointInTime(at, timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+	}
+
+	// The block is the one containing the point in time, not the current block
+	block, err := createBlockAt(blockTime, timezone, config.Claude.GetTokenLimit(), config.Claude.GetBlockDuration(), pointInTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC connection: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}()
+
+	apiRepo := repository.NewGRPCAPIRequestRepositoryWithConnection(conn)
+	statsRepo := repository.NewGRPCStatsRepositoryWithConnection(conn)
+
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(repository.NegotiateStatsRepository(statsRepo, apiRepo), &service.NoOpStatsCache{})
+
+	handler := cli.NewStatsHandler(calculateStatsQuery, timezone, block, config.Display.GetCostFormat())
+	handler.SetOrigin(origin)
+	if err := handler.HandleStats(period, pointInTime, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runQueryMetric prints a single metric, exit codes follow the format query mode so scripts can tell errors apart
+func runQueryMetric(config *Config, blockTime string, at string, origin string, options queryMetricOptions) int {
+	if options.name == "" {
+		fmt.Fprintf(os.Stderr, "Missing metric name, expected one of: %s\n", strings.Join(cli.MetricNames(), ", "))
+		return cli.ExitCodeInvalidFormat
+	}
+	if origin != "" {
+		if _, err := entity.ParseOrigin(origin); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return cli.ExitCodeError
+		}
+	}
+
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return cli.ExitCodeError
+	}
+
+	// Default to the current value
+	pointInTime := time.Now()
+	if at != "" {
+		pointInTime, err = entity.ParsePointInTime(at, timezone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return cli.ExitCodeInvalidPeriod
+		}
+	}
+
+	block, err := createBlockAt(blockTime, timezone, config.Claude.GetTokenLimit(), config.Claude.GetBlockDuration(), pointInTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return cli.ExitCodeError
+	}
+
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC connection: %v\n", err)
+		return cli.ExitCodeConnection
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}()
+
+	apiRepo := repository.NewGRPCAPIRequestRepositoryWithConnection(conn)
+	statsRepo := repository.NewGRPCStatsRepositoryWithConnection(conn)
+
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(repository.NegotiateStatsRepository(statsRepo, apiRepo), &service.NoOpStatsCache{})
+
+	handler := cli.NewMetricHandler(calculateStatsQuery, timezone, block, config.Display.GetCostFormat())
+	handler.SetOrigin(origin)
+	if err := handler.HandleMetric(options.name, options.period, pointInTime, options.raw, options.precision, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", entity.ErrorMessage(err))
+		return cli.ExitCode(err)
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runRecalculateCosts derives the cost of the stored requests from the embedded prices and returns the exit code
+// Only zero costs are filled unless overwrite is set, which also replaces the costs reported by Claude Code
+func runRecalculateCosts(config *Config, overwrite bool) int {
+	pricingRepository, err := repository.NewEmbeddedPricingRepository(dataFS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize pricing repository: %v\n", err)
+		return 1
+	}
+
+	// The database is locked while the server runs, stop it before recalculating
+	db, err := NewDatabase(config.Database.Path)
+	if errors.Is(err, ErrDatabaseLocked) {
+		fmt.Fprintf(os.Stderr, "A running ccmon server is using %s.\n", config.Database.Path)
+		fmt.Fprintf(os.Stderr, "Stop the server before recalculating costs.\n")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Printf("Error closing database: %v", err)
+		}
+	}()
+
+	// Replaced costs cannot be told apart afterwards, keep the database as it was for db undo-last
+	if !writeMaintenanceSnapshot(db, config.Database.Path, "recalculate-costs") {
+		return 1
+	}
+
+	command := usecase.NewRecalculateCostsCommand(repository.NewBoltDBAPIRequestRepository(db), pricingRepository)
+	result, err := command.Execute(context.Background(), usecase.RecalculateCostsParams{
+		Period:    entity.NewAllTimePeriod(time.Now()),
+		Overwrite: overwrite,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to recalculate costs: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Recalculated costs of %d requests in %s (%d scanned)\n", result.Updated, config.Database.Path, result.Scanned)
+	if result.Unpriced > 0 {
+		fmt.Printf("%d requests of models without a price were left unchanged\n", result.Unpriced)
+	}
+	fmt.Printf("Run ccmon db undo-last to restore the previous costs\n")
+	return 0
+}
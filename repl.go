@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runRepl runs the interactive query prompt against the server and returns the exit code
+func runRepl(config *Config) int {
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC connection: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}()
+
+	apiRepo := repository.NewGRPCAPIRequestRepositoryWithConnection(conn)
+	statsRepo := repository.NewGRPCStatsRepositoryWithConnection(conn)
+
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(repository.NegotiateStatsRepository(statsRepo, apiRepo), &service.NoOpStatsCache{})
+	getFilteredQuery := usecase.NewGetFilteredApiRequestsQuery(apiRepo)
+
+	handler := cli.NewReplHandler(calculateStatsQuery, getFilteredQuery, timezone, config.Display.GetCostFormat())
+	if err := handler.HandleRepl(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
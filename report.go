@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runReport runs a `ccmon report <kind>` command and returns the exit code
+func runReport(config *Config, kind string, format string, days int) int {
+	switch kind {
+	case "daily":
+		return runReportDaily(config, format, days)
+	case "":
+		fmt.Fprintf(os.Stderr, "Missing report, expected: daily\n")
+		return 1
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown report: %s\n", kind)
+		return 1
+	}
+}
+
+// runReportDaily prints the usage of the last days as a table matching the daily usage tab
+func runReportDaily(config *Config, format string, days int) int {
+	if err := cli.ValidateReportFormat(format); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	if err := cli.ValidateReportDays(days); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	timeFormat, err := config.Display.GetTimeFormat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid time format: %v\n", err)
+		return 1
+	}
+
+	apiRepo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := apiRepo.Close(); err != nil {
+			log.Printf("Error closing gRPC repository: %v", err)
+		}
+	}()
+
+	getUsageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(timezone))
+
+	handler := cli.NewReportHandler(getUsageQuery, timezone, timeFormat, config.Display.GetCostFormat())
+	if err := handler.HandleDailyReport(days, format, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes the data with the permissions through a temporary file renamed over path
+// Readers polling the file see either the previous or the new content, and an interrupted write keeps the previous one
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := file.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if err := file.Chmod(perm); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
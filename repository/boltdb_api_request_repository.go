@@ -3,6 +3,7 @@ package repository
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -17,7 +18,8 @@ const (
 
 // BoltDBAPIRequestRepository implements APIRequestRepository using BoltDB
 type BoltDBAPIRequestRepository struct {
-	db *bbolt.DB
+	db     *bbolt.DB
+	writer *writeBehind // nil writes every save right away
 }
 
 // NewBoltDBAPIRequestRepository creates a new BoltDB repository instance
@@ -27,11 +29,40 @@ func NewBoltDBAPIRequestRepository(db *bbolt.DB) *BoltDBAPIRequestRepository {
 	}
 }
 
-// Save stores an API request entity
+// Save stores an API request entity, queued for the next batch when write-behind is started
 func (r *BoltDBAPIRequestRepository) Save(req entity.APIRequest) error {
+	if r.writer != nil && r.writer.queue(req) {
+		return nil
+	}
 	return r.saveRequest(req)
 }
 
+// SaveBatch stores all API request entities in a single transaction, queued for the next batch when write-behind is started
+func (r *BoltDBAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	if r.writer != nil && r.writer.queue(reqs...) {
+		return nil
+	}
+	return r.writeRequests(reqs)
+}
+
+// writeRequests writes the API requests in a single transaction
+func (r *BoltDBAPIRequestRepository) writeRequests(reqs []entity.APIRequest) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(requestsBucket))
+		dailyStats := newDailyStatsWriter(tx)
+		for _, req := range reqs {
+			if err := r.putRequest(bucket, dailyStats, req); err != nil {
+				return err
+			}
+		}
+		return dailyStats.flush()
+	})
+}
+
 // FindByPeriodWithLimit retrieves API requests filtered by time period with limit and offset
 // Use limit = 0 for no limit (fetch all records)
 // Use offset = 0 when no offset is needed
@@ -47,7 +78,7 @@ func (r *BoltDBAPIRequestRepository) FindByPeriodWithLimit(period entity.Period,
 		}
 	} else {
 		// Query time range with limit/offset
-		dbRequests, err = r.queryTimeRangeWithLimit(period.StartAt(), period.EndAt(), limit, offset)
+		dbRequests, err = r.queryTimeRangeWithLimit(period.StartAt(), period.EndAt(), limit, offset, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -57,6 +88,228 @@ func (r *BoltDBAPIRequestRepository) FindByPeriodWithLimit(period entity.Period,
 	return r.convertToEntities(dbRequests), nil
 }
 
+// SampleByPeriod retrieves an evenly spread sample of at most size API requests of the period and the number of requests in it
+// Keys are counted without decoding the records, so only the sampled records pay for JSON decoding
+func (r *BoltDBAPIRequestRepository) SampleByPeriod(period entity.Period, size int) ([]entity.APIRequest, int, error) {
+	var dbRequests []schema.APIRequest
+	var total int
+
+	// All time periods scan the whole bucket
+	var startKey, endKey []byte
+	if !period.IsAllTime() {
+		startKey = []byte(period.StartAt().Format(time.RFC3339Nano))
+		endKey = []byte(period.EndAt().Format(time.RFC3339Nano) + "\xff")
+	}
+	inRange := func(k []byte) bool {
+		return k != nil && (endKey == nil || string(k) < string(endKey))
+	}
+
+	err := r.view(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(requestsBucket)).Cursor()
+
+		for k, _ := c.Seek(startKey); inRange(k); k, _ = c.Next() {
+			total++
+		}
+
+		step := 1
+		if size > 0 && total > size {
+			step = (total + size - 1) / size
+		}
+
+		i := 0
+		for k, v := c.Seek(startKey); inRange(k); k, v = c.Next() {
+			if i%step == 0 {
+				var req schema.APIRequest
+				if err := json.Unmarshal(v, &req); err == nil {
+					dbRequests = append(dbRequests, req)
+				}
+			}
+			i++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r.convertToEntities(dbRequests), total, nil
+}
+
+// FindByPeriods retrieves API requests of several periods with a single range scan
+// Requests are returned per period in the given order, a request is included in every period containing it
+func (r *BoltDBAPIRequestRepository) FindByPeriods(periods []entity.Period) ([][]entity.APIRequest, error) {
+	requestsByPeriod := make([][]entity.APIRequest, len(periods))
+	if len(periods) == 0 {
+		return requestsByPeriod, nil
+	}
+
+	start, end := periods[0].StartAt(), periods[0].EndAt()
+	for _, period := range periods {
+		// All-time periods are limited differently, they are not merged into the range scan
+		if period.IsAllTime() {
+			return r.findByPeriodsSeparately(periods)
+		}
+		if period.StartAt().Before(start) {
+			start = period.StartAt()
+		}
+		if period.EndAt().After(end) {
+			end = period.EndAt()
+		}
+	}
+
+	dbRequests, err := r.queryTimeRangeWithLimit(start, end, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, req := range r.convertToEntities(dbRequests) {
+		for i, period := range periods {
+			if req.Timestamp().Before(period.StartAt()) || req.Timestamp().After(period.EndAt()) {
+				continue
+			}
+			requestsByPeriod[i] = append(requestsByPeriod[i], req)
+		}
+	}
+
+	return requestsByPeriod, nil
+}
+
+// FindByFilter retrieves API requests matching the filter with limit and offset
+// The dimensions are matched during the range scan, so the limit applies to the matching requests
+func (r *BoltDBAPIRequestRepository) FindByFilter(filter entity.Filter, limit int, offset int) ([]entity.APIRequest, error) {
+	// All-time periods start at the zero time, which precedes every timestamp key
+	period := filter.Period()
+	dbRequests, err := r.queryTimeRangeWithLimit(period.StartAt(), period.EndAt(), limit, offset, func(req schema.APIRequest) bool {
+		return filter.Matches(r.convertToEntity(req))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.convertToEntities(dbRequests), nil
+}
+
+// FindPageByFilter retrieves the newest limit API requests matching the filter before the cursor
+// The cursor is the key of the oldest request of the previous page, the scan walks back from it so older pages don't read the newer records again
+func (r *BoltDBAPIRequestRepository) FindPageByFilter(filter entity.Filter, limit int, cursor string) ([]entity.APIRequest, string, error) {
+	// All time periods scan the whole bucket
+	var startKey, endKey []byte
+	period := filter.Period()
+	if !period.IsAllTime() {
+		startKey = []byte(period.StartAt().Format(time.RFC3339Nano))
+		endKey = []byte(period.EndAt().Format(time.RFC3339Nano) + "\xff")
+	}
+	if cursor != "" && (endKey == nil || cursor < string(endKey)) {
+		endKey = []byte(cursor)
+	}
+	inRange := func(k []byte) bool {
+		return k != nil && (startKey == nil || string(k) >= string(startKey))
+	}
+
+	var dbRequests []schema.APIRequest
+	var keys []string
+	err := r.view(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(requestsBucket)).Cursor()
+
+		// Start at the last key before the end key, which is excluded
+		k, v := c.Last()
+		if endKey != nil {
+			if k, v = c.Seek(endKey); k == nil {
+				k, v = c.Last()
+			} else {
+				k, v = c.Prev()
+			}
+		}
+
+		// One more request than the limit tells whether another page remains
+		for ; inRange(k) && (limit == 0 || len(dbRequests) <= limit); k, v = c.Prev() {
+			var req schema.APIRequest
+			if err := json.Unmarshal(v, &req); err != nil {
+				// Skip malformed entries
+				continue
+			}
+			if filter.HasDimensions() && !filter.Matches(r.convertToEntity(req)) {
+				continue
+			}
+			dbRequests = append(dbRequests, req)
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if limit > 0 && len(dbRequests) > limit {
+		dbRequests = dbRequests[:limit]
+		next = keys[limit-1]
+	}
+
+	// Collected newest first, pages are returned in chronological order
+	requests := r.convertToEntities(dbRequests)
+	for i, j := 0, len(requests)-1; i < j; i, j = i+1, j-1 {
+		requests[i], requests[j] = requests[j], requests[i]
+	}
+	return requests, next, nil
+}
+
+// CountByFilter counts API requests matching the filter
+// Without dimensions only the keys are counted, so no record pays for JSON decoding
+func (r *BoltDBAPIRequestRepository) CountByFilter(filter entity.Filter) (int, error) {
+	var count int
+
+	// All time periods scan the whole bucket
+	var startKey, endKey []byte
+	period := filter.Period()
+	if !period.IsAllTime() {
+		startKey = []byte(period.StartAt().Format(time.RFC3339Nano))
+		endKey = []byte(period.EndAt().Format(time.RFC3339Nano) + "\xff")
+	}
+	inRange := func(k []byte) bool {
+		return k != nil && (endKey == nil || string(k) < string(endKey))
+	}
+
+	err := r.view(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(requestsBucket)).Cursor()
+
+		for k, v := c.Seek(startKey); inRange(k); k, v = c.Next() {
+			if !filter.HasDimensions() {
+				count++
+				continue
+			}
+
+			var req schema.APIRequest
+			if err := json.Unmarshal(v, &req); err != nil {
+				// Skip malformed entries
+				continue
+			}
+			if filter.Matches(r.convertToEntity(req)) {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// findByPeriodsSeparately retrieves API requests of each period with its own query
+func (r *BoltDBAPIRequestRepository) findByPeriodsSeparately(periods []entity.Period) ([][]entity.APIRequest, error) {
+	requestsByPeriod := make([][]entity.APIRequest, len(periods))
+	for i, period := range periods {
+		requests, err := r.FindByPeriodWithLimit(period, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		requestsByPeriod[i] = requests
+	}
+	return requestsByPeriod, nil
+}
+
 // FindAll retrieves all API requests (limited to prevent memory issues)
 func (r *BoltDBAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
 	dbRequests, err := r.getAllRequests()
@@ -66,16 +319,22 @@ func (r *BoltDBAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
 	return r.convertToEntities(dbRequests), nil
 }
 
-// DeleteOlderThan deletes API requests older than the specified cutoff time
+// DeleteOlderThan deletes API requests older than the specified cutoff time, except the starred ones in keep
 // Returns the number of deleted records and any error
-func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
+	// Queued requests are written first, otherwise the old ones among them would outlive the cleanup
+	if err := r.flushPending(); err != nil {
+		return 0, err
+	}
+
 	deletedCount := 0
 
 	err := r.db.Update(func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket([]byte(requestsBucket))
 		c := bucket.Cursor()
 
-		// Collect keys to delete
+		// Collect keys to delete and remove them from the daily aggregates
+		dailyStats := newDailyStatsWriter(tx)
 		var keysToDelete [][]byte
 		for k, v := c.First(); k != nil; k, v = c.Next() {
 			// Parse the timestamp from the stored record to compare properly
@@ -85,12 +344,15 @@ func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int,
 				continue
 			}
 
-			// Only delete records that are strictly older than cutoff time
-			if req.Timestamp.Before(cutoffTime) {
+			// Only delete records that are strictly older than cutoff time and not starred
+			if req.Timestamp.Before(cutoffTime) && !keep.IsStarred(r.convertToEntity(req)) {
 				// Make a copy of the key since it's only valid for the life of the transaction
 				keyToDelete := make([]byte, len(k))
 				copy(keyToDelete, k)
 				keysToDelete = append(keysToDelete, keyToDelete)
+				if err := dailyStats.add(req, -1); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -102,44 +364,68 @@ func (r *BoltDBAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int,
 			deletedCount++
 		}
 
-		return nil
+		return dailyStats.flush()
 	})
 
 	return deletedCount, err
 }
 
-// Close closes the database connection
+// Close writes the queued requests and closes the database connection
 func (r *BoltDBAPIRequestRepository) Close() error {
+	if err := r.StopWriteBehind(); err != nil {
+		log.Printf("Failed to write queued requests before closing the database: %v", err)
+	}
 	return r.db.Close()
 }
 
 // saveRequest saves an API request to the database
 func (r *BoltDBAPIRequestRepository) saveRequest(req entity.APIRequest) error {
 	return r.db.Update(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket([]byte(requestsBucket))
+		dailyStats := newDailyStatsWriter(tx)
+		if err := r.putRequest(tx.Bucket([]byte(requestsBucket)), dailyStats, req); err != nil {
+			return err
+		}
+		return dailyStats.flush()
+	})
+}
 
-		// Use entity's ID method for key generation
-		key := req.ID()
+// putRequest writes an API request into the requests bucket of an open write transaction
+// The daily aggregates replace the stored request when it is written again, e.g. by a retried batch
+func (r *BoltDBAPIRequestRepository) putRequest(bucket *bbolt.Bucket, dailyStats *dailyStatsWriter, req entity.APIRequest) error {
+	// Use entity's ID method for key generation
+	key := req.ID()
 
-		// Convert entity to database schema
-		dbReq := r.convertFromEntity(req)
+	// Convert entity to database schema
+	dbReq := r.convertFromEntity(req)
 
-		// Serialize request to JSON
-		data, err := json.Marshal(dbReq)
-		if err != nil {
-			return fmt.Errorf("failed to serialize request: %w", err)
+	// Serialize request to JSON
+	data, err := json.Marshal(dbReq)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	if stored := bucket.Get([]byte(key)); stored != nil {
+		var previous schema.APIRequest
+		if err := json.Unmarshal(stored, &previous); err == nil {
+			if err := dailyStats.add(previous, -1); err != nil {
+				return err
+			}
 		}
+	}
+	if err := dailyStats.add(dbReq, 1); err != nil {
+		return err
+	}
 
-		return bucket.Put([]byte(key), data)
-	})
+	return bucket.Put([]byte(key), data)
 }
 
 // queryTimeRangeWithLimit queries requests within a time range with limit and offset
 // limit = 0 means no limit, offset = 0 means no offset
-func (r *BoltDBAPIRequestRepository) queryTimeRangeWithLimit(start, end time.Time, limit int, offset int) ([]schema.APIRequest, error) {
+// match skips the requests it returns false for before the limit is applied, nil keeps every request
+func (r *BoltDBAPIRequestRepository) queryTimeRangeWithLimit(start, end time.Time, limit int, offset int, match func(schema.APIRequest) bool) ([]schema.APIRequest, error) {
 	var requests []schema.APIRequest
 
-	err := r.db.View(func(tx *bbolt.Tx) error {
+	err := r.view(func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket([]byte(requestsBucket))
 		c := bucket.Cursor()
 
@@ -155,6 +441,9 @@ func (r *BoltDBAPIRequestRepository) queryTimeRangeWithLimit(start, end time.Tim
 					// Skip malformed entries
 					continue
 				}
+				if match != nil && !match(req) {
+					continue
+				}
 				requests = append(requests, req)
 			}
 			return nil
@@ -168,6 +457,9 @@ func (r *BoltDBAPIRequestRepository) queryTimeRangeWithLimit(start, end time.Tim
 				// Skip malformed entries
 				continue
 			}
+			if match != nil && !match(req) {
+				continue
+			}
 			allRequests = append(allRequests, req)
 		}
 
@@ -216,7 +508,7 @@ func (r *BoltDBAPIRequestRepository) getAllRequests() ([]schema.APIRequest, erro
 func (r *BoltDBAPIRequestRepository) getAllRequestsWithLimit(limit int, offset int) ([]schema.APIRequest, error) {
 	var requests []schema.APIRequest
 
-	err := r.db.View(func(tx *bbolt.Tx) error {
+	err := r.view(func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket([]byte(requestsBucket))
 		c := bucket.Cursor()
 
@@ -283,7 +575,7 @@ func (r *BoltDBAPIRequestRepository) convertToEntity(dbReq schema.APIRequest) en
 		dbReq.OutputTokens,
 		dbReq.CacheReadTokens,
 		dbReq.CacheCreationTokens,
-	)
+	).WithToolUse(dbReq.ToolUseTokens)
 	cost := entity.NewCost(dbReq.CostUSD)
 
 	return entity.NewAPIRequest(
@@ -293,7 +585,7 @@ func (r *BoltDBAPIRequestRepository) convertToEntity(dbReq schema.APIRequest) en
 		tokens,
 		cost,
 		dbReq.DurationMS,
-	)
+	).WithSource(dbReq.Source).WithOrigin(dbReq.Origin).WithUser(dbReq.User).WithProject(dbReq.Project)
 }
 
 // convertFromEntity converts an entity APIRequest to a database APIRequest
@@ -306,9 +598,14 @@ func (r *BoltDBAPIRequestRepository) convertFromEntity(e entity.APIRequest) sche
 		OutputTokens:        e.Tokens().Output(),
 		CacheReadTokens:     e.Tokens().CacheRead(),
 		CacheCreationTokens: e.Tokens().CacheCreation(),
+		ToolUseTokens:       e.Tokens().ToolUse(),
 		TotalTokens:         e.Tokens().Total(),
 		CostUSD:             e.Cost().Amount(),
 		DurationMS:          e.DurationMS(),
+		Source:              e.Source(),
+		Origin:              e.Origin(),
+		User:                e.User(),
+		Project:             e.Project(),
 	}
 }
 
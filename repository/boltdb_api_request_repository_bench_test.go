@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+	"go.etcd.io/bbolt"
+)
+
+// Benchmark dataset: 30 days with a request every 7.2 minutes
+const (
+	benchmarkDays           = 30
+	benchmarkRequestsPerDay = 200
+)
+
+// benchmarkStart is the first day of the benchmark dataset
+var benchmarkStart = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// setupBenchmarkRepository creates a repository filled with the benchmark dataset
+func setupBenchmarkRepository(tb testing.TB) *BoltDBAPIRequestRepository {
+	tb.Helper()
+
+	db, err := bbolt.Open(filepath.Join(tb.TempDir(), "bench.db"), 0600, nil)
+	if err != nil {
+		tb.Fatalf("Failed to open database: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			tb.Logf("Failed to close database: %v", err)
+		}
+	})
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		tb.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	interval := 24 * time.Hour / benchmarkRequestsPerDay
+	requests := make([]entity.APIRequest, 0, benchmarkDays*benchmarkRequestsPerDay)
+	for i := 0; i < benchmarkDays*benchmarkRequestsPerDay; i++ {
+		requests = append(requests, createTestEntity(fmt.Sprintf("session%d", i/50), benchmarkStart.Add(time.Duration(i)*interval)))
+	}
+	if err := repo.SaveBatch(requests); err != nil {
+		tb.Fatalf("Failed to save benchmark records: %v", err)
+	}
+
+	return repo
+}
+
+// benchmarkDailyPeriods returns the daily periods of the benchmark dataset
+func benchmarkDailyPeriods() []entity.Period {
+	periods := make([]entity.Period, 0, benchmarkDays)
+	for day := 0; day < benchmarkDays; day++ {
+		dayStart := benchmarkStart.AddDate(0, 0, day)
+		periods = append(periods, entity.NewPeriod(dayStart, dayStart.Add(24*time.Hour-time.Nanosecond)))
+	}
+	return periods
+}
+
+func BenchmarkBoltDBAPIRequestRepository_FindByPeriodWithLimit(b *testing.B) {
+	repo := setupBenchmarkRepository(b)
+	day := benchmarkDailyPeriods()[benchmarkDays/2]
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{name: "day", limit: 0},
+		{name: "day limit 100", limit: 100},
+	}
+
+	for _, tt := range tests {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := repo.FindByPeriodWithLimit(day, tt.limit, 0); err != nil {
+					b.Fatalf("FindByPeriodWithLimit failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBoltDBAPIRequestRepository_FindByPeriods(b *testing.B) {
+	repo := setupBenchmarkRepository(b)
+	periods := benchmarkDailyPeriods()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := repo.FindByPeriods(periods); err != nil {
+			b.Fatalf("FindByPeriods failed: %v", err)
+		}
+	}
+}
+
+func TestBoltDBAPIRequestRepository_AllocationBudget(t *testing.T) {
+	repo := setupBenchmarkRepository(t)
+	periods := benchmarkDailyPeriods()
+
+	// Decoding allocates per scanned record, budgets allow about 2 allocations per request
+	tests := []struct {
+		name   string
+		budget float64
+		scan   func() error
+	}{
+		{
+			name:   "day scan",
+			budget: 2 * benchmarkRequestsPerDay,
+			scan: func() error {
+				_, err := repo.FindByPeriodWithLimit(periods[benchmarkDays/2], 0, 0)
+				return err
+			},
+		},
+		{
+			name:   "month of daily periods",
+			budget: 2 * benchmarkDays * benchmarkRequestsPerDay,
+			scan: func() error {
+				_, err := repo.FindByPeriods(periods)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.CheckAllocationBudget(t, tt.budget, func() {
+				if err := tt.scan(); err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkBoltDBAPIRequestRepository_GetStatsByPeriod(b *testing.B) {
+	repo := setupBenchmarkRepository(b)
+	periods := benchmarkDailyPeriods()
+	if _, err := repo.BuildDailyStats(); err != nil {
+		b.Fatalf("BuildDailyStats failed: %v", err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, period := range periods {
+			if _, err := repo.GetStatsByPeriod(period); err != nil {
+				b.Fatalf("GetStatsByPeriod failed: %v", err)
+			}
+		}
+	}
+}
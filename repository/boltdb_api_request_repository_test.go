@@ -129,7 +129,7 @@ func TestBoltDBAPIRequestRepository_DeleteOlderThan(t *testing.T) {
 			}
 
 			// Execute DeleteOlderThan
-			deletedCount, err := repo.DeleteOlderThan(tt.cutoffTime)
+			deletedCount, err := repo.DeleteOlderThan(tt.cutoffTime, entity.Stars{})
 			if err != nil {
 				t.Fatalf("DeleteOlderThan() error = %v", err)
 			}
@@ -226,7 +226,7 @@ func TestBoltDBAPIRequestRepository_DeleteOlderThanWithLargeDataset(t *testing.T
 
 	// Delete records older than 500 hours from base time
 	cutoffTime := baseTime.Add(500 * time.Hour)
-	deletedCount, err := repo.DeleteOlderThan(cutoffTime)
+	deletedCount, err := repo.DeleteOlderThan(cutoffTime, entity.Stars{})
 	if err != nil {
 		t.Fatalf("DeleteOlderThan() error = %v", err)
 	}
@@ -284,7 +284,7 @@ func TestBoltDBAPIRequestRepository_DeleteOlderThanDatabaseError(t *testing.T) {
 
 	// Attempt to delete should return error
 	cutoffTime := time.Now().Add(-24 * time.Hour)
-	deletedCount, err := repo.DeleteOlderThan(cutoffTime)
+	deletedCount, err := repo.DeleteOlderThan(cutoffTime, entity.Stars{})
 
 	if err == nil {
 		t.Errorf("DeleteOlderThan() expected error when database is closed but got none")
@@ -322,3 +322,665 @@ func createTestEntity(sessionID string, timestamp time.Time) entity.APIRequest {
 	cost := entity.NewCost(0.001)
 	return entity.NewAPIRequest(sessionID, timestamp, "claude-3-sonnet", tokens, cost, 1000)
 }
+
+func TestBoltDBAPIRequestRepository_Source(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+
+	geminiReq := createTestEntity("gemini-session", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)).WithSource("gemini_cli")
+	if err := repo.Save(geminiReq); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Records saved before sources existed have no source field
+	legacy := repo.convertToEntity(createTestRecord("legacy-session", time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)))
+	if legacy.Source() != entity.SourceClaudeCode {
+		t.Errorf("legacy record Source() = %q, want %q", legacy.Source(), entity.SourceClaudeCode)
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("FindAll() returned %d requests, want 1", len(requests))
+	}
+	if requests[0].Source() != "gemini_cli" {
+		t.Errorf("Source() = %q, want %q", requests[0].Source(), "gemini_cli")
+	}
+}
+
+func TestBoltDBAPIRequestRepository_Origin(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+
+	importedReq := createTestEntity("imported-session", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)).WithOrigin(entity.OriginImport)
+	if err := repo.Save(importedReq); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Records saved before origins existed were received live
+	legacy := repo.convertToEntity(createTestRecord("legacy-session", time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)))
+	if legacy.Origin() != entity.OriginLive {
+		t.Errorf("legacy record Origin() = %q, want %q", legacy.Origin(), entity.OriginLive)
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("FindAll() returned %d requests, want 1", len(requests))
+	}
+	if requests[0].Origin() != entity.OriginImport {
+		t.Errorf("Origin() = %q, want %q", requests[0].Origin(), entity.OriginImport)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_ToolUseTokens(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+
+	tokens := entity.NewToken(100, 50, 0, 0).WithToolUse(30)
+	req := entity.NewAPIRequest("tool-session", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), "claude-sonnet-4-20250514", tokens, entity.NewCost(0.01), 1000)
+	if err := repo.Save(req); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	// Records saved before the split existed have no tool use tokens
+	legacy := repo.convertToEntity(createTestRecord("legacy-session", time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)))
+	if legacy.Tokens().ToolUse() != 0 {
+		t.Errorf("legacy record ToolUse() = %d, want 0", legacy.Tokens().ToolUse())
+	}
+
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("FindAll() returned %d requests, want 1", len(requests))
+	}
+	if requests[0].Tokens().ToolUse() != 30 {
+		t.Errorf("ToolUse() = %d, want 30", requests[0].Tokens().ToolUse())
+	}
+	if requests[0].Tokens().TextOutput() != 20 {
+		t.Errorf("TextOutput() = %d, want 20", requests[0].Tokens().TextOutput())
+	}
+}
+
+func TestBoltDBAPIRequestRepository_SaveBatch(t *testing.T) {
+	t.Parallel()
+
+	const batchSize = 50
+	baseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	requests := make([]entity.APIRequest, 0, batchSize)
+	for i := 0; i < batchSize; i++ {
+		requests = append(requests, createTestEntity(fmt.Sprintf("session-%d", i), baseTime.Add(time.Duration(i)*time.Second)))
+	}
+
+	tests := []struct {
+		name            string
+		save            func(repo *BoltDBAPIRequestRepository) error
+		expectedCommits int
+	}{
+		{
+			name: "individual saves commit once per request",
+			save: func(repo *BoltDBAPIRequestRepository) error {
+				for _, req := range requests {
+					if err := repo.Save(req); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expectedCommits: batchSize,
+		},
+		{
+			name: "batch save commits once per batch",
+			save: func(repo *BoltDBAPIRequestRepository) error {
+				return repo.SaveBatch(requests)
+			},
+			expectedCommits: 1,
+		},
+		{
+			name: "empty batch does not commit",
+			save: func(repo *BoltDBAPIRequestRepository) error {
+				return repo.SaveBatch(nil)
+			},
+			expectedCommits: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, err := bbolt.Open(createTempDB(t), 0600, nil)
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					t.Logf("Failed to close database: %v", err)
+				}
+			}()
+
+			err = db.Update(func(tx *bbolt.Tx) error {
+				_, err := tx.CreateBucket([]byte(requestsBucket))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to create bucket: %v", err)
+			}
+
+			repo := NewBoltDBAPIRequestRepository(db)
+
+			before := committedTxID(t, db)
+			if err := tt.save(repo); err != nil {
+				t.Fatalf("save failed: %v", err)
+			}
+			commits := committedTxID(t, db) - before
+
+			if commits != tt.expectedCommits {
+				t.Errorf("Expected %d commits, got %d", tt.expectedCommits, commits)
+			}
+
+			stored, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll() failed: %v", err)
+			}
+			expectedStored := batchSize
+			if tt.expectedCommits == 0 {
+				expectedStored = 0
+			}
+			if len(stored) != expectedStored {
+				t.Errorf("Expected %d stored requests, got %d", expectedStored, len(stored))
+			}
+		})
+	}
+}
+
+// committedTxID returns the ID of the last committed write transaction
+func committedTxID(t *testing.T, db *bbolt.DB) int {
+	t.Helper()
+
+	var id int
+	if err := db.View(func(tx *bbolt.Tx) error {
+		id = tx.ID()
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to read transaction ID: %v", err)
+	}
+	return id
+}
+
+func TestBoltDBAPIRequestRepository_FindByPeriods(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) entity.Period {
+		start := baseTime.AddDate(0, 0, offset)
+		return entity.NewPeriod(start, start.Add(24*time.Hour-time.Nanosecond))
+	}
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	if err := repo.SaveBatch([]entity.APIRequest{
+		createTestEntity("day-0-morning", baseTime.Add(9*time.Hour)),
+		createTestEntity("day-0-evening", baseTime.Add(21*time.Hour)),
+		createTestEntity("day-2", baseTime.AddDate(0, 0, 2).Add(12*time.Hour)),
+		createTestEntity("before-range", baseTime.AddDate(0, 0, -5)),
+		createTestEntity("after-range", baseTime.AddDate(0, 0, 10)),
+	}); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		periods []entity.Period
+	}{
+		{
+			name:    "no periods",
+			periods: nil,
+		},
+		{
+			name:    "consecutive days",
+			periods: []entity.Period{day(2), day(1), day(0)},
+		},
+		{
+			name:    "overlapping periods",
+			periods: []entity.Period{day(0), entity.NewPeriod(baseTime, baseTime.AddDate(0, 0, 3))},
+		},
+		{
+			name:    "includes all time period",
+			periods: []entity.Period{day(0), entity.NewAllTimePeriod(baseTime.AddDate(0, 0, 20))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestsByPeriod, err := repo.FindByPeriods(tt.periods)
+			if err != nil {
+				t.Fatalf("FindByPeriods() failed: %v", err)
+			}
+
+			if len(requestsByPeriod) != len(tt.periods) {
+				t.Fatalf("Expected %d results, got %d", len(tt.periods), len(requestsByPeriod))
+			}
+
+			// Each period must match the result of querying it separately
+			for i, period := range tt.periods {
+				expected, err := repo.FindByPeriodWithLimit(period, 0, 0)
+				if err != nil {
+					t.Fatalf("FindByPeriodWithLimit() failed: %v", err)
+				}
+
+				if len(requestsByPeriod[i]) != len(expected) {
+					t.Fatalf("Period %d: expected %d requests, got %d", i, len(expected), len(requestsByPeriod[i]))
+				}
+				for j := range expected {
+					if requestsByPeriod[i][j].ID() != expected[j].ID() {
+						t.Errorf("Period %d request %d: expected %s, got %s", i, j, expected[j].ID(), requestsByPeriod[i][j].ID())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_SampleByPeriod(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	var requests []entity.APIRequest
+	for i := 0; i < 100; i++ {
+		requests = append(requests, createTestEntity(fmt.Sprintf("session-%d", i), baseTime.Add(time.Duration(i)*time.Minute)))
+	}
+	requests = append(requests, createTestEntity("after-range", baseTime.AddDate(0, 0, 10)))
+	if err := repo.SaveBatch(requests); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	period := entity.NewPeriod(baseTime, baseTime.Add(2*time.Hour))
+
+	tests := []struct {
+		name           string
+		period         entity.Period
+		size           int
+		expectedTotal  int
+		expectedSample int
+	}{
+		{name: "sample smaller than period", period: period, size: 10, expectedTotal: 100, expectedSample: 10},
+		{name: "uneven step", period: period, size: 30, expectedTotal: 100, expectedSample: 25},
+		{name: "sample larger than period", period: period, size: 1000, expectedTotal: 100, expectedSample: 100},
+		{name: "all time", period: entity.NewAllTimePeriod(baseTime.AddDate(0, 0, 20)), size: 1000, expectedTotal: 101, expectedSample: 101},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample, total, err := repo.SampleByPeriod(tt.period, tt.size)
+			if err != nil {
+				t.Fatalf("SampleByPeriod() failed: %v", err)
+			}
+			if total != tt.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tt.expectedTotal, total)
+			}
+			if len(sample) != tt.expectedSample {
+				t.Errorf("Expected %d sampled requests, got %d", tt.expectedSample, len(sample))
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_FindByFilter(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	if err := repo.SaveBatch([]entity.APIRequest{
+		createTestEntity("session-a", baseTime.Add(1*time.Hour)),
+		createTestEntity("session-b", baseTime.Add(2*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(3*time.Hour)).WithSource("gemini_cli"),
+		createTestEntity("session-a", baseTime.Add(4*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(48*time.Hour)),
+	}); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	day := entity.NewPeriod(baseTime, baseTime.Add(24*time.Hour-time.Nanosecond))
+
+	tests := []struct {
+		name          string
+		filter        entity.Filter
+		limit         int
+		offset        int
+		expectedHours []int
+	}{
+		{
+			name:          "session in period",
+			filter:        entity.NewFilter(day).WithSessionID("session-a"),
+			expectedHours: []int{1, 3, 4},
+		},
+		{
+			name:          "session and source",
+			filter:        entity.NewFilter(day).WithSessionID("session-a").WithSource("gemini_cli"),
+			expectedHours: []int{3},
+		},
+		{
+			name:          "limit counts only matches",
+			filter:        entity.NewFilter(day).WithSessionID("session-a"),
+			limit:         2,
+			expectedHours: []int{3, 4},
+		},
+		{
+			name:          "offset counts only matches",
+			filter:        entity.NewFilter(day).WithSessionID("session-a"),
+			limit:         1,
+			offset:        2,
+			expectedHours: []int{1},
+		},
+		{
+			name:          "all time",
+			filter:        entity.NewFilter(entity.NewAllTimePeriod(baseTime.AddDate(0, 0, 5))).WithSessionID("session-a"),
+			expectedHours: []int{1, 3, 4, 48},
+		},
+		{
+			name:          "no match",
+			filter:        entity.NewFilter(day).WithModel("claude-opus-4-20250514"),
+			expectedHours: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := repo.FindByFilter(tt.filter, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("FindByFilter() failed: %v", err)
+			}
+
+			if len(requests) != len(tt.expectedHours) {
+				t.Fatalf("FindByFilter() returned %d requests, want %d", len(requests), len(tt.expectedHours))
+			}
+			for i, req := range requests {
+				expected := baseTime.Add(time.Duration(tt.expectedHours[i]) * time.Hour)
+				if !req.Timestamp().Equal(expected) {
+					t.Errorf("Request %d: Timestamp() = %v, want %v", i, req.Timestamp(), expected)
+				}
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_FindPageByFilter(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	if err := repo.SaveBatch([]entity.APIRequest{
+		createTestEntity("session-a", baseTime.Add(1*time.Hour)),
+		createTestEntity("session-b", baseTime.Add(2*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(3*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(4*time.Hour)),
+		createTestEntity("session-b", baseTime.Add(5*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(48*time.Hour)),
+	}); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	day := entity.NewPeriod(baseTime, baseTime.Add(24*time.Hour-time.Nanosecond))
+	allTime := entity.NewAllTimePeriod(baseTime.AddDate(0, 0, 5))
+
+	tests := []struct {
+		name          string
+		filter        entity.Filter
+		limit         int
+		expectedPages [][]int
+	}{
+		{
+			name:          "period",
+			filter:        entity.NewFilter(day),
+			limit:         2,
+			expectedPages: [][]int{{4, 5}, {2, 3}, {1}},
+		},
+		{
+			name:          "limit counts only matches",
+			filter:        entity.NewFilter(allTime).WithSessionID("session-a"),
+			limit:         2,
+			expectedPages: [][]int{{4, 48}, {1, 3}},
+		},
+		{
+			name:          "no limit",
+			filter:        entity.NewFilter(day).WithSessionID("session-b"),
+			expectedPages: [][]int{{2, 5}},
+		},
+		{
+			name:          "no match",
+			filter:        entity.NewFilter(day).WithModel("claude-opus-4-20250514"),
+			limit:         2,
+			expectedPages: [][]int{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cursor string
+			for page, expectedHours := range tt.expectedPages {
+				requests, next, err := repo.FindPageByFilter(tt.filter, tt.limit, cursor)
+				if err != nil {
+					t.Fatalf("FindPageByFilter() page %d failed: %v", page, err)
+				}
+
+				if len(requests) != len(expectedHours) {
+					t.Fatalf("FindPageByFilter() page %d returned %d requests, want %d", page, len(requests), len(expectedHours))
+				}
+				for i, req := range requests {
+					expected := baseTime.Add(time.Duration(expectedHours[i]) * time.Hour)
+					if !req.Timestamp().Equal(expected) {
+						t.Errorf("Page %d request %d: Timestamp() = %v, want %v", page, i, req.Timestamp(), expected)
+					}
+				}
+
+				lastPage := page == len(tt.expectedPages)-1
+				if lastPage != (next == "") {
+					t.Fatalf("FindPageByFilter() page %d returned cursor %q, last page %v", page, next, lastPage)
+				}
+				cursor = next
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_CountByFilter(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	if err := repo.SaveBatch([]entity.APIRequest{
+		createTestEntity("session-a", baseTime.Add(1*time.Hour)),
+		createTestEntity("session-b", baseTime.Add(2*time.Hour)),
+		createTestEntity("session-a", baseTime.Add(3*time.Hour)).WithSource("gemini_cli"),
+		createTestEntity("session-a", baseTime.Add(48*time.Hour)),
+	}); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	day := entity.NewPeriod(baseTime, baseTime.Add(24*time.Hour-time.Nanosecond))
+	allTime := entity.NewAllTimePeriod(baseTime.AddDate(0, 0, 5))
+
+	tests := []struct {
+		name     string
+		filter   entity.Filter
+		expected int
+	}{
+		{name: "period only", filter: entity.NewFilter(day), expected: 3},
+		{name: "session in period", filter: entity.NewFilter(day).WithSessionID("session-a"), expected: 2},
+		{name: "session and source", filter: entity.NewFilter(day).WithSessionID("session-a").WithSource("gemini_cli"), expected: 1},
+		{name: "all time", filter: entity.NewFilter(allTime), expected: 4},
+		{name: "all time session", filter: entity.NewFilter(allTime).WithSessionID("session-a"), expected: 3},
+		{name: "no match", filter: entity.NewFilter(day).WithModel("claude-opus-4-20250514"), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := repo.CountByFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("CountByFilter() failed: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("CountByFilter() = %d, want %d", count, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/repository/schema"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	dailyStatsBucket = "daily_stats"
+
+	// dailyStatsVersionKey holds the version the aggregates were calculated with, it sorts after every day key
+	dailyStatsVersionKey = "version"
+	// dailyStatsVersion changes when the aggregates are calculated differently, e.g. a model moves to another tier
+	dailyStatsVersion = "2"
+
+	// dailyStatsDayFormat is the key of each UTC day in the daily stats bucket
+	dailyStatsDayFormat = "2006-01-02"
+)
+
+// BuildDailyStats builds the daily aggregates from the stored requests when they are missing or outdated
+// Databases predating the aggregates are scanned once, returns true if the aggregates were built
+func (r *BoltDBAPIRequestRepository) BuildDailyStats() (bool, error) {
+	var built bool
+	err := r.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		built, err = ensureDailyStats(tx)
+		return err
+	})
+	return built, err
+}
+
+// GetStatsByPeriod retrieves the statistics of the period from the daily aggregates
+// The whole hours of the period are summed from the aggregates and only the partial hours at its edges are scanned,
+// the period is scanned completely when the database has no aggregates, e.g. opened read-only before a server built them
+func (r *BoltDBAPIRequestRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	var totals schema.HourStats
+
+	err := r.view(func(tx *bbolt.Tx) error {
+		start, end := period.StartAt().UTC(), period.EndAt().UTC()
+
+		bucket := tx.Bucket([]byte(dailyStatsBucket))
+		if bucket == nil || string(bucket.Get([]byte(dailyStatsVersionKey))) != dailyStatsVersion {
+			return r.scanStats(tx, start, end, &totals)
+		}
+
+		// Whole hours start at the first hour boundary within the period and end at the last one
+		firstHour := start.Truncate(time.Hour)
+		if firstHour.Before(start) {
+			firstHour = firstHour.Add(time.Hour)
+		}
+		lastHour := end.Add(time.Nanosecond).Truncate(time.Hour)
+		if !firstHour.Before(lastHour) {
+			return r.scanStats(tx, start, end, &totals)
+		}
+
+		if start.Before(firstHour) {
+			if err := r.scanStats(tx, start, firstHour.Add(-time.Nanosecond), &totals); err != nil {
+				return err
+			}
+		}
+		if err := sumDailyStats(bucket, firstHour, lastHour, &totals); err != nil {
+			return err
+		}
+		if !end.Before(lastHour) {
+			return r.scanStats(tx, lastHour, end, &totals)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Stats{}, err
+	}
+
+	models := make([]entity.ModelStats, 0, len(totals.Models))
+	for model, tier := range totals.Models {
+		models = append(models, entity.NewModelStats(model, int(tier.Requests), tierTokens(tier), entity.NewCost(tier.CostUSD)))
+	}
+
+	return entity.NewStats(
+		int(totals.Base.Requests),
+		int(totals.Premium.Requests),
+		tierTokens(totals.Base),
+		tierTokens(totals.Premium),
+		entity.NewCost(totals.Base.CostUSD),
+		entity.NewCost(totals.Premium.CostUSD),
+		period,
+	).WithLongContext(int(totals.LongContext.Requests), tierTokens(totals.LongContext), entity.NewCost(totals.LongContext.CostUSD)).WithModels(models), nil
+}
+
+// scanStats adds the requests stored between start and end, both included, to the totals
+func (r *BoltDBAPIRequestRepository) scanStats(tx *bbolt.Tx, start, end time.Time, totals *schema.HourStats) error {
+	c := tx.Bucket([]byte(requestsBucket)).Cursor()
+
+	startKey := []byte(start.Format(time.RFC3339Nano))
+	endKey := end.Format(time.RFC3339Nano) + "\xff"
+	for k, v := c.Seek(startKey); k != nil && string(k) < endKey; k, v = c.Next() {
+		var req schema.APIRequest
+		if err := json.Unmarshal(v, &req); err != nil {
+			// Skip malformed entries
+			continue
+		}
+		addRequestStats(totals, req, 1)
+	}
+	return nil
+}
+
+// sumDailyStats adds the aggregated hours starting from firstHour and before lastHour to the totals
+func sumDailyStats(bucket *bbolt.Bucket, firstHour, lastHour time.Time, totals *schema.HourStats) error {
+	c := bucket.Cursor()
+
+	endKey := lastHour.Add(-time.Nanosecond).Format(dailyStatsDayFormat)
+	for k, v := c.Seek([]byte(firstHour.Format(dailyStatsDayFormat))); k != nil && string(k) <= endKey; k, v = c.Next() {
+		day, err := time.Parse(dailyStatsDayFormat, string(k))
+		if err != nil {
+			continue
+		}
+
+		var stats schema.DailyStats
+		if err := json.Unmarshal(v, &stats); err != nil {
+			return fmt.Errorf("invalid daily stats of %s: %w", k, err)
+		}
+		for hour, hourStats := range stats.Hours {
+			hourStart := day.Add(time.Duration(hour) * time.Hour)
+			if hourStart.Before(firstHour) || !hourStart.Before(lastHour) {
+				continue
+			}
+			addHourStats(totals, hourStats)
+		}
+	}
+	return nil
+}
+
+// ensureDailyStats builds the daily aggregates from the requests bucket when they are missing or outdated
+func ensureDailyStats(tx *bbolt.Tx) (bool, error) {
+	bucket := tx.Bucket([]byte(dailyStatsBucket))
+	if bucket != nil && string(bucket.Get([]byte(dailyStatsVersionKey))) == dailyStatsVersion {
+		return false, nil
+	}
+
+	if bucket != nil {
+		if err := tx.DeleteBucket([]byte(dailyStatsBucket)); err != nil {
+			return false, fmt.Errorf("failed to clear daily stats: %w", err)
+		}
+	}
+	bucket, err := tx.CreateBucket([]byte(dailyStatsBucket))
+	if err != nil {
+		return false, fmt.Errorf("failed to create daily stats bucket: %w", err)
+	}
+
+	writer := newDailyStatsWriter(tx)
+	if requests := tx.Bucket([]byte(requestsBucket)); requests != nil {
+		err := requests.ForEach(func(k, v []byte) error {
+			var req schema.APIRequest
+			if err := json.Unmarshal(v, &req); err != nil {
+				// Skip malformed entries
+				return nil
+			}
+			return writer.add(req, 1)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+	if err := writer.flush(); err != nil {
+		return false, err
+	}
+
+	if err := bucket.Put([]byte(dailyStatsVersionKey), []byte(dailyStatsVersion)); err != nil {
+		return false, fmt.Errorf("failed to write daily stats version: %w", err)
+	}
+	return true, nil
+}
+
+// dailyStatsWriter keeps the daily aggregates up to date with the requests written in a transaction
+// Each day is decoded once and written back by flush, nothing is written when the database has no aggregates
+type dailyStatsWriter struct {
+	bucket *bbolt.Bucket
+	days   map[string]*schema.DailyStats
+}
+
+// newDailyStatsWriter creates a writer for the daily aggregates of an open write transaction
+func newDailyStatsWriter(tx *bbolt.Tx) *dailyStatsWriter {
+	return &dailyStatsWriter{
+		bucket: tx.Bucket([]byte(dailyStatsBucket)),
+		days:   make(map[string]*schema.DailyStats),
+	}
+}
+
+// add counts the request in the aggregates of its hour, a sign of -1 removes a stored request
+func (w *dailyStatsWriter) add(req schema.APIRequest, sign int64) error {
+	if w.bucket == nil {
+		return nil
+	}
+
+	at := req.Timestamp.UTC()
+	key := at.Format(dailyStatsDayFormat)
+	day, ok := w.days[key]
+	if !ok {
+		day = &schema.DailyStats{}
+		if data := w.bucket.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, day); err != nil {
+				return fmt.Errorf("invalid daily stats of %s: %w", key, err)
+			}
+		}
+		if day.Hours == nil {
+			day.Hours = make(map[int]schema.HourStats)
+		}
+		w.days[key] = day
+	}
+
+	hour := day.Hours[at.Hour()]
+	addRequestStats(&hour, req, sign)
+	// Hours without requests are dropped, so removed costs leave no rounding residue behind
+	if hour.Base.Requests == 0 && hour.Premium.Requests == 0 && hour.LongContext.Requests == 0 {
+		delete(day.Hours, at.Hour())
+	} else {
+		day.Hours[at.Hour()] = hour
+	}
+	return nil
+}
+
+// flush writes the changed days, days without requests are deleted
+func (w *dailyStatsWriter) flush() error {
+	if w.bucket == nil {
+		return nil
+	}
+
+	for key, day := range w.days {
+		if len(day.Hours) == 0 {
+			if err := w.bucket.Delete([]byte(key)); err != nil {
+				return fmt.Errorf("failed to delete daily stats of %s: %w", key, err)
+			}
+			continue
+		}
+
+		data, err := json.Marshal(day)
+		if err != nil {
+			return fmt.Errorf("failed to serialize daily stats of %s: %w", key, err)
+		}
+		if err := w.bucket.Put([]byte(key), data); err != nil {
+			return fmt.Errorf("failed to write daily stats of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// addRequestStats adds the request to the aggregates of its model tier and its model, a sign of -1 removes it
+func addRequestStats(hour *schema.HourStats, req schema.APIRequest, sign int64) {
+	addTierStats(hourTier(hour, req.Model), req, sign)
+
+	if hour.Models == nil {
+		hour.Models = make(map[string]schema.TierStats)
+	}
+	model := hour.Models[req.Model]
+	addTierStats(&model, req, sign)
+	if model.Requests == 0 {
+		delete(hour.Models, req.Model)
+	} else {
+		hour.Models[req.Model] = model
+	}
+}
+
+// hourTier returns the aggregates of the model tier the model belongs to, the same tiers as entity.NewStatsFromRequests
+func hourTier(hour *schema.HourStats, model string) *schema.TierStats {
+	m := entity.NewModel(model)
+	switch {
+	case m.IsLongContext():
+		return &hour.LongContext
+	case m.IsBase():
+		return &hour.Base
+	default:
+		return &hour.Premium
+	}
+}
+
+// addTierStats adds the request to the tier aggregates, a sign of -1 removes it
+func addTierStats(tier *schema.TierStats, req schema.APIRequest, sign int64) {
+	tier.Requests += sign
+	tier.InputTokens += sign * req.InputTokens
+	tier.OutputTokens += sign * req.OutputTokens
+	tier.CacheReadTokens += sign * req.CacheReadTokens
+	tier.CacheCreationTokens += sign * req.CacheCreationTokens
+	tier.ToolUseTokens += sign * req.ToolUseTokens
+	tier.CostUSD += float64(sign) * req.CostUSD
+
+	if tier.Requests == 0 {
+		*tier = schema.TierStats{}
+	}
+}
+
+// addHourStats adds the aggregates of an hour to the totals
+func addHourStats(totals *schema.HourStats, hour schema.HourStats) {
+	sumTierStats(&totals.Base, hour.Base)
+	sumTierStats(&totals.Premium, hour.Premium)
+	sumTierStats(&totals.LongContext, hour.LongContext)
+
+	if totals.Models == nil {
+		totals.Models = make(map[string]schema.TierStats)
+	}
+	for name, stats := range hour.Models {
+		model := totals.Models[name]
+		sumTierStats(&model, stats)
+		totals.Models[name] = model
+	}
+}
+
+// sumTierStats adds the tier aggregates to the totals
+func sumTierStats(totals *schema.TierStats, tier schema.TierStats) {
+	totals.Requests += tier.Requests
+	totals.InputTokens += tier.InputTokens
+	totals.OutputTokens += tier.OutputTokens
+	totals.CacheReadTokens += tier.CacheReadTokens
+	totals.CacheCreationTokens += tier.CacheCreationTokens
+	totals.ToolUseTokens += tier.ToolUseTokens
+	totals.CostUSD += tier.CostUSD
+}
+
+// tierTokens converts the token counts of tier aggregates
+func tierTokens(tier schema.TierStats) entity.Token {
+	return entity.NewToken(tier.InputTokens, tier.OutputTokens, tier.CacheReadTokens, tier.CacheCreationTokens).WithToolUse(tier.ToolUseTokens)
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+// createDailyStatsTestRequests returns requests of every tier spread over three days, including partial hours
+func createDailyStatsTestRequests() []entity.APIRequest {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	models := []string{"claude-3-haiku", "claude-sonnet-4", "claude-sonnet-4[1m]", "claude-opus-4"}
+
+	var requests []entity.APIRequest
+	for i := 0; i < 3*24*4; i++ {
+		tokens := entity.NewToken(int64(100+i), int64(50+i), int64(i), int64(2*i)).WithToolUse(int64(i % 7))
+		cost := entity.NewCost(0.001 * float64(i%5+1))
+		// Every 15 minutes with an odd offset, so hours are split by non-aligned periods
+		at := start.Add(time.Duration(i)*15*time.Minute + 7*time.Minute + 13*time.Second)
+		requests = append(requests, entity.NewAPIRequest("session", at, models[i%len(models)], tokens, cost, 1000))
+	}
+	return requests
+}
+
+// assertStatsEqual compares the stats calculated from the aggregates with the stats calculated from the requests
+func assertStatsEqual(t *testing.T, got, want entity.Stats) {
+	t.Helper()
+
+	if got.BaseRequests() != want.BaseRequests() || got.PremiumRequests() != want.PremiumRequests() || got.LongContextRequests() != want.LongContextRequests() {
+		t.Errorf("Requests = %d/%d/%d, want %d/%d/%d",
+			got.BaseRequests(), got.PremiumRequests(), got.LongContextRequests(),
+			want.BaseRequests(), want.PremiumRequests(), want.LongContextRequests())
+	}
+	for _, tier := range []struct {
+		name      string
+		got, want entity.Token
+	}{
+		{"base", got.BaseTokens(), want.BaseTokens()},
+		{"premium", got.PremiumTokens(), want.PremiumTokens()},
+		{"long context", got.LongContextTokens(), want.LongContextTokens()},
+	} {
+		if tier.got != tier.want {
+			t.Errorf("%s tokens = %+v, want %+v", tier.name, tier.got, tier.want)
+		}
+	}
+	// Costs are summed in a different order, so they may differ by rounding
+	for _, tier := range []struct {
+		name      string
+		got, want entity.Cost
+	}{
+		{"base", got.BaseCost(), want.BaseCost()},
+		{"premium", got.PremiumCost(), want.PremiumCost()},
+		{"long context", got.LongContextCost(), want.LongContextCost()},
+	} {
+		if math.Abs(tier.got.Amount()-tier.want.Amount()) > 1e-9 {
+			t.Errorf("%s cost = %f, want %f", tier.name, tier.got.Amount(), tier.want.Amount())
+		}
+	}
+	if got.Period() != want.Period() {
+		t.Errorf("Period = %v, want %v", got.Period(), want.Period())
+	}
+
+	if len(got.Models()) != len(want.Models()) {
+		t.Fatalf("Models = %d, want %d", len(got.Models()), len(want.Models()))
+	}
+	for i, model := range want.Models() {
+		gotModel := got.Models()[i]
+		if gotModel.Model() != model.Model() || gotModel.Requests() != model.Requests() || gotModel.Tokens() != model.Tokens() {
+			t.Errorf("Model %d = %s with %d requests and %+v, want %s with %d requests and %+v",
+				i, gotModel.Model(), gotModel.Requests(), gotModel.Tokens(), model.Model(), model.Requests(), model.Tokens())
+		}
+		if math.Abs(gotModel.Cost().Amount()-model.Cost().Amount()) > 1e-9 {
+			t.Errorf("Model %s cost = %f, want %f", model.Model(), gotModel.Cost().Amount(), model.Cost().Amount())
+		}
+	}
+}
+
+// scannedStats calculates the stats of the period from the stored requests
+func scannedStats(t *testing.T, repo *BoltDBAPIRequestRepository, period entity.Period) entity.Stats {
+	t.Helper()
+
+	requests, err := repo.FindByPeriodWithLimit(period, 0, 0)
+	if err != nil {
+		t.Fatalf("FindByPeriodWithLimit() failed: %v", err)
+	}
+	return entity.NewStatsFromRequests(requests, period).WithModels(entity.NewModelStatsFromRequests(requests))
+}
+
+// dailyStatsDays returns the days kept in the daily stats bucket
+func dailyStatsDays(t *testing.T, db *bbolt.DB) []string {
+	t.Helper()
+
+	var days []string
+	err := db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(dailyStatsBucket))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if string(k) != dailyStatsVersionKey {
+				days = append(days, string(k))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to read daily stats: %v", err)
+	}
+	return days
+}
+
+func TestBoltDBAPIRequestRepository_GetStatsByPeriod(t *testing.T) {
+	t.Parallel()
+
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	kathmandu := time.FixedZone("Asia/Kathmandu", 5*60*60+45*60)
+	day := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		period entity.Period
+	}{
+		{name: "utc day", period: entity.NewPeriod(day, day.Add(24*time.Hour-time.Nanosecond))},
+		{name: "timezone day", period: entity.NewDayPeriod(time.Date(2025, 3, 2, 12, 0, 0, 0, taipei), taipei)},
+		{name: "quarter hour timezone day", period: entity.NewDayPeriod(time.Date(2025, 3, 2, 12, 0, 0, 0, kathmandu), kathmandu)},
+		{name: "hour", period: entity.NewPeriod(day.Add(5*time.Hour), day.Add(6*time.Hour-time.Nanosecond))},
+		{name: "within an hour", period: entity.NewPeriod(day.Add(5*time.Hour+10*time.Minute), day.Add(5*time.Hour+40*time.Minute))},
+		{name: "partial hours at both edges", period: entity.NewPeriod(day.Add(-90*time.Minute), day.Add(26*time.Hour+20*time.Minute))},
+		{name: "all time", period: entity.NewAllTimePeriod(time.Date(2025, 3, 4, 0, 30, 0, 0, time.UTC))},
+		{name: "no requests", period: entity.NewPeriod(day.AddDate(1, 0, 0), day.AddDate(1, 0, 1))},
+	}
+
+	db, repo := openSnapshotTestRepository(t, createDailyStatsTestRequests()...)
+	built, err := repo.BuildDailyStats()
+	if err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+	if !built {
+		t.Fatal("Expected the daily stats of an existing database to be built")
+	}
+	if days := dailyStatsDays(t, db); len(days) != 3 {
+		t.Fatalf("Expected daily stats of 3 days, got %v", days)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetStatsByPeriod(tt.period)
+			if err != nil {
+				t.Fatalf("GetStatsByPeriod() failed: %v", err)
+			}
+			assertStatsEqual(t, got, scannedStats(t, repo, tt.period))
+		})
+	}
+
+	// Built aggregates are kept on the next startup
+	if built, err := repo.BuildDailyStats(); err != nil || built {
+		t.Errorf("BuildDailyStats() = %v, %v, want the existing aggregates to be kept", built, err)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_DailyStatsUpdates(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openSnapshotTestRepository(t)
+	if _, err := repo.BuildDailyStats(); err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+
+	requests := createDailyStatsTestRequests()
+	if err := repo.SaveBatch(requests[:100]); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+	for _, req := range requests[100:] {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	// Retried requests replace the stored ones instead of being counted twice
+	if err := repo.SaveBatch(requests[:10]); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+	updated := requests[20]
+	updated = entity.NewAPIRequest(updated.SessionID(), updated.Timestamp(), "claude-3-haiku", entity.NewToken(1, 1, 0, 0), entity.NewCost(0.5), 1000)
+	if err := repo.Save(updated); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	allTime := entity.NewAllTimePeriod(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC))
+	got, err := repo.GetStatsByPeriod(allTime)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	want := scannedStats(t, repo, allTime)
+	if want.TotalRequests() != len(requests) {
+		t.Fatalf("Expected %d stored requests, got %d", len(requests), want.TotalRequests())
+	}
+	assertStatsEqual(t, got, want)
+
+	// Cleaned up days are removed from the aggregates
+	if _, err := repo.DeleteOlderThan(time.Date(2025, 3, 2, 6, 0, 0, 0, time.UTC), entity.Stars{}); err != nil {
+		t.Fatalf("DeleteOlderThan() failed: %v", err)
+	}
+	got, err = repo.GetStatsByPeriod(allTime)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	assertStatsEqual(t, got, scannedStats(t, repo, allTime))
+	if days := dailyStatsDays(t, db); len(days) != 2 || days[0] != "2025-03-02" {
+		t.Errorf("Expected daily stats from 2025-03-02, got %v", days)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_DailyStatsOutdated(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openSnapshotTestRepository(t, createDailyStatsTestRequests()...)
+	if _, err := repo.BuildDailyStats(); err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+
+	// Aggregates calculated by another version are ignored until they are rebuilt
+	err := db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(dailyStatsBucket)).Put([]byte(dailyStatsVersionKey), []byte("0"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to change the daily stats version: %v", err)
+	}
+
+	period := entity.NewPeriod(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))
+	got, err := repo.GetStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	assertStatsEqual(t, got, scannedStats(t, repo, period))
+
+	built, err := repo.BuildDailyStats()
+	if err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+	if !built {
+		t.Error("Expected outdated daily stats to be rebuilt")
+	}
+	got, err = repo.GetStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	assertStatsEqual(t, got, scannedStats(t, repo, period))
+}
+
+func TestBoltDBSnapshotRepository_RestoreSnapshotDailyStats(t *testing.T) {
+	t.Parallel()
+
+	requests := createDailyStatsTestRequests()
+
+	// The primary predates the aggregates, the replica has aggregates of its stale data
+	primaryDB, _ := openSnapshotTestRepository(t, requests[:50]...)
+	replicaDB, replicaRepo := openSnapshotTestRepository(t, requests[50:]...)
+	if _, err := replicaRepo.BuildDailyStats(); err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+
+	var snapshot bytes.Buffer
+	if _, err := NewBoltDBSnapshotRepository(primaryDB).WriteSnapshot(context.Background(), &snapshot); err != nil {
+		t.Fatalf("WriteSnapshot() failed: %v", err)
+	}
+	if err := NewBoltDBSnapshotRepository(replicaDB).RestoreSnapshot(&snapshot); err != nil {
+		t.Fatalf("RestoreSnapshot() failed: %v", err)
+	}
+
+	allTime := entity.NewAllTimePeriod(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC))
+	got, err := replicaRepo.GetStatsByPeriod(allTime)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	if got.TotalRequests() != 50 {
+		t.Errorf("Expected the stats of the 50 restored requests, got %d", got.TotalRequests())
+	}
+	assertStatsEqual(t, got, scannedStats(t, replicaRepo, allTime))
+}
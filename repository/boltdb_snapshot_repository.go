@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// BoltDBSnapshotRepository implements usecase.SnapshotRepository and usecase.SnapshotRestoreRepository using BoltDB
+type BoltDBSnapshotRepository struct {
+	db *bbolt.DB
+}
+
+// NewBoltDBSnapshotRepository creates a new BoltDB snapshot repository instance
+func NewBoltDBSnapshotRepository(db *bbolt.DB) *BoltDBSnapshotRepository {
+	return &BoltDBSnapshotRepository{
+		db: db,
+	}
+}
+
+// WriteSnapshot writes the database inside a read transaction, so the copy is consistent while writes continue
+func (r *BoltDBSnapshotRepository) WriteSnapshot(ctx context.Context, w io.Writer) (int64, error) {
+	var size int64
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		size, err = tx.WriteTo(&contextWriter{ctx: ctx, w: w})
+		return err
+	})
+	if err != nil {
+		return size, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return size, nil
+}
+
+// RestoreSnapshot replaces every bucket found in the snapshot within a single write transaction
+// The daily aggregates are rebuilt when the snapshot has none, e.g. when the primary predates them
+func (r *BoltDBSnapshotRepository) RestoreSnapshot(reader io.Reader) error {
+	// Keep the received snapshot next to the database instead of a possibly small tmpfs
+	file, err := os.CreateTemp(filepath.Dir(r.db.Path()), "ccmon-snapshot-*.db")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			log.Printf("Error removing snapshot file: %v", err)
+		}
+	}()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to receive snapshot: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	snapshot, err := bbolt.Open(file.Name(), 0600, &bbolt.Options{ReadOnly: true, Timeout: 1 * time.Second})
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer func() {
+		if err := snapshot.Close(); err != nil {
+			log.Printf("Error closing snapshot: %v", err)
+		}
+	}()
+
+	return snapshot.View(func(src *bbolt.Tx) error {
+		return r.db.Update(func(dst *bbolt.Tx) error {
+			err := src.ForEach(func(name []byte, srcBucket *bbolt.Bucket) error {
+				if dst.Bucket(name) != nil {
+					if err := dst.DeleteBucket(name); err != nil {
+						return fmt.Errorf("failed to clear bucket %s: %w", name, err)
+					}
+				}
+
+				dstBucket, err := dst.CreateBucket(name)
+				if err != nil {
+					return fmt.Errorf("failed to create bucket %s: %w", name, err)
+				}
+
+				return srcBucket.ForEach(func(k, v []byte) error {
+					// Nested buckets are not used by the schema
+					if v == nil {
+						return nil
+					}
+					return dstBucket.Put(k, v)
+				})
+			})
+			if err != nil {
+				return err
+			}
+
+			// Aggregates left from before the restore no longer match the restored requests
+			if src.Bucket([]byte(dailyStatsBucket)) == nil && dst.Bucket([]byte(dailyStatsBucket)) != nil {
+				if err := dst.DeleteBucket([]byte(dailyStatsBucket)); err != nil {
+					return fmt.Errorf("failed to clear daily stats: %w", err)
+				}
+			}
+			_, err = ensureDailyStats(dst)
+			return err
+		})
+	})
+}
+
+// contextWriter stops a long running snapshot write when the context is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+// openSnapshotTestRepository opens a database with the requests bucket and saves the given requests
+func openSnapshotTestRepository(t *testing.T, requests ...entity.APIRequest) (*bbolt.DB, *BoltDBAPIRequestRepository) {
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	})
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	for _, req := range requests {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	return db, repo
+}
+
+func TestBoltDBSnapshotRepository_RestoreSnapshot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		primaryRequests  []entity.APIRequest
+		replicaRequests  []entity.APIRequest
+		expectedSessions []string
+	}{
+		{
+			name: "restore into empty replica",
+			primaryRequests: []entity.APIRequest{
+				createTestEntity("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+				createTestEntity("session2", time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedSessions: []string{"session1", "session2"},
+		},
+		{
+			name: "restore replaces stale replica data",
+			primaryRequests: []entity.APIRequest{
+				createTestEntity("session3", time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)),
+			},
+			replicaRequests: []entity.APIRequest{
+				createTestEntity("deleted-on-primary", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+			},
+			expectedSessions: []string{"session3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			primaryDB, _ := openSnapshotTestRepository(t, tt.primaryRequests...)
+			replicaDB, replicaRepo := openSnapshotTestRepository(t, tt.replicaRequests...)
+
+			var snapshot bytes.Buffer
+			size, err := NewBoltDBSnapshotRepository(primaryDB).WriteSnapshot(context.Background(), &snapshot)
+			if err != nil {
+				t.Fatalf("WriteSnapshot() failed: %v", err)
+			}
+			if size != int64(snapshot.Len()) {
+				t.Errorf("WriteSnapshot() size = %d, want %d", size, snapshot.Len())
+			}
+
+			if err := NewBoltDBSnapshotRepository(replicaDB).RestoreSnapshot(&snapshot); err != nil {
+				t.Fatalf("RestoreSnapshot() failed: %v", err)
+			}
+
+			requests, err := replicaRepo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll() failed: %v", err)
+			}
+			if len(requests) != len(tt.expectedSessions) {
+				t.Fatalf("FindAll() returned %d requests, want %d", len(requests), len(tt.expectedSessions))
+			}
+
+			found := make(map[string]bool)
+			for _, req := range requests {
+				found[req.SessionID()] = true
+			}
+			for _, sessionID := range tt.expectedSessions {
+				if !found[sessionID] {
+					t.Errorf("Expected session %s after restore", sessionID)
+				}
+			}
+		})
+	}
+}
+
+func TestBoltDBSnapshotRepository_RestoreInvalidSnapshot(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openSnapshotTestRepository(t, createTestEntity("session1", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)))
+
+	err := NewBoltDBSnapshotRepository(db).RestoreSnapshot(bytes.NewReader([]byte("not a database")))
+	if err == nil {
+		t.Fatal("RestoreSnapshot() expected error for invalid snapshot")
+	}
+
+	// Existing data is kept when the snapshot cannot be opened
+	requests, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("FindAll() returned %d requests, want 1", len(requests))
+	}
+}
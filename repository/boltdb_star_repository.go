@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	starsBucket = "stars"
+
+	// Star keys are prefixed by their scope, so request and session IDs share the bucket
+	starRequestPrefix = "request:"
+	starSessionPrefix = "session:"
+)
+
+// BoltDBStarRepository implements usecase.StarRepository using BoltDB
+type BoltDBStarRepository struct {
+	db *bbolt.DB
+}
+
+// NewBoltDBStarRepository creates a new BoltDB star repository instance
+func NewBoltDBStarRepository(db *bbolt.DB) *BoltDBStarRepository {
+	return &BoltDBStarRepository{
+		db: db,
+	}
+}
+
+// GetStars retrieves all starred requests and sessions
+// Databases created before stars existed have no stars bucket and nothing starred
+func (r *BoltDBStarRepository) GetStars() (entity.Stars, error) {
+	var requestIDs, sessionIDs []string
+
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(starsBucket))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			key := string(k)
+			switch {
+			case strings.HasPrefix(key, starRequestPrefix):
+				requestIDs = append(requestIDs, strings.TrimPrefix(key, starRequestPrefix))
+			case strings.HasPrefix(key, starSessionPrefix):
+				sessionIDs = append(sessionIDs, strings.TrimPrefix(key, starSessionPrefix))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return entity.Stars{}, fmt.Errorf("failed to read stars: %w", err)
+	}
+
+	return entity.NewStars(requestIDs, sessionIDs), nil
+}
+
+// SetStar stars the request or its whole session, StarNone removes both stars
+func (r *BoltDBStarRepository) SetStar(requestID string, sessionID string, scope entity.StarScope) error {
+	return r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(starsBucket))
+		if err != nil {
+			return fmt.Errorf("failed to create stars bucket: %w", err)
+		}
+
+		requestKey := []byte(starRequestPrefix + requestID)
+		sessionKey := []byte(starSessionPrefix + sessionID)
+		if err := bucket.Delete(requestKey); err != nil {
+			return fmt.Errorf("failed to unstar request %s: %w", requestID, err)
+		}
+		if err := bucket.Delete(sessionKey); err != nil {
+			return fmt.Errorf("failed to unstar session %s: %w", sessionID, err)
+		}
+
+		switch scope {
+		case entity.StarRequest:
+			return bucket.Put(requestKey, []byte{})
+		case entity.StarSession:
+			return bucket.Put(sessionKey, []byte{})
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+func TestBoltDBStarRepository_SetStar(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	repo := NewBoltDBStarRepository(db)
+	baseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	target := createTestEntity("session1", baseTime)
+	sibling := createTestEntity("session1", baseTime.Add(time.Minute))
+
+	// Databases without the stars bucket have nothing starred
+	stars, err := repo.GetStars()
+	if err != nil {
+		t.Fatalf("GetStars() error = %v", err)
+	}
+	if stars.Len() != 0 {
+		t.Errorf("GetStars() got %d stars, want 0", stars.Len())
+	}
+
+	tests := []struct {
+		scope           entity.StarScope
+		expectedTarget  entity.StarScope
+		expectedSibling entity.StarScope
+	}{
+		{scope: entity.StarRequest, expectedTarget: entity.StarRequest, expectedSibling: entity.StarNone},
+		{scope: entity.StarSession, expectedTarget: entity.StarSession, expectedSibling: entity.StarSession},
+		{scope: entity.StarNone, expectedTarget: entity.StarNone, expectedSibling: entity.StarNone},
+	}
+
+	for _, tt := range tests {
+		if err := repo.SetStar(target.ID(), target.SessionID(), tt.scope); err != nil {
+			t.Fatalf("SetStar(%v) error = %v", tt.scope, err)
+		}
+
+		stars, err := repo.GetStars()
+		if err != nil {
+			t.Fatalf("GetStars() error = %v", err)
+		}
+		if got := stars.ScopeOf(target); got != tt.expectedTarget {
+			t.Errorf("After SetStar(%v) target scope = %v, want %v", tt.scope, got, tt.expectedTarget)
+		}
+		if got := stars.ScopeOf(sibling); got != tt.expectedSibling {
+			t.Errorf("After SetStar(%v) sibling scope = %v, want %v", tt.scope, got, tt.expectedSibling)
+		}
+	}
+}
+
+func TestBoltDBAPIRequestRepository_DeleteOlderThanKeepsStarred(t *testing.T) {
+	t.Parallel()
+
+	db, err := bbolt.Open(createTempDB(t), 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucket([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	starRepo := NewBoltDBStarRepository(db)
+
+	baseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	starredRequest := createTestEntity("session1", baseTime)
+	requests := []entity.APIRequest{
+		starredRequest,
+		createTestEntity("session1", baseTime.Add(time.Hour)),
+		createTestEntity("session2", baseTime),
+		createTestEntity("session2", baseTime.Add(time.Hour)),
+		createTestEntity("session3", baseTime),
+	}
+	if err := repo.SaveBatch(requests); err != nil {
+		t.Fatalf("SaveBatch() error = %v", err)
+	}
+
+	if err := starRepo.SetStar(starredRequest.ID(), starredRequest.SessionID(), entity.StarRequest); err != nil {
+		t.Fatalf("SetStar() error = %v", err)
+	}
+	if err := starRepo.SetStar(requests[2].ID(), "session2", entity.StarSession); err != nil {
+		t.Fatalf("SetStar() error = %v", err)
+	}
+
+	stars, err := starRepo.GetStars()
+	if err != nil {
+		t.Fatalf("GetStars() error = %v", err)
+	}
+
+	deletedCount, err := repo.DeleteOlderThan(baseTime.Add(24*time.Hour), stars)
+	if err != nil {
+		t.Fatalf("DeleteOlderThan() error = %v", err)
+	}
+	if deletedCount != 2 {
+		t.Errorf("DeleteOlderThan() deleted count = %d, want 2", deletedCount)
+	}
+
+	remaining, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(remaining) != 3 {
+		t.Fatalf("Remaining records count = %d, want 3", len(remaining))
+	}
+	for _, req := range remaining {
+		if !stars.IsStarred(req) {
+			t.Errorf("Unstarred request %s was kept", req.ID())
+		}
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"github.com/elct9620/ccmon/usecase"
 )
 
+// statsSampleSize bounds the requests decoded for estimated stats, periods with fewer requests are calculated exactly
+const statsSampleSize = 10000
+
 // BoltDBStatsRepository implements usecase.StatsRepository by calculating stats from BoltDB APIRequestRepository
-// This is used on the server side where we have direct access to the BoltDB request data
+// This is used on the server side where we have direct access to the BoltDB request data,
+// and on the monitor side as a fallback when the server does not implement GetStats
 type BoltDBStatsRepository struct {
 	apiRequestRepository usecase.APIRequestRepository
 }
@@ -19,7 +23,12 @@ func NewBoltDBStatsRepository(apiRequestRepository usecase.APIRequestRepository)
 }
 
 // GetStatsByPeriod retrieves statistics by calculating them from API requests
+// Repositories keeping precomputed aggregates, like the BoltDB daily stats, answer without decoding the requests
 func (r *BoltDBStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	if repository, ok := r.apiRequestRepository.(usecase.StatsRepository); ok {
+		return repository.GetStatsByPeriod(period)
+	}
+
 	// Get all requests for the period (no limit)
 	requests, err := r.apiRequestRepository.FindByPeriodWithLimit(period, 0, 0)
 	if err != nil {
@@ -27,5 +36,44 @@ func (r *BoltDBStatsRepository) GetStatsByPeriod(period entity.Period) (entity.S
 	}
 
 	// Calculate stats from requests
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewStatsFromRequests(requests, period).WithModels(entity.NewModelStatsFromRequests(requests)), nil
+}
+
+// EstimateStatsByPeriod retrieves statistics estimated from a sample of the requests in the period
+// Repositories without sampling support calculate exact statistics
+func (r *BoltDBStatsRepository) EstimateStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	repository, ok := r.apiRequestRepository.(usecase.APIRequestSampleRepository)
+	if !ok {
+		return r.GetStatsByPeriod(period)
+	}
+
+	sample, total, err := repository.SampleByPeriod(period, statsSampleSize)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+
+	return entity.EstimateStatsFromSample(sample, total, period), nil
+}
+
+// GetStatsByFilter retrieves statistics by calculating them from the API requests matching the filter
+func (r *BoltDBStatsRepository) GetStatsByFilter(filter entity.Filter) (entity.Stats, error) {
+	requests, err := r.findByFilter(filter)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+
+	return entity.NewStatsFromRequests(requests, filter.Period()).WithModels(entity.NewModelStatsFromRequests(requests)), nil
+}
+
+// findByFilter retrieves every request matching the filter, filtering the period when the repository cannot
+func (r *BoltDBStatsRepository) findByFilter(filter entity.Filter) ([]entity.APIRequest, error) {
+	if repository, ok := r.apiRequestRepository.(usecase.APIRequestFilterRepository); ok {
+		return repository.FindByFilter(filter, 0, 0)
+	}
+
+	requests, err := r.apiRequestRepository.FindByPeriodWithLimit(filter.Period(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Apply(requests), nil
 }
@@ -137,3 +137,27 @@ func TestBoltDBStatsRepository_GetStatsByPeriod(t *testing.T) {
 		})
 	}
 }
+
+func TestBoltDBStatsRepository_EstimateStatsByPeriod(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+
+	// Repositories without sampling support fall back to exact stats
+	mockRepo := testutil.NewMockAPIRequestRepository()
+	mockRepo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000),
+	})
+
+	stats, err := NewBoltDBStatsRepository(mockRepo).EstimateStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.IsApproximate() {
+		t.Error("Expected exact stats without sampling support")
+	}
+	if stats.TotalRequests() != 1 {
+		t.Errorf("Expected 1 request, got %d", stats.TotalRequests())
+	}
+}
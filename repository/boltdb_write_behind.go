@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+// DefaultWriteBehindSize is the default number of pending requests which are written without waiting for the interval
+const DefaultWriteBehindSize = 1000
+
+// writeBehindAttempts is how many times the pending requests are written before they are dropped
+const writeBehindAttempts = 3
+
+// WriteBehindOptions configures how saved requests are batched into write transactions
+type WriteBehindOptions struct {
+	Interval time.Duration // longest time a saved request waits to be written
+	Size     int           // pending requests which are written without waiting for the interval
+}
+
+// writeBehind holds the saved requests until the flusher writes them in a single transaction
+type writeBehind struct {
+	options WriteBehindOptions
+
+	mu      sync.Mutex
+	pending []entity.APIRequest
+	stopped bool            // saves are written right away once stopped
+	onDrop  func(count int) // reports dropped requests, nil when nobody counts them
+
+	flushMu  sync.Mutex // held while pending requests are written, so readers wait for a running flush
+	failures int        // consecutive failed writes of the pending requests, guarded by flushMu
+	write    func(reqs []entity.APIRequest) error
+
+	full chan struct{} // signals the flusher that the size is reached
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartWriteBehind batches the saved requests, Save and SaveBatch return once the requests are queued
+// Queued requests are written in one transaction when the interval passes or the size is reached, and before
+// every read, so queries always include them. A crash loses the requests queued within the interval, which
+// StopWriteBehind writes on a graceful shutdown. Requests failing to be written are dropped after a few attempts,
+// so a broken database doesn't grow the queue without bound. It must be started before the repository is shared
+func (r *BoltDBAPIRequestRepository) StartWriteBehind(options WriteBehindOptions) {
+	if options.Interval <= 0 || r.writer != nil {
+		return
+	}
+	if options.Size <= 0 {
+		options.Size = DefaultWriteBehindSize
+	}
+
+	w := &writeBehind{
+		options: options,
+		write:   r.writeRequests,
+		full:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	r.writer = w
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(options.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-w.full:
+			case <-w.stop:
+				return
+			}
+			if err := r.flushPending(); err != nil {
+				log.Printf("Failed to write queued requests: %v", err)
+			}
+		}
+	}()
+}
+
+// StopWriteBehind stops batching and writes the queued requests, later saves are written right away
+func (r *BoltDBAPIRequestRepository) StopWriteBehind() error {
+	w := r.writer
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return nil
+	}
+	w.stopped = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+
+	return r.flushPending()
+}
+
+// SetWriteBehindDropHandler reports the number of queued requests dropped after failing every write, e.g. to count them as failed ingests
+// Without write-behind nothing is dropped, failed saves are returned to the caller
+func (r *BoltDBAPIRequestRepository) SetWriteBehindDropHandler(fn func(count int)) {
+	w := r.writer
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	w.onDrop = fn
+	w.mu.Unlock()
+}
+
+// queue adds the requests to the pending writes, waking the flusher once the size is reached
+// Returns false once stopped, the requests must be written by the caller
+func (w *writeBehind) queue(reqs ...entity.APIRequest) bool {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return false
+	}
+	w.pending = append(w.pending, reqs...)
+	full := len(w.pending) >= w.options.Size
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// flushPending writes the queued requests in a single transaction
+// Failed writes are queued again in front of newer requests, so the last saved version of a request wins,
+// until they failed writeBehindAttempts times in a row and are dropped and reported to the drop handler
+func (r *BoltDBAPIRequestRepository) flushPending() error {
+	w := r.writer
+	if w == nil {
+		return nil
+	}
+
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := w.write(pending); err != nil {
+		w.failures++
+		if w.failures >= writeBehindAttempts {
+			w.failures = 0
+			log.Printf("Dropped %d queued requests after %d failed writes", len(pending), writeBehindAttempts)
+
+			w.mu.Lock()
+			onDrop := w.onDrop
+			w.mu.Unlock()
+			if onDrop != nil {
+				onDrop(len(pending))
+			}
+			return err
+		}
+
+		w.mu.Lock()
+		w.pending = append(pending, w.pending...)
+		w.mu.Unlock()
+		return err
+	}
+	w.failures = 0
+	return nil
+}
+
+// view runs a read transaction after writing the queued requests, so reads include every saved request
+// A failing write doesn't fail the read, the queued requests are left out until they are written
+func (r *BoltDBAPIRequestRepository) view(fn func(tx *bbolt.Tx) error) error {
+	if err := r.flushPending(); err != nil {
+		log.Printf("Failed to write queued requests before reading: %v", err)
+	}
+	return r.db.View(fn)
+}
@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"go.etcd.io/bbolt"
+)
+
+// openWriteBehindTestRepository opens a database at the path with the daily stats built and write-behind started
+func openWriteBehindTestRepository(t *testing.T, path string, options WriteBehindOptions) (*bbolt.DB, *BoltDBAPIRequestRepository) {
+	t.Helper()
+
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(requestsBucket))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+
+	repo := NewBoltDBAPIRequestRepository(db)
+	if _, err := repo.BuildDailyStats(); err != nil {
+		t.Fatalf("BuildDailyStats() failed: %v", err)
+	}
+	repo.StartWriteBehind(options)
+	return db, repo
+}
+
+// writeBehindTestRequests returns requests of a day, one minute apart
+func writeBehindTestRequests(count int) []entity.APIRequest {
+	baseTime := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	requests := make([]entity.APIRequest, 0, count)
+	for i := 0; i < count; i++ {
+		requests = append(requests, createTestEntity(fmt.Sprintf("session-%d", i), baseTime.Add(time.Duration(i)*time.Minute)))
+	}
+	return requests
+}
+
+// writtenRequestCount returns the requests in the database file, without writing the queued ones first
+func writtenRequestCount(t *testing.T, db *bbolt.DB) int {
+	t.Helper()
+
+	var count int
+	if err := db.View(func(tx *bbolt.Tx) error {
+		count = tx.Bucket([]byte(requestsBucket)).Stats().KeyN
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to count requests: %v", err)
+	}
+	return count
+}
+
+// waitForWrittenRequests waits until the flusher has written the expected number of requests
+func waitForWrittenRequests(t *testing.T, db *bbolt.DB, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		count := writtenRequestCount(t, db)
+		if count == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d written requests, got %d", expected, count)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_WriteBehind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options WriteBehindOptions
+		saved   int
+		written int // requests in the file right after saving
+		flushed int // requests in the file once the flusher runs, without any read
+	}{
+		{name: "queued until the interval", options: WriteBehindOptions{Interval: 20 * time.Millisecond, Size: 100}, saved: 10, written: 0, flushed: 10},
+		{name: "written once the size is reached", options: WriteBehindOptions{Interval: time.Hour, Size: 10}, saved: 10, written: 0, flushed: 10},
+		{name: "held below the size", options: WriteBehindOptions{Interval: time.Hour, Size: 100}, saved: 10, written: 0, flushed: 0},
+		{name: "disabled", options: WriteBehindOptions{}, saved: 10, written: 10, flushed: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, repo := openWriteBehindTestRepository(t, createTempDB(t), tt.options)
+			defer func() {
+				if err := repo.Close(); err != nil {
+					t.Logf("Failed to close repository: %v", err)
+				}
+			}()
+
+			if err := repo.SaveBatch(writeBehindTestRequests(tt.saved)); err != nil {
+				t.Fatalf("SaveBatch() failed: %v", err)
+			}
+			// A flush may already run on a slow machine, but never before the size or interval is reached
+			if tt.options.Interval >= time.Hour || tt.options.Interval == 0 {
+				if count := writtenRequestCount(t, db); count != tt.written && count != tt.flushed {
+					t.Errorf("Expected %d written requests after saving, got %d", tt.written, count)
+				}
+			}
+			waitForWrittenRequests(t, db, tt.flushed)
+
+			// Reads include the queued requests
+			stored, err := repo.FindAll()
+			if err != nil {
+				t.Fatalf("FindAll() failed: %v", err)
+			}
+			if len(stored) != tt.saved {
+				t.Errorf("Expected FindAll() to return %d requests, got %d", tt.saved, len(stored))
+			}
+			if count := writtenRequestCount(t, db); count != tt.saved {
+				t.Errorf("Expected reads to write the queued requests, got %d of %d written", count, tt.saved)
+			}
+		})
+	}
+}
+
+func TestBoltDBAPIRequestRepository_WriteBehindBatchesTransactions(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openWriteBehindTestRepository(t, createTempDB(t), WriteBehindOptions{Interval: time.Hour, Size: 1000})
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close repository: %v", err)
+		}
+	}()
+
+	before := committedTxID(t, db)
+	for _, req := range writeBehindTestRequests(50) {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+	if _, err := repo.FindAll(); err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+
+	if commits := committedTxID(t, db) - before; commits != 1 {
+		t.Errorf("Expected the queued saves to be written in 1 commit, got %d", commits)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_StopWriteBehind(t *testing.T) {
+	t.Parallel()
+
+	path := createTempDB(t)
+	db, repo := openWriteBehindTestRepository(t, path, WriteBehindOptions{Interval: time.Hour, Size: 1000})
+
+	if err := repo.SaveBatch(writeBehindTestRequests(5)); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+	if err := repo.StopWriteBehind(); err != nil {
+		t.Fatalf("StopWriteBehind() failed: %v", err)
+	}
+	if count := writtenRequestCount(t, db); count != 5 {
+		t.Errorf("Expected stopping to write 5 queued requests, got %d", count)
+	}
+
+	// Saves after stopping are written right away
+	before := committedTxID(t, db)
+	if err := repo.Save(createTestEntity("after-stop", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if commits := committedTxID(t, db) - before; commits != 1 {
+		t.Errorf("Expected a save after stopping to commit right away, got %d commits", commits)
+	}
+	if err := repo.StopWriteBehind(); err != nil {
+		t.Errorf("Expected stopping twice to succeed, got %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+
+	// A graceful shutdown keeps every saved request
+	db, repo = openWriteBehindTestRepository(t, path, WriteBehindOptions{})
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+	stored, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(stored) != 6 {
+		t.Errorf("Expected 6 requests after reopening, got %d", len(stored))
+	}
+}
+
+func TestBoltDBAPIRequestRepository_WriteBehindCrash(t *testing.T) {
+	t.Parallel()
+
+	path := createTempDB(t)
+	db, repo := openWriteBehindTestRepository(t, path, WriteBehindOptions{Interval: time.Hour, Size: 10})
+
+	requests := writeBehindTestRequests(13)
+	if err := repo.SaveBatch(requests[:10]); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+	waitForWrittenRequests(t, db, 10)
+	if err := repo.SaveBatch(requests[10:]); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	// Closing the file without stopping leaves the queued requests unwritten, like a crash
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+	if err := repo.StopWriteBehind(); err == nil {
+		t.Error("Expected writing the queued requests to a closed database to fail")
+	}
+
+	db, repo = openWriteBehindTestRepository(t, path, WriteBehindOptions{})
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	// Only the written batch survives, and its daily stats were committed with it
+	stored, err := repo.FindAll()
+	if err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+	if len(stored) != 10 {
+		t.Errorf("Expected the 10 written requests to survive, got %d", len(stored))
+	}
+	if built, err := repo.BuildDailyStats(); err != nil || built {
+		t.Errorf("BuildDailyStats() = %v, %v, want the daily stats to be kept", built, err)
+	}
+
+	period := entity.NewAllTimePeriod(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	got, err := repo.GetStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	assertStatsEqual(t, got, scannedStats(t, repo, period))
+	if got.TotalRequests() != 10 {
+		t.Errorf("Expected daily stats of 10 requests, got %d", got.TotalRequests())
+	}
+}
+
+func TestBoltDBAPIRequestRepository_WriteBehindDeleteOlderThan(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openWriteBehindTestRepository(t, createTempDB(t), WriteBehindOptions{Interval: time.Hour, Size: 1000})
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close repository: %v", err)
+		}
+	}()
+
+	if err := repo.SaveBatch(writeBehindTestRequests(10)); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	// Queued requests older than the cutoff are deleted rather than written afterwards
+	cutoff := time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC)
+	deleted, err := repo.DeleteOlderThan(cutoff, entity.Stars{})
+	if err != nil {
+		t.Fatalf("DeleteOlderThan() failed: %v", err)
+	}
+	if deleted != 5 {
+		t.Errorf("Expected 5 deleted requests, got %d", deleted)
+	}
+	if count := writtenRequestCount(t, db); count != 5 {
+		t.Errorf("Expected 5 requests left, got %d", count)
+	}
+}
+
+func TestBoltDBAPIRequestRepository_WriteBehindFailedWrites(t *testing.T) {
+	t.Parallel()
+
+	db, repo := openWriteBehindTestRepository(t, createTempDB(t), WriteBehindOptions{Interval: time.Hour, Size: 1000})
+	defer func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close repository: %v", err)
+		}
+	}()
+
+	if err := repo.SaveBatch(writeBehindTestRequests(2)); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+	if _, err := repo.FindAll(); err != nil {
+		t.Fatalf("FindAll() failed: %v", err)
+	}
+
+	repo.writer.flushMu.Lock()
+	repo.writer.write = func(reqs []entity.APIRequest) error {
+		return errors.New("disk full")
+	}
+	repo.writer.flushMu.Unlock()
+
+	var dropped []int
+	repo.SetWriteBehindDropHandler(func(count int) {
+		dropped = append(dropped, count)
+	})
+
+	if err := repo.SaveBatch(writeBehindTestRequests(5)); err != nil {
+		t.Fatalf("SaveBatch() failed: %v", err)
+	}
+
+	// Reads keep working with the written requests while the queued ones fail
+	for i := 0; i < writeBehindAttempts; i++ {
+		stored, err := repo.FindAll()
+		if err != nil {
+			t.Fatalf("Expected reads to ignore the failed write, got %v", err)
+		}
+		if len(stored) != 2 {
+			t.Errorf("Expected the 2 written requests, got %d", len(stored))
+		}
+	}
+
+	// The queued requests are dropped once they failed every attempt
+	repo.writer.mu.Lock()
+	pending := len(repo.writer.pending)
+	repo.writer.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("Expected the failing requests to be dropped, got %d queued", pending)
+	}
+	if !slices.Equal(dropped, []int{5}) {
+		t.Errorf("Expected the 5 dropped requests to be reported once, got %v", dropped)
+	}
+	if count := writtenRequestCount(t, db); count != 2 {
+		t.Errorf("Expected 2 written requests, got %d", count)
+	}
+}
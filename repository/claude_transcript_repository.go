@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// maxTranscriptLineSize bounds a single transcript line, tool results with large file contents can exceed bufio's default
+const maxTranscriptLineSize = 16 * 1024 * 1024
+
+// transcriptLine is the subset of a Claude Code transcript line used for session titles
+type transcriptLine struct {
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+	Cwd     string `json:"cwd"`
+}
+
+// cachedTranscript is a parsed transcript, reused until the file is modified
+type cachedTranscript struct {
+	modTime time.Time
+	size    int64
+	title   entity.SessionTitle
+}
+
+// ClaudeTranscriptRepository reads session titles from Claude Code transcripts
+// Transcripts are stored as <dir>/<project>/<session ID>.jsonl, e.g. in ~/.claude/projects
+type ClaudeTranscriptRepository struct {
+	dir string
+
+	mu    sync.Mutex
+	cache map[string]cachedTranscript // keyed by transcript path
+}
+
+// NewClaudeTranscriptRepository creates a new ClaudeTranscriptRepository reading transcripts from dir
+func NewClaudeTranscriptRepository(dir string) *ClaudeTranscriptRepository {
+	return &ClaudeTranscriptRepository{
+		dir:   dir,
+		cache: make(map[string]cachedTranscript),
+	}
+}
+
+// FindSessionTitles retrieves the titles of the sessions, sessions without a transcript are left out
+// A missing transcripts directory is not an error, e.g. when the monitor runs on another host than Claude Code
+func (r *ClaudeTranscriptRepository) FindSessionTitles(sessionIDs []string) (entity.SessionTitles, error) {
+	titles := make(entity.SessionTitles)
+
+	for _, sessionID := range sessionIDs {
+		if _, ok := titles[sessionID]; ok || !isTranscriptName(sessionID) {
+			continue
+		}
+
+		paths, err := filepath.Glob(filepath.Join(r.dir, "*", sessionID+".jsonl"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range paths {
+			title, err := r.readTitle(path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !title.IsZero() {
+				titles[sessionID] = title
+				break
+			}
+		}
+	}
+
+	return titles, nil
+}
+
+// readTitle reads the latest summary and the workspace of a transcript, cached by modification time and size
+func (r *ClaudeTranscriptRepository) readTitle(path string) (entity.SessionTitle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return entity.SessionTitle{}, err
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[path]
+	r.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.title, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return entity.SessionTitle{}, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var summary, project string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLineSize)
+	for scanner.Scan() {
+		var line transcriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines, e.g. a line being written
+		}
+
+		if line.Type == "summary" && line.Summary != "" {
+			summary = line.Summary
+		}
+		if project == "" && line.Cwd != "" {
+			project = line.Cwd
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return entity.SessionTitle{}, err
+	}
+
+	title := entity.NewSessionTitle(summary, project)
+
+	r.mu.Lock()
+	r.cache[path] = cachedTranscript{modTime: info.ModTime(), size: info.Size(), title: title}
+	r.mu.Unlock()
+
+	return title, nil
+}
+
+// isTranscriptName reports whether the session ID can be used as a transcript file name without escaping the directory
+func isTranscriptName(sessionID string) bool {
+	return sessionID != "" && !strings.ContainsAny(sessionID, `/\*?[]`) && sessionID != "." && sessionID != ".."
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTranscript(t *testing.T, dir, project, sessionID string, lines ...string) string {
+	t.Helper()
+
+	projectDir := filepath.Join(dir, project)
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+
+	path := filepath.Join(projectDir, sessionID+".jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+	return path
+}
+
+func TestClaudeTranscriptRepository_FindSessionTitles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTranscript(t, dir, "-home-alice-billing", "session-summary",
+		`{"type":"user","sessionId":"session-summary","cwd":"/home/alice/billing","message":{"role":"user","content":"hi"}}`,
+		`not json`,
+		`{"type":"summary","summary":"Billing draft","leafUuid":"a"}`,
+		`{"type":"summary","summary":"Refactor billing module","leafUuid":"b"}`,
+	)
+	writeTranscript(t, dir, "-home-alice-api", "session-workspace",
+		`{"type":"user","sessionId":"session-workspace","cwd":"/home/alice/api"}`,
+	)
+	writeTranscript(t, dir, "-home-alice-empty", "session-empty", `{"type":"user"}`)
+
+	repo := NewClaudeTranscriptRepository(dir)
+	titles, err := repo.FindSessionTitles([]string{"session-summary", "session-workspace", "session-empty", "session-missing", "../session-summary", "*"})
+	if err != nil {
+		t.Fatalf("FindSessionTitles() failed: %v", err)
+	}
+
+	if len(titles) != 2 {
+		t.Fatalf("Expected 2 titles, got %d: %v", len(titles), titles)
+	}
+	if got := titles["session-summary"]; got.Title() != "Refactor billing module" || got.Project() != "/home/alice/billing" {
+		t.Errorf("Expected latest summary and workspace, got %q in %q", got.Title(), got.Project())
+	}
+	if got := titles["session-workspace"].Label(); got != "api" {
+		t.Errorf("Expected workspace label, got %q", got)
+	}
+}
+
+func TestClaudeTranscriptRepository_FindSessionTitles_Modified(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeTranscript(t, dir, "project", "session", `{"type":"summary","summary":"First"}`)
+
+	repo := NewClaudeTranscriptRepository(dir)
+	if titles, err := repo.FindSessionTitles([]string{"session"}); err != nil || titles["session"].Title() != "First" {
+		t.Fatalf("Expected first summary, got %v (%v)", titles, err)
+	}
+
+	writeTranscript(t, dir, "project", "session", `{"type":"summary","summary":"First"}`, `{"type":"summary","summary":"Second"}`)
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("Failed to touch transcript: %v", err)
+	}
+
+	if titles, err := repo.FindSessionTitles([]string{"session"}); err != nil || titles["session"].Title() != "Second" {
+		t.Errorf("Expected the modified transcript to be read again, got %v (%v)", titles, err)
+	}
+}
+
+func TestClaudeTranscriptRepository_FindSessionTitles_MissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	repo := NewClaudeTranscriptRepository(filepath.Join(t.TempDir(), "missing"))
+	titles, err := repo.FindSessionTitles([]string{"session"})
+	if err != nil {
+		t.Fatalf("Expected no error for a missing directory, got %v", err)
+	}
+	if len(titles) != 0 {
+		t.Errorf("Expected no titles, got %v", titles)
+	}
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// syntheticModel is the model of the messages Claude Code writes itself, e.g. when a request was interrupted
+const syntheticModel = "<synthetic>"
+
+// transcriptUsageLine is the subset of a Claude Code transcript line used for the usage of assistant messages
+type transcriptUsageLine struct {
+	Type      string    `json:"type"`
+	SessionID string    `json:"sessionId"`
+	Cwd       string    `json:"cwd"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId"`
+	CostUSD   float64   `json:"costUSD"` // reported by older Claude Code versions only
+	Message   struct {
+		ID    string `json:"id"`
+		Model string `json:"model"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ClaudeTranscriptUsageRepository reads the API requests of the assistant messages in Claude Code transcripts
+// Transcripts lack the duration of the requests, and only older Claude Code versions write their cost
+type ClaudeTranscriptUsageRepository struct{}
+
+// NewClaudeTranscriptUsageRepository creates a new ClaudeTranscriptUsageRepository
+func NewClaudeTranscriptUsageRepository() *ClaudeTranscriptUsageRepository {
+	return &ClaudeTranscriptUsageRepository{}
+}
+
+// FindAPIRequests retrieves the API requests of the transcript at path, or of every .jsonl file below the directory
+// A message is written once per content block with the same ID, only its last line is kept since it carries the final usage
+// The requests are ordered by timestamp and carry the message ID as their event ID
+func (r *ClaudeTranscriptUsageRepository) FindAPIRequests(path string) ([]entity.APIRequest, error) {
+	paths, err := transcriptPaths(path)
+	if err != nil {
+		return nil, err
+	}
+
+	byMessage := make(map[string]entity.APIRequest)
+	var requests []entity.APIRequest
+	for _, transcript := range paths {
+		if err := readTranscriptUsage(transcript, func(messageID string, req entity.APIRequest) {
+			if messageID == "" {
+				requests = append(requests, req)
+				return
+			}
+			byMessage[messageID] = req
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, req := range byMessage {
+		requests = append(requests, req)
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Timestamp().Before(requests[j].Timestamp())
+	})
+	return requests, nil
+}
+
+// transcriptPaths returns the path when it is a file, otherwise the .jsonl files below the directory in lexical order
+func transcriptPaths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var paths []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && filepath.Ext(p) == ".jsonl" {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// readTranscriptUsage calls found with the message ID and API request of each assistant message reporting its usage
+func readTranscriptUsage(path string, found func(messageID string, req entity.APIRequest)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTranscriptLineSize)
+	for scanner.Scan() {
+		var line transcriptUsageLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue // Skip malformed lines, e.g. a line being written
+		}
+		if line.Type != "assistant" || line.Message.Usage == nil || line.Message.Model == syntheticModel || line.Timestamp.IsZero() {
+			continue
+		}
+
+		usage := line.Message.Usage
+		tokens := entity.NewToken(usage.InputTokens, usage.OutputTokens, usage.CacheReadInputTokens, usage.CacheCreationInputTokens)
+		if tokens.Total() == 0 {
+			continue
+		}
+
+		messageID := line.Message.ID
+		if messageID != "" && line.RequestID != "" {
+			messageID += ":" + line.RequestID
+		}
+		req := entity.NewAPIRequest(line.SessionID, line.Timestamp, line.Message.Model, tokens, entity.NewCost(line.CostUSD), 0).
+			WithProject(workspaceName(line.Cwd)).
+			WithEventID(messageID)
+		found(messageID, req)
+	}
+	return scanner.Err()
+}
+
+// workspaceName returns the directory name of the workspace, transcripts copied from Windows use backslashes
+func workspaceName(cwd string) string {
+	cwd = strings.TrimRight(cwd, `/\`)
+	return cwd[strings.LastIndexAny(cwd, `/\`)+1:]
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClaudeTranscriptUsageRepository_FindAPIRequests(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTranscript(t, dir, "-home-alice-billing", "session-1",
+		`{"type":"user","sessionId":"session-1","cwd":"/home/alice/billing","timestamp":"2025-06-01T10:00:00Z","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","sessionId":"session-1","cwd":"/home/alice/billing","timestamp":"2025-06-01T10:00:05Z","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":1,"cache_read_input_tokens":100,"cache_creation_input_tokens":50}}}`,
+		`{"type":"assistant","sessionId":"session-1","cwd":"/home/alice/billing","timestamp":"2025-06-01T10:00:08Z","requestId":"req_1","message":{"id":"msg_1","model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"output_tokens":200,"cache_read_input_tokens":100,"cache_creation_input_tokens":50}}}`,
+		`not json`,
+		`{"type":"assistant","sessionId":"session-1","timestamp":"2025-06-01T10:01:00Z","message":{"id":"msg_2","model":"<synthetic>","usage":{"input_tokens":0,"output_tokens":0}}}`,
+	)
+	writeTranscript(t, dir, `-C-Users-bob-api`, "session-2",
+		`{"type":"assistant","sessionId":"session-2","cwd":"C:\\Users\\bob\\api","timestamp":"2025-05-31T09:00:00Z","costUSD":0.25,"message":{"id":"msg_3","model":"claude-opus-4-20250514","usage":{"input_tokens":5,"output_tokens":50}}}`,
+	)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`{"type":"assistant"}`), 0644); err != nil {
+		t.Fatalf("Failed to write notes: %v", err)
+	}
+
+	repo := NewClaudeTranscriptUsageRepository()
+	requests, err := repo.FindAPIRequests(dir)
+	if err != nil {
+		t.Fatalf("FindAPIRequests() failed: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d: %v", len(requests), requests)
+	}
+
+	// Ordered by timestamp
+	opus := requests[0]
+	if opus.SessionID() != "session-2" || opus.Project() != "api" || opus.Cost().Amount() != 0.25 {
+		t.Errorf("Expected the reported cost and Windows workspace, got %s in %q costing %v", opus.SessionID(), opus.Project(), opus.Cost().Amount())
+	}
+
+	sonnet := requests[1]
+	if !sonnet.Timestamp().Equal(time.Date(2025, 6, 1, 10, 0, 8, 0, time.UTC)) {
+		t.Errorf("Expected the last line of the message, got %v", sonnet.Timestamp())
+	}
+	if tokens := sonnet.Tokens(); tokens.Input() != 10 || tokens.Output() != 200 || tokens.CacheRead() != 100 || tokens.CacheCreation() != 50 {
+		t.Errorf("Expected the final usage of the message, got %+v", tokens)
+	}
+	if sonnet.Project() != "billing" || sonnet.Model().String() != "claude-sonnet-4-20250514" || sonnet.EventID() != "msg_1:req_1" {
+		t.Errorf("Expected billing project, sonnet model and message event ID, got %q, %q, %q", sonnet.Project(), sonnet.Model(), sonnet.EventID())
+	}
+}
+
+func TestClaudeTranscriptUsageRepository_FindAPIRequests_File(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeTranscript(t, dir, "project", "session",
+		`{"type":"assistant","sessionId":"session","timestamp":"2025-06-01T10:00:00Z","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1,"output_tokens":2}}}`,
+		`{"type":"assistant","sessionId":"session","timestamp":"2025-06-01T10:01:00Z","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1,"output_tokens":2}}}`,
+	)
+	writeTranscript(t, dir, "project", "other",
+		`{"type":"assistant","sessionId":"other","timestamp":"2025-06-01T10:00:00Z","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":1,"output_tokens":2}}}`,
+	)
+
+	requests, err := NewClaudeTranscriptUsageRepository().FindAPIRequests(path)
+	if err != nil {
+		t.Fatalf("FindAPIRequests() failed: %v", err)
+	}
+	// Messages without an ID are kept apart
+	if len(requests) != 2 {
+		t.Errorf("Expected only the 2 requests of the file, got %d", len(requests))
+	}
+}
+
+func TestClaudeTranscriptUsageRepository_FindAPIRequests_Missing(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClaudeTranscriptUsageRepository().FindAPIRequests(filepath.Join(t.TempDir(), "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error, got %v", err)
+	}
+}
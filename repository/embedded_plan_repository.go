@@ -32,6 +32,7 @@ type PlansDocument struct {
 
 type PlanConfig interface {
 	GetClaudePlan() string
+	GetClaudeUserPlan(user string) string
 }
 
 func NewEmbeddedPlanRepository(config PlanConfig, dataFS FileSystem) (*EmbeddedPlanRepository, error) {
@@ -53,7 +54,16 @@ func NewEmbeddedPlanRepository(config PlanConfig, dataFS FileSystem) (*EmbeddedP
 }
 
 func (r *EmbeddedPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
-	planName := r.config.GetClaudePlan()
+	return r.findPlan(r.config.GetClaudePlan()), nil
+}
+
+// GetUserPlan returns the plan assigned to the user, the configured plan when the user has no own plan
+func (r *EmbeddedPlanRepository) GetUserPlan(user string) (entity.Plan, error) {
+	return r.findPlan(r.config.GetClaudeUserPlan(user)), nil
+}
+
+// findPlan returns the plan of the name, unknown names are unset
+func (r *EmbeddedPlanRepository) findPlan(planName string) entity.Plan {
 	if planName == "" {
 		planName = "unset"
 	}
@@ -64,5 +74,5 @@ func (r *EmbeddedPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
 	}
 
 	cost := entity.NewCost(planData.Price)
-	return entity.NewPlan(planData.Name, cost), nil
+	return entity.NewPlan(planData.Name, cost)
 }
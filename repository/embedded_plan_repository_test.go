@@ -22,13 +22,21 @@ func (fs testDataEmbedFS) ReadFile(name string) ([]byte, error) {
 var mockDataFS = testDataEmbedFS{testDataFS}
 
 type mockPlanConfig struct {
-	plan string
+	plan  string
+	users map[string]string
 }
 
 func (m *mockPlanConfig) GetClaudePlan() string {
 	return m.plan
 }
 
+func (m *mockPlanConfig) GetClaudeUserPlan(user string) string {
+	if plan, ok := m.users[user]; ok {
+		return plan
+	}
+	return m.plan
+}
+
 func TestNewEmbeddedPlanRepository(t *testing.T) {
 	config := &mockPlanConfig{plan: "pro"}
 
@@ -101,6 +109,35 @@ func TestGetConfiguredPlan(t *testing.T) {
 	}
 }
 
+func TestGetUserPlan(t *testing.T) {
+	config := &mockPlanConfig{plan: "pro", users: map[string]string{"alice@example.com": "max20"}}
+
+	repo, err := NewEmbeddedPlanRepository(config, mockDataFS)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	tests := []struct {
+		user         string
+		expectedName string
+		expectedCost float64
+	}{
+		{"alice@example.com", "max20", 200.0},
+		{"bob@example.com", "pro", 20.0},
+		{"", "pro", 20.0},
+	}
+
+	for _, tt := range tests {
+		plan, err := repo.GetUserPlan(tt.user)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if plan.Name() != tt.expectedName || plan.Price().Amount() != tt.expectedCost {
+			t.Errorf("GetUserPlan(%q) = %s $%.1f, want %s $%.1f", tt.user, plan.Name(), plan.Price().Amount(), tt.expectedName, tt.expectedCost)
+		}
+	}
+}
+
 func TestPlanRepositoryInterface(t *testing.T) {
 	config := &mockPlanConfig{plan: "pro"}
 
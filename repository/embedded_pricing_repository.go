@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// EmbeddedPricingRepository provides the model prices shipped in data/pricing.json
+type EmbeddedPricingRepository struct {
+	table entity.PriceTable
+}
+
+// PriceData is the price of a model in USD per million tokens
+type PriceData struct {
+	Input         float64    `json:"input"`
+	Output        float64    `json:"output"`
+	CacheRead     float64    `json:"cache_read"`
+	CacheCreation float64    `json:"cache_creation"`
+	LongContext   *PriceData `json:"long_context,omitempty"` // prompts above 200K tokens of 1M context models
+}
+
+// PricingDocument is the layout of data/pricing.json
+type PricingDocument struct {
+	Models map[string]PriceData `json:"models"`
+}
+
+// NewEmbeddedPricingRepository creates a new EmbeddedPricingRepository reading data/pricing.json
+func NewEmbeddedPricingRepository(dataFS FileSystem) (*EmbeddedPricingRepository, error) {
+	pricingData, err := dataFS.ReadFile("data/pricing.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing.json: %w", err)
+	}
+
+	var doc PricingDocument
+	if err := json.Unmarshal(pricingData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pricing data: %w", err)
+	}
+
+	prices := make(map[string]entity.ModelPrice, len(doc.Models))
+	for name, data := range doc.Models {
+		price := data.toEntity()
+		if data.LongContext != nil {
+			price = price.WithLongContext(data.LongContext.toEntity())
+		}
+		prices[name] = price
+	}
+
+	return &EmbeddedPricingRepository{table: entity.NewPriceTable(prices)}, nil
+}
+
+// GetPriceTable returns the prices of the known models
+func (r *EmbeddedPricingRepository) GetPriceTable() (entity.PriceTable, error) {
+	return r.table, nil
+}
+
+// toEntity converts the price data to a model price without long context price
+func (d PriceData) toEntity() entity.ModelPrice {
+	return entity.NewModelPrice(d.Input, d.Output, d.CacheRead, d.CacheCreation)
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// mapFS serves files from memory
+type mapFS map[string]string
+
+func (fs mapFS) ReadFile(name string) ([]byte, error) {
+	data, ok := fs[name]
+	if !ok {
+		return nil, fmt.Errorf("file %s not found", name)
+	}
+	return []byte(data), nil
+}
+
+func TestEmbeddedPricingRepository_GetPriceTable(t *testing.T) {
+	repo, err := NewEmbeddedPricingRepository(mapFS{
+		"data/pricing.json": `{"models": {"claude-sonnet-4": {"input": 3, "output": 15, "cache_read": 0.3, "cache_creation": 3.75, "long_context": {"input": 6, "output": 22.5, "cache_read": 0.6, "cache_creation": 7.5}}}}`,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	table, err := repo.GetPriceTable()
+	if err != nil {
+		t.Fatalf("GetPriceTable() error = %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		model    string
+		tokens   entity.Token
+		expected float64
+	}{
+		{model: "claude-sonnet-4-20250514", tokens: entity.NewToken(1_000_000, 100_000, 0, 0), expected: 4.5},
+		{model: "claude-sonnet-4[1m]", tokens: entity.NewToken(1_000_000, 100_000, 0, 0), expected: 8.25},
+	}
+	for _, tt := range tests {
+		cost, ok := table.CostOf(entity.NewAPIRequest("session", now, tt.model, tt.tokens, entity.NewCost(0), 1000))
+		if !ok || math.Abs(cost.Amount()-tt.expected) > 1e-9 {
+			t.Errorf("CostOf(%s) = %f, %v, want %f", tt.model, cost.Amount(), ok, tt.expected)
+		}
+	}
+}
+
+func TestEmbeddedPricingRepository_Errors(t *testing.T) {
+	if _, err := NewEmbeddedPricingRepository(mapFS{}); err == nil {
+		t.Error("Expected an error without pricing.json")
+	}
+	if _, err := NewEmbeddedPricingRepository(mapFS{"data/pricing.json": "{"}); err == nil {
+		t.Error("Expected an error for malformed pricing.json")
+	}
+}
+
+func TestEmbeddedPricingRepository_ShippedPrices(t *testing.T) {
+	data, err := os.ReadFile("../data/pricing.json")
+	if err != nil {
+		t.Fatalf("Failed to read shipped pricing: %v", err)
+	}
+
+	repo, err := NewEmbeddedPricingRepository(mapFS{"data/pricing.json": string(data)})
+	if err != nil {
+		t.Fatalf("Failed to load shipped pricing: %v", err)
+	}
+	table, _ := repo.GetPriceTable()
+
+	for _, model := range []string{"claude-opus-4-1-20250805", "claude-sonnet-4-20250514", "claude-3-5-haiku-20241022", "claude-haiku-4-5-20251001"} {
+		if _, ok := table.Find(entity.NewModel(model)); !ok {
+			t.Errorf("Expected a shipped price for %s", model)
+		}
+	}
+}
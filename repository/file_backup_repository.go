@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileBackupRepository implements usecase.BackupRepository by keeping the latest backup in a single file
+type FileBackupRepository struct {
+	path string
+}
+
+// NewFileBackupRepository creates a new FileBackupRepository writing the backup to path
+func NewFileBackupRepository(path string) *FileBackupRepository {
+	return &FileBackupRepository{path: path}
+}
+
+// SaveBackup writes the backup through a temporary file renamed over the previous backup
+// An interrupted or failed backup keeps the previous one, so there is always a complete backup to restore
+func (r *FileBackupRepository) SaveBackup(ctx context.Context, write func(w io.Writer) (int64, error)) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	tmpPath := file.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	size, err := write(file)
+	if err != nil {
+		_ = file.Close()
+		return size, err
+	}
+	// The backup must be on disk before it replaces the previous one
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return size, fmt.Errorf("failed to sync backup file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return size, fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return size, err
+	}
+
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		return size, fmt.Errorf("failed to replace backup: %w", err)
+	}
+	return size, nil
+}
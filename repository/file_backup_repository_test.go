@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackupRepository_SaveBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backups", "ccmon.db.bak")
+	repo := NewFileBackupRepository(path)
+
+	write := func(content string) func(w io.Writer) (int64, error) {
+		return func(w io.Writer) (int64, error) {
+			n, err := io.WriteString(w, content)
+			return int64(n), err
+		}
+	}
+
+	size, err := repo.SaveBackup(context.Background(), write("first"))
+	if err != nil {
+		t.Fatalf("SaveBackup() error = %v", err)
+	}
+	if size != 5 {
+		t.Errorf("Expected 5 bytes written, got %d", size)
+	}
+
+	// A failed backup keeps the previous one
+	_, err = repo.SaveBackup(context.Background(), func(w io.Writer) (int64, error) {
+		_, _ = io.WriteString(w, "partial")
+		return 7, errors.New("snapshot failed")
+	})
+	if err == nil {
+		t.Fatal("Expected the snapshot error")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if string(data) != "first" {
+		t.Errorf("Expected the previous backup to be kept, got %q", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the backup file to be left, got %d files", len(entries))
+	}
+}
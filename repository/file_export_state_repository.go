@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// fileExportState is the JSON document of the export state file
+type fileExportState struct {
+	LastTimestamp time.Time `json:"last_timestamp"`
+	LastIDs       []string  `json:"last_ids"`
+}
+
+// FileExportStateRepository remembers how far previous exports got in a JSON file
+type FileExportStateRepository struct {
+	path string
+}
+
+// NewFileExportStateRepository creates a new FileExportStateRepository storing the state at path
+func NewFileExportStateRepository(path string) *FileExportStateRepository {
+	return &FileExportStateRepository{
+		path: path,
+	}
+}
+
+// LoadExportCursor reads the cursor of the last export, a missing file means nothing was exported yet
+func (r *FileExportStateRepository) LoadExportCursor() (entity.ExportCursor, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entity.ExportCursor{}, nil
+	}
+	if err != nil {
+		return entity.ExportCursor{}, err
+	}
+
+	var state fileExportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return entity.ExportCursor{}, fmt.Errorf("invalid export state file %s: %w", r.path, err)
+	}
+	return entity.NewExportCursor(state.LastTimestamp, state.LastIDs), nil
+}
+
+// SaveExportCursor writes the cursor through a temporary file, so an interrupted save keeps the previous state
+func (r *FileExportStateRepository) SaveExportCursor(cursor entity.ExportCursor) error {
+	data, err := json.MarshalIndent(fileExportState{
+		LastTimestamp: cursor.LastTimestamp(),
+		LastIDs:       cursor.LastIDs(),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(r.path, append(data, '\n'), 0600)
+}
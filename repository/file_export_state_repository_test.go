@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestFileExportStateRepository(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".ccmon-export.state")
+	repo := NewFileExportStateRepository(path)
+
+	// Nothing was exported before the state file exists
+	cursor, err := repo.LoadExportCursor()
+	if err != nil {
+		t.Fatalf("LoadExportCursor() error = %v", err)
+	}
+	if !cursor.IsZero() {
+		t.Errorf("LoadExportCursor() = %v, want a zero cursor", cursor.LastTimestamp())
+	}
+
+	lastTimestamp := time.Date(2025, 6, 1, 10, 0, 0, 123456789, time.UTC)
+	saved := entity.NewExportCursor(lastTimestamp, []string{"b", "a"})
+	if err := repo.SaveExportCursor(saved); err != nil {
+		t.Fatalf("SaveExportCursor() error = %v", err)
+	}
+
+	cursor, err = NewFileExportStateRepository(path).LoadExportCursor()
+	if err != nil {
+		t.Fatalf("LoadExportCursor() error = %v", err)
+	}
+	if !cursor.LastTimestamp().Equal(lastTimestamp) {
+		t.Errorf("LastTimestamp() = %v, want %v", cursor.LastTimestamp(), lastTimestamp)
+	}
+	if ids := cursor.LastIDs(); len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Errorf("LastIDs() = %v, want [a b]", ids)
+	}
+
+	// The temporary file is renamed over the state file
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("state directory has %d files, want only the state file", len(entries))
+	}
+}
+
+func TestFileExportStateRepository_LoadInvalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".ccmon-export.state")
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := NewFileExportStateRepository(path).LoadExportCursor(); err == nil {
+		t.Error("LoadExportCursor() error = nil, want an error for an invalid state file")
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// fileThrottleSignal is the JSON document of the throttle signal file
+type fileThrottleSignal struct {
+	GeneratedAt    time.Time `json:"generated_at"`
+	BlockStartAt   time.Time `json:"block_start_at"`
+	BlockEndAt     time.Time `json:"block_end_at"`
+	Tokens         int64     `json:"tokens"`        // rate limited tokens of the block
+	TokenLimit     int       `json:"token_limit"`   // 0 when the plan has no limit
+	UsagePercent   *float64  `json:"usage_percent"` // null without a limit
+	Threshold      float64   `json:"threshold"`
+	ShouldThrottle bool      `json:"should_throttle"`
+}
+
+// FileThrottleSignalRepository publishes the throttle signal as a JSON file for agent orchestrators to poll
+type FileThrottleSignalRepository struct {
+	path string
+}
+
+// NewFileThrottleSignalRepository creates a new FileThrottleSignalRepository writing the signal to path
+func NewFileThrottleSignalRepository(path string) *FileThrottleSignalRepository {
+	return &FileThrottleSignalRepository{
+		path: path,
+	}
+}
+
+// SaveThrottleSignal replaces the signal file through a temporary file, so pollers never read a partial document
+func (r *FileThrottleSignalRepository) SaveThrottleSignal(signal entity.ThrottleSignal) error {
+	block := signal.Block()
+	document := fileThrottleSignal{
+		GeneratedAt:    signal.GeneratedAt().UTC(),
+		BlockStartAt:   block.StartAt().UTC(),
+		BlockEndAt:     block.EndAt().UTC(),
+		Tokens:         signal.Tokens().Limited(),
+		TokenLimit:     block.TokenLimit(),
+		Threshold:      signal.Threshold(),
+		ShouldThrottle: signal.ShouldThrottle(),
+	}
+	if progress, ok := signal.Progress(); ok {
+		document.UsagePercent = &progress
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Orchestrators often run as another user, the signal holds no secrets
+	return writeFileAtomic(r.path, append(data, '\n'), 0644)
+}
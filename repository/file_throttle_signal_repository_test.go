@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestFileThrottleSignalRepository_SaveThrottleSignal(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		tokenLimit int
+		expected   map[string]any
+	}{
+		{
+			name:       "with a token limit",
+			tokenLimit: 7000,
+			expected: map[string]any{
+				"block_start_at":  "2025-06-01T05:00:00Z",
+				"block_end_at":    "2025-06-01T10:00:00Z",
+				"tokens":          float64(6300),
+				"token_limit":     float64(7000),
+				"usage_percent":   float64(90),
+				"should_throttle": true,
+			},
+		},
+		{
+			name: "without a token limit",
+			expected: map[string]any{
+				"token_limit":     float64(0),
+				"usage_percent":   nil,
+				"should_throttle": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := entity.NewThrottlePolicy(5, time.UTC, tt.tokenLimit, entity.DefaultThrottleThreshold)
+			if err != nil {
+				t.Fatalf("NewThrottlePolicy() error = %v", err)
+			}
+			signal := policy.Signal(policy.Block(now), entity.NewToken(6000, 300, 0, 0), now)
+
+			path := filepath.Join(t.TempDir(), "throttle.json")
+			if err := NewFileThrottleSignalRepository(path).SaveThrottleSignal(signal); err != nil {
+				t.Fatalf("SaveThrottleSignal() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			var document map[string]any
+			if err := json.Unmarshal(data, &document); err != nil {
+				t.Fatalf("Invalid signal file %q: %v", data, err)
+			}
+			for key, want := range tt.expected {
+				if document[key] != want {
+					t.Errorf("%s = %v, want %v", key, document[key], want)
+				}
+			}
+		})
+	}
+}
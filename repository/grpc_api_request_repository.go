@@ -4,12 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -19,22 +19,23 @@ type GRPCAPIRequestRepository struct {
 	conn   *grpc.ClientConn
 }
 
-// NewGRPCAPIRequestRepository creates a new gRPC repository instance
+// NewGRPCAPIRequestRepository creates a new gRPC repository instance with its own connection
 func NewGRPCAPIRequestRepository(serverAddress string) (*GRPCAPIRequestRepository, error) {
-	// Create connection with timeout
-	conn, err := grpc.NewClient(serverAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := NewGRPCConnection(serverAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+		return nil, err
 	}
 
-	client := pb.NewQueryServiceClient(conn)
+	repo := NewGRPCAPIRequestRepositoryWithConnection(conn)
+	repo.conn = conn.conn
+	return repo, nil
+}
 
+// NewGRPCAPIRequestRepositoryWithConnection creates a new gRPC repository instance on a shared connection
+func NewGRPCAPIRequestRepositoryWithConnection(conn *GRPCConnection) *GRPCAPIRequestRepository {
 	return &GRPCAPIRequestRepository{
-		client: client,
-		conn:   conn,
-	}, nil
+		client: conn.QueryClient(),
+	}
 }
 
 // Save is not supported in monitor mode (read-only repository)
@@ -46,38 +47,127 @@ func (r *GRPCAPIRequestRepository) Save(req entity.APIRequest) error {
 // Use limit = 0 for no limit (fetch all records)
 // Use offset = 0 when no offset is needed
 func (r *GRPCAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
-	// Convert entity.Period to protobuf timestamps
-	var startTime, endTime *timestamppb.Timestamp
+	return r.FindByFilter(entity.NewFilter(period), limit, offset)
+}
+
+// FindByFilter retrieves API requests matching the filter via gRPC with limit and offset
+// Servers predating filters return the whole period, the dimensions are applied again to the returned page
+func (r *GRPCAPIRequestRepository) FindByFilter(filter entity.Filter, limit int, offset int) ([]entity.APIRequest, error) {
+	req := newGetAPIRequestsRequest(filter, limit)
+	req.Offset = int32(offset)
+
+	requests, _, err := r.getAPIRequests(filter, req)
+	return requests, err
+}
+
+// FindPageByFilter retrieves the API requests matching the filter before the cursor via gRPC
+// The cursor is the page token of the server, servers predating page tokens return a single page without one
+func (r *GRPCAPIRequestRepository) FindPageByFilter(filter entity.Filter, limit int, cursor string) ([]entity.APIRequest, string, error) {
+	req := newGetAPIRequestsRequest(filter, limit)
+	req.PageToken = cursor
+
+	return r.getAPIRequests(filter, req)
+}
+
+// getAPIRequests calls GetAPIRequests and returns the requests with the next page token
+// Servers predating filters return the whole period, the dimensions are applied again to the returned page
+func (r *GRPCAPIRequestRepository) getAPIRequests(filter entity.Filter, req *pb.GetAPIRequestsRequest) ([]entity.APIRequest, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
+	resp, err := r.client.GetAPIRequests(ctx, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get API requests via gRPC: %w", classifyError(err))
+	}
+
+	// Convert protobuf responses to entities
+	entities := make([]entity.APIRequest, len(resp.Requests))
+	for i, pbReq := range resp.Requests {
+		entities[i] = convertProtoToAPIRequest(pbReq)
+	}
+
+	return filter.Apply(entities), resp.NextPageToken, nil
+}
+
+// newGetAPIRequestsRequest creates a GetAPIRequests request for the period and dimensions of the filter
+func newGetAPIRequestsRequest(filter entity.Filter, limit int) *pb.GetAPIRequestsRequest {
+	// Convert entity.Period to protobuf timestamps
+	period := filter.Period()
+	var startTime *timestamppb.Timestamp
 	if !period.IsAllTime() {
 		startTime = timestamppb.New(period.StartAt())
 	}
-	endTime = timestamppb.New(period.EndAt())
 
-	// Create gRPC request with timestamps, limit and offset
 	req := &pb.GetAPIRequestsRequest{
 		StartTime: startTime,
-		EndTime:   endTime,
+		EndTime:   timestamppb.New(period.EndAt()),
 		Limit:     int32(limit),
-		Offset:    int32(offset),
+	}
+	if filter.HasDimensions() {
+		req.Filter = convertFilterToProto(filter)
+	}
+	return req
+}
+
+// CountByFilter counts API requests matching the filter via gRPC
+// Servers predating counts return an Unimplemented error
+func (r *GRPCAPIRequestRepository) CountByFilter(filter entity.Filter) (int, error) {
+	period := filter.Period()
+	req := &pb.CountAPIRequestsRequest{
+		EndTime: timestamppb.New(period.EndAt()),
+	}
+	if !period.IsAllTime() {
+		req.StartTime = timestamppb.New(period.StartAt())
+	}
+	if filter.HasDimensions() {
+		req.Filter = convertFilterToProto(filter)
 	}
 
-	// Call gRPC service
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := r.client.GetAPIRequests(ctx, req)
+	resp, err := r.client.CountAPIRequests(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get API requests via gRPC: %w", err)
+		return 0, fmt.Errorf("failed to count API requests via gRPC: %w", classifyError(err))
 	}
 
-	// Convert protobuf responses to entities
-	entities := make([]entity.APIRequest, len(resp.Requests))
-	for i, pbReq := range resp.Requests {
-		entities[i] = convertProtoToAPIRequest(pbReq)
+	return int(resp.Count), nil
+}
+
+// WatchByFilter calls notify with each batch of stored requests matching the filter dimensions via a gRPC stream
+// Servers predating the stream and replicas return Unimplemented, callers keep polling instead
+func (r *GRPCAPIRequestRepository) WatchByFilter(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error {
+	req := &pb.WatchAPIRequestsRequest{}
+	if filter.HasDimensions() {
+		req.Filter = convertFilterToProto(filter)
+	}
+
+	stream, err := r.client.WatchAPIRequests(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to watch API requests via gRPC: %w", classifyError(err))
 	}
 
-	return entities, nil
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, io.EOF) {
+				// The server closes the stream when it shuts down, it is usually back after a restart
+				return entity.NewError(entity.ErrorKindConnection, "the ccmon server closed the API requests stream", err)
+			}
+			return fmt.Errorf("failed to watch API requests via gRPC: %w", classifyError(err))
+		}
+
+		requests := make([]entity.APIRequest, 0, len(resp.Requests))
+		for _, pbReq := range resp.Requests {
+			requests = append(requests, convertProtoToAPIRequest(pbReq))
+		}
+		if err := notify(requests); err != nil {
+			return err
+		}
+	}
 }
 
 // FindAll retrieves all API requests via gRPC
@@ -87,12 +177,15 @@ func (r *GRPCAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
 }
 
 // DeleteOlderThan is not supported in monitor mode (read-only repository)
-func (r *GRPCAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+func (r *GRPCAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
 	return 0, errors.New("delete operation not supported in monitor mode (read-only repository)")
 }
 
-// Close closes the gRPC connection
+// Close closes the gRPC connection owned by the repository, a shared connection is left open
 func (r *GRPCAPIRequestRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
 	return r.conn.Close()
 }
 
@@ -104,7 +197,7 @@ func convertProtoToAPIRequest(pbReq *pb.APIRequest) entity.APIRequest {
 		pbReq.OutputTokens,
 		pbReq.CacheReadTokens,
 		pbReq.CacheCreationTokens,
-	)
+	).WithToolUse(pbReq.ToolUseTokens)
 
 	// Create cost entity
 	cost := entity.NewCost(pbReq.CostUsd)
@@ -117,5 +210,30 @@ func convertProtoToAPIRequest(pbReq *pb.APIRequest) entity.APIRequest {
 		tokens,
 		cost,
 		pbReq.DurationMs,
-	)
+	).WithSource(pbReq.Source).WithOrigin(pbReq.Origin).WithUser(pbReq.User).WithProject(pbReq.Project).WithStar(convertProtoToStarScope(pbReq.Star))
+}
+
+// convertProtoToStarScope converts protobuf StarScope to entity.StarScope, unknown scopes are not starred
+func convertProtoToStarScope(scope pb.StarScope) entity.StarScope {
+	switch scope {
+	case pb.StarScope_STAR_SCOPE_REQUEST:
+		return entity.StarRequest
+	case pb.StarScope_STAR_SCOPE_SESSION:
+		return entity.StarSession
+	default:
+		return entity.StarNone
+	}
+}
+
+// convertFilterToProto converts the dimensions of entity.Filter to protobuf RequestFilter
+func convertFilterToProto(filter entity.Filter) *pb.RequestFilter {
+	return &pb.RequestFilter{
+		Model:     filter.Model(),
+		SessionId: filter.SessionID(),
+		Source:    filter.Source(),
+		Origin:    filter.Origin(),
+		User:      filter.User(),
+		Project:   filter.Project(),
+		Search:    filter.Search(),
+	}
 }
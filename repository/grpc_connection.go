@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCConnection is a client connection to a ccmon server shared by the gRPC repositories
+// Dial options such as TLS credentials are configured once here instead of per repository
+type GRPCConnection struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCConnection creates a connection to the server
+// Insecure transport credentials are used unless overridden by the given dial options
+func NewGRPCConnection(serverAddress string, opts ...grpc.DialOption) (*GRPCConnection, error) {
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.NewClient(serverAddress, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	return &GRPCConnection{conn: conn}, nil
+}
+
+// WithToken presents the token as a bearer token on every call, servers with server.tokens reject calls without one
+// An empty token sends nothing, so servers without tokens keep working
+func WithToken(token string) grpc.DialOption {
+	if token == "" {
+		return grpc.EmptyDialOption{}
+	}
+	return grpc.WithPerRPCCredentials(bearerToken(token))
+}
+
+// WithTLS connects over TLS instead of the insecure default, verifying the server certificate against the CA file
+// An empty CA file uses the system roots, the client certificate is only presented when set
+func WithTLS(caFile, certFile, keyFile string) (grpc.DialOption, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in CA file %s", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(config)), nil
+}
+
+// bearerToken sends the token in the authorization metadata
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity allows the token over insecure connections, like the snapshot token of replicas
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// QueryClient returns a QueryService client using the shared connection
+func (c *GRPCConnection) QueryClient() pb.QueryServiceClient {
+	return pb.NewQueryServiceClient(c.conn)
+}
+
+// StarClient returns a StarService client using the shared connection
+func (c *GRPCConnection) StarClient() pb.StarServiceClient {
+	return pb.NewStarServiceClient(c.conn)
+}
+
+// Close closes the shared connection, repositories created from it must not be used afterwards
+func (c *GRPCConnection) Close() error {
+	return c.conn.Close()
+}
+
+// classifyError wraps errors of a call with the kind users can act on, the gRPC status is kept for status.Code
+// Queries only take a period, so invalid arguments are invalid periods
+func classifyError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return entity.NewError(entity.ErrorKindConnection, "cannot connect to the ccmon server, start it with `ccmon -s` or check monitor.server", err)
+	case codes.DeadlineExceeded:
+		return entity.NewError(entity.ErrorKindConnection, "the ccmon server did not answer in time", err)
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return entity.NewError(entity.ErrorKindAuth, "the ccmon server rejected the credentials", err)
+	case codes.NotFound:
+		return entity.NewError(entity.ErrorKindNotFound, status.Convert(err).Message(), err)
+	case codes.InvalidArgument:
+		return entity.NewError(entity.ErrorKindInvalidPeriod, status.Convert(err).Message(), err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCConnection_SharedByRepositories(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterQueryServiceServer(server, &MockServerMetricsServer{
+		lag: &pb.IngestionLag{Samples: 2, AverageMs: 100, MaxMs: 200},
+	})
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := NewGRPCConnection("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+
+	first := NewGRPCIngestionLagRepositoryWithConnection(conn)
+	second := NewGRPCIngestionLagRepositoryWithConnection(conn)
+
+	if _, err := first.GetIngestionLag(); err != nil {
+		t.Fatalf("Unexpected error from first repository: %v", err)
+	}
+
+	if err := first.Close(); err != nil {
+		t.Fatalf("Unexpected error closing repository: %v", err)
+	}
+
+	lag, err := second.GetIngestionLag()
+	if err != nil {
+		t.Fatalf("Expected shared connection to stay open after repository close, got: %v", err)
+	}
+	if lag.Samples() != 2 {
+		t.Errorf("Expected 2 samples, got %d", lag.Samples())
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Unexpected error closing connection: %v", err)
+	}
+
+	if _, err := second.GetIngestionLag(); err == nil {
+		t.Error("Expected error after closing the shared connection")
+	}
+}
+
+func TestGRPCConnection_WithToken(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		expected []string
+	}{
+		{name: "bearer token", token: "read-secret", expected: []string{"Bearer read-secret"}},
+		{name: "no token", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authorization []string
+			listener := bufconn.Listen(1024 * 1024)
+			server := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				md, _ := metadata.FromIncomingContext(ctx)
+				authorization = md.Get("authorization")
+				return handler(ctx, req)
+			}))
+			pb.RegisterQueryServiceServer(server, &MockServerMetricsServer{lag: &pb.IngestionLag{}})
+			go func() {
+				_ = server.Serve(listener) // Expected to fail when test completes
+			}()
+			t.Cleanup(server.Stop)
+
+			conn, err := NewGRPCConnection("passthrough://bufnet",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+					return listener.Dial()
+				}),
+				WithToken(tt.token),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create connection: %v", err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+
+			if _, err := NewGRPCIngestionLagRepositoryWithConnection(conn).GetIngestionLag(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(authorization, tt.expected) {
+				t.Errorf("Expected authorization %v, got %v", tt.expected, authorization)
+			}
+		})
+	}
+}
+
+func TestGRPCConnection_WithTLS(t *testing.T) {
+	files := testutil.WriteTLSFiles(t)
+
+	tests := []struct {
+		name              string
+		requireClientCert bool
+		clientCert        bool
+		insecure          bool
+		wantErr           bool
+	}{
+		{name: "server certificate verified by the CA"},
+		{name: "client certificate presented", requireClientCert: true, clientCert: true},
+		{name: "client certificate missing", requireClientCert: true, wantErr: true},
+		{name: "cleartext client", insecure: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert, err := tls.LoadX509KeyPair(files.CertFile, files.KeyFile)
+			if err != nil {
+				t.Fatalf("Failed to load certificate: %v", err)
+			}
+			serverTLS := &tls.Config{Certificates: []tls.Certificate{cert}}
+			if tt.requireClientCert {
+				pem, err := os.ReadFile(files.CAFile)
+				if err != nil {
+					t.Fatalf("Failed to read CA: %v", err)
+				}
+				serverTLS.ClientCAs = x509.NewCertPool()
+				serverTLS.ClientCAs.AppendCertsFromPEM(pem)
+				serverTLS.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverTLS)))
+			pb.RegisterQueryServiceServer(server, &MockServerMetricsServer{lag: &pb.IngestionLag{}})
+			go func() {
+				_ = server.Serve(listener) // Expected to fail when test completes
+			}()
+			t.Cleanup(server.Stop)
+
+			var opts []grpc.DialOption
+			if !tt.insecure {
+				certFile, keyFile := "", ""
+				if tt.clientCert {
+					certFile, keyFile = files.CertFile, files.KeyFile
+				}
+				tlsOpt, err := WithTLS(files.CAFile, certFile, keyFile)
+				if err != nil {
+					t.Fatalf("Failed to configure TLS: %v", err)
+				}
+				opts = append(opts, tlsOpt)
+			}
+			conn, err := NewGRPCConnection(listener.Addr().String(), opts...)
+			if err != nil {
+				t.Fatalf("Failed to create connection: %v", err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = NewGRPCIngestionLagRepositoryWithConnection(conn).GetIngestionLag()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithTLS_InvalidFiles(t *testing.T) {
+	files := testutil.WriteTLSFiles(t)
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		caFile   string
+		certFile string
+		keyFile  string
+	}{
+		{name: "missing CA file", caFile: filepath.Join(t.TempDir(), "missing.pem")},
+		{name: "CA file without certificate", caFile: empty},
+		{name: "key not matching the certificate", caFile: files.CAFile, certFile: files.CertFile, keyFile: empty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := WithTLS(tt.caFile, tt.certFile, tt.keyFile); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind entity.ErrorKind
+	}{
+		{name: "server down", err: status.Error(codes.Unavailable, "connection refused"), kind: entity.ErrorKindConnection},
+		{name: "timeout", err: status.Error(codes.DeadlineExceeded, "context deadline exceeded"), kind: entity.ErrorKindConnection},
+		{name: "rejected credentials", err: status.Error(codes.Unauthenticated, "invalid token"), kind: entity.ErrorKindAuth},
+		{name: "not found", err: status.Error(codes.NotFound, "no such record"), kind: entity.ErrorKindNotFound},
+		{name: "invalid period", err: status.Error(codes.InvalidArgument, "invalid period"), kind: entity.ErrorKindInvalidPeriod},
+		{name: "server failure", err: status.Error(codes.Internal, "disk full"), kind: entity.ErrorKindUnknown},
+		{name: "other error", err: errors.New("boom"), kind: entity.ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			if kind := entity.ErrorKindOf(err); kind != tt.kind {
+				t.Errorf("Expected kind %q, got %q", tt.kind, kind)
+			}
+			// The gRPC status stays available, e.g. for Unimplemented fallbacks
+			if status.Code(err) != status.Code(tt.err) {
+				t.Errorf("Expected status code %v to be kept, got %v", status.Code(tt.err), status.Code(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCIngestStatsRepository implements usecase.IngestStatsRepository using gRPC GetIngestStats call
+type GRPCIngestStatsRepository struct {
+	client pb.QueryServiceClient
+}
+
+// NewGRPCIngestStatsRepositoryWithConnection creates a new gRPC ingest stats repository instance on a shared connection
+func NewGRPCIngestStatsRepositoryWithConnection(conn *GRPCConnection) *GRPCIngestStatsRepository {
+	return &GRPCIngestStatsRepository{
+		client: conn.QueryClient(),
+	}
+}
+
+// GetIngestStats retrieves the outcomes of the received events via gRPC GetIngestStats
+// Replicas and servers predating GetIngestStats report empty stats instead of an error
+func (r *GRPCIngestStatsRepository) GetIngestStats() (entity.IngestStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := r.client.GetIngestStats(ctx, &pb.GetIngestStatsRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return entity.IngestStats{}, nil
+	}
+	if err != nil {
+		return entity.IngestStats{}, fmt.Errorf("failed to get ingest stats via gRPC: %w", classifyError(err))
+	}
+
+	var since time.Time
+	if resp.Since != nil {
+		since = resp.Since.AsTime()
+	}
+	return entity.NewIngestStats(
+		convertProtoToIngestCounts(resp.LastHour),
+		convertProtoToIngestCounts(resp.LastDay),
+		since,
+	), nil
+}
+
+// convertProtoToIngestCounts converts protobuf IngestCounts to entity.IngestCounts
+func convertProtoToIngestCounts(pbCounts *pb.IngestCounts) entity.IngestCounts {
+	if pbCounts == nil {
+		return entity.IngestCounts{}
+	}
+
+	return entity.NewIngestCounts(
+		pbCounts.Accepted,
+		pbCounts.Ignored,
+		pbCounts.Dropped,
+		pbCounts.Malformed,
+		pbCounts.Duplicated,
+		pbCounts.Failed,
+	)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MockIngestStatsServer for testing GRPCIngestStatsRepository
+type MockIngestStatsServer struct {
+	pb.UnimplementedQueryServiceServer
+	resp *pb.GetIngestStatsResponse
+	err  error
+}
+
+func (m *MockIngestStatsServer) GetIngestStats(ctx context.Context, req *pb.GetIngestStatsRequest) (*pb.GetIngestStatsResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.resp, nil
+}
+
+// createGRPCIngestStatsRepository creates a GRPCIngestStatsRepository connected to a server with the given service
+func createGRPCIngestStatsRepository(t *testing.T, service pb.QueryServiceServer) *GRPCIngestStatsRepository {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterQueryServiceServer(server, service)
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := NewGRPCConnection("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("Failed to close connection: %v", err)
+		}
+	})
+
+	return NewGRPCIngestStatsRepositoryWithConnection(conn)
+}
+
+func TestGRPCIngestStatsRepository_GetIngestStats(t *testing.T) {
+	since := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		service          pb.QueryServiceServer
+		expectError      bool
+		expectedHour     int64
+		expectedMissing  int64
+		expectedDayTotal int64
+		expectedSince    time.Time
+	}{
+		{
+			name: "server reports stats",
+			service: &MockIngestStatsServer{
+				resp: &pb.GetIngestStatsResponse{
+					LastHour: &pb.IngestCounts{Accepted: 8, Ignored: 2},
+					LastDay:  &pb.IngestCounts{Accepted: 40, Ignored: 5, Dropped: 1, Malformed: 3, Duplicated: 2, Failed: 1},
+					Since:    timestamppb.New(since),
+				},
+			},
+			expectedHour:     10,
+			expectedMissing:  12,
+			expectedDayTotal: 52,
+			expectedSince:    since,
+		},
+		{
+			name:    "server without counts",
+			service: &MockIngestStatsServer{resp: &pb.GetIngestStatsResponse{}},
+		},
+		{
+			name:    "server without GetIngestStats",
+			service: &LegacyQueryServiceServer{},
+		},
+		{
+			name:        "server error",
+			service:     &MockIngestStatsServer{err: fmt.Errorf("internal error")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := createGRPCIngestStatsRepository(t, tt.service)
+
+			stats, err := repo.GetIngestStats()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if stats.LastHour().Total() != tt.expectedHour {
+				t.Errorf("Expected %d events in the last hour, got %d", tt.expectedHour, stats.LastHour().Total())
+			}
+			if stats.LastDay().Total() != tt.expectedDayTotal {
+				t.Errorf("Expected %d events in the last day, got %d", tt.expectedDayTotal, stats.LastDay().Total())
+			}
+			if stats.LastDay().Missing() != tt.expectedMissing {
+				t.Errorf("Expected %d missing events in the last day, got %d", tt.expectedMissing, stats.LastDay().Missing())
+			}
+			if !stats.Since().Equal(tt.expectedSince) {
+				t.Errorf("Expected since %v, got %v", tt.expectedSince, stats.Since())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCIngestionLagRepository implements usecase.IngestionLagRepository using gRPC GetServerMetrics call
+type GRPCIngestionLagRepository struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCIngestionLagRepository creates a new gRPC ingestion lag repository instance with its own connection
+func NewGRPCIngestionLagRepository(serverAddress string) (*GRPCIngestionLagRepository, error) {
+	conn, err := NewGRPCConnection(serverAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := NewGRPCIngestionLagRepositoryWithConnection(conn)
+	repo.conn = conn.conn
+	return repo, nil
+}
+
+// NewGRPCIngestionLagRepositoryWithConnection creates a new gRPC ingestion lag repository instance on a shared connection
+func NewGRPCIngestionLagRepositoryWithConnection(conn *GRPCConnection) *GRPCIngestionLagRepository {
+	return &GRPCIngestionLagRepository{
+		client: conn.QueryClient(),
+	}
+}
+
+// GetIngestionLag retrieves the server ingestion lag via gRPC GetServerMetrics
+// Servers predating GetServerMetrics report an empty lag instead of an error
+func (r *GRPCIngestionLagRepository) GetIngestionLag() (entity.IngestionLag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := r.client.GetServerMetrics(ctx, &pb.GetServerMetricsRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return entity.IngestionLag{}, nil
+	}
+	if err != nil {
+		return entity.IngestionLag{}, fmt.Errorf("failed to get server metrics via gRPC: %w", classifyError(err))
+	}
+
+	return convertProtoToIngestionLag(resp.IngestionLag), nil
+}
+
+// Close closes the gRPC connection owned by the repository, a shared connection is left open
+func (r *GRPCIngestionLagRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+	return r.conn.Close()
+}
+
+// convertProtoToIngestionLag converts protobuf IngestionLag to entity.IngestionLag
+func convertProtoToIngestionLag(pbLag *pb.IngestionLag) entity.IngestionLag {
+	if pbLag == nil {
+		return entity.IngestionLag{}
+	}
+
+	// Only the average is sent, the total is restored from it
+	average := time.Duration(pbLag.AverageMs) * time.Millisecond
+	return entity.NewIngestionLag(
+		pbLag.Samples,
+		average*time.Duration(pbLag.Samples),
+		time.Duration(pbLag.MaxMs)*time.Millisecond,
+	)
+}
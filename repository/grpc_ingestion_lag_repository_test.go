@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MockServerMetricsServer for testing GRPCIngestionLagRepository
+type MockServerMetricsServer struct {
+	pb.UnimplementedQueryServiceServer
+	lag       *pb.IngestionLag
+	retention *pb.Retention
+	err       error
+}
+
+func (m *MockServerMetricsServer) GetServerMetrics(ctx context.Context, req *pb.GetServerMetricsRequest) (*pb.GetServerMetricsResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return &pb.GetServerMetricsResponse{
+		IngestionLag: m.lag,
+		Retention:    m.retention,
+	}, nil
+}
+
+// createGRPCIngestionLagRepository creates a GRPCIngestionLagRepository connected to a server with the given service
+func createGRPCIngestionLagRepository(t *testing.T, service pb.QueryServiceServer) *GRPCIngestionLagRepository {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterQueryServiceServer(server, service)
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	repo := &GRPCIngestionLagRepository{
+		client: pb.NewQueryServiceClient(conn),
+		conn:   conn,
+	}
+	t.Cleanup(func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close repository: %v", err)
+		}
+	})
+	return repo
+}
+
+func TestGRPCIngestionLagRepository_GetIngestionLag(t *testing.T) {
+	tests := []struct {
+		name            string
+		service         pb.QueryServiceServer
+		expectError     bool
+		expectedSamples int64
+		expectedAverage time.Duration
+		expectedMax     time.Duration
+	}{
+		{
+			name: "server reports lag",
+			service: &MockServerMetricsServer{
+				lag: &pb.IngestionLag{Samples: 4, AverageMs: 2500, MaxMs: 6000},
+			},
+			expectedSamples: 4,
+			expectedAverage: 2500 * time.Millisecond,
+			expectedMax:     6 * time.Second,
+		},
+		{
+			name:    "server without lag field",
+			service: &MockServerMetricsServer{},
+		},
+		{
+			name:    "legacy server without GetServerMetrics",
+			service: &LegacyQueryServiceServer{},
+		},
+		{
+			name:        "server error",
+			service:     &MockServerMetricsServer{err: fmt.Errorf("internal error")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := createGRPCIngestionLagRepository(t, tt.service)
+
+			lag, err := repo.GetIngestionLag()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if lag.Samples() != tt.expectedSamples {
+				t.Errorf("Expected %d samples, got %d", tt.expectedSamples, lag.Samples())
+			}
+			if lag.Average() != tt.expectedAverage {
+				t.Errorf("Expected average %v, got %v", tt.expectedAverage, lag.Average())
+			}
+			if lag.Max() != tt.expectedMax {
+				t.Errorf("Expected max %v, got %v", tt.expectedMax, lag.Max())
+			}
+		})
+	}
+}
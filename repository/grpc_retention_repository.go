@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCRetentionRepository implements usecase.RetentionRepository using gRPC GetServerMetrics call
+type GRPCRetentionRepository struct {
+	client pb.QueryServiceClient
+	conn   *grpc.ClientConn
+}
+
+// NewGRPCRetentionRepository creates a new gRPC retention repository instance with its own connection
+func NewGRPCRetentionRepository(serverAddress string) (*GRPCRetentionRepository, error) {
+	conn, err := NewGRPCConnection(serverAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := NewGRPCRetentionRepositoryWithConnection(conn)
+	repo.conn = conn.conn
+	return repo, nil
+}
+
+// NewGRPCRetentionRepositoryWithConnection creates a new gRPC retention repository instance on a shared connection
+func NewGRPCRetentionRepositoryWithConnection(conn *GRPCConnection) *GRPCRetentionRepository {
+	return &GRPCRetentionRepository{
+		client: conn.QueryClient(),
+	}
+}
+
+// GetRetention retrieves the server retention policy via gRPC GetServerMetrics
+// Servers predating retention preview report a disabled retention instead of an error
+func (r *GRPCRetentionRepository) GetRetention() (entity.Retention, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := r.client.GetServerMetrics(ctx, &pb.GetServerMetricsRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return entity.Retention{}, nil
+	}
+	if err != nil {
+		return entity.Retention{}, fmt.Errorf("failed to get server metrics via gRPC: %w", classifyError(err))
+	}
+
+	return convertProtoToRetention(resp.Retention), nil
+}
+
+// Close closes the gRPC connection owned by the repository, a shared connection is left open
+func (r *GRPCRetentionRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+	return r.conn.Close()
+}
+
+// convertProtoToRetention converts protobuf Retention to entity.Retention
+func convertProtoToRetention(pbRetention *pb.Retention) entity.Retention {
+	if pbRetention == nil {
+		return entity.Retention{}
+	}
+
+	var nextCleanupAt time.Time
+	if pbRetention.NextCleanupAt != nil {
+		nextCleanupAt = pbRetention.NextCleanupAt.AsTime()
+	}
+	return entity.NewRetention(time.Duration(pbRetention.DurationMs)*time.Millisecond, nextCleanupAt)
+}
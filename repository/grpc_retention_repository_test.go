@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// createGRPCRetentionRepository creates a GRPCRetentionRepository connected to a server with the given service
+func createGRPCRetentionRepository(t *testing.T, service pb.QueryServiceServer) *GRPCRetentionRepository {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterQueryServiceServer(server, service)
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := NewGRPCConnection("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("Failed to close connection: %v", err)
+		}
+	})
+
+	return NewGRPCRetentionRepositoryWithConnection(conn)
+}
+
+func TestGRPCRetentionRepository_GetRetention(t *testing.T) {
+	nextCleanupAt := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		service           pb.QueryServiceServer
+		expectError       bool
+		expectedDuration  time.Duration
+		expectedNextRunAt time.Time
+	}{
+		{
+			name: "server reports scheduled retention",
+			service: &MockServerMetricsServer{
+				retention: &pb.Retention{
+					DurationMs:    (30 * 24 * time.Hour).Milliseconds(),
+					NextCleanupAt: timestamppb.New(nextCleanupAt),
+				},
+			},
+			expectedDuration:  30 * 24 * time.Hour,
+			expectedNextRunAt: nextCleanupAt,
+		},
+		{
+			name: "server reports retention before first cleanup",
+			service: &MockServerMetricsServer{
+				retention: &pb.Retention{DurationMs: (24 * time.Hour).Milliseconds()},
+			},
+			expectedDuration: 24 * time.Hour,
+		},
+		{
+			name:    "server without retention field",
+			service: &MockServerMetricsServer{},
+		},
+		{
+			name:    "legacy server without GetServerMetrics",
+			service: &LegacyQueryServiceServer{},
+		},
+		{
+			name:        "server error",
+			service:     &MockServerMetricsServer{err: fmt.Errorf("internal error")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := createGRPCRetentionRepository(t, tt.service)
+
+			retention, err := repo.GetRetention()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if retention.Duration() != tt.expectedDuration {
+				t.Errorf("Expected duration %v, got %v", tt.expectedDuration, retention.Duration())
+			}
+			if !retention.NextCleanupAt().Equal(tt.expectedNextRunAt) {
+				t.Errorf("Expected next cleanup at %v, got %v", tt.expectedNextRunAt, retention.NextCleanupAt())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GRPCSnapshotRepository implements usecase.SnapshotRepository by pulling the snapshot from a primary server
+type GRPCSnapshotRepository struct {
+	client pb.ReplicationServiceClient
+	conn   *grpc.ClientConn
+	token  string
+}
+
+// NewGRPCSnapshotRepository creates a new gRPC snapshot repository authenticated with the given token
+// Insecure transport credentials are used unless overridden by the given dial options, e.g. WithTLS
+func NewGRPCSnapshotRepository(serverAddress string, token string, opts ...grpc.DialOption) (*GRPCSnapshotRepository, error) {
+	// Create connection
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.NewClient(serverAddress, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+	}
+
+	client := pb.NewReplicationServiceClient(conn)
+
+	return &GRPCSnapshotRepository{
+		client: client,
+		conn:   conn,
+		token:  token,
+	}, nil
+}
+
+// WriteSnapshot receives the snapshot chunks via gRPC GetSnapshot and writes them to w
+func (r *GRPCSnapshotRepository) WriteSnapshot(ctx context.Context, w io.Writer) (int64, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+r.token)
+
+	stream, err := r.client.GetSnapshot(ctx, &pb.GetSnapshotRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get snapshot via gRPC: %w", err)
+	}
+
+	var size int64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return size, fmt.Errorf("failed to receive snapshot via gRPC: %w", err)
+		}
+
+		n, err := w.Write(chunk.Data)
+		size += int64(n)
+		if err != nil {
+			return size, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+}
+
+// Close closes the gRPC connection
+func (r *GRPCSnapshotRepository) Close() error {
+	return r.conn.Close()
+}
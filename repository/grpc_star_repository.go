@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrStarUnsupported is returned when the server does not accept stars, e.g. replicas and servers predating stars
+var ErrStarUnsupported = errors.New("starring is not supported by the server")
+
+// GRPCStarRepository implements usecase.StarRepository using the gRPC StarService
+type GRPCStarRepository struct {
+	client pb.StarServiceClient
+}
+
+// NewGRPCStarRepositoryWithConnection creates a new gRPC star repository instance on a shared connection
+func NewGRPCStarRepositoryWithConnection(conn *GRPCConnection) *GRPCStarRepository {
+	return &GRPCStarRepository{
+		client: conn.StarClient(),
+	}
+}
+
+// GetStars is not supported in monitor mode, requests from the server are already marked as starred
+func (r *GRPCStarRepository) GetStars() (entity.Stars, error) {
+	return entity.Stars{}, errors.New("listing stars not supported in monitor mode")
+}
+
+// SetStar stars the request or its whole session via gRPC SetStar
+func (r *GRPCStarRepository) SetStar(requestID string, sessionID string, scope entity.StarScope) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.client.SetStar(ctx, &pb.SetStarRequest{
+		RequestId: requestID,
+		SessionId: sessionID,
+		Scope:     convertStarScopeToProto(scope),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return ErrStarUnsupported
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set star via gRPC: %w", err)
+	}
+
+	return nil
+}
+
+// convertStarScopeToProto converts entity.StarScope to protobuf StarScope
+func convertStarScopeToProto(scope entity.StarScope) pb.StarScope {
+	switch scope {
+	case entity.StarRequest:
+		return pb.StarScope_STAR_SCOPE_REQUEST
+	case entity.StarSession:
+		return pb.StarScope_STAR_SCOPE_SESSION
+	default:
+		return pb.StarScope_STAR_SCOPE_UNSPECIFIED
+	}
+}
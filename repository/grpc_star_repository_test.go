@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/elct9620/ccmon/entity"
+	pb "github.com/elct9620/ccmon/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MockStarServer records the last SetStar request
+type MockStarServer struct {
+	pb.UnimplementedStarServiceServer
+	lastRequest *pb.SetStarRequest
+}
+
+func (m *MockStarServer) SetStar(ctx context.Context, req *pb.SetStarRequest) (*pb.SetStarResponse, error) {
+	m.lastRequest = req
+	return &pb.SetStarResponse{}, nil
+}
+
+// createGRPCStarRepository creates a GRPCStarRepository connected to a server, the star service is not registered when nil
+func createGRPCStarRepository(t *testing.T, service pb.StarServiceServer) *GRPCStarRepository {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	if service != nil {
+		pb.RegisterStarServiceServer(server, service)
+	}
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := NewGRPCConnection("passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := conn.Close(); err != nil {
+			t.Logf("Failed to close connection: %v", err)
+		}
+	})
+
+	return NewGRPCStarRepositoryWithConnection(conn)
+}
+
+func TestGRPCStarRepository_SetStar(t *testing.T) {
+	tests := []struct {
+		scope         entity.StarScope
+		expectedScope pb.StarScope
+	}{
+		{scope: entity.StarRequest, expectedScope: pb.StarScope_STAR_SCOPE_REQUEST},
+		{scope: entity.StarSession, expectedScope: pb.StarScope_STAR_SCOPE_SESSION},
+		{scope: entity.StarNone, expectedScope: pb.StarScope_STAR_SCOPE_UNSPECIFIED},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scope.String(), func(t *testing.T) {
+			service := &MockStarServer{}
+			repo := createGRPCStarRepository(t, service)
+
+			if err := repo.SetStar("request-1", "session-1", tt.scope); err != nil {
+				t.Fatalf("SetStar() error = %v", err)
+			}
+
+			if service.lastRequest.RequestId != "request-1" || service.lastRequest.SessionId != "session-1" {
+				t.Errorf("Expected request-1 of session-1, got %s of %s", service.lastRequest.RequestId, service.lastRequest.SessionId)
+			}
+			if service.lastRequest.Scope != tt.expectedScope {
+				t.Errorf("Expected scope %v, got %v", tt.expectedScope, service.lastRequest.Scope)
+			}
+		})
+	}
+}
+
+func TestGRPCStarRepository_SetStarUnsupported(t *testing.T) {
+	repo := createGRPCStarRepository(t, nil)
+
+	err := repo.SetStar("request-1", "session-1", entity.StarRequest)
+	if !errors.Is(err, ErrStarUnsupported) {
+		t.Errorf("Expected ErrStarUnsupported, got %v", err)
+	}
+}
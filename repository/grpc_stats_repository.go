@@ -7,8 +7,10 @@ import (
 
 	"github.com/elct9620/ccmon/entity"
 	pb "github.com/elct9620/ccmon/proto"
+	"github.com/elct9620/ccmon/usecase"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -19,26 +21,52 @@ type GRPCStatsRepository struct {
 	conn   *grpc.ClientConn
 }
 
-// NewGRPCStatsRepository creates a new gRPC stats repository instance
+// NewGRPCStatsRepository creates a new gRPC stats repository instance with its own connection
 func NewGRPCStatsRepository(serverAddress string) (*GRPCStatsRepository, error) {
-	// Create connection
-	conn, err := grpc.NewClient(serverAddress,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := NewGRPCConnection(serverAddress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", serverAddress, err)
+		return nil, err
 	}
 
-	client := pb.NewQueryServiceClient(conn)
+	repo := NewGRPCStatsRepositoryWithConnection(conn)
+	repo.conn = conn.conn
+	return repo, nil
+}
 
+// NewGRPCStatsRepositoryWithConnection creates a new gRPC stats repository instance on a shared connection
+func NewGRPCStatsRepositoryWithConnection(conn *GRPCConnection) *GRPCStatsRepository {
 	return &GRPCStatsRepository{
-		client: client,
-		conn:   conn,
-	}, nil
+		client: conn.QueryClient(),
+	}
 }
 
 // GetStatsByPeriod retrieves stats for a given period via gRPC GetStats
 func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	return r.GetStatsByFilter(entity.NewFilter(period))
+}
+
+// GetStatsByFilter retrieves stats of the filter origin, project and user via gRPC GetStats, other dimensions are not sent
+// Servers predating origins, projects or per-user stats ignore them and return the stats of every request
+func (r *GRPCStatsRepository) GetStatsByFilter(filter entity.Filter) (entity.Stats, error) {
+	return r.getStats(filter, false, false)
+}
+
+// EstimateStatsByPeriod retrieves stats for a given period via gRPC GetStats, allowing the server to estimate them
+// Servers predating estimates return exact stats
+func (r *GRPCStatsRepository) EstimateStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	return r.getStats(entity.NewFilter(period), true, false)
+}
+
+// GetFreshStatsByPeriod retrieves stats for a given period via gRPC GetStats, skipping the server stats cache
+// Servers predating it may return cached stats
+func (r *GRPCStatsRepository) GetFreshStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	return r.getStats(entity.NewFilter(period), false, true)
+}
+
+// getStats retrieves stats of the filter via gRPC GetStats
+func (r *GRPCStatsRepository) getStats(filter entity.Filter, approximate bool, fresh bool) (entity.Stats, error) {
+	period := filter.Period()
+
 	// Convert entity.Period to protobuf timestamps
 	var startTime, endTime *timestamppb.Timestamp
 
@@ -49,8 +77,13 @@ func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Sta
 
 	// Create gRPC request
 	req := &pb.GetStatsRequest{
-		StartTime: startTime,
-		EndTime:   endTime,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Origin:      filter.Origin(),
+		Project:     filter.Project(),
+		User:        filter.User(),
+		Approximate: approximate,
+		Fresh:       fresh,
 	}
 
 	// Call gRPC service
@@ -59,15 +92,49 @@ func (r *GRPCStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Sta
 
 	resp, err := r.client.GetStats(ctx, req)
 	if err != nil {
-		return entity.Stats{}, fmt.Errorf("failed to get stats via gRPC: %w", err)
+		return entity.Stats{}, fmt.Errorf("failed to get stats via gRPC: %w", classifyError(err))
 	}
 
 	// Convert protobuf response to entity
-	return convertProtoToStats(resp.Stats, period), nil
+	stats := convertProtoToStats(resp.Stats, period).WithApproximate(resp.Approximate)
+	if resp.CachedAt != nil {
+		stats = stats.WithCachedAt(resp.CachedAt.AsTime())
+	}
+	return stats, nil
+}
+
+// SupportsGetStats reports whether the connected server implements the GetStats RPC
+// Only an Unimplemented response is treated as unsupported, so an unreachable server
+// keeps using GetStats and recovers once it comes back
+func (r *GRPCStatsRepository) SupportsGetStats(ctx context.Context) bool {
+	// Probe with an empty period to keep the server-side work minimal
+	now := timestamppb.Now()
+	_, err := r.client.GetStats(ctx, &pb.GetStatsRequest{
+		StartTime: now,
+		EndTime:   now,
+	})
+	return status.Code(err) != codes.Unimplemented
 }
 
-// Close closes the gRPC connection
+// NegotiateStatsRepository returns the gRPC stats repository when the server supports GetStats,
+// otherwise it falls back to fetching requests and aggregating them on the client side
+// so a newer monitor can still connect to an older server
+func NegotiateStatsRepository(statsRepository *GRPCStatsRepository, apiRequestRepository usecase.APIRequestRepository) usecase.StatsRepository {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if statsRepository.SupportsGetStats(ctx) {
+		return statsRepository
+	}
+
+	return NewBoltDBStatsRepository(apiRequestRepository)
+}
+
+// Close closes the gRPC connection owned by the repository, a shared connection is left open
 func (r *GRPCStatsRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
 	return r.conn.Close()
 }
 
@@ -79,19 +146,35 @@ func convertProtoToStats(pbStats *pb.Stats, period entity.Period) entity.Stats {
 		pbStats.BaseTokens.Output,
 		pbStats.BaseTokens.CacheRead,
 		pbStats.BaseTokens.CacheCreation,
-	)
+	).WithToolUse(pbStats.BaseTokens.ToolUse)
 
 	premiumTokens := entity.NewToken(
 		pbStats.PremiumTokens.Input,
 		pbStats.PremiumTokens.Output,
 		pbStats.PremiumTokens.CacheRead,
 		pbStats.PremiumTokens.CacheCreation,
-	)
+	).WithToolUse(pbStats.PremiumTokens.ToolUse)
 
 	// Convert protobuf costs to entities
 	baseCost := entity.NewCost(pbStats.BaseCost.Amount)
 	premiumCost := entity.NewCost(pbStats.PremiumCost.Amount)
 
+	// 1M context fields are absent when the server predates the long context tier
+	var longContextTokens entity.Token
+	if pbStats.LongContextTokens != nil {
+		longContextTokens = entity.NewToken(
+			pbStats.LongContextTokens.Input,
+			pbStats.LongContextTokens.Output,
+			pbStats.LongContextTokens.CacheRead,
+			pbStats.LongContextTokens.CacheCreation,
+		).WithToolUse(pbStats.LongContextTokens.ToolUse)
+	}
+
+	var longContextCost entity.Cost
+	if pbStats.LongContextCost != nil {
+		longContextCost = entity.NewCost(pbStats.LongContextCost.Amount)
+	}
+
 	// Create stats entity
 	return entity.NewStats(
 		int(pbStats.BaseRequests),
@@ -101,5 +184,20 @@ func convertProtoToStats(pbStats *pb.Stats, period entity.Period) entity.Stats {
 		baseCost,
 		premiumCost,
 		period,
-	)
+	).WithLongContext(int(pbStats.LongContextRequests), longContextTokens, longContextCost).WithModels(convertProtoToModelStats(pbStats.Models))
+}
+
+// convertProtoToModelStats converts protobuf ModelStats to entities, servers predating the breakdown send none
+func convertProtoToModelStats(pbModels []*pb.ModelStats) []entity.ModelStats {
+	models := make([]entity.ModelStats, 0, len(pbModels))
+	for _, pbModel := range pbModels {
+		tokens := pbModel.GetTokens()
+		models = append(models, entity.NewModelStats(
+			pbModel.GetModel(),
+			int(pbModel.GetRequests()),
+			entity.NewToken(tokens.GetInput(), tokens.GetOutput(), tokens.GetCacheRead(), tokens.GetCacheCreation()).WithToolUse(tokens.GetToolUse()),
+			entity.NewCost(pbModel.GetCost().GetAmount()),
+		))
+	}
+	return models
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,23 +13,44 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // MockQueryServiceServer for testing GRPCStatsRepository
 type MockQueryServiceServer struct {
 	pb.UnimplementedQueryServiceServer
-	stats *pb.Stats
-	err   error
+	stats    *pb.Stats
+	err      error
+	cachedAt *timestamppb.Timestamp // served from the server cache unless fresh stats are asked for
+
+	mu      sync.Mutex
+	lastReq *pb.GetStatsRequest
+}
+
+// LastRequest returns the last GetStats request received
+func (m *MockQueryServiceServer) LastRequest() *pb.GetStatsRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastReq
 }
 
 func (m *MockQueryServiceServer) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	m.mu.Lock()
+	m.lastReq = req
+	m.mu.Unlock()
+
 	if m.err != nil {
 		return nil, m.err
 	}
 
-	return &pb.GetStatsResponse{
-		Stats: m.stats,
-	}, nil
+	resp := &pb.GetStatsResponse{
+		Stats:       m.stats,
+		Approximate: req.Approximate, // Echoes the request like a server estimating every allowed request
+	}
+	if !req.Fresh {
+		resp.CachedAt = m.cachedAt
+	}
+	return resp, nil
 }
 
 func (m *MockQueryServiceServer) GetAPIRequests(ctx context.Context, req *pb.GetAPIRequestsRequest) (*pb.GetAPIRequestsResponse, error) {
@@ -184,6 +206,53 @@ func TestGRPCStatsRepository_GetStatsByPeriod(t *testing.T) {
 			),
 			expectError: false,
 		},
+		{
+			name: "long context tier",
+			mockStats: &pb.Stats{
+				BaseRequests:        1,
+				PremiumRequests:     1,
+				TotalRequests:       3,
+				BaseTokens:          &pb.Token{Input: 100, Output: 80},
+				PremiumTokens:       &pb.Token{Input: 300, Output: 250},
+				TotalTokens:         &pb.Token{Input: 1400, Output: 530},
+				BaseCost:            &pb.Cost{Amount: 1.0},
+				PremiumCost:         &pb.Cost{Amount: 2.0},
+				TotalCost:           &pb.Cost{Amount: 13.0},
+				LongContextRequests: 1,
+				LongContextTokens:   &pb.Token{Input: 1000, Output: 200},
+				LongContextCost:     &pb.Cost{Amount: 10.0},
+			},
+			period: entity.NewAllTimePeriod(time.Now()),
+			expectedStats: entity.NewStats(
+				1, 1,
+				entity.NewToken(100, 80, 0, 0), entity.NewToken(300, 250, 0, 0),
+				entity.NewCost(1.0), entity.NewCost(2.0),
+				entity.NewAllTimePeriod(time.Now()),
+			).WithLongContext(1, entity.NewToken(1000, 200, 0, 0), entity.NewCost(10.0)),
+			expectError: false,
+		},
+		{
+			name: "tool use tokens",
+			mockStats: &pb.Stats{
+				BaseRequests:    1,
+				PremiumRequests: 1,
+				TotalRequests:   2,
+				BaseTokens:      &pb.Token{Input: 100, Output: 80},
+				PremiumTokens:   &pb.Token{Input: 300, Output: 250, ToolUse: 200},
+				TotalTokens:     &pb.Token{Input: 400, Output: 330, ToolUse: 200},
+				BaseCost:        &pb.Cost{Amount: 1.0},
+				PremiumCost:     &pb.Cost{Amount: 2.0},
+				TotalCost:       &pb.Cost{Amount: 3.0},
+			},
+			period: entity.NewAllTimePeriod(time.Now()),
+			expectedStats: entity.NewStats(
+				1, 1,
+				entity.NewToken(100, 80, 0, 0), entity.NewToken(300, 250, 0, 0).WithToolUse(200),
+				entity.NewCost(1.0), entity.NewCost(2.0),
+				entity.NewAllTimePeriod(time.Now()),
+			),
+			expectError: false,
+		},
 		{
 			name:        "gRPC error",
 			mockStats:   nil,
@@ -241,10 +310,185 @@ func TestGRPCStatsRepository_GetStatsByPeriod(t *testing.T) {
 			if result.PremiumCost().Amount() != tt.expectedStats.PremiumCost().Amount() {
 				t.Errorf("Premium cost: expected %.1f, got %.1f", tt.expectedStats.PremiumCost().Amount(), result.PremiumCost().Amount())
 			}
+			if result.LongContextRequests() != tt.expectedStats.LongContextRequests() {
+				t.Errorf("Long context requests: expected %d, got %d", tt.expectedStats.LongContextRequests(), result.LongContextRequests())
+			}
+			if result.LongContextCost().Amount() != tt.expectedStats.LongContextCost().Amount() {
+				t.Errorf("Long context cost: expected %.1f, got %.1f", tt.expectedStats.LongContextCost().Amount(), result.LongContextCost().Amount())
+			}
+			if result.TotalCost().Amount() != tt.expectedStats.TotalCost().Amount() {
+				t.Errorf("Total cost: expected %.1f, got %.1f", tt.expectedStats.TotalCost().Amount(), result.TotalCost().Amount())
+			}
+			if result.TotalTokens().ToolUse() != tt.expectedStats.TotalTokens().ToolUse() {
+				t.Errorf("Tool use tokens: expected %d, got %d", tt.expectedStats.TotalTokens().ToolUse(), result.TotalTokens().ToolUse())
+			}
 		})
 	}
 }
 
+func TestGRPCStatsRepository_GetStatsByPeriod_Models(t *testing.T) {
+	server, listener := setupMockGRPCServer(&pb.Stats{
+		PremiumRequests: 3,
+		BaseTokens:      &pb.Token{},
+		PremiumTokens:   &pb.Token{Input: 500, Output: 250, ToolUse: 40, Total: 750},
+		BaseCost:        &pb.Cost{},
+		PremiumCost:     &pb.Cost{Amount: 3.25},
+		TotalRequests:   3,
+		Models: []*pb.ModelStats{
+			{Model: "claude-opus-4-20250514", Requests: 1, Tokens: &pb.Token{Input: 200, Output: 100, ToolUse: 40}, Cost: &pb.Cost{Amount: 2.5}},
+			{Model: "claude-sonnet-4-20250514", Requests: 2, Tokens: &pb.Token{Input: 300, Output: 150}, Cost: &pb.Cost{Amount: 0.75}},
+		},
+	}, nil)
+	defer server.Stop()
+
+	repo, err := createGRPCStatsRepository(listener)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	stats, err := repo.GetStatsByPeriod(entity.NewPeriodFromDuration(time.Now(), time.Hour))
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+
+	models := stats.Models()
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 models, got %d", len(models))
+	}
+	if models[0].Model() != "claude-opus-4-20250514" || models[0].Requests() != 1 || models[0].Tokens().ToolUse() != 40 || models[0].Cost().Amount() != 2.5 {
+		t.Errorf("Expected opus with 1 request, 40 tool use tokens and $2.50, got %s with %d requests, %d tool use tokens and $%.2f",
+			models[0].Model(), models[0].Requests(), models[0].Tokens().ToolUse(), models[0].Cost().Amount())
+	}
+	if models[1].Model() != "claude-sonnet-4-20250514" || models[1].Tokens().Total() != 450 {
+		t.Errorf("Expected sonnet with 450 tokens, got %s with %d tokens", models[1].Model(), models[1].Tokens().Total())
+	}
+}
+
+func TestGRPCStatsRepository_EstimateStatsByPeriod(t *testing.T) {
+	server, listener := setupMockGRPCServer(&pb.Stats{
+		BaseRequests:  10,
+		TotalRequests: 10,
+		BaseTokens:    &pb.Token{},
+		PremiumTokens: &pb.Token{},
+		TotalTokens:   &pb.Token{},
+		BaseCost:      &pb.Cost{},
+		PremiumCost:   &pb.Cost{},
+		TotalCost:     &pb.Cost{},
+	}, nil)
+	defer server.Stop()
+
+	repo, err := createGRPCStatsRepository(listener)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	period := entity.NewPeriodFromDuration(time.Now(), time.Hour)
+
+	estimated, err := repo.EstimateStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("EstimateStatsByPeriod() failed: %v", err)
+	}
+	if !estimated.IsApproximate() || estimated.TotalRequests() != 10 {
+		t.Errorf("Expected approximate stats of 10 requests, got approximate=%v requests=%d", estimated.IsApproximate(), estimated.TotalRequests())
+	}
+
+	exact, err := repo.GetStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	if exact.IsApproximate() {
+		t.Error("Expected exact stats without asking for an estimate")
+	}
+}
+
+func TestGRPCStatsRepository_GetFreshStatsByPeriod(t *testing.T) {
+	cachedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterQueryServiceServer(server, &MockQueryServiceServer{
+		stats: &pb.Stats{
+			BaseRequests:  10,
+			TotalRequests: 10,
+			BaseTokens:    &pb.Token{},
+			PremiumTokens: &pb.Token{},
+			TotalTokens:   &pb.Token{},
+			BaseCost:      &pb.Cost{},
+			PremiumCost:   &pb.Cost{},
+			TotalCost:     &pb.Cost{},
+		},
+		cachedAt: timestamppb.New(cachedAt),
+	})
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	defer server.Stop()
+
+	repo, err := createGRPCStatsRepository(listener)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	period := entity.NewPeriodFromDuration(time.Now(), time.Hour)
+
+	cached, err := repo.GetStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetStatsByPeriod() failed: %v", err)
+	}
+	if !cached.CachedAt().Equal(cachedAt) {
+		t.Errorf("Expected stats cached at %v, got %v", cachedAt, cached.CachedAt())
+	}
+
+	fresh, err := repo.GetFreshStatsByPeriod(period)
+	if err != nil {
+		t.Fatalf("GetFreshStatsByPeriod() failed: %v", err)
+	}
+	if fresh.IsCached() || fresh.TotalRequests() != 10 {
+		t.Errorf("Expected fresh stats of 10 requests, got cached=%v requests=%d", fresh.IsCached(), fresh.TotalRequests())
+	}
+}
+
+func TestGRPCStatsRepository_GetStatsByFilter(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	mockService := &MockQueryServiceServer{
+		stats: &pb.Stats{
+			BaseTokens:    &pb.Token{},
+			PremiumTokens: &pb.Token{},
+			TotalTokens:   &pb.Token{},
+			BaseCost:      &pb.Cost{},
+			PremiumCost:   &pb.Cost{},
+			TotalCost:     &pb.Cost{},
+		},
+	}
+	pb.RegisterQueryServiceServer(server, mockService)
+	go func() {
+		_ = server.Serve(listener) // Expected to fail when test completes
+	}()
+	defer server.Stop()
+
+	repo, err := createGRPCStatsRepository(listener)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer func() { _ = repo.Close() }()
+
+	filter := entity.NewFilter(entity.NewPeriodFromDuration(time.Now(), time.Hour)).
+		WithOrigin(entity.OriginLive).
+		WithProject("ccmon").
+		WithUser("alice@example.com")
+	if _, err := repo.GetStatsByFilter(filter); err != nil {
+		t.Fatalf("GetStatsByFilter() failed: %v", err)
+	}
+
+	req := mockService.LastRequest()
+	if req.Origin != entity.OriginLive || req.Project != "ccmon" || req.User != "alice@example.com" {
+		t.Errorf("Expected origin, project and user to be sent, got %q %q %q", req.Origin, req.Project, req.User)
+	}
+}
+
 func TestGRPCStatsRepository_Close(t *testing.T) {
 	// Setup mock gRPC server
 	server, listener := setupMockGRPCServer(&pb.Stats{}, nil)
@@ -262,3 +506,84 @@ func TestGRPCStatsRepository_Close(t *testing.T) {
 		t.Errorf("Unexpected error closing repository: %v", err)
 	}
 }
+
+// LegacyQueryServiceServer simulates a server that predates the GetStats RPC
+type LegacyQueryServiceServer struct {
+	pb.UnimplementedQueryServiceServer
+	requests []*pb.APIRequest
+}
+
+func (m *LegacyQueryServiceServer) GetAPIRequests(ctx context.Context, req *pb.GetAPIRequestsRequest) (*pb.GetAPIRequestsResponse, error) {
+	return &pb.GetAPIRequestsResponse{
+		Requests:   m.requests,
+		TotalCount: int32(len(m.requests)),
+	}, nil
+}
+
+func TestNegotiateStatsRepository(t *testing.T) {
+	t.Run("server with GetStats uses gRPC stats", func(t *testing.T) {
+		server, listener := setupMockGRPCServer(&pb.Stats{PremiumRequests: 7}, nil)
+		defer server.Stop()
+
+		statsRepo, err := createGRPCStatsRepository(listener)
+		if err != nil {
+			t.Fatalf("Failed to create GRPCStatsRepository: %v", err)
+		}
+		defer func() {
+			if err := statsRepo.Close(); err != nil {
+				t.Logf("Failed to close statsRepo: %v", err)
+			}
+		}()
+
+		repo := NegotiateStatsRepository(statsRepo, nil)
+		if repo != statsRepo {
+			t.Fatalf("Expected GRPCStatsRepository, got %T", repo)
+		}
+	})
+
+	t.Run("legacy server falls back to client-side aggregation", func(t *testing.T) {
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer()
+		pb.RegisterQueryServiceServer(server, &LegacyQueryServiceServer{
+			requests: []*pb.APIRequest{
+				{SessionId: "s1", Timestamp: timestamppb.Now(), Model: "claude-3-haiku-20240307", InputTokens: 100, OutputTokens: 50, CostUsd: 0.01},
+				{SessionId: "s1", Timestamp: timestamppb.Now(), Model: "claude-sonnet-4-20250514", InputTokens: 200, OutputTokens: 100, CostUsd: 0.5},
+			},
+		})
+		go func() {
+			_ = server.Serve(listener) // Expected to fail when test completes
+		}()
+		defer server.Stop()
+
+		statsRepo, err := createGRPCStatsRepository(listener)
+		if err != nil {
+			t.Fatalf("Failed to create GRPCStatsRepository: %v", err)
+		}
+		defer func() {
+			if err := statsRepo.Close(); err != nil {
+				t.Logf("Failed to close statsRepo: %v", err)
+			}
+		}()
+		apiRepo := &GRPCAPIRequestRepository{client: statsRepo.client, conn: statsRepo.conn}
+
+		if statsRepo.SupportsGetStats(context.Background()) {
+			t.Fatal("Expected legacy server to not support GetStats")
+		}
+
+		repo := NegotiateStatsRepository(statsRepo, apiRepo)
+		if _, ok := repo.(*BoltDBStatsRepository); !ok {
+			t.Fatalf("Expected client-side stats repository, got %T", repo)
+		}
+
+		stats, err := repo.GetStatsByPeriod(entity.NewAllTimePeriod(time.Now()))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stats.BaseRequests() != 1 || stats.PremiumRequests() != 1 {
+			t.Errorf("Expected 1 base and 1 premium request, got %d and %d", stats.BaseRequests(), stats.PremiumRequests())
+		}
+		if stats.TotalCost().Amount() != 0.51 {
+			t.Errorf("Expected total cost 0.51, got %f", stats.TotalCost().Amount())
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// InMemoryAPIRequestRepository implements APIRequestRepository without persistence, used by the mock server
+// Requests are kept ordered by time and a request with the same ID replaces the stored one, like in BoltDB
+type InMemoryAPIRequestRepository struct {
+	mutex    sync.RWMutex
+	requests []entity.APIRequest
+	index    map[string]int
+}
+
+// NewInMemoryAPIRequestRepository creates a new empty in-memory repository
+func NewInMemoryAPIRequestRepository() *InMemoryAPIRequestRepository {
+	return &InMemoryAPIRequestRepository{
+		index: make(map[string]int),
+	}
+}
+
+// Save stores an API request entity
+func (r *InMemoryAPIRequestRepository) Save(req entity.APIRequest) error {
+	return r.SaveBatch([]entity.APIRequest{req})
+}
+
+// SaveBatch stores all API request entities at once, readers never see a partial batch
+func (r *InMemoryAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, req := range reqs {
+		if i, ok := r.index[req.ID()]; ok {
+			r.requests[i] = req
+			continue
+		}
+		r.requests = append(r.requests, req)
+		r.index[req.ID()] = len(r.requests) - 1
+	}
+	r.reindex()
+	return nil
+}
+
+// FindByPeriodWithLimit retrieves API requests filtered by time period with limit and offset
+// The limit keeps the latest requests and the offset skips the latest ones, like the BoltDB repository
+func (r *InMemoryAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	matched := r.requests
+	if !period.IsAllTime() {
+		from := sort.Search(len(r.requests), func(i int) bool {
+			return !r.requests[i].Timestamp().Before(period.StartAt())
+		})
+		to := sort.Search(len(r.requests), func(i int) bool {
+			return r.requests[i].Timestamp().After(period.EndAt())
+		})
+		matched = r.requests[from:max(from, to)]
+	}
+
+	end := max(len(matched)-offset, 0)
+	start := 0
+	if limit > 0 {
+		start = max(end-limit, 0)
+	}
+	return append([]entity.APIRequest(nil), matched[start:end]...), nil
+}
+
+// FindAll retrieves all API requests
+func (r *InMemoryAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
+	return r.FindByPeriodWithLimit(entity.NewAllTimePeriod(time.Now()), 0, 0)
+}
+
+// DeleteOlderThan deletes API requests older than the specified cutoff time, except the starred ones in keep
+// Returns the number of deleted records
+func (r *InMemoryAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.requests[:0]
+	for _, req := range r.requests {
+		if req.Timestamp().Before(cutoffTime) && !keep.IsStarred(req) {
+			continue
+		}
+		kept = append(kept, req)
+	}
+
+	deleted := len(r.requests) - len(kept)
+	r.requests = kept
+	r.reindex()
+	return deleted, nil
+}
+
+// reindex orders the requests by time and rebuilds the ID index
+func (r *InMemoryAPIRequestRepository) reindex() {
+	sort.SliceStable(r.requests, func(i, j int) bool {
+		return r.requests[i].Timestamp().Before(r.requests[j].Timestamp())
+	})
+
+	clear(r.index)
+	for i, req := range r.requests {
+		r.index[req.ID()] = i
+	}
+}
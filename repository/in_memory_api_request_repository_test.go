@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestInMemoryAPIRequestRepository(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	newRequest := func(sessionID string, offset time.Duration, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, base.Add(offset), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+	sessions := func(requests []entity.APIRequest) []string {
+		var ids []string
+		for _, req := range requests {
+			ids = append(ids, req.SessionID())
+		}
+		return ids
+	}
+
+	repo := NewInMemoryAPIRequestRepository()
+	// Stored out of order, the same ID replaces the stored request
+	if err := repo.SaveBatch([]entity.APIRequest{
+		newRequest("c", 2*time.Minute, 0.01),
+		newRequest("a", 0, 0.01),
+		newRequest("b", time.Minute, 0.01),
+	}); err != nil {
+		t.Fatalf("SaveBatch() returned error: %v", err)
+	}
+	if err := repo.Save(newRequest("a", 0, 0.05)); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := repo.Save(newRequest("d", 3*time.Minute, 0.01)); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		period   entity.Period
+		limit    int
+		offset   int
+		expected []string
+	}{
+		{name: "all time", period: entity.NewAllTimePeriod(base), expected: []string{"a", "b", "c", "d"}},
+		{name: "period includes both ends", period: entity.NewPeriod(base.Add(time.Minute), base.Add(2*time.Minute)), expected: []string{"b", "c"}},
+		{name: "limit keeps the latest", period: entity.NewAllTimePeriod(base), limit: 2, expected: []string{"c", "d"}},
+		{name: "offset skips the latest", period: entity.NewAllTimePeriod(base), limit: 2, offset: 1, expected: []string{"b", "c"}},
+		{name: "offset past the oldest", period: entity.NewAllTimePeriod(base), limit: 2, offset: 4},
+		{name: "empty period", period: entity.NewPeriod(base.Add(time.Hour), base.Add(2*time.Hour))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requests, err := repo.FindByPeriodWithLimit(tt.period, tt.limit, tt.offset)
+			if err != nil {
+				t.Fatalf("FindByPeriodWithLimit() returned error: %v", err)
+			}
+			got := sessions(requests)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected sessions %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("Expected sessions %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+
+	t.Run("replaced request", func(t *testing.T) {
+		t.Parallel()
+
+		requests, _ := repo.FindAll()
+		if requests[0].Cost().Amount() != 0.05 {
+			t.Errorf("Expected the replaced request to cost 0.05, got %v", requests[0].Cost().Amount())
+		}
+	})
+}
+
+func TestInMemoryAPIRequestRepository_DeleteOlderThan(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewInMemoryAPIRequestRepository()
+	old := entity.NewAPIRequest("old", base, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	starred := entity.NewAPIRequest("starred", base.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	recent := entity.NewAPIRequest("recent", base.Add(time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	if err := repo.SaveBatch([]entity.APIRequest{old, starred, recent}); err != nil {
+		t.Fatalf("SaveBatch() returned error: %v", err)
+	}
+
+	deleted, err := repo.DeleteOlderThan(base.Add(30*time.Minute), entity.NewStars([]string{starred.ID()}, nil))
+	if err != nil {
+		t.Fatalf("DeleteOlderThan() returned error: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted request, got %d", deleted)
+	}
+
+	requests, _ := repo.FindAll()
+	if len(requests) != 2 || requests[0].SessionID() != "starred" || requests[1].SessionID() != "recent" {
+		t.Errorf("Expected the starred and recent requests to be kept, got %d requests", len(requests))
+	}
+
+	// The index follows the kept requests
+	if err := repo.Save(recent); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if requests, _ := repo.FindAll(); len(requests) != 2 {
+		t.Errorf("Expected the saved request to replace the kept one, got %d requests", len(requests))
+	}
+}
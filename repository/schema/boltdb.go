@@ -2,7 +2,7 @@ package schema
 
 import "time"
 
-// APIRequest represents a single Claude Code API request
+// APIRequest represents a single AI CLI API request
 type APIRequest struct {
 	SessionID           string
 	Timestamp           time.Time
@@ -11,7 +11,37 @@ type APIRequest struct {
 	OutputTokens        int64
 	CacheReadTokens     int64
 	CacheCreationTokens int64
+	ToolUseTokens       int64 `json:",omitempty"` // part of OutputTokens, zero when telemetry does not report it
 	TotalTokens         int64
 	CostUSD             float64
 	DurationMS          int64
+	Source              string `json:",omitempty"` // empty for records stored before sources were tracked
+	Origin              string `json:",omitempty"` // empty for records stored before origins were tracked, which are live
+	User                string `json:",omitempty"` // empty when telemetry does not identify the user
+	Project             string `json:",omitempty"` // empty when telemetry does not identify the project
+}
+
+// DailyStats represents the precomputed usage of a UTC day, broken down by hour
+// Stats of periods aligned to whole hours, like the days of any whole-hour timezone, are summed from the hours
+type DailyStats struct {
+	Hours map[int]HourStats // keyed by the UTC hour of the day, hours without requests are left out
+}
+
+// HourStats represents the precomputed usage of an hour by model tier and by model
+type HourStats struct {
+	Base        TierStats
+	Premium     TierStats
+	LongContext TierStats
+	Models      map[string]TierStats `json:",omitempty"` // keyed by the model name
+}
+
+// TierStats represents the precomputed usage of a model tier or a model
+type TierStats struct {
+	Requests            int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	ToolUseTokens       int64 `json:",omitempty"`
+	CostUSD             float64
 }
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// WebhookFormat is the JSON payload shape expected by the receiving service
+type WebhookFormat string
+
+const (
+	WebhookGeneric WebhookFormat = "generic" // message as "text" with the alert details, accepted by Slack too
+	WebhookSlack   WebhookFormat = "slack"   // message as "text" only
+	WebhookDiscord WebhookFormat = "discord" // message as "content" only
+)
+
+// ParseWebhookFormat parses the payload format, empty defaults to generic
+func ParseWebhookFormat(format string) (WebhookFormat, error) {
+	switch WebhookFormat(format) {
+	case "", WebhookGeneric:
+		return WebhookGeneric, nil
+	case WebhookSlack, WebhookDiscord:
+		return WebhookFormat(format), nil
+	default:
+		return "", fmt.Errorf("unknown webhook format %q, expected generic, slack or discord", format)
+	}
+}
+
+// AlertWebhook is a single URL alerts are posted to
+type AlertWebhook struct {
+	URL    string
+	Format WebhookFormat
+}
+
+// AlertWebhookNotifier posts alerts as JSON to every webhook.
+type AlertWebhookNotifier struct {
+	webhooks   []AlertWebhook
+	client     *http.Client
+	costFormat entity.CostFormat
+}
+
+// webhookAlert is the generic JSON payload of an alert
+type webhookAlert struct {
+	Text        string  `json:"text"`
+	Rule        string  `json:"rule"`
+	Metric      string  `json:"metric"`
+	Value       float64 `json:"value"`
+	Threshold   float64 `json:"threshold"`
+	PeriodStart string  `json:"period_start"`
+	PeriodEnd   string  `json:"period_end"`
+	FiredAt     string  `json:"fired_at"`
+}
+
+// NewAlertWebhookNotifier creates a new alert notifier for the webhooks.
+func NewAlertWebhookNotifier(webhooks []AlertWebhook, costFormat entity.CostFormat) *AlertWebhookNotifier {
+	return &AlertWebhookNotifier{
+		webhooks:   webhooks,
+		client:     &http.Client{Timeout: webhookTimeout},
+		costFormat: costFormat,
+	}
+}
+
+// NotifyAlert posts the alert to every webhook, a failed webhook does not stop the others.
+func (n *AlertWebhookNotifier) NotifyAlert(ctx context.Context, alert entity.Alert) error {
+	var errs []error
+	for _, webhook := range n.webhooks {
+		if err := postWebhook(ctx, n.client, webhook.URL, n.payload(webhook.Format, alert)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// payload returns the JSON payload of the alert in the webhook format
+func (n *AlertWebhookNotifier) payload(format WebhookFormat, alert entity.Alert) any {
+	message := alert.Message(n.costFormat)
+
+	switch format {
+	case WebhookSlack:
+		return map[string]string{"text": message}
+	case WebhookDiscord:
+		return map[string]string{"content": message}
+	default:
+		return webhookAlert{
+			Text:        message,
+			Rule:        alert.Rule().String(),
+			Metric:      string(alert.Rule().Metric()),
+			Value:       alert.Value(),
+			Threshold:   alert.Threshold(),
+			PeriodStart: alert.Period().StartAt().UTC().Format(time.RFC3339),
+			PeriodEnd:   alert.Period().EndAt().UTC().Format(time.RFC3339),
+			FiredAt:     alert.FiredAt().UTC().Format(time.RFC3339),
+		}
+	}
+}
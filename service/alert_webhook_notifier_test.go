@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestAlertWebhookNotifier_NotifyAlert(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rule, err := entity.NewAlertRule("daily_cost", 10, 0)
+	if err != nil {
+		t.Fatalf("NewAlertRule() returned error: %v", err)
+	}
+	policy := entity.NewAlertPolicy([]entity.AlertRule{rule}, 0, time.UTC, 0)
+	stats := entity.NewStatsFromRequests([]entity.APIRequest{
+		entity.NewAPIRequest("s1", now, "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(12.3), 0),
+	}, policy.Period(rule, now))
+	alert, fired := policy.Evaluate(rule, stats, now)
+	if !fired {
+		t.Fatal("Expected the rule to fire")
+	}
+
+	tests := []struct {
+		name         string
+		format       string
+		status       int
+		expectedBody map[string]any
+		errMsg       string
+	}{
+		{
+			name:   "generic posts the alert details",
+			format: "generic",
+			status: http.StatusOK,
+			expectedBody: map[string]any{
+				"text":         "Daily cost $12.30 is above $10.00",
+				"rule":         "daily_cost > 10",
+				"metric":       "daily_cost",
+				"value":        12.3,
+				"threshold":    float64(10),
+				"period_start": "2025-06-01T00:00:00Z",
+				"period_end":   "2025-06-01T23:59:59Z",
+				"fired_at":     "2025-06-01T12:00:00Z",
+			},
+		},
+		{
+			name:   "slack posts the message as text",
+			format: "slack",
+			status: http.StatusOK,
+			expectedBody: map[string]any{
+				"text": "Daily cost $12.30 is above $10.00",
+			},
+		},
+		{
+			name:   "discord posts the message as content",
+			format: "discord",
+			status: http.StatusNoContent,
+			expectedBody: map[string]any{
+				"content": "Daily cost $12.30 is above $10.00",
+			},
+		},
+		{
+			name:   "non-2xx response is an error",
+			format: "slack",
+			status: http.StatusBadRequest,
+			errMsg: "400",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			format, err := ParseWebhookFormat(tt.format)
+			if err != nil {
+				t.Fatalf("ParseWebhookFormat() returned error: %v", err)
+			}
+			notifier := NewAlertWebhookNotifier([]AlertWebhook{{URL: server.URL, Format: format}}, entity.DefaultCostFormat())
+			err = notifier.NotifyAlert(context.Background(), alert)
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NotifyAlert() returned error: %v", err)
+			}
+
+			if len(body) != len(tt.expectedBody) {
+				t.Errorf("Expected %d fields, got %v", len(tt.expectedBody), body)
+			}
+			for key, want := range tt.expectedBody {
+				if body[key] != want {
+					t.Errorf("Expected %s = %v, got %v", key, want, body[key])
+				}
+			}
+		})
+	}
+}
+
+func TestParseWebhookFormat(t *testing.T) {
+	if format, err := ParseWebhookFormat(""); err != nil || format != WebhookGeneric {
+		t.Errorf("Expected empty format to default to generic, got %q, %v", format, err)
+	}
+	if _, err := ParseWebhookFormat("teams"); err == nil {
+		t.Error("Expected unknown format to be an error")
+	}
+}
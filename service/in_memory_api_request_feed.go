@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DefaultFeedBuffer is the number of batches kept for each watcher before new batches are dropped
+const DefaultFeedBuffer = 64
+
+// InMemoryAPIRequestFeed delivers stored API requests to the watchers of this process.
+// A watcher which falls behind by more than the buffer misses batches instead of slowing down ingestion.
+type InMemoryAPIRequestFeed struct {
+	watchers map[*feedWatcher]struct{}
+	mutex    sync.RWMutex
+	buffer   int
+}
+
+// feedWatcher is a single watcher with the dimensions it is interested in
+type feedWatcher struct {
+	filter  entity.Filter
+	batches chan []entity.APIRequest
+}
+
+// NewInMemoryAPIRequestFeed creates a new in-memory feed keeping up to buffer batches for each watcher.
+func NewInMemoryAPIRequestFeed(buffer int) *InMemoryAPIRequestFeed {
+	if buffer <= 0 {
+		buffer = DefaultFeedBuffer
+	}
+
+	return &InMemoryAPIRequestFeed{
+		watchers: make(map[*feedWatcher]struct{}),
+		buffer:   buffer,
+	}
+}
+
+// Publish delivers the stored requests to every watcher whose filter matches them.
+func (f *InMemoryAPIRequestFeed) Publish(requests []entity.APIRequest) {
+	if len(requests) == 0 {
+		return
+	}
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for watcher := range f.watchers {
+		matched := watcher.filter.Apply(requests)
+		if len(matched) == 0 {
+			continue
+		}
+
+		select {
+		case watcher.batches <- matched:
+		default:
+			// The watcher is behind, it refreshes from the repository on the next batch anyway
+		}
+	}
+}
+
+// WatchByFilter calls notify with each batch of published requests matching the filter dimensions until ctx is done.
+func (f *InMemoryAPIRequestFeed) WatchByFilter(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error {
+	watcher := &feedWatcher{
+		filter:  filter,
+		batches: make(chan []entity.APIRequest, f.buffer),
+	}
+
+	f.mutex.Lock()
+	f.watchers[watcher] = struct{}{}
+	f.mutex.Unlock()
+
+	defer func() {
+		f.mutex.Lock()
+		delete(f.watchers, watcher)
+		f.mutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case batch := <-watcher.batches:
+			if err := notify(batch); err != nil {
+				return err
+			}
+		}
+	}
+}
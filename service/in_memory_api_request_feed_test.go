@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func newFeedRequest(sessionID string, model string) entity.APIRequest {
+	return entity.NewAPIRequest(sessionID, time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), model, entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+}
+
+// waitForWatchers waits until the feed has the expected number of watchers
+func waitForWatchers(t *testing.T, feed *InMemoryAPIRequestFeed, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		feed.mutex.RLock()
+		count := len(feed.watchers)
+		feed.mutex.RUnlock()
+		if count == expected {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d watchers", expected)
+}
+
+func TestInMemoryAPIRequestFeed_WatchByFilter(t *testing.T) {
+	feed := NewInMemoryAPIRequestFeed(0)
+	filter := entity.NewFilter(entity.NewAllTimePeriod(time.Now())).WithModel("claude-sonnet-4-20250514")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan []entity.APIRequest, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- feed.WatchByFilter(ctx, filter, func(requests []entity.APIRequest) error {
+			received <- requests
+			return nil
+		})
+	}()
+	waitForWatchers(t, feed, 1)
+
+	feed.Publish([]entity.APIRequest{
+		newFeedRequest("session-1", "claude-sonnet-4-20250514"),
+		newFeedRequest("session-2", "claude-3-5-haiku-20241022"),
+	})
+
+	select {
+	case requests := <-received:
+		if len(requests) != 1 || requests[0].SessionID() != "session-1" {
+			t.Errorf("Expected only the matching request, got %d requests", len(requests))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the published requests to be delivered")
+	}
+
+	// Batches without matching requests are not delivered
+	feed.Publish([]entity.APIRequest{newFeedRequest("session-3", "claude-3-5-haiku-20241022")})
+	select {
+	case requests := <-received:
+		t.Errorf("Expected no delivery, got %d requests", len(requests))
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected no error after cancel, got %v", err)
+	}
+	waitForWatchers(t, feed, 0)
+}
+
+func TestInMemoryAPIRequestFeed_NotifyError(t *testing.T) {
+	feed := NewInMemoryAPIRequestFeed(0)
+	notifyErr := errors.New("stream closed")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- feed.WatchByFilter(context.Background(), entity.NewFilter(entity.NewAllTimePeriod(time.Now())), func(requests []entity.APIRequest) error {
+			return notifyErr
+		})
+	}()
+	waitForWatchers(t, feed, 1)
+
+	feed.Publish([]entity.APIRequest{newFeedRequest("session-1", "claude-sonnet-4-20250514")})
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, notifyErr) {
+			t.Errorf("Expected notify error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the watch to stop")
+	}
+	waitForWatchers(t, feed, 0)
+}
+
+func TestInMemoryAPIRequestFeed_SlowWatcherDoesNotBlock(t *testing.T) {
+	feed := NewInMemoryAPIRequestFeed(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := make(chan struct{})
+	go func() {
+		_ = feed.WatchByFilter(ctx, entity.NewFilter(entity.NewAllTimePeriod(time.Now())), func(requests []entity.APIRequest) error {
+			<-release
+			return nil
+		})
+	}()
+	waitForWatchers(t, feed, 1)
+
+	published := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			feed.Publish([]entity.APIRequest{newFeedRequest("session-1", "claude-sonnet-4-20250514")})
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Expected publishing not to wait for a slow watcher")
+	}
+	close(release)
+}
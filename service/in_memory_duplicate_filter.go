@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDedupeWindow is how long appended API requests are remembered when the config does not set a window
+const DefaultDedupeWindow = 10 * time.Minute
+
+// InMemoryDuplicateFilter remembers the keys of appended API requests for a window.
+// Keys are forgotten on restart, a record retried across a restart is stored again.
+type InMemoryDuplicateFilter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	expiresAt map[string]time.Time
+	nextPrune time.Time
+	now       func() time.Time
+}
+
+// NewInMemoryDuplicateFilter creates a filter remembering each key for the window after it is first seen
+func NewInMemoryDuplicateFilter(window time.Duration) *InMemoryDuplicateFilter {
+	return &InMemoryDuplicateFilter{
+		window:    window,
+		expiresAt: make(map[string]time.Time),
+		now:       time.Now,
+	}
+}
+
+// Seen returns true if the key was seen within the window, otherwise it remembers the key
+// A duplicate does not extend the window, so a record retried forever is stored again once per window
+func (f *InMemoryDuplicateFilter) Seen(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	f.pruneExpired(now)
+
+	if expiresAt, ok := f.expiresAt[key]; ok && now.Before(expiresAt) {
+		return true
+	}
+	f.expiresAt[key] = now.Add(f.window)
+	return false
+}
+
+// Forget drops the key, so the next request with it is not a duplicate
+func (f *InMemoryDuplicateFilter) Forget(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.expiresAt, key)
+}
+
+// Len returns the number of remembered keys
+func (f *InMemoryDuplicateFilter) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.expiresAt)
+}
+
+// pruneExpired drops the expired keys at most once per window, so the map doesn't grow with every request
+// The caller must hold the lock
+func (f *InMemoryDuplicateFilter) pruneExpired(now time.Time) {
+	if now.Before(f.nextPrune) {
+		return
+	}
+	f.nextPrune = now.Add(f.window)
+
+	for key, expiresAt := range f.expiresAt {
+		if !now.Before(expiresAt) {
+			delete(f.expiresAt, key)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestInMemoryDuplicateFilter_Seen(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	filter := NewInMemoryDuplicateFilter(time.Minute)
+	filter.now = func() time.Time { return now }
+
+	if filter.Seen("a") {
+		t.Error("Expected the first request not to be a duplicate")
+	}
+	if !filter.Seen("a") {
+		t.Error("Expected the re-sent request to be a duplicate")
+	}
+	if filter.Seen("b") {
+		t.Error("Expected another key not to be a duplicate")
+	}
+
+	// The window starts when the key is first seen, duplicates don't extend it
+	now = now.Add(59 * time.Second)
+	if !filter.Seen("a") {
+		t.Error("Expected the request to be a duplicate within the window")
+	}
+	now = now.Add(time.Second)
+	if filter.Seen("a") {
+		t.Error("Expected the request to be stored again after the window")
+	}
+
+	filter.Forget("a")
+	if filter.Seen("a") {
+		t.Error("Expected a forgotten key not to be a duplicate")
+	}
+}
+
+func TestInMemoryDuplicateFilter_PrunesExpiredKeys(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	filter := NewInMemoryDuplicateFilter(time.Minute)
+	filter.now = func() time.Time { return now }
+
+	for i := 0; i < 100; i++ {
+		filter.Seen(fmt.Sprintf("key-%d", i))
+	}
+	if filter.Len() != 100 {
+		t.Fatalf("Expected 100 remembered keys, got %d", filter.Len())
+	}
+
+	now = now.Add(time.Minute)
+	filter.Seen("new")
+	if filter.Len() != 1 {
+		t.Errorf("Expected the expired keys to be pruned, got %d keys", filter.Len())
+	}
+}
+
+func TestInMemoryDuplicateFilter_ConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	filter := NewInMemoryDuplicateFilter(time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	stored := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if !filter.Seen(fmt.Sprintf("key-%d", j)) {
+					mu.Lock()
+					stored++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stored != 100 {
+		t.Errorf("Expected each key to be stored once, got %d stores", stored)
+	}
+}
@@ -20,6 +20,7 @@ type InMemoryStatsCache struct {
 
 // CachedStats represents a cached statistics entry with expiration time.
 type CachedStats struct {
+	Period    entity.Period
 	Stats     *entity.Stats
 	ExpiresAt time.Time
 }
@@ -56,20 +57,41 @@ func (c *InMemoryStatsCache) Get(period entity.Period) *entity.Stats {
 }
 
 // Set stores statistics in the cache for the given period.
+// Stats are returned marked as cached since now, or since the cache they were served from calculated them.
 func (c *InMemoryStatsCache) Set(period entity.Period, stats *entity.Stats) {
 	c.tryCleanupExpired()
 
 	key := c.generateKey(period)
-	expiresAt := time.Now().Add(c.ttl)
+	now := time.Now()
+	expiresAt := now.Add(c.ttl)
+
+	cached := *stats
+	if !cached.IsCached() {
+		cached = cached.WithCachedAt(now)
+	}
 
 	c.mutex.Lock()
 	c.cache[key] = &CachedStats{
-		Stats:     stats,
+		Period:    period,
+		Stats:     &cached,
 		ExpiresAt: expiresAt,
 	}
 	c.mutex.Unlock()
 }
 
+// Invalidate evicts the cached statistics of every period containing at.
+// Periods which ended before at, like the previous days, stay cached.
+func (c *InMemoryStatsCache) Invalidate(at time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, cached := range c.cache {
+		if cached.Period.Contains(at) {
+			delete(c.cache, key)
+		}
+	}
+}
+
 // generateKey creates a unique cache key from the period timestamps.
 func (c *InMemoryStatsCache) generateKey(period entity.Period) string {
 	return fmt.Sprintf("%d_%d", period.StartAt().Unix(), period.EndAt().Unix())
@@ -101,3 +101,52 @@ func TestInMemoryStatsCache_CleanupGoroutineLimit(t *testing.T) {
 		t.Error("Expected cleanup flag to be reset, indicating no orphaned goroutines")
 	}
 }
+
+func TestInMemoryStatsCache_CachedAt(t *testing.T) {
+	cache := NewInMemoryStatsCache(time.Minute)
+	period := entity.NewPeriod(time.Now().Add(-1*time.Hour), time.Now())
+
+	before := time.Now()
+	cache.Set(period, &entity.Stats{})
+
+	result := cache.Get(period)
+	if result == nil || !result.IsCached() {
+		t.Fatal("Expected cached stats to be marked as cached")
+	}
+	if result.CachedAt().Before(before) || result.CachedAt().After(time.Now()) {
+		t.Errorf("Expected cached at to be when the stats were set, got %v", result.CachedAt())
+	}
+
+	// Stats served from another cache keep when they were calculated
+	calculatedAt := before.Add(-30 * time.Second)
+	stats := entity.Stats{}.WithCachedAt(calculatedAt)
+	cache.Set(period, &stats)
+	if result := cache.Get(period); !result.CachedAt().Equal(calculatedAt) {
+		t.Errorf("Expected cached at %v to be kept, got %v", calculatedAt, result.CachedAt())
+	}
+}
+
+func TestInMemoryStatsCache_Invalidate(t *testing.T) {
+	cache := NewInMemoryStatsCache(time.Minute)
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	yesterday := entity.NewPeriod(start.Add(-24*time.Hour), start.Add(-time.Nanosecond))
+	today := entity.NewPeriod(start, start.Add(24*time.Hour-time.Nanosecond))
+	hour := entity.NewPeriod(start.Add(10*time.Hour), start.Add(11*time.Hour-time.Nanosecond))
+	allTime := entity.NewAllTimePeriod(start.Add(24 * time.Hour))
+
+	for _, period := range []entity.Period{yesterday, today, hour, allTime} {
+		cache.Set(period, &entity.Stats{})
+	}
+
+	// A request stored at 10:30 changes the stats of today, its hour and all time
+	cache.Invalidate(start.Add(10*time.Hour + 30*time.Minute))
+
+	if cache.Get(yesterday) == nil {
+		t.Error("Expected the stats of yesterday to stay cached")
+	}
+	for name, period := range map[string]entity.Period{"today": today, "hour": hour, "all time": allTime} {
+		if cache.Get(period) != nil {
+			t.Errorf("Expected the stats of %s to be evicted", name)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// mockModel is a model the generator picks with its weight and prices in USD per million tokens
+type mockModel struct {
+	name          string
+	weight        int
+	input         float64
+	output        float64
+	cacheRead     float64
+	cacheCreation float64
+}
+
+// mockModels are picked by weight, most requests go to Sonnet like in a typical Claude Code session
+var mockModels = []mockModel{
+	{name: "claude-sonnet-4-20250514", weight: 60, input: 3, output: 15, cacheRead: 0.3, cacheCreation: 3.75},
+	{name: "claude-opus-4-20250514", weight: 25, input: 15, output: 75, cacheRead: 1.5, cacheCreation: 18.75},
+	{name: "claude-3-5-haiku-20241022", weight: 15, input: 0.8, output: 4, cacheRead: 0.08, cacheCreation: 1},
+}
+
+var (
+	mockProjects = []string{"ccmon", "website", "infra"}
+	mockUsers    = []string{"alice@example.com", "bob@example.com"}
+)
+
+// MockRequestGenerator implements usecase.MockRequestGenerator with Claude Code sessions spread over working hours.
+// Each hour is generated from its own seed, so a range always returns the same requests however it is split.
+type MockRequestGenerator struct {
+	seed     int64
+	timezone *time.Location
+}
+
+// NewMockRequestGenerator creates a new generator, working hours and days follow the timezone.
+func NewMockRequestGenerator(seed int64, timezone *time.Location) *MockRequestGenerator {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	return &MockRequestGenerator{
+		seed:     seed,
+		timezone: timezone,
+	}
+}
+
+// Generate returns the requests made from start until before end, ordered by time.
+func (g *MockRequestGenerator) Generate(start, end time.Time) []entity.APIRequest {
+	var requests []entity.APIRequest
+	for hour := start.Truncate(time.Hour); hour.Before(end); hour = hour.Add(time.Hour) {
+		for _, req := range g.hour(hour) {
+			if !req.Timestamp().Before(start) && req.Timestamp().Before(end) {
+				requests = append(requests, req)
+			}
+		}
+	}
+	return requests
+}
+
+// hour returns every request of the hour, a few sessions on working hours and the odd one in the evening
+func (g *MockRequestGenerator) hour(hour time.Time) []entity.APIRequest {
+	rng := rand.New(rand.NewSource(g.seed ^ hour.Unix()))
+
+	local := hour.In(g.timezone)
+	workday := local.Weekday() != time.Saturday && local.Weekday() != time.Sunday
+	sessions := 0
+	if workday && local.Hour() >= 9 && local.Hour() < 19 {
+		sessions = 1 + rng.Intn(3)
+	} else if rng.Intn(6) == 0 {
+		sessions = 1
+	}
+
+	var requests []entity.APIRequest
+	for range sessions {
+		sessionID := fmt.Sprintf("mock-%08x", rng.Uint32())
+		project := mockProjects[rng.Intn(len(mockProjects))]
+		user := mockUsers[rng.Intn(len(mockUsers))]
+
+		at := hour.Add(time.Duration(rng.Intn(50)) * time.Minute)
+		for range 5 + rng.Intn(20) {
+			at = at.Add(time.Duration(10+rng.Intn(120)) * time.Second)
+			if !at.Before(hour.Add(time.Hour)) {
+				break
+			}
+			requests = append(requests, g.request(rng, sessionID, at).WithProject(project).WithUser(user))
+		}
+	}
+
+	// Sessions of the same hour overlap
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Timestamp().Before(requests[j].Timestamp())
+	})
+	return requests
+}
+
+// request returns a single request of the session with tokens and cost of a random model
+func (g *MockRequestGenerator) request(rng *rand.Rand, sessionID string, at time.Time) entity.APIRequest {
+	model := mockModels[len(mockModels)-1]
+	pick := rng.Intn(100)
+	for _, candidate := range mockModels {
+		if pick < candidate.weight {
+			model = candidate
+			break
+		}
+		pick -= candidate.weight
+	}
+
+	input := int64(50 + rng.Intn(3000))
+	output := int64(100 + rng.Intn(2500))
+	cacheRead := int64(rng.Intn(60000))
+	cacheCreation := int64(rng.Intn(8000))
+	cost := (float64(input)*model.input + float64(output)*model.output +
+		float64(cacheRead)*model.cacheRead + float64(cacheCreation)*model.cacheCreation) / 1_000_000
+
+	durationMS := int64(2000 + rng.Intn(25000))
+	return entity.NewAPIRequest(sessionID, at, model.name, entity.NewToken(input, output, cacheRead, cacheCreation), entity.NewCost(cost), durationMS)
+}
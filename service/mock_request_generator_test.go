@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMockRequestGenerator_Generate(t *testing.T) {
+	generator := NewMockRequestGenerator(42, time.UTC)
+	// Monday to Sunday
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	requests := generator.Generate(start, end)
+	if len(requests) == 0 {
+		t.Fatal("Expected a week of requests")
+	}
+
+	for i, req := range requests {
+		if req.Timestamp().Before(start) || !req.Timestamp().Before(end) {
+			t.Fatalf("Request %d at %v is outside of the range", i, req.Timestamp())
+		}
+		if i > 0 && req.Timestamp().Before(requests[i-1].Timestamp()) {
+			t.Fatalf("Request %d at %v is before the previous one", i, req.Timestamp())
+		}
+		if req.Cost().Amount() <= 0 || req.Tokens().Total() <= 0 {
+			t.Fatalf("Request %d has no usage: cost %v, tokens %d", i, req.Cost().Amount(), req.Tokens().Total())
+		}
+		if req.Project() == "" || req.User() == "" {
+			t.Fatalf("Request %d has no project or user", i)
+		}
+	}
+
+	t.Run("the same range returns the same requests however it is split", func(t *testing.T) {
+		middle := start.Add(50*time.Hour + 17*time.Minute)
+		split := append(generator.Generate(start, middle), generator.Generate(middle, end)...)
+		if len(split) != len(requests) {
+			t.Fatalf("Expected %d requests, got %d", len(requests), len(split))
+		}
+		for i := range requests {
+			if split[i].ID() != requests[i].ID() || split[i].Cost() != requests[i].Cost() {
+				t.Fatalf("Request %d differs: %s vs %s", i, split[i].ID(), requests[i].ID())
+			}
+		}
+	})
+
+	t.Run("working hours are busier than the weekend", func(t *testing.T) {
+		weekday := len(generator.Generate(start.Add(9*time.Hour), start.Add(19*time.Hour)))
+		weekend := len(generator.Generate(start.AddDate(0, 0, 5).Add(9*time.Hour), start.AddDate(0, 0, 5).Add(19*time.Hour)))
+		if weekday <= weekend {
+			t.Errorf("Expected more requests on Monday than on Saturday, got %d and %d", weekday, weekend)
+		}
+	})
+
+	t.Run("another seed returns other requests", func(t *testing.T) {
+		other := NewMockRequestGenerator(7, time.UTC).Generate(start, end)
+		if len(other) == len(requests) && other[0].ID() == requests[0].ID() {
+			t.Error("Expected another seed to generate other requests")
+		}
+	})
+}
@@ -1,6 +1,10 @@
 package service
 
-import "github.com/elct9620/ccmon/entity"
+import (
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
 
 // NoOpStatsCache is a no-operation implementation that never caches.
 // Used when caching is disabled via configuration.
@@ -15,3 +19,8 @@ func (c *NoOpStatsCache) Get(period entity.Period) *entity.Stats {
 func (c *NoOpStatsCache) Set(period entity.Period, stats *entity.Stats) {
 	// No-op: caching is disabled
 }
+
+// Invalidate does nothing, as nothing is cached
+func (c *NoOpStatsCache) Invalidate(at time.Time) {
+	// No-op: caching is disabled
+}
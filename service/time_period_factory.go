@@ -9,6 +9,7 @@ import (
 // TimePeriodFactory implements PeriodFactory using timezone-aware calculations
 type TimePeriodFactory struct {
 	timezone *time.Location
+	now      func() time.Time
 }
 
 // NewTimePeriodFactory creates a new TimePeriodFactory with the given timezone
@@ -18,27 +19,19 @@ func NewTimePeriodFactory(timezone *time.Location) *TimePeriodFactory {
 	}
 	return &TimePeriodFactory{
 		timezone: timezone,
+		now:      time.Now,
 	}
 }
 
 // CreateDaily creates a period for today using timezone-aware boundaries
+// Days when the clocks change for daylight saving time are 23 or 25 hours long
 func (f *TimePeriodFactory) CreateDaily() entity.Period {
-	now := time.Now().In(f.timezone)
-	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, f.timezone)
-	dayEnd := dayStart.Add(24*time.Hour - time.Nanosecond)
-
-	// Convert to UTC for database queries but maintain timezone-aware boundaries
-	return entity.NewPeriod(dayStart.UTC(), dayEnd.UTC())
+	// Boundaries are in UTC for database queries but follow the calendar day of the timezone
+	return entity.NewDayPeriod(f.now(), f.timezone)
 }
 
 // CreateMonthly creates a period for current month using timezone-aware boundaries
 func (f *TimePeriodFactory) CreateMonthly() entity.Period {
-	now := time.Now().In(f.timezone)
-	// First day of current month at 00:00:00 in user's timezone
-	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, f.timezone)
-	// First day of next month minus 1 nanosecond to get end of current month
-	monthEnd := monthStart.AddDate(0, 1, 0).Add(-time.Nanosecond)
-
-	// Convert to UTC for database queries but maintain timezone-aware boundaries
-	return entity.NewPeriod(monthStart.UTC(), monthEnd.UTC())
+	// Boundaries are in UTC for database queries but follow the calendar month of the timezone
+	return entity.NewMonthPeriod(f.now(), f.timezone)
 }
@@ -13,6 +13,7 @@ func TestTimePeriodFactory(t *testing.T) {
 	}
 
 	factory := NewTimePeriodFactory(loc)
+	factory.now = func() time.Time { return time.Date(2025, 6, 15, 12, 0, 0, 0, loc) }
 
 	t.Run("CreateDaily", func(t *testing.T) {
 		period := factory.CreateDaily()
@@ -67,3 +68,45 @@ func TestTimePeriodFactory(t *testing.T) {
 		}
 	})
 }
+
+func TestTimePeriodFactory_DaylightSavingTime(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "spring forward day is 23 hours long",
+			now:           time.Date(2025, 3, 9, 18, 0, 0, 0, loc),
+			expectedStart: time.Date(2025, 3, 9, 0, 0, 0, 0, loc),
+			expectedEnd:   time.Date(2025, 3, 10, 0, 0, 0, 0, loc).Add(-time.Nanosecond),
+		},
+		{
+			name:          "fall back day is 25 hours long",
+			now:           time.Date(2025, 11, 2, 18, 0, 0, 0, loc),
+			expectedStart: time.Date(2025, 11, 2, 0, 0, 0, 0, loc),
+			expectedEnd:   time.Date(2025, 11, 3, 0, 0, 0, 0, loc).Add(-time.Nanosecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewTimePeriodFactory(loc)
+			factory.now = func() time.Time { return tt.now }
+
+			period := factory.CreateDaily()
+			if !period.StartAt().Equal(tt.expectedStart) {
+				t.Errorf("daily period start: got %v, want %v", period.StartAt(), tt.expectedStart)
+			}
+			if !period.EndAt().Equal(tt.expectedEnd) {
+				t.Errorf("daily period end: got %v, want %v", period.EndAt(), tt.expectedEnd)
+			}
+		})
+	}
+}
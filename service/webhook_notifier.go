@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier posts the daily summary as JSON to a webhook URL.
+// The message is sent as "text", which Slack-compatible incoming webhooks post as is.
+type WebhookNotifier struct {
+	url        string
+	client     *http.Client
+	costFormat entity.CostFormat
+}
+
+// webhookDailySummary is the JSON payload of the daily summary
+type webhookDailySummary struct {
+	Text     string  `json:"text"`
+	Cost     float64 `json:"cost"`
+	Requests int     `json:"requests"`
+	Tokens   int64   `json:"tokens"`
+	PlanPace *int    `json:"plan_pace,omitempty"` // percent of the plan pace, omitted without a priced plan
+}
+
+// NewWebhookNotifier creates a new webhook notifier for the URL.
+func NewWebhookNotifier(url string, costFormat entity.CostFormat) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		client:     &http.Client{Timeout: webhookTimeout},
+		costFormat: costFormat,
+	}
+}
+
+// NotifyDailySummary posts the summary, any non-2xx response is an error.
+func (n *WebhookNotifier) NotifyDailySummary(ctx context.Context, summary entity.DailySummary) error {
+	stats := summary.Stats()
+	payload := webhookDailySummary{
+		Text:     summary.Message(n.costFormat),
+		Cost:     stats.TotalCost().Amount(),
+		Requests: stats.TotalRequests(),
+		Tokens:   stats.TotalTokens().Total(),
+	}
+	if pace, ok := summary.PlanPace(); ok {
+		payload.PlanPace = &pace
+	}
+
+	return postWebhook(ctx, n.client, n.url, payload)
+}
+
+// postWebhook posts the payload as JSON to the URL, any non-2xx response is an error.
+func postWebhook(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+func TestWebhookNotifier_NotifyDailySummary(t *testing.T) {
+	day := entity.NewPeriod(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 23, 59, 59, 0, time.UTC))
+	stats := entity.NewStatsFromRequests([]entity.APIRequest{
+		entity.NewAPIRequest("s1", day.StartAt(), "claude-sonnet-4-20250514", entity.NewToken(1000, 500, 0, 0), entity.NewCost(0.4), 0),
+	}, day)
+
+	tests := []struct {
+		name         string
+		plan         entity.Plan
+		status       int
+		expectedBody map[string]any
+		errMsg       string
+	}{
+		{
+			name:   "posts the summary with the plan pace",
+			plan:   entity.NewPlan("pro", entity.NewCost(20)),
+			status: http.StatusOK,
+			expectedBody: map[string]any{
+				"text":      "Today: $0.40, 1 requests, 60% of plan pace",
+				"cost":      0.4,
+				"requests":  float64(1),
+				"tokens":    float64(1500),
+				"plan_pace": float64(60),
+			},
+		},
+		{
+			name:   "leaves out the plan pace without a priced plan",
+			plan:   entity.NewPlan("unset", entity.NewCost(0)),
+			status: http.StatusNoContent,
+			expectedBody: map[string]any{
+				"text":     "Today: $0.40, 1 requests",
+				"cost":     0.4,
+				"requests": float64(1),
+				"tokens":   float64(1500),
+			},
+		},
+		{
+			name:   "non-2xx response is an error",
+			plan:   entity.NewPlan("unset", entity.NewCost(0)),
+			status: http.StatusInternalServerError,
+			errMsg: "500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST, got %s", r.Method)
+				}
+				if got := r.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Expected JSON content type, got %q", got)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("Failed to decode body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			notifier := NewWebhookNotifier(server.URL, entity.DefaultCostFormat())
+			err := notifier.NotifyDailySummary(context.Background(), entity.NewDailySummary(stats, tt.plan))
+
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NotifyDailySummary() returned error: %v", err)
+			}
+
+			if len(body) != len(tt.expectedBody) {
+				t.Errorf("Expected %d fields, got %v", len(tt.expectedBody), body)
+			}
+			for key, want := range tt.expectedBody {
+				if body[key] != want {
+					t.Errorf("Expected %s = %v, got %v", key, want, body[key])
+				}
+			}
+		})
+	}
+}
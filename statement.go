@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runStatement writes the monthly statement to stdout and returns the exit code
+func runStatement(config *Config, month string, output string) int {
+	if err := cli.ValidateStatementOutput(output); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	// Default to the current month
+	if month == "" {
+		month = time.Now().In(timezone).Format("2006-01")
+	}
+	monthStart, err := entity.ParseStatementMonth(month, timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	apiRepo, err := repository.NewGRPCAPIRequestRepository(config.Monitor.Server)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC repository: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := apiRepo.Close(); err != nil {
+			log.Printf("Error closing gRPC repository: %v", err)
+		}
+	}()
+
+	planRepository, err := repository.NewEmbeddedPlanRepository(config, dataFS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize plan repository: %v\n", err)
+		return 1
+	}
+
+	// Statements show exact amounts, humanized costs like "1.2k" do not belong on an expense report
+	costFormat := entity.NewCostFormat(config.Display.GetCostFormat().Precision(), false)
+
+	handler := cli.NewStatementHandler(usecase.NewGetStatementQuery(apiRepo, planRepository, createSessionTitleRepository(config.Claude)), costFormat)
+	if err := handler.HandleStatement(monthStart, output, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	return 0
+}
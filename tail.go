@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runTail prints the requests the server stores as log lines until interrupted and returns the exit code
+func runTail(config *Config) int {
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC connection: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watchQuery := usecase.NewWatchApiRequestsQuery(repository.NewGRPCAPIRequestRepositoryWithConnection(conn))
+	handler := cli.NewTailHandler(watchQuery, timezone)
+	if err := handler.HandleTail(ctx, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to tail %s: %s\n", config.Monitor.Server, entity.ErrorMessage(err))
+		return 1
+	}
+	return 0
+}
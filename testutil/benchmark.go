@@ -0,0 +1,21 @@
+package testutil
+
+import "testing"
+
+// allocationBudgetRuns is the number of runs averaged by CheckAllocationBudget
+const allocationBudgetRuns = 20
+
+// CheckAllocationBudget fails the test when f allocates more than budget times per run on average
+// Allocation counts don't depend on the machine speed, so the budgets guard hot paths in every test run
+// The check is skipped under the race detector, which adds allocations
+func CheckAllocationBudget(t *testing.T, budget float64, f func()) {
+	t.Helper()
+
+	if raceEnabled {
+		t.Skip("allocation budgets are not checked under the race detector")
+	}
+
+	if allocs := testing.AllocsPerRun(allocationBudgetRuns, f); allocs > budget {
+		t.Errorf("Expected at most %.0f allocations per run, got %.0f", budget, allocs)
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !race
+
+package testutil
+
+// raceEnabled reports whether the race detector is on, it adds allocations to instrumented code
+const raceEnabled = false
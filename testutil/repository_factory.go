@@ -1,7 +1,9 @@
 package testutil
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -18,8 +20,10 @@ func (e *MockError) Error() string {
 
 // MockAPIRequestRepository implements usecase.APIRequestRepository for testing
 type MockAPIRequestRepository struct {
-	requests []entity.APIRequest
-	err      error
+	mu             sync.Mutex // guards saves from concurrent receiver workers
+	requests       []entity.APIRequest
+	err            error
+	saveBatchCalls int
 }
 
 // NewMockAPIRequestRepository creates a new mock API request repository
@@ -49,6 +53,8 @@ func (m *MockAPIRequestRepository) SetError(err error) {
 
 // Save implements usecase.APIRequestRepository
 func (m *MockAPIRequestRepository) Save(req entity.APIRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return m.err
 	}
@@ -56,6 +62,25 @@ func (m *MockAPIRequestRepository) Save(req entity.APIRequest) error {
 	return nil
 }
 
+// SaveBatch implements usecase.APIRequestBatchRepository
+func (m *MockAPIRequestRepository) SaveBatch(reqs []entity.APIRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saveBatchCalls++
+	if m.err != nil {
+		return m.err
+	}
+	m.requests = append(m.requests, reqs...)
+	return nil
+}
+
+// SaveBatchCalls returns how many times SaveBatch was called
+func (m *MockAPIRequestRepository) SaveBatchCalls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.saveBatchCalls
+}
+
 // FindByPeriodWithLimit implements usecase.APIRequestRepository
 func (m *MockAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, limit int, offset int) ([]entity.APIRequest, error) {
 	if m.err != nil {
@@ -86,6 +111,8 @@ func (m *MockAPIRequestRepository) FindByPeriodWithLimit(period entity.Period, l
 
 // FindAll implements usecase.APIRequestRepository
 func (m *MockAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -93,7 +120,7 @@ func (m *MockAPIRequestRepository) FindAll() ([]entity.APIRequest, error) {
 }
 
 // DeleteOlderThan implements usecase.APIRequestRepository
-func (m *MockAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+func (m *MockAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
 	if m.err != nil {
 		return 0, m.err
 	}
@@ -102,7 +129,7 @@ func (m *MockAPIRequestRepository) DeleteOlderThan(cutoffTime time.Time) (int, e
 	deletedCount := 0
 
 	for _, req := range m.requests {
-		if req.Timestamp().Before(cutoffTime) {
+		if req.Timestamp().Before(cutoffTime) && !keep.IsStarred(req) {
 			deletedCount++
 		} else {
 			remaining = append(remaining, req)
@@ -129,7 +156,25 @@ func (m *MockStatsRepository) GetStatsByPeriod(period entity.Period) (entity.Sta
 	if err != nil {
 		return entity.Stats{}, err
 	}
-	return entity.NewStatsFromRequests(requests, period), nil
+	return entity.NewStatsFromRequests(requests, period).WithModels(entity.NewModelStatsFromRequests(requests)), nil
+}
+
+// GetStatsByFilter implements usecase.StatsFilterRepository
+func (m *MockStatsRepository) GetStatsByFilter(filter entity.Filter) (entity.Stats, error) {
+	requests, err := m.apiRepo.FindByPeriodWithLimit(filter.Period(), 0, 0)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+	return entity.NewStatsFromRequests(filter.Apply(requests), filter.Period()), nil
+}
+
+// EstimateStatsByPeriod implements usecase.StatsEstimateRepository, the exact stats are marked as approximate
+func (m *MockStatsRepository) EstimateStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	stats, err := m.GetStatsByPeriod(period)
+	if err != nil {
+		return entity.Stats{}, err
+	}
+	return stats.WithApproximate(true), nil
 }
 
 // InstrumentedRepository wraps a repository to count method calls for performance testing
@@ -163,8 +208,8 @@ func (r *InstrumentedRepository) FindAll() ([]entity.APIRequest, error) {
 }
 
 // DeleteOlderThan implements usecase.APIRequestRepository
-func (r *InstrumentedRepository) DeleteOlderThan(cutoffTime time.Time) (int, error) {
-	return r.repo.DeleteOlderThan(cutoffTime)
+func (r *InstrumentedRepository) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
+	return r.repo.DeleteOlderThan(cutoffTime, keep)
 }
 
 // InstrumentedStatsRepository wraps InstrumentedRepository to implement StatsRepository
@@ -249,10 +294,11 @@ func CreateTestStats() entity.Stats {
 
 // MockStatsCache implements usecase.StatsCache for testing
 type MockStatsCache struct {
-	getFunc   func(period entity.Period) *entity.Stats
-	setFunc   func(period entity.Period, stats *entity.Stats)
-	getCalled int
-	setCalled int
+	getFunc     func(period entity.Period) *entity.Stats
+	setFunc     func(period entity.Period, stats *entity.Stats)
+	getCalled   int
+	setCalled   int
+	invalidated []time.Time
 }
 
 // NewMockStatsCache creates a new mock stats cache
@@ -302,6 +348,16 @@ func (m *MockStatsCache) Set(period entity.Period, stats *entity.Stats) {
 	}
 }
 
+// Invalidate implements usecase.StatsCache
+func (m *MockStatsCache) Invalidate(at time.Time) {
+	m.invalidated = append(m.invalidated, at)
+}
+
+// Invalidated returns the times passed to Invalidate in order
+func (m *MockStatsCache) Invalidated() []time.Time {
+	return m.invalidated
+}
+
 // NoOpStatsCache creates a cache that does nothing (for testing when caching is disabled)
 func NewNoOpStatsCache() *MockStatsCache {
 	return &MockStatsCache{
@@ -312,8 +368,9 @@ func NewNoOpStatsCache() *MockStatsCache {
 
 // MockPlanRepository implements usecase.PlanRepository for testing
 type MockPlanRepository struct {
-	plan entity.Plan
-	err  error
+	plan  entity.Plan
+	users map[string]entity.Plan
+	err   error
 }
 
 // NewMockPlanRepository creates a new mock plan repository
@@ -336,17 +393,55 @@ func (m *MockPlanRepository) SetError(err error) {
 	m.err = err
 }
 
+// SetUserPlan assigns a plan to the user
+func (m *MockPlanRepository) SetUserPlan(user string, plan entity.Plan) {
+	if m.users == nil {
+		m.users = make(map[string]entity.Plan)
+	}
+	m.users[user] = plan
+}
+
 // GetConfiguredPlan implements usecase.PlanRepository
 func (m *MockPlanRepository) GetConfiguredPlan() (entity.Plan, error) {
 	return m.plan, m.err
 }
 
+// GetUserPlan implements usecase.UserPlanRepository, users without an own plan get the configured plan
+func (m *MockPlanRepository) GetUserPlan(user string) (entity.Plan, error) {
+	if plan, ok := m.users[user]; ok {
+		return plan, m.err
+	}
+	return m.plan, m.err
+}
+
+// MockPricingRepository implements usecase.PricingRepository for testing
+type MockPricingRepository struct {
+	table entity.PriceTable
+	err   error
+}
+
+// NewMockPricingRepository creates a new mock pricing repository with the prices of the models
+func NewMockPricingRepository(prices map[string]entity.ModelPrice) *MockPricingRepository {
+	return &MockPricingRepository{table: entity.NewPriceTable(prices)}
+}
+
+// SetError sets an error to be returned by the repository
+func (m *MockPricingRepository) SetError(err error) {
+	m.err = err
+}
+
+// GetPriceTable implements usecase.PricingRepository
+func (m *MockPricingRepository) GetPriceTable() (entity.PriceTable, error) {
+	return m.table, m.err
+}
+
 // MockRepositoryWithDeleteFunc allows customization of DeleteOlderThan behavior for cleanup testing
 type MockRepositoryWithDeleteFunc struct {
 	*MockAPIRequestRepository
 	deleteOlderThanFunc func(cutoffTime time.Time) (int, error)
 	deleteCallCount     int
 	lastCutoffTime      time.Time
+	lastKeep            entity.Stars
 }
 
 // NewMockRepositoryWithDeleteFunc creates a repository with customizable DeleteOlderThan behavior
@@ -358,13 +453,14 @@ func NewMockRepositoryWithDeleteFunc(deleteFunc func(cutoffTime time.Time) (int,
 }
 
 // DeleteOlderThan overrides the base implementation with custom behavior
-func (m *MockRepositoryWithDeleteFunc) DeleteOlderThan(cutoffTime time.Time) (int, error) {
+func (m *MockRepositoryWithDeleteFunc) DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error) {
 	m.deleteCallCount++
 	m.lastCutoffTime = cutoffTime
+	m.lastKeep = keep
 	if m.deleteOlderThanFunc != nil {
 		return m.deleteOlderThanFunc(cutoffTime)
 	}
-	return m.MockAPIRequestRepository.DeleteOlderThan(cutoffTime)
+	return m.MockAPIRequestRepository.DeleteOlderThan(cutoffTime, keep)
 }
 
 // GetDeleteCallCount returns the number of DeleteOlderThan calls
@@ -377,6 +473,11 @@ func (m *MockRepositoryWithDeleteFunc) GetLastCutoffTime() time.Time {
 	return m.lastCutoffTime
 }
 
+// GetLastKeep returns the last starred records passed to DeleteOlderThan
+func (m *MockRepositoryWithDeleteFunc) GetLastKeep() entity.Stars {
+	return m.lastKeep
+}
+
 // MockRepositoryWithCustomFunc allows custom behavior for FindByPeriodWithLimit
 type MockRepositoryWithCustomFunc struct {
 	*MockAPIRequestRepository
@@ -521,3 +622,211 @@ func CreateTestAPIRequestsSetInTimezone(dailyBaseRequests, dailyPremiumRequests,
 
 	return requests
 }
+
+// MockIngestionLagRepository implements usecase.IngestionLagRepository for testing
+type MockIngestionLagRepository struct {
+	lag entity.IngestionLag
+	err error
+}
+
+// NewMockIngestionLagRepository creates a mock repository returning the given lag
+func NewMockIngestionLagRepository(lag entity.IngestionLag) *MockIngestionLagRepository {
+	return &MockIngestionLagRepository{lag: lag}
+}
+
+// SetError sets the error to be returned by GetIngestionLag
+func (m *MockIngestionLagRepository) SetError(err error) {
+	m.err = err
+}
+
+// GetIngestionLag implements usecase.IngestionLagRepository
+func (m *MockIngestionLagRepository) GetIngestionLag() (entity.IngestionLag, error) {
+	if m.err != nil {
+		return entity.IngestionLag{}, m.err
+	}
+	return m.lag, nil
+}
+
+// MockIngestStatsRepository implements usecase.IngestStatsRepository for testing
+type MockIngestStatsRepository struct {
+	stats entity.IngestStats
+	err   error
+}
+
+// NewMockIngestStatsRepository creates a mock repository returning the given stats
+func NewMockIngestStatsRepository(stats entity.IngestStats) *MockIngestStatsRepository {
+	return &MockIngestStatsRepository{stats: stats}
+}
+
+// SetError sets the error to be returned by GetIngestStats
+func (m *MockIngestStatsRepository) SetError(err error) {
+	m.err = err
+}
+
+// GetIngestStats implements usecase.IngestStatsRepository
+func (m *MockIngestStatsRepository) GetIngestStats() (entity.IngestStats, error) {
+	if m.err != nil {
+		return entity.IngestStats{}, m.err
+	}
+	return m.stats, nil
+}
+
+// MockRetentionRepository implements usecase.RetentionRepository for testing
+type MockRetentionRepository struct {
+	retention entity.Retention
+	err       error
+}
+
+// NewMockRetentionRepository creates a mock repository returning the given retention
+func NewMockRetentionRepository(retention entity.Retention) *MockRetentionRepository {
+	return &MockRetentionRepository{retention: retention}
+}
+
+// SetError sets the error to be returned by GetRetention
+func (m *MockRetentionRepository) SetError(err error) {
+	m.err = err
+}
+
+// GetRetention implements usecase.RetentionRepository
+func (m *MockRetentionRepository) GetRetention() (entity.Retention, error) {
+	if m.err != nil {
+		return entity.Retention{}, m.err
+	}
+	return m.retention, nil
+}
+
+// MockAPIRequestWatchRepository implements usecase.APIRequestWatchRepository for testing
+// It notifies the batches in order, then returns the error as if the server closed the stream
+type MockAPIRequestWatchRepository struct {
+	batches [][]entity.APIRequest
+	err     error
+}
+
+// NewMockAPIRequestWatchRepository creates a mock repository notifying the given batches
+func NewMockAPIRequestWatchRepository(batches ...[]entity.APIRequest) *MockAPIRequestWatchRepository {
+	return &MockAPIRequestWatchRepository{batches: batches}
+}
+
+// SetError sets the error to be returned after the batches are notified
+func (m *MockAPIRequestWatchRepository) SetError(err error) {
+	m.err = err
+}
+
+// WatchByFilter implements usecase.APIRequestWatchRepository
+func (m *MockAPIRequestWatchRepository) WatchByFilter(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error {
+	for _, batch := range m.batches {
+		if err := notify(filter.Apply(batch)); err != nil {
+			return err
+		}
+	}
+	return m.err
+}
+
+// MockStarRepository implements usecase.StarRepository for testing
+type MockStarRepository struct {
+	requests map[string]struct{}
+	sessions map[string]struct{}
+	err      error
+}
+
+// NewMockStarRepository creates a new mock star repository without stars
+func NewMockStarRepository() *MockStarRepository {
+	return &MockStarRepository{
+		requests: make(map[string]struct{}),
+		sessions: make(map[string]struct{}),
+	}
+}
+
+// SetError sets the error to be returned by all methods
+func (m *MockStarRepository) SetError(err error) {
+	m.err = err
+}
+
+// GetStars implements usecase.StarRepository
+func (m *MockStarRepository) GetStars() (entity.Stars, error) {
+	if m.err != nil {
+		return entity.Stars{}, m.err
+	}
+
+	requestIDs := make([]string, 0, len(m.requests))
+	for id := range m.requests {
+		requestIDs = append(requestIDs, id)
+	}
+	sessionIDs := make([]string, 0, len(m.sessions))
+	for id := range m.sessions {
+		sessionIDs = append(sessionIDs, id)
+	}
+	return entity.NewStars(requestIDs, sessionIDs), nil
+}
+
+// SetStar implements usecase.StarRepository
+func (m *MockStarRepository) SetStar(requestID string, sessionID string, scope entity.StarScope) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	delete(m.requests, requestID)
+	delete(m.sessions, sessionID)
+	switch scope {
+	case entity.StarRequest:
+		m.requests[requestID] = struct{}{}
+	case entity.StarSession:
+		m.sessions[sessionID] = struct{}{}
+	}
+	return nil
+}
+
+// MockTranscriptUsageRepository implements usecase.TranscriptUsageRepository for testing
+type MockTranscriptUsageRepository struct {
+	requests []entity.APIRequest
+	err      error
+}
+
+// NewMockTranscriptUsageRepository creates a new mock transcript usage repository returning the requests for any path
+func NewMockTranscriptUsageRepository(requests []entity.APIRequest) *MockTranscriptUsageRepository {
+	return &MockTranscriptUsageRepository{requests: requests}
+}
+
+// SetError sets the error to be returned by FindAPIRequests
+func (m *MockTranscriptUsageRepository) SetError(err error) {
+	m.err = err
+}
+
+// FindAPIRequests implements usecase.TranscriptUsageRepository
+func (m *MockTranscriptUsageRepository) FindAPIRequests(path string) ([]entity.APIRequest, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.requests, nil
+}
+
+// MockSessionTitleRepository implements usecase.SessionTitleRepository for testing
+type MockSessionTitleRepository struct {
+	titles entity.SessionTitles
+	err    error
+}
+
+// NewMockSessionTitleRepository creates a new mock session title repository with the given titles
+func NewMockSessionTitleRepository(titles entity.SessionTitles) *MockSessionTitleRepository {
+	return &MockSessionTitleRepository{titles: titles}
+}
+
+// SetError sets the error to be returned by FindSessionTitles
+func (m *MockSessionTitleRepository) SetError(err error) {
+	m.err = err
+}
+
+// FindSessionTitles implements usecase.SessionTitleRepository
+func (m *MockSessionTitleRepository) FindSessionTitles(sessionIDs []string) (entity.SessionTitles, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	titles := make(entity.SessionTitles)
+	for _, sessionID := range sessionIDs {
+		if title, ok := m.titles[sessionID]; ok {
+			titles[sessionID] = title
+		}
+	}
+	return titles, nil
+}
@@ -143,7 +143,7 @@ func TestMockAPIRequestRepository_DeleteOlderThan(t *testing.T) {
 		t.Fatalf("Failed to save newReq: %v", err)
 	}
 
-	deletedCount, err := repo.DeleteOlderThan(cutoff)
+	deletedCount, err := repo.DeleteOlderThan(cutoff, entity.Stars{})
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
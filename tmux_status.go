@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/elct9620/ccmon/handler/cli"
+	"github.com/elct9620/ccmon/repository"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/usecase"
+)
+
+// runTmuxStatus prints a tmux status segment for `#(ccmon tmux-status)` and returns the exit code
+func runTmuxStatus(config *Config, blockTime string) int {
+	timezone, err := time.LoadLocation(config.Monitor.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %v\n", err)
+		return 1
+	}
+
+	// Block tracking is optional, only daily cost is shown without it
+	block, err := createBlock(blockTime, timezone, config.Claude.GetTokenLimit(), config.Claude.GetBlockDuration())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	conn, err := dialMonitorServer(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize gRPC connection: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("Error closing gRPC connection: %v", err)
+		}
+	}()
+
+	apiRepo := repository.NewGRPCAPIRequestRepositoryWithConnection(conn)
+	statsRepo := repository.NewGRPCStatsRepositoryWithConnection(conn)
+
+	// tmux runs the command on every status refresh, so the cache would never be hit
+	calculateStatsQuery := usecase.NewCalculateStatsQuery(repository.NegotiateStatsRepository(statsRepo, apiRepo), &service.NoOpStatsCache{})
+	periodFactory := service.NewTimePeriodFactory(timezone)
+
+	handler := cli.NewTmuxStatusHandler(calculateStatsQuery, periodFactory, block, config.Display.GetCostFormat())
+	if err := handler.HandleTmuxStatus(); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,12 @@
+package usecase
+
+import "github.com/elct9620/ccmon/entity"
+
+// APIRequestFeed defines the interface for pushing stored API requests to the watchers.
+// Implementations must not block the publisher when a watcher is slow.
+type APIRequestFeed interface {
+	APIRequestWatchRepository
+
+	// Publish delivers the stored requests to every watcher whose filter matches them.
+	Publish(requests []entity.APIRequest)
+}
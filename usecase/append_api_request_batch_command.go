@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// AppendApiRequestBatchCommand handles the command to append multiple API requests at once
+type AppendApiRequestBatchCommand struct {
+	repository APIRequestBatchRepository
+	feed       APIRequestFeed
+	cache      StatsCache
+	duplicates DuplicateFilter
+}
+
+// AppendApiRequestBatchOptions contains the optional collaborators notified of the stored requests
+type AppendApiRequestBatchOptions struct {
+	Feed       APIRequestFeed  // pushes the stored requests to the watchers
+	StatsCache StatsCache      // evicts the cached stats covering the stored requests
+	Duplicates DuplicateFilter // drops requests already appended within the dedupe window
+}
+
+// NewAppendApiRequestBatchCommand creates a new AppendApiRequestBatchCommand with the given repository
+func NewAppendApiRequestBatchCommand(repository APIRequestBatchRepository) *AppendApiRequestBatchCommand {
+	return &AppendApiRequestBatchCommand{
+		repository: repository,
+	}
+}
+
+// NewAppendApiRequestBatchCommandWithFeed creates a new AppendApiRequestBatchCommand which publishes the stored requests to the feed
+func NewAppendApiRequestBatchCommandWithFeed(repository APIRequestBatchRepository, feed APIRequestFeed) *AppendApiRequestBatchCommand {
+	return NewAppendApiRequestBatchCommandWithOptions(repository, AppendApiRequestBatchOptions{Feed: feed})
+}
+
+// NewAppendApiRequestBatchCommandWithOptions creates a new AppendApiRequestBatchCommand with the optional feed and stats cache
+func NewAppendApiRequestBatchCommandWithOptions(repository APIRequestBatchRepository, options AppendApiRequestBatchOptions) *AppendApiRequestBatchCommand {
+	return &AppendApiRequestBatchCommand{
+		repository: repository,
+		feed:       options.Feed,
+		cache:      options.StatsCache,
+		duplicates: options.Duplicates,
+	}
+}
+
+// AppendApiRequestBatchResult contains the outcome of appending a batch of API requests
+type AppendApiRequestBatchResult struct {
+	Saved      int
+	Duplicates int // repeated within the batch or appended within the dedupe window
+}
+
+// Execute executes the append API request batch command
+// Requests sharing the same ID within the batch are stored once, keeping the first occurrence
+// With a duplicate filter, requests appended by an earlier batch within the dedupe window are dropped too
+func (c *AppendApiRequestBatchCommand) Execute(ctx context.Context, params []AppendApiRequestParams) (AppendApiRequestBatchResult, error) {
+	seen := make(map[string]struct{}, len(params))
+	apiRequests := make([]entity.APIRequest, 0, len(params))
+
+	for _, p := range params {
+		apiRequest := p.apiRequest()
+
+		if _, ok := seen[apiRequest.ID()]; ok {
+			continue
+		}
+		seen[apiRequest.ID()] = struct{}{}
+		if c.duplicates != nil && c.duplicates.Seen(apiRequest.DedupeKey()) {
+			continue
+		}
+		apiRequests = append(apiRequests, apiRequest)
+	}
+
+	result := AppendApiRequestBatchResult{
+		Saved:      len(apiRequests),
+		Duplicates: len(params) - len(apiRequests),
+	}
+
+	if len(apiRequests) == 0 {
+		return result, nil
+	}
+
+	if err := c.repository.SaveBatch(apiRequests); err != nil {
+		// The failed records are reported to the exporter as rejected, they must not be taken for duplicates when sent again
+		if c.duplicates != nil {
+			for _, apiRequest := range apiRequests {
+				c.duplicates.Forget(apiRequest.DedupeKey())
+			}
+		}
+		return AppendApiRequestBatchResult{}, err
+	}
+
+	// Cached stats are evicted before watchers are told, so their refresh includes the stored requests
+	if c.cache != nil {
+		for _, apiRequest := range apiRequests {
+			c.cache.Invalidate(apiRequest.Timestamp())
+		}
+	}
+
+	// Watchers only see requests which are stored, so a refresh after the push always includes them
+	if c.feed != nil {
+		c.feed.Publish(apiRequests)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestAppendApiRequestBatchCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newParams := func(sessionID string, offset time.Duration) AppendApiRequestParams {
+		return AppendApiRequestParams{
+			SessionID:  sessionID,
+			Timestamp:  baseTime.Add(offset),
+			Model:      "claude-sonnet-4-20250514",
+			Tokens:     entity.NewToken(100, 50, 0, 0),
+			Cost:       entity.NewCost(0.01),
+			DurationMS: 1000,
+		}
+	}
+
+	tests := []struct {
+		name              string
+		params            []AppendApiRequestParams
+		repoErr           error
+		expectedResult    AppendApiRequestBatchResult
+		expectError       bool
+		expectedStored    int
+		expectedCallCount int
+	}{
+		{
+			name: "stores all requests in a single call",
+			params: []AppendApiRequestParams{
+				newParams("session-1", 0),
+				newParams("session-1", time.Second),
+				newParams("session-2", 0),
+			},
+			expectedResult:    AppendApiRequestBatchResult{Saved: 3},
+			expectedStored:    3,
+			expectedCallCount: 1,
+		},
+		{
+			name: "drops duplicated requests within the batch",
+			params: []AppendApiRequestParams{
+				newParams("session-1", 0),
+				newParams("session-1", 0),
+				newParams("session-2", 0),
+				newParams("session-1", 0),
+			},
+			expectedResult:    AppendApiRequestBatchResult{Saved: 2, Duplicates: 2},
+			expectedStored:    2,
+			expectedCallCount: 1,
+		},
+		{
+			name:              "empty batch skips the repository",
+			params:            nil,
+			expectedResult:    AppendApiRequestBatchResult{},
+			expectedStored:    0,
+			expectedCallCount: 0,
+		},
+		{
+			name: "returns repository error",
+			params: []AppendApiRequestParams{
+				newParams("session-1", 0),
+			},
+			repoErr:           &testutil.MockError{Message: "database connection failed"},
+			expectedResult:    AppendApiRequestBatchResult{},
+			expectError:       true,
+			expectedStored:    0,
+			expectedCallCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			if tt.repoErr != nil {
+				repo.SetError(tt.repoErr)
+			}
+			command := NewAppendApiRequestBatchCommand(repo)
+
+			result, err := command.Execute(context.Background(), tt.params)
+
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if result != tt.expectedResult {
+				t.Errorf("Expected result %+v, got %+v", tt.expectedResult, result)
+			}
+			if repo.SaveBatchCalls() != tt.expectedCallCount {
+				t.Errorf("Expected %d SaveBatch calls, got %d", tt.expectedCallCount, repo.SaveBatchCalls())
+			}
+
+			repo.SetError(nil)
+			stored, _ := repo.FindAll()
+			if len(stored) != tt.expectedStored {
+				t.Errorf("Expected %d stored requests, got %d", tt.expectedStored, len(stored))
+			}
+		})
+	}
+}
+
+// recordingFeed records the published batches
+type recordingFeed struct {
+	published [][]entity.APIRequest
+}
+
+func (f *recordingFeed) Publish(requests []entity.APIRequest) {
+	f.published = append(f.published, requests)
+}
+
+func (f *recordingFeed) WatchByFilter(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestAppendApiRequestBatchCommand_PublishesToFeed(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	params := []AppendApiRequestParams{
+		{SessionID: "session-1", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+		{SessionID: "session-1", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+		{SessionID: "session-2", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+	}
+
+	t.Run("publishes the stored requests once", func(t *testing.T) {
+		t.Parallel()
+
+		feed := &recordingFeed{}
+		command := NewAppendApiRequestBatchCommandWithFeed(testutil.NewMockAPIRequestRepository(), feed)
+
+		if _, err := command.Execute(context.Background(), params); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(feed.published) != 1 {
+			t.Fatalf("Expected 1 published batch, got %d", len(feed.published))
+		}
+		if len(feed.published[0]) != 2 {
+			t.Errorf("Expected 2 published requests without duplicates, got %d", len(feed.published[0]))
+		}
+	})
+
+	t.Run("does not publish requests which failed to store", func(t *testing.T) {
+		t.Parallel()
+
+		feed := &recordingFeed{}
+		repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database connection failed"})
+		command := NewAppendApiRequestBatchCommandWithFeed(repo, feed)
+
+		if _, err := command.Execute(context.Background(), params); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+		if len(feed.published) != 0 {
+			t.Errorf("Expected no published batch, got %d", len(feed.published))
+		}
+	})
+}
+
+func TestAppendApiRequestBatchCommand_InvalidatesStatsCache(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	params := []AppendApiRequestParams{
+		{SessionID: "session-1", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+		{SessionID: "session-2", Timestamp: baseTime.Add(time.Hour), Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+	}
+
+	t.Run("evicts the stats covering each stored request", func(t *testing.T) {
+		t.Parallel()
+
+		cache := testutil.NewMockStatsCache()
+		command := NewAppendApiRequestBatchCommandWithOptions(testutil.NewMockAPIRequestRepository(), AppendApiRequestBatchOptions{StatsCache: cache})
+
+		if _, err := command.Execute(context.Background(), params); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		invalidated := cache.Invalidated()
+		if len(invalidated) != 2 || !invalidated[0].Equal(baseTime) || !invalidated[1].Equal(baseTime.Add(time.Hour)) {
+			t.Errorf("Expected the timestamps of both requests to be invalidated, got %v", invalidated)
+		}
+	})
+
+	t.Run("keeps the cache when the requests failed to store", func(t *testing.T) {
+		t.Parallel()
+
+		cache := testutil.NewMockStatsCache()
+		repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database connection failed"})
+		command := NewAppendApiRequestBatchCommandWithOptions(repo, AppendApiRequestBatchOptions{StatsCache: cache})
+
+		if _, err := command.Execute(context.Background(), params); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+		if len(cache.Invalidated()) != 0 {
+			t.Errorf("Expected nothing to be invalidated, got %v", cache.Invalidated())
+		}
+	})
+}
+
+// memoryDuplicateFilter remembers every key without expiring it
+type memoryDuplicateFilter struct {
+	seen map[string]struct{}
+}
+
+func (f *memoryDuplicateFilter) Seen(key string) bool {
+	if _, ok := f.seen[key]; ok {
+		return true
+	}
+	f.seen[key] = struct{}{}
+	return false
+}
+
+func (f *memoryDuplicateFilter) Forget(key string) {
+	delete(f.seen, key)
+}
+
+func TestAppendApiRequestBatchCommand_DropsResentRequests(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := []AppendApiRequestParams{
+		{SessionID: "session-1", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+		{SessionID: "session-2", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01), EventID: "event-2"},
+	}
+	// The retry repeats both records, the second at another time but with the same event ID
+	retried := []AppendApiRequestParams{
+		first[0],
+		{SessionID: "session-2", Timestamp: baseTime.Add(time.Minute), Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01), EventID: "event-2"},
+		{SessionID: "session-3", Timestamp: baseTime, Model: "claude-sonnet-4-20250514", Tokens: entity.NewToken(100, 50, 0, 0), Cost: entity.NewCost(0.01)},
+	}
+
+	t.Run("drops requests appended by an earlier batch", func(t *testing.T) {
+		t.Parallel()
+
+		feed := &recordingFeed{}
+		repo := testutil.NewMockAPIRequestRepository()
+		command := NewAppendApiRequestBatchCommandWithOptions(repo, AppendApiRequestBatchOptions{Feed: feed, Duplicates: &memoryDuplicateFilter{seen: map[string]struct{}{}}})
+
+		if _, err := command.Execute(context.Background(), first); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		result, err := command.Execute(context.Background(), retried)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := (AppendApiRequestBatchResult{Saved: 1, Duplicates: 2}); result != expected {
+			t.Errorf("Expected result %+v, got %+v", expected, result)
+		}
+
+		stored, _ := repo.FindAll()
+		if len(stored) != 3 {
+			t.Errorf("Expected 3 stored requests, got %d", len(stored))
+		}
+		if len(feed.published) != 2 || len(feed.published[1]) != 1 || feed.published[1][0].SessionID() != "session-3" {
+			t.Errorf("Expected only the new request to be published, got %v", feed.published)
+		}
+	})
+
+	t.Run("stores the retry of a failed batch", func(t *testing.T) {
+		t.Parallel()
+
+		repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database connection failed"})
+		command := NewAppendApiRequestBatchCommandWithOptions(repo, AppendApiRequestBatchOptions{Duplicates: &memoryDuplicateFilter{seen: map[string]struct{}{}}})
+
+		if _, err := command.Execute(context.Background(), first); err == nil {
+			t.Fatal("Expected error but got none")
+		}
+		repo.SetError(nil)
+		result, err := command.Execute(context.Background(), first)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if expected := (AppendApiRequestBatchResult{Saved: 2}); result != expected {
+			t.Errorf("Expected result %+v, got %+v", expected, result)
+		}
+	})
+}
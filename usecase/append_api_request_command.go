@@ -10,6 +10,14 @@ import (
 // AppendApiRequestCommand handles the command to append a new API request
 type AppendApiRequestCommand struct {
 	repository APIRequestRepository
+	cache      StatsCache
+	duplicates DuplicateFilter
+}
+
+// AppendApiRequestOptions contains the optional collaborators of appending an API request
+type AppendApiRequestOptions struct {
+	StatsCache StatsCache      // evicts the cached stats covering the stored request
+	Duplicates DuplicateFilter // rejects requests already appended within the dedupe window
 }
 
 // NewAppendApiRequestCommand creates a new AppendApiRequestCommand with the given repository
@@ -19,6 +27,20 @@ func NewAppendApiRequestCommand(repository APIRequestRepository) *AppendApiReque
 	}
 }
 
+// NewAppendApiRequestCommandWithStatsCache creates a new AppendApiRequestCommand which evicts the cached stats covering the stored request
+func NewAppendApiRequestCommandWithStatsCache(repository APIRequestRepository, cache StatsCache) *AppendApiRequestCommand {
+	return NewAppendApiRequestCommandWithOptions(repository, AppendApiRequestOptions{StatsCache: cache})
+}
+
+// NewAppendApiRequestCommandWithOptions creates a new AppendApiRequestCommand with the optional stats cache and duplicate filter
+func NewAppendApiRequestCommandWithOptions(repository APIRequestRepository, options AppendApiRequestOptions) *AppendApiRequestCommand {
+	return &AppendApiRequestCommand{
+		repository: repository,
+		cache:      options.StatsCache,
+		duplicates: options.Duplicates,
+	}
+}
+
 // AppendApiRequestParams contains the parameters for appending an API request
 type AppendApiRequestParams struct {
 	SessionID  string
@@ -27,20 +49,47 @@ type AppendApiRequestParams struct {
 	Tokens     entity.Token
 	Cost       entity.Cost
 	DurationMS int64
+	Source     string // Optional, defaults to Claude Code
+	Origin     string // Optional, defaults to live
+	User       string // Optional, empty when telemetry does not identify the user
+	Project    string // Optional, empty when telemetry does not identify the project
+	EventID    string // Optional, identifies the record when the exporter sends it again
 }
 
 // Execute executes the append API request command
+// ErrDuplicateAPIRequest is returned without storing the request when it was appended within the dedupe window
 func (c *AppendApiRequestCommand) Execute(ctx context.Context, params AppendApiRequestParams) error {
 	// Create the API request entity
-	apiRequest := entity.NewAPIRequest(
-		params.SessionID,
-		params.Timestamp,
-		params.Model,
-		params.Tokens,
-		params.Cost,
-		params.DurationMS,
-	)
+	apiRequest := params.apiRequest()
+
+	if c.duplicates != nil && c.duplicates.Seen(apiRequest.DedupeKey()) {
+		return ErrDuplicateAPIRequest
+	}
 
 	// Save the API request via repository
-	return c.repository.Save(apiRequest)
+	if err := c.repository.Save(apiRequest); err != nil {
+		// The exporter retries the failed record, which must not be taken for a duplicate
+		if c.duplicates != nil {
+			c.duplicates.Forget(apiRequest.DedupeKey())
+		}
+		return err
+	}
+
+	// Stats including the request are recalculated on the next query instead of after the TTL
+	if c.cache != nil {
+		c.cache.Invalidate(apiRequest.Timestamp())
+	}
+	return nil
+}
+
+// apiRequest creates the API request entity of the parameters
+func (p AppendApiRequestParams) apiRequest() entity.APIRequest {
+	return entity.NewAPIRequest(
+		p.SessionID,
+		p.Timestamp,
+		p.Model,
+		p.Tokens,
+		p.Cost,
+		p.DurationMS,
+	).WithSource(p.Source).WithOrigin(p.Origin).WithUser(p.User).WithProject(p.Project).WithEventID(p.EventID)
 }
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+	"io"
+)
+
+// BackupDatabaseCommand handles writing a consistent database backup, which a corrupt database is restored from
+type BackupDatabaseCommand struct {
+	snapshotRepository SnapshotRepository
+	backupRepository   BackupRepository
+}
+
+// NewBackupDatabaseCommand creates a new BackupDatabaseCommand with the given repositories
+func NewBackupDatabaseCommand(snapshotRepository SnapshotRepository, backupRepository BackupRepository) *BackupDatabaseCommand {
+	return &BackupDatabaseCommand{
+		snapshotRepository: snapshotRepository,
+		backupRepository:   backupRepository,
+	}
+}
+
+// Execute replaces the backup with a snapshot of the database and returns its size in bytes
+func (c *BackupDatabaseCommand) Execute(ctx context.Context) (int64, error) {
+	return c.backupRepository.SaveBackup(ctx, func(w io.Writer) (int64, error) {
+		return c.snapshotRepository.WriteSnapshot(ctx, w)
+	})
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+// stubSnapshotRepository writes fixed snapshot content
+type stubSnapshotRepository struct {
+	content string
+}
+
+func (r *stubSnapshotRepository) WriteSnapshot(ctx context.Context, w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, r.content)
+	return int64(n), err
+}
+
+// memoryBackupRepository keeps the backup in memory
+type memoryBackupRepository struct {
+	backup bytes.Buffer
+}
+
+func (r *memoryBackupRepository) SaveBackup(ctx context.Context, write func(w io.Writer) (int64, error)) (int64, error) {
+	r.backup.Reset()
+	return write(&r.backup)
+}
+
+func TestBackupDatabaseCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	backupRepo := &memoryBackupRepository{}
+	command := NewBackupDatabaseCommand(&stubSnapshotRepository{content: "snapshot"}, backupRepo)
+
+	size, err := command.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if size != 8 || backupRepo.backup.String() != "snapshot" {
+		t.Errorf("Expected the snapshot to be backed up, got %d bytes %q", size, backupRepo.backup.String())
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/elct9620/ccmon/entity"
 )
@@ -20,18 +21,45 @@ func NewCalculateStatsQuery(statsRepository StatsRepository, cache StatsCache) *
 	}
 }
 
+// ErrStatsFilterUnsupported is returned when stats of an origin, a project or a user are requested from a repository without filter support
+var ErrStatsFilterUnsupported = errors.New("stats repository does not support filtering by origin, project or user")
+
 // CalculateStatsParams contains the parameters for calculating statistics
 type CalculateStatsParams struct {
-	Period entity.Period
+	Period  entity.Period
+	Origin  string // Only requests of this origin, empty for every origin
+	Project string // Only requests of this project, empty for every project
+	User    string // Only requests of this user, empty for every user
+	// Approximate allows stats estimated from a sample when they are not cached, estimates are never cached
+	Approximate bool
+	// Fresh skips cached stats, also in caches behind the repository, the fresh stats replace the cached ones
+	Fresh bool
 }
 
 // Execute executes the calculate statistics query
 func (q *CalculateStatsQuery) Execute(ctx context.Context, params CalculateStatsParams) (entity.Stats, error) {
-	if cachedStats := q.cache.Get(params.Period); cachedStats != nil {
-		return *cachedStats, nil
+	if params.Origin != "" || params.Project != "" || params.User != "" {
+		// Stats of an origin, a project or a user are not cached, the cache is keyed by period only
+		repository, ok := q.statsRepository.(StatsFilterRepository)
+		if !ok {
+			return entity.Stats{}, ErrStatsFilterUnsupported
+		}
+		return repository.GetStatsByFilter(entity.NewFilter(params.Period).WithOrigin(params.Origin).WithProject(params.Project).WithUser(params.User))
+	}
+
+	if !params.Fresh {
+		if cachedStats := q.cache.Get(params.Period); cachedStats != nil {
+			return *cachedStats, nil
+		}
+
+		if params.Approximate {
+			if repository, ok := q.statsRepository.(StatsEstimateRepository); ok {
+				return repository.EstimateStatsByPeriod(params.Period)
+			}
+		}
 	}
 
-	stats, err := q.statsRepository.GetStatsByPeriod(params.Period)
+	stats, err := q.getStatsByPeriod(params.Period, params.Fresh)
 	if err != nil {
 		return entity.Stats{}, err
 	}
@@ -40,3 +68,13 @@ func (q *CalculateStatsQuery) Execute(ctx context.Context, params CalculateStats
 
 	return stats, nil
 }
+
+// getStatsByPeriod retrieves the stats from the repository, fresh stats skip the caches of repositories supporting it
+func (q *CalculateStatsQuery) getStatsByPeriod(period entity.Period, fresh bool) (entity.Stats, error) {
+	if fresh {
+		if repository, ok := q.statsRepository.(StatsFreshRepository); ok {
+			return repository.GetFreshStatsByPeriod(period)
+		}
+	}
+	return q.statsRepository.GetStatsByPeriod(period)
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -60,7 +61,7 @@ func TestCalculateStatsQuery_Execute(t *testing.T) {
 			expectRepoCalled: false,
 			expectCacheSet:   false,
 			validateResult: func(t *testing.T, result entity.Stats) {
-				if result != cachedStats {
+				if !reflect.DeepEqual(result, cachedStats) {
 					t.Error("Expected cached stats to be returned")
 				}
 			},
@@ -118,7 +119,7 @@ func TestCalculateStatsQuery_Execute(t *testing.T) {
 			expectRepoCalled: true,
 			expectCacheSet:   false,
 			validateResult: func(t *testing.T, result entity.Stats) {
-				if result != (entity.Stats{}) {
+				if !reflect.DeepEqual(result, entity.Stats{}) {
 					t.Error("Expected empty stats on error")
 				}
 			},
@@ -178,3 +179,192 @@ func TestCalculateStatsQuery_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateStatsQuery_Execute_Origin(t *testing.T) {
+	now := time.Now()
+	live := entity.NewAPIRequest("live", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	imported := entity.NewAPIRequest("imported", now, "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.02), 1000).WithOrigin(entity.OriginImport)
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		origin        string
+		expectedCount int
+	}{
+		{name: "live only", origin: entity.OriginLive, expectedCount: 1},
+		{name: "import only", origin: entity.OriginImport, expectedCount: 1},
+		{name: "every origin", origin: "", expectedCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{live, imported})
+			query := NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+
+			stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Origin: tt.origin})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if stats.TotalRequests() != tt.expectedCount {
+				t.Errorf("Expected %d requests, got %d", tt.expectedCount, stats.TotalRequests())
+			}
+		})
+	}
+
+	t.Run("unsupported repository", func(t *testing.T) {
+		apiRepo, statsRepo, _ := testutil.NewInstrumentedRepositoryPair()
+		apiRepo.SetMockData([]entity.APIRequest{live, imported})
+		query := NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+
+		_, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Origin: entity.OriginLive})
+		if !errors.Is(err, ErrStatsFilterUnsupported) {
+			t.Errorf("Expected ErrStatsFilterUnsupported, got %v", err)
+		}
+	})
+}
+
+func TestCalculateStatsQuery_Execute_Project(t *testing.T) {
+	now := time.Now()
+	ccmon := entity.NewAPIRequest("ccmon", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000).WithProject("ccmon")
+	other := entity.NewAPIRequest("other", now, "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.02), 1000).WithProject("other")
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{ccmon, other})
+	query := NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+
+	stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Project: "ccmon"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TotalRequests() != 1 {
+		t.Errorf("Expected 1 request, got %d", stats.TotalRequests())
+	}
+	if stats.TotalCost().Amount() != 0.01 {
+		t.Errorf("Expected cost 0.01, got %f", stats.TotalCost().Amount())
+	}
+}
+
+func TestCalculateStatsQuery_Execute_User(t *testing.T) {
+	now := time.Now()
+	alice := entity.NewAPIRequest("alice", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000).WithUser("alice@example.com")
+	bob := entity.NewAPIRequest("bob", now, "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.02), 1000).WithUser("bob@example.com")
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+
+	_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{alice, bob})
+	cache := testutil.NewMockStatsCache()
+	query := NewCalculateStatsQuery(statsRepo, cache)
+
+	stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, User: "bob@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TotalRequests() != 1 || stats.TotalCost().Amount() != 0.02 {
+		t.Errorf("Expected only the request of bob, got %d requests costing %f", stats.TotalRequests(), stats.TotalCost().Amount())
+	}
+	if cache.Get(period) != nil {
+		t.Error("Expected stats of a user not to be cached")
+	}
+}
+
+func TestCalculateStatsQuery_Execute_Approximate(t *testing.T) {
+	now := time.Now()
+	request := entity.NewAPIRequest("session", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+
+	t.Run("estimates on cache miss without caching", func(t *testing.T) {
+		_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{request})
+		cache := testutil.NewMockStatsCache()
+		query := NewCalculateStatsQuery(statsRepo, cache)
+
+		stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Approximate: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !stats.IsApproximate() {
+			t.Error("Expected approximate stats")
+		}
+		if cache.SetCallCount() != 0 {
+			t.Errorf("Expected estimates not to be cached, got %d cache sets", cache.SetCallCount())
+		}
+	})
+
+	t.Run("cache hit is exact", func(t *testing.T) {
+		_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{request})
+		cached := entity.NewStatsFromRequests([]entity.APIRequest{request}, period)
+		cache := testutil.NewMockStatsCacheWithData(func(entity.Period) *entity.Stats { return &cached })
+		query := NewCalculateStatsQuery(statsRepo, cache)
+
+		stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Approximate: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.IsApproximate() {
+			t.Error("Expected exact cached stats")
+		}
+	})
+
+	t.Run("repository without estimates", func(t *testing.T) {
+		apiRepo, statsRepo, _ := testutil.NewInstrumentedRepositoryPair()
+		apiRepo.SetMockData([]entity.APIRequest{request})
+		query := NewCalculateStatsQuery(statsRepo, testutil.NewMockStatsCache())
+
+		stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Approximate: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.IsApproximate() || stats.TotalRequests() != 1 {
+			t.Errorf("Expected exact stats of 1 request, got approximate=%v requests=%d", stats.IsApproximate(), stats.TotalRequests())
+		}
+	})
+}
+
+// freshStatsRepository counts the stats retrieved bypassing the caches behind it
+type freshStatsRepository struct {
+	*testutil.MockStatsRepository
+	freshCalls int
+}
+
+func (r *freshStatsRepository) GetFreshStatsByPeriod(period entity.Period) (entity.Stats, error) {
+	r.freshCalls++
+	return r.GetStatsByPeriod(period)
+}
+
+func TestCalculateStatsQuery_Execute_Fresh(t *testing.T) {
+	now := time.Now()
+	request := entity.NewAPIRequest("session", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	period := entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour))
+	stale := entity.NewStatsFromRequests(nil, period).WithCachedAt(now.Add(-time.Minute))
+
+	t.Run("skips the cache and replaces the cached stats", func(t *testing.T) {
+		_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{request})
+		cache := testutil.NewMockStatsCacheWithData(func(entity.Period) *entity.Stats { return &stale })
+		query := NewCalculateStatsQuery(statsRepo, cache)
+
+		stats, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Fresh: true})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats.IsCached() || stats.TotalRequests() != 1 {
+			t.Errorf("Expected fresh stats of 1 request, got cached=%v requests=%d", stats.IsCached(), stats.TotalRequests())
+		}
+		if cache.SetCallCount() != 1 {
+			t.Errorf("Expected the fresh stats to be cached, got %d cache sets", cache.SetCallCount())
+		}
+	})
+
+	t.Run("skips the caches behind the repository", func(t *testing.T) {
+		_, statsRepo := testutil.NewMockRepositoryWithData([]entity.APIRequest{request})
+		repo := &freshStatsRepository{MockStatsRepository: statsRepo}
+		query := NewCalculateStatsQuery(repo, testutil.NewMockStatsCache())
+
+		if _, err := query.Execute(context.Background(), CalculateStatsParams{Period: period}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := query.Execute(context.Background(), CalculateStatsParams{Period: period, Fresh: true, Approximate: true}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if repo.freshCalls != 1 {
+			t.Errorf("Expected 1 fresh repository call, got %d", repo.freshCalls)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// QuotaStatus contains the result of a quota check
+type QuotaStatus struct {
+	DailyCost entity.Cost
+	Exceeded  bool
+}
+
+// CheckQuotaQuery handles checking today's cost against the hard daily quota
+type CheckQuotaQuery struct {
+	calculateStatsQuery *CalculateStatsQuery
+	periodFactory       PeriodFactory
+	quota               entity.Quota
+}
+
+// NewCheckQuotaQuery creates a new CheckQuotaQuery with the given dependencies
+func NewCheckQuotaQuery(calculateStatsQuery *CalculateStatsQuery, periodFactory PeriodFactory, quota entity.Quota) *CheckQuotaQuery {
+	return &CheckQuotaQuery{
+		calculateStatsQuery: calculateStatsQuery,
+		periodFactory:       periodFactory,
+		quota:               quota,
+	}
+}
+
+// Execute checks the quota, a disabled quota is never exceeded and skips the stats lookup
+func (q *CheckQuotaQuery) Execute(ctx context.Context) (QuotaStatus, error) {
+	if !q.quota.IsEnabled() {
+		return QuotaStatus{}, nil
+	}
+
+	stats, err := q.calculateStatsQuery.Execute(ctx, CalculateStatsParams{Period: q.periodFactory.CreateDaily()})
+	if err != nil {
+		return QuotaStatus{}, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	return QuotaStatus{
+		DailyCost: stats.TotalCost(),
+		Exceeded:  q.quota.IsExceeded(stats.TotalCost()),
+	}, nil
+}
@@ -3,34 +3,58 @@ package usecase
 import (
 	"context"
 	"time"
+
+	"github.com/elct9620/ccmon/entity"
 )
 
 // CleanupOldRecordsCommand handles the command to cleanup old API request records
 type CleanupOldRecordsCommand struct {
-	repository APIRequestRepository
+	repository     APIRequestRepository
+	starRepository StarRepository
 }
 
 // NewCleanupOldRecordsCommand creates a new CleanupOldRecordsCommand with the given repository
 func NewCleanupOldRecordsCommand(repository APIRequestRepository) *CleanupOldRecordsCommand {
+	return NewCleanupOldRecordsCommandWithStars(repository, nil)
+}
+
+// NewCleanupOldRecordsCommandWithStars creates a new CleanupOldRecordsCommand which never deletes starred records
+func NewCleanupOldRecordsCommandWithStars(repository APIRequestRepository, starRepository StarRepository) *CleanupOldRecordsCommand {
 	return &CleanupOldRecordsCommand{
-		repository: repository,
+		repository:     repository,
+		starRepository: starRepository,
 	}
 }
 
 // CleanupOldRecordsParams contains the parameters for cleaning up old records
 type CleanupOldRecordsParams struct {
 	CutoffTime time.Time
+	DryRun     bool // count the records which would be deleted without deleting them
 }
 
 // CleanupOldRecordsResult contains the result of the cleanup operation
 type CleanupOldRecordsResult struct {
-	DeletedCount int
+	DeletedCount int // records which would be deleted in a dry run
 }
 
 // Execute executes the cleanup old records command
 func (c *CleanupOldRecordsCommand) Execute(ctx context.Context, params CleanupOldRecordsParams) (*CleanupOldRecordsResult, error) {
+	// Starred requests and sessions are kept regardless of their age
+	var keep entity.Stars
+	if c.starRepository != nil {
+		stars, err := c.starRepository.GetStars()
+		if err != nil {
+			return nil, err
+		}
+		keep = stars
+	}
+
+	if params.DryRun {
+		return c.countOlderThan(params.CutoffTime, keep)
+	}
+
 	// Delete records older than cutoff time via repository
-	deletedCount, err := c.repository.DeleteOlderThan(params.CutoffTime)
+	deletedCount, err := c.repository.DeleteOlderThan(params.CutoffTime, keep)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +63,22 @@ func (c *CleanupOldRecordsCommand) Execute(ctx context.Context, params CleanupOl
 		DeletedCount: deletedCount,
 	}, nil
 }
+
+// countOlderThan counts the records DeleteOlderThan would delete
+func (c *CleanupOldRecordsCommand) countOlderThan(cutoffTime time.Time, keep entity.Stars) (*CleanupOldRecordsResult, error) {
+	requests, err := c.repository.FindByPeriodWithLimit(entity.NewAllTimePeriod(cutoffTime), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, req := range requests {
+		if req.Timestamp().Before(cutoffTime) && !keep.IsStarred(req) {
+			count++
+		}
+	}
+
+	return &CleanupOldRecordsResult{
+		DeletedCount: count,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/elct9620/ccmon/entity"
 	"github.com/elct9620/ccmon/testutil"
 )
 
@@ -112,6 +113,102 @@ func TestCleanupOldRecordsCommand_Execute(t *testing.T) {
 	}
 }
 
+func TestCleanupOldRecordsCommand_KeepsStarredRecords(t *testing.T) {
+	t.Parallel()
+
+	cutoffTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newRequest := func(sessionID string, offset time.Duration) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, cutoffTime.Add(offset), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	}
+
+	starredRequest := newRequest("session-1", -3*time.Hour)
+	repo := testutil.NewMockAPIRequestRepository()
+	for _, req := range []entity.APIRequest{
+		starredRequest,
+		newRequest("session-1", -2*time.Hour),
+		newRequest("session-2", -2*time.Hour),
+		newRequest("session-2", -time.Hour),
+		newRequest("session-3", -time.Hour),
+		newRequest("session-3", time.Hour),
+	} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	starRepo := testutil.NewMockStarRepository()
+	if err := starRepo.SetStar(starredRequest.ID(), starredRequest.SessionID(), entity.StarRequest); err != nil {
+		t.Fatalf("Failed to star request: %v", err)
+	}
+	if err := starRepo.SetStar("", "session-2", entity.StarSession); err != nil {
+		t.Fatalf("Failed to star session: %v", err)
+	}
+
+	command := NewCleanupOldRecordsCommandWithStars(repo, starRepo)
+	result, err := command.Execute(context.Background(), CleanupOldRecordsParams{CutoffTime: cutoffTime})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	// Only the unstarred records of session-1 and session-3 before the cutoff are removed
+	if result.DeletedCount != 2 {
+		t.Errorf("Execute() DeletedCount = %d, want 2", result.DeletedCount)
+	}
+
+	remaining, _ := repo.FindAll()
+	if len(remaining) != 4 {
+		t.Errorf("Expected 4 remaining requests, got %d", len(remaining))
+	}
+
+	starRepo.SetError(&testutil.MockError{Message: "stars unavailable"})
+	if _, err := command.Execute(context.Background(), CleanupOldRecordsParams{CutoffTime: cutoffTime}); err == nil {
+		t.Error("Execute() expected error when stars cannot be read, nothing should be deleted")
+	}
+}
+
+func TestCleanupOldRecordsCommand_DryRun(t *testing.T) {
+	t.Parallel()
+
+	cutoffTime := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	newRequest := func(sessionID string, offset time.Duration) entity.APIRequest {
+		return entity.NewAPIRequest(sessionID, cutoffTime.Add(offset), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	}
+
+	repo := testutil.NewMockAPIRequestRepository()
+	for _, req := range []entity.APIRequest{
+		newRequest("session-1", -2*time.Hour),
+		newRequest("session-2", -2*time.Hour),
+		newRequest("session-3", -time.Hour),
+		newRequest("session-3", 0),
+		newRequest("session-3", time.Hour),
+	} {
+		if err := repo.Save(req); err != nil {
+			t.Fatalf("Failed to save request: %v", err)
+		}
+	}
+
+	starRepo := testutil.NewMockStarRepository()
+	if err := starRepo.SetStar("", "session-2", entity.StarSession); err != nil {
+		t.Fatalf("Failed to star session: %v", err)
+	}
+
+	command := NewCleanupOldRecordsCommandWithStars(repo, starRepo)
+	result, err := command.Execute(context.Background(), CleanupOldRecordsParams{CutoffTime: cutoffTime, DryRun: true})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error = %v", err)
+	}
+
+	// The unstarred records strictly before the cutoff would be removed
+	if result.DeletedCount != 2 {
+		t.Errorf("Execute() DeletedCount = %d, want 2", result.DeletedCount)
+	}
+
+	remaining, _ := repo.FindAll()
+	if len(remaining) != 5 {
+		t.Errorf("Expected a dry run to keep all 5 requests, got %d", len(remaining))
+	}
+}
+
 func TestCleanupOldRecordsCommand_ExecuteContextCancellation(t *testing.T) {
 	// Create mock repository that simulates a slow operation
 	mockRepo := testutil.NewMockRepositoryWithDeleteFunc(func(cutoffTime time.Time) (int, error) {
@@ -0,0 +1,17 @@
+package usecase
+
+import "errors"
+
+// ErrDuplicateAPIRequest is returned when the API request was already appended within the dedupe window
+var ErrDuplicateAPIRequest = errors.New("duplicate API request")
+
+// DuplicateFilter defines the interface for remembering recently appended API requests.
+// Exporters send a record again when they retry an export, the filter lets it be stored once.
+type DuplicateFilter interface {
+	// Seen returns true if the key was remembered within the window, otherwise it remembers the key.
+	Seen(key string) bool
+
+	// Forget drops a remembered key.
+	// Called when storing the request failed, so the retried record is stored.
+	Forget(key string)
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// AlertNotifier delivers fired alerts, e.g. to chat webhooks
+type AlertNotifier interface {
+	NotifyAlert(ctx context.Context, alert entity.Alert) error
+}
+
+// EvaluateAlertsCommand checks the alert rules against the usage and notifies each rule once per period
+type EvaluateAlertsCommand struct {
+	calculateStatsQuery *CalculateStatsQuery
+	policy              entity.AlertPolicy
+	notifier            AlertNotifier
+
+	// Rules which fired in their current period, kept until the period ends
+	mutex sync.Mutex
+	fired map[string]time.Time
+}
+
+// NewEvaluateAlertsCommand creates a new EvaluateAlertsCommand
+func NewEvaluateAlertsCommand(calculateStatsQuery *CalculateStatsQuery, policy entity.AlertPolicy, notifier AlertNotifier) *EvaluateAlertsCommand {
+	return &EvaluateAlertsCommand{
+		calculateStatsQuery: calculateStatsQuery,
+		policy:              policy,
+		notifier:            notifier,
+		fired:               make(map[string]time.Time),
+	}
+}
+
+// Execute evaluates every rule which has not fired in its period containing now and notifies the fired alerts
+// A failed delivery is retried on the next evaluation, the delivered alerts are returned with the error
+func (c *EvaluateAlertsCommand) Execute(ctx context.Context, now time.Time) ([]entity.Alert, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Forget the rules of ended periods, they may fire again in the next one
+	for key, endAt := range c.fired {
+		if now.After(endAt) {
+			delete(c.fired, key)
+		}
+	}
+
+	// Rules sharing a period are checked against the same stats
+	statsByPeriod := make(map[entity.Period]entity.Stats)
+	var alerts []entity.Alert
+	var errs []error
+	for _, rule := range c.policy.Rules() {
+		period := c.policy.Period(rule, now)
+		if _, ok := c.fired[entity.AlertKey(rule, period)]; ok {
+			continue
+		}
+
+		stats, ok := statsByPeriod[period]
+		if !ok {
+			var err error
+			stats, err = c.calculateStatsQuery.Execute(ctx, CalculateStatsParams{Period: period.Until(now)})
+			if err != nil {
+				return alerts, fmt.Errorf("failed to calculate stats of %s: %w", rule, err)
+			}
+			statsByPeriod[period] = stats
+		}
+
+		alert, fired := c.policy.Evaluate(rule, stats, now)
+		if !fired {
+			continue
+		}
+		if err := c.notifier.NotifyAlert(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send alert %s: %w", rule, err))
+			continue
+		}
+		c.fired[alert.Key()] = period.EndAt()
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// recordingAlertNotifier records the delivered alerts
+type recordingAlertNotifier struct {
+	alerts []entity.Alert
+	err    error
+}
+
+func (n *recordingAlertNotifier) NotifyAlert(ctx context.Context, alert entity.Alert) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.alerts = append(n.alerts, alert)
+	return nil
+}
+
+func TestEvaluateAlertsCommand_Execute(t *testing.T) {
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session", now.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 1500, 0, 0), entity.NewCost(6), 1000),
+		// Before the block started at 5am, only counted by the daily cost
+		entity.NewAPIRequest("session", now.Add(-3*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 1500, 0, 0), entity.NewCost(6), 1000),
+	}
+
+	dailyCost, _ := entity.NewAlertRule("daily_cost", 10, 0)
+	blockTokens, _ := entity.NewAlertRule("block_tokens", 0, 80)
+	monthlyCost, _ := entity.NewAlertRule("monthly_cost", 100, 0)
+	policy := entity.NewAlertPolicy([]entity.AlertRule{dailyCost, blockTokens, monthlyCost}, 5, time.UTC, 7000)
+
+	_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	notifier := &recordingAlertNotifier{}
+	command := NewEvaluateAlertsCommand(NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), policy, notifier)
+
+	alerts, err := command.Execute(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(alerts) != 2 || len(notifier.alerts) != 2 {
+		t.Fatalf("Expected the daily cost and block tokens alerts, got %d alerts and %d notified", len(alerts), len(notifier.alerts))
+	}
+	if alerts[0].Rule().Metric() != entity.AlertDailyCost || alerts[1].Rule().Metric() != entity.AlertBlockTokens {
+		t.Errorf("Expected daily_cost then block_tokens, got %s and %s", alerts[0].Rule(), alerts[1].Rule())
+	}
+
+	// The same period never alerts twice
+	alerts, err = command.Execute(context.Background(), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(alerts) != 0 || len(notifier.alerts) != 2 {
+		t.Errorf("Expected no alert again in the same period, got %d", len(alerts))
+	}
+
+	// The next block starts over, the day has already alerted
+	_, statsRepo = testutil.NewMockRepositoryWithData(append(requests,
+		entity.NewAPIRequest("session", now.Add(3*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(6000, 0, 0, 0), entity.NewCost(1), 1000),
+	))
+	command.calculateStatsQuery = NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{})
+	alerts, err = command.Execute(context.Background(), now.Add(3*time.Hour+time.Minute))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Rule().Metric() != entity.AlertBlockTokens {
+		t.Errorf("Expected only the block tokens alert of the next block, got %d alerts", len(alerts))
+	}
+}
+
+func TestEvaluateAlertsCommand_RetriesFailedDelivery(t *testing.T) {
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session", now.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 1500, 0, 0), entity.NewCost(12), 1000),
+	}
+	dailyCost, _ := entity.NewAlertRule("daily_cost", 10, 0)
+	policy := entity.NewAlertPolicy([]entity.AlertRule{dailyCost}, 0, time.UTC, 0)
+
+	_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	notifier := &recordingAlertNotifier{err: errors.New("webhook responded with 500 Internal Server Error")}
+	command := NewEvaluateAlertsCommand(NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), policy, notifier)
+
+	if _, err := command.Execute(context.Background(), now); err == nil {
+		t.Fatal("Execute() error = nil, want the delivery error")
+	}
+
+	notifier.err = nil
+	alerts, err := command.Execute(context.Background(), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Errorf("Expected the failed alert to be sent again, got %d alerts", len(alerts))
+	}
+}
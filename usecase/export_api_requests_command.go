@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// exportPageSize is the number of requests read and written at a time, so an export never loads every request at once
+const exportPageSize = 1000
+
+// ExportApiRequestsCommand handles exporting stored API requests, optionally only the ones added since the last export
+type ExportApiRequestsCommand struct {
+	requestsQuery   *GetFilteredApiRequestsQuery
+	stateRepository ExportStateRepository
+}
+
+// NewExportApiRequestsCommand creates a new ExportApiRequestsCommand
+// The state repository may be nil when only full exports are run
+func NewExportApiRequestsCommand(repository APIRequestRepository, stateRepository ExportStateRepository) *ExportApiRequestsCommand {
+	return &ExportApiRequestsCommand{
+		requestsQuery:   NewGetFilteredApiRequestsQuery(repository),
+		stateRepository: stateRepository,
+	}
+}
+
+// ExportApiRequestsParams contains the parameters for exporting API requests
+type ExportApiRequestsParams struct {
+	Since     time.Time // Requests before it are left out, zero exports from the first request, ignored with SinceLast
+	Until     time.Time // Requests after it are left for the next export
+	SinceLast bool      // Only export requests added since the last export and remember how far this one got
+}
+
+// ExportApiRequestsResult contains the outcome of an export
+type ExportApiRequestsResult struct {
+	Exported int
+}
+
+// Execute writes the requests to export page by page, newest first
+// The export state only advances after every page is written, so a failed run is exported again by the next one
+func (c *ExportApiRequestsCommand) Execute(ctx context.Context, params ExportApiRequestsParams, write func([]entity.APIRequest) error) (ExportApiRequestsResult, error) {
+	var cursor entity.ExportCursor
+	if params.SinceLast {
+		if c.stateRepository == nil {
+			return ExportApiRequestsResult{}, fmt.Errorf("no export state configured")
+		}
+
+		var err error
+		cursor, err = c.stateRepository.LoadExportCursor()
+		if err != nil {
+			return ExportApiRequestsResult{}, fmt.Errorf("failed to load export state: %w", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ExportApiRequestsResult{}, err
+	}
+
+	period := cursor.Period(params.Until)
+	if !params.SinceLast && !params.Since.IsZero() {
+		period = entity.NewPeriod(params.Since, params.Until)
+	}
+
+	next := cursor
+	exported := 0
+	pageParams := GetFilteredApiRequestsParams{Filter: entity.NewFilter(period), Limit: exportPageSize}
+	for {
+		requests, pageCursor, err := c.requestsQuery.ExecutePage(ctx, pageParams)
+		if err != nil {
+			return ExportApiRequestsResult{}, fmt.Errorf("failed to get requests: %w", err)
+		}
+
+		// Pages are walked back from the newest request, each page is in chronological order
+		pending := cursor.Pending(requests)
+		slices.Reverse(pending)
+		if len(pending) > 0 {
+			if err := write(pending); err != nil {
+				return ExportApiRequestsResult{}, err
+			}
+		}
+		next = next.Advance(pending)
+		exported += len(pending)
+
+		if pageCursor == "" {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return ExportApiRequestsResult{}, err
+		}
+		pageParams.Cursor = pageCursor
+	}
+
+	if params.SinceLast {
+		if err := c.stateRepository.SaveExportCursor(next); err != nil {
+			return ExportApiRequestsResult{}, fmt.Errorf("failed to save export state: %w", err)
+		}
+	}
+
+	return ExportApiRequestsResult{Exported: exported}, nil
+}
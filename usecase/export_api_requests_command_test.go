@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// memoryExportStateRepository keeps the export cursor in memory
+type memoryExportStateRepository struct {
+	cursor entity.ExportCursor
+	saves  int
+}
+
+func (r *memoryExportStateRepository) LoadExportCursor() (entity.ExportCursor, error) {
+	return r.cursor, nil
+}
+
+func (r *memoryExportStateRepository) SaveExportCursor(cursor entity.ExportCursor) error {
+	r.cursor = cursor
+	r.saves++
+	return nil
+}
+
+func TestExportApiRequestsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", baseTime, "claude-sonnet-4-20250514", 100, 50, 0.01),
+		testutil.CreateTestAPIRequest("session-1", baseTime.Add(time.Minute), "claude-sonnet-4-20250514", 100, 50, 0.01),
+	}
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData(requests)
+	state := &memoryExportStateRepository{}
+	command := NewExportApiRequestsCommand(repo, state)
+
+	var written []entity.APIRequest
+	write := func(requests []entity.APIRequest) error {
+		written = requests
+		return nil
+	}
+
+	// The first differential export includes every record up to until, newest first
+	result, err := command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour), SinceLast: true}, write)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Exported != 2 || len(written) != 2 {
+		t.Fatalf("Execute() exported %d records, want 2", result.Exported)
+	}
+	if !written[0].Timestamp().Equal(baseTime.Add(time.Minute)) {
+		t.Errorf("Execute() wrote %v first, want the newest record", written[0].Timestamp())
+	}
+
+	// Nothing new since the last export
+	result, err = command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour), SinceLast: true}, write)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Exported != 0 {
+		t.Errorf("Execute() exported %d records again, want 0", result.Exported)
+	}
+
+	// Only the new record is exported
+	repo.SetMockData(append(requests, testutil.CreateTestAPIRequest("session-2", baseTime.Add(2*time.Minute), "claude-sonnet-4-20250514", 100, 50, 0.01)))
+	result, err = command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour), SinceLast: true}, write)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Exported != 1 || written[0].SessionID() != "session-2" {
+		t.Errorf("Execute() exported %d records, want only session-2", result.Exported)
+	}
+
+	// Full exports ignore and keep the state
+	saves := state.saves
+	result, err = command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour)}, write)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Exported != 3 {
+		t.Errorf("Execute() exported %d records, want 3", result.Exported)
+	}
+	if state.saves != saves {
+		t.Error("Execute() saved the export state of a full export")
+	}
+
+	// Since leaves out the older requests
+	result, err = command.Execute(context.Background(), ExportApiRequestsParams{Since: baseTime.Add(time.Minute), Until: baseTime.Add(time.Hour)}, write)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Exported != 2 {
+		t.Errorf("Execute() exported %d records since %v, want 2", result.Exported, baseTime.Add(time.Minute))
+	}
+}
+
+func TestExportApiRequestsCommand_ExecutePages(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	requests := make([]entity.APIRequest, 0, 2*exportPageSize+1)
+	for i := 0; i < cap(requests); i++ {
+		requests = append(requests, testutil.CreateTestAPIRequest("session-1", baseTime.Add(time.Duration(i)*time.Second), "claude-sonnet-4-20250514", 100, 50, 0.01))
+	}
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData(requests)
+	state := &memoryExportStateRepository{}
+	command := NewExportApiRequestsCommand(repo, state)
+
+	var pages, written int
+	result, err := command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour), SinceLast: true}, func(requests []entity.APIRequest) error {
+		pages++
+		written += len(requests)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// Each page is written as it is read
+	if pages != 3 || written != len(requests) || result.Exported != len(requests) {
+		t.Errorf("Execute() wrote %d records in %d pages, want %d records in 3 pages", written, pages, len(requests))
+	}
+	if newest := requests[len(requests)-1].Timestamp(); !state.cursor.LastTimestamp().Equal(newest) {
+		t.Errorf("Execute() saved the export state at %v, want %v", state.cursor.LastTimestamp(), newest)
+	}
+}
+
+func TestExportApiRequestsCommand_ExecuteWriteError(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		testutil.CreateTestAPIRequest("session-1", baseTime, "claude-sonnet-4-20250514", 100, 50, 0.01),
+	})
+	state := &memoryExportStateRepository{}
+	command := NewExportApiRequestsCommand(repo, state)
+
+	writeErr := errors.New("broken pipe")
+	_, err := command.Execute(context.Background(), ExportApiRequestsParams{Until: baseTime.Add(time.Hour), SinceLast: true}, func([]entity.APIRequest) error {
+		return writeErr
+	})
+	if !errors.Is(err, writeErr) {
+		t.Fatalf("Execute() error = %v, want %v", err, writeErr)
+	}
+
+	// A failed export is exported again by the next run
+	if !state.cursor.IsZero() {
+		t.Errorf("Execute() advanced the export state to %v after a write error", state.cursor.LastTimestamp())
+	}
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// MockRequestGenerator produces synthetic API requests for developing clients without real telemetry
+type MockRequestGenerator interface {
+	// Generate returns the requests made from start until before end, the same range always returns the same requests
+	Generate(start, end time.Time) []entity.APIRequest
+}
+
+// GenerateMockRequestsCommand stores the synthetic requests made since the previous run
+type GenerateMockRequestsCommand struct {
+	generator          MockRequestGenerator
+	appendBatchCommand *AppendApiRequestBatchCommand
+
+	mutex sync.Mutex
+	since time.Time
+}
+
+// NewGenerateMockRequestsCommand creates a new GenerateMockRequestsCommand, the first run stores the requests made since the given time
+func NewGenerateMockRequestsCommand(generator MockRequestGenerator, appendBatchCommand *AppendApiRequestBatchCommand, since time.Time) *GenerateMockRequestsCommand {
+	return &GenerateMockRequestsCommand{
+		generator:          generator,
+		appendBatchCommand: appendBatchCommand,
+		since:              since,
+	}
+}
+
+// Execute stores the requests made until now, they are published to watchers like received telemetry
+func (c *GenerateMockRequestsCommand) Execute(ctx context.Context, now time.Time) (AppendApiRequestBatchResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !now.After(c.since) {
+		return AppendApiRequestBatchResult{}, nil
+	}
+
+	requests := c.generator.Generate(c.since, now)
+	params := make([]AppendApiRequestParams, 0, len(requests))
+	for _, req := range requests {
+		params = append(params, AppendApiRequestParams{
+			SessionID:  req.SessionID(),
+			Timestamp:  req.Timestamp(),
+			Model:      req.Model().String(),
+			Tokens:     req.Tokens(),
+			Cost:       req.Cost(),
+			DurationMS: req.DurationMS(),
+			Source:     req.Source(),
+			Origin:     req.Origin(),
+			User:       req.User(),
+			Project:    req.Project(),
+		})
+	}
+
+	result, err := c.appendBatchCommand.Execute(ctx, params)
+	if err != nil {
+		return AppendApiRequestBatchResult{}, err
+	}
+	c.since = now
+	return result, nil
+}
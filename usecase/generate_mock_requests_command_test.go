@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// everyMinuteGenerator makes a request at the start of every minute
+type everyMinuteGenerator struct{}
+
+func (everyMinuteGenerator) Generate(start, end time.Time) []entity.APIRequest {
+	var requests []entity.APIRequest
+	for at := start.Truncate(time.Minute); at.Before(end); at = at.Add(time.Minute) {
+		if at.Before(start) {
+			continue
+		}
+		requests = append(requests, entity.NewAPIRequest("mock", at, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000).WithProject("demo"))
+	}
+	return requests
+}
+
+func TestGenerateMockRequestsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	command := NewGenerateMockRequestsCommand(everyMinuteGenerator{}, NewAppendApiRequestBatchCommand(repo), since)
+
+	steps := []struct {
+		now      time.Time
+		expected int
+	}{
+		{now: since.Add(10 * time.Minute), expected: 10}, // the history since the given time
+		{now: since.Add(10*time.Minute + 30*time.Second), expected: 1},
+		{now: since.Add(12 * time.Minute), expected: 1}, // only the requests made since the previous run
+		{now: since.Add(11 * time.Minute), expected: 0}, // a clock going back generates nothing
+	}
+
+	for _, step := range steps {
+		result, err := command.Execute(context.Background(), step.now)
+		if err != nil {
+			t.Fatalf("Execute(%v) returned error: %v", step.now, err)
+		}
+		if result.Saved != step.expected {
+			t.Errorf("Execute(%v) saved %d requests, expected %d", step.now, result.Saved, step.expected)
+		}
+	}
+
+	stored, _ := repo.FindAll()
+	if len(stored) != 12 {
+		t.Fatalf("Expected 12 stored requests, got %d", len(stored))
+	}
+	if stored[0].Project() != "demo" {
+		t.Errorf("Expected the generated project to be kept, got %q", stored[0].Project())
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DefaultBlockHistoryBlocks is the number of blocks listed by default, the current block and the five days before it
+const DefaultBlockHistoryBlocks = 25
+
+// GetBlockHistoryQuery handles listing the usage of past blocks against their token limit
+type GetBlockHistoryQuery struct {
+	usageQuery *GetUsageQuery
+	block      entity.Block
+}
+
+// NewGetBlockHistoryQuery creates a new GetBlockHistoryQuery, the block carries the configured start and token limit
+func NewGetBlockHistoryQuery(usageQuery *GetUsageQuery, block entity.Block) *GetBlockHistoryQuery {
+	return &GetBlockHistoryQuery{
+		usageQuery: usageQuery,
+		block:      block,
+	}
+}
+
+// Execute lists the usage of the block containing now and the blocks before it, newest first
+// Blocks follow each other from the configured block and keep its length, like the current block of the monitor
+func (q *GetBlockHistoryQuery) Execute(ctx context.Context, now time.Time, blocks int) ([]BlockUsage, error) {
+	if blocks <= 0 {
+		return nil, nil
+	}
+
+	history := make([]BlockUsage, blocks)
+	periods := make([]entity.Period, blocks)
+	block := q.block.NextBlock(now)
+	for i := range history {
+		history[i].Block = block
+		periods[i] = block.Period()
+		block = block.PreviousBlock()
+	}
+
+	usage, err := q.usageQuery.ListByPeriods(ctx, periods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate block stats: %w", err)
+	}
+
+	// Only rate-limited tokens count toward block limits
+	for i, stats := range usage.GetStats() {
+		history[i].Tokens = stats.RateLimitedTokens()
+		history[i].Cost = stats.TotalCost()
+	}
+	return history, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetBlockHistoryQuery_Execute(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	newRequest := func(at time.Duration, model string, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session", day.Add(at), model, entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		newRequest(15*time.Hour+30*time.Minute, "claude-sonnet-4-20250514", 1),
+		newRequest(11*time.Hour, "claude-sonnet-4-20250514", 2),
+		newRequest(11*time.Hour+30*time.Minute, "claude-3-5-haiku-20241022", 0.5),
+		newRequest(4*time.Hour, "claude-opus-4-20250514", 4),
+	})
+	block := entity.NewBlockWithLimit(day.Add(5*time.Hour), 1000)
+	query := NewGetBlockHistoryQuery(NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC)), block)
+
+	history, err := query.Execute(context.Background(), day.Add(16*time.Hour), 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		startAt time.Duration
+		tokens  int64
+		cost    float64
+	}{
+		{startAt: 15 * time.Hour, tokens: 150, cost: 1},
+		{startAt: 10 * time.Hour, tokens: 150, cost: 2.5}, // base model tokens are not limited
+		{startAt: 5 * time.Hour, tokens: 0, cost: 0},
+		{startAt: 0, tokens: 150, cost: 4},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d blocks, got %d", len(expected), len(history))
+	}
+	for i, want := range expected {
+		got := history[i]
+		if !got.Block.StartAt().Equal(day.Add(want.startAt)) {
+			t.Errorf("Block %d: expected start %v, got %v", i, day.Add(want.startAt), got.Block.StartAt())
+		}
+		if got.Block.TokenLimit() != 1000 {
+			t.Errorf("Block %d: expected the token limit to be kept, got %d", i, got.Block.TokenLimit())
+		}
+		if got.Tokens.Limited() != want.tokens {
+			t.Errorf("Block %d: expected %d limited tokens, got %d", i, want.tokens, got.Tokens.Limited())
+		}
+		if got.Cost.Amount() != want.cost {
+			t.Errorf("Block %d: expected cost %v, got %v", i, want.cost, got.Cost.Amount())
+		}
+	}
+}
+
+func TestGetBlockHistoryQuery_Execute_Error(t *testing.T) {
+	repo := testutil.NewMockAPIRequestRepositoryWithError(errors.New("database error"))
+	block := entity.NewBlockWithLimit(time.Date(2025, 6, 1, 5, 0, 0, 0, time.UTC), 1000)
+	query := NewGetBlockHistoryQuery(NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC)), block)
+
+	if _, err := query.Execute(context.Background(), block.StartAt(), DefaultBlockHistoryBlocks); err == nil {
+		t.Error("Expected an error when the usage lookup fails")
+	}
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// BlockUsage contains the usage of the block containing the time it was queried at
+type BlockUsage struct {
+	Block  entity.Block
+	Tokens entity.Token // rate-limited tokens used since the block started
+	Cost   entity.Cost  // cost of every request in the block, base models included
+}
+
+// GetBlockUsageQuery handles calculating the usage of the current block against its token limit
+type GetBlockUsageQuery struct {
+	calculateStatsQuery *CalculateStatsQuery
+	block               entity.Block
+	user                string
+}
+
+// NewGetBlockUsageQuery creates a new GetBlockUsageQuery, the block carries the configured token limit
+// An empty user counts every request
+func NewGetBlockUsageQuery(calculateStatsQuery *CalculateStatsQuery, block entity.Block, user string) *GetBlockUsageQuery {
+	return &GetBlockUsageQuery{
+		calculateStatsQuery: calculateStatsQuery,
+		block:               block,
+		user:                user,
+	}
+}
+
+// Execute calculates the usage of the block containing now, the block advances once the configured one ends
+func (q *GetBlockUsageQuery) Execute(ctx context.Context, now time.Time) (BlockUsage, error) {
+	block := q.block.NextBlock(now)
+
+	stats, err := q.calculateStatsQuery.Execute(ctx, CalculateStatsParams{
+		Period: block.Period(),
+		User:   q.user,
+	})
+	if err != nil {
+		return BlockUsage{}, fmt.Errorf("failed to calculate block stats: %w", err)
+	}
+
+	// Only rate-limited tokens count toward block limits
+	return BlockUsage{
+		Block:  block,
+		Tokens: stats.RateLimitedTokens(),
+		Cost:   stats.TotalCost(),
+	}, nil
+}
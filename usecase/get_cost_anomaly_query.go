@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetCostAnomalyQuery handles comparing today's premium and 1M context cost with the baseline of the 7 days before
+type GetCostAnomalyQuery struct {
+	usageQuery *GetUsageQuery
+	policy     entity.CostAnomalyPolicy
+	timezone   *time.Location
+}
+
+// NewGetCostAnomalyQuery creates a new GetCostAnomalyQuery, days follow the timezone
+func NewGetCostAnomalyQuery(usageQuery *GetUsageQuery, policy entity.CostAnomalyPolicy, timezone *time.Location) *GetCostAnomalyQuery {
+	return &GetCostAnomalyQuery{
+		usageQuery: usageQuery,
+		policy:     policy,
+		timezone:   timezone,
+	}
+}
+
+// Policy returns the multiple of the baseline days are flagged above
+func (q *GetCostAnomalyQuery) Policy() entity.CostAnomalyPolicy {
+	return q.policy
+}
+
+// Execute compares today with its baseline, a disabled policy skips the usage lookup
+func (q *GetCostAnomalyQuery) Execute(ctx context.Context) (entity.CostAnomaly, error) {
+	if !q.policy.IsEnabled() {
+		return entity.CostAnomaly{}, nil
+	}
+
+	usage, err := q.usageQuery.ListByDay(ctx, 1, q.timezone)
+	if err != nil {
+		return entity.CostAnomaly{}, fmt.Errorf("failed to list daily usage: %w", err)
+	}
+
+	today := usage.GetStats()[0]
+	average, _ := usage.MovingAverageAt(0)
+	return q.policy.Detect(today.RateLimitedCost(), average.Baseline()), nil
+}
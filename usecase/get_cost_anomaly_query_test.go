@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetCostAnomalyQuery_Execute(t *testing.T) {
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	todayStart := periodFactory.CreateDaily().StartAt()
+	newRequest := func(daysAgo int, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session", todayStart.AddDate(0, 0, -daysAgo), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+	// A week at $1 a day
+	week := func(today float64) []entity.APIRequest {
+		requests := []entity.APIRequest{newRequest(0, today)}
+		for daysAgo := 1; daysAgo <= 7; daysAgo++ {
+			requests = append(requests, newRequest(daysAgo, 1))
+		}
+		return requests
+	}
+
+	tests := []struct {
+		name             string
+		multiple         float64
+		requests         []entity.APIRequest
+		expectedAnomaly  bool
+		expectedBaseline float64
+	}{
+		{name: "disabled policy", multiple: 0, requests: week(5)},
+		{name: "today above the multiple", multiple: 2, requests: week(2.5), expectedAnomaly: true, expectedBaseline: 1},
+		{name: "today within the multiple", multiple: 2, requests: week(1.5), expectedBaseline: 1},
+		{name: "no usage before today", multiple: 2, requests: []entity.APIRequest{newRequest(0, 5)}},
+		{
+			name:     "1M context usage today",
+			multiple: 2,
+			requests: append(week(0.5), entity.NewAPIRequest("session", todayStart, "claude-sonnet-4-20250514[1m]", entity.NewToken(100_000, 5_000, 0, 0), entity.NewCost(4), 1000)),
+			// $0.50 of premium and $4 of 1M context usage is $4.50 against the $1 baseline
+			expectedAnomaly:  true,
+			expectedBaseline: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(tt.requests)
+			query := NewGetCostAnomalyQuery(NewGetUsageQuery(repo, periodFactory), entity.NewCostAnomalyPolicy(tt.multiple), time.UTC)
+
+			anomaly, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if anomaly.IsAnomaly() != tt.expectedAnomaly {
+				t.Errorf("Expected IsAnomaly() = %v, got %v", tt.expectedAnomaly, anomaly.IsAnomaly())
+			}
+			if anomaly.Baseline().Amount() != tt.expectedBaseline {
+				t.Errorf("Expected a baseline of %.2f, got %.2f", tt.expectedBaseline, anomaly.Baseline().Amount())
+			}
+		})
+	}
+}
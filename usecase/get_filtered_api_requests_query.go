@@ -2,30 +2,156 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 
 	"github.com/elct9620/ccmon/entity"
 )
 
+// ErrInvalidCursor is returned when the cursor of a page was not returned by ExecutePage
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
 // GetFilteredApiRequestsQuery handles the query to get filtered API requests
 type GetFilteredApiRequestsQuery struct {
-	repository APIRequestRepository
+	repository     APIRequestRepository
+	starRepository StarRepository
 }
 
 // NewGetFilteredApiRequestsQuery creates a new GetFilteredApiRequestsQuery with the given repository
 func NewGetFilteredApiRequestsQuery(repository APIRequestRepository) *GetFilteredApiRequestsQuery {
+	return NewGetFilteredApiRequestsQueryWithStars(repository, nil)
+}
+
+// NewGetFilteredApiRequestsQueryWithStars creates a new GetFilteredApiRequestsQuery which marks the starred requests
+func NewGetFilteredApiRequestsQueryWithStars(repository APIRequestRepository, starRepository StarRepository) *GetFilteredApiRequestsQuery {
 	return &GetFilteredApiRequestsQuery{
-		repository: repository,
+		repository:     repository,
+		starRepository: starRepository,
 	}
 }
 
 // GetFilteredApiRequestsParams contains the parameters for getting filtered API requests
 type GetFilteredApiRequestsParams struct {
-	Period entity.Period
-	Limit  int // Use 0 for no limit
-	Offset int // Use 0 for no offset
+	Filter entity.Filter
+	Limit  int    // Use 0 for no limit
+	Offset int    // Use 0 for no offset
+	Cursor string // Cursor returned by ExecutePage for the previous page, empty for the newest page
 }
 
 // Execute executes the get filtered API requests query
 func (q *GetFilteredApiRequestsQuery) Execute(ctx context.Context, params GetFilteredApiRequestsParams) ([]entity.APIRequest, error) {
-	return q.repository.FindByPeriodWithLimit(params.Period, params.Limit, params.Offset)
+	requests, err := q.findByFilter(params.Filter, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+	return q.markStars(requests)
+}
+
+// ExecutePage executes the query for the page before the cursor, the offset is not used
+// The returned cursor continues with the older requests, empty when none remain
+func (q *GetFilteredApiRequestsQuery) ExecutePage(ctx context.Context, params GetFilteredApiRequestsParams) ([]entity.APIRequest, string, error) {
+	requests, cursor, err := q.findPageByFilter(params.Filter, params.Limit, params.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	requests, err = q.markStars(requests)
+	if err != nil {
+		return nil, "", err
+	}
+	return requests, cursor, nil
+}
+
+// markStars marks the starred requests, the requests are returned as is without a star repository
+func (q *GetFilteredApiRequestsQuery) markStars(requests []entity.APIRequest) ([]entity.APIRequest, error) {
+	if q.starRepository == nil {
+		return requests, nil
+	}
+
+	stars, err := q.starRepository.GetStars()
+	if err != nil {
+		return nil, err
+	}
+	if stars.Len() == 0 {
+		return requests, nil
+	}
+
+	starred := make([]entity.APIRequest, len(requests))
+	for i, req := range requests {
+		starred[i] = req.WithStar(stars.ScopeOf(req))
+	}
+	return starred, nil
+}
+
+// Count returns the number of requests matching the filter, regardless of any limit
+// Repositories without count support read the period and count the matching requests
+func (q *GetFilteredApiRequestsQuery) Count(ctx context.Context, filter entity.Filter) (int, error) {
+	if repository, ok := q.repository.(APIRequestCountRepository); ok {
+		return repository.CountByFilter(filter)
+	}
+
+	requests, err := q.repository.FindByPeriodWithLimit(filter.Period(), 0, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(filter.Apply(requests)), nil
+}
+
+// findByFilter retrieves the requests matching the filter
+// Repositories without filter support are read by period, the dimensions and pagination are applied afterwards
+func (q *GetFilteredApiRequestsQuery) findByFilter(filter entity.Filter, limit int, offset int) ([]entity.APIRequest, error) {
+	if !filter.HasDimensions() {
+		return q.repository.FindByPeriodWithLimit(filter.Period(), limit, offset)
+	}
+	if repository, ok := q.repository.(APIRequestFilterRepository); ok {
+		return repository.FindByFilter(filter, limit, offset)
+	}
+
+	requests, err := q.repository.FindByPeriodWithLimit(filter.Period(), 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return paginate(filter.Apply(requests), limit, offset), nil
+}
+
+// findPageByFilter retrieves the requests matching the filter before the cursor
+// Repositories without cursor support are read by offset, the cursor is the number of requests already returned
+// A full page is assumed to be followed by another one, so the last page may be empty
+func (q *GetFilteredApiRequestsQuery) findPageByFilter(filter entity.Filter, limit int, cursor string) ([]entity.APIRequest, string, error) {
+	if repository, ok := q.repository.(APIRequestPageRepository); ok {
+		return repository.FindPageByFilter(filter, limit, cursor)
+	}
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+		}
+	}
+
+	requests, err := q.findByFilter(filter, limit, offset)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit == 0 || len(requests) < limit {
+		return requests, "", nil
+	}
+	return requests, strconv.Itoa(offset + len(requests)), nil
+}
+
+// paginate returns the latest limit requests before the newest offset requests, pages count back from the newest request
+// Requests are in chronological order, 0 means no limit or offset
+func paginate(requests []entity.APIRequest, limit int, offset int) []entity.APIRequest {
+	end := len(requests) - offset
+	if end <= 0 {
+		return []entity.APIRequest{}
+	}
+
+	start := 0
+	if limit > 0 && end > limit {
+		start = end - limit
+	}
+	return requests[start:end]
 }
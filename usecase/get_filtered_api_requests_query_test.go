@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetFilteredApiRequestsQuery_MarksStarredRequests(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	starred := entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	plain := entity.NewAPIRequest("session-2", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	_ = repo.Save(starred)
+	_ = repo.Save(plain)
+
+	starRepo := testutil.NewMockStarRepository()
+	_ = starRepo.SetStar(starred.ID(), starred.SessionID(), entity.StarRequest)
+
+	query := NewGetFilteredApiRequestsQueryWithStars(repo, starRepo)
+	requests, err := query.Execute(context.Background(), GetFilteredApiRequestsParams{Filter: entity.NewFilter(entity.NewAllTimePeriod(timestamp.Add(time.Hour)))})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	scopes := make(map[string]entity.StarScope, len(requests))
+	for _, req := range requests {
+		scopes[req.ID()] = req.Star()
+	}
+	if scopes[starred.ID()] != entity.StarRequest {
+		t.Errorf("Expected starred request to be marked, got %v", scopes[starred.ID()])
+	}
+	if scopes[plain.ID()] != entity.StarNone {
+		t.Errorf("Expected plain request to be unmarked, got %v", scopes[plain.ID()])
+	}
+
+	starRepo.SetError(&testutil.MockError{Message: "stars unavailable"})
+	if _, err := query.Execute(context.Background(), GetFilteredApiRequestsParams{Filter: entity.NewFilter(entity.NewAllTimePeriod(timestamp.Add(time.Hour)))}); err == nil {
+		t.Error("Expected error when stars cannot be read")
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_FiltersByDimensions(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	newRequest := func(session string, minutes int, model string) entity.APIRequest {
+		return entity.NewAPIRequest(session, timestamp.Add(time.Duration(minutes)*time.Minute), model, entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	}
+	requests := []entity.APIRequest{
+		newRequest("session-1", 0, "claude-sonnet-4-20250514"),
+		newRequest("session-1", 1, "claude-3-5-haiku-20241022"),
+		newRequest("session-2", 2, "claude-sonnet-4-20250514"),
+		newRequest("session-1", 3, "claude-sonnet-4-20250514"),
+		newRequest("session-1", 4, "claude-sonnet-4-20250514"),
+	}
+	period := entity.NewAllTimePeriod(timestamp.Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		filter      entity.Filter
+		limit       int
+		offset      int
+		expectedIDs []string
+	}{
+		{
+			name:        "period only",
+			filter:      entity.NewFilter(period),
+			expectedIDs: []string{requests[0].ID(), requests[1].ID(), requests[2].ID(), requests[3].ID(), requests[4].ID()},
+		},
+		{
+			name:        "model and session",
+			filter:      entity.NewFilter(period).WithModel("claude-sonnet-4-20250514").WithSessionID("session-1"),
+			expectedIDs: []string{requests[0].ID(), requests[3].ID(), requests[4].ID()},
+		},
+		{
+			name:        "limit keeps the latest matches",
+			filter:      entity.NewFilter(period).WithSessionID("session-1"),
+			limit:       2,
+			expectedIDs: []string{requests[3].ID(), requests[4].ID()},
+		},
+		{
+			name:        "offset skips the newest matches",
+			filter:      entity.NewFilter(period).WithSessionID("session-1"),
+			limit:       2,
+			offset:      2,
+			expectedIDs: []string{requests[0].ID(), requests[1].ID()},
+		},
+		{
+			name:        "offset past the matches",
+			filter:      entity.NewFilter(period).WithSessionID("session-2"),
+			offset:      1,
+			expectedIDs: []string{},
+		},
+		{
+			name:        "no match",
+			filter:      entity.NewFilter(period).WithModel("claude-opus-4-20250514"),
+			expectedIDs: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(requests)
+			query := NewGetFilteredApiRequestsQuery(repo)
+
+			result, err := query.Execute(context.Background(), GetFilteredApiRequestsParams{Filter: tt.filter, Limit: tt.limit, Offset: tt.offset})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %d requests, got %d", len(tt.expectedIDs), len(result))
+			}
+			for i, req := range result {
+				if req.ID() != tt.expectedIDs[i] {
+					t.Errorf("Request %d: expected ID %s, got %s", i, tt.expectedIDs[i], req.ID())
+				}
+			}
+		})
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_Count(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	for i, session := range []string{"session-1", "session-2", "session-1", "session-1"} {
+		_ = repo.Save(entity.NewAPIRequest(session, timestamp.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000))
+	}
+	query := NewGetFilteredApiRequestsQuery(repo)
+	period := entity.NewAllTimePeriod(timestamp.Add(time.Hour))
+
+	tests := []struct {
+		name     string
+		filter   entity.Filter
+		expected int
+	}{
+		{name: "period only", filter: entity.NewFilter(period), expected: 4},
+		{name: "dimensions", filter: entity.NewFilter(period).WithSessionID("session-1"), expected: 3},
+		{name: "no match", filter: entity.NewFilter(period).WithSessionID("session-3"), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := query.Count(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if count != tt.expected {
+				t.Errorf("Expected count %d, got %d", tt.expected, count)
+			}
+		})
+	}
+
+	repo.SetError(&testutil.MockError{Message: "database unavailable"})
+	if _, err := query.Count(context.Background(), entity.NewFilter(period)); err == nil {
+		t.Error("Expected error when the requests cannot be read")
+	}
+}
+
+func TestGetFilteredApiRequestsQuery_ExecutePage(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	for i := 0; i < 5; i++ {
+		_ = repo.Save(entity.NewAPIRequest(fmt.Sprintf("session-%d", i), timestamp.Add(time.Duration(i)*time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000))
+	}
+
+	// The mock repository has no cursor support, pages continue by offset
+	query := NewGetFilteredApiRequestsQuery(repo)
+	params := GetFilteredApiRequestsParams{Filter: entity.NewFilter(entity.NewAllTimePeriod(timestamp.Add(time.Hour))), Limit: 2}
+
+	var sessions []string
+	for page := 0; page < 3; page++ {
+		requests, cursor, err := query.ExecutePage(context.Background(), params)
+		if err != nil {
+			t.Fatalf("Page %d: unexpected error: %v", page, err)
+		}
+		for _, req := range requests {
+			sessions = append(sessions, req.SessionID())
+		}
+		if (cursor == "") != (page == 2) {
+			t.Fatalf("Page %d: unexpected cursor %q", page, cursor)
+		}
+		params.Cursor = cursor
+	}
+
+	expected := []string{"session-0", "session-1", "session-2", "session-3", "session-4"}
+	if !slices.Equal(sessions, expected) {
+		t.Errorf("Expected every request once, got %v", sessions)
+	}
+
+	params.Cursor = "not-a-cursor"
+	if _, _, err := query.ExecutePage(context.Background(), params); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetIngestStatsQuery handles the retrieval of the outcomes of received API request events
+type GetIngestStatsQuery struct {
+	ingestStatsRepository IngestStatsRepository
+}
+
+// NewGetIngestStatsQuery creates a new GetIngestStatsQuery with the given repository
+func NewGetIngestStatsQuery(ingestStatsRepository IngestStatsRepository) *GetIngestStatsQuery {
+	return &GetIngestStatsQuery{
+		ingestStatsRepository: ingestStatsRepository,
+	}
+}
+
+// Execute executes the get ingest stats query
+func (q *GetIngestStatsQuery) Execute(ctx context.Context) (entity.IngestStats, error) {
+	return q.ingestStatsRepository.GetIngestStats()
+}
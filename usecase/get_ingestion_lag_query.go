@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetIngestionLagQuery handles the retrieval of ingestion lag metrics
+type GetIngestionLagQuery struct {
+	ingestionLagRepository IngestionLagRepository
+}
+
+// NewGetIngestionLagQuery creates a new GetIngestionLagQuery with the given repository
+func NewGetIngestionLagQuery(ingestionLagRepository IngestionLagRepository) *GetIngestionLagQuery {
+	return &GetIngestionLagQuery{
+		ingestionLagRepository: ingestionLagRepository,
+	}
+}
+
+// Execute executes the get ingestion lag query
+func (q *GetIngestionLagQuery) Execute(ctx context.Context) (entity.IngestionLag, error) {
+	return q.ingestionLagRepository.GetIngestionLag()
+}
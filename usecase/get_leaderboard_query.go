@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetLeaderboardQuery handles ranking the users by their usage in a period
+type GetLeaderboardQuery struct {
+	repository APIRequestRepository
+}
+
+// NewGetLeaderboardQuery creates a new GetLeaderboardQuery with the given repository
+func NewGetLeaderboardQuery(repository APIRequestRepository) *GetLeaderboardQuery {
+	return &GetLeaderboardQuery{
+		repository: repository,
+	}
+}
+
+// GetLeaderboardParams contains the parameters for ranking the users
+type GetLeaderboardParams struct {
+	Period entity.Period // e.g. the last 7 days
+}
+
+// Execute executes the get leaderboard query
+func (q *GetLeaderboardQuery) Execute(ctx context.Context, params GetLeaderboardParams) (entity.Leaderboard, error) {
+	requests, err := q.repository.FindByPeriodWithLimit(params.Period, 0, 0) // No limit for stats calculation
+	if err != nil {
+		return entity.Leaderboard{}, fmt.Errorf("failed to find requests: %w", err)
+	}
+
+	return entity.NewLeaderboard(requests, params.Period), nil
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetLeaderboardQuery_Execute(t *testing.T) {
+	now := time.Date(2025, 8, 8, 12, 0, 0, 0, time.UTC)
+	week := entity.NewPeriodFromDuration(now, 7*24*time.Hour)
+
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(4), 1000).WithUser("alice@example.com"),
+		entity.NewAPIRequest("session-2", now.Add(-3*24*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(10, 5, 0, 0), entity.NewCost(6), 1000).WithUser("bob@example.com"),
+		entity.NewAPIRequest("session-3", now.Add(-8*24*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(10, 5, 0, 0), entity.NewCost(100), 1000).WithUser("alice@example.com"), // previous week
+	}
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData(requests)
+	query := NewGetLeaderboardQuery(repo)
+
+	leaderboard, err := query.Execute(context.Background(), GetLeaderboardParams{Period: week})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries := leaderboard.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(entries))
+	}
+	if entries[0].User() != "bob@example.com" || entries[1].User() != "alice@example.com" {
+		t.Errorf("Expected bob ahead of alice, got %s and %s", entries[0].User(), entries[1].User())
+	}
+	if cost := entries[1].Stats().TotalCost().Amount(); cost != 4 {
+		t.Errorf("Expected alice weekly cost 4, got %v", cost)
+	}
+}
+
+func TestGetLeaderboardQuery_Execute_Error(t *testing.T) {
+	repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database error"})
+	query := NewGetLeaderboardQuery(repo)
+
+	if _, err := query.Execute(context.Background(), GetLeaderboardParams{Period: entity.NewAllTimePeriod(time.Now())}); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
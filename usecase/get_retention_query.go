@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetRetentionQuery handles the retrieval of the server retention policy
+type GetRetentionQuery struct {
+	retentionRepository RetentionRepository
+}
+
+// NewGetRetentionQuery creates a new GetRetentionQuery with the given repository
+func NewGetRetentionQuery(retentionRepository RetentionRepository) *GetRetentionQuery {
+	return &GetRetentionQuery{
+		retentionRepository: retentionRepository,
+	}
+}
+
+// Execute executes the get retention query
+func (q *GetRetentionQuery) Execute(ctx context.Context) (entity.Retention, error) {
+	return q.retentionRepository.GetRetention()
+}
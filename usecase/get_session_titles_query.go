@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetSessionTitlesQuery handles the retrieval of human-readable session titles
+type GetSessionTitlesQuery struct {
+	sessionTitleRepository SessionTitleRepository
+}
+
+// NewGetSessionTitlesQuery creates a new GetSessionTitlesQuery with the given repository
+func NewGetSessionTitlesQuery(sessionTitleRepository SessionTitleRepository) *GetSessionTitlesQuery {
+	return &GetSessionTitlesQuery{
+		sessionTitleRepository: sessionTitleRepository,
+	}
+}
+
+// Execute executes the get session titles query
+func (q *GetSessionTitlesQuery) Execute(ctx context.Context, sessionIDs []string) (entity.SessionTitles, error) {
+	if len(sessionIDs) == 0 {
+		return entity.SessionTitles{}, nil
+	}
+
+	return q.sessionTitleRepository.FindSessionTitles(sessionIDs)
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"io"
+)
+
+// GetSnapshotQuery handles streaming a consistent database snapshot to replicas
+type GetSnapshotQuery struct {
+	snapshotRepository SnapshotRepository
+}
+
+// NewGetSnapshotQuery creates a new GetSnapshotQuery with the given repository
+func NewGetSnapshotQuery(snapshotRepository SnapshotRepository) *GetSnapshotQuery {
+	return &GetSnapshotQuery{
+		snapshotRepository: snapshotRepository,
+	}
+}
+
+// Execute writes the snapshot to w and returns its size in bytes
+func (q *GetSnapshotQuery) Execute(ctx context.Context, w io.Writer) (int64, error) {
+	return q.snapshotRepository.WriteSnapshot(ctx, w)
+}
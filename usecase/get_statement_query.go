@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// defaultStatementTopSessions is the number of sessions listed when no limit is given
+const defaultStatementTopSessions = 10
+
+// GetStatementQuery handles building a monthly usage statement
+type GetStatementQuery struct {
+	repository             APIRequestRepository
+	planRepository         PlanRepository
+	sessionTitleRepository SessionTitleRepository
+}
+
+// NewGetStatementQuery creates a new GetStatementQuery with the given repositories
+// sessionTitleRepository is optional, top sessions are listed by ID when nil
+func NewGetStatementQuery(repository APIRequestRepository, planRepository PlanRepository, sessionTitleRepository SessionTitleRepository) *GetStatementQuery {
+	return &GetStatementQuery{
+		repository:             repository,
+		planRepository:         planRepository,
+		sessionTitleRepository: sessionTitleRepository,
+	}
+}
+
+// GetStatementParams contains parameters for building a statement
+type GetStatementParams struct {
+	MonthStart  time.Time // first day of the month, its timezone sets the day boundaries
+	TopSessions int       // number of sessions to list, 0 uses the default
+}
+
+// Execute builds the statement for the requested month
+func (q *GetStatementQuery) Execute(ctx context.Context, params GetStatementParams) (entity.Statement, error) {
+	topSessions := params.TopSessions
+	if topSessions <= 0 {
+		topSessions = defaultStatementTopSessions
+	}
+
+	// The days before the month fill the moving averages of the first days
+	period := entity.NewStatementPeriod(params.MonthStart)
+	historyStart := params.MonthStart.AddDate(0, 0, -(entity.LongMovingAverageDays - 1))
+	requests, err := q.repository.FindByPeriodWithLimit(entity.NewPeriod(historyStart.UTC(), period.EndAt()), 0, 0) // No limit, every request is a line item input
+	if err != nil {
+		return entity.Statement{}, fmt.Errorf("failed to get requests: %w", err)
+	}
+
+	plan, err := q.planRepository.GetConfiguredPlan()
+	if err != nil {
+		return entity.Statement{}, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	statement := entity.NewStatement(params.MonthStart, requests, plan, topSessions)
+	statement = statement.WithSessionTitles(q.findSessionTitles(statement.TopSessions()))
+	return statement.WithMovingAverages(q.calculateMovingAverages(statement, historyStart, requests)), nil
+}
+
+// findSessionTitles returns the titles of the sessions, titles are cosmetic so a failed lookup lists the sessions by ID
+func (q *GetStatementQuery) findSessionTitles(sessions []entity.Session) entity.SessionTitles {
+	if q.sessionTitleRepository == nil || len(sessions) == 0 {
+		return nil
+	}
+
+	sessionIDs := make([]string, len(sessions))
+	for i, session := range sessions {
+		sessionIDs[i] = session.ID()
+	}
+
+	titles, err := q.sessionTitleRepository.FindSessionTitles(sessionIDs)
+	if err != nil {
+		return nil
+	}
+	return titles
+}
+
+// calculateMovingAverages returns the total cost moving averages of the statement days, the requests before the month fill the windows
+func (q *GetStatementQuery) calculateMovingAverages(statement entity.Statement, historyStart time.Time, requests []entity.APIRequest) []entity.MovingAverage {
+	monthStart := statement.MonthStart()
+	historyDays := make([]entity.Cost, 0, entity.LongMovingAverageDays-1)
+	for dayStart := historyStart; dayStart.Before(monthStart); dayStart = dayStart.AddDate(0, 0, 1) {
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		cost := entity.NewCost(0)
+		for _, req := range requests {
+			if !req.Timestamp().Before(dayStart) && req.Timestamp().Before(dayEnd) {
+				cost = cost.Add(req.Cost())
+			}
+		}
+		historyDays = append(historyDays, cost)
+	}
+
+	costs := historyDays
+	for _, day := range statement.Days() {
+		costs = append(costs, day.TotalCost())
+	}
+	return entity.CalculateMovingAverages(costs, len(statement.Days()))
+}
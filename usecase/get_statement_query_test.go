@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetStatementQuery_Execute_MovingAverages(t *testing.T) {
+	monthStart := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	newRequest := func(day time.Time, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session", day.Add(10*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+
+	tests := []struct {
+		name          string
+		requests      []entity.APIRequest
+		expectedTotal float64
+		expectedShort map[int]float64 // day index -> 7-day average
+		expectedLong  map[int]float64 // day index -> 30-day average
+	}{
+		{
+			name: "previous month fills the windows",
+			requests: []entity.APIRequest{
+				newRequest(monthStart.AddDate(0, 0, -1), 7),
+				newRequest(monthStart.AddDate(0, 0, -29), 3),
+				newRequest(monthStart.AddDate(0, 0, -30), 100), // outside the 30-day window
+				newRequest(monthStart, 7),
+			},
+			expectedTotal: 7,
+			expectedShort: map[int]float64{0: 2, 5: 2, 6: 1, 7: 0},
+			expectedLong:  map[int]float64{0: 17.0 / 30, 1: 14.0 / 30},
+		},
+		{
+			name:          "no usage",
+			expectedShort: map[int]float64{0: 0, 29: 0},
+			expectedLong:  map[int]float64{0: 0, 29: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(tt.requests)
+			query := NewGetStatementQuery(repo, testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))), nil)
+
+			statement, err := query.Execute(context.Background(), GetStatementParams{MonthStart: monthStart})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if statement.Total().TotalCost().Amount() != tt.expectedTotal {
+				t.Errorf("Expected only the month's cost %.2f in the total, got %.2f", tt.expectedTotal, statement.Total().TotalCost().Amount())
+			}
+
+			for i, expected := range tt.expectedShort {
+				average, ok := statement.MovingAverageAt(i)
+				if !ok {
+					t.Fatalf("Day %d: expected a moving average", i)
+				}
+				if math.Abs(average.Short().Amount()-expected) > 1e-9 {
+					t.Errorf("Day %d: expected 7-day average %.4f, got %.4f", i, expected, average.Short().Amount())
+				}
+			}
+			for i, expected := range tt.expectedLong {
+				average, _ := statement.MovingAverageAt(i)
+				if math.Abs(average.Long().Amount()-expected) > 1e-9 {
+					t.Errorf("Day %d: expected 30-day average %.4f, got %.4f", i, expected, average.Long().Amount())
+				}
+			}
+		})
+	}
+}
+
+func TestGetStatementQuery_Execute_SessionTitles(t *testing.T) {
+	monthStart := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("5f3a1c2e", monthStart.Add(10*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(2), 1000),
+		entity.NewAPIRequest("9b7d4e1f", monthStart.Add(11*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(1), 1000),
+	})
+	planRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20)))
+	titleRepo := testutil.NewMockSessionTitleRepository(entity.SessionTitles{
+		"5f3a1c2e": entity.NewSessionTitle("Refactor billing module", "/home/alice/billing"),
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{name: "titled sessions", expected: []string{"Refactor billing module", "9b7d4e1f"}},
+		{name: "failed lookup lists sessions by ID", err: &testutil.MockError{Message: "permission denied"}, expected: []string{"5f3a1c2e", "9b7d4e1f"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			titleRepo.SetError(tt.err)
+			query := NewGetStatementQuery(repo, planRepo, titleRepo)
+
+			statement, err := query.Execute(context.Background(), GetStatementParams{MonthStart: monthStart})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			sessions := statement.TopSessions()
+			if len(sessions) != len(tt.expected) {
+				t.Fatalf("Expected %d sessions, got %d", len(tt.expected), len(sessions))
+			}
+			for i, expected := range tt.expected {
+				if sessions[i].Name() != expected {
+					t.Errorf("Session %d: expected name %q, got %q", i, expected, sessions[i].Name())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetStreakQuery handles counting the consecutive days meeting the daily goal
+type GetStreakQuery struct {
+	usageQuery *GetUsageQuery
+	goal       entity.Goal
+	timezone   *time.Location
+}
+
+// NewGetStreakQuery creates a new GetStreakQuery, days follow the timezone
+func NewGetStreakQuery(usageQuery *GetUsageQuery, goal entity.Goal, timezone *time.Location) *GetStreakQuery {
+	return &GetStreakQuery{
+		usageQuery: usageQuery,
+		goal:       goal,
+		timezone:   timezone,
+	}
+}
+
+// Goal returns the daily goal the streak is counted against
+func (q *GetStreakQuery) Goal() entity.Goal {
+	return q.goal
+}
+
+// Execute counts the streak over the last entity.StreakDays days, a disabled goal skips the usage lookup
+func (q *GetStreakQuery) Execute(ctx context.Context) (entity.Streak, error) {
+	if !q.goal.IsEnabled() {
+		return entity.Streak{}, nil
+	}
+
+	usage, err := q.usageQuery.ListByDay(ctx, entity.StreakDays, q.timezone)
+	if err != nil {
+		return entity.Streak{}, fmt.Errorf("failed to list daily usage: %w", err)
+	}
+
+	return q.goal.CalculateStreak(usage.GetStats()), nil
+}
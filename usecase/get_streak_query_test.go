@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetStreakQuery_Execute(t *testing.T) {
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	todayStart := periodFactory.CreateDaily().StartAt()
+	newRequest := func(daysAgo int, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session", todayStart.AddDate(0, 0, -daysAgo), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+
+	tests := []struct {
+		name         string
+		goal         entity.Goal
+		requests     []entity.APIRequest
+		expectedDays int
+	}{
+		{
+			name:     "disabled goal",
+			goal:     entity.NewGoal(entity.NewCost(0), 0),
+			requests: []entity.APIRequest{newRequest(0, 1)},
+		},
+		{
+			name:         "days under the goal until an expensive day",
+			goal:         entity.NewGoal(entity.NewCost(5), 0),
+			requests:     []entity.APIRequest{newRequest(0, 1), newRequest(1, 2), newRequest(3, 4), newRequest(4, 9)},
+			expectedDays: 4,
+		},
+		{
+			name:     "today over the goal",
+			goal:     entity.NewGoal(entity.NewCost(5), 0),
+			requests: []entity.APIRequest{newRequest(0, 6), newRequest(1, 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(tt.requests)
+			query := NewGetStreakQuery(NewGetUsageQuery(repo, periodFactory), tt.goal, time.UTC)
+
+			streak, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if streak.Days() != tt.expectedDays {
+				t.Errorf("Expected a %d day streak, got %d", tt.expectedDays, streak.Days())
+			}
+		})
+	}
+}
+
+func TestGetStreakQuery_Execute_Error(t *testing.T) {
+	repo := testutil.NewMockAPIRequestRepositoryWithError(errors.New("database error"))
+	query := NewGetStreakQuery(NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC)), entity.NewGoal(entity.NewCost(5), 0), time.UTC)
+
+	if _, err := query.Execute(context.Background()); err == nil {
+		t.Error("Expected an error when the usage lookup fails")
+	}
+}
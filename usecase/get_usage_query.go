@@ -2,15 +2,21 @@ package usecase
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
+	"golang.org/x/sync/errgroup"
 )
 
+// usageQueryWorkers bounds the concurrent period queries against repositories without multi-period scans
+const usageQueryWorkers = 4
+
 // GetUsageQuery handles retrieving usage statistics grouped by periods
 type GetUsageQuery struct {
-	repository    APIRequestRepository
-	periodFactory PeriodFactory
+	repository      APIRequestRepository
+	statsRepository StatsRepository
+	periodFactory   PeriodFactory
 }
 
 // NewGetUsageQuery creates a new GetUsageQuery with the given dependencies
@@ -21,41 +27,271 @@ func NewGetUsageQuery(repository APIRequestRepository, periodFactory PeriodFacto
 	}
 }
 
+// NewGetUsageQueryWithStats creates a new GetUsageQuery calculating the stats of each period with the stats repository
+// Stats repositories reading precomputed aggregates avoid decoding every request of the periods
+func NewGetUsageQueryWithStats(repository APIRequestRepository, statsRepository StatsRepository, periodFactory PeriodFactory) *GetUsageQuery {
+	return &GetUsageQuery{
+		repository:      repository,
+		statsRepository: statsRepository,
+		periodFactory:   periodFactory,
+	}
+}
+
 // ListByDay retrieves usage statistics grouped by daily periods
 func (q *GetUsageQuery) ListByDay(ctx context.Context, days int, timezone *time.Location) (entity.Usage, error) {
-	var dailyStats []entity.Stats
+	return q.ListByDayRange(ctx, 0, days, timezone)
+}
 
-	for i := 0; i < days; i++ {
+// ListByDayRange retrieves usage statistics for a window of daily periods, newest first,
+// starting offsetDays before today (offsetDays = 0 starts from today)
+// Each day carries the 7-day and 30-day moving average of the premium and 1M context cost, the days before the window fill the averages
+func (q *GetUsageQuery) ListByDayRange(ctx context.Context, offsetDays int, days int, timezone *time.Location) (entity.Usage, error) {
+	historyDays := 0
+	if days > 0 {
+		historyDays = entity.LongMovingAverageDays - 1
+	}
+
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	periods := make([]entity.Period, 0, days+historyDays)
+	for i := offsetDays; i < offsetDays+days+historyDays; i++ {
 		// Create historical daily period (today minus i days)
-		period := q.createHistoricalDailyPeriod(i)
+		periods = append(periods, q.createHistoricalDailyPeriod(i, timezone))
+	}
+
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats[:days]).WithMovingAverages(q.calculateMovingAverages(stats, days)), nil
+}
+
+// ListByHour retrieves usage statistics of the last hours on the clock of the timezone, newest first
+// The current hour is included, hours carry no moving averages
+func (q *GetUsageQuery) ListByHour(ctx context.Context, hours int, timezone *time.Location) (entity.Usage, error) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	// Hours are stepped on the absolute time, a daylight saving time change repeats or skips an hour on the clock
+	currentHour := entity.NewHourPeriod(time.Now(), timezone)
+	periods := make([]entity.Period, 0, hours)
+	for i := 0; i < hours; i++ {
+		periods = append(periods, entity.NewHourPeriod(currentHour.StartAt().Add(-time.Duration(i)*time.Hour), timezone))
+	}
+
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats), nil
+}
+
+// ListByWeek retrieves usage statistics of the last calendar weeks starting on Monday, newest first
+// The current week is included, each week carries its change from the week before
+func (q *GetUsageQuery) ListByWeek(ctx context.Context, weeks int, timezone *time.Location) (entity.Usage, error) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	// Noon is never skipped by a clock change, unlike midnight in some timezones
+	today := q.periodFactory.CreateDaily().StartAt().In(timezone)
+	return q.listByCalendar(ctx, weeks, func(i int) entity.Period {
+		return entity.NewWeekPeriod(time.Date(today.Year(), today.Month(), today.Day()-7*i, 12, 0, 0, 0, timezone), timezone)
+	})
+}
+
+// ListByMonth retrieves usage statistics of the last calendar months, newest first
+// The current month is included, each month carries its change from the month before
+func (q *GetUsageQuery) ListByMonth(ctx context.Context, months int, timezone *time.Location) (entity.Usage, error) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	today := q.periodFactory.CreateDaily().StartAt().In(timezone)
+	return q.listByCalendar(ctx, months, func(i int) entity.Period {
+		return entity.NewMonthPeriod(time.Date(today.Year(), today.Month()-time.Month(i), 1, 12, 0, 0, 0, timezone), timezone)
+	})
+}
+
+// ListByHourBetween retrieves usage statistics of the clock hours overlapping the period, newest first
+// Unlike ListByHour the hours are not relative to now, so charts can ask for any time range
+func (q *GetUsageQuery) ListByHourBetween(ctx context.Context, period entity.Period, timezone *time.Location) (entity.Usage, error) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
+
+	var periods []entity.Period
+	for hour := entity.NewHourPeriod(period.EndAt(), timezone); !hour.EndAt().Before(period.StartAt()); hour = entity.NewHourPeriod(hour.StartAt().Add(-time.Hour), timezone) {
+		periods = append(periods, hour)
+	}
+
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats), nil
+}
+
+// ListByDayBetween retrieves usage statistics of the days overlapping the period, newest first
+// Days carry no moving averages, a chart can calculate its own over the returned range
+func (q *GetUsageQuery) ListByDayBetween(ctx context.Context, period entity.Period, timezone *time.Location) (entity.Usage, error) {
+	if timezone == nil {
+		timezone = time.UTC
+	}
 
-		// Get requests for this day using the API request repository
-		requests, err := q.repository.FindByPeriodWithLimit(period, 0, 0) // No limit for stats calculation
+	var periods []entity.Period
+	last := period.EndAt().In(timezone)
+	for i := 0; ; i++ {
+		// Noon is never skipped by a clock change, unlike midnight in some timezones
+		day := entity.NewDayPeriod(time.Date(last.Year(), last.Month(), last.Day()-i, 12, 0, 0, 0, timezone), timezone)
+		if day.EndAt().Before(period.StartAt()) {
+			break
+		}
+		periods = append(periods, day)
+	}
+
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats), nil
+}
+
+// ListByPeriods retrieves usage statistics of the given periods, in the same order as the periods
+// Callers slice the time their own way, e.g. into the blocks of the token limit
+func (q *GetUsageQuery) ListByPeriods(ctx context.Context, periods []entity.Period) (entity.Usage, error) {
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats), nil
+}
+
+// listByCalendar retrieves the usage of count periods created by periodAgo, newest first
+// One more period is read, so the oldest period has a change as well
+func (q *GetUsageQuery) listByCalendar(ctx context.Context, count int, periodAgo func(i int) entity.Period) (entity.Usage, error) {
+	if count <= 0 {
+		return entity.NewUsage(nil), nil
+	}
+
+	periods := make([]entity.Period, 0, count+1)
+	for i := 0; i <= count; i++ {
+		periods = append(periods, periodAgo(i))
+	}
+
+	stats, err := q.statsByPeriods(ctx, periods)
+	if err != nil {
+		return entity.Usage{}, err
+	}
+
+	return entity.NewUsage(stats[:count]).WithPrevious(stats[count]), nil
+}
+
+// calculateMovingAverages returns the premium and 1M context cost moving averages of the first days of the newest first stats
+func (q *GetUsageQuery) calculateMovingAverages(dailyStats []entity.Stats, days int) []entity.MovingAverage {
+	// Moving averages trail in chronological order, oldest first
+	costs := make([]entity.Cost, len(dailyStats))
+	for i, stat := range dailyStats {
+		costs[len(dailyStats)-1-i] = stat.RateLimitedCost()
+	}
+
+	averages := entity.CalculateMovingAverages(costs, days)
+	slices.Reverse(averages)
+	return averages
+}
+
+// statsByPeriods calculates the stats of each period, in the same order as the periods
+// The stats repository is queried concurrently when set, otherwise the stats are calculated from the requests
+func (q *GetUsageQuery) statsByPeriods(ctx context.Context, periods []entity.Period) ([]entity.Stats, error) {
+	stats := make([]entity.Stats, len(periods))
+
+	if q.statsRepository == nil {
+		requestsByPeriod, err := q.findByPeriods(ctx, periods)
 		if err != nil {
-			return entity.Usage{}, err
+			return nil, err
+		}
+		for i, period := range periods {
+			stats[i] = q.calculateStatsFromRequests(requestsByPeriod[i], period)
 		}
+		return stats, nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(usageQueryWorkers)
+	for i, period := range periods {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			periodStats, err := q.statsRepository.GetStatsByPeriod(period)
+			if err != nil {
+				return err
+			}
+			stats[i] = periodStats
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// findByPeriods retrieves the requests of each period, in the same order as the periods
+// Repositories supporting multi-period scans are read once, others are queried concurrently
+func (q *GetUsageQuery) findByPeriods(ctx context.Context, periods []entity.Period) ([][]entity.APIRequest, error) {
+	if repository, ok := q.repository.(APIRequestMultiPeriodRepository); ok {
+		return repository.FindByPeriods(periods)
+	}
+
+	requestsByPeriod := make([][]entity.APIRequest, len(periods))
 
-		// Calculate stats for this day
-		stats := q.calculateStatsFromRequests(requests, period)
-		dailyStats = append(dailyStats, stats)
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(usageQueryWorkers)
+	for i, period := range periods {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			requests, err := q.repository.FindByPeriodWithLimit(period, 0, 0) // No limit for stats calculation
+			if err != nil {
+				return err
+			}
+			requestsByPeriod[i] = requests
+			return nil
+		})
 	}
 
-	return entity.NewUsage(dailyStats), nil
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return requestsByPeriod, nil
 }
 
 // createHistoricalDailyPeriod creates a daily period for i days ago using PeriodFactory
-func (q *GetUsageQuery) createHistoricalDailyPeriod(daysAgo int) entity.Period {
+// Days are counted on the calendar, so a daylight saving time change does not shift the earlier days by an hour
+func (q *GetUsageQuery) createHistoricalDailyPeriod(daysAgo int, timezone *time.Location) entity.Period {
 	// Get today's period from the factory
 	todayPeriod := q.periodFactory.CreateDaily()
+	if daysAgo == 0 {
+		return todayPeriod
+	}
 
-	// Calculate the offset for historical days
-	dayOffset := time.Duration(daysAgo) * 24 * time.Hour
-
-	// Create historical period by shifting both start and end times
-	startAt := todayPeriod.StartAt().Add(-dayOffset)
-	endAt := todayPeriod.EndAt().Add(-dayOffset)
-
-	return entity.NewPeriod(startAt, endAt)
+	// Noon is never skipped by a clock change, unlike midnight in some timezones
+	today := todayPeriod.StartAt().In(timezone)
+	noon := time.Date(today.Year(), today.Month(), today.Day()-daysAgo, 12, 0, 0, 0, timezone)
+	return entity.NewDayPeriod(noon, timezone)
 }
 
 // calculateStatsFromRequests calculates statistics from a list of requests
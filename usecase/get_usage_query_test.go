@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -46,6 +47,247 @@ func TestGetUsageQuery_ListByDay(t *testing.T) {
 	}
 }
 
+func TestGetUsageQuery_ListByDayRange(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+
+	// One request per offset to identify which days are included in the window
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("recent", today.AddDate(0, 0, -5), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1000),
+		entity.NewAPIRequest("old", today.AddDate(0, 0, -35), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.002), 1000),
+		entity.NewAPIRequest("older", today.AddDate(0, 0, -61), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.003), 1000),
+	})
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	query := NewGetUsageQuery(repo, periodFactory)
+
+	tests := []struct {
+		name          string
+		offsetDays    int
+		days          int
+		expectedCosts map[int]float64 // index in window -> cost
+	}{
+		{
+			name:          "current window",
+			offsetDays:    0,
+			days:          30,
+			expectedCosts: map[int]float64{5: 0.001},
+		},
+		{
+			name:          "previous window",
+			offsetDays:    30,
+			days:          30,
+			expectedCosts: map[int]float64{5: 0.002},
+		},
+		{
+			name:          "window before previous",
+			offsetDays:    60,
+			days:          30,
+			expectedCosts: map[int]float64{1: 0.003},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := query.ListByDayRange(context.Background(), tt.offsetDays, tt.days, time.UTC)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			stats := usage.GetStats()
+			if len(stats) != tt.days {
+				t.Fatalf("Expected %d stats, got %d", tt.days, len(stats))
+			}
+
+			expectedFirstDay := periodFactory.CreateDaily().StartAt().AddDate(0, 0, -tt.offsetDays)
+			if !stats[0].Period().StartAt().Equal(expectedFirstDay) {
+				t.Errorf("Expected window to start at %v, got %v", expectedFirstDay, stats[0].Period().StartAt())
+			}
+
+			for i, stat := range stats {
+				expected := tt.expectedCosts[i]
+				if stat.TotalCost().Amount() != expected {
+					t.Errorf("Day %d: expected cost %.3f, got %.3f", i, expected, stat.TotalCost().Amount())
+				}
+			}
+		})
+	}
+}
+
+func TestGetUsageQuery_ListByHour(t *testing.T) {
+	currentHour := entity.NewHourPeriod(time.Now(), time.UTC)
+
+	// Two requests in the previous hour and one 47 hours ago, the oldest hour of the window
+	req1 := entity.NewAPIRequest("session1", currentHour.StartAt().Add(-50*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1500)
+	req2 := entity.NewAPIRequest("session1", currentHour.StartAt().Add(-10*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.002), 2000)
+	req3 := entity.NewAPIRequest("session2", currentHour.StartAt().Add(-47*time.Hour+time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1500)
+	req4 := entity.NewAPIRequest("session3", currentHour.StartAt().Add(-48*time.Hour+time.Minute), "claude-3-5-haiku-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1500)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{req1, req2, req3, req4})
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByHour(context.Background(), 48, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != 48 {
+		t.Fatalf("Expected 48 stats, got %d", len(stats))
+	}
+	if !stats[0].Period().StartAt().Equal(currentHour.StartAt()) {
+		t.Errorf("Expected the current hour first, got %v", stats[0].Period().StartAt())
+	}
+	if stats[1].TotalRequests() != 2 {
+		t.Errorf("Expected 2 requests in the previous hour, got %d", stats[1].TotalRequests())
+	}
+	if stats[47].TotalRequests() != 1 {
+		t.Errorf("Expected 1 request in the oldest hour, got %d", stats[47].TotalRequests())
+	}
+	if _, ok := usage.MovingAverageAt(0); ok {
+		t.Error("Expected hours without moving averages")
+	}
+}
+
+func TestGetUsageQuery_ListBetween(t *testing.T) {
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	req1 := entity.NewAPIRequest("session1", time.Date(2025, 6, 1, 1, 30, 0, 0, taipei), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.1), 1500)
+	req2 := entity.NewAPIRequest("session1", time.Date(2025, 6, 2, 23, 10, 0, 0, taipei), "claude-3-5-sonnet-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.2), 2000)
+	req3 := entity.NewAPIRequest("session2", time.Date(2025, 6, 2, 23, 50, 0, 0, taipei), "claude-3-5-haiku-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.4), 1500)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{req1, req2, req3})
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(taipei))
+	period := entity.NewPeriod(time.Date(2025, 6, 1, 1, 15, 0, 0, taipei), time.Date(2025, 6, 2, 23, 20, 0, 0, taipei))
+
+	tests := []struct {
+		name          string
+		list          func() (entity.Usage, error)
+		expectedFirst time.Time
+		expectedLast  time.Time
+		expectedCosts map[int]float64 // cost of the periods with requests, newest first
+		expectedCount int
+	}{
+		{
+			name:          "days",
+			list:          func() (entity.Usage, error) { return query.ListByDayBetween(context.Background(), period, taipei) },
+			expectedFirst: time.Date(2025, 6, 2, 0, 0, 0, 0, taipei),
+			expectedLast:  time.Date(2025, 6, 1, 0, 0, 0, 0, taipei),
+			expectedCosts: map[int]float64{0: 0.6, 1: 0.1},
+			expectedCount: 2,
+		},
+		{
+			name:          "hours",
+			list:          func() (entity.Usage, error) { return query.ListByHourBetween(context.Background(), period, taipei) },
+			expectedFirst: time.Date(2025, 6, 2, 23, 0, 0, 0, taipei),
+			expectedLast:  time.Date(2025, 6, 1, 1, 0, 0, 0, taipei),
+			expectedCosts: map[int]float64{0: 0.6, 46: 0.1},
+			expectedCount: 47,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := tt.list()
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			stats := usage.GetStats()
+			if len(stats) != tt.expectedCount {
+				t.Fatalf("Expected %d periods, got %d", tt.expectedCount, len(stats))
+			}
+			if !stats[0].Period().StartAt().Equal(tt.expectedFirst) {
+				t.Errorf("Expected the newest period to start at %v, got %v", tt.expectedFirst, stats[0].Period().StartAt())
+			}
+			if !stats[len(stats)-1].Period().StartAt().Equal(tt.expectedLast) {
+				t.Errorf("Expected the oldest period to start at %v, got %v", tt.expectedLast, stats[len(stats)-1].Period().StartAt())
+			}
+			for i, stat := range stats {
+				if got, want := stat.TotalCost().Amount(), tt.expectedCosts[i]; math.Abs(got-want) > 1e-9 {
+					t.Errorf("Period %d: expected cost %.3f, got %.3f", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUsageQuery_ListByWeek(t *testing.T) {
+	currentWeek := entity.NewWeekPeriod(time.Now(), time.UTC)
+
+	// Two requests this week, one last week and one in the week before the window
+	req1 := entity.NewAPIRequest("session1", currentWeek.StartAt().Add(time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.3), 1500)
+	req2 := entity.NewAPIRequest("session1", currentWeek.StartAt().Add(2*time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(200, 100, 0, 0), entity.NewCost(0.3), 2000)
+	req3 := entity.NewAPIRequest("session2", currentWeek.StartAt().Add(-time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.4), 1500)
+	req4 := entity.NewAPIRequest("session3", currentWeek.StartAt().AddDate(0, 0, -14).Add(time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.8), 1500)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{req1, req2, req3, req4})
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByWeek(context.Background(), 2, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 weeks, got %d", len(stats))
+	}
+	if !stats[0].Period().StartAt().Equal(currentWeek.StartAt()) || stats[0].Period().StartAt().Weekday() != time.Monday {
+		t.Errorf("Expected the current week starting on Monday first, got %v", stats[0].Period().StartAt())
+	}
+	if stats[0].TotalRequests() != 2 || stats[1].TotalRequests() != 1 {
+		t.Errorf("Expected 2 and 1 requests, got %d and %d", stats[0].TotalRequests(), stats[1].TotalRequests())
+	}
+
+	// The week before the window is only read for the change of the oldest week
+	change, ok := usage.ChangeAt(1)
+	if !ok || change.Previous().Amount() != 0.8 {
+		t.Errorf("Expected the oldest week to change from 0.8, got %.2f (%v)", change.Previous().Amount(), ok)
+	}
+	change, _ = usage.ChangeAt(0)
+	if percent, ok := change.Percent(); !ok || percent < 49.9 || percent > 50.1 {
+		t.Errorf("Expected +50%% week over week, got %.2f (%v)", percent, ok)
+	}
+}
+
+func TestGetUsageQuery_ListByMonth(t *testing.T) {
+	currentMonth := entity.NewMonthPeriod(time.Now(), time.UTC)
+
+	// One request this month and two last month
+	req1 := entity.NewAPIRequest("session1", currentMonth.StartAt().Add(time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1), 1500)
+	req2 := entity.NewAPIRequest("session2", currentMonth.StartAt().Add(-time.Minute), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1), 1500)
+	req3 := entity.NewAPIRequest("session2", currentMonth.StartAt().AddDate(0, -1, 0), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(1), 1500)
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData([]entity.APIRequest{req1, req2, req3})
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByMonth(context.Background(), 12, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != 12 {
+		t.Fatalf("Expected 12 months, got %d", len(stats))
+	}
+	if !stats[0].Period().StartAt().Equal(currentMonth.StartAt()) {
+		t.Errorf("Expected the current month first, got %v", stats[0].Period().StartAt())
+	}
+	if expected := currentMonth.StartAt().AddDate(0, -11, 0); !stats[11].Period().StartAt().Equal(expected) {
+		t.Errorf("Expected the oldest month to start at %v, got %v", expected, stats[11].Period().StartAt())
+	}
+	if stats[0].TotalRequests() != 1 || stats[1].TotalRequests() != 2 {
+		t.Errorf("Expected 1 and 2 requests, got %d and %d", stats[0].TotalRequests(), stats[1].TotalRequests())
+	}
+	if change, ok := usage.ChangeAt(0); !ok || change.Delta().Amount() != -1 {
+		t.Errorf("Expected a month over month delta of -1, got %.2f (%v)", change.Delta().Amount(), ok)
+	}
+}
+
 func TestGetUsageQuery_ListByDay_Error(t *testing.T) {
 	repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database error"})
 	periodFactory := service.NewTimePeriodFactory(time.UTC)
@@ -171,3 +413,245 @@ func TestGetUsageQuery_ListByDay_NilTimezone(t *testing.T) {
 		t.Errorf("Expected 1 request, got %d", stat.TotalRequests())
 	}
 }
+
+// multiPeriodRepository counts multi-period scans over the mock repository
+type multiPeriodRepository struct {
+	*testutil.MockAPIRequestRepository
+	scans int
+}
+
+func (r *multiPeriodRepository) FindByPeriods(periods []entity.Period) ([][]entity.APIRequest, error) {
+	r.scans++
+
+	requestsByPeriod := make([][]entity.APIRequest, len(periods))
+	for i, period := range periods {
+		requests, err := r.FindByPeriodWithLimit(period, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		requestsByPeriod[i] = requests
+	}
+	return requestsByPeriod, nil
+}
+
+func TestGetUsageQuery_ListByDay_MultiPeriodRepository(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+
+	mock := testutil.NewMockAPIRequestRepository()
+	mock.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session1", today.AddDate(0, 0, -3), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1000),
+	})
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+
+	tests := []struct {
+		name          string
+		repo          *multiPeriodRepository
+		expectedScans int
+	}{
+		{
+			name:          "uses a single scan",
+			repo:          &multiPeriodRepository{MockAPIRequestRepository: mock},
+			expectedScans: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := NewGetUsageQuery(tt.repo, periodFactory)
+
+			usage, err := query.ListByDay(context.Background(), 30, time.UTC)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if tt.repo.scans != tt.expectedScans {
+				t.Errorf("Expected %d scans, got %d", tt.expectedScans, tt.repo.scans)
+			}
+
+			stats := usage.GetStats()
+			if len(stats) != 30 {
+				t.Fatalf("Expected 30 stats, got %d", len(stats))
+			}
+			for i, stat := range stats {
+				expected := 0
+				if i == 3 {
+					expected = 1
+				}
+				if stat.TotalRequests() != expected {
+					t.Errorf("Day %d: expected %d requests, got %d", i, expected, stat.TotalRequests())
+				}
+			}
+		})
+	}
+}
+
+func TestGetUsageQuery_ListByDay_StatsRepository(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+
+	data := testutil.NewMockAPIRequestRepository()
+	data.SetMockData([]entity.APIRequest{
+		entity.NewAPIRequest("session1", today.AddDate(0, 0, -2), "claude-3-5-sonnet-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.002), 1000),
+		entity.NewAPIRequest("session2", today.AddDate(0, 0, -2), "claude-3-5-haiku-20241022", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.001), 1000),
+	})
+	// The requests are never read when the stats repository calculates the stats
+	repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "requests should not be read"})
+	query := NewGetUsageQueryWithStats(repo, testutil.NewMockStatsRepository(data), service.NewTimePeriodFactory(time.UTC))
+
+	usage, err := query.ListByDay(context.Background(), 7, time.UTC)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != 7 {
+		t.Fatalf("Expected 7 stats, got %d", len(stats))
+	}
+	if stats[2].PremiumRequests() != 1 || stats[2].BaseRequests() != 1 {
+		t.Errorf("Expected 1 premium and 1 base request two days ago, got %d and %d", stats[2].PremiumRequests(), stats[2].BaseRequests())
+	}
+	if stats[0].TotalRequests() != 0 {
+		t.Errorf("Expected no requests today, got %d", stats[0].TotalRequests())
+	}
+
+	// The moving averages are calculated from the stats of the days before the window as well
+	if _, ok := usage.MovingAverageAt(6); !ok {
+		t.Error("Expected a moving average of the oldest day")
+	}
+}
+
+func TestGetUsageQuery_ListByDay_CancelledContext(t *testing.T) {
+	repo := testutil.NewMockAPIRequestRepository()
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	query := NewGetUsageQuery(repo, periodFactory)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := query.ListByDay(ctx, 30, time.UTC); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestGetUsageQuery_ListByDayRange_MovingAverages(t *testing.T) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, time.UTC)
+
+	// $1 of premium usage on each of the last 40 days with a $9 spike of 1M context usage 3 days ago
+	var requests []entity.APIRequest
+	for i := 0; i < 40; i++ {
+		requests = append(requests, entity.NewAPIRequest("daily", today.AddDate(0, 0, -i), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(1), 1000))
+	}
+	requests = append(requests, entity.NewAPIRequest("spike", today.AddDate(0, 0, -3).Add(time.Minute), "claude-sonnet-4-20250514[1m]", entity.NewToken(100, 50, 0, 0), entity.NewCost(9), 1000))
+
+	repo := testutil.NewMockAPIRequestRepository()
+	repo.SetMockData(requests)
+	query := NewGetUsageQuery(repo, service.NewTimePeriodFactory(time.UTC))
+
+	tests := []struct {
+		name          string
+		offsetDays    int
+		expectedShort map[int]float64 // index in window -> 7-day average
+		expectedLong  map[int]float64 // index in window -> 30-day average
+	}{
+		{
+			name:          "spike inside both windows",
+			offsetDays:    0,
+			expectedShort: map[int]float64{0: 16.0 / 7, 3: 16.0 / 7, 4: 1},
+			expectedLong:  map[int]float64{0: 39.0 / 30, 3: 39.0 / 30, 4: 1},
+		},
+		{
+			name:          "history past the recorded days counts as zero",
+			offsetDays:    30,
+			expectedShort: map[int]float64{0: 1, 6: 4.0 / 7},
+			expectedLong:  map[int]float64{0: 10.0 / 30, 6: 4.0 / 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := query.ListByDayRange(context.Background(), tt.offsetDays, 7, time.UTC)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(usage.GetStats()) != 7 {
+				t.Fatalf("Expected 7 stats, got %d", len(usage.GetStats()))
+			}
+
+			for i, expected := range tt.expectedShort {
+				average, ok := usage.MovingAverageAt(i)
+				if !ok {
+					t.Fatalf("Day %d: expected a moving average", i)
+				}
+				if math.Abs(average.Short().Amount()-expected) > 1e-9 {
+					t.Errorf("Day %d: expected 7-day average %.4f, got %.4f", i, expected, average.Short().Amount())
+				}
+			}
+			for i, expected := range tt.expectedLong {
+				average, _ := usage.MovingAverageAt(i)
+				if math.Abs(average.Long().Amount()-expected) > 1e-9 {
+					t.Errorf("Day %d: expected 30-day average %.4f, got %.4f", i, expected, average.Long().Amount())
+				}
+			}
+
+			if _, ok := usage.MovingAverageAt(7); ok {
+				t.Error("Expected no moving average outside the window")
+			}
+		})
+	}
+}
+
+// fixedDayPeriodFactory creates the periods of a fixed point in time
+type fixedDayPeriodFactory struct {
+	now      time.Time
+	timezone *time.Location
+}
+
+func (f fixedDayPeriodFactory) CreateDaily() entity.Period {
+	return entity.NewDayPeriod(f.now, f.timezone)
+}
+
+func (f fixedDayPeriodFactory) CreateMonthly() entity.Period {
+	return entity.NewMonthPeriod(f.now, f.timezone)
+}
+
+func TestGetUsageQuery_ListByDay_DaylightSavingTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	// Days before the clocks went forward on 2025-03-09 keep their midnight boundaries
+	repo := testutil.NewMockAPIRequestRepository()
+	query := NewGetUsageQuery(repo, fixedDayPeriodFactory{now: time.Date(2025, 3, 10, 12, 0, 0, 0, newYork), timezone: newYork})
+
+	usage, err := query.ListByDay(context.Background(), 3, newYork)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		start time.Time
+		end   time.Time
+	}{
+		{time.Date(2025, 3, 10, 0, 0, 0, 0, newYork), time.Date(2025, 3, 11, 0, 0, 0, 0, newYork)},
+		{time.Date(2025, 3, 9, 0, 0, 0, 0, newYork), time.Date(2025, 3, 10, 0, 0, 0, 0, newYork)},
+		{time.Date(2025, 3, 8, 0, 0, 0, 0, newYork), time.Date(2025, 3, 9, 0, 0, 0, 0, newYork)},
+	}
+
+	stats := usage.GetStats()
+	if len(stats) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(stats))
+	}
+	for i, day := range expected {
+		period := stats[i].Period()
+		if !period.StartAt().Equal(day.start) {
+			t.Errorf("Day %d: expected start %v, got %v", i, day.start, period.StartAt().In(newYork))
+		}
+		if !period.EndAt().Equal(day.end.Add(-time.Nanosecond)) {
+			t.Errorf("Day %d: expected end before %v, got %v", i, day.end, period.EndAt().In(newYork))
+		}
+	}
+}
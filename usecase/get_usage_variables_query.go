@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/elct9620/ccmon/entity"
 )
@@ -18,18 +20,74 @@ type GetUsageVariablesQuery struct {
 	statsQuery     *CalculateStatsQuery
 	planRepository PlanRepository
 	periodFactory  PeriodFactory
+	block          *entity.Block
+	blockUsage     *GetBlockUsageQuery
+	costFormat     entity.CostFormat
+	budget         entity.Budget
+	streakQuery    *GetStreakQuery
+	anomalyQuery   *GetCostAnomalyQuery
+	project        string
+	user           string
 }
 
+// UsageVariablesOptions holds optional settings for GetUsageVariablesQuery
+type UsageVariablesOptions struct {
+	// Block enables block variables, they are reported as unavailable without it
+	Block *entity.Block
+	// CostFormat controls how cost variables are rendered, one decimal is kept without a precision
+	CostFormat entity.CostFormat
+	// Budget overrides the plan price for budget left variables
+	Budget entity.Budget
+	// Streak enables the goal streak variable, it is reported as unavailable without a goal
+	Streak *GetStreakQuery
+	// CostAnomaly enables the cost anomaly variable, it is reported as unavailable without an enabled policy
+	CostAnomaly *GetCostAnomalyQuery
+	// Project enables the project variables, they are reported as unavailable without it
+	Project string
+	// User selects the plan of a team member and only counts their requests, empty counts every request
+	User string
+}
+
+// unavailableValue is used for variables that can't be computed, e.g. block variables without a block,
+// budget variables without a plan price or budget, or the cost per 1K tokens before the first request of the day
+const unavailableValue = "n/a"
+
+// usageVariableCostPrecision is the decimals of cost variables when no precision is configured
+const usageVariableCostPrecision = 1
+
 // NewGetUsageVariablesQuery creates a new GetUsageVariablesQuery with the given dependencies
 func NewGetUsageVariablesQuery(
 	statsQuery *CalculateStatsQuery,
 	planRepository PlanRepository,
 	periodFactory PeriodFactory,
 ) *GetUsageVariablesQuery {
+	return NewGetUsageVariablesQueryWithOptions(statsQuery, planRepository, periodFactory, UsageVariablesOptions{})
+}
+
+// NewGetUsageVariablesQueryWithOptions creates a new GetUsageVariablesQuery with optional settings
+func NewGetUsageVariablesQueryWithOptions(
+	statsQuery *CalculateStatsQuery,
+	planRepository PlanRepository,
+	periodFactory PeriodFactory,
+	options UsageVariablesOptions,
+) *GetUsageVariablesQuery {
+	var blockUsage *GetBlockUsageQuery
+	if options.Block != nil {
+		blockUsage = NewGetBlockUsageQuery(statsQuery, *options.Block, options.User)
+	}
+
 	return &GetUsageVariablesQuery{
 		statsQuery:     statsQuery,
 		planRepository: planRepository,
 		periodFactory:  periodFactory,
+		block:          options.Block,
+		blockUsage:     blockUsage,
+		costFormat:     options.CostFormat.OrPrecision(usageVariableCostPrecision),
+		budget:         options.Budget,
+		streakQuery:    options.Streak,
+		anomalyQuery:   options.CostAnomaly,
+		project:        options.Project,
+		user:           options.User,
 	}
 }
 
@@ -41,7 +99,7 @@ func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string
 	}
 
 	// Get configured plan for percentage calculations
-	plan, err := q.planRepository.GetConfiguredPlan()
+	plan, err := q.getPlan()
 	if err != nil {
 		// Don't fail the entire query if plan is not configured
 		// Use an unset plan as fallback
@@ -60,6 +118,7 @@ func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string
 	// Get daily stats
 	dailyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
 		Period: dailyPeriod,
+		User:   q.user,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
@@ -73,13 +132,213 @@ func (q *GetUsageVariablesQuery) Execute(ctx context.Context) (map[string]string
 	// Get monthly stats
 	monthlyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
 		Period: monthlyPeriod,
+		User:   q.user,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate monthly stats: %w", err)
 	}
 
 	// Generate the variable map
-	return q.generateVariableMap(plan, dailyStats, monthlyStats), nil
+	variables := q.generateVariableMap(plan, dailyStats, monthlyStats)
+
+	// Add block comparison variables
+	if err := q.addBlockComparisonVariables(ctx, variables, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Add block schedule variables
+	q.addBlockTimeVariables(variables, time.Now())
+
+	// Add block usage variables
+	if err := q.addBlockUsageVariables(ctx, variables, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Add goal streak variable
+	if err := q.addStreakVariable(ctx, variables); err != nil {
+		return nil, err
+	}
+
+	// Add cost anomaly variable
+	if err := q.addCostAnomalyVariable(ctx, variables); err != nil {
+		return nil, err
+	}
+
+	// Add project cost variables
+	if err := q.addProjectVariables(ctx, variables, dailyPeriod, monthlyPeriod); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// getPlan returns the plan of the user when the repository assigns plans to team members, the configured plan otherwise
+func (q *GetUsageVariablesQuery) getPlan() (entity.Plan, error) {
+	if repository, ok := q.planRepository.(UserPlanRepository); ok && q.user != "" {
+		return repository.GetUserPlan(q.user)
+	}
+	return q.planRepository.GetConfiguredPlan()
+}
+
+// addBlockComparisonVariables compares the current block to the previous block at the same elapsed time
+func (q *GetUsageVariablesQuery) addBlockComparisonVariables(ctx context.Context, variables map[string]string, now time.Time) error {
+	variables[entity.PrevBlockUsageVariable.Key()] = unavailableValue
+	variables[entity.BlockVsPrevVariable.Key()] = unavailableValue
+
+	if q.block == nil {
+		return nil
+	}
+
+	currentBlock := q.block.NextBlock(now)
+	previousBlock := currentBlock.PreviousBlock()
+	elapsed := currentBlock.Elapsed(now)
+
+	currentStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: currentBlock.ElapsedPeriod(elapsed),
+		User:   q.user,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to calculate current block stats: %w", err)
+	}
+
+	previousStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{
+		Period: previousBlock.ElapsedPeriod(elapsed),
+		User:   q.user,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to calculate previous block stats: %w", err)
+	}
+
+	// Only rate-limited tokens count toward block limits
+	previousTokens := previousStats.RateLimitedTokens()
+	if previousBlock.HasLimit() {
+		variables[entity.PrevBlockUsageVariable.Key()] = fmt.Sprintf("%d%%", int(previousBlock.CalculateProgress(previousTokens)))
+	} else {
+		variables[entity.PrevBlockUsageVariable.Key()] = entity.FormatTokenCount(previousTokens.Limited())
+	}
+
+	// Without previous usage there is no meaningful ratio
+	if previousTokens.Limited() > 0 {
+		change := (float64(currentStats.RateLimitedTokens().Limited())/float64(previousTokens.Limited()) - 1) * 100
+		variables[entity.BlockVsPrevVariable.Key()] = fmt.Sprintf("%+d%%", int(change))
+	}
+
+	return nil
+}
+
+// addBlockTimeVariables adds when the current block ends, so automation can schedule heavy runs after the reset
+func (q *GetUsageVariablesQuery) addBlockTimeVariables(variables map[string]string, now time.Time) {
+	variables[entity.BlockTimeLeftVariable.Key()] = unavailableValue
+	variables[entity.BlockTimeRemainingVariable.Key()] = unavailableValue
+	variables[entity.BlockEndAtVariable.Key()] = unavailableValue
+
+	if q.block == nil {
+		return
+	}
+
+	currentBlock := q.block.NextBlock(now)
+	variables[entity.BlockTimeLeftVariable.Key()] = formatBlockTimeLeft(currentBlock.Remaining(now))
+	// Named after the other block usage variables, the same value as @block_time_left
+	variables[entity.BlockTimeRemainingVariable.Key()] = variables[entity.BlockTimeLeftVariable.Key()]
+	variables[entity.BlockEndAtVariable.Key()] = currentBlock.EndAt().Format(time.RFC3339)
+}
+
+// addBlockUsageVariables adds the usage of the current block against the configured token limit
+func (q *GetUsageVariablesQuery) addBlockUsageVariables(ctx context.Context, variables map[string]string, now time.Time) error {
+	variables[entity.BlockUsageVariable.Key()] = unavailableValue
+	variables[entity.BlockTokensRemainingVariable.Key()] = unavailableValue
+	variables[entity.BlockLimitVariable.Key()] = unavailableValue
+
+	if q.blockUsage == nil {
+		return nil
+	}
+
+	usage, err := q.blockUsage.Execute(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	// Without a limit only the used tokens can be shown
+	if !usage.Block.HasLimit() {
+		variables[entity.BlockUsageVariable.Key()] = entity.FormatTokenCount(usage.Tokens.Limited())
+		return nil
+	}
+
+	variables[entity.BlockUsageVariable.Key()] = fmt.Sprintf("%d%%", int(usage.Block.CalculateProgress(usage.Tokens)))
+	if remaining, ok := usage.Block.TokensRemaining(usage.Tokens); ok {
+		variables[entity.BlockTokensRemainingVariable.Key()] = entity.FormatTokenCount(remaining)
+	}
+	variables[entity.BlockLimitVariable.Key()] = entity.FormatTokenCount(int64(usage.Block.TokenLimit()))
+	return nil
+}
+
+// addStreakVariable adds the consecutive days meeting the daily goal
+func (q *GetUsageVariablesQuery) addStreakVariable(ctx context.Context, variables map[string]string) error {
+	variables[entity.StreakVariable.Key()] = unavailableValue
+
+	if q.streakQuery == nil || !q.streakQuery.Goal().IsEnabled() {
+		return nil
+	}
+
+	streak, err := q.streakQuery.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to calculate goal streak: %w", err)
+	}
+
+	variables[entity.StreakVariable.Key()] = streak.String()
+	return nil
+}
+
+// addCostAnomalyVariable adds how many times its baseline today costs, empty while today is within the multiple
+func (q *GetUsageVariablesQuery) addCostAnomalyVariable(ctx context.Context, variables map[string]string) error {
+	variables[entity.CostAnomalyVariable.Key()] = unavailableValue
+
+	if q.anomalyQuery == nil || !q.anomalyQuery.Policy().IsEnabled() {
+		return nil
+	}
+
+	anomaly, err := q.anomalyQuery.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect cost anomaly: %w", err)
+	}
+
+	if _, ok := anomaly.Ratio(); ok {
+		variables[entity.CostAnomalyVariable.Key()] = anomaly.String()
+	}
+	return nil
+}
+
+// addProjectVariables adds the daily and monthly cost of the project
+func (q *GetUsageVariablesQuery) addProjectVariables(ctx context.Context, variables map[string]string, dailyPeriod, monthlyPeriod entity.Period) error {
+	variables[entity.ProjectDailyCostVariable.Key()] = unavailableValue
+	variables[entity.ProjectMonthlyCostVariable.Key()] = unavailableValue
+
+	if q.project == "" {
+		return nil
+	}
+
+	dailyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: dailyPeriod, Project: q.project, User: q.user})
+	if err != nil {
+		return fmt.Errorf("failed to calculate daily project stats: %w", err)
+	}
+
+	monthlyStats, err := q.statsQuery.Execute(ctx, CalculateStatsParams{Period: monthlyPeriod, Project: q.project, User: q.user})
+	if err != nil {
+		return fmt.Errorf("failed to calculate monthly project stats: %w", err)
+	}
+
+	variables[entity.ProjectDailyCostVariable.Key()] = q.costFormat.Format(dailyStats.TotalCost())
+	variables[entity.ProjectMonthlyCostVariable.Key()] = q.costFormat.Format(monthlyStats.TotalCost())
+	return nil
+}
+
+// formatBlockTimeLeft formats the time left in minutes as a Go duration (e.g. "1h23m"), parseable by time.ParseDuration
+func formatBlockTimeLeft(remaining time.Duration) string {
+	remaining = remaining.Truncate(time.Minute)
+	if remaining <= 0 {
+		return "0m"
+	}
+	return strings.TrimSuffix(remaining.String(), "0s")
 }
 
 // generateVariableMap creates the substitution map from stats and plan data
@@ -92,11 +351,11 @@ func (q *GetUsageVariablesQuery) generateVariableMap(
 
 	// Daily cost
 	dailyCost := dailyStats.TotalCost()
-	variables[entity.DailyCostVariable.Key()] = fmt.Sprintf("$%.1f", dailyCost.Amount())
+	variables[entity.DailyCostVariable.Key()] = q.costFormat.Format(dailyCost)
 
 	// Monthly cost
 	monthlyCost := monthlyStats.TotalCost()
-	variables[entity.MonthlyCostVariable.Key()] = fmt.Sprintf("$%.1f", monthlyCost.Amount())
+	variables[entity.MonthlyCostVariable.Key()] = q.costFormat.Format(monthlyCost)
 
 	// Daily plan usage percentage - using entity business logic
 	dailyPercentage := plan.CalculateUsagePercentageInPeriod(dailyCost, dailyStats.Period())
@@ -106,5 +365,30 @@ func (q *GetUsageVariablesQuery) generateVariableMap(
 	monthlyPercentage := plan.CalculateUsagePercentage(monthlyCost)
 	variables[entity.MonthlyPlanUsageVariable.Key()] = fmt.Sprintf("%d%%", monthlyPercentage)
 
+	// Budget left, from the budget config or the plan price
+	variables[entity.DailyBudgetLeftVariable.Key()] = unavailableValue
+	if left, ok := q.budget.DailyLeft(plan, dailyStats.Period(), dailyCost); ok {
+		variables[entity.DailyBudgetLeftVariable.Key()] = q.costFormat.Format(left)
+	}
+	variables[entity.MonthlyBudgetLeftVariable.Key()] = unavailableValue
+	if left, ok := q.budget.MonthlyLeft(plan, monthlyCost); ok {
+		variables[entity.MonthlyBudgetLeftVariable.Key()] = q.costFormat.Format(left)
+	}
+
+	// 1M context costs, reported separately since they are priced higher than premium
+	variables[entity.DailyLongContextCostVariable.Key()] = q.costFormat.Format(dailyStats.LongContextCost())
+	variables[entity.MonthlyLongContextCostVariable.Key()] = q.costFormat.Format(monthlyStats.LongContextCost())
+
+	// Output tokens spent on tool calls, zero unless telemetry reports the split
+	variables[entity.DailyToolTokensVariable.Key()] = entity.FormatTokenCount(dailyStats.TotalTokens().ToolUse())
+	variables[entity.MonthlyToolTokensVariable.Key()] = entity.FormatTokenCount(monthlyStats.TotalTokens().ToolUse())
+	variables[entity.DailyToolShareVariable.Key()] = fmt.Sprintf("%d%%", int(dailyStats.TotalTokens().ToolUseShare()))
+
+	// Cost per 1K tokens, falls as cache reads and cheaper models take a larger share
+	variables[entity.CostPerKiloTokenVariable.Key()] = unavailableValue
+	if costPerKiloToken, ok := dailyStats.CostPerKiloToken(); ok {
+		variables[entity.CostPerKiloTokenVariable.Key()] = fmt.Sprintf("$%.4f", costPerKiloToken.Amount())
+	}
+
 	return variables
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
 	"github.com/elct9620/ccmon/testutil"
 	"github.com/elct9620/ccmon/usecase"
 )
@@ -101,6 +102,35 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 				"@monthly_cost":       "$140.0",
 				"@daily_plan_usage":   calculateExpectedDailyUsage(1.0, 20.0), // Calculate based on current month
 				"@monthly_plan_usage": "700%",                                 // (140/20)*100 = 700%
+
+				"@daily_budget_left":   "$0.0",
+				"@monthly_budget_left": "$0.0",
+
+				"@daily_long_context_cost":   "$0.0",
+				"@monthly_long_context_cost": "$0.0",
+
+				"@daily_tool_tokens":   "0",
+				"@monthly_tool_tokens": "0",
+				"@daily_tool_share":    "0%",
+
+				"@cost_per_ktok": "$0.1888", // $1.00 for 5,298 tokens
+
+				"@prev_block_usage": "n/a",
+				"@block_vs_prev":    "n/a",
+				"@block_time_left":  "n/a",
+				"@block_end_at":     "n/a",
+
+				"@block_usage":            "n/a",
+				"@block_tokens_remaining": "n/a",
+				"@block_time_remaining":   "n/a",
+				"@block_limit":            "n/a",
+
+				"@streak": "n/a",
+
+				"@cost_anomaly": "n/a",
+
+				"@project_daily_cost":   "n/a",
+				"@project_monthly_cost": "n/a",
 			},
 		},
 		{
@@ -113,6 +143,35 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 				"@monthly_cost":       "$140.0",
 				"@daily_plan_usage":   "0%", // unset plan always returns 0%
 				"@monthly_plan_usage": "0%", // unset plan always returns 0%
+
+				"@daily_budget_left":   "n/a",
+				"@monthly_budget_left": "n/a",
+
+				"@daily_long_context_cost":   "$0.0",
+				"@monthly_long_context_cost": "$0.0",
+
+				"@daily_tool_tokens":   "0",
+				"@monthly_tool_tokens": "0",
+				"@daily_tool_share":    "0%",
+
+				"@cost_per_ktok": "$0.1888", // $1.00 for 5,298 tokens
+
+				"@prev_block_usage": "n/a",
+				"@block_vs_prev":    "n/a",
+				"@block_time_left":  "n/a",
+				"@block_end_at":     "n/a",
+
+				"@block_usage":            "n/a",
+				"@block_tokens_remaining": "n/a",
+				"@block_time_remaining":   "n/a",
+				"@block_limit":            "n/a",
+
+				"@streak": "n/a",
+
+				"@cost_anomaly": "n/a",
+
+				"@project_daily_cost":   "n/a",
+				"@project_monthly_cost": "n/a",
 			},
 		},
 		{
@@ -125,6 +184,124 @@ func TestGetUsageVariablesQuery_Execute(t *testing.T) {
 				"@monthly_cost":       "$140.0",
 				"@daily_plan_usage":   "0%", // fallback to unset plan always returns 0%
 				"@monthly_plan_usage": "0%", // fallback to unset plan always returns 0%
+
+				"@daily_budget_left":   "n/a",
+				"@monthly_budget_left": "n/a",
+
+				"@daily_long_context_cost":   "$0.0",
+				"@monthly_long_context_cost": "$0.0",
+
+				"@daily_tool_tokens":   "0",
+				"@monthly_tool_tokens": "0",
+				"@daily_tool_share":    "0%",
+
+				"@cost_per_ktok": "$0.1888", // $1.00 for 5,298 tokens
+
+				"@prev_block_usage": "n/a",
+				"@block_vs_prev":    "n/a",
+				"@block_time_left":  "n/a",
+				"@block_end_at":     "n/a",
+
+				"@block_usage":            "n/a",
+				"@block_tokens_remaining": "n/a",
+				"@block_time_remaining":   "n/a",
+				"@block_limit":            "n/a",
+
+				"@streak": "n/a",
+
+				"@cost_anomaly": "n/a",
+
+				"@project_daily_cost":   "n/a",
+				"@project_monthly_cost": "n/a",
+			},
+		},
+		{
+			name: "long context costs reported separately",
+			plan: entity.NewPlan("pro", entity.NewCost(20.0)),
+			dailyRequests: append(createAPIRequests(5, 3, 0.5, 0.5),
+				entity.NewAPIRequest("test-session", now, "claude-sonnet-4-20250514[1m]", entity.NewToken(5000, 500, 0, 0), entity.NewCost(2.0), 1000)),
+			monthlyRequests: append(createAPIRequests(50, 30, 50.0, 90.0),
+				entity.NewAPIRequest("test-session", now, "claude-sonnet-4-20250514[1m]", entity.NewToken(5000, 500, 0, 0), entity.NewCost(10.0), 1000)),
+			expectedVars: map[string]string{
+				"@daily_cost":         "$3.0",
+				"@monthly_cost":       "$150.0",
+				"@daily_plan_usage":   calculateExpectedDailyUsage(3.0, 20.0),
+				"@monthly_plan_usage": "750%",
+
+				"@daily_budget_left":   "$0.0",
+				"@monthly_budget_left": "$0.0",
+
+				"@daily_long_context_cost":   "$2.0",
+				"@monthly_long_context_cost": "$10.0",
+
+				"@daily_tool_tokens":   "0",
+				"@monthly_tool_tokens": "0",
+				"@daily_tool_share":    "0%",
+
+				"@cost_per_ktok": "$0.2778", // $3.00 for 10,798 tokens
+
+				"@prev_block_usage": "n/a",
+				"@block_vs_prev":    "n/a",
+				"@block_time_left":  "n/a",
+				"@block_end_at":     "n/a",
+
+				"@block_usage":            "n/a",
+				"@block_tokens_remaining": "n/a",
+				"@block_time_remaining":   "n/a",
+				"@block_limit":            "n/a",
+
+				"@streak": "n/a",
+
+				"@cost_anomaly": "n/a",
+
+				"@project_daily_cost":   "n/a",
+				"@project_monthly_cost": "n/a",
+			},
+		},
+		{
+			name: "tool use tokens split from output",
+			plan: entity.NewPlan("pro", entity.NewCost(20.0)),
+			dailyRequests: []entity.APIRequest{
+				entity.NewAPIRequest("test-session", now, "claude-sonnet-4-20250514", entity.NewToken(5000, 2000, 0, 0).WithToolUse(1500), entity.NewCost(1.0), 1000),
+			},
+			monthlyRequests: []entity.APIRequest{
+				entity.NewAPIRequest("test-session", now, "claude-sonnet-4-20250514", entity.NewToken(5000, 2000, 0, 0).WithToolUse(1500), entity.NewCost(1.0), 1000),
+				entity.NewAPIRequest("test-session", now.Add(-48*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 2000, 0, 0), entity.NewCost(1.0), 1000),
+			},
+			expectedVars: map[string]string{
+				"@daily_cost":         "$1.0",
+				"@monthly_cost":       "$2.0",
+				"@daily_plan_usage":   calculateExpectedDailyUsage(1.0, 20.0),
+				"@monthly_plan_usage": "10%",
+
+				"@daily_budget_left":   "$0.0", // the daily share of the plan price is below the daily cost
+				"@monthly_budget_left": "$18.0",
+
+				"@daily_long_context_cost":   "$0.0",
+				"@monthly_long_context_cost": "$0.0",
+
+				"@daily_tool_tokens":   "1.5K",
+				"@monthly_tool_tokens": "1.5K",
+				"@daily_tool_share":    "75%",
+
+				"@cost_per_ktok": "$0.1429", // $1.00 for 7,000 tokens
+
+				"@prev_block_usage": "n/a",
+				"@block_vs_prev":    "n/a",
+				"@block_time_left":  "n/a",
+				"@block_end_at":     "n/a",
+
+				"@block_usage":            "n/a",
+				"@block_tokens_remaining": "n/a",
+				"@block_time_remaining":   "n/a",
+				"@block_limit":            "n/a",
+
+				"@streak": "n/a",
+
+				"@cost_anomaly": "n/a",
+
+				"@project_daily_cost":   "n/a",
+				"@project_monthly_cost": "n/a",
 			},
 		},
 		{
@@ -352,3 +529,460 @@ func TestGetUsageVariablesQuery_CurrentMonthCalculation(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUsageVariablesQuery_BlockComparison(t *testing.T) {
+	now := time.Now()
+	// Current block started an hour ago, so the previous block is compared over its first hour
+	blockStart := now.Add(-time.Hour)
+
+	premiumRequest := func(timestamp time.Time, tokens int64) entity.APIRequest {
+		return testutil.CreateTestAPIRequest("test-session", timestamp, "claude-sonnet-4-20250514", tokens, 0, 0.1)
+	}
+
+	tests := []struct {
+		name             string
+		block            *entity.Block
+		requests         []entity.APIRequest
+		expectedPrevious string
+		expectedVsPrev   string
+	}{
+		{
+			name:             "no block configured",
+			block:            nil,
+			requests:         []entity.APIRequest{premiumRequest(now.Add(-30*time.Minute), 1000)},
+			expectedPrevious: "n/a",
+			expectedVsPrev:   "n/a",
+		},
+		{
+			name:  "heavier than previous block",
+			block: blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			requests: []entity.APIRequest{
+				premiumRequest(now.Add(-30*time.Minute), 1500),
+				premiumRequest(blockStart.Add(-5*time.Hour+30*time.Minute), 1000),
+				// Outside the same relative elapsed time in the previous block
+				premiumRequest(blockStart.Add(-5*time.Hour+3*time.Hour), 5000),
+			},
+			expectedPrevious: "10%",
+			expectedVsPrev:   "+50%",
+		},
+		{
+			name:  "lighter than previous block without limit",
+			block: blockPtr(entity.NewBlock(blockStart)),
+			requests: []entity.APIRequest{
+				premiumRequest(now.Add(-30*time.Minute), 500),
+				premiumRequest(blockStart.Add(-5*time.Hour+30*time.Minute), 2000),
+			},
+			expectedPrevious: "2.0K",
+			expectedVsPrev:   "-75%",
+		},
+		{
+			name:             "no usage in previous block",
+			block:            blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			requests:         []entity.APIRequest{premiumRequest(now.Add(-30*time.Minute), 1500)},
+			expectedPrevious: "0%",
+			expectedVsPrev:   "n/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(tt.requests)
+			statsQuery := usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache())
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(now.Add(-24*time.Hour), now),
+				monthlyPeriod: entity.NewPeriod(now.Add(-30*24*time.Hour), now),
+			}
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				statsQuery,
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				periodFactory,
+				usecase.UsageVariablesOptions{Block: tt.block},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@prev_block_usage"]; got != tt.expectedPrevious {
+				t.Errorf("@prev_block_usage: got %s, want %s", got, tt.expectedPrevious)
+			}
+			if got := vars["@block_vs_prev"]; got != tt.expectedVsPrev {
+				t.Errorf("@block_vs_prev: got %s, want %s", got, tt.expectedVsPrev)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_BlockTime(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name             string
+		block            *entity.Block
+		expectedTimeLeft string
+		expectedEndAt    string
+	}{
+		{
+			name:             "no block configured",
+			expectedTimeLeft: "n/a",
+			expectedEndAt:    "n/a",
+		},
+		{
+			name:             "current block",
+			block:            blockPtr(entity.NewBlock(now.Add(-3*time.Hour - 37*time.Minute - 30*time.Second))),
+			expectedTimeLeft: "1h22m",
+			expectedEndAt:    now.Add(-3*time.Hour - 37*time.Minute - 30*time.Second).Add(entity.TimeBlockDuration).Format(time.RFC3339),
+		},
+		{
+			name:             "block advances after it ends",
+			block:            blockPtr(entity.NewBlock(now.Add(-5*time.Hour - 30*time.Minute - 30*time.Second))),
+			expectedTimeLeft: "4h29m",
+			expectedEndAt:    now.Add(-30*time.Minute - 30*time.Second).Add(entity.TimeBlockDuration).Format(time.RFC3339),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(nil)
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(now.Add(-24*time.Hour), now),
+				monthlyPeriod: entity.NewPeriod(now.Add(-30*24*time.Hour), now),
+			}
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				periodFactory,
+				usecase.UsageVariablesOptions{Block: tt.block, CostFormat: entity.DefaultCostFormat()},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@block_time_left"]; got != tt.expectedTimeLeft {
+				t.Errorf("@block_time_left: got %s, want %s", got, tt.expectedTimeLeft)
+			}
+			if got := vars["@block_end_at"]; got != tt.expectedEndAt {
+				t.Errorf("@block_end_at: got %s, want %s", got, tt.expectedEndAt)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_BlockUsage(t *testing.T) {
+	now := time.Now()
+	blockStart := now.Add(-time.Hour)
+
+	premiumRequest := func(timestamp time.Time, tokens int64) entity.APIRequest {
+		return testutil.CreateTestAPIRequest("test-session", timestamp, "claude-sonnet-4-20250514", tokens, 0, 0.1)
+	}
+
+	tests := []struct {
+		name     string
+		block    *entity.Block
+		requests []entity.APIRequest
+		expected map[string]string
+	}{
+		{
+			name:     "no block configured",
+			requests: []entity.APIRequest{premiumRequest(now.Add(-30*time.Minute), 1000)},
+			expected: map[string]string{"@block_usage": "n/a", "@block_tokens_remaining": "n/a", "@block_time_remaining": "n/a", "@block_limit": "n/a"},
+		},
+		{
+			name:  "within limit",
+			block: blockPtr(entity.NewBlockWithLimit(blockStart, 10000)),
+			requests: []entity.APIRequest{
+				premiumRequest(now.Add(-30*time.Minute), 4200),
+				// Before the block started
+				premiumRequest(blockStart.Add(-time.Minute), 5000),
+				// Base models do not count toward the limit
+				testutil.CreateTestAPIRequest("test-session", now.Add(-10*time.Minute), "claude-3-haiku-20240307", 3000, 0, 0.01),
+			},
+			expected: map[string]string{"@block_usage": "42%", "@block_tokens_remaining": "5.8K", "@block_time_remaining": "3h59m", "@block_limit": "10.0K"},
+		},
+		{
+			name:     "limit exceeded",
+			block:    blockPtr(entity.NewBlockWithLimit(blockStart, 1000)),
+			requests: []entity.APIRequest{premiumRequest(now.Add(-30*time.Minute), 1500)},
+			expected: map[string]string{"@block_usage": "150%", "@block_tokens_remaining": "0", "@block_time_remaining": "3h59m", "@block_limit": "1.0K"},
+		},
+		{
+			name:     "without limit",
+			block:    blockPtr(entity.NewBlock(blockStart)),
+			requests: []entity.APIRequest{premiumRequest(now.Add(-30*time.Minute), 1500)},
+			expected: map[string]string{"@block_usage": "1.5K", "@block_tokens_remaining": "n/a", "@block_time_remaining": "3h59m", "@block_limit": "n/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(tt.requests)
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(now.Add(-24*time.Hour), now),
+				monthlyPeriod: entity.NewPeriod(now.Add(-30*24*time.Hour), now),
+			}
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				periodFactory,
+				usecase.UsageVariablesOptions{Block: tt.block, CostFormat: entity.DefaultCostFormat()},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for key, expected := range tt.expected {
+				if got := vars[key]; got != expected {
+					t.Errorf("%s: got %s, want %s", key, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_Project(t *testing.T) {
+	now := time.Now()
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(1.5), 1000).WithProject("ccmon"),
+		entity.NewAPIRequest("session-2", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(2.0), 1000).WithProject("other"),
+		entity.NewAPIRequest("session-1", now.Add(-10*24*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.5), 1000).WithProject("ccmon"),
+	}
+
+	tests := []struct {
+		name            string
+		project         string
+		expectedDaily   string
+		expectedMonthly string
+	}{
+		{name: "no project", project: "", expectedDaily: "n/a", expectedMonthly: "n/a"},
+		{name: "project with usage", project: "ccmon", expectedDaily: "$1.50", expectedMonthly: "$2.00"},
+		{name: "project without usage", project: "unused", expectedDaily: "$0.00", expectedMonthly: "$0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour)),
+				monthlyPeriod: entity.NewPeriod(now.Add(-30*24*time.Hour), now.Add(time.Hour)),
+			}
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				periodFactory,
+				usecase.UsageVariablesOptions{CostFormat: entity.DefaultCostFormat(), Project: tt.project},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@project_daily_cost"]; got != tt.expectedDaily {
+				t.Errorf("@project_daily_cost: got %s, want %s", got, tt.expectedDaily)
+			}
+			if got := vars["@project_monthly_cost"]; got != tt.expectedMonthly {
+				t.Errorf("@project_monthly_cost: got %s, want %s", got, tt.expectedMonthly)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_CostAnomaly(t *testing.T) {
+	now := time.Now().UTC()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	newRequest := func(daysAgo int, cost float64) entity.APIRequest {
+		return entity.NewAPIRequest("session", todayStart.AddDate(0, 0, -daysAgo), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(cost), 1000)
+	}
+	requests := []entity.APIRequest{newRequest(0, 3)}
+	for daysAgo := 1; daysAgo <= 7; daysAgo++ {
+		requests = append(requests, newRequest(daysAgo, 1))
+	}
+
+	tests := []struct {
+		name     string
+		multiple float64
+		anomaly  bool
+		expected string
+	}{
+		{name: "without the query", expected: "n/a"},
+		{name: "disabled policy", multiple: 0, anomaly: true, expected: "n/a"},
+		{name: "today above the multiple", multiple: 2, anomaly: true, expected: "3.0x"},
+		{name: "today within the multiple", multiple: 4, anomaly: true, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiRepo, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(todayStart, todayStart.AddDate(0, 0, 1)),
+				monthlyPeriod: entity.NewPeriod(todayStart.AddDate(0, 0, -30), todayStart.AddDate(0, 0, 1)),
+			}
+
+			options := usecase.UsageVariablesOptions{CostFormat: entity.DefaultCostFormat()}
+			if tt.anomaly {
+				usageQuery := usecase.NewGetUsageQuery(apiRepo, service.NewTimePeriodFactory(time.UTC))
+				options.CostAnomaly = usecase.NewGetCostAnomalyQuery(usageQuery, entity.NewCostAnomalyPolicy(tt.multiple), time.UTC)
+			}
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0))),
+				periodFactory,
+				options,
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@cost_anomaly"]; got != tt.expected {
+				t.Errorf("@cost_anomaly: got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetUsageVariablesQuery_User(t *testing.T) {
+	now := time.Now()
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(50), 1000).WithUser("alice@example.com"),
+		entity.NewAPIRequest("session-2", now, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(10), 1000).WithUser("bob@example.com"),
+	}
+
+	tests := []struct {
+		name            string
+		user            string
+		expectedCost    string
+		expectedMonthly string
+	}{
+		{name: "every user on the team plan", user: "", expectedCost: "$60.00", expectedMonthly: "300%"},
+		{name: "user with an own plan", user: "alice@example.com", expectedCost: "$50.00", expectedMonthly: "50%"},
+		{name: "user on the team plan", user: "bob@example.com", expectedCost: "$10.00", expectedMonthly: "50%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			periodFactory := &MockPeriodFactory{
+				dailyPeriod:   entity.NewPeriod(now.Add(-time.Hour), now.Add(time.Hour)),
+				monthlyPeriod: entity.NewPeriod(now.Add(-30*24*time.Hour), now.Add(time.Hour)),
+			}
+			planRepo := testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20.0)))
+			planRepo.SetUserPlan("alice@example.com", entity.NewPlan("max", entity.NewCost(100.0)))
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				usecase.NewCalculateStatsQuery(statsRepo, testutil.NewNoOpStatsCache()),
+				planRepo,
+				periodFactory,
+				usecase.UsageVariablesOptions{CostFormat: entity.DefaultCostFormat(), User: tt.user},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@monthly_cost"]; got != tt.expectedCost {
+				t.Errorf("@monthly_cost: got %s, want %s", got, tt.expectedCost)
+			}
+			if got := vars["@monthly_plan_usage"]; got != tt.expectedMonthly {
+				t.Errorf("@monthly_plan_usage: got %s, want %s", got, tt.expectedMonthly)
+			}
+		})
+	}
+}
+
+func blockPtr(block entity.Block) *entity.Block {
+	return &block
+}
+
+func TestGetUsageVariablesQuery_BudgetLeft(t *testing.T) {
+	// June has 30 days, a $30 plan allows $1 a day
+	dailyPeriod := entity.NewPeriod(
+		time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 15, 23, 59, 59, 999999999, time.UTC),
+	)
+	monthlyPeriod := entity.NewPeriod(
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 30, 23, 59, 59, 999999999, time.UTC),
+	)
+	dailyRequests := createAPIRequests(0, 2, 0, 0.6)    // $0.60 spent today
+	monthlyRequests := createAPIRequests(0, 4, 0, 26.6) // $26.60 spent this month
+
+	tests := []struct {
+		name                string
+		plan                entity.Plan
+		budget              entity.Budget
+		expectedDailyLeft   string
+		expectedMonthlyLeft string
+	}{
+		{
+			name:                "plan price without budget config",
+			plan:                entity.NewPlan("pro", entity.NewCost(30)),
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(0)),
+			expectedDailyLeft:   "$0.40",
+			expectedMonthlyLeft: "$3.40",
+		},
+		{
+			name:                "budget config overrides plan price",
+			plan:                entity.NewPlan("pro", entity.NewCost(30)),
+			budget:              entity.NewBudget(entity.NewCost(4), entity.NewCost(100)),
+			expectedDailyLeft:   "$3.40",
+			expectedMonthlyLeft: "$73.40",
+		},
+		{
+			name:                "overspent monthly budget",
+			plan:                entity.NewPlan("unset", entity.NewCost(0)),
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(20)),
+			expectedDailyLeft:   "$0.07",
+			expectedMonthlyLeft: "$0.00",
+		},
+		{
+			name:                "no plan price nor budget",
+			plan:                entity.NewPlan("unset", entity.NewCost(0)),
+			budget:              entity.NewBudget(entity.NewCost(0), entity.NewCost(0)),
+			expectedDailyLeft:   "n/a",
+			expectedMonthlyLeft: "n/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statsQuery := usecase.NewCalculateStatsQuery(
+				testutil.NewMockPeriodBasedRepository(dailyRequests, monthlyRequests),
+				testutil.NewNoOpStatsCache(),
+			)
+
+			query := usecase.NewGetUsageVariablesQueryWithOptions(
+				statsQuery,
+				testutil.NewMockPlanRepository(tt.plan),
+				&MockPeriodFactory{dailyPeriod: dailyPeriod, monthlyPeriod: monthlyPeriod},
+				usecase.UsageVariablesOptions{
+					CostFormat: entity.DefaultCostFormat(),
+					Budget:     tt.budget,
+				},
+			)
+
+			vars, err := query.Execute(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := vars["@daily_budget_left"]; got != tt.expectedDailyLeft {
+				t.Errorf("@daily_budget_left: got %s, want %s", got, tt.expectedDailyLeft)
+			}
+			if got := vars["@monthly_budget_left"]; got != tt.expectedMonthlyLeft {
+				t.Errorf("@monthly_budget_left: got %s, want %s", got, tt.expectedMonthlyLeft)
+			}
+		})
+	}
+}
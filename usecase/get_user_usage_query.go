@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// GetUserUsageQuery handles retrieving the daily and block usage of each user against their quota
+type GetUserUsageQuery struct {
+	repository APIRequestRepository
+	quotas     entity.UserQuotas
+}
+
+// NewGetUserUsageQuery creates a new GetUserUsageQuery with the given repository and user quotas
+func NewGetUserUsageQuery(repository APIRequestRepository, quotas entity.UserQuotas) *GetUserUsageQuery {
+	return &GetUserUsageQuery{
+		repository: repository,
+		quotas:     quotas,
+	}
+}
+
+// GetUserUsageParams contains the parameters for retrieving the usage of each user
+type GetUserUsageParams struct {
+	Daily entity.Period  // The day the quotas apply to
+	Block *entity.Period // Optional, the block usage is empty when nil
+}
+
+// Execute executes the get user usage query
+func (q *GetUserUsageQuery) Execute(ctx context.Context, params GetUserUsageParams) ([]entity.UserUsage, error) {
+	daily, err := q.repository.FindByPeriodWithLimit(params.Daily, 0, 0) // No limit for stats calculation
+	if err != nil {
+		return nil, fmt.Errorf("failed to find daily requests: %w", err)
+	}
+
+	var block []entity.APIRequest
+	var blockPeriod entity.Period
+	if params.Block != nil {
+		blockPeriod = *params.Block
+		block, err = q.repository.FindByPeriodWithLimit(blockPeriod, 0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find block requests: %w", err)
+		}
+	}
+
+	return entity.NewUserUsages(daily, params.Daily, block, blockPeriod, q.quotas), nil
+}
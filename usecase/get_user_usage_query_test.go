@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestGetUserUsageQuery_Execute(t *testing.T) {
+	dayStart := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	daily := entity.NewPeriod(dayStart, dayStart.Add(24*time.Hour-time.Nanosecond))
+	block := entity.NewPeriod(dayStart.Add(10*time.Hour), dayStart.Add(15*time.Hour))
+
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", dayStart.Add(2*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(4), 1000).WithUser("alice@example.com"),
+		entity.NewAPIRequest("session-1", dayStart.Add(11*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(200, 100, 0, 0), entity.NewCost(3), 1000).WithUser("alice@example.com"),
+		entity.NewAPIRequest("session-2", dayStart.Add(12*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(10, 5, 0, 0), entity.NewCost(1), 1000).WithUser("bob@example.com"),
+		entity.NewAPIRequest("session-3", dayStart.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(10, 5, 0, 0), entity.NewCost(100), 1000).WithUser("bob@example.com"), // previous day
+	}
+	quotas := entity.NewUserQuotas(entity.NewCost(5), map[string]entity.Cost{"bob@example.com": entity.NewCost(0)})
+
+	tests := []struct {
+		name                string
+		block               *entity.Period
+		expectedBlockTokens map[string]int64
+	}{
+		{
+			name:                "daily and block usage",
+			block:               &block,
+			expectedBlockTokens: map[string]int64{"alice@example.com": 300, "bob@example.com": 15},
+		},
+		{
+			name:                "without block",
+			expectedBlockTokens: map[string]int64{"alice@example.com": 0, "bob@example.com": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := testutil.NewMockAPIRequestRepository()
+			repo.SetMockData(requests)
+			query := NewGetUserUsageQuery(repo, quotas)
+
+			usages, err := query.Execute(context.Background(), GetUserUsageParams{Daily: daily, Block: tt.block})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(usages) != 2 {
+				t.Fatalf("Expected 2 users, got %d", len(usages))
+			}
+
+			alice, bob := usages[0], usages[1]
+			if alice.Daily().TotalCost().Amount() != 7 {
+				t.Errorf("Expected alice daily cost 7, got %v", alice.Daily().TotalCost().Amount())
+			}
+			if alice.Status() != entity.UserQuotaExceeded {
+				t.Errorf("Expected alice to exceed the default quota, got %q", alice.Status())
+			}
+			if bob.Daily().TotalCost().Amount() != 1 {
+				t.Errorf("Expected bob daily cost 1 without the previous day, got %v", bob.Daily().TotalCost().Amount())
+			}
+			if bob.Status() != entity.UserQuotaUnlimited {
+				t.Errorf("Expected bob to be unlimited, got %q", bob.Status())
+			}
+
+			for _, usage := range usages {
+				if got := usage.Block().RateLimitedTokens().Limited(); got != tt.expectedBlockTokens[usage.User()] {
+					t.Errorf("Expected %s block tokens %d, got %d", usage.User(), tt.expectedBlockTokens[usage.User()], got)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUserUsageQuery_Execute_Error(t *testing.T) {
+	repo := testutil.NewMockAPIRequestRepositoryWithError(&testutil.MockError{Message: "database error"})
+	query := NewGetUserUsageQuery(repo, entity.UserQuotas{})
+
+	_, err := query.Execute(context.Background(), GetUserUsageParams{Daily: entity.NewPeriodFromDuration(time.Now(), time.Hour)})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
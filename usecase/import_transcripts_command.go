@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ImportMatchWindow is how far apart a stored request and a transcript message may be to be the same request
+// Telemetry stamps a request when its response completed, the transcript when the message was written
+const ImportMatchWindow = 5 * time.Minute
+
+// ImportTranscriptsCommand backfills the history from Claude Code transcripts, e.g. usage made before ccmon was set up
+// Requests already stored, received live or imported before, are skipped so importing again adds nothing
+type ImportTranscriptsCommand struct {
+	transcripts       TranscriptUsageRepository
+	repository        APIRequestRepository
+	pricingRepository PricingRepository
+}
+
+// NewImportTranscriptsCommand creates a new ImportTranscriptsCommand
+func NewImportTranscriptsCommand(transcripts TranscriptUsageRepository, repository APIRequestRepository, pricingRepository PricingRepository) *ImportTranscriptsCommand {
+	return &ImportTranscriptsCommand{
+		transcripts:       transcripts,
+		repository:        repository,
+		pricingRepository: pricingRepository,
+	}
+}
+
+// ImportTranscriptsParams contains the parameters for importing transcripts
+type ImportTranscriptsParams struct {
+	Path string // transcript file or directory of transcripts, e.g. ~/.claude/projects
+}
+
+// ImportTranscriptsResult contains the result of the import
+type ImportTranscriptsResult struct {
+	Found      int // requests in the transcripts
+	Imported   int // requests stored with the import origin
+	Duplicates int // requests already stored
+	Unpriced   int // imported requests of models without a price, stored with a zero cost
+}
+
+// Execute reads the requests of the transcripts and stores the ones not stored yet
+// Transcripts of current Claude Code versions have no cost, those requests are priced from the price table
+func (c *ImportTranscriptsCommand) Execute(ctx context.Context, params ImportTranscriptsParams) (*ImportTranscriptsResult, error) {
+	requests, err := c.transcripts.FindAPIRequests(params.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcripts: %w", err)
+	}
+
+	result := &ImportTranscriptsResult{Found: len(requests)}
+	if len(requests) == 0 {
+		return result, nil
+	}
+
+	table, err := c.pricingRepository.GetPriceTable()
+	if err != nil {
+		return nil, err
+	}
+
+	// The transcript requests are ordered by timestamp, only stored requests around them can match
+	period := entity.NewPeriod(requests[0].Timestamp().Add(-ImportMatchWindow), requests[len(requests)-1].Timestamp().Add(ImportMatchWindow))
+	stored, err := c.repository.FindByPeriodWithLimit(period, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string][]time.Time, len(stored))
+	for _, req := range stored {
+		key := importMatchKey(req)
+		known[key] = append(known[key], req.Timestamp())
+	}
+
+	var imported []entity.APIRequest
+	for _, req := range requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		key := importMatchKey(req)
+		if isKnownRequest(known[key], req.Timestamp()) {
+			result.Duplicates++
+			continue
+		}
+		known[key] = append(known[key], req.Timestamp())
+
+		if req.Cost().Amount() == 0 {
+			if cost, ok := table.CostOf(req); ok {
+				req = req.WithCost(cost)
+			} else {
+				result.Unpriced++
+			}
+		}
+		imported = append(imported, req.WithOrigin(entity.OriginImport))
+	}
+	result.Imported = len(imported)
+
+	if len(imported) == 0 {
+		return result, nil
+	}
+	if batchRepository, ok := c.repository.(APIRequestBatchRepository); ok {
+		if err := batchRepository.SaveBatch(imported); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	for _, req := range imported {
+		if err := c.repository.Save(req); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// importMatchKey identifies a request by what telemetry and transcripts both report unchanged, the session, model and tokens
+func importMatchKey(req entity.APIRequest) string {
+	tokens := req.Tokens()
+	return fmt.Sprintf("%s_%s_%d_%d_%d_%d", req.SessionID(), req.Model(), tokens.Input(), tokens.Output(), tokens.CacheRead(), tokens.CacheCreation())
+}
+
+// isKnownRequest returns true if any of the timestamps is within the match window of the timestamp
+func isKnownRequest(timestamps []time.Time, timestamp time.Time) bool {
+	for _, known := range timestamps {
+		if diff := known.Sub(timestamp).Abs(); diff <= ImportMatchWindow {
+			return true
+		}
+	}
+	return false
+}
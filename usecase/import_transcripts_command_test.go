@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestImportTranscriptsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	baseTime := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	tokens := entity.NewToken(1_000_000, 100_000, 0, 0)
+	// Sonnet is $3 input and $15 output per million tokens
+	pricingRepo := testutil.NewMockPricingRepository(map[string]entity.ModelPrice{"claude-sonnet-4": entity.NewModelPrice(3, 15, 0.3, 3.75)})
+
+	repo := testutil.NewMockAPIRequestRepository()
+	// Received live, stamped a few seconds after the transcript wrote the message
+	_ = repo.Save(entity.NewAPIRequest("session-1", baseTime.Add(3*time.Second), "claude-sonnet-4-20250514", tokens, entity.NewCost(4.5), 1000))
+
+	transcripts := testutil.NewMockTranscriptUsageRepository([]entity.APIRequest{
+		entity.NewAPIRequest("session-1", baseTime, "claude-sonnet-4-20250514", tokens, entity.NewCost(0), 0),
+		entity.NewAPIRequest("session-1", baseTime.Add(time.Hour), "claude-sonnet-4-20250514", tokens, entity.NewCost(0), 0),
+		entity.NewAPIRequest("session-2", baseTime.Add(2*time.Hour), "claude-opus-4-20250514", tokens, entity.NewCost(1.25), 0),
+		entity.NewAPIRequest("session-2", baseTime.Add(3*time.Hour), "gpt-5", tokens, entity.NewCost(0), 0),
+	})
+
+	command := NewImportTranscriptsCommand(transcripts, repo, pricingRepo)
+	result, err := command.Execute(context.Background(), ImportTranscriptsParams{Path: "~/.claude/projects"})
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	expected := ImportTranscriptsResult{Found: 4, Imported: 3, Duplicates: 1, Unpriced: 1}
+	if *result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *result)
+	}
+
+	stored, _ := repo.FindAll()
+	if len(stored) != 4 {
+		t.Fatalf("Expected 4 stored requests, got %d", len(stored))
+	}
+	costs := map[string]float64{}
+	for _, req := range stored {
+		if req.Timestamp().After(baseTime.Add(time.Minute)) {
+			if req.Origin() != entity.OriginImport {
+				t.Errorf("Expected imported requests to have the import origin, got %q", req.Origin())
+			}
+			costs[req.Model().String()] = req.Cost().Amount()
+		}
+	}
+	if math.Abs(costs["claude-sonnet-4-20250514"]-4.5) > 1e-9 {
+		t.Errorf("Expected the zero cost to be priced, got %v", costs["claude-sonnet-4-20250514"])
+	}
+	if costs["claude-opus-4-20250514"] != 1.25 {
+		t.Errorf("Expected the transcript cost to be kept, got %v", costs["claude-opus-4-20250514"])
+	}
+
+	// Importing again finds every request stored
+	result, err = command.Execute(context.Background(), ImportTranscriptsParams{Path: "~/.claude/projects"})
+	if err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+	if result.Imported != 0 || result.Duplicates != 4 {
+		t.Errorf("Expected a second import to add nothing, got %+v", *result)
+	}
+}
+
+func TestImportTranscriptsCommand_Execute_Error(t *testing.T) {
+	t.Parallel()
+
+	transcripts := testutil.NewMockTranscriptUsageRepository(nil)
+	transcripts.SetError(errors.New("permission denied"))
+
+	command := NewImportTranscriptsCommand(transcripts, testutil.NewMockAPIRequestRepository(), testutil.NewMockPricingRepository(nil))
+	if _, err := command.Execute(context.Background(), ImportTranscriptsParams{Path: "/root/.claude/projects"}); err == nil {
+		t.Error("Expected an error when the transcripts can't be read")
+	}
+}
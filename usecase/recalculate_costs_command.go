@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// RecalculateCostsCommand derives the cost of stored API requests from the price table
+// Claude Code sometimes reports a zero cost, which leaves the history short of the actual usage
+type RecalculateCostsCommand struct {
+	repository        APIRequestRepository
+	pricingRepository PricingRepository
+}
+
+// NewRecalculateCostsCommand creates a new RecalculateCostsCommand
+func NewRecalculateCostsCommand(repository APIRequestRepository, pricingRepository PricingRepository) *RecalculateCostsCommand {
+	return &RecalculateCostsCommand{
+		repository:        repository,
+		pricingRepository: pricingRepository,
+	}
+}
+
+// RecalculateCostsParams contains the parameters for recalculating costs
+type RecalculateCostsParams struct {
+	Period    entity.Period // requests to recalculate, use entity.NewAllTimePeriod for the whole history
+	Overwrite bool          // also replace the non-zero costs reported by the client
+	DryRun    bool          // count the requests which would be updated without updating them
+}
+
+// RecalculateCostsResult contains the result of the recalculation
+type RecalculateCostsResult struct {
+	Scanned  int // requests in the period
+	Updated  int // requests with a changed cost, which would be updated in a dry run
+	Unpriced int // requests to recalculate of models without a price
+}
+
+// Execute recalculates the costs of the requests in the period and stores the changed ones
+func (c *RecalculateCostsCommand) Execute(ctx context.Context, params RecalculateCostsParams) (*RecalculateCostsResult, error) {
+	table, err := c.pricingRepository.GetPriceTable()
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := c.repository.FindByPeriodWithLimit(params.Period, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecalculateCostsResult{Scanned: len(requests)}
+	var updated []entity.APIRequest
+	for _, req := range requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !params.Overwrite && req.Cost().Amount() > 0 {
+			continue
+		}
+
+		cost, ok := table.CostOf(req)
+		if !ok {
+			result.Unpriced++
+			continue
+		}
+		if cost == req.Cost() {
+			continue
+		}
+		updated = append(updated, req.WithCost(cost))
+	}
+	result.Updated = len(updated)
+
+	if params.DryRun || len(updated) == 0 {
+		return result, nil
+	}
+
+	// Requests keep their ID, so saving them again replaces the stored cost
+	if batchRepository, ok := c.repository.(APIRequestBatchRepository); ok {
+		if err := batchRepository.SaveBatch(updated); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	for _, req := range updated {
+		if err := c.repository.Save(req); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
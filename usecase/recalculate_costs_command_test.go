@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestRecalculateCostsCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	// Sonnet is $3 input and $15 output per million tokens
+	sonnet := entity.NewModelPrice(3, 15, 0.3, 3.75)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session-1", now.Add(-3*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(1_000_000, 100_000, 0, 0), entity.NewCost(0), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-2*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(1_000_000, 0, 0, 0), entity.NewCost(2.5), 1000),
+		entity.NewAPIRequest("session-1", now.Add(-time.Hour), "gpt-5", entity.NewToken(1000, 1000, 0, 0), entity.NewCost(0), 1000),
+	}
+
+	tests := []struct {
+		name             string
+		params           RecalculateCostsParams
+		expectedResult   RecalculateCostsResult
+		expectedCosts    []float64
+		expectedBatchRun int
+	}{
+		{
+			name:             "fills zero costs",
+			params:           RecalculateCostsParams{Period: entity.NewAllTimePeriod(now)},
+			expectedResult:   RecalculateCostsResult{Scanned: 3, Updated: 1, Unpriced: 1},
+			expectedCosts:    []float64{4.5},
+			expectedBatchRun: 1,
+		},
+		{
+			name:             "overwrites reported costs",
+			params:           RecalculateCostsParams{Period: entity.NewAllTimePeriod(now), Overwrite: true},
+			expectedResult:   RecalculateCostsResult{Scanned: 3, Updated: 2, Unpriced: 1},
+			expectedCosts:    []float64{4.5, 3},
+			expectedBatchRun: 1,
+		},
+		{
+			name:           "dry run",
+			params:         RecalculateCostsParams{Period: entity.NewAllTimePeriod(now), DryRun: true},
+			expectedResult: RecalculateCostsResult{Scanned: 3, Updated: 1, Unpriced: 1},
+		},
+		{
+			name:           "period",
+			params:         RecalculateCostsParams{Period: entity.NewPeriod(now.Add(-150*time.Minute), now)},
+			expectedResult: RecalculateCostsResult{Scanned: 2, Updated: 0, Unpriced: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockAPIRequestRepository()
+			for _, req := range requests {
+				_ = repo.Save(req)
+			}
+			pricingRepo := testutil.NewMockPricingRepository(map[string]entity.ModelPrice{"claude-sonnet-4": sonnet})
+
+			result, err := NewRecalculateCostsCommand(repo, pricingRepo).Execute(context.Background(), tt.params)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if *result != tt.expectedResult {
+				t.Errorf("Execute() = %+v, want %+v", *result, tt.expectedResult)
+			}
+			if repo.SaveBatchCalls() != tt.expectedBatchRun {
+				t.Errorf("Expected %d batch saves, got %d", tt.expectedBatchRun, repo.SaveBatchCalls())
+			}
+
+			// The mock appends saved requests after the original ones
+			stored, _ := repo.FindAll()
+			saved := stored[len(requests):]
+			if len(saved) != len(tt.expectedCosts) {
+				t.Fatalf("Expected %d saved requests, got %d", len(tt.expectedCosts), len(saved))
+			}
+			for i, req := range saved {
+				if math.Abs(req.Cost().Amount()-tt.expectedCosts[i]) > 1e-9 {
+					t.Errorf("Expected saved cost %.2f, got %.2f", tt.expectedCosts[i], req.Cost().Amount())
+				}
+				if req.ID() != requests[i].ID() {
+					t.Errorf("Expected the saved request to keep ID %s, got %s", requests[i].ID(), req.ID())
+				}
+			}
+		})
+	}
+}
+
+func TestRecalculateCostsCommand_PricingError(t *testing.T) {
+	t.Parallel()
+
+	pricingRepo := testutil.NewMockPricingRepository(nil)
+	pricingRepo.SetError(errors.New("pricing unavailable"))
+
+	_, err := NewRecalculateCostsCommand(testutil.NewMockAPIRequestRepository(), pricingRepo).Execute(context.Background(), RecalculateCostsParams{Period: entity.NewAllTimePeriod(time.Now())})
+	if err == nil {
+		t.Error("Expected an error when the prices cannot be read")
+	}
+}
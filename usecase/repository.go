@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/elct9620/ccmon/entity"
@@ -19,9 +21,15 @@ type APIRequestRepository interface {
 	// FindAll retrieves all API requests (limited to prevent memory issues)
 	FindAll() ([]entity.APIRequest, error)
 
-	// DeleteOlderThan deletes API requests older than the specified cutoff time
+	// DeleteOlderThan deletes API requests older than the specified cutoff time, except the starred ones in keep
 	// Returns the number of deleted records and any error
-	DeleteOlderThan(cutoffTime time.Time) (int, error)
+	DeleteOlderThan(cutoffTime time.Time, keep entity.Stars) (int, error)
+}
+
+// APIRequestBatchRepository defines the repository interface for storing API requests in bulk
+type APIRequestBatchRepository interface {
+	// SaveBatch stores all API request entities in a single transaction
+	SaveBatch(reqs []entity.APIRequest) error
 }
 
 // PlanRepository defines the repository interface for plan configuration access
@@ -30,8 +38,152 @@ type PlanRepository interface {
 	GetConfiguredPlan() (entity.Plan, error)
 }
 
+// PricingRepository defines the repository interface for model price access
+type PricingRepository interface {
+	// GetPriceTable retrieves the prices of the known models
+	GetPriceTable() (entity.PriceTable, error)
+}
+
+// UserPlanRepository is implemented by plan repositories which assign plans to the members of a team
+type UserPlanRepository interface {
+	// GetUserPlan retrieves the plan of the user, the configured plan when the user has no own plan
+	GetUserPlan(user string) (entity.Plan, error)
+}
+
+// APIRequestMultiPeriodRepository is implemented by API request repositories which read several periods in a single scan
+type APIRequestMultiPeriodRepository interface {
+	// FindByPeriods retrieves the requests of each period, in the same order as the periods
+	FindByPeriods(periods []entity.Period) ([][]entity.APIRequest, error)
+}
+
+// APIRequestFilterRepository is implemented by API request repositories which apply the filter dimensions themselves
+// The limit and offset apply to the matching requests
+type APIRequestFilterRepository interface {
+	// FindByFilter retrieves the requests matching the filter with limit and offset, 0 means no limit or offset
+	FindByFilter(filter entity.Filter, limit int, offset int) ([]entity.APIRequest, error)
+}
+
+// APIRequestPageRepository is implemented by API request repositories which continue a page from a cursor instead of skipping an offset
+type APIRequestPageRepository interface {
+	// FindPageByFilter retrieves the newest limit requests matching the filter before the cursor, 0 means no limit and an empty cursor starts at the newest request
+	// The returned cursor continues with the older requests, empty when none remain
+	FindPageByFilter(filter entity.Filter, limit int, cursor string) ([]entity.APIRequest, string, error)
+}
+
+// APIRequestCountRepository is implemented by API request repositories which count the matching requests without returning them
+type APIRequestCountRepository interface {
+	// CountByFilter counts the requests matching the filter
+	CountByFilter(filter entity.Filter) (int, error)
+}
+
+// APIRequestSampleRepository is implemented by API request repositories which sample a period without decoding every request
+type APIRequestSampleRepository interface {
+	// SampleByPeriod retrieves an evenly spread sample of at most size requests of the period and the number of requests in it
+	SampleByPeriod(period entity.Period, size int) ([]entity.APIRequest, int, error)
+}
+
+// APIRequestWatchRepository defines the repository interface for receiving API requests as they are stored
+type APIRequestWatchRepository interface {
+	// WatchByFilter calls notify with each batch of stored requests matching the filter dimensions until ctx is done
+	// The period of the filter is ignored, an error from notify stops watching and is returned
+	WatchByFilter(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error
+}
+
 // StatsRepository defines the repository interface for statistics access
 type StatsRepository interface {
 	// GetStatsByPeriod retrieves aggregated statistics for a given period
 	GetStatsByPeriod(period entity.Period) (entity.Stats, error)
 }
+
+// StatsFilterRepository is an optional StatsRepository extension for statistics of filtered requests
+type StatsFilterRepository interface {
+	// GetStatsByFilter retrieves aggregated statistics of the requests matching the filter
+	GetStatsByFilter(filter entity.Filter) (entity.Stats, error)
+}
+
+// StatsEstimateRepository is an optional StatsRepository extension for a fast first response on huge databases
+type StatsEstimateRepository interface {
+	// EstimateStatsByPeriod retrieves statistics of the period, estimated from a sample when the period has too many requests
+	EstimateStatsByPeriod(period entity.Period) (entity.Stats, error)
+}
+
+// StatsFreshRepository is an optional StatsRepository extension for repositories with a cache behind them, e.g. a remote server
+type StatsFreshRepository interface {
+	// GetFreshStatsByPeriod retrieves aggregated statistics for a given period which are never served from a cache
+	GetFreshStatsByPeriod(period entity.Period) (entity.Stats, error)
+}
+
+// IngestionLagRepository defines the repository interface for ingestion lag metrics access
+type IngestionLagRepository interface {
+	// GetIngestionLag retrieves the lag between event timestamps and server receive time
+	GetIngestionLag() (entity.IngestionLag, error)
+}
+
+// IngestStatsRepository defines the repository interface for the outcomes of received API request events
+type IngestStatsRepository interface {
+	// GetIngestStats retrieves how many events were stored or not stored, and why, over the last hour and day
+	GetIngestStats() (entity.IngestStats, error)
+}
+
+// RetentionRepository defines the repository interface for the server retention policy access
+type RetentionRepository interface {
+	// GetRetention retrieves the retention policy and when the next cleanup runs
+	GetRetention() (entity.Retention, error)
+}
+
+// StarRepository defines the repository interface for starred requests and sessions access
+type StarRepository interface {
+	// GetStars retrieves all starred requests and sessions
+	GetStars() (entity.Stars, error)
+
+	// SetStar stars the request or its whole session, StarNone removes both stars
+	SetStar(requestID string, sessionID string, scope entity.StarScope) error
+}
+
+// SnapshotRepository defines the repository interface for reading database snapshots
+type SnapshotRepository interface {
+	// WriteSnapshot writes a consistent copy of the database to w
+	// Returns the number of bytes written
+	WriteSnapshot(ctx context.Context, w io.Writer) (int64, error)
+}
+
+// SnapshotRestoreRepository defines the repository interface for replacing data from a snapshot
+type SnapshotRestoreRepository interface {
+	// RestoreSnapshot replaces the stored data with the snapshot read from r
+	// Readers keep seeing the previous data until the restore is complete
+	RestoreSnapshot(r io.Reader) error
+}
+
+// BackupRepository defines the repository interface for storing database backups
+type BackupRepository interface {
+	// SaveBackup stores the backup written by write, replacing the previous one only when write succeeds
+	// Returns the number of bytes written
+	SaveBackup(ctx context.Context, write func(w io.Writer) (int64, error)) (int64, error)
+}
+
+// SessionTitleRepository defines the repository interface for human-readable session titles
+type SessionTitleRepository interface {
+	// FindSessionTitles retrieves the titles of the sessions, sessions without a known title are left out
+	FindSessionTitles(sessionIDs []string) (entity.SessionTitles, error)
+}
+
+// TranscriptUsageRepository defines the repository interface for the API requests recorded in Claude Code transcripts
+type TranscriptUsageRepository interface {
+	// FindAPIRequests retrieves the API requests of the transcripts at the path, a transcript file or a directory of them
+	FindAPIRequests(path string) ([]entity.APIRequest, error)
+}
+
+// ExportStateRepository defines the repository interface for remembering how far previous exports got
+type ExportStateRepository interface {
+	// LoadExportCursor retrieves the cursor of the last export, zero before the first export
+	LoadExportCursor() (entity.ExportCursor, error)
+
+	// SaveExportCursor stores the cursor after an export completed
+	SaveExportCursor(cursor entity.ExportCursor) error
+}
+
+// ThrottleSignalRepository defines the repository interface for publishing the throttle signal to agent orchestrators
+type ThrottleSignalRepository interface {
+	// SaveThrottleSignal replaces the published signal, readers never see a partially written one
+	SaveThrottleSignal(signal entity.ThrottleSignal) error
+}
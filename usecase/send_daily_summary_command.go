@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// DailySummaryNotifier delivers the end-of-day summary, e.g. to a chat webhook
+type DailySummaryNotifier interface {
+	NotifyDailySummary(ctx context.Context, summary entity.DailySummary) error
+}
+
+// SendDailySummaryCommand builds today's usage summary and sends it to the notifier
+type SendDailySummaryCommand struct {
+	calculateStatsQuery *CalculateStatsQuery
+	planRepository      PlanRepository
+	periodFactory       PeriodFactory
+	notifier            DailySummaryNotifier
+}
+
+// NewSendDailySummaryCommand creates a new SendDailySummaryCommand, notifier is optional and the summary is only returned when nil
+func NewSendDailySummaryCommand(calculateStatsQuery *CalculateStatsQuery, planRepository PlanRepository, periodFactory PeriodFactory, notifier DailySummaryNotifier) *SendDailySummaryCommand {
+	return &SendDailySummaryCommand{
+		calculateStatsQuery: calculateStatsQuery,
+		planRepository:      planRepository,
+		periodFactory:       periodFactory,
+		notifier:            notifier,
+	}
+}
+
+// SendDailySummaryResult contains the summary and whether the notifier delivered it
+type SendDailySummaryResult struct {
+	Summary   entity.DailySummary
+	Delivered bool
+}
+
+// Execute builds the summary of today and sends it, the result is returned with the error when sending fails
+func (c *SendDailySummaryCommand) Execute(ctx context.Context) (*SendDailySummaryResult, error) {
+	stats, err := c.calculateStatsQuery.Execute(ctx, CalculateStatsParams{Period: c.periodFactory.CreateDaily()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate daily stats: %w", err)
+	}
+
+	// The plan pace is left out when no plan is configured
+	plan, err := c.planRepository.GetConfiguredPlan()
+	if err != nil {
+		plan = entity.NewPlan("unset", entity.NewCost(0))
+	}
+
+	result := &SendDailySummaryResult{Summary: entity.NewDailySummary(stats, plan)}
+	if c.notifier == nil {
+		return result, nil
+	}
+
+	if err := c.notifier.NotifyDailySummary(ctx, result.Summary); err != nil {
+		return result, fmt.Errorf("failed to send daily summary: %w", err)
+	}
+	result.Delivered = true
+	return result, nil
+}
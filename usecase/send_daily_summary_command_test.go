@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// recordingNotifier records the summaries it is asked to send
+type recordingNotifier struct {
+	summaries []entity.DailySummary
+	err       error
+}
+
+func (n *recordingNotifier) NotifyDailySummary(ctx context.Context, summary entity.DailySummary) error {
+	n.summaries = append(n.summaries, summary)
+	return n.err
+}
+
+func TestSendDailySummaryCommand_Execute(t *testing.T) {
+	periodFactory := service.NewTimePeriodFactory(time.UTC)
+	todayStart := periodFactory.CreateDaily().StartAt()
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session", todayStart.Add(time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(1.5), 1000),
+		entity.NewAPIRequest("session", todayStart.Add(2*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(2.5), 1000),
+		entity.NewAPIRequest("session", todayStart.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(9), 1000),
+	}
+
+	tests := []struct {
+		name          string
+		notifier      *recordingNotifier
+		planRepo      PlanRepository
+		wantDelivered bool
+		wantPace      bool
+		wantErr       bool
+	}{
+		{
+			name:          "sends today's summary",
+			notifier:      &recordingNotifier{},
+			planRepo:      testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))),
+			wantDelivered: true,
+			wantPace:      true,
+		},
+		{
+			name:     "without notifier the summary is only returned",
+			planRepo: testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))),
+			wantPace: true,
+		},
+		{
+			name:          "plan error leaves out the pace",
+			notifier:      &recordingNotifier{},
+			planRepo:      testutil.NewMockPlanRepositoryWithError(errors.New("no plan")),
+			wantDelivered: true,
+		},
+		{
+			name:     "failed delivery still returns the summary",
+			notifier: &recordingNotifier{err: errors.New("webhook down")},
+			planRepo: testutil.NewMockPlanRepository(entity.NewPlan("pro", entity.NewCost(20))),
+			wantPace: true,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+			var notifier DailySummaryNotifier
+			if tt.notifier != nil {
+				notifier = tt.notifier
+			}
+			command := NewSendDailySummaryCommand(NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), tt.planRepo, periodFactory, notifier)
+
+			result, err := command.Execute(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if result == nil {
+				t.Fatal("Expected a result")
+			}
+			if result.Delivered != tt.wantDelivered {
+				t.Errorf("Delivered = %v, want %v", result.Delivered, tt.wantDelivered)
+			}
+
+			stats := result.Summary.Stats()
+			if stats.TotalRequests() != 2 || stats.TotalCost().Amount() != 4.0 {
+				t.Errorf("Expected today's 2 requests costing $4.00, got %d requests costing %v", stats.TotalRequests(), stats.TotalCost().Amount())
+			}
+			if _, ok := result.Summary.PlanPace(); ok != tt.wantPace {
+				t.Errorf("PlanPace() ok = %v, want %v", ok, tt.wantPace)
+			}
+			if tt.notifier != nil && len(tt.notifier.summaries) != 1 {
+				t.Errorf("Expected the notifier to be called once, got %d", len(tt.notifier.summaries))
+			}
+		})
+	}
+}
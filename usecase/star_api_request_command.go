@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// ErrStarTargetMissing is returned when the request or session to star is not given
+var ErrStarTargetMissing = errors.New("request ID and session ID are required to star a request")
+
+// StarApiRequestCommand handles the command to star an API request or its session so the cleanup keeps it
+type StarApiRequestCommand struct {
+	repository StarRepository
+}
+
+// NewStarApiRequestCommand creates a new StarApiRequestCommand with the given repository
+func NewStarApiRequestCommand(repository StarRepository) *StarApiRequestCommand {
+	return &StarApiRequestCommand{
+		repository: repository,
+	}
+}
+
+// StarApiRequestParams contains the parameters for starring an API request
+type StarApiRequestParams struct {
+	RequestID string
+	SessionID string
+	Scope     entity.StarScope // StarNone removes the request and session stars
+}
+
+// Execute executes the star API request command
+func (c *StarApiRequestCommand) Execute(ctx context.Context, params StarApiRequestParams) error {
+	if params.RequestID == "" || params.SessionID == "" {
+		return ErrStarTargetMissing
+	}
+
+	return c.repository.SetStar(params.RequestID, params.SessionID, params.Scope)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+func TestStarApiRequestCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC)
+	target := entity.NewAPIRequest("session-1", timestamp, "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+	sibling := entity.NewAPIRequest("session-1", timestamp.Add(time.Minute), "claude-sonnet-4-20250514", entity.NewToken(100, 50, 0, 0), entity.NewCost(0.01), 1000)
+
+	tests := []struct {
+		name           string
+		scopes         []entity.StarScope
+		requestID      string
+		expectedTarget entity.StarScope
+		expectedOther  entity.StarScope
+		expectedErr    error
+	}{
+		{
+			name:           "star the request only",
+			scopes:         []entity.StarScope{entity.StarRequest},
+			requestID:      target.ID(),
+			expectedTarget: entity.StarRequest,
+			expectedOther:  entity.StarNone,
+		},
+		{
+			name:           "star the whole session",
+			scopes:         []entity.StarScope{entity.StarRequest, entity.StarSession},
+			requestID:      target.ID(),
+			expectedTarget: entity.StarSession,
+			expectedOther:  entity.StarSession,
+		},
+		{
+			name:           "unstar removes the session star",
+			scopes:         []entity.StarScope{entity.StarSession, entity.StarNone},
+			requestID:      target.ID(),
+			expectedTarget: entity.StarNone,
+			expectedOther:  entity.StarNone,
+		},
+		{
+			name:           "missing request ID",
+			scopes:         []entity.StarScope{entity.StarRequest},
+			requestID:      "",
+			expectedTarget: entity.StarNone,
+			expectedOther:  entity.StarNone,
+			expectedErr:    ErrStarTargetMissing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := testutil.NewMockStarRepository()
+			command := NewStarApiRequestCommand(repo)
+
+			var err error
+			for _, scope := range tt.scopes {
+				err = command.Execute(context.Background(), StarApiRequestParams{
+					RequestID: tt.requestID,
+					SessionID: target.SessionID(),
+					Scope:     scope,
+				})
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			stars, _ := repo.GetStars()
+			if got := stars.ScopeOf(target); got != tt.expectedTarget {
+				t.Errorf("Expected target scope %v, got %v", tt.expectedTarget, got)
+			}
+			if got := stars.ScopeOf(sibling); got != tt.expectedOther {
+				t.Errorf("Expected sibling scope %v, got %v", tt.expectedOther, got)
+			}
+		})
+	}
+}
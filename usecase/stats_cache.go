@@ -1,6 +1,10 @@
 package usecase
 
-import "github.com/elct9620/ccmon/entity"
+import (
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
 
 // StatsCache defines the interface for caching statistics query results.
 // Implementations should handle TTL-based expiration and thread-safe access.
@@ -12,4 +16,8 @@ type StatsCache interface {
 	// Set stores statistics in the cache for the given period.
 	// The implementation determines the TTL for cache entries.
 	Set(period entity.Period, stats *entity.Stats)
+
+	// Invalidate evicts the cached statistics of every period containing at.
+	// Called when a request is stored, so its period is recalculated before the TTL expires.
+	Invalidate(at time.Time)
 }
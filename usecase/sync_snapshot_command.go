@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// SyncSnapshotResult contains the result of a snapshot sync
+type SyncSnapshotResult struct {
+	Size int64 // snapshot size in bytes
+}
+
+// SyncSnapshotCommand handles replacing local data with a snapshot pulled from the primary server
+type SyncSnapshotCommand struct {
+	source SnapshotRepository
+	target SnapshotRestoreRepository
+}
+
+// NewSyncSnapshotCommand creates a new SyncSnapshotCommand copying from source into target
+func NewSyncSnapshotCommand(source SnapshotRepository, target SnapshotRestoreRepository) *SyncSnapshotCommand {
+	return &SyncSnapshotCommand{
+		source: source,
+		target: target,
+	}
+}
+
+// Execute pulls the snapshot and restores it, local data is untouched if either side fails
+func (c *SyncSnapshotCommand) Execute(ctx context.Context) (SyncSnapshotResult, error) {
+	reader, writer := io.Pipe()
+
+	sizeChan := make(chan int64, 1)
+	go func() {
+		size, err := c.source.WriteSnapshot(ctx, writer)
+		sizeChan <- size
+		writer.CloseWithError(err)
+	}()
+
+	restoreErr := c.target.RestoreSnapshot(reader)
+	// Unblock the source if the restore stopped reading early
+	reader.CloseWithError(restoreErr)
+	size := <-sizeChan
+
+	if restoreErr != nil {
+		return SyncSnapshotResult{}, fmt.Errorf("failed to sync snapshot: %w", restoreErr)
+	}
+
+	return SyncSnapshotResult{Size: size}, nil
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// stubSnapshotSource writes fixed data, optionally failing after it
+type stubSnapshotSource struct {
+	data []byte
+	err  error
+}
+
+func (s *stubSnapshotSource) WriteSnapshot(ctx context.Context, w io.Writer) (int64, error) {
+	n, err := w.Write(s.data)
+	if err != nil {
+		return int64(n), err
+	}
+	return int64(n), s.err
+}
+
+// stubSnapshotTarget records the restored data
+type stubSnapshotTarget struct {
+	restored []byte
+}
+
+func (s *stubSnapshotTarget) RestoreSnapshot(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.restored = data
+	return nil
+}
+
+func TestSyncSnapshotCommand_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		source           *stubSnapshotSource
+		expectError      bool
+		expectedSize     int64
+		expectedRestored string
+	}{
+		{
+			name:             "successful sync",
+			source:           &stubSnapshotSource{data: []byte("snapshot")},
+			expectedSize:     8,
+			expectedRestored: "snapshot",
+		},
+		{
+			name:        "source failure keeps target untouched",
+			source:      &stubSnapshotSource{data: []byte("partial"), err: errors.New("connection reset")},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target := &stubSnapshotTarget{}
+			command := NewSyncSnapshotCommand(tt.source, target)
+
+			result, err := command.Execute(context.Background())
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				if target.restored != nil {
+					t.Errorf("Expected nothing restored, got %q", target.restored)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Size != tt.expectedSize {
+				t.Errorf("Expected size %d, got %d", tt.expectedSize, result.Size)
+			}
+			if string(target.restored) != tt.expectedRestored {
+				t.Errorf("Expected restored %q, got %q", tt.expectedRestored, target.restored)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// WatchApiRequestsQuery handles receiving API requests as they are stored
+type WatchApiRequestsQuery struct {
+	repository APIRequestWatchRepository
+	cache      StatsCache
+}
+
+// NewWatchApiRequestsQuery creates a new WatchApiRequestsQuery with the given repository
+func NewWatchApiRequestsQuery(repository APIRequestWatchRepository) *WatchApiRequestsQuery {
+	return &WatchApiRequestsQuery{
+		repository: repository,
+	}
+}
+
+// NewWatchApiRequestsQueryWithStatsCache creates a new WatchApiRequestsQuery which evicts the cached stats covering the pushed requests
+// The monitor caches stats of the server too, so the refresh after a push would otherwise wait for the TTL
+func NewWatchApiRequestsQueryWithStatsCache(repository APIRequestWatchRepository, cache StatsCache) *WatchApiRequestsQuery {
+	return &WatchApiRequestsQuery{
+		repository: repository,
+		cache:      cache,
+	}
+}
+
+// Execute calls notify with each batch of stored requests matching the filter dimensions until ctx is done
+func (q *WatchApiRequestsQuery) Execute(ctx context.Context, filter entity.Filter, notify func([]entity.APIRequest) error) error {
+	if q.cache == nil {
+		return q.repository.WatchByFilter(ctx, filter, notify)
+	}
+
+	return q.repository.WatchByFilter(ctx, filter, func(requests []entity.APIRequest) error {
+		for _, request := range requests {
+			q.cache.Invalidate(request.Timestamp())
+		}
+		return notify(requests)
+	})
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+)
+
+// WriteThrottleSignalCommand publishes the current block usage for agent orchestrators to poll
+type WriteThrottleSignalCommand struct {
+	calculateStatsQuery *CalculateStatsQuery
+	repository          ThrottleSignalRepository
+	policy              entity.ThrottlePolicy
+}
+
+// NewWriteThrottleSignalCommand creates a new WriteThrottleSignalCommand
+func NewWriteThrottleSignalCommand(calculateStatsQuery *CalculateStatsQuery, repository ThrottleSignalRepository, policy entity.ThrottlePolicy) *WriteThrottleSignalCommand {
+	return &WriteThrottleSignalCommand{
+		calculateStatsQuery: calculateStatsQuery,
+		repository:          repository,
+		policy:              policy,
+	}
+}
+
+// Execute calculates the usage of the block containing now and publishes the signal
+func (c *WriteThrottleSignalCommand) Execute(ctx context.Context, now time.Time) (entity.ThrottleSignal, error) {
+	block := c.policy.Block(now)
+	stats, err := c.calculateStatsQuery.Execute(ctx, CalculateStatsParams{Period: block.Period()})
+	if err != nil {
+		return entity.ThrottleSignal{}, fmt.Errorf("failed to calculate block stats: %w", err)
+	}
+
+	signal := c.policy.Signal(block, stats.RateLimitedTokens(), now)
+	if err := c.repository.SaveThrottleSignal(signal); err != nil {
+		return signal, fmt.Errorf("failed to save throttle signal: %w", err)
+	}
+	return signal, nil
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elct9620/ccmon/entity"
+	"github.com/elct9620/ccmon/service"
+	"github.com/elct9620/ccmon/testutil"
+)
+
+// recordingThrottleSignalRepository records the saved signals
+type recordingThrottleSignalRepository struct {
+	signals []entity.ThrottleSignal
+	err     error
+}
+
+func (r *recordingThrottleSignalRepository) SaveThrottleSignal(signal entity.ThrottleSignal) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.signals = append(r.signals, signal)
+	return nil
+}
+
+func TestWriteThrottleSignalCommand_Execute(t *testing.T) {
+	now := time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)
+	requests := []entity.APIRequest{
+		entity.NewAPIRequest("session", now.Add(-time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 1500, 0, 0), entity.NewCost(1.5), 1000),
+		// Haiku does not count toward the limit
+		entity.NewAPIRequest("session", now.Add(-time.Hour), "claude-3-5-haiku-20241022", entity.NewToken(50000, 1000, 0, 0), entity.NewCost(0.1), 1000),
+		// Before the block started at 5am
+		entity.NewAPIRequest("session", now.Add(-3*time.Hour), "claude-sonnet-4-20250514", entity.NewToken(5000, 1500, 0, 0), entity.NewCost(1.5), 1000),
+	}
+	policy, err := entity.NewThrottlePolicy(5, time.UTC, 7000, entity.DefaultThrottleThreshold)
+	if err != nil {
+		t.Fatalf("NewThrottlePolicy() error = %v", err)
+	}
+
+	_, statsRepo := testutil.NewMockRepositoryWithData(requests)
+	repo := &recordingThrottleSignalRepository{}
+	command := NewWriteThrottleSignalCommand(NewCalculateStatsQuery(statsRepo, &service.NoOpStatsCache{}), repo, policy)
+
+	signal, err := command.Execute(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(repo.signals) != 1 {
+		t.Fatalf("Expected the signal to be saved once, got %d", len(repo.signals))
+	}
+	if signal.Tokens().Limited() != 6500 {
+		t.Errorf("Tokens().Limited() = %d, want the 6500 premium tokens of the block", signal.Tokens().Limited())
+	}
+	if !signal.ShouldThrottle() {
+		t.Error("ShouldThrottle() = false, want true above 90% of the limit")
+	}
+
+	repo.err = errors.New("read-only file system")
+	if _, err := command.Execute(context.Background(), now); err == nil {
+		t.Error("Execute() error = nil, want the save error")
+	}
+}